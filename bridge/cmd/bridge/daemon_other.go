@@ -0,0 +1,39 @@
+//go:build !unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// startDaemonProcess cannot detach a process on this platform
+func startDaemonProcess(args, env []string, logFile string) (*os.Process, error) {
+	return nil, errDaemonUnsupported
+}
+
+// processRunning reports whether a process with the given PID exists
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	_, err := os.FindProcess(pid)
+	return err == nil
+}
+
+// terminateProcess kills a process outright, as there is no SIGTERM to ask
+// it to exit first. It always reports that the process was killed.
+func terminateProcess(pid int, grace time.Duration) (killed bool, err error) {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false, nil
+	}
+	if err := process.Kill(); err != nil {
+		return true, fmt.Errorf("failed to kill process %d: %w", pid, err)
+	}
+	if !waitForExit(pid, daemonKillTimeout) {
+		return true, fmt.Errorf("process %d is still running after being killed", pid)
+	}
+	return true, nil
+}
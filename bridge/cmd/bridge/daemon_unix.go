@@ -0,0 +1,80 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// startDaemonProcess re-executes the bridge with args in a new session, so
+// it has no controlling terminal. Stdin reads from /dev/null and stdout and
+// stderr are appended to logFile.
+func startDaemonProcess(args, env []string, logFile string) (*os.Process, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate executable: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(logFile), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	out, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer out.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = env
+	cmd.Stdin = devNull
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start daemon process: %w", err)
+	}
+	return cmd.Process, nil
+}
+
+// processRunning reports whether a process with the given PID exists
+func processRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// Signal 0 checks for existence; EPERM means it exists as another user
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// terminateProcess asks a process to exit with SIGTERM and, if it is still
+// alive after grace, sends SIGKILL. It reports whether SIGKILL was needed.
+func terminateProcess(pid int, grace time.Duration) (killed bool, err error) {
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		if err == syscall.ESRCH {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to send SIGTERM to process %d: %w", pid, err)
+	}
+	if waitForExit(pid, grace) {
+		return false, nil
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		return true, fmt.Errorf("failed to send SIGKILL to process %d: %w", pid, err)
+	}
+	if !waitForExit(pid, daemonKillTimeout) {
+		return true, fmt.Errorf("process %d is still running after SIGKILL", pid)
+	}
+	return true, nil
+}
@@ -0,0 +1,73 @@
+//go:build unix
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// startProcess runs a command and reaps it in the background, so it does not
+// linger as a zombie once it exits
+func startProcess(t *testing.T, name string, args ...string) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command(name, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go cmd.Wait()
+	t.Cleanup(func() { cmd.Process.Kill() })
+
+	// Wait for the process to report it is ready
+	if _, err := bufio.NewReader(stdout).ReadString('\n'); err != nil {
+		t.Fatalf("process did not start: %v", err)
+	}
+	return cmd
+}
+
+func TestProcessRunning(t *testing.T) {
+	if !processRunning(os.Getpid()) {
+		t.Error("processRunning() = false for the current process")
+	}
+	if processRunning(0) || processRunning(-1) {
+		t.Error("processRunning() = true for an invalid PID")
+	}
+}
+
+func TestTerminateProcess(t *testing.T) {
+	cmd := startProcess(t, "sh", "-c", "echo ready; exec sleep 30")
+
+	killed, err := terminateProcess(cmd.Process.Pid, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if killed {
+		t.Error("terminateProcess() needed SIGKILL for a process that exits on SIGTERM")
+	}
+	if processRunning(cmd.Process.Pid) {
+		t.Error("process is still running")
+	}
+}
+
+func TestTerminateProcessEscalatesToSIGKILL(t *testing.T) {
+	// An ignored signal stays ignored across exec
+	cmd := startProcess(t, "sh", "-c", `trap "" TERM; echo ready; exec sleep 30`)
+
+	killed, err := terminateProcess(cmd.Process.Pid, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !killed {
+		t.Error("terminateProcess() did not report SIGKILL for a process ignoring SIGTERM")
+	}
+	if processRunning(cmd.Process.Pid) {
+		t.Error("process is still running")
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/armorclaw/bridge/internal/adapter"
+	"github.com/armorclaw/bridge/pkg/config"
+	"github.com/armorclaw/bridge/pkg/docker"
+	"github.com/armorclaw/bridge/pkg/keystore"
+	"github.com/armorclaw/bridge/pkg/license"
+)
+
+// doctorTimeout bounds each network check run by the doctor command
+const doctorTimeout = 10 * time.Second
+
+// doctorDeviceID is the Matrix device the doctor logs in as, kept apart
+// from the bridge's own device so a check never replaces its session
+const doctorDeviceID = "armorclaw-doctor"
+
+// doctorStatus is the outcome of a single doctor check
+type doctorStatus int
+
+const (
+	doctorPass doctorStatus = iota
+	doctorWarn
+	doctorFail
+	doctorSkip
+)
+
+// doctorResult reports one check: what was found and, when the check did
+// not pass, how to fix it
+type doctorResult struct {
+	name   string
+	status doctorStatus
+	detail string
+	hint   string
+}
+
+// runDoctorCommand checks the environment the bridge needs and prints a
+// pass/fail report. It exits non-zero when any check fails.
+func runDoctorCommand(cliCfg cliConfig) {
+	fmt.Printf("ArmorClaw Bridge v%s doctor\n\n", version)
+
+	if failed := printDoctorReport(os.Stdout, runDoctorChecks(cliCfg)); failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// runDoctorChecks runs every check. Checks that depend on the
+// configuration are skipped when it cannot be loaded.
+func runDoctorChecks(cliCfg cliConfig) []doctorResult {
+	cfg, result := checkConfig(cliCfg)
+	results := []doctorResult{result, checkDocker()}
+
+	if cfg != nil {
+		results = append(results,
+			checkSocket(cfg.Server.SocketPath),
+			checkKeystore(cfg),
+			checkMatrix(cfg),
+		)
+	}
+
+	return append(results, checkLicenseServer(license.DefaultServerURL))
+}
+
+// printDoctorReport writes the results and a summary line, and returns the
+// number of failed checks
+func printDoctorReport(w io.Writer, results []doctorResult) int {
+	var passed, warned, failed int
+	for _, r := range results {
+		var mark string
+		switch r.status {
+		case doctorPass:
+			mark = "✓"
+			passed++
+		case doctorWarn:
+			mark = "⚠"
+			warned++
+		case doctorFail:
+			mark = "✗"
+			failed++
+		case doctorSkip:
+			mark = "-"
+		}
+
+		fmt.Fprintf(w, "%s %-15s %s\n", mark, r.name, r.detail)
+		if r.status != doctorPass && r.hint != "" {
+			fmt.Fprintf(w, "  %-15s → %s\n", "", r.hint)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d passed, %d warning(s), %d failed\n", passed, warned, failed)
+	return failed
+}
+
+// checkConfig loads and validates the configuration the bridge would use
+func checkConfig(cliCfg cliConfig) (*config.Config, doctorResult) {
+	r := doctorResult{name: "Configuration"}
+
+	cfg, err := config.Load(cliCfg.configPath)
+	if err == nil {
+		applyCLIOverrides(cfg, cliCfg)
+		err = cfg.Validate()
+	}
+	if err != nil {
+		r.status = doctorFail
+		var problems config.ValidationErrors
+		if stderrors.As(err, &problems) {
+			r.detail = fmt.Sprintf("%d problem(s), first: %s: %s", len(problems), problems[0].Field, problems[0].Reason)
+			r.hint = "Run 'armorclaw-bridge validate' to list every problem"
+		} else {
+			r.detail = err.Error()
+			r.hint = "Create a configuration with 'armorclaw-bridge init' or point to one with --config"
+		}
+		return nil, r
+	}
+
+	r.detail = "loaded and valid"
+	if cliCfg.configPath != "" {
+		r.detail = "loaded from " + cliCfg.configPath
+	}
+	return cfg, r
+}
+
+// checkDocker checks that the Docker daemon answers. Setting
+// ARMORCLAW_SKIP_DOCKER_CHECK skips it (for testing).
+func checkDocker() doctorResult {
+	r := doctorResult{name: "Docker"}
+
+	if os.Getenv("ARMORCLAW_SKIP_DOCKER_CHECK") != "" {
+		r.status = doctorSkip
+		r.detail = "skipped (ARMORCLAW_SKIP_DOCKER_CHECK set)"
+		return r
+	}
+
+	if !docker.IsAvailable() {
+		r.status = doctorFail
+		r.detail = "Docker is not available or not running"
+		r.hint = "Start Docker (e.g. 'sudo systemctl start docker') and make sure this user can access /var/run/docker.sock"
+		return r
+	}
+
+	r.detail = "daemon is running"
+	return r
+}
+
+// prepareRuntimeDir creates the directory holding the bridge socket and
+// checks that the bridge can create files in it
+func prepareRuntimeDir(socketPath string) (string, error) {
+	runtimeDir := filepath.Dir(socketPath)
+	if runtimeDir == "" || runtimeDir == "." {
+		runtimeDir = filepath.Join(os.TempDir(), "armorclaw")
+	}
+	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
+		return runtimeDir, fmt.Errorf("failed to create runtime directory %s: %w", runtimeDir, err)
+	}
+
+	probe, err := os.CreateTemp(runtimeDir, ".armorclaw-probe-*")
+	if err != nil {
+		return runtimeDir, fmt.Errorf("runtime directory %s is not writable: %w", runtimeDir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return runtimeDir, nil
+}
+
+// checkSocket checks that the socket directory is writable and reports a
+// socket file left behind by a bridge that is no longer running
+func checkSocket(socketPath string) doctorResult {
+	r := doctorResult{name: "Socket"}
+
+	runtimeDir, err := prepareRuntimeDir(socketPath)
+	if err != nil {
+		r.status = doctorFail
+		r.detail = err.Error()
+		r.hint = fmt.Sprintf("Run 'sudo mkdir -p %s && sudo chown $USER %s', or set server.socket_path to a writable location", runtimeDir, runtimeDir)
+		return r
+	}
+
+	if _, err := os.Stat(socketPath); err == nil {
+		conn, err := net.DialTimeout("unix", socketPath, time.Second)
+		if err != nil {
+			r.status = doctorWarn
+			r.detail = "stale socket file " + socketPath
+			r.hint = fmt.Sprintf("No bridge is listening; remove it with 'rm %s'", socketPath)
+			return r
+		}
+		conn.Close()
+		r.detail = "bridge is listening on " + socketPath
+		return r
+	}
+
+	r.detail = runtimeDir + " is writable"
+	return r
+}
+
+// checkKeystore opens the keystore to check that it can be decrypted with
+// the current master key. A keystore that does not exist yet is left alone.
+func checkKeystore(cfg *config.Config) doctorResult {
+	r := doctorResult{name: "Keystore"}
+
+	if _, err := os.Stat(cfg.Keystore.DBPath); os.IsNotExist(err) {
+		r.status = doctorWarn
+		r.detail = "not created yet: " + cfg.Keystore.DBPath
+		r.hint = "It is created on first start; make sure its directory is writable"
+		return r
+	}
+
+	ks, err := keystore.New(cfg.ToKeystoreConfig())
+	if err == nil {
+		err = ks.Open()
+	}
+	if err != nil {
+		r.status = doctorFail
+		r.detail = strings.SplitN(err.Error(), "\n", 2)[0]
+		if strings.Contains(err.Error(), "KEY MISMATCH") {
+			r.hint = "The keystore was created with a different master key; restore keystore.master_key or start with --migrate-keystore on the original machine"
+		} else {
+			r.hint = "Check the permissions of " + filepath.Dir(cfg.Keystore.DBPath)
+		}
+		return r
+	}
+	ks.Close()
+
+	r.detail = "opened " + cfg.Keystore.DBPath
+	return r
+}
+
+// checkMatrix checks that the homeserver answers and that the configured
+// account can log in
+func checkMatrix(cfg *config.Config) doctorResult {
+	r := doctorResult{name: "Matrix"}
+
+	if !cfg.Matrix.Enabled || cfg.Matrix.HomeserverURL == "" {
+		r.status = doctorSkip
+		r.detail = "disabled"
+		return r
+	}
+
+	homeserver := strings.TrimSuffix(cfg.Matrix.HomeserverURL, "/")
+	if err := probeURL(homeserver + "/_matrix/client/versions"); err != nil {
+		r.status = doctorFail
+		r.detail = fmt.Sprintf("%s is unreachable: %v", homeserver, err)
+		r.hint = "Check matrix.homeserver_url and that the homeserver is running and reachable from this host"
+		return r
+	}
+
+	if cfg.Matrix.Username == "" || cfg.Matrix.Password == "" {
+		r.status = doctorWarn
+		r.detail = homeserver + " is reachable; no credentials to test login"
+		r.hint = "Set matrix.username and matrix.password so the bridge can log in"
+		return r
+	}
+
+	matrix, err := adapter.New(adapter.Config{
+		HomeserverURL: homeserver,
+		DeviceID:      doctorDeviceID,
+		Password:      cfg.Matrix.Password,
+	})
+	if err != nil {
+		r.status = doctorFail
+		r.detail = err.Error()
+		return r
+	}
+	defer matrix.Close()
+
+	if err := matrix.Login(cfg.Matrix.Username, cfg.Matrix.Password); err != nil {
+		r.status = doctorFail
+		r.detail = "login as " + cfg.Matrix.Username + " failed: " + err.Error()
+		r.hint = "Check matrix.username and matrix.password, and that the account exists on " + homeserver
+		return r
+	}
+
+	r.detail = "logged in as " + matrix.GetUserID()
+	return r
+}
+
+// checkLicenseServer checks that the license server can be reached.
+// Premium features keep working from cached validations for a grace
+// period, so an unreachable server is only a warning.
+func checkLicenseServer(serverURL string) doctorResult {
+	r := doctorResult{name: "License server"}
+
+	if err := probeURL(serverURL); err != nil {
+		r.status = doctorWarn
+		r.detail = fmt.Sprintf("%s is unreachable: %v", serverURL, err)
+		r.hint = "Premium features fall back to cached validations; allow outbound HTTPS to the license server"
+		return r
+	}
+
+	r.detail = serverURL + " is reachable"
+	return r
+}
+
+// probeURL sends a GET to url. Any HTTP response other than a server error
+// counts as reachable.
+func probeURL(url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/armorclaw/bridge/pkg/config"
+)
+
+func TestCheckSocket(t *testing.T) {
+	dir := t.TempDir()
+	socketPath := filepath.Join(dir, "run", "bridge.sock")
+
+	if r := checkSocket(socketPath); r.status != doctorPass {
+		t.Errorf("fresh directory: %+v", r)
+	}
+	if _, err := os.Stat(filepath.Dir(socketPath)); err != nil {
+		t.Errorf("runtime directory not created: %v", err)
+	}
+
+	// A socket file nobody listens on is stale
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := checkSocket(socketPath); r.status != doctorPass || !strings.Contains(r.detail, "listening") {
+		t.Errorf("live socket: %+v", r)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	if r := checkSocket(socketPath); r.status != doctorWarn || r.hint == "" {
+		t.Errorf("stale socket: %+v", r)
+	}
+}
+
+func TestCheckSocketUnwritable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	dir := filepath.Join(t.TempDir(), "run")
+	if err := os.Mkdir(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := checkSocket(filepath.Join(dir, "bridge.sock")); r.status != doctorFail || r.hint == "" {
+		t.Errorf("unwritable directory: %+v", r)
+	}
+}
+
+func TestCheckKeystoreMissing(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Keystore.DBPath = filepath.Join(t.TempDir(), "keystore.db")
+
+	if r := checkKeystore(cfg); r.status != doctorWarn {
+		t.Errorf("missing keystore: %+v", r)
+	}
+	if _, err := os.Stat(cfg.Keystore.DBPath + ".salt"); !os.IsNotExist(err) {
+		t.Error("checking a missing keystore created files")
+	}
+}
+
+func TestCheckMatrix(t *testing.T) {
+	loginStatus := http.StatusOK
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_matrix/client/versions":
+			w.Write([]byte(`{"versions":["v1.11"]}`))
+		case "/_matrix/client/v3/login":
+			w.WriteHeader(loginStatus)
+			w.Write([]byte(`{"access_token":"tok","device_id":"armorclaw-doctor","user_id":"@bridge:example.com"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer hs.Close()
+
+	cfg := config.DefaultConfig()
+	if r := checkMatrix(cfg); r.status != doctorSkip {
+		t.Errorf("disabled: %+v", r)
+	}
+
+	cfg.Matrix.Enabled = true
+	cfg.Matrix.HomeserverURL = hs.URL
+	cfg.Matrix.Username = ""
+	if r := checkMatrix(cfg); r.status != doctorWarn {
+		t.Errorf("no credentials: %+v", r)
+	}
+
+	cfg.Matrix.Username = "bridge"
+	cfg.Matrix.Password = "secret"
+	if r := checkMatrix(cfg); r.status != doctorPass || !strings.Contains(r.detail, "@bridge:example.com") {
+		t.Errorf("login: %+v", r)
+	}
+
+	loginStatus = http.StatusForbidden
+	if r := checkMatrix(cfg); r.status != doctorFail || r.hint == "" {
+		t.Errorf("login refused: %+v", r)
+	}
+
+	hs.Close()
+	if r := checkMatrix(cfg); r.status != doctorFail || !strings.Contains(r.detail, "unreachable") {
+		t.Errorf("homeserver down: %+v", r)
+	}
+}
+
+func TestCheckLicenseServer(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	if r := checkLicenseServer(srv.URL); r.status != doctorPass {
+		t.Errorf("reachable: %+v", r)
+	}
+
+	srv.Close()
+	if r := checkLicenseServer(srv.URL); r.status != doctorWarn {
+		t.Errorf("unreachable: %+v", r)
+	}
+}
+
+func TestPrintDoctorReport(t *testing.T) {
+	var out bytes.Buffer
+	failed := printDoctorReport(&out, []doctorResult{
+		{name: "Configuration", status: doctorPass, detail: "loaded and valid", hint: "unused"},
+		{name: "Docker", status: doctorFail, detail: "not running", hint: "start Docker"},
+		{name: "Matrix", status: doctorSkip, detail: "disabled"},
+		{name: "License server", status: doctorWarn, detail: "unreachable", hint: "allow HTTPS"},
+	})
+
+	if failed != 1 {
+		t.Errorf("failed = %d, want 1", failed)
+	}
+	report := out.String()
+	for _, want := range []string{"✓ Configuration", "✗ Docker", "→ start Docker", "→ allow HTTPS", "1 passed, 1 warning(s), 1 failed"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "unused") {
+		t.Errorf("hint printed for a passing check:\n%s", report)
+	}
+}
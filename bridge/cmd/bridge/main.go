@@ -7,17 +7,22 @@ package main
 import (
 	"bufio"
 	"context"
+	"crypto/ed25519"
 	"encoding/base64"
 	"encoding/json"
+	stderrors "errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -26,6 +31,8 @@ import (
 	"github.com/armorclaw/bridge/internal/ai"
 	"github.com/armorclaw/bridge/internal/events"
 	"github.com/armorclaw/bridge/internal/wizard"
+	"github.com/armorclaw/bridge/pkg/audit"
+	"github.com/armorclaw/bridge/pkg/auth"
 	"github.com/armorclaw/bridge/pkg/budget"
 	"github.com/armorclaw/bridge/pkg/config"
 	"github.com/armorclaw/bridge/pkg/discovery"
@@ -34,13 +41,17 @@ import (
 	"github.com/armorclaw/bridge/pkg/eventbus"
 	"github.com/armorclaw/bridge/pkg/health"
 	"github.com/armorclaw/bridge/pkg/keystore"
+	"github.com/armorclaw/bridge/pkg/license"
 	"github.com/armorclaw/bridge/pkg/logger"
 	"github.com/armorclaw/bridge/pkg/notification"
+	"github.com/armorclaw/bridge/pkg/plugin"
 	"github.com/armorclaw/bridge/pkg/providers"
 	"github.com/armorclaw/bridge/pkg/provisioning"
+	"github.com/armorclaw/bridge/pkg/push"
 	"github.com/armorclaw/bridge/pkg/qr"
 	"github.com/armorclaw/bridge/pkg/rpc"
 	"github.com/armorclaw/bridge/pkg/secretary"
+	"github.com/armorclaw/bridge/pkg/secrets"
 	"github.com/armorclaw/bridge/pkg/setup"
 	"github.com/armorclaw/bridge/pkg/studio"
 	"github.com/armorclaw/bridge/pkg/trust"
@@ -50,6 +61,7 @@ import (
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"golang.org/x/term"
 
 	"github.com/armorclaw/bridge/internal/sdtw"
 	"github.com/armorclaw/bridge/internal/skills"
@@ -67,6 +79,7 @@ import (
 
 var (
 	version   = "0.2.0"
+	gitCommit = ""
 	buildTime = "unknown"
 )
 
@@ -104,15 +117,19 @@ type cliConfig struct {
 	addKeyDisplayName string
 	addKeyBaseURL     string
 	startKeyId        string
+	keysFile          string
 	// QR code command flags
-	qrHost string
-	qrPort int
+	qrHost   string
+	qrPort   int
+	qrOutput string
 	// Agent command flags
 	agentType         string
 	agentName         string
 	agentRoom         string
 	agentKey          string
 	agentCapabilities string
+	// set-log-level command flag (the level uses --log-level)
+	logFormat string
 }
 
 func main() {
@@ -139,6 +156,16 @@ func main() {
 		return
 	}
 
+	if cliCfg.command == "doctor" {
+		runDoctorCommand(cliCfg)
+		return
+	}
+
+	if cliCfg.command == "config" {
+		runConfigCommand(cliCfg)
+		return
+	}
+
 	if cliCfg.command == "setup" {
 		runSetupCommand(cliCfg)
 		return
@@ -193,6 +220,16 @@ func main() {
 		return
 	}
 
+	if cliCfg.command == "export-keys" {
+		runExportKeysCommand(cliCfg)
+		return
+	}
+
+	if cliCfg.command == "import-keys" {
+		runImportKeysCommand(cliCfg)
+		return
+	}
+
 	if cliCfg.command == "start" {
 		runStartCommand(cliCfg)
 		return
@@ -208,6 +245,21 @@ func main() {
 		return
 	}
 
+	if cliCfg.command == "stop-agent" {
+		runStopAgentCommand(cliCfg)
+		return
+	}
+
+	if cliCfg.command == "agent-status" {
+		runAgentStatusCommand(cliCfg)
+		return
+	}
+
+	if cliCfg.command == "set-log-level" {
+		runSetLogLevelCommand(cliCfg)
+		return
+	}
+
 	// Default: Start the bridge server
 	runBridgeServer(cliCfg)
 }
@@ -237,12 +289,89 @@ func runInitCommand(cliCfg cliConfig) {
 func runValidateCommand(cliCfg cliConfig) {
 	cfg, err := config.Load(cliCfg.configPath)
 	if err != nil {
-		log.Fatalf("Configuration validation failed: %v", err)
+		var problems config.ValidationErrors
+		if !stderrors.As(err, &problems) {
+			log.Fatalf("Configuration validation failed: %v", err)
+		}
+		log.Printf("✗ Configuration is invalid (%d problem(s)):", len(problems))
+		for _, p := range problems {
+			if p.Value == nil {
+				log.Printf("  %s: %s", p.Field, p.Reason)
+			} else {
+				log.Printf("  %s = %q: %s", p.Field, fmt.Sprint(p.Value), p.Reason)
+			}
+		}
+		os.Exit(1)
 	}
 	log.Printf("✓ Configuration is valid!")
 	log.Printf(" Socket: %s", cfg.Server.SocketPath)
 }
 
+// runConfigCommand handles configuration file maintenance (migrate)
+func runConfigCommand(cliCfg cliConfig) {
+	// The action follows "config", which is already consumed when given first
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "config" {
+		args = args[1:]
+	}
+	if len(args) < 1 {
+		printCommandHelp("config")
+		log.Fatal("Error: config requires an action (migrate)")
+	}
+
+	switch args[0] {
+	case "migrate":
+		runConfigMigrate(cliCfg)
+	default:
+		printCommandHelp("config")
+		log.Fatalf("Error: unknown config action: %s", args[0])
+	}
+}
+
+// runConfigMigrate upgrades a config file to the current schema version and
+// reports what changed
+func runConfigMigrate(cliCfg cliConfig) {
+	path := cliCfg.configPath
+	if path == "" {
+		for _, p := range config.ConfigPaths() {
+			if _, err := os.Stat(p); err == nil {
+				path = p
+				break
+			}
+		}
+	}
+	if path == "" {
+		log.Fatal("No configuration file found. Pass one with --config")
+	}
+
+	report, err := config.MigrateFile(path)
+	if err != nil {
+		log.Fatalf("Configuration migration failed: %v", err)
+	}
+	if !report.Changed() {
+		log.Printf("✓ %s is already at config version %d", path, report.ToVersion)
+		return
+	}
+
+	log.Printf("✓ Migrated %s from config version %d to %d", path, report.FromVersion, report.ToVersion)
+	log.Printf(" Original saved as %s.bak", path)
+	for _, r := range report.Renamed {
+		log.Printf("  renamed:  %s", r)
+	}
+	for _, d := range report.Dropped {
+		log.Printf("  dropped:  %s", d)
+	}
+	for _, u := range report.Unknown {
+		log.Printf("  removed unknown setting: %s", u)
+	}
+	if len(report.Added) > 0 {
+		log.Printf("  added %d setting(s) with default values:", len(report.Added))
+		for _, a := range report.Added {
+			log.Printf("    %s", a)
+		}
+	}
+}
+
 // runReadminCommand initiates admin reset mode
 func runReadminCommand(cliCfg cliConfig) {
 	// For now, just log the reason. Full implementation will be added in later tasks.
@@ -593,17 +722,12 @@ func runSetupCommandLegacy(cliCfg cliConfig) {
 		fmt.Printf("\n🔑 %s API Key\n", providerName)
 		fmt.Println("Enter your API key (input will be hidden):")
 
-		// Read API key securely
-		var apiKey string
-		var err error
-
-		// Try to use termios for hidden input on Unix-like systems
-		apiKey, err = readPassword(reader)
+		// Never fall back to echoed input, which would leave the key in
+		// the terminal scrollback
+		apiKey, err := readPassword(reader)
 		if err != nil {
-			// Fallback to regular input
-			fmt.Print("API Key: ")
-			apiKey, _ = reader.ReadString('\n')
-			apiKey = strings.TrimSpace(apiKey)
+			fmt.Printf("⚠️  Failed to read API key: %v\n", err)
+			apiKey = ""
 		}
 
 		if apiKey == "" {
@@ -780,28 +904,33 @@ func getHostname() string {
 	return "armorclaw-server"
 }
 
-// readPassword reads a password from stdin with hidden input (Unix-like systems)
+// readPassword reads an API key without echoing it. When stdin is not a
+// terminal, the key is read as a line from reader instead.
 func readPassword(reader *bufio.Reader) (string, error) {
-	// On Windows, this won't hide the input, but it will still work
-	// On Unix-like systems, we use syscall to disable echo
-	// For simplicity, this implementation just reads normally
-	// A production version would use termios for hidden input
 	fmt.Print("API Key: ")
-	input, err := reader.ReadString('\n')
-	return strings.TrimSpace(input), err
+
+	// Piped input has no echo to hide, so read it as a line
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		input, err := reader.ReadString('\n')
+		if err == io.EOF && input != "" {
+			err = nil
+		}
+		return strings.TrimSpace(input), err
+	}
+
+	key, err := term.ReadPassword(fd)
+	fmt.Println()
+	return strings.TrimSpace(string(key)), err
 }
 
 // validateAPIKeyFormat performs basic validation of API key format
 func validateAPIKeyFormat(provider, key string) bool {
 	switch provider {
-	case "openai":
-		return strings.HasPrefix(key, "sk-") || strings.HasPrefix(key, "sk-proj-")
-	case "anthropic":
-		return strings.HasPrefix(key, "sk-ant-")
-	case "openrouter":
-		return len(key) > 20 // OpenRouter keys vary
-	case "google", "xai":
-		return len(key) > 20 // Basic length check
+	case "openai", "anthropic", "openrouter", "google", "xai":
+		// The prefixes are distinct, so a key is well-formed exactly when
+		// it is detected as belonging to the chosen provider
+		return detectProviderFromKey(key) == provider
 	}
 	return true
 }
@@ -827,7 +956,7 @@ func runCompletionCommand(cliCfg cliConfig) {
 # Or source it in: ~/.bashrc
 
 _armorclaw_bridge_commands() {
-    local commands="init validate add-key list-keys start start-agent generate-qr setup version help completion"
+    local commands="init validate doctor config add-key list-keys export-keys import-keys start start-agent stop-agent agent-status set-log-level generate-qr setup version help completion"
     echo "$commands"
 }
 
@@ -870,12 +999,31 @@ _armorclaw_bridge() {
         completion)
             COMPREPLY=($(compgen -W "bash zsh" -- "$cur"))
             ;;
+        config)
+            COMPREPLY=($(compgen -W "migrate --config --help -h" -- "$cur"))
+            ;;
         generate-qr)
-            COMPREPLY=($(compgen -W "--host --port --help -h" -- "$cur"))
+            COMPREPLY=($(compgen -W "--host --port --output --help -h" -- "$cur"))
             ;;
         start-agent)
             COMPREPLY=($(compgen -W "--type --name --room --key --capabilities --help -h" -- "$cur"))
             ;;
+        stop-agent|agent-status)
+            COMPREPLY=($(compgen -W "--id --socket --help -h" -- "$cur"))
+            ;;
+        set-log-level)
+            case "$prev" in
+                --log-level)
+                    COMPREPLY=($(compgen -W "debug info warn error" -- "$cur"))
+                    ;;
+                --log-format)
+                    COMPREPLY=($(compgen -W "json text" -- "$cur"))
+                    ;;
+                *)
+                    COMPREPLY=($(compgen -W "--log-level --log-format --socket --help -h" -- "$cur"))
+                    ;;
+            esac
+            ;;
     esac
 }
 
@@ -891,11 +1039,18 @@ _armorclaw_bridge() {
     commands=(
         'init:Initialize configuration file'
         'validate:Validate configuration'
+        'doctor:Check the environment the bridge needs'
+        'config:Maintain configuration file (migrate)'
         'setup:Run interactive setup wizard'
         'add-key:Add an API key to the keystore'
         'list-keys:List all stored API keys'
+        'export-keys:Export keys to a passphrase-encrypted bundle'
+        'import-keys:Import keys from an exported bundle'
         'start:Start an agent container (legacy)'
         'start-agent:Start an AI agent (OpenClaw, assistant, etc.)'
+        'stop-agent:Stop a running AI agent'
+        'agent-status:Show an AI agent's status'
+        'set-log-level:Change the running bridge log level/format'
         'generate-qr:Generate QR code for ArmorChat discovery'
         'completion:Generate shell completion script'
         'version:Show version information'
@@ -913,6 +1068,10 @@ _armorclaw_bridge() {
                            '--name[Display name]' \
                            '--help[Show help]'
                 ;;
+            export-keys|import-keys)
+                _arguments '--file[Bundle path]:file:_files' \
+                           '--help[Show help]'
+                ;;
             start)
                 _arguments '--key[Key ID]:keys:(_armorclaw_bridge_keys)' \
                            '--help[Show help]'
@@ -923,6 +1082,7 @@ _armorclaw_bridge() {
             generate-qr)
                 _arguments '--host[Public hostname/domain]' \
                            '--port[Public port]' \
+                           '--output[PNG output path]:file:_files' \
                            '--help[Show help]'
                 ;;
             start-agent)
@@ -933,6 +1093,17 @@ _armorclaw_bridge() {
                            '--capabilities[Comma-separated capabilities]' \
                            '--help[Show help]'
                 ;;
+            stop-agent|agent-status)
+                _arguments '--id[Agent ID]' \
+                           '--socket[Bridge socket path]:file:_files' \
+                           '--help[Show help]'
+                ;;
+            set-log-level)
+                _arguments '--log-level[Log level]:levels:(debug info warn error)' \
+                           '--log-format[Log format]:formats:(json text)' \
+                           '--socket[Bridge socket path]:file:_files' \
+                           '--help[Show help]'
+                ;;
         esac
     fi
 }
@@ -967,67 +1138,154 @@ _armorclaw_bridge_keys() {
 	}
 }
 
+// daemonStartTimeout bounds how long daemon start waits for the daemon to
+// write its PID file
+const daemonStartTimeout = 10 * time.Second
+
+// daemonStopTimeout bounds how long daemon stop waits after SIGTERM before
+// killing the daemon
+const daemonStopTimeout = 10 * time.Second
+
+// daemonKillTimeout bounds how long daemon stop waits for the daemon to go
+// away after SIGKILL
+const daemonKillTimeout = 5 * time.Second
+
+// errDaemonUnsupported is returned where the platform cannot detach a
+// background process
+var errDaemonUnsupported = stderrors.New("background daemon mode is not supported on this platform")
+
 // runDaemonCommand manages daemon operations (start/stop/restart/status/logs)
 func runDaemonCommand(cliCfg cliConfig) {
-	if len(os.Args) < 3 {
+	// The action follows "daemon", which is already consumed when given first
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "daemon" {
+		args = args[1:]
+	}
+	if len(args) < 1 {
 		printDaemonHelp()
 		log.Fatal("Error: daemon requires an action (start, stop, restart, status, logs)")
 	}
 
-	action := os.Args[2]
+	action := args[0]
 
 	switch action {
 	case "start":
 		daemonStart(cliCfg)
 	case "stop":
-		daemonStop()
+		daemonStop(cliCfg)
 	case "restart":
-		daemonStop()
-		time.Sleep(1 * time.Second)
+		if daemonStatusRunning(cliCfg) {
+			daemonStop(cliCfg)
+		}
 		daemonStart(cliCfg)
 	case "status":
-		daemonStatus()
+		daemonStatus(cliCfg)
 	case "logs":
-		daemonLogs()
+		daemonLogs(cliCfg)
 	default:
 		printDaemonHelp()
 		log.Fatalf("Error: unknown daemon action: %s", action)
 	}
 }
 
-// daemonStart starts the bridge as a daemon
-func daemonStart(cliCfg cliConfig) {
-	// Load configuration
+// loadDaemonConfig loads the configuration for daemon commands, falling back
+// to defaults, and fills in the daemon PID file
+func loadDaemonConfig(cliCfg cliConfig) *config.Config {
 	cfg, err := config.Load(cliCfg.configPath)
 	if err != nil {
-		log.Printf("Warning: Using default configuration: %v", err)
 		cfg = config.DefaultConfig()
 	}
-
-	// Enable daemonize
-	cfg.Server.Daemonize = true
-
-	// Set default PID file if not specified
 	if cfg.Server.PidFile == "" {
 		cfg.Server.PidFile = "/run/armorclaw/bridge.pid"
 	}
+	return cfg
+}
 
-	// Set default log file for daemon
-	if cfg.Logging.Output == "stdout" {
-		cfg.Logging.Output = "file"
-		if cfg.Logging.File == "" {
-			homeDir, err := os.UserHomeDir()
-			if err != nil {
-				log.Fatalf("Failed to determine home directory: %v", err)
-			}
-			cfg.Logging.File = filepath.Join(homeDir, ".armorclaw", "bridge.log")
+// daemonLogFile returns the file the daemon writes its output to
+func daemonLogFile(cfg *config.Config) (string, error) {
+	if cfg.Logging.File != "" {
+		return cfg.Logging.File, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".armorclaw", "bridge.log"), nil
+}
+
+// readPIDFile returns the PID recorded in a PID file
+func readPIDFile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("invalid PID file %s", path)
+	}
+	return pid, nil
+}
+
+// writePIDFile records the current process in a PID file
+func writePIDFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create PID file directory: %w", err)
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644)
+}
+
+// daemonArgs returns the command-line flags passed on to the daemon process.
+// The Matrix password is passed through the environment instead, so it does
+// not show up in the process list.
+func daemonArgs(cliCfg cliConfig) ([]string, error) {
+	var args []string
+	if cliCfg.configPath != "" {
+		// The daemon may not share our working directory assumptions
+		path, err := filepath.Abs(cliCfg.configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve config path: %w", err)
 		}
+		args = append(args, "--config", path)
+	}
+	if cliCfg.socketPath != "" {
+		args = append(args, "--socket", cliCfg.socketPath)
+	}
+	if cliCfg.dbPath != "" {
+		args = append(args, "--db", cliCfg.dbPath)
+	}
+	if cliCfg.matrixHomeserver != "" {
+		args = append(args, "--matrix-homeserver", cliCfg.matrixHomeserver)
+	}
+	if cliCfg.matrixUsername != "" {
+		args = append(args, "--matrix-username", cliCfg.matrixUsername)
+	}
+	if cliCfg.matrixEnabled {
+		args = append(args, "--matrix-enabled")
+	}
+	if cliCfg.logLevel != "" {
+		args = append(args, "--log-level", cliCfg.logLevel)
+	}
+	return args, nil
+}
+
+// daemonStart starts the bridge as a background daemon. The bridge is
+// re-executed in its own session, detached from the terminal, with its
+// output going to the log file. The daemon writes the PID file itself once
+// its configuration has loaded.
+func daemonStart(cliCfg cliConfig) {
+	cfg := loadDaemonConfig(cliCfg)
+
+	logFile, err := daemonLogFile(cfg)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	// Check if already running
-	if daemonStatusRunning() {
+	if daemonStatusRunning(cliCfg) {
 		log.Fatal("Error: daemon is already running")
 	}
+	// Clear a stale PID file so we can tell when the daemon writes its own
+	os.Remove(cfg.Server.PidFile)
 
 	// Create runtime directory
 	runtimeDir := filepath.Dir(cfg.Server.SocketPath)
@@ -1037,64 +1295,76 @@ func daemonStart(cliCfg cliConfig) {
 
 	log.Println("Starting ArmorClaw Bridge as daemon...")
 	log.Printf("PID file: %s", cfg.Server.PidFile)
-	log.Printf("Log file: %s", cfg.Logging.File)
+	log.Printf("Log file: %s", logFile)
 
-	// Fork and detach (simulate daemon mode for now - on Windows, we'll run in foreground)
-	// For true daemon mode, we'd use syscall fork/exec, but for cross-platform compatibility:
-	// We'll start in background with output redirected
-
-	// For now, just start normally with logging to file
-	// Note: This will run in foreground, not true daemon mode
-	// True daemon mode would require fork/exec which is platform-specific
-	log.Println("Note: Running in foreground (true daemon mode requires platform-specific fork)")
-	runBridgeServer(cliCfg)
-}
+	args, err := daemonArgs(cliCfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	env := append(os.Environ(), "ARMORCLAW_DAEMONIZE=true", "ARMORCLAW_PID_FILE="+cfg.Server.PidFile)
+	if cliCfg.matrixPassword != "" {
+		env = append(env, "ARMORCLAW_MATRIX_PASSWORD="+cliCfg.matrixPassword)
+	}
 
-// daemonStop stops the daemon
-func daemonStop() {
-	// Load config to get PID file location
-	cfg, err := config.Load("")
+	proc, err := startDaemonProcess(args, env, logFile)
+	if stderrors.Is(err, errDaemonUnsupported) {
+		log.Printf("Note: %v, running in foreground", err)
+		os.Setenv("ARMORCLAW_DAEMONIZE", "true")
+		os.Setenv("ARMORCLAW_PID_FILE", cfg.Server.PidFile)
+		runBridgeServer(cliCfg)
+		return
+	}
 	if err != nil {
-		cfg = config.DefaultConfig()
+		log.Fatalf("Failed to start daemon: %v", err)
 	}
 
-	if cfg.Server.PidFile == "" {
-		cfg.Server.PidFile = "/run/armorclaw/bridge.pid"
+	exited := make(chan *os.ProcessState, 1)
+	go func() {
+		state, _ := proc.Wait()
+		exited <- state
+	}()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(daemonStartTimeout)
+	for {
+		select {
+		case state := <-exited:
+			log.Fatalf("Daemon exited during startup (%v), see %s", state, logFile)
+		case <-timeout:
+			log.Printf("Daemon started (PID: %d) but has not written its PID file yet, see %s", proc.Pid, logFile)
+			return
+		case <-ticker.C:
+			if pid, err := readPIDFile(cfg.Server.PidFile); err == nil && pid == proc.Pid {
+				log.Printf("✓ Daemon started (PID: %d)", pid)
+				return
+			}
+		}
 	}
+}
 
-	// Read PID file
-	pidData, err := os.ReadFile(cfg.Server.PidFile)
+// daemonStop stops the daemon, sending SIGKILL if it does not exit after
+// SIGTERM
+func daemonStop(cliCfg cliConfig) {
+	cfg := loadDaemonConfig(cliCfg)
+
+	pid, err := readPIDFile(cfg.Server.PidFile)
 	if err != nil {
 		log.Fatalf("Failed to read PID file: %v", err)
 	}
 
-	pidStr := strings.TrimSpace(string(pidData))
-	var pid int
-	fmt.Sscanf(pidStr, "%d", &pid)
-
-	// Check if process is running
-	process, err := os.FindProcess(pid)
-	if err != nil {
+	if !processRunning(pid) {
 		log.Printf("Process %d not found (already stopped?)", pid)
 		os.Remove(cfg.Server.PidFile)
 		return
 	}
 
-	// Send SIGTERM for graceful shutdown
-	if err := process.Signal(syscall.SIGTERM); err != nil {
-		log.Fatalf("Failed to send SIGTERM to process %d: %v", pid, err)
+	killed, err := terminateProcess(pid, daemonStopTimeout)
+	if err != nil {
+		log.Fatalf("Failed to stop daemon: %v", err)
 	}
-
-	// Wait a bit and check if it stopped
-	time.Sleep(2 * time.Second)
-
-	_, err = os.FindProcess(pid)
-	if err == nil {
-		// Process still running, force kill
-		log.Printf("Process %d did not stop gracefully, sending SIGKILL...", pid)
-		// Note: os.Process.Signal is not available on all platforms
-		// For production, would use syscall.Kill
-		time.Sleep(500 * time.Millisecond)
+	if killed {
+		log.Printf("Process %d did not stop gracefully within %s, sent SIGKILL", pid, daemonStopTimeout)
 	}
 
 	// Clean up PID file
@@ -1103,39 +1373,19 @@ func daemonStop() {
 	log.Printf("✓ Daemon stopped (PID: %d)", pid)
 }
 
-// daemonRestart restarts the daemon
-func daemonRestart() {
-	log.Println("Restarting daemon...")
-	// Implementation: stop then start
-	// This is handled by calling stop then start
-}
-
 // daemonStatus checks daemon status
-func daemonStatus() {
-	// Load config to get PID file location
-	cfg, err := config.Load("")
-	if err != nil {
-		cfg = config.DefaultConfig()
-	}
-
-	if cfg.Server.PidFile == "" {
-		cfg.Server.PidFile = "/run/armorclaw/bridge.pid"
-	}
+func daemonStatus(cliCfg cliConfig) {
+	cfg := loadDaemonConfig(cliCfg)
 
 	// Check PID file
-	pidData, err := os.ReadFile(cfg.Server.PidFile)
+	pid, err := readPIDFile(cfg.Server.PidFile)
 	if err != nil {
 		fmt.Println("Daemon status: Stopped (no PID file)")
 		return
 	}
 
-	pidStr := strings.TrimSpace(string(pidData))
-	var pid int
-	fmt.Sscanf(pidStr, "%d", &pid)
-
 	// Check if process is running
-	_, err = os.FindProcess(pid)
-	if err != nil {
+	if !processRunning(pid) {
 		fmt.Printf("Daemon status: Stopped (stale PID file, PID: %d)\n", pid)
 		os.Remove(cfg.Server.PidFile)
 		return
@@ -1154,20 +1404,10 @@ func daemonStatus() {
 }
 
 // daemonLogs shows daemon logs
-func daemonLogs() {
-	// Load config to get log file location
-	cfg, err := config.Load("")
+func daemonLogs(cliCfg cliConfig) {
+	logFile, err := daemonLogFile(loadDaemonConfig(cliCfg))
 	if err != nil {
-		cfg = config.DefaultConfig()
-	}
-
-	logFile := cfg.Logging.File
-	if logFile == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			log.Fatal("Failed to determine home directory")
-		}
-		logFile = filepath.Join(homeDir, ".armorclaw", "bridge.log")
+		log.Fatal(err)
 	}
 
 	if _, err := os.Stat(logFile); os.IsNotExist(err) {
@@ -1187,28 +1427,26 @@ func daemonLogs() {
 	}
 }
 
-// daemonStatusRunning checks if daemon is running without loading config
-func daemonStatusRunning() bool {
-	cfg, err := config.Load("")
-	if err != nil {
-		cfg = config.DefaultConfig()
-	}
-
-	if cfg.Server.PidFile == "" {
-		cfg.Server.PidFile = "/run/armorclaw/bridge.pid"
+// waitForExit polls until the process exits, reporting false if it is still
+// running after timeout
+func waitForExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for processRunning(pid) {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
 	}
+	return true
+}
 
-	pidData, err := os.ReadFile(cfg.Server.PidFile)
+// daemonStatusRunning checks if the daemon recorded in the PID file is running
+func daemonStatusRunning(cliCfg cliConfig) bool {
+	pid, err := readPIDFile(loadDaemonConfig(cliCfg).Server.PidFile)
 	if err != nil {
 		return false
 	}
-
-	pidStr := strings.TrimSpace(string(pidData))
-	var pid int
-	fmt.Sscanf(pidStr, "%d", &pid)
-
-	_, err = os.FindProcess(pid)
-	return err == nil
+	return processRunning(pid)
 }
 
 // getProcessStartTime gets the creation time of a process
@@ -1400,6 +1638,108 @@ func runListKeysCommand(cliCfg cliConfig) {
 	}
 }
 
+// openCLIKeystore loads configuration and opens the keystore for a CLI command
+func openCLIKeystore(cliCfg cliConfig) *keystore.Keystore {
+	cfg, err := config.Load(cliCfg.configPath)
+	if err != nil {
+		log.Printf("Warning: Using default configuration: %v", err)
+		cfg = config.DefaultConfig()
+	}
+
+	ks, err := keystore.New(cfg.ToKeystoreConfig())
+	if err != nil {
+		log.Fatalf("Failed to initialize keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		log.Fatalf("Failed to open keystore: %v", err)
+	}
+	return ks
+}
+
+// readExportPassphrase returns the bundle passphrase from the environment or
+// prompts for it without echo. Exports ask for confirmation.
+func readExportPassphrase(confirm bool) string {
+	if passphrase := os.Getenv("ARMORCLAW_EXPORT_PASSPHRASE"); passphrase != "" {
+		return passphrase
+	}
+
+	fmt.Fprint(os.Stderr, "Bundle passphrase: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		log.Fatalf("Failed to read passphrase: %v (set ARMORCLAW_EXPORT_PASSPHRASE for non-interactive use)", err)
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+		again, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			log.Fatalf("Failed to read passphrase: %v", err)
+		}
+		if string(again) != string(passphrase) {
+			log.Fatal("Error: passphrases do not match")
+		}
+	}
+
+	return string(passphrase)
+}
+
+// runExportKeysCommand writes all credentials to a passphrase-encrypted bundle
+func runExportKeysCommand(cliCfg cliConfig) {
+	if cliCfg.keysFile == "" {
+		log.Fatal("Error: --file is required")
+	}
+
+	ks := openCLIKeystore(cliCfg)
+	defer ks.Close()
+
+	passphrase := readExportPassphrase(true)
+	if len(passphrase) < keystore.MinExportPassphraseLength {
+		log.Fatalf("Error: passphrase must be at least %d characters", keystore.MinExportPassphraseLength)
+	}
+
+	f, err := os.OpenFile(cliCfg.keysFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		log.Fatalf("Failed to create bundle file: %v", err)
+	}
+
+	if err := ks.Export(f, passphrase); err != nil {
+		f.Close()
+		os.Remove(cliCfg.keysFile)
+		log.Fatalf("Failed to export keys: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("Failed to write bundle file: %v", err)
+	}
+
+	log.Printf("✓ Keys exported to %s", cliCfg.keysFile)
+	log.Println("  Keep the bundle and its passphrase safe; anyone with both can read your keys.")
+}
+
+// runImportKeysCommand restores credentials from an exported bundle
+func runImportKeysCommand(cliCfg cliConfig) {
+	if cliCfg.keysFile == "" {
+		log.Fatal("Error: --file is required")
+	}
+
+	f, err := os.Open(cliCfg.keysFile)
+	if err != nil {
+		log.Fatalf("Failed to open bundle file: %v", err)
+	}
+	defer f.Close()
+
+	ks := openCLIKeystore(cliCfg)
+	defer ks.Close()
+
+	if err := ks.Import(f, readExportPassphrase(false)); err != nil {
+		log.Fatalf("Failed to import keys: %v", err)
+	}
+
+	log.Printf("✓ Keys imported from %s", cliCfg.keysFile)
+	log.Println("  Run 'armorclaw-bridge list-keys' to review them.")
+}
+
 // runStartCommand starts an agent container
 func runStartCommand(cliCfg cliConfig) {
 	// Load configuration
@@ -1555,6 +1895,14 @@ func runGenerateQRCommand(cliCfg cliConfig) {
 		fmt.Printf("   Use deep link: %s\n", deepLinkURL)
 	}
 	fmt.Println("")
+
+	if cliCfg.qrOutput != "" {
+		if err := qrResult.WritePNG(cliCfg.qrOutput, 0); err != nil {
+			log.Fatalf("Failed to write QR image: %v", err)
+		}
+		fmt.Printf("QR code image written to %s\n", cliCfg.qrOutput)
+		fmt.Println("")
+	}
 }
 
 // min helper function
@@ -1702,9 +2050,186 @@ func runStartAgentCommand(cliCfg cliConfig) {
 	}
 }
 
+// bridgeRPCError is an error returned by the bridge for an RPC call
+type bridgeRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *bridgeRPCError) Error() string {
+	return fmt.Sprintf("code %d: %s", e.Code, e.Message)
+}
+
+// bridgeSocketPath returns the bridge socket for CLI commands, exiting if
+// the bridge is not running
+func bridgeSocketPath(cliCfg cliConfig) string {
+	socketPath := cliCfg.socketPath
+	if socketPath == "" {
+		socketPath = "/run/armorclaw/bridge.sock"
+	}
+	if _, err := os.Stat(socketPath); os.IsNotExist(err) {
+		log.Fatal("Error: Bridge is not running. Start it first with: armorclaw-bridge")
+	}
+	return socketPath
+}
+
+// callBridgeRPC sends one JSON-RPC request over the bridge socket and decodes
+// its result. An error reply from the bridge is returned as *bridgeRPCError.
+func callBridgeRPC(socketPath, method string, params interface{}, result interface{}) error {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to connect to bridge: %w", err)
+	}
+	defer conn.Close()
+
+	request := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	}
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *bridgeRPCError `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if len(response.Result) == 0 || string(response.Result) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// runStopAgentCommand stops a running agent via the agent.stop bridge RPC
+func runStopAgentCommand(cliCfg cliConfig) {
+	// --id is shared with add-key, where it names the key
+	agentID := cliCfg.addKeyId
+	if agentID == "" {
+		log.Fatal("Error: --id is required. Specify the agent ID printed by start-agent.")
+	}
+
+	var result struct {
+		AgentID string `json:"agent_id"`
+		Status  string `json:"status"`
+	}
+	err := callBridgeRPC(bridgeSocketPath(cliCfg), "agent.stop", map[string]interface{}{"agent_id": agentID}, &result)
+	if rpcErr, ok := err.(*bridgeRPCError); ok {
+		if rpcErr.Code == rpc.MethodNotFound {
+			log.Fatal("Error: This bridge does not support agent.stop. Stop the agent's container with the container.terminate RPC instead.")
+		}
+		log.Fatalf("Error: Agent stop failed (code %d): %s", rpcErr.Code, rpcErr.Message)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	status := result.Status
+	if status == "" {
+		status = "stopped"
+	}
+	fmt.Printf("✓ Agent %s %s\n", agentID, status)
+}
+
+// runSetLogLevelCommand changes a running bridge's log level and/or format
+// via the log.set_level RPC, without a restart
+func runSetLogLevelCommand(cliCfg cliConfig) {
+	params := map[string]interface{}{}
+	if cliCfg.logLevel != "" {
+		params["level"] = cliCfg.logLevel
+	}
+	if cliCfg.logFormat != "" {
+		params["format"] = cliCfg.logFormat
+	}
+	if len(params) == 0 {
+		log.Fatal("Error: --log-level or --log-format is required")
+	}
+
+	var result struct {
+		Level          string `json:"level"`
+		Format         string `json:"format"`
+		PreviousLevel  string `json:"previous_level"`
+		PreviousFormat string `json:"previous_format"`
+	}
+	err := callBridgeRPC(bridgeSocketPath(cliCfg), "log.set_level", params, &result)
+	if rpcErr, ok := err.(*bridgeRPCError); ok {
+		log.Fatalf("Error: Changing log settings failed (code %d): %s", rpcErr.Code, rpcErr.Message)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	fmt.Printf("✓ Log level:  %s -> %s\n", result.PreviousLevel, result.Level)
+	fmt.Printf("✓ Log format: %s -> %s\n", result.PreviousFormat, result.Format)
+	fmt.Println("  This lasts until the bridge restarts. Set logging.level/format in the config to keep it.")
+}
+
+// runAgentStatusCommand shows an agent's status via the agent.status bridge RPC
+func runAgentStatusCommand(cliCfg cliConfig) {
+	// --id is shared with add-key, where it names the key
+	agentID := cliCfg.addKeyId
+	if agentID == "" {
+		log.Fatal("Error: --id is required. Specify the agent ID printed by start-agent.")
+	}
+
+	var result struct {
+		AgentID       string   `json:"agent_id"`
+		Name          string   `json:"name"`
+		Type          string   `json:"type"`
+		Status        string   `json:"status"`
+		RoomID        string   `json:"room_id"`
+		UptimeSeconds int64    `json:"uptime_seconds"`
+		Capabilities  []string `json:"capabilities"`
+	}
+	err := callBridgeRPC(bridgeSocketPath(cliCfg), "agent.status", map[string]interface{}{"agent_id": agentID}, &result)
+	if rpcErr, ok := err.(*bridgeRPCError); ok {
+		if rpcErr.Code == rpc.MethodNotFound {
+			log.Fatal("Error: This bridge does not support agent.status. List agent containers with the container.list RPC instead.")
+		}
+		log.Fatalf("Error: Agent status failed (code %d): %s", rpcErr.Code, rpcErr.Message)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+	if result.AgentID == "" {
+		log.Fatal("Error: No result returned from bridge")
+	}
+
+	fmt.Println("┌─────────────────────────────────────────────────────────────────────────────┐")
+	fmt.Println("│ AGENT STATUS                                                                │")
+	fmt.Println("├─────────────────────────────────────────────────────────────────────────────┤")
+	fmt.Printf("│ Agent ID:     %s\n", result.AgentID)
+	if result.Name != "" {
+		fmt.Printf("│ Name:         %s\n", result.Name)
+	}
+	if result.Type != "" {
+		fmt.Printf("│ Type:         %s\n", result.Type)
+	}
+	fmt.Printf("│ Status:       %s\n", result.Status)
+	fmt.Printf("│ Uptime:       %s\n", time.Duration(result.UptimeSeconds)*time.Second)
+	fmt.Printf("│ Room:         %s\n", result.RoomID)
+	if len(result.Capabilities) > 0 {
+		fmt.Printf("│ Capabilities: %s\n", strings.Join(result.Capabilities, ", "))
+	}
+	fmt.Println("└─────────────────────────────────────────────────────────────────────────────┘")
+}
+
 // runBridgeServer starts the bridge server
 func runBridgeServer(cliCfg cliConfig) {
 	log.Printf("Starting ArmorClaw Bridge v%s", version)
+	if gitCommit != "" {
+		log.Printf("Git commit: %s", gitCommit)
+	}
 	log.Printf("Build time: %s", buildTime)
 
 	// Check for ARMORCLAW_API_KEY environment variable (OpenClaw compatibility)
@@ -1723,28 +2248,7 @@ func runBridgeServer(cliCfg cliConfig) {
 	}
 
 	// Apply CLI flag overrides
-	if cliCfg.socketPath != "" {
-		cfg.Server.SocketPath = cliCfg.socketPath
-	}
-	if cliCfg.dbPath != "" {
-		cfg.Keystore.DBPath = cliCfg.dbPath
-	}
-	if cliCfg.matrixHomeserver != "" {
-		cfg.Matrix.HomeserverURL = cliCfg.matrixHomeserver
-		cfg.Matrix.Enabled = true
-	}
-	if cliCfg.matrixUsername != "" {
-		cfg.Matrix.Username = cliCfg.matrixUsername
-	}
-	if cliCfg.matrixPassword != "" {
-		cfg.Matrix.Password = cliCfg.matrixPassword
-	}
-	if cliCfg.matrixEnabled {
-		cfg.Matrix.Enabled = true
-	}
-	if cliCfg.logLevel != "" {
-		cfg.Logging.Level = cliCfg.logLevel
-	}
+	applyCLIOverrides(cfg, cliCfg)
 
 	// Validate final configuration
 	if err := cfg.Validate(); err != nil {
@@ -1754,6 +2258,14 @@ func runBridgeServer(cliCfg cliConfig) {
 	// Setup logging
 	setupLogging(cfg.Logging)
 
+	// Record the daemon's PID once the configuration is known to be good
+	if cfg.Server.Daemonize && cfg.Server.PidFile != "" {
+		if err := writePIDFile(cfg.Server.PidFile); err != nil {
+			log.Fatalf("Failed to write PID file: %v", err)
+		}
+		defer os.Remove(cfg.Server.PidFile)
+	}
+
 	log.Printf("Configuration loaded successfully")
 	log.Printf("Socket: %s", cfg.Server.SocketPath)
 	log.Printf("Keystore: %s", cfg.Keystore.DBPath)
@@ -1763,27 +2275,18 @@ func runBridgeServer(cliCfg cliConfig) {
 		log.Printf("Matrix: disabled")
 	}
 
-	// Pre-flight checks
-	// Check Docker availability before initializing services
-	// Skip check if ARMORCLAW_SKIP_DOCKER_CHECK is set (for testing)
-	if os.Getenv("ARMORCLAW_SKIP_DOCKER_CHECK") == "" {
-		log.Println("Checking Docker availability...")
-		if !docker.IsAvailable() {
-			log.Fatalf("Docker is not available or not running. " +
-				"Please start Docker and ensure the daemon is accessible.")
-		}
-		log.Println("Docker is available")
+	// Pre-flight checks, shared with the doctor command
+	log.Println("Checking Docker availability...")
+	if r := checkDocker(); r.status == doctorFail {
+		log.Fatalf("%s. %s (run 'armorclaw-bridge doctor' for a full report)", r.detail, r.hint)
 	} else {
-		log.Println("Skipping Docker availability check (ARMORCLAW_SKIP_DOCKER_CHECK set)")
+		log.Printf("Docker: %s", r.detail)
 	}
 
-	// Ensure base runtime directory exists (cross-platform safe)
-	runtimeDir := filepath.Dir(cfg.Server.SocketPath)
-	if runtimeDir == "" || runtimeDir == "." {
-		runtimeDir = filepath.Join(os.TempDir(), "armorclaw")
-	}
-	if err := os.MkdirAll(runtimeDir, 0755); err != nil {
-		log.Fatalf("Failed to create runtime directory %s: %v", runtimeDir, err)
+	// Ensure base runtime directory exists and is writable (cross-platform safe)
+	runtimeDir, err := prepareRuntimeDir(cfg.Server.SocketPath)
+	if err != nil {
+		log.Fatalf("%v (run 'armorclaw-bridge doctor' for a full report)", err)
 	}
 	log.Printf("Runtime directory ready: %s", runtimeDir)
 
@@ -1894,12 +2397,15 @@ func runBridgeServer(cliCfg cliConfig) {
 
 	// Initialize error handling system
 	log.Println("Initializing error handling system...")
+	errors.SetBuildInfo(version, gitCommit, buildTime)
 	errorCfg := cfg.ToErrorSystemConfig()
 	errorSystem, err := errors.Initialize(errors.Config{
 		StorePath:       errorCfg.StorePath,
 		RetentionDays:   errorCfg.RetentionDays,
 		RateLimitWindow: errorCfg.RateLimitWindow,
+		CodeWindows:     errorCfg.CodeWindows,
 		RetentionPeriod: errorCfg.RetentionPeriod,
+		DigestInterval:  errorCfg.DigestInterval,
 		ConfigAdminMXID: errorCfg.ConfigAdminMXID,
 		SetupUserMXID:   errorCfg.SetupUserMXID,
 		AdminRoomID:     errorCfg.AdminRoomID,
@@ -1907,6 +2413,9 @@ func runBridgeServer(cliCfg cliConfig) {
 		Enabled:         errorCfg.Enabled,
 		StoreEnabled:    errorCfg.StoreEnabled,
 		NotifyEnabled:   errorCfg.NotifyEnabled,
+
+		EscalationMXIDs:   errorCfg.EscalationMXIDs,
+		EscalationTimeout: errorCfg.EscalationTimeout,
 	})
 	if err != nil {
 		log.Fatalf("Failed to initialize error system: %v", err)
@@ -1932,6 +2441,13 @@ func runBridgeServer(cliCfg cliConfig) {
 	// Create session manager
 	sessionConfig := webrtc.DefaultSessionConfig()
 	sessionConfig.DefaultTTL = 30 * time.Minute
+	if cfg.Voice.TTL.MaxTTL != "" {
+		if d, err := time.ParseDuration(cfg.Voice.TTL.MaxTTL); err == nil && d > 0 {
+			sessionConfig.MaxTTL = d
+		} else {
+			log.Printf("Warning: invalid voice.ttl.max_ttl %q, using %s", cfg.Voice.TTL.MaxTTL, sessionConfig.MaxTTL)
+		}
+	}
 	sessionMgr := webrtc.NewSessionManager(sessionConfig)
 
 	// Create token manager (requires secret for signing)
@@ -1950,39 +2466,25 @@ func runBridgeServer(cliCfg cliConfig) {
 		log.Fatalf("Failed to create WebRTC engine: %v", err)
 	}
 
+	// Hang up calls when their session TTL runs out
+	sessionMgr.SetExpiryHandler(func(session *webrtc.Session) {
+		webrtcEngine.ClosePeerConnection(session.ID)
+	})
+
 	// Create TURN manager (required for voice features)
 	// TURN_SECRET must be configured — the default is intentionally empty
 	// to prevent deploying with a known shared secret.
 	var turnMgr *turn.Manager
 	if cfg.WebRTC.TURNSharedSecret != "" {
-		// Use default TURN config with the configured secret
-		turnConfig := turn.DefaultConfig()
-		turnConfig.Secret = cfg.WebRTC.TURNSharedSecret
-		if cfg.WebRTC.TURNServerURL != "" {
-			// Parse TURN URL (format: turn:host:port)
-			turnURL := cfg.WebRTC.TURNServerURL
-			if strings.HasPrefix(turnURL, "turn:") {
-				turnURL = strings.TrimPrefix(turnURL, "turn:")
-			} else if strings.HasPrefix(turnURL, "turns:") {
-				turnURL = strings.TrimPrefix(turnURL, "turns:")
-			}
-			parts := strings.Split(turnURL, ":")
-			if len(parts) >= 1 {
-				turnConfig.Servers[0].Host = parts[0]
-			}
-			if len(parts) >= 2 {
-				var port int
-				fmt.Sscanf(parts[1], "%d", &port)
-				turnConfig.Servers[0].Port = port
-			}
-		}
+		// Configured TURN servers, in failover order, with the shared secret
+		turnConfig := cfg.ToTURNConfig()
 		var turnErr error
 		turnMgr, turnErr = turn.NewManager(turnConfig)
 		if turnErr != nil {
 			log.Fatalf("FATAL: %v", turnErr)
 		}
 		webrtcEngine.SetTURNManager(turnMgr)
-		log.Println("TURN manager initialized")
+		log.Printf("TURN manager initialized with %d server(s)", len(turnConfig.Servers))
 	}
 
 	// TODO: Voice package needs refactoring - uncomment when fixed
@@ -2102,13 +2604,9 @@ func runBridgeServer(cliCfg cliConfig) {
 		}
 	*/
 
-	// Create budget tracker (unused, kept for future use)
-	_, err = budget.NewBudgetTracker(budget.BudgetConfig{
-		DailyLimitUSD:   cfg.Budget.DailyLimitUSD,
-		MonthlyLimitUSD: cfg.Budget.MonthlyLimitUSD,
-		AlertThreshold:  cfg.Budget.AlertThreshold,
-		HardStop:        cfg.Budget.HardStop,
-	})
+	// Create budget tracker; ai.chat attributes spend to it per key and
+	// threshold crossings are reported through the error system
+	budgetTracker, err := budget.NewBudgetTracker(cfg.ToBudgetConfig(), budget.WithErrorSystem(errorSystem))
 	if err != nil {
 		log.Fatalf("Failed to create budget tracker: %v", err)
 	}
@@ -2125,6 +2623,7 @@ func runBridgeServer(cliCfg cliConfig) {
 			docker.ScopeExec,
 			docker.ScopeRemove,
 		},
+		DefaultLimits: cfg.ToContainerLimits(),
 	})
 	if err != nil {
 		log.Fatalf("Failed to create Docker client: %v", err)
@@ -2141,13 +2640,18 @@ func runBridgeServer(cliCfg cliConfig) {
 			AdminRoomID: cfg.Notifications.AdminRoomID,
 			Enabled:     true,
 		})
+		for alertType, tmpl := range cfg.Notifications.Templates {
+			if err := notifier.SetTemplate(alertType, tmpl); err != nil {
+				log.Printf("Warning: Ignoring notification template: %v", err)
+			}
+		}
 	} else {
 		log.Println("Notifications disabled (Matrix not enabled or no admin room configured)")
 	}
 
 	// Initialize health monitor
 	log.Println("Initializing container health monitor...")
-	healthConfig := health.DefaultMonitorConfig()
+	healthConfig := cfg.ToHealthConfig()
 	healthMonitor := health.NewMonitor(dockerClient, healthConfig)
 
 	// Set up container failure handler
@@ -2192,6 +2696,7 @@ func runBridgeServer(cliCfg cliConfig) {
 			WebSocketPath:     cfg.EventBus.WebSocketPath,
 			MaxSubscribers:    cfg.EventBus.MaxSubscribers,
 			InactivityTimeout: inactivityTimeout,
+			BacklogSize:       cfg.EventBus.ReplayBacklog,
 			EnableLog:         cfg.EventBus.EnableDurableLog,
 			LogDir:            logDir,
 			MaxLogFileSize:    cfg.EventBus.MaxLogFileSize,
@@ -2208,6 +2713,7 @@ func runBridgeServer(cliCfg cliConfig) {
 	} else {
 		log.Println("Event bus disabled (Matrix not enabled)")
 	}
+	sessionMgr.SetEventBus(eventBus)
 
 	// Initialize YARA scanner (required by email ingest for attachment scanning)
 	yaraRulesPath := filepath.Join("configs", "yara_rules.yar")
@@ -2232,6 +2738,7 @@ func runBridgeServer(cliCfg cliConfig) {
 			signalingSvr.SetTLS(cfg.WebRTC.SignalingTLSCert, cfg.WebRTC.SignalingTLSKey)
 			log.Printf("Signaling server TLS enabled")
 		}
+		signalingSvr.SetKeepalive(cfg.SignalingKeepalive())
 
 		// Start signaling server
 		if err := signalingSvr.Start(); err != nil {
@@ -2271,18 +2778,39 @@ func runBridgeServer(cliCfg cliConfig) {
 	if cfg.Matrix.Enabled && cfg.Matrix.HomeserverURL != "" {
 		log.Println("Initializing high-throughput Matrix event bus...")
 
-		// Use default buffer size of 1024
-		bufferSize := 1024
+		// Bounds how far a matrix.receive reader may fall behind
+		bufferSize := cfg.Matrix.EventBufferSize
+		if bufferSize <= 0 {
+			bufferSize = events.DefaultMaxEvents
+		}
 
-		matrixBus = events.NewMatrixEventBus(bufferSize)
+		// The buffer is kept in the keystore database so matrix.receive
+		// readers can resume after a restart
+		if eventStore, err := events.NewMatrixEventStore(ks.GetDB()); err != nil {
+			log.Printf("Warning: Matrix events will not survive a restart: %v", err)
+		} else if matrixBus, err = events.NewPersistentMatrixEventBus(bufferSize, eventStore); err != nil {
+			log.Printf("Warning: Matrix events will not survive a restart: %v", err)
+		}
+		if matrixBus == nil {
+			matrixBus = events.NewMatrixEventBus(bufferSize)
+		}
 		log.Printf("Matrix event bus initialized with buffer size: %d", bufferSize)
 
 		// Create basic MatrixAdapter
 		var err error
 		matrixAdapter, err = adapter.New(adapter.Config{
-			HomeserverURL: cfg.Matrix.HomeserverURL,
-			DeviceID:      "armorclaw-bridge",
-			Password:      cfg.Matrix.Password,
+			HomeserverURL:   cfg.Matrix.HomeserverURL,
+			DeviceID:        "armorclaw-bridge",
+			Password:        cfg.Matrix.Password,
+			TrustedSenders:  cfg.Matrix.ZeroTrust.TrustedSenders,
+			TrustedRooms:    cfg.Matrix.ZeroTrust.TrustedRooms,
+			RejectUntrusted: cfg.Matrix.ZeroTrust.RejectUntrusted,
+
+			TokenRefreshMargin: cfg.GetTokenRefreshMargin(),
+			MaxRetries:         cfg.Matrix.Retry.MaxRetries,
+			RetryDelay:         time.Duration(cfg.Matrix.Retry.RetryDelay) * time.Second,
+			BackoffMultiplier:  cfg.Matrix.Retry.BackoffMultiplier,
+			EventBufferSize:    bufferSize,
 		})
 		if err != nil {
 			log.Printf("Warning: Failed to create matrix adapter: %v", err)
@@ -2297,6 +2825,11 @@ func runBridgeServer(cliCfg cliConfig) {
 			} else {
 				matrixAdapter.StartSync()
 				log.Println("Matrix sync loop started")
+
+				// Deliver error notifications, including any queued before login
+				if errorSystem != nil {
+					errorSystem.SetMatrixSender(&errorsMatrixSender{adapter: matrixAdapter})
+				}
 			}
 			log.Printf("Matrix adapter initialized: %s", matrixAdapter.GetUserID())
 		}
@@ -2422,6 +2955,16 @@ func runBridgeServer(cliCfg cliConfig) {
 	// Create Docker client adapter for toolsidecar (v6 microkernel)
 	toolsidecarDocker := &toolsidecarDockerAdapter{client: dockerClient}
 
+	// Secret sockets deliver API keys to agents spawned with a key_id
+	secretInjector, err := secrets.NewSecretInjector(docker.SecretHostSocketDir,
+		logger.NewSecurityLogger(logger.Global().WithComponent("secrets")))
+	if err != nil {
+		log.Printf("Warning: Secret injection unavailable, agents cannot be spawned with a key: %v", err)
+		secretInjector = nil
+	} else {
+		defer secretInjector.Stop()
+	}
+
 	// Create Studio service
 	var studioService *studio.StudioIntegration
 	studioDataPath := filepath.Join(filepath.Dir(cfg.Keystore.DBPath), "studio")
@@ -2429,6 +2972,9 @@ func runBridgeServer(cliCfg cliConfig) {
 	// Create Docker client adapter for studio
 	studioDockerAdapter := studio.NewDockerClientAdapter(
 		func(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, name string) (string, error) {
+			if err := dockerClient.EnsureImage(ctx, config.Image); err != nil {
+				return "", err
+			}
 			return dockerClient.CreateContainer(ctx, config, hostConfig, nil, nil)
 		},
 		func(ctx context.Context, containerID string) error {
@@ -2464,9 +3010,11 @@ func runBridgeServer(cliCfg cliConfig) {
 	}
 
 	studioService, err = studio.NewIntegration(studio.IntegrationConfig{
-		DataPath:      studioDataPath,
-		DockerClient:  studioDockerAdapter,
-		MatrixAdapter: studioMatrix,
+		DataPath:       studioDataPath,
+		DockerClient:   studioDockerAdapter,
+		MatrixAdapter:  studioMatrix,
+		Credentials:    ks,
+		SecretInjector: secretInjector,
 	})
 	if err != nil {
 		log.Printf("Warning: Failed to initialize studio: %v", err)
@@ -2475,8 +3023,72 @@ func runBridgeServer(cliCfg cliConfig) {
 		log.Println("Studio service initialized")
 	}
 
+	// Audit log shared by governance RPCs, audit.export and the MCP router.
+	// Entries are hash-chained, keyed from the keystore, so exports can be
+	// checked for tampering.
+	auditLog, err := audit.NewAuditLog(audit.Config{
+		Path:          audit.DefaultConfig().Path,
+		HashChain:     true,
+		ChainKey:      ks.DeriveKey("audit-chain"),
+		RetentionDays: cfg.Compliance.AuditRetentionDays,
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to open audit log: %v", err)
+		auditLog = nil
+	} else {
+		auditLog.StartPruning(shutdownCtx)
+	}
+
+	// Load external adapter plugins
+	pluginMgr := plugin.NewPluginManager(plugin.ManagerConfig{PluginDir: cfg.Plugins.Dir})
+	if cfg.Plugins.ConfigFile != "" {
+		if configs, err := pluginMgr.ReadConfigFile(cfg.Plugins.ConfigFile); err != nil {
+			log.Printf("Warning: Plugins not loaded: %v", err)
+		} else {
+			started, failed := pluginMgr.LoadConfigured(configs)
+			for path, err := range failed {
+				log.Printf("Warning: Plugin %s failed to start: %v", path, err)
+			}
+			log.Printf("Plugins started: %d", started)
+		}
+	}
+
+	// Validate the premium license, if one is configured. The cached result
+	// drives the license expiry warnings. In offline mode a signed token
+	// stands in for the key.
+	var licenseClient *license.Client
+	if cfg.License.Key != "" || (cfg.License.OfflineMode && cfg.License.OfflineToken != "") {
+		var publicKey ed25519.PublicKey
+		if cfg.License.PublicKey != "" {
+			pk, err := license.ParsePublicKey(cfg.License.PublicKey)
+			if err != nil {
+				log.Printf("Warning: Invalid license public key: %v", err)
+			}
+			publicKey = pk
+		}
+		lc, err := license.NewClient(license.ClientConfig{
+			ServerURL:    cfg.License.ServerURL,
+			LicenseKey:   cfg.License.Key,
+			Version:      version,
+			CacheDir:     cfg.License.CacheDir,
+			OfflineMode:  cfg.License.OfflineMode,
+			OfflineToken: cfg.License.OfflineToken,
+			PublicKey:    publicKey,
+		})
+		if err != nil {
+			log.Printf("Warning: License client not created: %v", err)
+		} else {
+			licenseClient = lc
+			go func() {
+				if _, err := lc.Validate(shutdownCtx, ""); err != nil {
+					log.Printf("Warning: License validation failed: %v", err)
+				}
+			}()
+		}
+	}
+
 	// Initialize v6 MCP Router (if enabled)
-	mcpRouter, mcpTranslator := setupMCPRouter(cfg, toolsidecarDocker, vaultClient)
+	mcpRouter, mcpTranslator := setupMCPRouter(cfg, auditLog, toolsidecarDocker, vaultClient, notifier)
 
 	rolodexStore, rolodexService, webdavService, calendarService := setupSecretaryServices(ks)
 	if rolodexStore != nil {
@@ -2491,7 +3103,7 @@ func runBridgeServer(cliCfg cliConfig) {
 		rolodexStore, workflowOrchestrator, orchestratorIntegration,
 		matrixAdapter, studioService,
 		rolodexService, webdavService, calendarService,
-		approvalEngine, trustEngine,
+		approvalEngine, trustEngine, errorSystem,
 	)
 	if taskScheduler != nil {
 		defer taskScheduler.Stop()
@@ -2520,6 +3132,59 @@ func runBridgeServer(cliCfg cliConfig) {
 		log.Fatalf("Failed to initialize invite store: %v", err)
 	}
 
+	// Runtime sender allowlist changes are persisted alongside the config list
+	var senderAllowlist *trust.SenderAllowlist
+	if matrixAdapter != nil {
+		senderAllowlist, err = trust.NewSenderAllowlist(ks.GetDB(), matrixAdapter)
+		if err != nil {
+			log.Fatalf("Failed to initialize sender allowlist: %v", err)
+		}
+	}
+
+	// Push notifications to mobile clients (FCM/APNs)
+	var pushGateway *push.Gateway
+	var pushDispatcher *push.Dispatcher
+	if cfg.Push.Enabled {
+		pushStore, err := push.NewDeviceStore(ks.GetDB())
+		if err != nil {
+			log.Fatalf("Failed to initialize push device store: %v", err)
+		}
+		pushGateway, err = push.NewGateway(push.Config{
+			FCMEnabled:         cfg.Push.FCMEnabled(),
+			FCMProjectID:       cfg.Push.FCMProjectID,
+			FCMCredentialsFile: cfg.Push.FCMCredentialsFile,
+			APNSEnabled:        cfg.Push.APNSEnabled(),
+			APNSCertFile:       cfg.Push.APNSCertFile,
+			APNSKeyFile:        cfg.Push.APNSKeyFile,
+			APNSTopic:          cfg.Push.APNSTopic,
+			APNSEnvironment:    cfg.Push.APNSEnvironment,
+			Store:              pushStore,
+		})
+		if err != nil {
+			log.Fatalf("Failed to initialize push gateway: %v", err)
+		}
+
+		var ignoreSenders []string
+		if matrixAdapter != nil {
+			ignoreSenders = append(ignoreSenders, matrixAdapter.GetUserID())
+		}
+		pushDispatcher = push.NewDispatcher(pushGateway, push.DispatcherConfig{
+			NotifyMessages: cfg.Push.NotifyMessages,
+			IgnoreSenders:  ignoreSenders,
+		})
+		if eventBus != nil {
+			go func() {
+				if err := pushDispatcher.Run(shutdownCtx, eventBus); err != nil {
+					log.Printf("Warning: push message notifications stopped: %v", err)
+				}
+			}()
+		}
+		if errorSystem != nil {
+			errorSystem.SetCriticalHook(pushDispatcher.NotifyCriticalError)
+		}
+		log.Printf("Push notifications enabled (fcm=%v, apns=%v)", cfg.Push.FCMEnabled(), cfg.Push.APNSEnabled())
+	}
+
 	metrics := rpc.NewMetrics()
 	log.Println("Metrics initialized")
 
@@ -2541,9 +3206,26 @@ func runBridgeServer(cliCfg cliConfig) {
 	rpcCfg.HardeningStore = hardeningStore
 	rpcCfg.DeviceStore = deviceStore
 	rpcCfg.InviteStore = inviteStore
+	rpcCfg.AuditLog = auditLog
+	rpcCfg.SenderAllowlist = senderAllowlist
 	rpcCfg.Metrics = metrics
+	rpcCfg.ErrorSystem = errorSystem
 	rpcCfg.MCPRouter = mcpRouter
+	rpcCfg.BuildTime = buildTime
 	rpcCfg.Translator = mcpTranslator
+	rpcCfg.WebRTCEngine = webrtcEngine
+	rpcCfg.WebRTCSessions = sessionMgr
+	rpcCfg.TURNManager = turnMgr
+	rpcCfg.Budget = budgetTracker
+	rpcCfg.HealthMonitor = healthMonitor
+	rpcCfg.PluginManager = pluginMgr
+	if licenseClient != nil {
+		rpcCfg.LicenseClient = licenseClient
+	}
+	rpcCfg.WebRTCTokens = tokenMgr
+	rpcCfg.PushGateway = pushGateway
+	rpcCfg.PushDispatcher = pushDispatcher
+	rpcCfg.PIIApprovalValidity = cfg.PIIApprovalValidityDuration()
 
 	if rolodexStore != nil && workflowOrchestrator != nil {
 		rpcCfg.SecretaryHandler = rpc.NewSecretaryHandler(secretary.NewRPCHandler(secretary.RPCHandlerConfig{
@@ -2603,6 +3285,7 @@ func runBridgeServer(cliCfg cliConfig) {
 	if pushGW == "" && matrixHS != "" {
 		pushGW = strings.TrimSuffix(matrixHS, "/") + "/_matrix/push/v1/notify"
 	}
+	advertiseIP := net.ParseIP(cfg.Discovery.AdvertiseIP) // nil unless pinned
 
 	if cfg.Discovery.Enabled && !cfg.HTTP.Enabled {
 		// Start HTTP discovery server (listens on port 8080)
@@ -2616,6 +3299,7 @@ func runBridgeServer(cliCfg cliConfig) {
 			APIPath:          cfg.Discovery.APIPath,
 			WSPath:           cfg.Discovery.WSPath,
 			Metrics:          metrics,
+			AdvertiseIP:      advertiseIP,
 		})
 		if err != nil {
 			log.Printf("Warning: Failed to create HTTP discovery server: %v", err)
@@ -2643,6 +3327,15 @@ func runBridgeServer(cliCfg cliConfig) {
 			ExtraTXT: map[string]string{
 				"hardware": cfg.Discovery.Hardware,
 			},
+			AdvertiseIP: advertiseIP,
+		}
+		if cfg.Discovery.AnnounceInterval != "" {
+			if d, err := time.ParseDuration(cfg.Discovery.AnnounceInterval); err == nil {
+				if d == 0 {
+					d = -1 // "0s" turns re-announcement off
+				}
+				discoveryConfig.AnnounceInterval = d
+			}
 		}
 
 		discoveryServer, err = discovery.NewServerWithConfig(discoveryConfig)
@@ -2690,12 +3383,17 @@ func runBridgeServer(cliCfg cliConfig) {
 			WSPath:           cfg.Discovery.WSPath,
 			Metrics:          metrics,
 			ServerMode:       cfg.Server.Mode,
+			AdvertiseIP:      advertiseIP,
+			AdminRoomID:      cfg.Notifications.AdminRoomID,
+			WSKeepalive:      cfg.EventBusKeepalive(),
 		}, server)
 
+		httpsServer.SetAuthMiddleware(newHTTPAuthMiddleware(cfg, provisioningMgr))
 		server.SetTLSInfoProvider(httpsServer)
 
 		if eventBus != nil && httpsServer != nil {
 			eventBus.SetBroadcaster(httpsServer)
+			httpsServer.SetEventReplayer(eventBus)
 		}
 		if eventBus != nil {
 			if err := eventBus.Start(); err != nil {
@@ -2714,6 +3412,26 @@ func runBridgeServer(cliCfg cliConfig) {
 		log.Printf("HTTPS bridge server: https://%s:%d", hostname, cfg.HTTP.Port)
 	}
 
+	// Start Prometheus metrics listener
+	var metricsServer *http.Server
+	if cfg.Metrics.Enabled {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", server.MetricsHandler())
+		metricsServer = &http.Server{
+			Addr:              cfg.Metrics.ListenAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Warning: metrics server failed: %v", err)
+			}
+		}()
+
+		log.Printf("Prometheus metrics: http://%s/metrics", cfg.Metrics.ListenAddr)
+	}
+
 	log.Println("ArmorClaw Bridge is running")
 	log.Println("Press Ctrl+C to stop")
 	log.Println("")
@@ -2721,6 +3439,22 @@ func runBridgeServer(cliCfg cliConfig) {
 	// Show connection guidance for ArmorChat
 	printConnectionGuidance(cfg)
 
+	// Reload safe settings on SIGHUP without restarting
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		current := cfg
+		for {
+			select {
+			case <-hupCh:
+				current = reloadConfig(cliCfg, current, errorSystem, budgetTracker)
+			case <-shutdownCtx.Done():
+				signal.Stop(hupCh)
+				return
+			}
+		}
+	}()
+
 	// Wait for interrupt signal
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -2745,6 +3479,11 @@ func runBridgeServer(cliCfg cliConfig) {
 			httpsServer.Stop(context.Background())
 		}
 
+		if metricsServer != nil {
+			log.Println("Stopping metrics server...")
+			metricsServer.Shutdown(context.Background())
+		}
+
 		// Stop WebRTC signaling server
 		if signalingSvr != nil {
 			log.Println("Stopping WebRTC signaling server...")
@@ -2771,6 +3510,10 @@ func runBridgeServer(cliCfg cliConfig) {
 		log.Println("Stopping health monitor...")
 		healthMonitor.Stop()
 
+		// Stop plugins
+		log.Println("Stopping plugins...")
+		pluginMgr.StopAll()
+
 		// Stop notifier
 		if notifier != nil {
 			log.Println("Stopping notifier...")
@@ -2799,6 +3542,34 @@ func runBridgeServer(cliCfg cliConfig) {
 	log.Println("ArmorClaw Bridge stopped")
 }
 
+// newHTTPAuthMiddleware authenticates RPC callers on the HTTPS server. They
+// present a Matrix access token (the session of a signed-in device) or a
+// provisioning admin token as a bearer token.
+func newHTTPAuthMiddleware(cfg *config.Config, provisioningMgr *provisioning.Manager) *auth.RPCAuthMiddleware {
+	mwCfg := auth.RPCAuthMiddlewareConfig{
+		PublicMethods: auth.DefaultPublicMethods,
+		AdminMethods:  auth.DefaultAdminMethods,
+	}
+	if provisioningMgr != nil {
+		mwCfg.AdminTokenValidator = provisioningMgr
+	}
+	if cfg.Matrix.Enabled && cfg.Matrix.HomeserverURL != "" {
+		provider, err := auth.NewMatrixAuthProvider(auth.MatrixAuthProviderConfig{
+			HomeserverURL: cfg.Matrix.HomeserverURL,
+		})
+		if err != nil {
+			log.Printf("Warning: Matrix token authentication unavailable for HTTPS RPC: %v", err)
+		} else {
+			mwCfg.Provider = provider
+		}
+	}
+
+	if mwCfg.Provider == nil && mwCfg.AdminTokenValidator == nil {
+		log.Println("Warning: no way to authenticate HTTPS RPC callers; only public methods will be served")
+	}
+	return auth.NewRPCAuthMiddleware(mwCfg)
+}
+
 // autoStoreKey automatically stores an API key from environment variable
 func autoStoreKey(apiKey string) {
 	// Detect provider from key format
@@ -2854,18 +3625,39 @@ func autoStoreKey(apiKey string) {
 
 // detectProviderFromKey attempts to detect the AI provider from the API key format
 func detectProviderFromKey(key string) string {
-	// OpenAI keys start with sk-
-	if strings.HasPrefix(key, "sk-") || strings.HasPrefix(key, "sk-proj-") {
-		return "openai"
-	}
-	// Anthropic keys start with sk-ant-
-	if strings.HasPrefix(key, "sk-ant-") {
+	switch {
+	// Anthropic (sk-ant-) and OpenRouter (sk-or-) share OpenAI's sk- prefix,
+	// so they must be checked first
+	case strings.HasPrefix(key, "sk-ant-"):
 		return "anthropic"
+	case strings.HasPrefix(key, "sk-or-"):
+		return "openrouter"
+	// OpenAI keys start with sk- (project keys with sk-proj-)
+	case strings.HasPrefix(key, "sk-"):
+		return "openai"
+	case strings.HasPrefix(key, "xai-") && len(key) > 20:
+		return "xai"
+	case isGoogleAPIKey(key):
+		return "google"
 	}
-	// Add more patterns as needed
 	return ""
 }
 
+// isGoogleAPIKey reports whether key looks like a Google API key: "AIza"
+// followed by 35 URL-safe base64 characters
+func isGoogleAPIKey(key string) bool {
+	if len(key) != 39 || !strings.HasPrefix(key, "AIza") {
+		return false
+	}
+	for _, c := range key[4:] {
+		isAlnum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlnum && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
 func parseFlags() cliConfig {
 	cfg := cliConfig{}
 
@@ -2891,21 +3683,24 @@ func parseFlags() cliConfig {
 	flag.StringVar(&cfg.addKeyToken, "t", "", "API token for add-key (short for --token)")
 	flag.StringVar(&cfg.addKeyToken, "token", "", "API token for add-key (or use ARMORCLAW_API_KEY env var)")
 	flag.StringVar(&cfg.addKeyId, "i", "", "Key ID for add-key (short for --id)")
-	flag.StringVar(&cfg.addKeyId, "id", "", "Key ID for add-key (default: <provider>-default)")
+	flag.StringVar(&cfg.addKeyId, "id", "", "Key ID for add-key (default: <provider>-default), or agent ID for stop-agent and agent-status")
 	flag.StringVar(&cfg.addKeyDisplayName, "n", "", "Display name for add-key (short for --display-name)")
 	flag.StringVar(&cfg.addKeyDisplayName, "display-name", "", "Display name for add-key")
 	flag.StringVar(&cfg.addKeyBaseURL, "b", "", "Base URL for OpenAI-compatible API providers (short for --base-url)")
 	flag.StringVar(&cfg.addKeyBaseURL, "base-url", "", "Base URL for OpenAI-compatible API providers")
 	flag.StringVar(&cfg.startKeyId, "key", "", "Key ID for start command")
+	flag.StringVar(&cfg.keysFile, "file", "", "Bundle path for export-keys/import-keys")
 	// QR code command flags
 	flag.StringVar(&cfg.qrHost, "host", "", "Host/domain for QR code (generate-qr command)")
 	flag.IntVar(&cfg.qrPort, "port", 0, "Port for QR code (generate-qr command)")
+	flag.StringVar(&cfg.qrOutput, "output", "", "Write the QR code as a PNG to this path (generate-qr command)")
 	// Agent command flags
 	flag.StringVar(&cfg.agentType, "type", "assistant", "Agent type (start-agent command)")
 	flag.StringVar(&cfg.agentName, "agent-name", "", "Agent display name (start-agent command)")
 	flag.StringVar(&cfg.agentRoom, "room", "", "Matrix room ID for agent (start-agent command)")
 	flag.StringVar(&cfg.agentKey, "agent-key", "", "API key ID for agent (start-agent command)")
 	flag.StringVar(&cfg.agentCapabilities, "capabilities", "chat", "Comma-separated capabilities (start-agent command)")
+	flag.StringVar(&cfg.logFormat, "log-format", "", "Log format: json, text (set-log-level command)")
 
 	// Pre-parse to extract command first (before full flag parsing)
 	// This handles: armorclaw-bridge add-key --provider openai
@@ -2956,6 +3751,9 @@ func setupLogging(cfg config.LoggingConfig) {
 
 func printVersion() {
 	fmt.Printf("ArmorClaw Bridge v%s\n", version)
+	if gitCommit != "" {
+		fmt.Printf("Git commit: %s\n", gitCommit)
+	}
 	fmt.Printf("Build time: %s\n", buildTime)
 	fmt.Println("License: MIT")
 	fmt.Println("https://github.com/Gemutly/ArmorClaw")
@@ -2968,12 +3766,19 @@ func printHelp() {
 COMMANDS:
     init              Initialize configuration file
     validate          Validate configuration
+    doctor            Check Docker, socket, keystore, Matrix and license server
+    config migrate    Upgrade configuration file to the current version
     setup             Run interactive setup wizard (Huh? TUI)
     container-setup   Run container setup wizard (Huh? TUI + infrastructure)
     add-key           Add an API key to the keystore
     list-keys   List all stored API keys
+    export-keys Export API keys to a passphrase-encrypted bundle
+    import-keys Import API keys from an exported bundle
     start       Start an agent container (legacy, use start-agent)
     start-agent Start an AI agent (OpenClaw, assistant, etc.)
+    stop-agent  Stop a running AI agent
+    agent-status Show an AI agent's status, uptime and room
+    set-log-level Change a running bridge's log level/format
     generate-qr Generate QR code for ArmorChat discovery
     completion  Generate shell completion script
     version     Show version information
@@ -3020,6 +3825,7 @@ CONFIGURATION:
 ENVIRONMENT VARIABLES:
     ARMORCLAW_API_KEY     API key (auto-stored on bridge startup)
     ARMORCLAW_CONFIG      Path to configuration file
+    ARMORCLAW_EXPORT_PASSPHRASE  Passphrase for export-keys/import-keys
 
 DOCUMENTATION:
     https://github.com/Gemutly/ArmorClaw
@@ -3271,6 +4077,42 @@ EXAMPLES:
 
     # No keys? Add one:
     armorclaw-bridge add-key --provider openai --token sk-proj-...
+`
+	case "export-keys":
+		help = `COMMAND: export-keys
+
+Export all stored API keys to a portable bundle. The bundle is encrypted
+with a passphrase rather than the hardware-derived key, so it can be
+imported on another machine with import-keys.
+
+USAGE:
+    armorclaw-bridge export-keys --file PATH [-c|--config path]
+
+FLAGS:
+    --file string   Path to write the bundle to (created with mode 0600)
+
+The passphrase is read from ARMORCLAW_EXPORT_PASSPHRASE or prompted for.
+It must be at least 8 characters.
+
+EXAMPLES:
+    armorclaw-bridge export-keys --file ~/armorclaw-keys.json
+`
+	case "import-keys":
+		help = `COMMAND: import-keys
+
+Import API keys from a bundle created by export-keys. Keys with the same
+ID as an existing key are overwritten.
+
+USAGE:
+    armorclaw-bridge import-keys --file PATH [-c|--config path]
+
+FLAGS:
+    --file string   Path of the bundle to import
+
+The passphrase is read from ARMORCLAW_EXPORT_PASSPHRASE or prompted for.
+
+EXAMPLES:
+    armorclaw-bridge import-keys --file ~/armorclaw-keys.json
 `
 	case "start":
 		help = `COMMAND: start
@@ -3302,16 +4144,18 @@ This command creates a signed configuration URL that ArmorChat can use
 to automatically discover and connect to this bridge.
 
 USAGE:
-    armorclaw-bridge generate-qr [--host hostname] [--port port]
+    armorclaw-bridge generate-qr [--host hostname] [--port port] [--output file.png]
 
 FLAGS:
-    --host string   Public hostname/domain (default: system hostname)
-    --port int      Public port (default: from config)
+    --host string     Public hostname/domain (default: system hostname)
+    --port int        Public port (default: from config)
+    --output string   Also write the QR code as a PNG image to this path
 
 OUTPUT:
     • Deep link URL (armorclaw://config?d=...)
     • Web link URL (https://armorclaw.app/config?d=...)
     • Configuration summary
+    • Scannable QR code rendered in the terminal
 
 EXAMPLES:
     # Generate QR with defaults
@@ -3323,6 +4167,9 @@ EXAMPLES:
     # Generate QR for local development
     armorclaw-bridge generate-qr --host 192.168.1.100
 
+    # Save the QR code as an image to share or print
+    armorclaw-bridge generate-qr --host bridge.example.com --output armorchat-qr.png
+
 DISCOVERY METHODS:
     ArmorChat supports multiple discovery methods:
 
@@ -3379,8 +4226,11 @@ AGENT MANAGEMENT:
     After starting, users can interact with the agent in the Matrix room.
     The agent will respond to messages based on its configuration.
 
+    To check on an agent:
+    armorclaw-bridge agent-status --id AGENT_ID
+
     To stop an agent:
-    • Via RPC: echo '{"method":"agent.stop","params":{"agent_id":"xxx"}}' | socat - UNIX-CONNECT:/run/armorclaw/bridge.sock
+    armorclaw-bridge stop-agent --id AGENT_ID
 
 DOCKER COMPOSE:
     For OpenClaw agents, you can also use Docker Compose:
@@ -3390,6 +4240,81 @@ DOCKER COMPOSE:
     This requires:
     • ARMORCLAW_MATRIX_ROOM environment variable set
     • OpenClaw container image built
+`
+	case "stop-agent":
+		help = `COMMAND: stop-agent
+
+Stop a running AI agent via the bridge RPC (agent.stop).
+
+USAGE:
+    armorclaw-bridge stop-agent --id AGENT_ID [flags]
+
+FLAGS:
+    --id string       Agent ID, as printed by start-agent (required)
+    --socket string   Bridge socket path (default: /run/armorclaw/bridge.sock)
+
+EXAMPLES:
+    # Stop an agent started with start-agent
+    armorclaw-bridge stop-agent --id assistant-1760000000
+`
+	case "agent-status":
+		help = `COMMAND: agent-status
+
+Show an AI agent's status, uptime and room via the bridge RPC (agent.status).
+
+USAGE:
+    armorclaw-bridge agent-status --id AGENT_ID [flags]
+
+FLAGS:
+    --id string       Agent ID, as printed by start-agent (required)
+    --socket string   Bridge socket path (default: /run/armorclaw/bridge.sock)
+
+EXAMPLES:
+    # Check an agent started with start-agent
+    armorclaw-bridge agent-status --id assistant-1760000000
+`
+	case "doctor":
+		help = `COMMAND: doctor
+
+Check everything the bridge needs before it starts and print a pass/fail
+report with a fix for each problem: configuration, Docker, the socket
+directory, the keystore, Matrix reachability and login, and the license
+server. Exits with status 1 when any check fails.
+
+The Matrix check logs in as the configured account on a separate
+"armorclaw-doctor" device, so a running bridge keeps its session.
+
+USAGE:
+    armorclaw-bridge doctor [-c|--config path]
+
+EXAMPLES:
+    # Check a first-time install
+    armorclaw-bridge doctor
+
+    # Check a specific configuration
+    armorclaw-bridge doctor -c /etc/armorclaw/config.toml
+`
+	case "set-log-level":
+		help = `COMMAND: set-log-level
+
+Change a running bridge's log level and/or format via the bridge RPC
+(log.set_level). Takes effect from the next log line, for every component,
+until the bridge restarts.
+
+USAGE:
+    armorclaw-bridge set-log-level [--log-level LEVEL] [--log-format FORMAT] [flags]
+
+FLAGS:
+    --log-level string    debug, info, warn or error
+    --log-format string   json or text
+    --socket string       Bridge socket path (default: /run/armorclaw/bridge.sock)
+
+EXAMPLES:
+    # Switch to verbose JSON while reproducing an error
+    armorclaw-bridge set-log-level --log-level debug --log-format json
+
+    # Back to normal
+    armorclaw-bridge set-log-level --log-level info --log-format text
 `
 	case "completion":
 		help = `COMMAND: completion
@@ -3407,6 +4332,27 @@ EXAMPLES:
     # Generate zsh completion
     armorclaw-bridge completion zsh > ~/.zsh/completions/_armorclaw-bridge
     # Then add to ~/.zshrc: autoload -U compinit && compinit
+`
+	case "config":
+		help = `COMMAND: config
+
+Maintain the configuration file.
+
+USAGE:
+    armorclaw-bridge config migrate [-c|--config path]
+
+ACTIONS:
+    migrate    Upgrade the file to the current config version: renamed
+               settings are moved to their new names, missing settings are
+               filled with defaults and the file is stamped with the version.
+               The original is kept as <path>.bak.
+
+EXAMPLES:
+    # Migrate default config
+    armorclaw-bridge config migrate
+
+    # Migrate custom config
+    armorclaw-bridge config migrate -c /path/to/config.toml
 `
 	case "validate":
 		help = `COMMAND: validate
@@ -3447,6 +4393,27 @@ func (s *studioMatrixAdapter) ReplyToEvent(ctx context.Context, roomID, eventID,
 	return s.adapter.ReplyToEvent(ctx, roomID, eventID, message)
 }
 
+// errorsMatrixSender wraps adapter.MatrixAdapter to satisfy
+// errors.MatrixMessageSender and errors.MatrixHTMLSender. The adapter has no
+// context support, so ctx is only checked before sending.
+type errorsMatrixSender struct {
+	adapter *adapter.MatrixAdapter
+}
+
+func (s *errorsMatrixSender) SendMessage(ctx context.Context, roomID, message, msgType string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return s.adapter.SendMessage(roomID, message, msgType)
+}
+
+func (s *errorsMatrixSender) SendHTMLMessage(ctx context.Context, roomID, message, formattedBody, msgType string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return s.adapter.SendHTMLMessage(roomID, message, formattedBody, msgType)
+}
+
 // studioFactoryAdapter bridges studio.AgentFactory to secretary.FactoryInterface
 type studioFactoryAdapter struct {
 	factory *studio.AgentFactory
@@ -3481,13 +4448,25 @@ func (s *schedulerMatrixAdapter) SendEvent(ctx context.Context, roomID, eventTyp
 	return s.adapter.SendEvent(roomID, eventType, data)
 }
 
-// compositeStudioHandler tries studio commands first, then secretary commands
+// compositeStudioHandler tries error commands from the admin room first,
+// then studio commands, then secretary commands
 type compositeStudioHandler struct {
+	matrix    *adapter.MatrixAdapter
+	errors    *errors.System
 	studio    *studio.StudioIntegration
 	secretary *secretary.SecretaryCommandHandler
 }
 
 func (c *compositeStudioHandler) HandleMatrixMessage(ctx context.Context, roomID, userID, eventID, text string) bool {
+	// Error triage commands (!errors *), answered in the admin room
+	if c.errors != nil {
+		if reply, ok := c.errors.HandleCommand(ctx, roomID, userID, text); ok {
+			if _, err := c.matrix.SendMessage(roomID, reply, "m.notice"); err != nil {
+				log.Printf("Warning: failed to answer error command: %v", err)
+			}
+			return true
+		}
+	}
 	// Try studio commands (!agent *) first
 	if c.studio != nil {
 		if c.studio.HandleMatrixMessage(ctx, roomID, userID, eventID, text) {
@@ -3506,6 +4485,9 @@ type toolsidecarDockerAdapter struct {
 }
 
 func (a *toolsidecarDockerAdapter) ContainerCreate(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig any, platform any, name string) (container.CreateResponse, error) {
+	if err := a.client.EnsureImage(ctx, config.Image); err != nil {
+		return container.CreateResponse{}, err
+	}
 	id, err := a.client.CreateContainer(ctx, config, hostConfig, nil, nil)
 	if err != nil {
 		return container.CreateResponse{}, err
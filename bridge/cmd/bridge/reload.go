@@ -0,0 +1,101 @@
+package main
+
+import (
+	"log"
+	"reflect"
+
+	"github.com/armorclaw/bridge/pkg/budget"
+	"github.com/armorclaw/bridge/pkg/config"
+	"github.com/armorclaw/bridge/pkg/errors"
+	"github.com/armorclaw/bridge/pkg/logger"
+)
+
+// applyCLIOverrides applies command-line flags on top of a loaded config
+func applyCLIOverrides(cfg *config.Config, cliCfg cliConfig) {
+	if cliCfg.socketPath != "" {
+		cfg.Server.SocketPath = cliCfg.socketPath
+	}
+	if cliCfg.dbPath != "" {
+		cfg.Keystore.DBPath = cliCfg.dbPath
+	}
+	if cliCfg.matrixHomeserver != "" {
+		cfg.Matrix.HomeserverURL = cliCfg.matrixHomeserver
+		cfg.Matrix.Enabled = true
+	}
+	if cliCfg.matrixUsername != "" {
+		cfg.Matrix.Username = cliCfg.matrixUsername
+	}
+	if cliCfg.matrixPassword != "" {
+		cfg.Matrix.Password = cliCfg.matrixPassword
+	}
+	if cliCfg.matrixEnabled {
+		cfg.Matrix.Enabled = true
+	}
+	if cliCfg.logLevel != "" {
+		cfg.Logging.Level = cliCfg.logLevel
+	}
+}
+
+// reloadConfig re-reads the configuration on SIGHUP and applies the settings
+// that can change while running: log level, budget limits, error
+// notification rate limits and the admin MXID. Other changes are logged as
+// requiring a restart. An unreadable or invalid file leaves the running
+// configuration untouched. It returns the configuration now in effect.
+func reloadConfig(cliCfg cliConfig, current *config.Config, errorSystem *errors.System, budgetTracker *budget.BudgetTracker) *config.Config {
+	log.Println("Reloading configuration...")
+
+	next, err := config.Load(cliCfg.configPath)
+	if err != nil {
+		log.Printf("Warning: Config reload failed, keeping current configuration: %v", err)
+		return current
+	}
+	applyCLIOverrides(next, cliCfg)
+	if err := next.Validate(); err != nil {
+		log.Printf("Warning: Reloaded configuration is invalid, keeping current configuration: %v", err)
+		return current
+	}
+
+	applied := *current
+
+	if next.Logging.Level != current.Logging.Level {
+		if err := logger.SetLevel(next.Logging.Level); err != nil {
+			log.Printf("Warning: Failed to change log level: %v", err)
+		} else {
+			applied.Logging.Level = next.Logging.Level
+			log.Printf("Log level: %s -> %s", current.Logging.Level, next.Logging.Level)
+		}
+	}
+
+	// The reset schedule keys recorded usage, so it only changes on restart
+	nextBudget := next.Budget
+	nextBudget.ResetSchedule = current.Budget.ResetSchedule
+	if !reflect.DeepEqual(nextBudget, current.Budget) {
+		budgetTracker.UpdateLimits(next.ToBudgetConfig())
+		applied.Budget = nextBudget
+		log.Printf("Budget limits: daily $%.2f, monthly $%.2f, alert at %.0f%%, mode %s",
+			nextBudget.DailyLimitUSD, nextBudget.MonthlyLimitUSD, nextBudget.AlertThreshold, next.ToBudgetConfig().EnforcementMode())
+	}
+
+	if errorSystem != nil {
+		if next.ErrorSystem.RateLimitWindow != current.ErrorSystem.RateLimitWindow ||
+			!reflect.DeepEqual(next.ErrorSystem.CodeWindows, current.ErrorSystem.CodeWindows) {
+			errorCfg := next.ToErrorSystemConfig()
+			errorSystem.SetRateLimits(errorCfg.RateLimitWindow, errorCfg.CodeWindows)
+			applied.ErrorSystem.RateLimitWindow = next.ErrorSystem.RateLimitWindow
+			applied.ErrorSystem.CodeWindows = next.ErrorSystem.CodeWindows
+			log.Printf("Error notification rate limit window: %s", next.ErrorSystem.RateLimitWindow)
+		}
+		if next.ErrorSystem.AdminMXID != current.ErrorSystem.AdminMXID {
+			errorSystem.SetAdminMXID(next.ErrorSystem.AdminMXID)
+			applied.ErrorSystem.AdminMXID = next.ErrorSystem.AdminMXID
+			log.Printf("Error notification admin: %s", next.ErrorSystem.AdminMXID)
+		}
+	}
+
+	for _, key := range config.RestartRequired(&applied, next) {
+		log.Printf("Config change to %s requires a restart to take effect", key)
+	}
+
+	log.Println("Configuration reloaded")
+	return &applied
+}
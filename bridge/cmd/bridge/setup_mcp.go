@@ -4,6 +4,8 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/armorclaw/bridge/pkg/config"
 	"github.com/armorclaw/bridge/pkg/governor"
 	"github.com/armorclaw/bridge/pkg/mcp"
+	"github.com/armorclaw/bridge/pkg/notification"
 	"github.com/armorclaw/bridge/pkg/pii"
 	"github.com/armorclaw/bridge/pkg/toolsidecar"
 	"github.com/armorclaw/bridge/pkg/translator"
@@ -19,7 +22,7 @@ import (
 
 // setupMCPRouter initializes the v6 MCP Router when V6Microkernel is enabled.
 // Returns the router and RPC-to-MCP translator (either may be nil if disabled or on error).
-func setupMCPRouter(cfg *config.Config, toolsidecarDocker *toolsidecarDockerAdapter, vaultClient *vault.VaultGovernanceClient) (*mcp.MCPRouter, *translator.RPCToMCPTranslator) {
+func setupMCPRouter(cfg *config.Config, auditor *audit.AuditLog, toolsidecarDocker *toolsidecarDockerAdapter, vaultClient *vault.VaultGovernanceClient, notifier *notification.Notifier) (*mcp.MCPRouter, *translator.RPCToMCPTranslator) {
 	var mcpRouter *mcp.MCPRouter
 	var mcpTranslator *translator.RPCToMCPTranslator
 
@@ -35,11 +38,14 @@ func setupMCPRouter(cfg *config.Config, toolsidecarDocker *toolsidecarDockerAdap
 			log.Printf("V6 Microkernel disabled: toolsidecar provisioner: %v", provErr)
 		} else {
 			consentMgr := pii.NewHITLConsentManager(pii.HITLConfig{
-				Timeout: 60 * time.Second,
+				Timeout:                cfg.HITLTimeoutDuration(),
+				TimeoutAction:          pii.TimeoutAction(cfg.Compliance.HITLTimeoutAction),
+				WarnBefore:             cfg.HITLTimeoutWarningDuration(),
+				TimeoutWarningCallback: consentAdminAlert(notifier, "hitl_expiring"),
+				EscalationCallback:     consentAdminAlert(notifier, "hitl_escalated"),
 			})
-			auditor, auditErr := audit.NewAuditLog(audit.DefaultConfig())
-			if auditErr != nil {
-				log.Printf("V6 Microkernel disabled: audit log: %v", auditErr)
+			if auditor == nil {
+				log.Println("V6 Microkernel disabled: audit log unavailable")
 			} else {
 				var err error
 				mcpRouter, err = mcp.New(mcp.Config{
@@ -62,3 +68,22 @@ func setupMCPRouter(cfg *config.Config, toolsidecarDocker *toolsidecarDockerAdap
 
 	return mcpRouter, mcpTranslator
 }
+
+// consentAdminAlert returns a HITL consent callback that posts a security
+// alert to the admin room, or nil when notifications are disabled.
+func consentAdminAlert(notifier *notification.Notifier, eventType string) func(ctx context.Context, request *pii.AccessRequest) error {
+	if notifier == nil {
+		return nil
+	}
+	return func(ctx context.Context, request *pii.AccessRequest) error {
+		message := fmt.Sprintf("PII access request %s from %s needs a decision before %s",
+			request.ID, request.SkillName, request.ExpiresAt.Format(time.RFC3339))
+		return notifier.SendSecurityAlert(eventType, message, map[string]interface{}{
+			"request_id":     request.ID,
+			"skill_id":       request.SkillID,
+			"profile_id":     request.ProfileID,
+			"expires_at":     request.ExpiresAt,
+			"timeout_action": string(request.TimeoutAction),
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/armorclaw/bridge/internal/events"
 	"github.com/armorclaw/bridge/pkg/browser"
 	"github.com/armorclaw/bridge/pkg/config"
+	"github.com/armorclaw/bridge/pkg/errors"
 	"github.com/armorclaw/bridge/pkg/keystore"
 	"github.com/armorclaw/bridge/pkg/secretary"
 	"github.com/armorclaw/bridge/pkg/studio"
@@ -149,6 +150,15 @@ func setupWorkflowEngine(rolodexStore secretary.Store, matrixBus *events.MatrixE
 			log.Printf("Warning: failed to create workflow orchestrator: %v", orchErr)
 			workflowOrchestrator = nil
 		}
+		if workflowOrchestrator != nil {
+			// Pick up workflows that were running or blocked before a restart
+			recovered, err := workflowOrchestrator.RecoverWorkflows()
+			if err != nil {
+				log.Printf("Warning: failed to recover workflows: %v", err)
+			} else if recovered > 0 {
+				log.Printf("Recovered %d workflow(s) from before restart", recovered)
+			}
+		}
 	}
 
 	var orchestratorIntegration *secretary.OrchestratorIntegration
@@ -203,6 +213,7 @@ func setupSecretaryCommandHandler(
 	calendarService *secretary.CalendarService,
 	approvalEngine *secretary.ApprovalEngineImpl,
 	trustEngine *secretary.TrustedWorkflowEngine,
+	errorSystem *errors.System,
 ) *secretary.TaskScheduler {
 	if matrixAdapter != nil {
 		secretaryHandler := secretary.NewSecretaryCommandHandler(secretary.SecretaryCommandHandlerConfig{
@@ -219,6 +230,8 @@ func setupSecretaryCommandHandler(
 			TrustEngine:    trustEngine,
 		})
 		matrixAdapter.SetStudioCommandHandler(&compositeStudioHandler{
+			matrix:    matrixAdapter,
+			errors:    errorSystem,
 			studio:    studioService,
 			secretary: secretaryHandler,
 		})
@@ -12,6 +12,7 @@ require (
 	github.com/hashicorp/mdns v1.0.6
 	github.com/hillu/go-yara/v4 v4.3.4
 	github.com/mattn/go-sqlite3 v1.14.42
+	github.com/miekg/dns v1.1.55
 	github.com/mutecomm/go-sqlcipher/v4 v4.4.2
 	github.com/opencontainers/image-spec v1.1.1
 	github.com/pion/rtp v1.8.3
@@ -69,7 +70,6 @@ require (
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-localereader v0.0.1 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
-	github.com/miekg/dns v1.1.55 // indirect
 	github.com/mitchellh/hashstructure/v2 v2.0.2 // indirect
 	github.com/moby/docker-image-spec v1.3.1 // indirect
 	github.com/moby/sys/atomicwriter v0.1.0 // indirect
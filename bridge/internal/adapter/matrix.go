@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -46,6 +47,12 @@ type MatrixAdapter struct {
 	cancel           context.CancelFunc
 	piiScrubber      *pii.Scrubber
 	lastExpiryCheck  time.Time               // P1-HIGH-1: Track last token expiry check
+	tokenExpiresAt   time.Time               // When the access token expires (zero if it does not)
+	refreshMargin    time.Duration           // How long before expiry to refresh the access token
+	refreshFailures  int                     // Consecutive failed proactive refreshes
+	maxRetries       int                     // Resends after a transient send failure
+	retryDelay       time.Duration           // Backoff before the first resend
+	backoffFactor    float64                 // Backoff growth per resend
 	commandHandler   *CommandHandler         // Command handler for admin operations
 	studioCmdHandler StudioCommandHandler    // Studio command handler for Agent Factory
 	trustVerifier    *TrustVerifier          // Zero-trust verification
@@ -155,10 +162,49 @@ type Config struct {
 	RejectUntrusted bool     // If true, return error to sender; if false, drop silently
 	RefreshToken    string   // P1-HIGH-1: Encrypted refresh token from login
 
+	// TokenRefreshMargin is how long before expiry the access token is
+	// refreshed. Zero uses DefaultTokenRefreshMargin.
+	TokenRefreshMargin time.Duration
+
+	// Send retry policy for transient failures (network errors, 429, 5xx).
+	// MaxRetries of zero disables retries; a zero RetryDelay uses
+	// DefaultRetryDelay and a BackoffMultiplier below 1 uses 2.
+	MaxRetries        int
+	RetryDelay        time.Duration
+	BackoffMultiplier float64
+
+	// EventBufferSize bounds the event bus that buffers received events
+	// for cursor-based readers (0 = events.DefaultMaxEvents)
+	EventBufferSize int
+
 	// Trust verification settings
 	MinTrustLevel trust.TrustScore // Minimum trust level required for message processing
 }
 
+const (
+	// DefaultTokenRefreshMargin is how long before expiry the access token
+	// is refreshed when Config.TokenRefreshMargin is not set
+	DefaultTokenRefreshMargin = 5 * time.Minute
+
+	// HTMLFormat is the only formatted_body format defined by the Matrix spec
+	HTMLFormat = "org.matrix.custom.html"
+
+	// DefaultRetryDelay is the backoff before the first resend when
+	// Config.RetryDelay is not set
+	DefaultRetryDelay = 1 * time.Second
+
+	// maxRetryWait caps a single backoff. A homeserver asking to wait
+	// longer than this gets the failure surfaced instead.
+	maxRetryWait = 60 * time.Second
+
+	// maxTokenRefreshFailures is how many consecutive failed refreshes are
+	// tolerated before MAT-004 is raised
+	maxTokenRefreshFailures = 3
+)
+
+// tokenCheckInterval is how often the token refresh loop checks expiry
+var tokenCheckInterval = 30 * time.Second
+
 // New creates a new Matrix adapter
 func New(cfg Config) (*MatrixAdapter, error) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -169,6 +215,20 @@ func New(cfg Config) (*MatrixAdapter, error) {
 		minTrustLevel = trust.TrustScoreLow // Default: allow low trust and above
 	}
 
+	refreshMargin := cfg.TokenRefreshMargin
+	if refreshMargin <= 0 {
+		refreshMargin = DefaultTokenRefreshMargin
+	}
+
+	retryDelay := cfg.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = DefaultRetryDelay
+	}
+	backoffFactor := cfg.BackoffMultiplier
+	if backoffFactor < 1 {
+		backoffFactor = 2
+	}
+
 	return &MatrixAdapter{
 		homeserverURL:   cfg.HomeserverURL,
 		deviceID:        cfg.DeviceID,
@@ -177,6 +237,10 @@ func New(cfg Config) (*MatrixAdapter, error) {
 		trustedRooms:    cfg.TrustedRooms,
 		rejectUntrusted: cfg.RejectUntrusted,
 		refreshToken:    cfg.RefreshToken,
+		refreshMargin:   refreshMargin,
+		maxRetries:      cfg.MaxRetries,
+		retryDelay:      retryDelay,
+		backoffFactor:   backoffFactor,
 		minTrustLevel:   minTrustLevel,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
@@ -185,6 +249,7 @@ func New(cfg Config) (*MatrixAdapter, error) {
 			Timeout: 90 * time.Second,
 		},
 		eventQueue:  make(chan *MatrixEvent, 100),
+		eventBus:    events.NewMatrixEventBus(cfg.EventBufferSize),
 		ctx:         ctx,
 		cancel:      cancel,
 		piiScrubber: pii.New(),
@@ -201,8 +266,9 @@ func (m *MatrixAdapter) Login(username, password string) error {
 			"type": "m.id.user",
 			"user": username,
 		},
-		"password":  password,
-		"device_id": m.deviceID,
+		"password":      password,
+		"device_id":     m.deviceID,
+		"refresh_token": true, // Ask for expiring tokens so they can be refreshed
 	}
 
 	body, err := json.Marshal(payload)
@@ -262,6 +328,7 @@ func (m *MatrixAdapter) Login(username, password string) error {
 		DeviceID     string `json:"device_id"`
 		UserID       string `json:"user_id"`
 		RefreshToken string `json:"refresh_token"` // P1-HIGH-1: Capture refresh token
+		ExpiresIn    int64  `json:"expires_in_ms"` // Omitted when the token does not expire
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -278,6 +345,7 @@ func (m *MatrixAdapter) Login(username, password string) error {
 	m.accessToken = result.AccessToken
 	m.userID = result.UserID
 	m.refreshToken = result.RefreshToken // P1-HIGH-1: Store refresh token for long-lived sessions
+	m.tokenExpiresAt = tokenExpiry(result.ExpiresIn)
 	m.mu.Unlock()
 
 	matrixTracker.Success("login", map[string]any{"user_id": result.UserID})
@@ -286,7 +354,14 @@ func (m *MatrixAdapter) Login(username, password string) error {
 
 // SendMessage sends a message to a Matrix room
 func (m *MatrixAdapter) SendMessage(roomID, message, msgType string) (string, error) {
-	matrixTracker.Event("send_message", map[string]any{"room_id": roomID, "msg_type": msgType})
+	return m.SendHTMLMessage(roomID, message, "", msgType)
+}
+
+// SendHTMLMessage sends a message with an org.matrix.custom.html
+// formatted_body alongside the plain body, which clients that cannot render
+// HTML fall back to. An empty formattedBody sends a plain message.
+func (m *MatrixAdapter) SendHTMLMessage(roomID, message, formattedBody, msgType string) (string, error) {
+	matrixTracker.Event("send_message", map[string]any{"room_id": roomID, "msg_type": msgType, "formatted": formattedBody != ""})
 
 	// P1-HIGH-1: Ensure token is valid before sending
 	if err := m.ensureValidToken(); err != nil {
@@ -326,6 +401,11 @@ func (m *MatrixAdapter) SendMessage(roomID, message, msgType string) (string, er
 		"msgtype": msgType,
 		"body":    scrubbedMessage,
 	}
+	if formattedBody != "" {
+		scrubbedHTML, _ := m.piiScrubber.Scrub(formattedBody)
+		payload["format"] = HTMLFormat
+		payload["formatted_body"] = scrubbedHTML
+	}
 
 	body, err := json.Marshal(payload)
 	if err != nil {
@@ -357,33 +437,16 @@ func (m *MatrixAdapter) SendMessage(roomID, message, msgType string) (string, er
 	u.Path = fmt.Sprintf("/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
 		roomID, txnID)
 
-	req, err := http.NewRequestWithContext(
-		m.ctx,
-		"PUT",
-		u.String(),
-		bytes.NewReader(body),
-	)
-	if err != nil {
-		err := errsys.NewBuilder("MAT-021").
-			Wrap(fmt.Errorf("failed to create request: %w", err)).
-			WithFunction("SendMessage").
-			WithInputs(map[string]any{"room_id": roomID}).
-			Build()
-		matrixTracker.Failure("send_message", err, map[string]any{"reason": "request_create_failed"})
-		return "", err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := m.httpClient.Do(req)
+	// The transaction ID stays the same across retries, so the homeserver
+	// deduplicates a send whose response was lost
+	resp, attempts, err := m.putWithRetry(u.String(), token, body)
 	if err != nil {
 		err := errsys.NewBuilder("MAT-021").
-			Wrap(fmt.Errorf("send request failed: %w", err)).
+			Wrap(fmt.Errorf("send request failed after %d attempts: %w", attempts, err)).
 			WithFunction("SendMessage").
-			WithInputs(map[string]any{"room_id": roomID}).
+			WithInputs(map[string]any{"room_id": roomID, "attempts": attempts}).
 			Build()
-		matrixTracker.Failure("send_message", err, map[string]any{"reason": "request_failed"})
+		matrixTracker.Failure("send_message", err, map[string]any{"reason": "request_failed", "attempts": attempts})
 		return "", err
 	}
 	defer resp.Body.Close()
@@ -391,11 +454,11 @@ func (m *MatrixAdapter) SendMessage(roomID, message, msgType string) (string, er
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		err := errsys.NewBuilder("MAT-021").
-			Wrap(fmt.Errorf("send failed: status %d, response: %s", resp.StatusCode, string(body))).
+			Wrap(fmt.Errorf("send failed after %d attempts: status %d, response: %s", attempts, resp.StatusCode, string(body))).
 			WithFunction("SendMessage").
-			WithInputs(map[string]any{"room_id": roomID, "status": resp.StatusCode}).
+			WithInputs(map[string]any{"room_id": roomID, "status": resp.StatusCode, "attempts": attempts}).
 			Build()
-		matrixTracker.Failure("send_message", err, map[string]any{"reason": "send_failed", "status": resp.StatusCode})
+		matrixTracker.Failure("send_message", err, map[string]any{"reason": "send_failed", "status": resp.StatusCode, "attempts": attempts})
 		return "", err
 	}
 
@@ -869,9 +932,10 @@ func (m *MatrixAdapter) ReceiveEvents() <-chan *MatrixEvent {
 	return m.eventQueue
 }
 
-// StartSync begins the background sync loop
+// StartSync begins the background sync and token refresh loops
 func (m *MatrixAdapter) StartSync() {
 	go m.syncLoop()
+	go m.tokenRefreshLoop()
 }
 
 // syncLoop runs the continuous sync loop
@@ -1132,53 +1196,103 @@ func isRetryableStatusCode(statusCode int) bool {
 	return statusCode >= 500 && statusCode < 600
 }
 
-// SendMessageWithRetry sends a message with retry logic for transient failures
+// SendMessageWithRetry sends a message with retry logic for transient failures.
+// SendMessage itself retries according to the adapter's retry policy; this
+// is kept for callers written against the older API.
 func (m *MatrixAdapter) SendMessageWithRetry(roomID, message, msgType string) (string, error) {
-	const maxAttempts = 3
-	const baseDelay = 1 * time.Second
+	return m.SendMessage(roomID, message, msgType)
+}
 
-	matrixTracker.Event("send_message_retry", map[string]any{"room_id": roomID, "max_attempts": maxAttempts})
+// putWithRetry sends an idempotent PUT, retrying network errors, 429 and
+// 5xx responses up to maxRetries times with exponential backoff. A 429's
+// Retry-After header or retry_after_ms field replaces the computed backoff.
+// The final response is returned with its body unread, along with the
+// number of attempts made.
+func (m *MatrixAdapter) putWithRetry(target, token string, body []byte) (*http.Response, int, error) {
+	delay := m.retryDelay
+
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(m.ctx, "PUT", target, bytes.NewReader(body))
+		if err != nil {
+			return nil, attempt, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
 
-	var lastErr error
+		resp, err := m.httpClient.Do(req)
+		lastAttempt := attempt > m.maxRetries
 
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		eventID, err := m.SendMessage(roomID, message, msgType)
-		if err == nil {
-			return eventID, nil
+		wait := delay
+		if err != nil {
+			if lastAttempt || m.ctx.Err() != nil || !isRetryableHTTPError(err) {
+				return nil, attempt, err
+			}
+		} else {
+			if lastAttempt || (resp.StatusCode != http.StatusTooManyRequests && !isRetryableStatusCode(resp.StatusCode)) {
+				return resp, attempt, nil
+			}
+			// Buffer the body so the response can still be returned if the
+			// homeserver asks for a longer wait than we are willing to make
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if after := retryAfter(resp.Header, data); after > 0 {
+					wait = after
+				}
+			}
 		}
 
-		// Check if error is retryable
-		if !isRetryableHTTPError(err) {
-			return "", err // Non-retryable error
+		if wait > maxRetryWait {
+			return resp, attempt, err
 		}
 
-		lastErr = err
+		logger.Global().Warn("Matrix send failed, retrying",
+			"attempt", attempt,
+			"max_retries", m.maxRetries,
+			"backoff", wait,
+			"status", statusOf(resp),
+			"error", err,
+		)
 
-		// Don't wait after the last attempt
-		if attempt < maxAttempts-1 {
-			backoff := baseDelay * time.Duration(1<<uint(attempt))
+		select {
+		case <-time.After(wait):
+		case <-m.ctx.Done():
+			return nil, attempt, m.ctx.Err()
+		}
+		delay = time.Duration(float64(delay) * m.backoffFactor)
+	}
+}
 
-			select {
-			case <-time.After(backoff):
-			case <-m.ctx.Done():
-				err := errsys.NewBuilder("MAT-021").
-					Wrap(m.ctx.Err()).
-					WithFunction("SendMessageWithRetry").
-					WithInputs(map[string]any{"room_id": roomID, "attempt": attempt}).
-					Build()
-				matrixTracker.Failure("send_message_retry", err, map[string]any{"reason": "context_canceled"})
-				return "", err
-			}
+// retryAfter returns how long a rate-limited response asks the client to
+// wait, from the Retry-After header (seconds or HTTP date) or the Matrix
+// M_LIMIT_EXCEEDED retry_after_ms field. It returns zero if neither is set.
+func retryAfter(header http.Header, body []byte) time.Duration {
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return time.Until(t)
 		}
 	}
 
-	err := errsys.NewBuilder("MAT-021").
-		Wrap(fmt.Errorf("send message failed after %d attempts: %w", maxAttempts, lastErr)).
-		WithFunction("SendMessageWithRetry").
-		WithInputs(map[string]any{"room_id": roomID, "attempts": maxAttempts}).
-		Build()
-	matrixTracker.Failure("send_message_retry", err, map[string]any{"reason": "max_retries_exceeded"})
-	return "", err
+	var limited struct {
+		RetryAfterMs int64 `json:"retry_after_ms"`
+	}
+	if json.Unmarshal(body, &limited) == nil && limited.RetryAfterMs > 0 {
+		return time.Duration(limited.RetryAfterMs) * time.Millisecond
+	}
+	return 0
+}
+
+// statusOf returns the response status code, or 0 for a transport error
+func statusOf(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
 }
 
 // SendEvent sends a custom event type to a Matrix room
@@ -1452,6 +1566,7 @@ func (m *MatrixAdapter) RefreshAccessToken() error {
 		m.refreshToken = result.RefreshToken // Update if rotated
 	}
 	m.lastExpiryCheck = time.Now() // Reset expiry check timer
+	m.tokenExpiresAt = tokenExpiry(result.ExpiresIn)
 	m.mu.Unlock()
 
 	logger.Global().Info("Matrix access token refreshed via refresh_token",
@@ -1510,6 +1625,96 @@ func (m *MatrixAdapter) ensureValidToken() error {
 	return nil
 }
 
+// tokenExpiry converts a Matrix expires_in_ms value to an absolute time.
+// A zero time means the token does not expire.
+func tokenExpiry(expiresInMs int64) time.Time {
+	if expiresInMs <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(expiresInMs) * time.Millisecond)
+}
+
+// tokenRefreshLoop refreshes the access token shortly before it expires
+func (m *MatrixAdapter) tokenRefreshLoop() {
+	ticker := time.NewTicker(tokenCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-ticker.C:
+			m.refreshTokenIfExpiring()
+		}
+	}
+}
+
+// refreshTokenIfExpiring refreshes the access token once it is within the
+// refresh margin of expiry, falling back to a password login when the
+// refresh token is missing or rejected. After maxTokenRefreshFailures
+// consecutive failures it raises MAT-004 so an admin can intervene before
+// the session is lost.
+func (m *MatrixAdapter) refreshTokenIfExpiring() error {
+	m.mu.RLock()
+	expiresAt := m.tokenExpiresAt
+	margin := m.refreshMargin
+	hasRefreshToken := m.refreshToken != ""
+	password := m.password
+	username := m.userID
+	m.mu.RUnlock()
+
+	if expiresAt.IsZero() || time.Until(expiresAt) > margin {
+		return nil
+	}
+
+	var err error
+	if hasRefreshToken {
+		err = m.RefreshAccessToken()
+	} else {
+		err = fmt.Errorf("no refresh token available")
+	}
+	if err != nil && password != "" && username != "" {
+		if loginErr := m.Login(username, password); loginErr != nil {
+			err = fmt.Errorf("refresh=%w, login=%w", err, loginErr)
+		} else {
+			err = nil
+		}
+	}
+
+	m.mu.Lock()
+	if err == nil {
+		m.refreshFailures = 0
+	} else {
+		m.refreshFailures++
+	}
+	failures := m.refreshFailures
+	m.mu.Unlock()
+
+	if err == nil {
+		return nil
+	}
+
+	logger.Global().Warn("Matrix access token refresh failed",
+		"attempt", failures,
+		"expires_at", expiresAt,
+		"error", err,
+	)
+
+	traced := errsys.NewBuilder("MAT-004").
+		Wrap(fmt.Errorf("access token refresh failed: %w", err)).
+		WithFunction("refreshTokenIfExpiring").
+		WithInputs(map[string]any{"failures": failures, "expires_at": expiresAt}).
+		Build()
+	matrixTracker.Failure("token_refresh", traced, map[string]any{"failures": failures})
+
+	if failures == maxTokenRefreshFailures && errsys.GetGlobalNotifier() != nil {
+		if notifyErr := errsys.GlobalNotify(m.ctx, traced); notifyErr != nil {
+			logger.Global().Warn("Failed to notify token refresh failure", "error", notifyErr)
+		}
+	}
+	return traced
+}
+
 // containsAny checks if the string contains any of the substrings
 func containsAny(s string, substrings ...string) bool {
 	for _, sub := range substrings {
@@ -1544,8 +1749,8 @@ func (m *MatrixAdapter) SetStudioCommandHandler(h StudioCommandHandler) {
 	m.studioCmdHandler = h
 }
 
-// SetEventBus sets the high-throughput event bus for agent streaming
-// This replaces the old polling-based event queue
+// SetEventBus sets the high-throughput event bus for agent streaming,
+// replacing the buffer the adapter creates for itself
 func (m *MatrixAdapter) SetEventBus(bus *events.MatrixEventBus) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -1992,7 +2197,7 @@ func (m *MatrixAdapter) SendFormattedMessage(ctx context.Context, roomID, plainB
 	payload := map[string]interface{}{
 		"msgtype":        "m.text",
 		"body":           plainBody,
-		"format":         "org.matrix.custom.html",
+		"format":         HTMLFormat,
 		"formatted_body": formattedBody,
 	}
 
@@ -0,0 +1,148 @@
+package adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+	"github.com/armorclaw/bridge/pkg/logger"
+)
+
+// newSendRetryAdapter returns a logged-in adapter whose sends are answered
+// by respond, which receives the 1-based attempt number. The returned slice
+// records each request path.
+func newSendRetryAdapter(t *testing.T, maxRetries int, respond func(w http.ResponseWriter, attempt int)) (*MatrixAdapter, *[]string) {
+	t.Helper()
+	logger.Initialize("info", "text", "stdout")
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		respond(w, len(paths))
+	}))
+	t.Cleanup(server.Close)
+
+	m, _ := New(Config{
+		HomeserverURL: server.URL,
+		MaxRetries:    maxRetries,
+		RetryDelay:    time.Millisecond,
+	})
+	t.Cleanup(func() { m.Close() })
+	m.accessToken = "test-token"
+	m.syncToken = "s1"
+	return m, &paths
+}
+
+func TestSendMessageRetriesServerErrors(t *testing.T) {
+	m, paths := newSendRetryAdapter(t, 3, func(w http.ResponseWriter, attempt int) {
+		if attempt < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"event_id":"$sent"}`))
+	})
+
+	eventID, err := m.SendMessage("!room:example.com", "hello", "m.text")
+	if err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if eventID != "$sent" {
+		t.Errorf("event ID = %q, want $sent", eventID)
+	}
+	if len(*paths) != 3 {
+		t.Fatalf("made %d requests, want 3", len(*paths))
+	}
+	for _, p := range *paths {
+		if p != (*paths)[0] {
+			t.Errorf("retry used path %s, want the original transaction %s", p, (*paths)[0])
+		}
+	}
+}
+
+func TestSendMessageHonorsRetryAfter(t *testing.T) {
+	m, paths := newSendRetryAdapter(t, 1, func(w http.ResponseWriter, attempt int) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"errcode":"M_LIMIT_EXCEEDED"}`))
+			return
+		}
+		w.Write([]byte(`{"event_id":"$sent"}`))
+	})
+
+	start := time.Now()
+	if _, err := m.SendMessage("!room:example.com", "hello", "m.text"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+	if len(*paths) != 2 {
+		t.Errorf("made %d requests, want 2", len(*paths))
+	}
+}
+
+func TestSendMessageRetriesExhausted(t *testing.T) {
+	m, paths := newSendRetryAdapter(t, 2, func(w http.ResponseWriter, attempt int) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"errcode":"M_UNKNOWN"}`))
+	})
+
+	_, err := m.SendMessage("!room:example.com", "hello", "m.text")
+	traced, ok := err.(*errsys.TracedError)
+	if !ok {
+		t.Fatalf("error = %v, want *TracedError", err)
+	}
+	if traced.Code != "MAT-021" {
+		t.Errorf("code = %s, want MAT-021", traced.Code)
+	}
+	if !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("error = %q, want attempt count", err)
+	}
+	if len(*paths) != 3 {
+		t.Errorf("made %d requests, want 3", len(*paths))
+	}
+}
+
+func TestSendMessageDoesNotRetryClientErrors(t *testing.T) {
+	m, paths := newSendRetryAdapter(t, 3, func(w http.ResponseWriter, attempt int) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errcode":"M_FORBIDDEN"}`))
+	})
+
+	if _, err := m.SendMessage("!room:example.com", "hello", "m.text"); err == nil {
+		t.Fatal("SendMessage() succeeded on 403")
+	}
+	if len(*paths) != 1 {
+		t.Errorf("made %d requests, want 1", len(*paths))
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		want   time.Duration
+	}{
+		{"seconds header", "3", "", 3 * time.Second},
+		{"retry_after_ms body", "", `{"errcode":"M_LIMIT_EXCEEDED","retry_after_ms":1500}`, 1500 * time.Millisecond},
+		{"header wins", "2", `{"retry_after_ms":9000}`, 2 * time.Second},
+		{"neither", "", `{"errcode":"M_LIMIT_EXCEEDED"}`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set("Retry-After", tt.header)
+			}
+			if got := retryAfter(header, []byte(tt.body)); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package adapter
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/armorclaw/bridge/internal/events"
@@ -256,3 +258,32 @@ func TestProcessEvents_MatrixStateEventNotCustom(t *testing.T) {
 		t.Errorf("expected 1 event processed, got %d", processed)
 	}
 }
+
+func TestSendHTMLMessagePayload(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"event_id":"$sent"}`))
+	}))
+	defer server.Close()
+
+	m, _ := New(Config{HomeserverURL: server.URL})
+	defer m.Close()
+	m.accessToken = "test-token"
+	m.syncToken = "s1"
+
+	if _, err := m.SendHTMLMessage("!room:example.com", "*hi*", "<em>hi</em>", "m.notice"); err != nil {
+		t.Fatalf("SendHTMLMessage() error = %v", err)
+	}
+	if payload["format"] != HTMLFormat || payload["formatted_body"] != "<em>hi</em>" || payload["body"] != "*hi*" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+
+	if _, err := m.SendMessage("!room:example.com", "plain", "m.text"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if _, ok := payload["formatted_body"]; ok {
+		t.Errorf("plain message should not carry formatted_body: %v", payload)
+	}
+}
@@ -0,0 +1,163 @@
+package adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+	"github.com/armorclaw/bridge/pkg/logger"
+)
+
+// newTokenTestServer serves login and refresh; refreshStatus controls the
+// refresh response and loginStatus the login response
+func newTokenTestServer(t *testing.T, refreshStatus, loginStatus int, refreshes, logins *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		switch r.URL.Path {
+		case "/_matrix/client/v3/login":
+			atomic.AddInt32(logins, 1)
+			if payload["refresh_token"] != true {
+				t.Errorf("login did not request a refresh token: %v", payload)
+			}
+			w.WriteHeader(loginStatus)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "login-token",
+				"refresh_token": "login-refresh",
+				"user_id":       "@bridge:example.com",
+				"expires_in_ms": 60000,
+			})
+		case "/_matrix/client/v3/refresh":
+			atomic.AddInt32(refreshes, 1)
+			w.WriteHeader(refreshStatus)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "refreshed-token",
+				"refresh_token": "rotated-refresh",
+				"expires_in_ms": 3600000,
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestLoginRecordsTokenExpiry(t *testing.T) {
+	logger.Initialize("info", "text", "stdout")
+
+	var refreshes, logins int32
+	server := newTokenTestServer(t, http.StatusOK, http.StatusOK, &refreshes, &logins)
+	defer server.Close()
+
+	m, _ := New(Config{HomeserverURL: server.URL, TokenRefreshMargin: 30 * time.Second})
+	defer m.Close()
+
+	if err := m.Login("bridge", "secret"); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if remaining := time.Until(m.tokenExpiresAt); remaining <= 0 || remaining > time.Minute {
+		t.Errorf("token expires in %v, want about 1m", remaining)
+	}
+
+	// Outside the margin nothing happens
+	if err := m.refreshTokenIfExpiring(); err != nil {
+		t.Fatalf("refreshTokenIfExpiring() error = %v", err)
+	}
+	if refreshes != 0 {
+		t.Errorf("refreshed %d times before the margin, want 0", refreshes)
+	}
+}
+
+func TestRefreshTokenIfExpiring(t *testing.T) {
+	logger.Initialize("info", "text", "stdout")
+
+	var refreshes, logins int32
+	server := newTokenTestServer(t, http.StatusOK, http.StatusOK, &refreshes, &logins)
+	defer server.Close()
+
+	m, _ := New(Config{HomeserverURL: server.URL})
+	defer m.Close()
+	m.accessToken = "old-token"
+	m.refreshToken = "old-refresh"
+	m.tokenExpiresAt = time.Now().Add(time.Minute)
+
+	if err := m.refreshTokenIfExpiring(); err != nil {
+		t.Fatalf("refreshTokenIfExpiring() error = %v", err)
+	}
+	if refreshes != 1 || logins != 0 {
+		t.Errorf("refreshes = %d, logins = %d; want 1, 0", refreshes, logins)
+	}
+	if m.GetAccessToken() != "refreshed-token" || m.refreshToken != "rotated-refresh" {
+		t.Errorf("tokens = %q, %q; want refreshed-token, rotated-refresh", m.GetAccessToken(), m.refreshToken)
+	}
+	if time.Until(m.tokenExpiresAt) < 59*time.Minute {
+		t.Errorf("token expiry not updated: %v", m.tokenExpiresAt)
+	}
+}
+
+func TestRefreshTokenFallsBackToLogin(t *testing.T) {
+	logger.Initialize("info", "text", "stdout")
+
+	var refreshes, logins int32
+	server := newTokenTestServer(t, http.StatusUnauthorized, http.StatusOK, &refreshes, &logins)
+	defer server.Close()
+
+	m, _ := New(Config{HomeserverURL: server.URL, Password: "secret"})
+	defer m.Close()
+	m.userID = "@bridge:example.com"
+	m.accessToken = "old-token"
+	m.refreshToken = "revoked-refresh"
+	m.tokenExpiresAt = time.Now().Add(time.Minute)
+	m.refreshFailures = 2
+
+	if err := m.refreshTokenIfExpiring(); err != nil {
+		t.Fatalf("refreshTokenIfExpiring() error = %v", err)
+	}
+	if refreshes != 1 || logins != 1 {
+		t.Errorf("refreshes = %d, logins = %d; want 1, 1", refreshes, logins)
+	}
+	if m.GetAccessToken() != "login-token" {
+		t.Errorf("access token = %q, want login-token", m.GetAccessToken())
+	}
+	if m.refreshFailures != 0 {
+		t.Errorf("refreshFailures = %d after success, want 0", m.refreshFailures)
+	}
+}
+
+func TestRefreshTokenPersistentFailure(t *testing.T) {
+	logger.Initialize("info", "text", "stdout")
+
+	var refreshes, logins int32
+	server := newTokenTestServer(t, http.StatusUnauthorized, http.StatusForbidden, &refreshes, &logins)
+	defer server.Close()
+
+	m, _ := New(Config{HomeserverURL: server.URL, Password: "wrong"})
+	defer m.Close()
+	m.userID = "@bridge:example.com"
+	m.accessToken = "old-token"
+	m.refreshToken = "revoked-refresh"
+	m.tokenExpiresAt = time.Now().Add(time.Minute)
+
+	for i := 1; i <= maxTokenRefreshFailures; i++ {
+		err := m.refreshTokenIfExpiring()
+		traced, ok := err.(*errsys.TracedError)
+		if !ok {
+			t.Fatalf("attempt %d: error = %v, want *TracedError", i, err)
+		}
+		if traced.Code != "MAT-004" {
+			t.Errorf("attempt %d: code = %s, want MAT-004", i, traced.Code)
+		}
+		if m.refreshFailures != i {
+			t.Errorf("attempt %d: refreshFailures = %d", i, m.refreshFailures)
+		}
+	}
+	if m.GetAccessToken() != "old-token" {
+		t.Errorf("access token changed to %q after failed refresh", m.GetAccessToken())
+	}
+}
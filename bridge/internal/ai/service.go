@@ -2,6 +2,7 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -45,6 +46,9 @@ func (s *AIService) Chat(ctx context.Context, req ChatRequest, keyID string) (*C
 
 	cred, err := s.keystore.Retrieve(keyID)
 	if err != nil {
+		if errors.Is(err, keystore.ErrKeyExpired) {
+			return nil, fmt.Errorf("API key %q has expired, rotate it with rotate_key: %w", keyID, err)
+		}
 		return nil, fmt.Errorf("failed to retrieve API key: %w", err)
 	}
 
@@ -54,7 +58,12 @@ func (s *AIService) Chat(ctx context.Context, req ChatRequest, keyID string) (*C
 		return nil, err
 	}
 
-	return client.Chat(ctx, req)
+	resp, err := client.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Provider = string(provider)
+	return resp, nil
 }
 
 func (s *AIService) getClient(provider ProviderType, apiKey, baseURL string) (AIClient, error) {
@@ -51,6 +51,7 @@ type Message struct {
 
 type ChatResponse struct {
 	RequestID    string `json:"request_id,omitempty"`
+	Provider     string `json:"provider,omitempty"`
 	Model        string `json:"model"`
 	Content      string `json:"content"`
 	Usage        Usage  `json:"usage"`
@@ -37,12 +37,30 @@ type MatrixEventBus struct {
 
 	nextSeq uint64
 
-	// reusable batch buffer (micro-optimization)
-	batch []MatrixEvent
+	// evicted counts events overwritten to make room for newer ones
+	evicted uint64
+
+	// store, when set, keeps the buffer across restarts
+	store *MatrixEventStore
 
 	subscribers []chan MatrixEvent
 }
 
+// Page is one batch of events returned to a cursor-based reader
+type Page struct {
+	Events []MatrixEvent
+	// Next is the cursor to pass on the following call
+	Next uint64
+	// Dropped is how many events after the caller's cursor were evicted
+	// before they were read. Zero unless the reader fell behind.
+	Dropped uint64
+	// HasMore is set when further events are already buffered past Next
+	HasMore bool
+	// Reset is set when the cursor was outside the buffer (the reader fell
+	// behind, or the bus restarted) and reading resumed at the oldest event
+	Reset bool
+}
+
 func NewMatrixEventBus(size int) *MatrixEventBus {
 
 	if size <= 0 {
@@ -53,7 +71,6 @@ func NewMatrixEventBus(size int) *MatrixEventBus {
 		buffer:  make([]MatrixEvent, size),
 		size:    uint64(size),
 		nextSeq: 1,
-		batch:   make([]MatrixEvent, MaxBatchSize),
 	}
 
 	b.cond = sync.NewCond(&b.mu)
@@ -61,6 +78,37 @@ func NewMatrixEventBus(size int) *MatrixEventBus {
 	return b
 }
 
+// NewPersistentMatrixEventBus returns a bus whose buffer is kept in store.
+// Events buffered before a restart are reloaded and sequence numbers carry
+// on from the last one stored, so readers' cursors stay valid.
+func NewPersistentMatrixEventBus(size int, store *MatrixEventStore) (*MatrixEventBus, error) {
+	b := NewMatrixEventBus(size)
+
+	stored, err := store.Load(int(b.size))
+	if err != nil {
+		return nil, err
+	}
+
+	// Paging relies on contiguous sequence numbers; a failed write leaves a
+	// gap, so keep only the run after the last one
+	first := 0
+	for i := 1; i < len(stored); i++ {
+		if stored[i].Seq != stored[i-1].Seq+1 {
+			first = i
+		}
+	}
+	stored = stored[first:]
+
+	copy(b.buffer, stored)
+	b.count = uint64(len(stored))
+	if len(stored) > 0 {
+		b.nextSeq = stored[len(stored)-1].Seq + 1
+	}
+	b.store = store
+
+	return b, nil
+}
+
 func (b *MatrixEventBus) Publish(e MatrixEvent) uint64 {
 
 	b.mu.Lock()
@@ -76,6 +124,18 @@ func (b *MatrixEventBus) Publish(e MatrixEvent) uint64 {
 		b.count++
 	} else {
 		b.start = (b.start + 1) % b.size
+		b.evicted++
+	}
+
+	if b.store != nil {
+		var trimThrough uint64
+		if e.Seq > b.size {
+			trimThrough = e.Seq - b.size
+		}
+		if err := b.store.Append(e, trimThrough); err != nil {
+			// The event is still buffered in memory; only a restart loses it
+			slog.Warn("failed to persist matrix event", "seq", e.Seq, "error", err)
+		}
 	}
 
 	b.cond.Broadcast()
@@ -98,68 +158,92 @@ func (b *MatrixEventBus) Publish(e MatrixEvent) uint64 {
 	return e.Seq
 }
 
-func (b *MatrixEventBus) getEventsAfterLocked(cursor uint64) ([]MatrixEvent, uint64, bool) {
+// pageAfterLocked returns up to limit events with sequence numbers above
+// cursor. A cursor older than the buffer resumes at the oldest retained
+// event and reports the gap as dropped; cursor 0 means "from the oldest"
+// and never reports a gap. A cursor from before a restart of the bus, past
+// any sequence issued, also resumes at the oldest event.
+func (b *MatrixEventBus) pageAfterLocked(cursor uint64, limit int) Page {
+	if limit <= 0 || limit > MaxBatchSize {
+		limit = MaxBatchSize
+	}
+
+	if cursor >= b.nextSeq {
+		if b.count == 0 {
+			return Page{Next: 0, Reset: true}
+		}
+		page := b.pageAfterLocked(0, limit)
+		page.Reset = true
+		return page
+	}
 
 	if b.count == 0 {
-		return nil, cursor, false
+		return Page{Next: cursor}
 	}
 
 	oldest := b.buffer[b.start%b.size].Seq
 	newest := b.buffer[(b.start+b.count-1)%b.size].Seq
 
+	var dropped uint64
 	if cursor < oldest-1 {
-		return nil, oldest, true
+		if cursor > 0 {
+			dropped = oldest - 1 - cursor
+		}
+		cursor = oldest - 1
 	}
 
 	if cursor >= newest {
-		return nil, cursor, false
+		return Page{Next: cursor, Dropped: dropped, Reset: dropped > 0}
 	}
 
-	batchCount := 0
-	var lastSeq uint64
-
-	for i := uint64(0); i < b.count && batchCount < MaxBatchSize; i++ {
-
-		idx := (b.start + i) % b.size
-		ev := b.buffer[idx]
-
-		if ev.Seq > cursor {
-
-			b.batch[batchCount] = ev
-			batchCount++
-			lastSeq = ev.Seq
-		}
+	// Sequence numbers are contiguous, so the first unread event's
+	// position follows directly from the cursor
+	first := cursor + 1 - oldest
+	n := newest - cursor
+	if n > uint64(limit) {
+		n = uint64(limit)
 	}
 
-	if batchCount == 0 {
-		return nil, cursor, false
+	events := make([]MatrixEvent, n)
+	for i := uint64(0); i < n; i++ {
+		events[i] = b.buffer[(b.start+first+i)%b.size]
 	}
 
-	return b.batch[:batchCount], lastSeq, false
+	next := events[n-1].Seq
+	return Page{
+		Events:  events,
+		Next:    next,
+		Dropped: dropped,
+		HasMore: next < newest,
+		Reset:   dropped > 0,
+	}
 }
 
-func (b *MatrixEventBus) GetEventsAfter(cursor uint64) ([]MatrixEvent, uint64, bool) {
-
+// GetEventsAfter returns up to limit buffered events after cursor without
+// waiting
+func (b *MatrixEventBus) GetEventsAfter(cursor uint64, limit int) Page {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	return b.getEventsAfterLocked(cursor)
+	return b.pageAfterLocked(cursor, limit)
 }
 
-func (b *MatrixEventBus) WaitForEvents(ctx context.Context, cursor uint64) ([]MatrixEvent, uint64, bool) {
+// WaitForEvents returns up to limit events after cursor, waiting until at
+// least one is available, the reader is found to have missed events, or
+// ctx is done
+func (b *MatrixEventBus) WaitForEvents(ctx context.Context, cursor uint64, limit int) Page {
 	ticker := time.NewTicker(25 * time.Millisecond)
 	defer ticker.Stop()
 
 	for {
-		events, next, reset := b.getEventsAfterLocked(cursor)
-
-		if len(events) > 0 || reset {
-			return events, next, reset
+		page := b.GetEventsAfter(cursor, limit)
+		if len(page.Events) > 0 || page.Reset {
+			return page
 		}
 
 		select {
 		case <-ctx.Done():
-			return nil, cursor, false
+			return page
 		case <-ticker.C:
 		}
 	}
@@ -185,5 +269,7 @@ func (b *MatrixEventBus) Status() map[string]interface{} {
 		"next_seq":   b.nextSeq,
 		"oldest_seq": b.buffer[(b.start+b.count-1)%b.size].Seq,
 		"next_event": b.nextSeq,
+		"evicted":    b.evicted,
+		"persistent": b.store != nil,
 	}
 }
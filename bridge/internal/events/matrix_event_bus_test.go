@@ -0,0 +1,163 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func publishN(b *MatrixEventBus, n int) {
+	for i := 0; i < n; i++ {
+		b.Publish(MatrixEvent{Type: "m.room.message"})
+	}
+}
+
+func TestGetEventsAfterPagesWithLimit(t *testing.T) {
+	b := NewMatrixEventBus(16)
+	publishN(b, 5)
+
+	page := b.GetEventsAfter(0, 2)
+	if len(page.Events) != 2 || page.Next != 2 || !page.HasMore {
+		t.Fatalf("first page = %d events, next %d, has_more %v; want 2, 2, true", len(page.Events), page.Next, page.HasMore)
+	}
+
+	page = b.GetEventsAfter(page.Next, 10)
+	if len(page.Events) != 3 || page.Next != 5 || page.HasMore {
+		t.Fatalf("second page = %d events, next %d, has_more %v; want 3, 5, false", len(page.Events), page.Next, page.HasMore)
+	}
+	if page.Events[0].Seq != 3 {
+		t.Errorf("second page starts at seq %d, want 3", page.Events[0].Seq)
+	}
+	if page.Dropped != 0 || page.Reset {
+		t.Errorf("unexpected drop: %+v", page)
+	}
+}
+
+func TestGetEventsAfterReportsDropped(t *testing.T) {
+	b := NewMatrixEventBus(4)
+	publishN(b, 2)
+	cursor := b.GetEventsAfter(0, 0).Next
+
+	// Six more events overflow the four-slot buffer, evicting 3 and 4
+	publishN(b, 6)
+
+	page := b.GetEventsAfter(cursor, 0)
+	if page.Dropped != 2 || !page.Reset {
+		t.Errorf("dropped = %d, reset = %v; want 2, true", page.Dropped, page.Reset)
+	}
+	if len(page.Events) != 4 || page.Events[0].Seq != 5 || page.Next != 8 {
+		t.Errorf("page = %d events from seq %d, next %d; want 4 from 5, next 8", len(page.Events), page.Events[0].Seq, page.Next)
+	}
+
+	if status := b.Status(); status["evicted"] != uint64(4) {
+		t.Errorf("evicted = %v, want 4", status["evicted"])
+	}
+}
+
+func TestGetEventsAfterFreshCursorIsNotDropped(t *testing.T) {
+	b := NewMatrixEventBus(4)
+	publishN(b, 10)
+
+	page := b.GetEventsAfter(0, 0)
+	if page.Dropped != 0 || page.Reset {
+		t.Errorf("fresh reader reported a drop: %+v", page)
+	}
+	if len(page.Events) != 4 || page.Events[0].Seq != 7 {
+		t.Errorf("page = %d events from seq %d; want 4 from 7", len(page.Events), page.Events[0].Seq)
+	}
+}
+
+func TestGetEventsAfterCursorFromBeforeRestart(t *testing.T) {
+	b := NewMatrixEventBus(16)
+	publishN(b, 3)
+
+	page := b.GetEventsAfter(500, 0)
+	if !page.Reset || len(page.Events) != 3 || page.Next != 3 {
+		t.Errorf("page = %d events, next %d, reset %v; want 3, 3, true", len(page.Events), page.Next, page.Reset)
+	}
+}
+
+func TestWaitForEventsTimesOut(t *testing.T) {
+	b := NewMatrixEventBus(16)
+	publishN(b, 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	page := b.WaitForEvents(ctx, 2, 0)
+	if len(page.Events) != 0 || page.Next != 2 {
+		t.Errorf("page = %d events, next %d; want 0, 2", len(page.Events), page.Next)
+	}
+}
+
+func TestWaitForEventsWakesOnPublish(t *testing.T) {
+	b := NewMatrixEventBus(16)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		publishN(b, 1)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	page := b.WaitForEvents(ctx, 0, 0)
+	if len(page.Events) != 1 || page.Next != 1 {
+		t.Errorf("page = %d events, next %d; want 1, 1", len(page.Events), page.Next)
+	}
+}
+
+func TestPersistentBusSurvivesRestart(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewMatrixEventStore(db)
+	if err != nil {
+		t.Fatalf("NewMatrixEventStore() error = %v", err)
+	}
+
+	b, err := NewPersistentMatrixEventBus(4, store)
+	if err != nil {
+		t.Fatalf("NewPersistentMatrixEventBus() error = %v", err)
+	}
+	publishN(b, 5)
+	b.Publish(MatrixEvent{ID: "$last", Type: "m.room.message", Content: map[string]interface{}{"body": "hi"}})
+	cursor := b.GetEventsAfter(0, 2).Next
+
+	var rows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM matrix_events`).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 4 {
+		t.Errorf("stored %d events, want the buffer size 4", rows)
+	}
+
+	restarted, err := NewPersistentMatrixEventBus(4, store)
+	if err != nil {
+		t.Fatalf("NewPersistentMatrixEventBus() after restart error = %v", err)
+	}
+
+	page := restarted.GetEventsAfter(cursor, 0)
+	if page.Reset || page.Dropped != 0 || len(page.Events) != 2 {
+		t.Fatalf("page after restart = %+v, want the 2 events after cursor %d", page, cursor)
+	}
+	last := page.Events[1]
+	if last.Seq != 6 || last.ID != "$last" {
+		t.Errorf("last event = %+v, want seq 6 $last", last)
+	}
+	if content, _ := json.Marshal(last.Content); string(content) != `{"body":"hi"}` {
+		t.Errorf("content = %s, want the stored JSON", content)
+	}
+
+	if seq := restarted.Publish(MatrixEvent{Type: "m.room.message"}); seq != 7 {
+		t.Errorf("first seq after restart = %d, want 7", seq)
+	}
+}
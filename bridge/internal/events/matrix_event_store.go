@@ -0,0 +1,114 @@
+package events
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// MatrixEventStore persists the MatrixEventBus buffer so that buffered
+// events and sequence numbers survive a bridge restart, and matrix.receive
+// cursors issued before it stay valid
+type MatrixEventStore struct {
+	db *sql.DB
+}
+
+// NewMatrixEventStore opens a MatrixEventStore against db, creating the
+// schema if needed
+func NewMatrixEventStore(db *sql.DB) (*MatrixEventStore, error) {
+	s := &MatrixEventStore{db: db}
+	if err := s.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize matrix event schema: %w", err)
+	}
+	return s, nil
+}
+
+// initSchema creates the matrix_events table if it does not already exist
+func (s *MatrixEventStore) initSchema() error {
+	const ddl = `
+	CREATE TABLE IF NOT EXISTS matrix_events (
+		seq      INTEGER PRIMARY KEY,
+		event_id TEXT NOT NULL,
+		room_id  TEXT NOT NULL,
+		sender   TEXT NOT NULL,
+		type     TEXT NOT NULL,
+		content  TEXT
+	);
+	`
+	_, err := s.db.Exec(ddl)
+	return err
+}
+
+// Append stores e and deletes every event at or below trimThrough, keeping
+// the table the same size as the bus buffer
+func (s *MatrixEventStore) Append(e MatrixEvent, trimThrough uint64) error {
+	var content []byte
+	if e.Content != nil {
+		var err error
+		if content, err = json.Marshal(e.Content); err != nil {
+			return fmt.Errorf("failed to encode matrix event: %w", err)
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to store matrix event: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO matrix_events (seq, event_id, room_id, sender, type, content)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, int64(e.Seq), e.ID, e.RoomID, e.Sender, e.Type, nullableString(content)); err != nil {
+		return fmt.Errorf("failed to store matrix event: %w", err)
+	}
+	if trimThrough > 0 {
+		if _, err := tx.Exec(`DELETE FROM matrix_events WHERE seq <= ?`, int64(trimThrough)); err != nil {
+			return fmt.Errorf("failed to trim matrix events: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to store matrix event: %w", err)
+	}
+	return nil
+}
+
+// Load returns the newest limit events, oldest first. Content comes back as
+// the JSON it was stored as.
+func (s *MatrixEventStore) Load(limit int) ([]MatrixEvent, error) {
+	rows, err := s.db.Query(`
+		SELECT seq, event_id, room_id, sender, type, content FROM (
+			SELECT * FROM matrix_events ORDER BY seq DESC LIMIT ?
+		) ORDER BY seq
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load matrix events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []MatrixEvent
+	for rows.Next() {
+		var (
+			e       MatrixEvent
+			seq     int64
+			content sql.NullString
+		)
+		if err := rows.Scan(&seq, &e.ID, &e.RoomID, &e.Sender, &e.Type, &content); err != nil {
+			return nil, err
+		}
+		e.Seq = uint64(seq)
+		if content.Valid {
+			e.Content = json.RawMessage(content.String)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func nullableString(b []byte) interface{} {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
@@ -47,6 +47,29 @@ type SDTWAdapter interface {
 	Metrics() (AdapterMetrics, error)
 }
 
+// HistoryProvider is implemented by adapters that can read a channel's
+// recent messages, used to backfill a newly bridged Matrix room
+type HistoryProvider interface {
+	// FetchHistory returns up to limit of the most recent messages in
+	// target.Channel, oldest first. Metadata carries the sender's
+	// "user_id" and, when known, "user_name".
+	FetchHistory(ctx context.Context, target Target, limit int) ([]Message, error)
+}
+
+// ProfileProvider is implemented by adapters that can look up a platform
+// user's public profile, used to keep Matrix ghost profiles current
+type ProfileProvider interface {
+	// FetchUserProfile returns the display name and avatar of userID
+	FetchUserProfile(ctx context.Context, userID string) (*UserProfile, error)
+}
+
+// UserProfile is a platform user's public profile
+type UserProfile struct {
+	UserID      string
+	DisplayName string
+	AvatarURL   string // HTTPS URL of the avatar image; empty if unset
+}
+
 // CapabilitySet defines adapter feature support
 type CapabilitySet struct {
 	Read         bool // Can receive messages
@@ -0,0 +1,225 @@
+// Package sdtw provides channel history reads for backfill
+package sdtw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page size limits of the platform history APIs
+const (
+	maxSlackHistoryLimit   = 1000
+	maxDiscordHistoryLimit = 100
+)
+
+// FetchHistory returns up to limit of the most recent messages in a Slack
+// channel, oldest first. Joins, topic changes and other system messages are
+// skipped.
+func (s *SlackAdapter) FetchHistory(ctx context.Context, target Target, limit int) ([]Message, error) {
+	if target.Channel == "" {
+		return nil, NewAdapterError(ErrInvalidTarget, "channel is required", false)
+	}
+	if limit <= 0 || limit > maxSlackHistoryLimit {
+		limit = maxSlackHistoryLimit
+	}
+
+	query := url.Values{}
+	query.Set("channel", target.Channel)
+	query.Set("limit", strconv.Itoa(limit))
+
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		"https://slack.com/api/conversations.history?"+query.Encode(), nil)
+	if err != nil {
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.botToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.RecordError(err)
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK       bool   `json:"ok"`
+		Error    string `json:"error"`
+		Messages []struct {
+			Type     string `json:"type"`
+			Subtype  string `json:"subtype"`
+			User     string `json:"user"`
+			BotID    string `json:"bot_id"`
+			Username string `json:"username"`
+			Text     string `json:"text"`
+			TS       string `json:"ts"`
+			ThreadTS string `json:"thread_ts"`
+		} `json:"messages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, NewAdapterError(ErrPlatformError, "failed to parse response", true)
+	}
+	if !result.OK {
+		s.RecordError(fmt.Errorf("slack API error: %s", result.Error))
+		return nil, NewAdapterError(mapSlackError(result.Error), result.Error, isRetryableSlackError(result.Error))
+	}
+
+	messages := make([]Message, 0, len(result.Messages))
+	for _, m := range result.Messages {
+		if m.Subtype != "" && m.Subtype != "bot_message" && m.Subtype != "thread_broadcast" {
+			continue
+		}
+		sent, err := parseSlackTS(m.TS)
+		if err != nil {
+			continue
+		}
+
+		userID := m.User
+		if userID == "" {
+			userID = m.BotID
+		}
+		msg := Message{
+			ID:        m.TS,
+			Content:   m.Text,
+			Type:      MessageTypeText,
+			Timestamp: sent,
+			Metadata:  map[string]string{"user_id": userID},
+		}
+		if m.Username != "" {
+			msg.Metadata["user_name"] = m.Username
+		}
+		if m.ThreadTS != "" && m.ThreadTS != m.TS {
+			msg.ReplyTo = m.ThreadTS
+		}
+		messages = append(messages, msg)
+	}
+
+	sortOldestFirst(messages)
+	return messages, nil
+}
+
+// parseSlackTS converts a Slack message timestamp ("1712345678.123456") to
+// a time
+func parseSlackTS(ts string) (time.Time, error) {
+	secs, frac, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid slack timestamp %q", ts)
+	}
+	var micros int64
+	if frac != "" {
+		frac = (frac + "000000")[:6]
+		if micros, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid slack timestamp %q", ts)
+		}
+	}
+	return time.Unix(sec, micros*int64(time.Microsecond)), nil
+}
+
+// FetchHistory returns up to limit of the most recent messages in a Discord
+// channel, oldest first. Discord returns at most 100 messages per request.
+// System messages such as pins and joins are skipped.
+func (d *DiscordAdapter) FetchHistory(ctx context.Context, target Target, limit int) ([]Message, error) {
+	if target.Channel == "" {
+		return nil, NewAdapterError(ErrInvalidTarget, "channel is required", false)
+	}
+	if limit <= 0 || limit > maxDiscordHistoryLimit {
+		limit = maxDiscordHistoryLimit
+	}
+
+	endpoint := fmt.Sprintf("https://discord.com/api/v10/channels/%s/messages?limit=%d",
+		url.PathEscape(target.Channel), limit)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	req.Header.Set("Authorization", "Bot "+d.botToken)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		d.RecordError(err)
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, NewAdapterError(ErrPlatformError, "failed to read response", true)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		d.RecordError(fmt.Errorf("discord API error: %s", apiErr.Message))
+		return nil, NewAdapterError(mapDiscordError(resp.StatusCode, apiErr.Message), apiErr.Message,
+			resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500)
+	}
+
+	var result []struct {
+		ID        string    `json:"id"`
+		Type      int       `json:"type"`
+		Content   string    `json:"content"`
+		Timestamp time.Time `json:"timestamp"`
+		Author    struct {
+			ID         string `json:"id"`
+			Username   string `json:"username"`
+			GlobalName string `json:"global_name"`
+		} `json:"author"`
+		MessageReference *struct {
+			MessageID string `json:"message_id"`
+		} `json:"message_reference"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, NewAdapterError(ErrPlatformError, "failed to parse response", true)
+	}
+
+	messages := make([]Message, 0, len(result))
+	for _, m := range result {
+		// 0 is a default message and 19 a reply; the rest are system messages
+		if m.Type != 0 && m.Type != 19 {
+			continue
+		}
+
+		name := m.Author.GlobalName
+		if name == "" {
+			name = m.Author.Username
+		}
+		msg := Message{
+			ID:        m.ID,
+			Content:   m.Content,
+			Type:      MessageTypeText,
+			Timestamp: m.Timestamp,
+			Metadata: map[string]string{
+				"user_id":   m.Author.ID,
+				"user_name": name,
+			},
+		}
+		if m.MessageReference != nil {
+			msg.ReplyTo = m.MessageReference.MessageID
+		}
+		messages = append(messages, msg)
+	}
+
+	sortOldestFirst(messages)
+	return messages, nil
+}
+
+// sortOldestFirst orders messages by timestamp; the platforms return the
+// newest first
+func sortOldestFirst(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+}
@@ -0,0 +1,114 @@
+// Package sdtw provides tests for channel history reads
+package sdtw
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to a test server, keeping the path
+// and query of the platform URL
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func testClient(t *testing.T, handler http.HandlerFunc) *http.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+	return &http.Client{Transport: redirectTransport{target: target}}
+}
+
+func TestSlackFetchHistory(t *testing.T) {
+	adapter := NewSlackAdapter()
+	adapter.botToken = "xoxb-test"
+	adapter.client = testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/conversations.history" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("channel") != "C123" || r.URL.Query().Get("limit") != "5" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"ok": true, "messages": [
+			{"type": "message", "user": "U2", "text": "second", "ts": "1712345690.000200"},
+			{"type": "message", "subtype": "channel_join", "user": "U3", "text": "joined", "ts": "1712345680.000000"},
+			{"type": "message", "user": "U1", "text": "first", "ts": "1712345678.123456"}
+		]}`))
+	})
+
+	messages, err := adapter.FetchHistory(context.Background(), Target{Channel: "C123"}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2 without the join", len(messages))
+	}
+	if messages[0].Content != "first" || messages[0].Metadata["user_id"] != "U1" {
+		t.Errorf("first message = %+v", messages[0])
+	}
+	if want := time.Unix(1712345678, 123456000); !messages[0].Timestamp.Equal(want) {
+		t.Errorf("timestamp = %v, want %v", messages[0].Timestamp, want)
+	}
+}
+
+func TestSlackFetchHistoryError(t *testing.T) {
+	adapter := NewSlackAdapter()
+	adapter.client = testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+	})
+
+	_, err := adapter.FetchHistory(context.Background(), Target{Channel: "C404"}, 10)
+	if adapterErr, ok := err.(*AdapterError); !ok || adapterErr.Code != ErrInvalidTarget {
+		t.Errorf("err = %v, want invalid target", err)
+	}
+}
+
+func TestDiscordFetchHistory(t *testing.T) {
+	adapter := NewDiscordAdapter()
+	adapter.botToken = "discord-test"
+	adapter.client = testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v10/channels/42/messages" || r.URL.Query().Get("limit") != "100" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.Header.Get("Authorization") != "Bot discord-test" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`[
+			{"id": "3", "type": 19, "content": "reply", "timestamp": "2026-01-02T10:05:00Z",
+			 "author": {"id": "u2", "username": "bob"}, "message_reference": {"message_id": "1"}},
+			{"id": "2", "type": 6, "content": "", "timestamp": "2026-01-02T10:01:00Z", "author": {"id": "u1"}},
+			{"id": "1", "type": 0, "content": "hello", "timestamp": "2026-01-02T10:00:00Z",
+			 "author": {"id": "u1", "username": "alice", "global_name": "Alice"}}
+		]`))
+	})
+
+	// Limits above a single page are capped
+	messages, err := adapter.FetchHistory(context.Background(), Target{Channel: "42"}, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("got %d messages, want 2 without the pin", len(messages))
+	}
+	if messages[0].ID != "1" || messages[0].Metadata["user_name"] != "Alice" {
+		t.Errorf("first message = %+v", messages[0])
+	}
+	if messages[1].ReplyTo != "1" || messages[1].Metadata["user_name"] != "bob" {
+		t.Errorf("second message = %+v", messages[1])
+	}
+}
@@ -0,0 +1,131 @@
+// Package sdtw provides platform user profile lookups
+package sdtw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// FetchUserProfile looks up a Slack user with users.info. The display name
+// falls back to the real name and then the username.
+func (s *SlackAdapter) FetchUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	if userID == "" {
+		return nil, NewAdapterError(ErrInvalidTarget, "user ID is required", false)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		"https://slack.com/api/users.info?user="+url.QueryEscape(userID), nil)
+	if err != nil {
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.botToken)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.RecordError(err)
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		User  struct {
+			ID       string `json:"id"`
+			Name     string `json:"name"`
+			RealName string `json:"real_name"`
+			Profile  struct {
+				DisplayName string `json:"display_name"`
+				RealName    string `json:"real_name"`
+				Image192    string `json:"image_192"`
+			} `json:"profile"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, NewAdapterError(ErrPlatformError, "failed to parse response", true)
+	}
+	if !result.OK {
+		s.RecordError(fmt.Errorf("slack API error: %s", result.Error))
+		return nil, NewAdapterError(mapSlackError(result.Error), result.Error, isRetryableSlackError(result.Error))
+	}
+
+	user := result.User
+	return &UserProfile{
+		UserID:      userID,
+		DisplayName: firstNonEmpty(user.Profile.DisplayName, user.Profile.RealName, user.RealName, user.Name),
+		AvatarURL:   user.Profile.Image192,
+	}, nil
+}
+
+// FetchUserProfile looks up a Discord user. The display name is the global
+// name when set, otherwise the username.
+func (d *DiscordAdapter) FetchUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	if userID == "" {
+		return nil, NewAdapterError(ErrInvalidTarget, "user ID is required", false)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET",
+		"https://discord.com/api/v10/users/"+url.PathEscape(userID), nil)
+	if err != nil {
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	req.Header.Set("Authorization", "Bot "+d.botToken)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		d.RecordError(err)
+		return nil, NewAdapterError(ErrNetworkError, err.Error(), true)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, NewAdapterError(ErrPlatformError, "failed to read response", true)
+	}
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = resp.Status
+		}
+		d.RecordError(fmt.Errorf("discord API error: %s", apiErr.Message))
+		return nil, NewAdapterError(mapDiscordError(resp.StatusCode, apiErr.Message), apiErr.Message,
+			resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500)
+	}
+
+	var user struct {
+		ID         string `json:"id"`
+		Username   string `json:"username"`
+		GlobalName string `json:"global_name"`
+		Avatar     string `json:"avatar"`
+	}
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, NewAdapterError(ErrPlatformError, "failed to parse response", true)
+	}
+
+	profile := &UserProfile{
+		UserID:      userID,
+		DisplayName: firstNonEmpty(user.GlobalName, user.Username),
+	}
+	if user.Avatar != "" {
+		profile.AvatarURL = fmt.Sprintf("https://cdn.discordapp.com/avatars/%s/%s.png", user.ID, user.Avatar)
+	}
+	return profile, nil
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
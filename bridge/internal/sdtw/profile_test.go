@@ -0,0 +1,52 @@
+// Package sdtw provides tests for platform user profile lookups
+package sdtw
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestSlackFetchUserProfile(t *testing.T) {
+	adapter := NewSlackAdapter()
+	adapter.client = testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/users.info" || r.URL.Query().Get("user") != "U123" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"ok": true, "user": {"id": "U123", "name": "alice", "real_name": "Alice Smith",
+			"profile": {"display_name": "", "real_name": "Alice Smith", "image_192": "https://avatars.slack-edge.com/a_192.png"}}}`))
+	})
+
+	profile, err := adapter.FetchUserProfile(context.Background(), "U123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.DisplayName != "Alice Smith" || profile.AvatarURL != "https://avatars.slack-edge.com/a_192.png" {
+		t.Errorf("profile = %+v", profile)
+	}
+}
+
+func TestDiscordFetchUserProfile(t *testing.T) {
+	adapter := NewDiscordAdapter()
+	adapter.client = testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v10/users/42":
+			w.Write([]byte(`{"id": "42", "username": "bob", "global_name": null, "avatar": "abc123"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Unknown User", "code": 10013}`))
+		}
+	})
+
+	profile, err := adapter.FetchUserProfile(context.Background(), "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.DisplayName != "bob" || profile.AvatarURL != "https://cdn.discordapp.com/avatars/42/abc123.png" {
+		t.Errorf("profile = %+v", profile)
+	}
+
+	if _, err := adapter.FetchUserProfile(context.Background(), "404"); err == nil {
+		t.Error("expected an error for an unknown user")
+	}
+}
@@ -110,6 +110,10 @@ type GhostUser struct {
 	AvatarURL   string    `json:"avatar_url"`
 	CreatedAt   time.Time `json:"created_at"`
 	LastActive  time.Time `json:"last_active"`
+
+	// PlatformAvatarURL is the platform image AvatarURL was uploaded from
+	PlatformAvatarURL string    `json:"platform_avatar_url,omitempty"`
+	ProfileSyncedAt   time.Time `json:"profile_synced_at,omitempty"`
 }
 
 // New creates a new AppService instance
@@ -433,6 +437,36 @@ func (as *AppService) GetGhostUserByExternal(platform, externalID string) (*Ghos
 	return nil, false
 }
 
+// ListGhostUsers returns a copy of every registered ghost user
+func (as *AppService) ListGhostUsers() []GhostUser {
+	as.ghostMu.RLock()
+	defer as.ghostMu.RUnlock()
+
+	users := make([]GhostUser, 0, len(as.ghostUsers))
+	for _, gu := range as.ghostUsers {
+		users = append(users, *gu)
+	}
+	return users
+}
+
+// UpdateGhostProfile records a ghost user's synced display name and avatar.
+// avatarURL is the Matrix content URI and platformAvatarURL its source.
+func (as *AppService) UpdateGhostProfile(userID, displayName, avatarURL, platformAvatarURL string) error {
+	as.ghostMu.Lock()
+	defer as.ghostMu.Unlock()
+
+	gu, exists := as.ghostUsers[userID]
+	if !exists {
+		return fmt.Errorf("ghost user %s not registered", userID)
+	}
+
+	gu.DisplayName = displayName
+	gu.AvatarURL = avatarURL
+	gu.PlatformAvatarURL = platformAvatarURL
+	gu.ProfileSyncedAt = time.Now()
+	return nil
+}
+
 // GetBridgeUserID returns the Matrix user ID of the bridge bot
 func (as *AppService) GetBridgeUserID() string {
 	return fmt.Sprintf("@%s:%s", as.config.SenderLocalpart, as.config.ServerName)
@@ -6,6 +6,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -35,6 +37,11 @@ type BridgeConfig struct {
 
 	// Platform adapters
 	Adapters map[Platform]sdtw.SDTWAdapter
+
+	// ProfileSyncInterval sets how often ghost display names and avatars are
+	// refreshed from their platforms. Zero uses DefaultProfileSyncInterval;
+	// negative disables periodic syncing.
+	ProfileSyncInterval time.Duration
 }
 
 // BridgedChannel represents a bridged channel between Matrix and external platform
@@ -58,6 +65,9 @@ type BridgeManager struct {
 	logger   *slog.Logger
 	scrubber *pii.HIPAAScrubber
 
+	// httpClient downloads platform avatars for ghost profiles
+	httpClient *http.Client
+
 	// Platform adapters
 	adapters  map[Platform]sdtw.SDTWAdapter
 	adapterMu sync.RWMutex
@@ -91,6 +101,9 @@ func NewBridgeManager(config BridgeConfig) (*BridgeManager, error) {
 		channels: make(map[string]*BridgedChannel),
 		ctx:      ctx,
 		cancel:   cancel,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
 		logger: slog.Default().With(
 			"component", "bridge_manager",
 		),
@@ -109,6 +122,10 @@ func NewBridgeManager(config BridgeConfig) (*BridgeManager, error) {
 		bm.adapters = make(map[Platform]sdtw.SDTWAdapter)
 	}
 
+	if bm.config.ProfileSyncInterval == 0 {
+		bm.config.ProfileSyncInterval = DefaultProfileSyncInterval
+	}
+
 	return bm, nil
 }
 
@@ -120,6 +137,12 @@ func (bm *BridgeManager) Start() error {
 	bm.wg.Add(1)
 	go bm.processMatrixEvents()
 
+	// Keep ghost display names and avatars current
+	if bm.config.ProfileSyncInterval > 0 {
+		bm.wg.Add(1)
+		go bm.syncProfilesPeriodically(bm.config.ProfileSyncInterval)
+	}
+
 	// Start platform adapters
 	for platform, adapter := range bm.adapters {
 		if err := adapter.Start(bm.ctx); err != nil {
@@ -229,6 +252,88 @@ func (bm *BridgeManager) UnbridgeChannel(platform Platform, channelID string) er
 	return nil
 }
 
+// maxBackfillMessages caps how much history a single backfill injects
+const maxBackfillMessages = 100
+
+// Backfill copies up to limit of a bridged channel's most recent messages
+// into its Matrix room, sent by ghost users with their original timestamps.
+// The platform adapter must implement sdtw.HistoryProvider. Messages are
+// sent oldest first and backfill stops at the first failed send; the number
+// of messages sent is returned either way.
+func (bm *BridgeManager) Backfill(ctx context.Context, platform Platform, channelID string, limit int) (int, error) {
+	key := fmt.Sprintf("%s:%s", platform, channelID)
+
+	bm.channelMu.RLock()
+	channel, exists := bm.channels[key]
+	bm.channelMu.RUnlock()
+	if !exists {
+		return 0, fmt.Errorf("channel %s not bridged", key)
+	}
+
+	bm.adapterMu.RLock()
+	adapter, ok := bm.adapters[platform]
+	bm.adapterMu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("no adapter registered for %s", platform)
+	}
+	history, ok := adapter.(sdtw.HistoryProvider)
+	if !ok {
+		return 0, fmt.Errorf("%s adapter does not support history backfill", platform)
+	}
+
+	if limit <= 0 || limit > maxBackfillMessages {
+		limit = maxBackfillMessages
+	}
+
+	messages, err := history.FetchHistory(ctx, sdtw.Target{Platform: string(platform), RoomID: channel.MatrixRoomID, Channel: channelID}, limit)
+	if err != nil {
+		return 0, fmt.Errorf("fetch history: %w", err)
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.Before(messages[j].Timestamp)
+	})
+	if len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+
+	sent := 0
+	for _, msg := range messages {
+		if msg.Content == "" {
+			continue
+		}
+
+		senderID := msg.Metadata["user_id"]
+		if senderID == "" {
+			senderID = channelID
+		}
+		ghostUserID, err := bm.ensureGhostUser(channel, senderID, msg.Metadata["user_name"])
+		if err != nil {
+			return sent, fmt.Errorf("ghost user for %s: %w", senderID, err)
+		}
+
+		// A transaction ID derived from the platform message makes a retried
+		// backfill idempotent on the homeserver
+		txnID := fmt.Sprintf("backfill-%s-%s", platform, msg.ID)
+		_, err = bm.client.SendMessageAt(ctx, channel.MatrixRoomID, txnID, MessageEvent{
+			MsgType: "m.text",
+			Body:    msg.Content,
+		}, ghostUserID, msg.Timestamp)
+		if err != nil {
+			return sent, fmt.Errorf("send message %s: %w", msg.ID, err)
+		}
+		sent++
+	}
+
+	bm.logger.Info("channel_backfilled",
+		"matrix_room", channel.MatrixRoomID,
+		"platform", platform,
+		"channel", channelID,
+		"messages", sent,
+	)
+
+	return sent, nil
+}
+
 // processMatrixEvents handles events from Matrix to external platforms
 func (bm *BridgeManager) processMatrixEvents() {
 	defer bm.wg.Done()
@@ -343,44 +448,14 @@ func (bm *BridgeManager) HandlePlatformEvent(evt sdtw.ExternalEvent) {
 	if senderID == "" {
 		senderID = evt.Source
 	}
-	ghostUserID := bm.as.GenerateGhostUserID(string(platform), senderID)
-
-	// Register ghost user if not exists
-	ghostUser, exists := bm.as.GetGhostUser(ghostUserID)
-	if !exists {
-		senderName := evt.Metadata["user_name"]
-		ghostUser = &GhostUser{
-			UserID:      ghostUserID,
-			Platform:    string(platform),
-			ExternalID:  senderID,
-			DisplayName: senderName,
-		}
-		if err := bm.as.RegisterGhostUser(ghostUser); err != nil {
-			bm.logger.Error("register_ghost_user_failed",
-				"user_id", ghostUserID,
-				"error", err,
-			)
-			return
-		}
-
-		// Ensure ghost user is in the Matrix room
-		if err := bm.client.JoinRoom(bm.ctx, channel.MatrixRoomID, ghostUserID); err != nil {
-			bm.logger.Error("ghost_join_room_failed",
-				"user_id", ghostUserID,
-				"room_id", channel.MatrixRoomID,
-				"error", err,
-			)
-		}
-
-		// Update display name if provided
-		if senderName != "" {
-			bm.client.SetDisplayName(bm.ctx, senderName, ghostUserID)
-		}
+	ghostUserID, err := bm.ensureGhostUser(channel, senderID, evt.Metadata["user_name"])
+	if err != nil {
+		return
 	}
 
 	// Send message to Matrix
 	content := evt.Content
-	_, err := bm.client.SendText(bm.ctx, channel.MatrixRoomID, content, ghostUserID)
+	_, err = bm.client.SendText(bm.ctx, channel.MatrixRoomID, content, ghostUserID)
 	if err != nil {
 		bm.logger.Error("send_to_matrix_failed",
 			"room_id", channel.MatrixRoomID,
@@ -390,6 +465,46 @@ func (bm *BridgeManager) HandlePlatformEvent(evt sdtw.ExternalEvent) {
 	}
 }
 
+// ensureGhostUser returns the ghost user for a platform sender, registering
+// it and joining it to the channel's Matrix room on first use
+func (bm *BridgeManager) ensureGhostUser(channel *BridgedChannel, senderID, senderName string) (string, error) {
+	ghostUserID := bm.as.GenerateGhostUserID(string(channel.Platform), senderID)
+
+	if _, exists := bm.as.GetGhostUser(ghostUserID); exists {
+		return ghostUserID, nil
+	}
+
+	ghostUser := &GhostUser{
+		UserID:      ghostUserID,
+		Platform:    string(channel.Platform),
+		ExternalID:  senderID,
+		DisplayName: senderName,
+	}
+	if err := bm.as.RegisterGhostUser(ghostUser); err != nil {
+		bm.logger.Error("register_ghost_user_failed",
+			"user_id", ghostUserID,
+			"error", err,
+		)
+		return "", err
+	}
+
+	// Ensure ghost user is in the Matrix room
+	if err := bm.client.JoinRoom(bm.ctx, channel.MatrixRoomID, ghostUserID); err != nil {
+		bm.logger.Error("ghost_join_room_failed",
+			"user_id", ghostUserID,
+			"room_id", channel.MatrixRoomID,
+			"error", err,
+		)
+	}
+
+	// Update display name if provided
+	if senderName != "" {
+		bm.client.SetDisplayName(bm.ctx, senderName, ghostUserID)
+	}
+
+	return ghostUserID, nil
+}
+
 // GetBridgedChannels returns all bridged channels
 func (bm *BridgeManager) GetBridgedChannels() []*BridgedChannel {
 	bm.channelMu.RLock()
@@ -2,7 +2,15 @@
 package appservice
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/internal/sdtw"
 )
 
 func TestNewBridgeManager(t *testing.T) {
@@ -202,3 +210,100 @@ func TestPlatformConstants(t *testing.T) {
 		}
 	}
 }
+
+// historyAdapter adds canned channel history to a real adapter
+type historyAdapter struct {
+	sdtw.SDTWAdapter
+	messages []sdtw.Message
+}
+
+func (h *historyAdapter) FetchHistory(ctx context.Context, target sdtw.Target, limit int) ([]sdtw.Message, error) {
+	return h.messages, nil
+}
+
+func TestBridgeManagerBackfill(t *testing.T) {
+	var mu sync.Mutex
+	var sends []*http.Request
+	homeserver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/send/m.room.message/") {
+			mu.Lock()
+			sends = append(sends, r)
+			mu.Unlock()
+			w.Write([]byte(`{"event_id": "$event"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer homeserver.Close()
+
+	as, _ := New(Config{
+		HomeserverURL: homeserver.URL,
+		ASToken:       "test_as_token",
+		HSToken:       "test_hs_token",
+		ID:            "armorclaw-bridge",
+		ServerName:    "example.com",
+	})
+
+	first := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	adapter := &historyAdapter{
+		SDTWAdapter: sdtw.NewSlackAdapter(),
+		messages: []sdtw.Message{
+			{ID: "2", Content: "second", Timestamp: first.Add(time.Minute), Metadata: map[string]string{"user_id": "U2"}},
+			{ID: "1", Content: "first", Timestamp: first, Metadata: map[string]string{"user_id": "U1", "user_name": "Alice"}},
+			{ID: "3", Content: "", Timestamp: first.Add(2 * time.Minute), Metadata: map[string]string{"user_id": "U1"}},
+		},
+	}
+	bm, err := NewBridgeManager(BridgeConfig{
+		AppService: as,
+		Client:     NewClient(homeserver.URL, "test_as_token"),
+		Adapters:   map[Platform]sdtw.SDTWAdapter{PlatformSlack: adapter},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create BridgeManager: %v", err)
+	}
+
+	if _, err := bm.Backfill(context.Background(), PlatformSlack, "C12345", 10); err == nil {
+		t.Error("Backfill() should fail for an unbridged channel")
+	}
+
+	bm.BridgeChannel("!room:example.com", PlatformSlack, "C12345")
+	sent, err := bm.Backfill(context.Background(), PlatformSlack, "C12345", 10)
+	if err != nil {
+		t.Fatalf("Backfill() error = %v", err)
+	}
+	if sent != 2 || len(sends) != 2 {
+		t.Fatalf("Backfill() sent %d (%d requests), want 2 without the empty message", sent, len(sends))
+	}
+
+	// Oldest first, as the ghost user, with the original timestamp
+	q := sends[0].URL.Query()
+	if q.Get("user_id") != as.GenerateGhostUserID("slack", "U1") {
+		t.Errorf("first message sent as %q", q.Get("user_id"))
+	}
+	if q.Get("ts") != "1767348000000" {
+		t.Errorf("first message ts = %q, want 1767348000000", q.Get("ts"))
+	}
+	if !strings.HasSuffix(sends[0].URL.Path, "/backfill-slack-1") {
+		t.Errorf("first message path = %s, want the backfill transaction ID", sends[0].URL.Path)
+	}
+}
+
+func TestBridgeManagerBackfillUnsupported(t *testing.T) {
+	as, _ := New(Config{
+		HomeserverURL: "https://matrix.example.com",
+		ASToken:       "test_as_token",
+		HSToken:       "test_hs_token",
+		ID:            "armorclaw-bridge",
+		ServerName:    "example.com",
+	})
+	bm, _ := NewBridgeManager(BridgeConfig{
+		AppService: as,
+		Client:     NewClient("https://matrix.example.com", "test_as_token"),
+		Adapters:   map[Platform]sdtw.SDTWAdapter{PlatformTeams: sdtw.NewWhatsAppAdapter()},
+	})
+	bm.BridgeChannel("!room:example.com", PlatformTeams, "19:abc")
+
+	if _, err := bm.Backfill(context.Background(), PlatformTeams, "19:abc", 10); err == nil {
+		t.Error("Backfill() should fail when the adapter cannot read history")
+	}
+}
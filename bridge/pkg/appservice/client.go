@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -102,6 +103,18 @@ func (c *Client) CreateRoom(ctx context.Context, req RoomCreateRequest, asUser s
 
 // SendMessage sends a message to a room
 func (c *Client) SendMessage(ctx context.Context, roomID, eventID string, message MessageEvent, asUser string) (string, error) {
+	return c.sendMessage(ctx, roomID, eventID, message, asUser, time.Time{})
+}
+
+// SendMessageAt sends a message with an explicit origin_server_ts, used to
+// backfill messages with their original send time. The homeserver only
+// honours the timestamp for application service users.
+func (c *Client) SendMessageAt(ctx context.Context, roomID, eventID string, message MessageEvent, asUser string, ts time.Time) (string, error) {
+	return c.sendMessage(ctx, roomID, eventID, message, asUser, ts)
+}
+
+// sendMessage sends a message, overriding its timestamp when ts is set
+func (c *Client) sendMessage(ctx context.Context, roomID, eventID string, message MessageEvent, asUser string, ts time.Time) (string, error) {
 	// Generate transaction ID if not provided
 	if eventID == "" {
 		eventID = fmt.Sprintf("m%d", time.Now().UnixNano())
@@ -121,6 +134,11 @@ func (c *Client) SendMessage(ctx context.Context, roomID, eventID string, messag
 	}
 
 	c.setHeaders(httpReq, asUser)
+	if !ts.IsZero() {
+		q := httpReq.URL.Query()
+		q.Set("ts", strconv.FormatInt(ts.UnixMilli(), 10))
+		httpReq.URL.RawQuery = q.Encode()
+	}
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -315,6 +333,43 @@ func (c *Client) SetAvatarURL(ctx context.Context, avatarURL, asUser string) err
 	return nil
 }
 
+// UploadMedia uploads content to the homeserver's media repository and
+// returns its mxc:// content URI
+func (c *Client) UploadMedia(ctx context.Context, content io.Reader, contentType, filename, asUser string) (string, error) {
+	endpoint := fmt.Sprintf("%s/_matrix/media/v3/upload", c.homeserverURL)
+	if filename != "" {
+		endpoint += "?filename=" + url.QueryEscape(filename)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, content)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	c.setHeaders(httpReq, asUser)
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("upload media failed (%d): %s", resp.StatusCode, string(errBody))
+	}
+
+	var result struct {
+		ContentURI string `json:"content_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	return result.ContentURI, nil
+}
+
 // GetRoomState gets state from a room
 func (c *Client) GetRoomState(ctx context.Context, roomID, eventType, stateKey, asUser string) (map[string]interface{}, error) {
 	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/state/%s",
@@ -0,0 +1,170 @@
+// Package appservice provides bridge management for SDTW adapters
+// This file syncs ghost user display names and avatars from their platforms
+package appservice
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/armorclaw/bridge/internal/sdtw"
+)
+
+const (
+	// DefaultProfileSyncInterval is how often ghost profiles are refreshed
+	// when BridgeConfig.ProfileSyncInterval is zero
+	DefaultProfileSyncInterval = 6 * time.Hour
+
+	// maxAvatarBytes caps the size of a platform avatar copied to Matrix
+	maxAvatarBytes = 5 << 20
+)
+
+// ProfileSyncResult summarises a ghost profile sync
+type ProfileSyncResult struct {
+	Checked int `json:"checked"` // ghosts whose platform supports profile lookups
+	Updated int `json:"updated"` // ghosts whose name or avatar changed
+	Failed  int `json:"failed"`
+}
+
+// SyncGhostProfiles refreshes the display name and avatar of every ghost
+// user from its platform. Ghosts on platforms whose adapter does not
+// implement sdtw.ProfileProvider are skipped. A failure for one ghost is
+// logged and counted without stopping the sync.
+func (bm *BridgeManager) SyncGhostProfiles(ctx context.Context) (ProfileSyncResult, error) {
+	var result ProfileSyncResult
+
+	for _, ghost := range bm.as.ListGhostUsers() {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		bm.adapterMu.RLock()
+		adapter := bm.adapters[Platform(ghost.Platform)]
+		bm.adapterMu.RUnlock()
+		provider, ok := adapter.(sdtw.ProfileProvider)
+		if !ok {
+			continue
+		}
+		result.Checked++
+
+		updated, err := bm.syncGhostProfile(ctx, provider, ghost)
+		if err != nil {
+			result.Failed++
+			bm.logger.Warn("ghost_profile_sync_failed",
+				"user_id", ghost.UserID,
+				"platform", ghost.Platform,
+				"error", err,
+			)
+			continue
+		}
+		if updated {
+			result.Updated++
+		}
+	}
+
+	bm.logger.Info("ghost_profiles_synced",
+		"checked", result.Checked,
+		"updated", result.Updated,
+		"failed", result.Failed,
+	)
+
+	return result, nil
+}
+
+// syncGhostProfile copies one ghost's platform profile to Matrix, reporting
+// whether anything changed
+func (bm *BridgeManager) syncGhostProfile(ctx context.Context, provider sdtw.ProfileProvider, ghost GhostUser) (bool, error) {
+	profile, err := provider.FetchUserProfile(ctx, ghost.ExternalID)
+	if err != nil {
+		return false, fmt.Errorf("fetch profile: %w", err)
+	}
+
+	displayName, avatarURL, platformAvatarURL := ghost.DisplayName, ghost.AvatarURL, ghost.PlatformAvatarURL
+	changed := false
+
+	if profile.DisplayName != "" && profile.DisplayName != displayName {
+		if err := bm.client.SetDisplayName(ctx, profile.DisplayName, ghost.UserID); err != nil {
+			return false, err
+		}
+		displayName = profile.DisplayName
+		changed = true
+	}
+
+	if profile.AvatarURL != "" && profile.AvatarURL != platformAvatarURL {
+		contentURI, err := bm.uploadAvatar(ctx, profile.AvatarURL, ghost.UserID)
+		if err != nil {
+			return false, err
+		}
+		if err := bm.client.SetAvatarURL(ctx, contentURI, ghost.UserID); err != nil {
+			return false, err
+		}
+		avatarURL, platformAvatarURL = contentURI, profile.AvatarURL
+		changed = true
+	}
+
+	if err := bm.as.UpdateGhostProfile(ghost.UserID, displayName, avatarURL, platformAvatarURL); err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// uploadAvatar downloads a platform avatar and uploads it to the
+// homeserver, returning its content URI
+func (bm *BridgeManager) uploadAvatar(ctx context.Context, avatarURL, asUser string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, avatarURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("create avatar request: %w", err)
+	}
+
+	resp, err := bm.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("download avatar: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download avatar failed (%d)", resp.StatusCode)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("avatar is not an image: %q", contentType)
+	}
+	if resp.ContentLength > maxAvatarBytes {
+		return "", fmt.Errorf("avatar exceeds %d bytes", maxAvatarBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxAvatarBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("read avatar: %w", err)
+	}
+	if len(data) > maxAvatarBytes {
+		return "", fmt.Errorf("avatar exceeds %d bytes", maxAvatarBytes)
+	}
+
+	return bm.client.UploadMedia(ctx, bytes.NewReader(data), contentType, path.Base(req.URL.Path), asUser)
+}
+
+// syncProfilesPeriodically refreshes ghost profiles every interval until
+// the bridge manager stops
+func (bm *BridgeManager) syncProfilesPeriodically(interval time.Duration) {
+	defer bm.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-bm.ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := bm.SyncGhostProfiles(bm.ctx); err != nil && bm.ctx.Err() == nil {
+				bm.logger.Error("ghost_profile_sync_failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package appservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/internal/sdtw"
+)
+
+// profileAdapter adds canned user profiles to a real adapter
+type profileAdapter struct {
+	sdtw.SDTWAdapter
+	profiles map[string]*sdtw.UserProfile
+}
+
+func (p *profileAdapter) FetchUserProfile(ctx context.Context, userID string) (*sdtw.UserProfile, error) {
+	profile, ok := p.profiles[userID]
+	if !ok {
+		return nil, fmt.Errorf("unknown user %s", userID)
+	}
+	return profile, nil
+}
+
+// profileHomeserver records profile updates and media uploads, and serves
+// avatar images under /avatars/
+type profileHomeserver struct {
+	*httptest.Server
+	mu           sync.Mutex
+	displayNames map[string]string
+	avatars      map[string]string
+	uploads      int
+}
+
+func newProfileHomeserver(t *testing.T) *profileHomeserver {
+	t.Helper()
+	hs := &profileHomeserver{displayNames: map[string]string{}, avatars: map[string]string{}}
+	hs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hs.mu.Lock()
+		defer hs.mu.Unlock()
+
+		user := r.URL.Query().Get("user_id")
+		var body map[string]string
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/avatars/"):
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("\x89PNG"))
+		case r.URL.Path == "/_matrix/media/v3/upload":
+			hs.uploads++
+			fmt.Fprintf(w, `{"content_uri": "mxc://example.com/avatar%d"}`, hs.uploads)
+		case strings.HasSuffix(r.URL.Path, "/displayname"):
+			json.NewDecoder(r.Body).Decode(&body)
+			hs.displayNames[user] = body["displayname"]
+			w.Write([]byte(`{}`))
+		case strings.HasSuffix(r.URL.Path, "/avatar_url"):
+			json.NewDecoder(r.Body).Decode(&body)
+			hs.avatars[user] = body["avatar_url"]
+			w.Write([]byte(`{}`))
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(hs.Close)
+	return hs
+}
+
+func newProfileSyncManager(t *testing.T, hs *profileHomeserver, adapter sdtw.SDTWAdapter) (*BridgeManager, *AppService) {
+	t.Helper()
+	as, _ := New(Config{
+		HomeserverURL: hs.URL,
+		ASToken:       "test_as_token",
+		HSToken:       "test_hs_token",
+		ID:            "armorclaw-bridge",
+		ServerName:    "example.com",
+	})
+	bm, err := NewBridgeManager(BridgeConfig{
+		AppService:          as,
+		Client:              NewClient(hs.URL, "test_as_token"),
+		Adapters:            map[Platform]sdtw.SDTWAdapter{PlatformSlack: adapter},
+		ProfileSyncInterval: -1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create BridgeManager: %v", err)
+	}
+	return bm, as
+}
+
+func TestSyncGhostProfiles(t *testing.T) {
+	hs := newProfileHomeserver(t)
+	adapter := &profileAdapter{
+		SDTWAdapter: sdtw.NewSlackAdapter(),
+		profiles: map[string]*sdtw.UserProfile{
+			"U1": {UserID: "U1", DisplayName: "Alice", AvatarURL: hs.URL + "/avatars/u1.png"},
+		},
+	}
+	bm, as := newProfileSyncManager(t, hs, adapter)
+
+	ghostID := as.GenerateGhostUserID("slack", "U1")
+	as.RegisterGhostUser(&GhostUser{UserID: ghostID, Platform: "slack", ExternalID: "U1"})
+	as.RegisterGhostUser(&GhostUser{UserID: "@slack_U404:example.com", Platform: "slack", ExternalID: "U404"})
+	as.RegisterGhostUser(&GhostUser{UserID: "@teams_T1:example.com", Platform: "teams", ExternalID: "T1"})
+
+	result, err := bm.SyncGhostProfiles(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (ProfileSyncResult{Checked: 2, Updated: 1, Failed: 1}) {
+		t.Errorf("result = %+v, want 2 checked, 1 updated, 1 failed", result)
+	}
+	if hs.displayNames[ghostID] != "Alice" || hs.avatars[ghostID] != "mxc://example.com/avatar1" {
+		t.Errorf("homeserver profile = %q, %q", hs.displayNames[ghostID], hs.avatars[ghostID])
+	}
+	ghost, _ := as.GetGhostUser(ghostID)
+	if ghost.DisplayName != "Alice" || ghost.AvatarURL != "mxc://example.com/avatar1" || ghost.ProfileSyncedAt.IsZero() {
+		t.Errorf("ghost = %+v", ghost)
+	}
+
+	// An unchanged profile is not uploaded again
+	result, _ = bm.SyncGhostProfiles(context.Background())
+	if result.Updated != 0 || hs.uploads != 1 {
+		t.Errorf("second sync updated %d with %d uploads, want no changes", result.Updated, hs.uploads)
+	}
+
+	// A renamed user is picked up
+	adapter.profiles["U1"] = &sdtw.UserProfile{UserID: "U1", DisplayName: "Alice Smith", AvatarURL: hs.URL + "/avatars/u1.png"}
+	result, _ = bm.SyncGhostProfiles(context.Background())
+	if result.Updated != 1 || hs.displayNames[ghostID] != "Alice Smith" || hs.uploads != 1 {
+		t.Errorf("rename sync = %+v, name %q, uploads %d", result, hs.displayNames[ghostID], hs.uploads)
+	}
+}
+
+func TestSyncGhostProfilesPeriodically(t *testing.T) {
+	hs := newProfileHomeserver(t)
+	adapter := &profileAdapter{
+		SDTWAdapter: sdtw.NewSlackAdapter(),
+		profiles:    map[string]*sdtw.UserProfile{"U1": {UserID: "U1", DisplayName: "Alice"}},
+	}
+	bm, as := newProfileSyncManager(t, hs, adapter)
+	bm.config.ProfileSyncInterval = 10 * time.Millisecond
+	ghostID := as.GenerateGhostUserID("slack", "U1")
+	as.RegisterGhostUser(&GhostUser{UserID: ghostID, Platform: "slack", ExternalID: "U1"})
+
+	bm.Start()
+	defer bm.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		hs.mu.Lock()
+		name := hs.displayNames[ghostID]
+		hs.mu.Unlock()
+		if name == "Alice" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("periodic sync did not update the ghost display name")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -3,6 +3,7 @@ package audit
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sync"
@@ -72,18 +73,48 @@ type Entry struct {
 	RoomID    string      `json:"room_id"`
 	UserID    string      `json:"user_id"`
 	Details   interface{} `json:"details,omitempty"`
+
+	// Set when the hash chain is enabled; see VerifyChain
+	Hash         string `json:"hash,omitempty"`
+	PreviousHash string `json:"previous_hash,omitempty"`
 }
 
 type AuditLog struct {
-	mu     sync.RWMutex
-	path   string
-	events []Entry
-	maxLen int
+	mu        sync.RWMutex
+	path      string
+	events    []Entry
+	maxLen    int
+	hashChain bool
+	chainKey  []byte
+	lastHash  string
+
+	retention     time.Duration
+	pruneInterval time.Duration
+	prunedTotal   int
+	logger        *slog.Logger
 }
 
 type Config struct {
 	Path   string
 	MaxLen int
+
+	// HashChain links each new entry to the previous one by hash so that
+	// VerifyChain can detect edited, inserted or deleted entries
+	HashChain bool
+
+	// ChainKey keys the HMAC-SHA256 that links chained entries, so the
+	// chain cannot be recomputed without it. Required with HashChain, at
+	// least 32 bytes, and must stay the same across restarts.
+	ChainKey []byte
+
+	// RetentionDays deletes entries older than this many days, on load and
+	// from StartPruning. 0 keeps entries until MaxLen is reached.
+	RetentionDays int
+
+	// PruneInterval is how often StartPruning runs (default 1h)
+	PruneInterval time.Duration
+
+	Logger *slog.Logger
 }
 
 func DefaultConfig() Config {
@@ -97,16 +128,38 @@ func NewAuditLog(cfg Config) (*AuditLog, error) {
 	if cfg.MaxLen == 0 {
 		cfg.MaxLen = 10000
 	}
+	if cfg.RetentionDays < 0 {
+		return nil, fmt.Errorf("invalid audit retention: %d days", cfg.RetentionDays)
+	}
+	if cfg.HashChain && len(cfg.ChainKey) < minChainKeyLen {
+		return nil, fmt.Errorf("audit hash chain needs a key of at least %d bytes", minChainKeyLen)
+	}
+	if cfg.PruneInterval == 0 {
+		cfg.PruneInterval = defaultPruneInterval
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default().With("component", "audit_log")
+	}
 
 	al := &AuditLog{
-		path:   cfg.Path,
-		events: make([]Entry, 0, 1000),
-		maxLen: cfg.MaxLen,
+		path:          cfg.Path,
+		events:        make([]Entry, 0, 1000),
+		maxLen:        cfg.MaxLen,
+		hashChain:     cfg.HashChain,
+		chainKey:      cfg.ChainKey,
+		retention:     time.Duration(cfg.RetentionDays) * 24 * time.Hour,
+		pruneInterval: cfg.PruneInterval,
+		logger:        cfg.Logger,
 	}
 
 	if err := al.loadFromFile(); err != nil && !os.IsNotExist(err) {
 		return nil, fmt.Errorf("failed to load audit log: %w", err)
 	}
+	al.lastHash = lastChainHash(al.events)
+
+	if _, err := al.Prune(); err != nil {
+		return nil, fmt.Errorf("failed to prune audit log: %w", err)
+	}
 
 	return al, nil
 }
@@ -119,6 +172,12 @@ func (al *AuditLog) Log(entry Entry) error {
 		entry.Timestamp = time.Now()
 	}
 
+	if al.hashChain {
+		if err := al.chain(&entry); err != nil {
+			return err
+		}
+	}
+
 	al.events = append(al.events, entry)
 
 	if len(al.events) > al.maxLen {
@@ -145,6 +204,26 @@ type QueryParams struct {
 	SessionID string
 	RoomID    string
 	Since     time.Time
+	Until     time.Time
+}
+
+func (p QueryParams) matches(entry Entry) bool {
+	if p.EventType != "" && entry.EventType != p.EventType {
+		return false
+	}
+	if p.SessionID != "" && entry.SessionID != p.SessionID {
+		return false
+	}
+	if p.RoomID != "" && entry.RoomID != p.RoomID {
+		return false
+	}
+	if !p.Since.IsZero() && entry.Timestamp.Before(p.Since) {
+		return false
+	}
+	if !p.Until.IsZero() && entry.Timestamp.After(p.Until) {
+		return false
+	}
+	return true
 }
 
 func (al *AuditLog) Query(params QueryParams) ([]Entry, error) {
@@ -161,17 +240,7 @@ func (al *AuditLog) Query(params QueryParams) ([]Entry, error) {
 	var result []Entry
 	for i := len(al.events) - 1; i >= 0 && len(result) < params.Limit; i-- {
 		entry := al.events[i]
-
-		if params.EventType != "" && entry.EventType != params.EventType {
-			continue
-		}
-		if params.SessionID != "" && entry.SessionID != params.SessionID {
-			continue
-		}
-		if params.RoomID != "" && entry.RoomID != params.RoomID {
-			continue
-		}
-		if !params.Since.IsZero() && entry.Timestamp.Before(params.Since) {
+		if !params.matches(entry) {
 			continue
 		}
 
@@ -192,6 +261,7 @@ func (al *AuditLog) Clear() error {
 	defer al.mu.Unlock()
 
 	al.events = make([]Entry, 0)
+	al.lastHash = ""
 	return al.saveToFile()
 }
 
@@ -252,5 +322,6 @@ func (al *AuditLog) ImportJSON(data []byte) error {
 	}
 
 	al.events = events
+	al.lastHash = lastChainHash(events)
 	return al.saveToFile()
 }
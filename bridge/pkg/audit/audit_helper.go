@@ -564,6 +564,48 @@ func (l *CriticalOperationLogger) LogProfileAccess(ctx context.Context, profileI
 	return err
 }
 
+// LogProfileRead logs a successful profile read in its own event type,
+// listing the sensitive fields that were decrypted for it
+// CRITICAL: Never log actual PII values - only field names
+func (l *CriticalOperationLogger) LogProfileRead(ctx context.Context, profileID string, decryptedFields []string) error {
+	l.mu.RLock()
+	auditLog := l.auditLog
+	l.mu.RUnlock()
+
+	if auditLog == nil {
+		return nil
+	}
+
+	actor := Actor{
+		Type: "system",
+		ID:   "keystore",
+	}
+	resource := Resource{
+		Type: "pii_profile",
+		ID:   profileID,
+	}
+	if decryptedFields == nil {
+		decryptedFields = []string{}
+	}
+	severity := "medium"
+	if len(decryptedFields) > 0 {
+		severity = "high"
+	}
+	details := map[string]interface{}{
+		"operation":        "retrieve",
+		"decrypted_fields": decryptedFields,
+	}
+	compliance := ComplianceFlags{
+		Category:      "pii_access",
+		Severity:      severity,
+		AuditRequired: true,
+		PHIInvolved:   true,
+	}
+
+	_, err := auditLog.LogEntry("pii_profile_read", actor, "retrieve", resource, details, compliance)
+	return err
+}
+
 // LogProfileDeleted logs when a user profile is deleted
 func (l *CriticalOperationLogger) LogProfileDeleted(ctx context.Context, profileID string) error {
 	l.mu.RLock()
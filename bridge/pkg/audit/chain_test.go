@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// deviceDetails is a struct whose JSON key order differs from the sorted
+// order a reload produces. Attempts is set beyond float64 precision.
+type deviceDetails struct {
+	Reason   string `json:"reason"`
+	DeviceID string `json:"device_id"`
+	Attempts int64  `json:"attempts"`
+}
+
+// testChainKey is a fixed 32-byte ChainKey for chained test logs
+var testChainKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newChainedLog(t *testing.T) *AuditLog {
+	t.Helper()
+
+	al, err := NewAuditLog(Config{Path: filepath.Join(t.TempDir(), "audit.json"), HashChain: true, ChainKey: testChainKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.LogEvent(EventDeviceApproved, "", "", "@admin:example.com", deviceDetails{Reason: "known", DeviceID: "dev-1", Attempts: 9007199254740993})
+	al.LogEvent(EventInviteCreated, "", "!room:example.com", "@admin:example.com", map[string]interface{}{"role": "user"})
+	al.LogEvent(EventCallEnded, "session-1", "!room:example.com", "@alice:example.com", nil)
+	return al
+}
+
+func TestVerifyChainValid(t *testing.T) {
+	al := newChainedLog(t)
+
+	if al.events[0].PreviousHash != genesisHash || al.events[1].PreviousHash != al.events[0].Hash {
+		t.Fatalf("entries not linked: %+v", al.events)
+	}
+	if result := al.VerifyChain(); !result.Valid || result.TotalEntries != 3 {
+		t.Errorf("VerifyChain() = %+v, want valid with 3 entries", result)
+	}
+
+	// Hashes survive a save and reload, and new entries continue the chain
+	reloaded, err := NewAuditLog(Config{Path: al.path, HashChain: true, ChainKey: testChainKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded.LogEvent(EventCallCreated, "session-2", "", "@bob:example.com", nil)
+	if result := reloaded.VerifyChain(); !result.Valid {
+		t.Errorf("VerifyChain() after reload = %+v, want valid", result)
+	}
+}
+
+func TestVerifyChainDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(al *AuditLog)
+		want   int64
+	}{
+		{"edited field", func(al *AuditLog) { al.events[1].UserID = "@mallory:example.com" }, 2},
+		{"edited details", func(al *AuditLog) { al.events[0].Details = map[string]interface{}{"reason": "forged"} }, 1},
+		{"deleted entry", func(al *AuditLog) { al.events = append(al.events[:1], al.events[2:]...) }, 2},
+		{"stripped hash", func(al *AuditLog) { al.events[2].Hash = "" }, 3},
+		{"rehashed without the key", func(al *AuditLog) {
+			al.events[1].UserID = "@mallory:example.com"
+			for i := 1; i < len(al.events); i++ {
+				al.events[i].PreviousHash = al.events[i-1].Hash
+				al.events[i].Hash, _ = entryHash([]byte("attacker-chosen-key-0123456789ab"), al.events[i])
+			}
+		}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			al := newChainedLog(t)
+			tt.tamper(al)
+
+			result := al.VerifyChain()
+			if result.Valid || result.TamperedAt == nil || *result.TamperedAt != tt.want {
+				t.Errorf("VerifyChain() = %+v, want tampering at entry %d", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyChainWrongKey(t *testing.T) {
+	al := newChainedLog(t)
+
+	other, err := NewAuditLog(Config{Path: al.path, HashChain: true, ChainKey: []byte("fedcba9876543210fedcba9876543210")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := other.VerifyChain(); result.Valid {
+		t.Errorf("VerifyChain() with another key = %+v, want invalid", result)
+	}
+}
+
+func TestNewAuditLogRequiresChainKey(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("too-short")} {
+		if _, err := NewAuditLog(Config{Path: filepath.Join(t.TempDir(), "audit.json"), HashChain: true, ChainKey: key}); err == nil {
+			t.Errorf("NewAuditLog() with a %d-byte chain key: want error", len(key))
+		}
+	}
+}
+
+func TestVerifyChainDisabled(t *testing.T) {
+	al, _ := NewAuditLog(Config{})
+	if result := al.VerifyChain(); !result.Valid {
+		t.Errorf("VerifyChain() on empty log = %+v, want valid", result)
+	}
+
+	al.LogEvent(EventCallCreated, "session-1", "", "@alice:example.com", nil)
+	if al.events[0].Hash != "" {
+		t.Error("entries should not be hashed without HashChain")
+	}
+	if result := al.VerifyChain(); result.Valid || result.Error == "" {
+		t.Errorf("VerifyChain() = %+v, want an error for an unchained log", result)
+	}
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// csvHeader lists the ExportCSV columns
+var csvHeader = []string{
+	"timestamp", "event_type", "session_id", "room_id", "user_id",
+	"details", "hash", "previous_hash",
+}
+
+// ExportCSV writes entries matching params to w as CSV, oldest first, and
+// returns the number of entries written. Details are encoded as JSON. A
+// Limit <= 0 exports every matching entry; otherwise the most recent Limit.
+// Unlike Query, the limit is not capped.
+func (al *AuditLog) ExportCSV(w io.Writer, params QueryParams) (int, error) {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	var entries []Entry
+	for _, entry := range al.events {
+		if params.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if params.Limit > 0 && len(entries) > params.Limit {
+		entries = entries[len(entries)-params.Limit:]
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return 0, err
+	}
+
+	for i, entry := range entries {
+		details := ""
+		if entry.Details != nil {
+			data, err := json.Marshal(entry.Details)
+			if err != nil {
+				return i, fmt.Errorf("failed to encode details of entry at %s: %w",
+					entry.Timestamp.Format(time.RFC3339Nano), err)
+			}
+			details = string(data)
+		}
+
+		record := []string{
+			entry.Timestamp.UTC().Format(time.RFC3339Nano),
+			string(entry.EventType),
+			entry.SessionID,
+			entry.RoomID,
+			entry.UserID,
+			details,
+			entry.Hash,
+			entry.PreviousHash,
+		}
+		if err := cw.Write(record); err != nil {
+			return i, err
+		}
+	}
+
+	cw.Flush()
+	return len(entries), cw.Error()
+}
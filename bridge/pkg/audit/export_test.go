@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+)
+
+func TestAuditLogExportCSV(t *testing.T) {
+	al := newChainedLog(t)
+
+	var buf bytes.Buffer
+	n, err := al.ExportCSV(&buf, QueryParams{RoomID: "!room:example.com"})
+	if err != nil {
+		t.Fatalf("ExportCSV() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("ExportCSV() wrote %d entries, want 2", n)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 || records[0][0] != "timestamp" || records[0][7] != "previous_hash" {
+		t.Fatalf("records = %v, want a header and 2 rows", records)
+	}
+
+	// Oldest first, with details as JSON and the chain hashes
+	if records[1][1] != string(EventInviteCreated) || records[1][5] != `{"role":"user"}` {
+		t.Errorf("first row = %v, want the invite with JSON details", records[1])
+	}
+	if records[2][6] != al.events[2].Hash || records[2][7] != al.events[1].Hash {
+		t.Errorf("last row = %v, want its hash and the previous entry's", records[2])
+	}
+	if _, err := time.Parse(time.RFC3339Nano, records[1][0]); err != nil {
+		t.Errorf("timestamp %q is not RFC3339: %v", records[1][0], err)
+	}
+}
+
+func TestAuditLogExportCSVLimit(t *testing.T) {
+	al := newChainedLog(t)
+
+	var buf bytes.Buffer
+	n, err := al.ExportCSV(&buf, QueryParams{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, _ := csv.NewReader(&buf).ReadAll()
+	if n != 1 || len(records) != 2 || records[1][1] != string(EventCallEnded) {
+		t.Errorf("records = %v, want only the most recent entry", records)
+	}
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// defaultPruneInterval is how often StartPruning runs by default
+const defaultPruneInterval = time.Hour
+
+// Prune deletes entries older than the retention window and returns how
+// many were removed. It does nothing if no retention is configured.
+func (al *AuditLog) Prune() (int, error) {
+	if al.retention <= 0 {
+		return 0, nil
+	}
+	return al.PruneBefore(time.Now().Add(-al.retention))
+}
+
+// PruneBefore deletes entries logged before cutoff and returns how many were
+// removed. With the hash chain enabled, the oldest remaining entry still
+// links to a pruned one; VerifyChain does not check that link.
+func (al *AuditLog) PruneBefore(cutoff time.Time) (int, error) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	kept := al.events[:0]
+	for _, entry := range al.events {
+		if !entry.Timestamp.Before(cutoff) {
+			kept = append(kept, entry)
+		}
+	}
+	pruned := len(al.events) - len(kept)
+	if pruned == 0 {
+		return 0, nil
+	}
+
+	// Zero the tail so pruned details can be garbage collected
+	for i := len(kept); i < len(al.events); i++ {
+		al.events[i] = Entry{}
+	}
+	al.events = kept
+	al.prunedTotal += pruned
+
+	attrs := []any{
+		"pruned", pruned,
+		"remaining", len(al.events),
+		"total_pruned", al.prunedTotal,
+		"cutoff", cutoff.UTC().Format(time.RFC3339),
+	}
+	if al.retention > 0 {
+		attrs = append(attrs, "retention_days", int(al.retention/(24*time.Hour)))
+	}
+	al.logger.Info("audit_log_pruned", attrs...)
+
+	return pruned, al.saveToFile()
+}
+
+// StartPruning prunes the log every PruneInterval until ctx is cancelled.
+// It does nothing if no retention is configured.
+func (al *AuditLog) StartPruning(ctx context.Context) {
+	if al.retention <= 0 {
+		return
+	}
+
+	al.logger.Info("audit_log_retention_enabled",
+		"retention_days", int(al.retention/(24*time.Hour)),
+		"interval", al.pruneInterval.String(),
+	)
+
+	go func() {
+		ticker := time.NewTicker(al.pruneInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := al.Prune(); err != nil {
+					al.logger.Error("audit_log_prune_failed", "error", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func logAt(t *testing.T, al *AuditLog, age time.Duration, eventType EventType) {
+	t.Helper()
+	if err := al.Log(Entry{Timestamp: time.Now().Add(-age), EventType: eventType}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPruneRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.json")
+	al, err := NewAuditLog(Config{Path: path, RetentionDays: 30, HashChain: true, ChainKey: testChainKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logAt(t, al, 45*24*time.Hour, EventCallCreated)
+	logAt(t, al, 31*24*time.Hour, EventCallEnded)
+	logAt(t, al, 29*24*time.Hour, EventDeviceApproved)
+	logAt(t, al, time.Hour, EventInviteCreated)
+
+	pruned, err := al.Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 2 || al.Count() != 2 || al.events[0].EventType != EventDeviceApproved {
+		t.Errorf("pruned %d, kept %+v; want the 2 entries inside 30 days", pruned, al.events)
+	}
+	if result := al.VerifyChain(); !result.Valid {
+		t.Errorf("VerifyChain() after pruning = %+v, want valid", result)
+	}
+
+	// The pruned log was saved
+	reloaded, err := NewAuditLog(Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.Count() != 2 {
+		t.Errorf("reloaded %d entries, want 2", reloaded.Count())
+	}
+}
+
+func TestPruneOnLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.json")
+	al, _ := NewAuditLog(Config{Path: path})
+	logAt(t, al, 100*24*time.Hour, EventCallCreated)
+	logAt(t, al, time.Hour, EventCallEnded)
+
+	// A shorter window applies as soon as the log is opened
+	al, err := NewAuditLog(Config{Path: path, RetentionDays: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if al.Count() != 1 {
+		t.Errorf("loaded %d entries, want 1 inside 90 days", al.Count())
+	}
+}
+
+func TestPruneWithoutRetention(t *testing.T) {
+	al, _ := NewAuditLog(Config{})
+	logAt(t, al, 365*24*time.Hour, EventCallCreated)
+
+	if pruned, _ := al.Prune(); pruned != 0 || al.Count() != 1 {
+		t.Errorf("pruned %d without retention, want 0", pruned)
+	}
+	if _, err := NewAuditLog(Config{RetentionDays: -1}); err == nil {
+		t.Error("negative retention should be rejected")
+	}
+}
+
+func TestStartPruning(t *testing.T) {
+	al, _ := NewAuditLog(Config{RetentionDays: 1, PruneInterval: 10 * time.Millisecond})
+	logAt(t, al, 48*time.Hour, EventCallCreated)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	al.StartPruning(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for al.Count() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("background pruning did not remove the expired entry")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
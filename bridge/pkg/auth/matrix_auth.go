@@ -446,6 +446,21 @@ var DefaultAdminMethods = []string{
 	"invite.list",
 	"invite.revoke",
 	"invite.validate",
+	"trust.add_sender",
+	"trust.remove_sender",
+	"trust.list",
+	"container.exec",
+	"container.logs",
+	"container.terminate",
+	"list_keys",
+	"find_duplicate_keys",
+	"rotate_key",
+	"list_key_versions",
+	"log.set_level",
+	"plugin.reload",
+	"recovery.cancel",
+	"resolve_error",
+	"export_errors",
 }
 
 // ============================================================================
@@ -248,6 +248,7 @@ func TestDefaultAdminMethodsContainDeviceInvite(t *testing.T) {
 	expected := []string{
 		"device.list", "device.get", "device.approve", "device.reject",
 		"invite.create", "invite.list", "invite.revoke", "invite.validate",
+		"container.exec",
 	}
 
 	for _, method := range expected {
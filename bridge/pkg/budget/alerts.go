@@ -0,0 +1,105 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+)
+
+// Budget error codes reported through the error system
+const (
+	codeBudgetWarning  = "BGT-001"
+	codeBudgetExceeded = "BGT-002"
+)
+
+// WithErrorSystem reports threshold crossings through the given error system.
+// Without one, crossings go to the global error notifier if it is set.
+func WithErrorSystem(system *errsys.System) BudgetTrackerOption {
+	return func(b *BudgetTracker) {
+		b.errorSystem = system
+	}
+}
+
+// SetErrorSystem sets the error system used for threshold notifications
+func (b *BudgetTracker) SetErrorSystem(system *errsys.System) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.errorSystem = system
+}
+
+// thresholdError builds the BGT error for a limit, or returns nil if spend
+// is below the alert threshold. Reaching the limit with hard stop enabled is
+// critical; anything else at or above the threshold is a warning.
+func (b *BudgetTracker) thresholdError(limitName string, spent, limit float64, window Window) *errsys.TracedError {
+	if limit <= 0 {
+		return nil
+	}
+
+	percent := spent / limit * 100
+	exceeded := spent >= limit
+	if !exceeded && (b.config.AlertThreshold <= 0 || percent < b.config.AlertThreshold) {
+		return nil
+	}
+
+	code := codeBudgetWarning
+	msg := fmt.Sprintf("%s budget at %.1f%% ($%.2f of $%.2f)", limitName, percent, spent, limit)
+	if exceeded {
+		msg = fmt.Sprintf("%s budget limit exceeded: $%.2f of $%.2f", limitName, spent, limit)
+		switch b.config.EnforcementMode() {
+		case ModeHardStop:
+			code = codeBudgetExceeded
+			msg += "; new requests are blocked until " + window.End.Format(time.RFC3339)
+		case ModeThrottle:
+			msg += "; requests are throttled until " + window.End.Format(time.RFC3339)
+		}
+	}
+
+	return errsys.NewBuilder(code).
+		WithMessage(msg).
+		WithFunction("BudgetTracker.checkLimits").
+		WithStateValue("limit", limitName).
+		WithStateValue("spent_usd", roundCents(spent)).
+		WithStateValue("limit_usd", limit).
+		WithStateValue("percent", math.Round(percent*10)/10).
+		WithStateValue("alert_threshold", b.config.AlertThreshold).
+		WithStateValue("hard_stop", b.config.HardStop).
+		WithStateValue("mode", string(b.config.EnforcementMode())).
+		WithStateValue("window_start", window.Start.Format(time.RFC3339)).
+		WithStateValue("window_end", window.End.Format(time.RFC3339)).
+		Build()
+}
+
+// reportThreshold notifies the admin the first time a limit crosses into a
+// new severity within its window. Callers must hold the write lock.
+func (b *BudgetTracker) reportThreshold(key, limitName string, spent, limit float64, window Window) {
+	traced := b.thresholdError(limitName, spent, limit, window)
+	if traced == nil {
+		return
+	}
+
+	key += "/" + traced.Code
+	windowKey := window.Start.Format(time.RFC3339)
+	if b.alerted[key] == windowKey {
+		return
+	}
+	b.alerted[key] = windowKey
+
+	ctx := context.Background()
+	var err error
+	if b.errorSystem != nil {
+		err = b.errorSystem.Notify(ctx, traced)
+	} else if errsys.GetGlobalNotifier() != nil {
+		err = errsys.GlobalNotify(ctx, traced)
+	}
+	if err != nil {
+		fmt.Printf("[BUDGET ALERT] %s - %s (notify failed: %v)\n", traced.Code, traced.Message, err)
+	}
+}
+
+// roundCents rounds a dollar amount to whole cents
+func roundCents(usd float64) float64 {
+	return math.Round(usd*100) / 100
+}
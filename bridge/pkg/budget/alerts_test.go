@@ -0,0 +1,90 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+)
+
+func TestThresholdError(t *testing.T) {
+	tracker, err := NewBudgetTracker(BudgetConfig{DailyLimitUSD: 10, AlertThreshold: 80})
+	if err != nil {
+		t.Fatalf("NewBudgetTracker failed: %v", err)
+	}
+	window := Schedule{Kind: ScheduleDaily}.Window(time.Now())
+
+	if traced := tracker.thresholdError("daily", 7.99, 10, window); traced != nil {
+		t.Errorf("Expected no error below threshold, got %s", traced.Code)
+	}
+
+	warning := tracker.thresholdError("daily", 8.25, 10, window)
+	if warning == nil || warning.Code != "BGT-001" || warning.Severity != errsys.SeverityWarning {
+		t.Fatalf("Expected BGT-001 warning at 82.5%%, got %+v", warning)
+	}
+	if warning.State["spent_usd"] != 8.25 || warning.State["limit_usd"] != 10.0 || warning.State["percent"] != 82.5 {
+		t.Errorf("Expected spend, limit and percent in state, got %v", warning.State)
+	}
+
+	// Without hard stop an exceeded limit is still only a warning
+	if exceeded := tracker.thresholdError("daily", 12, 10, window); exceeded == nil || exceeded.Code != "BGT-001" {
+		t.Errorf("Expected BGT-001 when exceeded without hard stop, got %+v", exceeded)
+	}
+
+	tracker.config.HardStop = true
+	critical := tracker.thresholdError("daily", 10, 10, window)
+	if critical == nil || critical.Code != "BGT-002" || critical.Severity != errsys.SeverityCritical {
+		t.Fatalf("Expected BGT-002 at hard stop, got %+v", critical)
+	}
+	if !strings.Contains(critical.Message, "blocked") {
+		t.Errorf("Expected message to mention blocked requests, got %q", critical.Message)
+	}
+}
+
+func TestThresholdNotifications(t *testing.T) {
+	system, err := errsys.Initialize(errsys.Config{
+		StoreEnabled:    false,
+		ConfigAdminMXID: "@admin:example.com",
+		Enabled:         true,
+		NotifyEnabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("initialize error system: %v", err)
+	}
+	defer system.Stop()
+	system.SetDryRun(true)
+
+	tracker, err := NewBudgetTracker(BudgetConfig{
+		DailyLimitUSD:  10,
+		AlertThreshold: 80,
+		HardStop:       true,
+	}, WithErrorSystem(system))
+	if err != nil {
+		t.Fatalf("NewBudgetTracker failed: %v", err)
+	}
+
+	// Below threshold, crossing it, staying above it, then hitting the limit
+	for _, cost := range []float64{5, 3.5, 0.5, 1} {
+		_ = tracker.RecordKeyUsage("key", "openai", cost)
+	}
+
+	messages := system.DryRunMessages()
+	if len(messages) != 2 {
+		t.Fatalf("Expected one warning and one critical notification, got %d: %+v", len(messages), messages)
+	}
+	if messages[0].Code != "BGT-001" || messages[1].Code != "BGT-002" {
+		t.Errorf("Expected BGT-001 then BGT-002, got %s then %s", messages[0].Code, messages[1].Code)
+	}
+	if !strings.Contains(messages[0].Message, "85.0%") {
+		t.Errorf("Expected warning to include the percentage, got %q", messages[0].Message)
+	}
+
+	// A reset starts a fresh window of alerts
+	if err := tracker.Reset(); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if len(tracker.alerted) != 0 {
+		t.Errorf("Expected reset to clear reported crossings, got %v", tracker.alerted)
+	}
+}
@@ -0,0 +1,103 @@
+package budget
+
+import (
+	"sort"
+	"time"
+)
+
+// ProviderSpend is the spend attributed to one provider
+type ProviderSpend struct {
+	Provider string  `json:"provider"`
+	CostUSD  float64 `json:"cost_usd"`
+	Records  int     `json:"records"`
+}
+
+// KeySpend is the spend attributed to one keystore credential
+type KeySpend struct {
+	KeyID    string  `json:"key_id"`
+	Provider string  `json:"provider"`
+	CostUSD  float64 `json:"cost_usd"`
+	Records  int     `json:"records"`
+}
+
+// SpendBreakdown groups spend since a point in time by provider and key.
+// Usage recorded without a key counts towards its provider and
+// UnattributedUSD but has no entry in ByKey.
+type SpendBreakdown struct {
+	Since           time.Time       `json:"since"`
+	TotalUSD        float64         `json:"total_usd"`
+	UnattributedUSD float64         `json:"unattributed_usd"`
+	ByProvider      []ProviderSpend `json:"by_provider"`
+	ByKey           []KeySpend      `json:"by_key"`
+}
+
+// unknownProvider labels usage recorded without a provider
+const unknownProvider = "unknown"
+
+// Breakdown returns spend recorded at or after since, grouped by provider
+// and key, largest spend first
+func (b *BudgetTracker) Breakdown(since time.Time) SpendBreakdown {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	providers := make(map[string]*ProviderSpend)
+	keys := make(map[string]*KeySpend)
+	result := SpendBreakdown{
+		Since:      since,
+		ByProvider: []ProviderSpend{},
+		ByKey:      []KeySpend{},
+	}
+
+	for _, record := range b.usageHistory {
+		if record.Timestamp.Before(since) {
+			continue
+		}
+		result.TotalUSD += record.CostUSD
+
+		provider := record.Provider
+		if provider == "" {
+			provider = unknownProvider
+		}
+		p, ok := providers[provider]
+		if !ok {
+			p = &ProviderSpend{Provider: provider}
+			providers[provider] = p
+		}
+		p.CostUSD += record.CostUSD
+		p.Records++
+
+		if record.KeyID == "" {
+			result.UnattributedUSD += record.CostUSD
+			continue
+		}
+		k, ok := keys[record.KeyID]
+		if !ok {
+			k = &KeySpend{KeyID: record.KeyID, Provider: provider}
+			keys[record.KeyID] = k
+		}
+		k.CostUSD += record.CostUSD
+		k.Records++
+	}
+
+	for _, p := range providers {
+		result.ByProvider = append(result.ByProvider, *p)
+	}
+	sort.Slice(result.ByProvider, func(i, j int) bool {
+		if result.ByProvider[i].CostUSD != result.ByProvider[j].CostUSD {
+			return result.ByProvider[i].CostUSD > result.ByProvider[j].CostUSD
+		}
+		return result.ByProvider[i].Provider < result.ByProvider[j].Provider
+	})
+
+	for _, k := range keys {
+		result.ByKey = append(result.ByKey, *k)
+	}
+	sort.Slice(result.ByKey, func(i, j int) bool {
+		if result.ByKey[i].CostUSD != result.ByKey[j].CostUSD {
+			return result.ByKey[i].CostUSD > result.ByKey[j].CostUSD
+		}
+		return result.ByKey[i].KeyID < result.ByKey[j].KeyID
+	})
+
+	return result
+}
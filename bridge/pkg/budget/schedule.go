@@ -0,0 +1,211 @@
+package budget
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Reset schedule kinds for the period budget (MonthlyLimitUSD)
+const (
+	ScheduleDaily   = "daily"
+	ScheduleWeekly  = "weekly"
+	ScheduleMonthly = "monthly"
+	ScheduleEvery   = "every"
+)
+
+// minScheduleEvery keeps custom windows from degenerating into per-request limits
+const minScheduleEvery = time.Hour
+
+// defaultScheduleAnchor is a Monday midnight, so "every:168h" lines up with
+// calendar weeks unless another anchor is given
+var defaultScheduleAnchor = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local)
+
+// Window is a budget period covering [Start, End)
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether t falls inside the window
+func (w Window) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// Schedule decides when the period budget resets. Windows are computed in
+// local time, so they follow the host's midnight.
+type Schedule struct {
+	Kind    string        // daily, weekly, monthly or every
+	Weekday time.Weekday  // weekly: day the window starts
+	Day     int           // monthly: day of the month the window starts (1-28)
+	Every   time.Duration // every: window length
+	Anchor  time.Time     // every: start of one window
+}
+
+// ParseSchedule parses a reset schedule:
+//
+//	monthly            1st of each month (default)
+//	monthly:15         15th of each month (1-28)
+//	weekly             Mondays
+//	weekly:sun         any weekday, by name or three-letter abbreviation
+//	daily              midnight
+//	every:14d          fixed-length windows of hours (h) or days (d),
+//	every:14d@2025-01-06  optionally anchored at a date
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	kind, arg, hasArg := strings.Cut(spec, ":")
+
+	switch kind {
+	case "", ScheduleMonthly:
+		s := Schedule{Kind: ScheduleMonthly, Day: 1}
+		if hasArg {
+			day, err := strconv.Atoi(arg)
+			if err != nil || day < 1 || day > 28 {
+				return Schedule{}, fmt.Errorf("invalid reset schedule %q: monthly day must be between 1 and 28", spec)
+			}
+			s.Day = day
+		}
+		return s, nil
+
+	case ScheduleWeekly:
+		s := Schedule{Kind: ScheduleWeekly, Weekday: time.Monday}
+		if hasArg {
+			day, ok := parseWeekday(arg)
+			if !ok {
+				return Schedule{}, fmt.Errorf("invalid reset schedule %q: unknown weekday %q", spec, arg)
+			}
+			s.Weekday = day
+		}
+		return s, nil
+
+	case ScheduleDaily:
+		if hasArg {
+			return Schedule{}, fmt.Errorf("invalid reset schedule %q: daily takes no argument", spec)
+		}
+		return Schedule{Kind: ScheduleDaily}, nil
+
+	case ScheduleEvery:
+		length, anchor, hasAnchor := strings.Cut(arg, "@")
+		every, err := parseScheduleDuration(length)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid reset schedule %q: %w", spec, err)
+		}
+		s := Schedule{Kind: ScheduleEvery, Every: every, Anchor: defaultScheduleAnchor}
+		if hasAnchor {
+			t, err := time.ParseInLocation("2006-01-02", anchor, time.Local)
+			if err != nil {
+				return Schedule{}, fmt.Errorf("invalid reset schedule %q: anchor must be a YYYY-MM-DD date", spec)
+			}
+			s.Anchor = t
+		}
+		return s, nil
+
+	default:
+		return Schedule{}, fmt.Errorf("invalid reset schedule %q: must be daily, weekly, monthly or every", spec)
+	}
+}
+
+// parseScheduleDuration accepts Go durations plus a whole-day "d" suffix
+func parseScheduleDuration(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window length %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window length %q", s)
+		}
+		d = parsed
+	}
+	if d < minScheduleEvery {
+		return 0, fmt.Errorf("window length must be at least %s", minScheduleEvery)
+	}
+	return d, nil
+}
+
+// parseWeekday matches a full or three-letter weekday name
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// Window returns the budget window containing t
+func (s Schedule) Window(t time.Time) Window {
+	t = t.In(time.Local)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+
+	switch s.Kind {
+	case ScheduleDaily:
+		return Window{Start: midnight, End: midnight.AddDate(0, 0, 1)}
+
+	case ScheduleWeekly:
+		back := (int(t.Weekday()) - int(s.Weekday) + 7) % 7
+		start := midnight.AddDate(0, 0, -back)
+		return Window{Start: start, End: start.AddDate(0, 0, 7)}
+
+	case ScheduleEvery:
+		n := t.Sub(s.Anchor) / s.Every
+		start := s.Anchor.Add(n * s.Every)
+		if start.After(t) {
+			start = start.Add(-s.Every)
+		}
+		return Window{Start: start, End: start.Add(s.Every)}
+
+	default:
+		day := s.Day
+		if day < 1 {
+			day = 1
+		}
+		start := time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, time.Local)
+		if start.After(t) {
+			start = start.AddDate(0, -1, 0)
+		}
+		return Window{Start: start, End: start.AddDate(0, 1, 0)}
+	}
+}
+
+// key identifies the window containing t in the usage maps. The default
+// monthly schedule keeps the "2006-01" keys used by earlier snapshots.
+func (s Schedule) key(t time.Time) string {
+	start := s.Window(t).Start
+	if s.Kind == ScheduleMonthly && s.Day <= 1 {
+		return start.Format("2006-01")
+	}
+	return start.Format(time.RFC3339)
+}
+
+// label names the period in limit errors and alerts
+func (s Schedule) label() string {
+	switch s.Kind {
+	case ScheduleDaily, ScheduleWeekly:
+		return s.Kind
+	case ScheduleEvery:
+		return "period"
+	default:
+		return ScheduleMonthly
+	}
+}
+
+// String returns the schedule in ParseSchedule syntax
+func (s Schedule) String() string {
+	switch s.Kind {
+	case ScheduleDaily:
+		return ScheduleDaily
+	case ScheduleWeekly:
+		return ScheduleWeekly + ":" + strings.ToLower(s.Weekday.String()[:3])
+	case ScheduleEvery:
+		return fmt.Sprintf("%s:%s@%s", ScheduleEvery, s.Every, s.Anchor.Format("2006-01-02"))
+	default:
+		return fmt.Sprintf("%s:%d", ScheduleMonthly, max(s.Day, 1))
+	}
+}
@@ -0,0 +1,101 @@
+package budget
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	valid := map[string]string{
+		"":                     "monthly:1",
+		"monthly":              "monthly:1",
+		"monthly:15":           "monthly:15",
+		"Weekly":               "weekly:mon",
+		"weekly:sunday":        "weekly:sun",
+		"weekly:fri":           "weekly:fri",
+		"daily":                "daily",
+		"every:14d@2025-01-06": "every:336h0m0s@2025-01-06",
+		"every:12h":            "every:12h0m0s@2024-01-01",
+	}
+	for spec, want := range valid {
+		schedule, err := ParseSchedule(spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) returned error: %v", spec, err)
+			continue
+		}
+		if got := schedule.String(); got != want {
+			t.Errorf("ParseSchedule(%q) = %s, want %s", spec, got, want)
+		}
+	}
+
+	invalid := []string{"monthly:31", "monthly:x", "weekly:someday", "daily:2", "every:30m", "every:2w", "every:7d@tomorrow", "yearly"}
+	for _, spec := range invalid {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("Expected ParseSchedule(%q) to fail", spec)
+		}
+	}
+}
+
+func TestScheduleWindow(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2025, time.March, 12, 15, 30, 0, 0, time.Local)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2025, month, d, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		spec  string
+		start time.Time
+		end   time.Time
+	}{
+		{"monthly", day(time.March, 1), day(time.April, 1)},
+		{"monthly:15", day(time.February, 15), day(time.March, 15)},
+		{"monthly:12", day(time.March, 12), day(time.April, 12)},
+		{"weekly", day(time.March, 10), day(time.March, 17)},
+		{"weekly:wed", day(time.March, 12), day(time.March, 19)},
+		{"weekly:thu", day(time.March, 6), day(time.March, 13)},
+		{"daily", day(time.March, 12), day(time.March, 13)},
+		{"every:14d@2025-03-03", day(time.March, 3), day(time.March, 17)},
+		{"every:14d@2025-04-14", day(time.March, 3), day(time.March, 17)},
+	}
+
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q) returned error: %v", tt.spec, err)
+		}
+		window := schedule.Window(now)
+		if !window.Start.Equal(tt.start) || !window.End.Equal(tt.end) {
+			t.Errorf("%s: window = [%v, %v), want [%v, %v)", tt.spec, window.Start, window.End, tt.start, tt.end)
+		}
+		if !window.Contains(now) {
+			t.Errorf("%s: window does not contain now", tt.spec)
+		}
+	}
+}
+
+func TestWeeklyResetSchedule(t *testing.T) {
+	tracker, err := NewBudgetTracker(BudgetConfig{MonthlyLimitUSD: 5.00, HardStop: true, ResetSchedule: "weekly"})
+	if err != nil {
+		t.Fatalf("NewBudgetTracker returned error: %v", err)
+	}
+
+	if err := tracker.RecordKeyUsage("openai-main", "openai", 6.00); err == nil {
+		t.Error("Expected the weekly limit to be enforced")
+	} else if !strings.HasPrefix(err.Error(), "weekly budget limit exceeded") {
+		t.Errorf("Expected a weekly limit error, got: %v", err)
+	}
+
+	status := tracker.Status()
+	if status.Schedule != "weekly:mon" || status.PeriodSpentUSD != 6.00 || status.PeriodLimitUSD != 5.00 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+	if status.Window.Start.Weekday() != time.Monday || status.Window.End.Sub(status.Window.Start) < 167*time.Hour {
+		t.Errorf("Expected a Monday-to-Monday window, got %+v", status.Window)
+	}
+
+	if _, err := NewBudgetTracker(BudgetConfig{ResetSchedule: "fortnightly"}); err == nil {
+		t.Error("Expected an invalid reset schedule to be rejected")
+	}
+}
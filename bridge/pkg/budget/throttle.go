@@ -0,0 +1,102 @@
+package budget
+
+import (
+	"fmt"
+	"time"
+)
+
+// Mode is how the tracker enforces a limit once it is reached
+type Mode string
+
+const (
+	// ModeAlert only reports a reached limit
+	ModeAlert Mode = "alert"
+	// ModeHardStop rejects new sessions and usage past the limit
+	ModeHardStop Mode = "hard_stop"
+	// ModeThrottle keeps serving requests past the limit, but delays them
+	// and switches them to BudgetConfig.ThrottleModel when one is set
+	ModeThrottle Mode = "throttle"
+)
+
+const (
+	// defaultThrottleDelay is the delay at the limit when ThrottleDelay is unset
+	defaultThrottleDelay = 5 * time.Second
+	// maxThrottleDelay caps the delay however far spend is over the limit
+	maxThrottleDelay = time.Minute
+)
+
+// ParseMode validates a configured enforcement mode. An empty mode keeps the
+// behavior of the hard_stop setting.
+func ParseMode(mode string, hardStop bool) (Mode, error) {
+	switch Mode(mode) {
+	case "":
+		if hardStop {
+			return ModeHardStop, nil
+		}
+		return ModeAlert, nil
+	case ModeAlert, ModeHardStop, ModeThrottle:
+		return Mode(mode), nil
+	default:
+		return "", fmt.Errorf("unknown budget mode %q (want alert, hard_stop or throttle)", mode)
+	}
+}
+
+// EnforcementMode returns the configured mode, falling back to HardStop when
+// Mode is empty or unknown
+func (c BudgetConfig) EnforcementMode() Mode {
+	mode, err := ParseMode(c.Mode, c.HardStop)
+	if err != nil {
+		mode, _ = ParseMode("", c.HardStop)
+	}
+	return mode
+}
+
+// Throttle describes how requests are slowed down while spend is over a
+// limit in throttle mode
+type Throttle struct {
+	Active bool
+	Delay  time.Duration
+	Model  string // Cheaper model to use instead of the requested one; empty keeps it
+}
+
+// Throttle reports whether requests should currently be throttled. The delay
+// starts at ThrottleDelay when a limit is reached and grows in proportion to
+// the overspend, up to one minute.
+func (b *BudgetTracker) Throttle() Throttle {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	return b.throttleAt(time.Now())
+}
+
+// throttleAt computes the throttle for the windows containing now. Callers
+// must hold the lock.
+func (b *BudgetTracker) throttleAt(now time.Time) Throttle {
+	if b.config.EnforcementMode() != ModeThrottle {
+		return Throttle{}
+	}
+
+	ratio := 0.0
+	if limit := b.config.DailyLimitUSD; limit > 0 {
+		ratio = b.dailyUsage[now.Format("2006-01-02")] / limit
+	}
+	if limit := b.config.MonthlyLimitUSD; limit > 0 {
+		if r := b.monthlyUsage[b.schedule.key(now)] / limit; r > ratio {
+			ratio = r
+		}
+	}
+	if ratio < 1 {
+		return Throttle{}
+	}
+
+	base := b.config.ThrottleDelay
+	if base <= 0 {
+		base = defaultThrottleDelay
+	}
+	delay := time.Duration(float64(base) * ratio)
+	if delay > maxThrottleDelay {
+		delay = maxThrottleDelay
+	}
+
+	return Throttle{Active: true, Delay: delay, Model: b.config.ThrottleModel}
+}
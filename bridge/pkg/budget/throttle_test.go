@@ -0,0 +1,78 @@
+package budget
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		mode     string
+		hardStop bool
+		want     Mode
+		wantErr  bool
+	}{
+		{"", true, ModeHardStop, false},
+		{"", false, ModeAlert, false},
+		{"throttle", true, ModeThrottle, false},
+		{"alert", true, ModeAlert, false},
+		{"slow", false, "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMode(tt.mode, tt.hardStop)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMode(%q, %v) = %q, %v; want %q", tt.mode, tt.hardStop, got, err, tt.want)
+		}
+	}
+
+	if _, err := NewBudgetTracker(BudgetConfig{Mode: "slow"}); err == nil {
+		t.Error("NewBudgetTracker accepted an unknown mode")
+	}
+}
+
+func TestThrottleMode(t *testing.T) {
+	tracker, err := NewBudgetTracker(BudgetConfig{
+		DailyLimitUSD: 10,
+		HardStop:      true,
+		Mode:          "throttle",
+		ThrottleModel: "claude-3-haiku",
+	})
+	if err != nil {
+		t.Fatalf("NewBudgetTracker returned error: %v", err)
+	}
+
+	if th := tracker.Throttle(); th.Active {
+		t.Errorf("throttle active below the limit: %+v", th)
+	}
+
+	// Usage past the limit is accepted, not rejected as with hard stop
+	if err := tracker.RecordKeyUsage("k", "openai", 12); err != nil {
+		t.Errorf("RecordKeyUsage past the limit returned error: %v", err)
+	}
+	if err := tracker.CanStartSession(); err != nil {
+		t.Errorf("CanStartSession past the limit returned error: %v", err)
+	}
+	if state := tracker.GetWorkflowState(); state != WorkflowThrottled || state.IsPaused() {
+		t.Errorf("GetWorkflowState() = %v, want throttled", state)
+	}
+
+	th := tracker.Throttle()
+	if !th.Active || th.Delay != 6*time.Second || th.Model != "claude-3-haiku" {
+		t.Errorf("Throttle() = %+v, want 6s (default 5s at 120%%) on claude-3-haiku", th)
+	}
+
+	// The delay is capped however far over the limit spend goes
+	tracker.RecordKeyUsage("k", "openai", 1000)
+	if th := tracker.Throttle(); th.Delay != maxThrottleDelay {
+		t.Errorf("Throttle().Delay = %v, want %v", th.Delay, maxThrottleDelay)
+	}
+
+	// Other modes never throttle
+	tracker.UpdateLimits(BudgetConfig{DailyLimitUSD: 10, HardStop: true})
+	if th := tracker.Throttle(); th.Active {
+		t.Errorf("throttle active in hard stop mode: %+v", th)
+	}
+	if state := tracker.GetWorkflowState(); state != WorkflowPausedInsufficientFunds {
+		t.Errorf("GetWorkflowState() = %v, want paused_insufficient_funds", state)
+	}
+}
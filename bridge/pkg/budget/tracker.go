@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	errsys "github.com/armorclaw/bridge/pkg/errors"
 	"github.com/armorclaw/bridge/pkg/notification"
 )
 
@@ -35,6 +36,9 @@ const (
 	WorkflowCompleted
 	// WorkflowFailed means terminated due to error
 	WorkflowFailed
+	// WorkflowThrottled means a limit is reached and requests are slowed
+	// down instead of paused (throttle mode)
+	WorkflowThrottled
 )
 
 // String returns the string representation of the workflow state
@@ -50,6 +54,8 @@ func (s WorkflowState) String() string {
 		return "completed"
 	case WorkflowFailed:
 		return "failed"
+	case WorkflowThrottled:
+		return "throttled"
 	default:
 		return "unknown"
 	}
@@ -69,6 +75,7 @@ func (s WorkflowState) CanResume() bool {
 // UsageRecord tracks token usage for a session
 type UsageRecord struct {
 	SessionID    string    `json:"session_id"`
+	KeyID        string    `json:"key_id,omitempty"` // Keystore credential that paid for the usage
 	Provider     string    `json:"provider"`
 	Model        string    `json:"model"`
 	InputTokens  int       `json:"input_tokens"`
@@ -84,19 +91,34 @@ type BudgetConfig struct {
 	AlertThreshold  float64 `toml:"alert_threshold" env:"ARMORCLAW_ALERT_THRESHOLD"` // % of limit
 	HardStop        bool    `toml:"hard_stop" env:"ARMORCLAW_HARD_STOP"`
 	ProviderCosts   map[string]float64 `toml:"provider_costs"`
+	// ResetSchedule sets the window MonthlyLimitUSD applies to; see
+	// ParseSchedule. Empty means calendar months.
+	ResetSchedule string `toml:"reset_schedule" env:"ARMORCLAW_BUDGET_RESET_SCHEDULE"`
+	// Mode selects what happens once a limit is reached; see ParseMode.
+	// Empty follows HardStop.
+	Mode string `toml:"mode" env:"ARMORCLAW_BUDGET_MODE"`
+	// ThrottleDelay is the delay applied per request at the limit in
+	// throttle mode (0 = 5s)
+	ThrottleDelay time.Duration `toml:"throttle_delay" env:"ARMORCLAW_BUDGET_THROTTLE_DELAY"`
+	// ThrottleModel replaces the requested model in throttle mode (empty =
+	// keep the requested model)
+	ThrottleModel string `toml:"throttle_model" env:"ARMORCLAW_BUDGET_THROTTLE_MODEL"`
 }
 
 // BudgetTracker monitors and enforces token budgets
 // Thread-safe with Write-Ahead Log persistence for crash recovery
 type BudgetTracker struct {
 	config        BudgetConfig
+	schedule      Schedule
 	usageHistory  []UsageRecord
 	dailyUsage    map[string]float64
-	monthlyUsage  map[string]float64
+	monthlyUsage  map[string]float64 // Keyed by reset window, see Schedule.key
 	sessionUsage  map[string]float64
 	mutex         sync.RWMutex
 	costs         map[string]float64
 	notifier      *notification.Notifier
+	errorSystem   *errsys.System
+	alerted       map[string]string // limit/code -> window start already reported
 
 	// Persistence layer (WAL-based)
 	persistence   *persistentStore
@@ -122,15 +144,12 @@ func WithNotifier(notifier *notification.Notifier) BudgetTrackerOption {
 
 // NewBudgetTracker creates a new budget tracker with optional persistence
 func NewBudgetTracker(config BudgetConfig, opts ...BudgetTrackerOption) (*BudgetTracker, error) {
-	// Merge custom provider costs with defaults
-	costs := make(map[string]float64)
-	for k, v := range TokenCosts {
-		costs[k] = v
+	schedule, err := ParseSchedule(config.ResetSchedule)
+	if err != nil {
+		return nil, err
 	}
-	if config.ProviderCosts != nil {
-		for k, v := range config.ProviderCosts {
-			costs[k] = v
-		}
+	if _, err := ParseMode(config.Mode, config.HardStop); err != nil {
+		return nil, err
 	}
 
 	// Default persistence config (disabled for backward compatibility)
@@ -141,11 +160,13 @@ func NewBudgetTracker(config BudgetConfig, opts ...BudgetTrackerOption) (*Budget
 
 	b := &BudgetTracker{
 		config:        config,
+		schedule:      schedule,
 		usageHistory:  make([]UsageRecord, 0),
 		dailyUsage:    make(map[string]float64),
 		monthlyUsage:  make(map[string]float64),
 		sessionUsage:  make(map[string]float64),
-		costs:         costs,
+		alerted:       make(map[string]string),
+		costs:         mergeCosts(config.ProviderCosts),
 		persistConfig: persistConfig,
 	}
 
@@ -171,6 +192,18 @@ func NewBudgetTracker(config BudgetConfig, opts ...BudgetTrackerOption) (*Budget
 	return b, nil
 }
 
+// mergeCosts overlays custom provider costs on the default token costs
+func mergeCosts(providerCosts map[string]float64) map[string]float64 {
+	costs := make(map[string]float64, len(TokenCosts)+len(providerCosts))
+	for k, v := range TokenCosts {
+		costs[k] = v
+	}
+	for k, v := range providerCosts {
+		costs[k] = v
+	}
+	return costs
+}
+
 // recoverFromPersistence replays WAL and restores state
 func (b *BudgetTracker) recoverFromPersistence() error {
 	if b.persistence == nil {
@@ -180,7 +213,10 @@ func (b *BudgetTracker) recoverFromPersistence() error {
 	// Load snapshot first
 	state, err := b.persistence.loadSnapshot()
 	if err == nil && state != nil {
+		// The reset schedule always follows the current configuration
+		schedule := b.config.ResetSchedule
 		b.config = state.Config
+		b.config.ResetSchedule = schedule
 		b.usageHistory = state.UsageHistory
 		b.dailyUsage = state.DailyUsage
 		b.monthlyUsage = state.MonthlyUsage
@@ -198,16 +234,25 @@ func (b *BudgetTracker) recoverFromPersistence() error {
 		b.applyRecord(record)
 	}
 
+	// Re-key period totals in case the reset schedule changed since the
+	// snapshot was taken
+	b.monthlyUsage = make(map[string]float64)
+	for _, record := range b.usageHistory {
+		b.monthlyUsage[b.schedule.key(record.Timestamp)] += record.CostUSD
+	}
+
 	return nil
 }
 
 // applyRecord applies a usage record to the in-memory state (without persistence)
 func (b *BudgetTracker) applyRecord(record UsageRecord) {
 	date := record.Timestamp.Format("2006-01-02")
-	month := record.Timestamp.Format("2006-01")
+	month := b.schedule.key(record.Timestamp)
 	b.dailyUsage[date] += record.CostUSD
 	b.monthlyUsage[month] += record.CostUSD
-	b.sessionUsage[record.SessionID] += record.CostUSD
+	if record.SessionID != "" {
+		b.sessionUsage[record.SessionID] += record.CostUSD
+	}
 	b.usageHistory = append(b.usageHistory, record)
 }
 
@@ -241,7 +286,7 @@ func (b *BudgetTracker) RecordUsage(record UsageRecord) error {
 
 	// Update tracking maps
 	date := record.Timestamp.Format("2006-01-02")
-	month := record.Timestamp.Format("2006-01")
+	month := b.schedule.key(record.Timestamp)
 	b.dailyUsage[date] += record.CostUSD
 	b.monthlyUsage[month] += record.CostUSD
 	b.sessionUsage[record.SessionID] += record.CostUSD
@@ -250,17 +295,52 @@ func (b *BudgetTracker) RecordUsage(record UsageRecord) error {
 	return b.checkLimits(&record)
 }
 
+// RecordKeyUsage attributes an already priced spend to a key and provider.
+// Use it when the caller knows the cost; RecordUsage prices by token count.
+func (b *BudgetTracker) RecordKeyUsage(keyID, provider string, costUSD float64) error {
+	if costUSD < 0 {
+		return fmt.Errorf("cost cannot be negative: %f", costUSD)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	record := UsageRecord{
+		KeyID:     keyID,
+		Provider:  provider,
+		CostUSD:   costUSD,
+		Timestamp: time.Now(),
+	}
+
+	if b.persistence != nil {
+		if err := b.persistence.AppendUsage(record); err != nil {
+			return fmt.Errorf("failed to persist usage record: %w", err)
+		}
+	}
+	b.applyRecord(record)
+
+	return b.checkLimits(&record)
+}
+
 // checkLimits verifies if usage is within budget limits
 func (b *BudgetTracker) checkLimits(record *UsageRecord) error {
 	date := record.Timestamp.Format("2006-01-02")
-	month := record.Timestamp.Format("2006-01")
+	month := b.schedule.key(record.Timestamp)
 
 	dailyCost := b.dailyUsage[date]
 	monthlyCost := b.monthlyUsage[month]
 
+	// Report threshold crossings through the error system
+	b.reportThreshold("daily", "daily", dailyCost, b.config.DailyLimitUSD,
+		Schedule{Kind: ScheduleDaily}.Window(record.Timestamp))
+	b.reportThreshold("period", b.schedule.label(), monthlyCost, b.config.MonthlyLimitUSD,
+		b.schedule.Window(record.Timestamp))
+
+	hardStop := b.config.EnforcementMode() == ModeHardStop
+
 	// Check daily limit
 	if b.config.DailyLimitUSD > 0 && dailyCost >= b.config.DailyLimitUSD {
-		if b.config.HardStop {
+		if hardStop {
 			return fmt.Errorf("daily budget limit exceeded: $%.2f / $%.2f",
 				dailyCost, b.config.DailyLimitUSD)
 		}
@@ -270,9 +350,9 @@ func (b *BudgetTracker) checkLimits(record *UsageRecord) error {
 
 	// Check monthly limit
 	if b.config.MonthlyLimitUSD > 0 && monthlyCost >= b.config.MonthlyLimitUSD {
-		if b.config.HardStop {
-			return fmt.Errorf("monthly budget limit exceeded: $%.2f / $%.2f",
-				monthlyCost, b.config.MonthlyLimitUSD)
+		if hardStop {
+			return fmt.Errorf("%s budget limit exceeded: $%.2f / $%.2f",
+				b.schedule.label(), monthlyCost, b.config.MonthlyLimitUSD)
 		}
 		b.sendAlert("monthly_limit_exceeded", monthlyCost, b.config.MonthlyLimitUSD)
 	}
@@ -304,21 +384,21 @@ func (b *BudgetTracker) CanStartSession() error {
 
 	now := time.Now()
 	date := now.Format("2006-01-02")
-	month := now.Format("2006-01")
+	month := b.schedule.key(now)
 
 	dailyCost := b.dailyUsage[date]
 	monthlyCost := b.monthlyUsage[month]
 
 	// Check if we've already hit the limits
-	if b.config.HardStop {
+	if b.config.EnforcementMode() == ModeHardStop {
 		if b.config.DailyLimitUSD > 0 && dailyCost >= b.config.DailyLimitUSD {
 			return fmt.Errorf("daily budget limit reached: $%.2f / $%.2f",
 				dailyCost, b.config.DailyLimitUSD)
 		}
 
 		if b.config.MonthlyLimitUSD > 0 && monthlyCost >= b.config.MonthlyLimitUSD {
-			return fmt.Errorf("monthly budget limit reached: $%.2f / $%.2f",
-				monthlyCost, b.config.MonthlyLimitUSD)
+			return fmt.Errorf("%s budget limit reached: $%.2f / $%.2f",
+				b.schedule.label(), monthlyCost, b.config.MonthlyLimitUSD)
 		}
 	}
 
@@ -333,21 +413,23 @@ func (b *BudgetTracker) GetWorkflowState() WorkflowState {
 
 	now := time.Now()
 	date := now.Format("2006-01-02")
-	month := now.Format("2006-01")
+	month := b.schedule.key(now)
 
 	dailyCost := b.dailyUsage[date]
 	monthlyCost := b.monthlyUsage[month]
 
 	// Check if budget is exhausted
-	if b.config.DailyLimitUSD > 0 && dailyCost >= b.config.DailyLimitUSD {
-		return WorkflowPausedInsufficientFunds
+	exhausted := (b.config.DailyLimitUSD > 0 && dailyCost >= b.config.DailyLimitUSD) ||
+		(b.config.MonthlyLimitUSD > 0 && monthlyCost >= b.config.MonthlyLimitUSD)
+	if !exhausted {
+		return WorkflowRunning
 	}
 
-	if b.config.MonthlyLimitUSD > 0 && monthlyCost >= b.config.MonthlyLimitUSD {
-		return WorkflowPausedInsufficientFunds
+	// Throttled workflows keep running, only slower
+	if b.config.EnforcementMode() == ModeThrottle {
+		return WorkflowThrottled
 	}
-
-	return WorkflowRunning
+	return WorkflowPausedInsufficientFunds
 }
 
 // CanResumeWorkflow checks if a paused workflow can be resumed
@@ -357,7 +439,7 @@ func (b *BudgetTracker) CanResumeWorkflow() error {
 	if state == WorkflowPausedInsufficientFunds {
 		now := time.Now()
 		date := now.Format("2006-01-02")
-		month := now.Format("2006-01")
+		month := b.schedule.key(now)
 
 		b.mutex.RLock()
 		dailyCost := b.dailyUsage[date]
@@ -371,7 +453,7 @@ func (b *BudgetTracker) CanResumeWorkflow() error {
 		if b.config.MonthlyLimitUSD > 0 && monthlyCost >= b.config.MonthlyLimitUSD {
 			limit = b.config.MonthlyLimitUSD
 			current = monthlyCost
-			limitType = "monthly"
+			limitType = b.schedule.label()
 		}
 
 		return fmt.Errorf("cannot resume: %s budget exhausted ($%.2f of $%.2f)",
@@ -390,12 +472,12 @@ func (b *BudgetTracker) GetDailyUsage() float64 {
 	return b.dailyUsage[date]
 }
 
-// GetMonthlyUsage returns current monthly usage in USD
+// GetMonthlyUsage returns usage in the current reset window in USD
 func (b *BudgetTracker) GetMonthlyUsage() float64 {
 	b.mutex.RLock()
 	defer b.mutex.RUnlock()
 
-	month := time.Now().Format("2006-01")
+	month := b.schedule.key(time.Now())
 	return b.monthlyUsage[month]
 }
 
@@ -407,16 +489,71 @@ func (b *BudgetTracker) GetSessionUsage(sessionID string) float64 {
 	return b.sessionUsage[sessionID]
 }
 
+// CurrentWindow returns the reset window MonthlyLimitUSD currently applies to
+func (b *BudgetTracker) CurrentWindow() Window {
+	return b.schedule.Window(time.Now())
+}
+
+// Status is a point-in-time view of spend against the configured limits
+type Status struct {
+	Schedule       string
+	Window         Window
+	PeriodSpentUSD float64
+	PeriodLimitUSD float64
+	DailySpentUSD  float64
+	DailyLimitUSD  float64
+	Mode           Mode
+	Throttle       Throttle
+}
+
+// Status reports spend in the current reset window and day
+func (b *BudgetTracker) Status() Status {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	now := time.Now()
+	return Status{
+		Schedule:       b.schedule.String(),
+		Window:         b.schedule.Window(now),
+		PeriodSpentUSD: b.monthlyUsage[b.schedule.key(now)],
+		PeriodLimitUSD: b.config.MonthlyLimitUSD,
+		DailySpentUSD:  b.dailyUsage[now.Format("2006-01-02")],
+		DailyLimitUSD:  b.config.DailyLimitUSD,
+		Mode:           b.config.EnforcementMode(),
+		Throttle:       b.throttleAt(now),
+	}
+}
+
 // GetDailyLimit returns the configured daily limit
 func (b *BudgetTracker) GetDailyLimit() float64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
 	return b.config.DailyLimitUSD
 }
 
 // GetMonthlyLimit returns the configured monthly limit
 func (b *BudgetTracker) GetMonthlyLimit() float64 {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
 	return b.config.MonthlyLimitUSD
 }
 
+// UpdateLimits applies new limits, alert threshold, enforcement mode and
+// provider costs to a running tracker, keeping recorded usage. The reset
+// schedule is not changed since usage is already keyed by its windows.
+// Threshold alerts are re-armed so crossings of the new limits are reported.
+func (b *BudgetTracker) UpdateLimits(config BudgetConfig) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	config.ResetSchedule = b.config.ResetSchedule
+	b.config = config
+	b.costs = mergeCosts(config.ProviderCosts)
+	b.alerted = make(map[string]string)
+}
+
 // sendAlert sends a budget alert via the notification system
 func (b *BudgetTracker) sendAlert(alertType string, current, limit float64) {
 	// Try to send via notification system first
@@ -465,6 +602,7 @@ func (b *BudgetTracker) Reset() error {
 	b.dailyUsage = make(map[string]float64)
 	b.monthlyUsage = make(map[string]float64)
 	b.sessionUsage = make(map[string]float64)
+	b.alerted = make(map[string]string)
 
 	return nil
 }
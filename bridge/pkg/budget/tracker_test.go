@@ -561,3 +561,105 @@ func TestConcurrentUsageRecording(t *testing.T) {
 		t.Error("Should have recorded usage from concurrent operations")
 	}
 }
+
+func TestRecordKeyUsageBreakdown(t *testing.T) {
+	tracker, err := NewBudgetTracker(BudgetConfig{DailyLimitUSD: 100.00, MonthlyLimitUSD: 1000.00})
+	if err != nil {
+		t.Fatalf("NewBudgetTracker returned error: %v", err)
+	}
+
+	start := time.Now()
+	for _, usage := range []struct {
+		keyID    string
+		provider string
+		cost     float64
+	}{
+		{"openai-main", "openai", 1.50},
+		{"openai-main", "openai", 0.50},
+		{"openai-agent", "openai", 3.00},
+		{"anthropic-main", "anthropic", 1.00},
+	} {
+		if err := tracker.RecordKeyUsage(usage.keyID, usage.provider, usage.cost); err != nil {
+			t.Fatalf("RecordKeyUsage returned error: %v", err)
+		}
+	}
+	// Token-priced usage without a key is still counted
+	if err := tracker.RecordUsage(UsageRecord{SessionID: "s1", Model: "gpt-4", InputTokens: 100000}); err != nil {
+		t.Fatalf("RecordUsage returned error: %v", err)
+	}
+
+	if err := tracker.RecordKeyUsage("openai-main", "openai", -1); err == nil {
+		t.Error("Expected negative cost to be rejected")
+	}
+
+	breakdown := tracker.Breakdown(start)
+	if breakdown.TotalUSD != 9.00 {
+		t.Errorf("Expected total 9.00, got %.2f", breakdown.TotalUSD)
+	}
+	if breakdown.UnattributedUSD != 3.00 {
+		t.Errorf("Expected 3.00 unattributed, got %.2f", breakdown.UnattributedUSD)
+	}
+
+	if len(breakdown.ByProvider) != 3 || breakdown.ByProvider[0].Provider != "openai" || breakdown.ByProvider[0].CostUSD != 5.00 {
+		t.Errorf("Expected openai to lead providers with 5.00, got %+v", breakdown.ByProvider)
+	}
+
+	if len(breakdown.ByKey) != 3 {
+		t.Fatalf("Expected 3 keys, got %+v", breakdown.ByKey)
+	}
+	top := breakdown.ByKey[0]
+	if top.KeyID != "openai-agent" || top.CostUSD != 3.00 || top.Records != 1 {
+		t.Errorf("Expected openai-agent to lead keys, got %+v", top)
+	}
+	if main := breakdown.ByKey[1]; main.KeyID != "openai-main" || main.CostUSD != 2.00 || main.Records != 2 {
+		t.Errorf("Expected openai-main with 2.00 over 2 records, got %+v", main)
+	}
+
+	if later := tracker.Breakdown(time.Now().Add(time.Minute)); later.TotalUSD != 0 || len(later.ByKey) != 0 {
+		t.Errorf("Expected no spend after now, got %+v", later)
+	}
+}
+
+func TestUpdateLimits(t *testing.T) {
+	tracker, err := NewBudgetTracker(BudgetConfig{
+		DailyLimitUSD:   0.01,
+		MonthlyLimitUSD: 100.00,
+		HardStop:        true,
+		ResetSchedule:   "weekly",
+	})
+	if err != nil {
+		t.Fatalf("NewBudgetTracker returned error: %v", err)
+	}
+
+	record := UsageRecord{
+		SessionID:    "test-session",
+		Provider:     "openai",
+		Model:        "gpt-3.5-turbo",
+		InputTokens:  14000,
+		OutputTokens: 10000,
+	}
+	if err := tracker.RecordUsage(record); err == nil {
+		t.Fatal("record over the daily limit should fail")
+	}
+
+	tracker.UpdateLimits(BudgetConfig{
+		DailyLimitUSD:   5.00,
+		MonthlyLimitUSD: 50.00,
+		HardStop:        true,
+		ProviderCosts:   map[string]float64{"gpt-3.5-turbo": 1.00},
+		ResetSchedule:   "daily",
+	})
+
+	if tracker.GetDailyLimit() != 5.00 || tracker.GetMonthlyLimit() != 50.00 {
+		t.Errorf("limits = %.2f/%.2f, want 5.00/50.00", tracker.GetDailyLimit(), tracker.GetMonthlyLimit())
+	}
+	if tracker.GetCost("gpt-3.5-turbo") != 1.00 {
+		t.Errorf("cost = %.2f, want the reloaded 1.00", tracker.GetCost("gpt-3.5-turbo"))
+	}
+	if got := tracker.Status().Schedule; got != "weekly:mon" {
+		t.Errorf("schedule = %q, want it unchanged", got)
+	}
+	if err := tracker.RecordUsage(record); err != nil {
+		t.Errorf("record under the raised limit should succeed: %v", err)
+	}
+}
@@ -5,14 +5,21 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/armorclaw/bridge/internal/adapter"
 	"github.com/armorclaw/bridge/pkg/budget"
+	"github.com/armorclaw/bridge/pkg/docker"
+	"github.com/armorclaw/bridge/pkg/health"
 	"github.com/armorclaw/bridge/pkg/keystore"
 	"github.com/armorclaw/bridge/pkg/rpc"
+	"github.com/armorclaw/bridge/pkg/turn"
+	"github.com/armorclaw/bridge/pkg/websocket"
 )
 
 // errors.Config alias for type compatibility (imported in main.go to avoid circular dependency)
@@ -69,12 +76,32 @@ type BudgetConfig struct {
 	// HardStop prevents new sessions when limits are exceeded
 	HardStop bool `toml:"hard_stop" env:"ARMORCLAW_HARD_STOP"`
 
+	// Mode selects what happens once a limit is reached: "alert", "hard_stop"
+	// or "throttle". Empty follows hard_stop.
+	Mode string `toml:"mode" env:"ARMORCLAW_BUDGET_MODE"`
+
+	// ThrottleDelay is the per-request delay at the limit in throttle mode
+	// (e.g. "5s"); it grows with the overspend up to one minute
+	ThrottleDelay string `toml:"throttle_delay" env:"ARMORCLAW_BUDGET_THROTTLE_DELAY"`
+
+	// ThrottleModel is the cheaper model requests switch to in throttle mode
+	// (empty = keep the requested model)
+	ThrottleModel string `toml:"throttle_model" env:"ARMORCLAW_BUDGET_THROTTLE_MODEL"`
+
 	// ProviderCosts allows custom token costs per model
 	ProviderCosts map[string]float64 `toml:"provider_costs"`
+
+	// ResetSchedule sets when the monthly_limit_usd window resets: "monthly"
+	// (default), "monthly:15", "weekly", "weekly:sun", "daily" or "every:14d@2025-01-06"
+	ResetSchedule string `toml:"reset_schedule" env:"ARMORCLAW_BUDGET_RESET_SCHEDULE"`
 }
 
 // Config holds all bridge configuration
 type Config struct {
+	// Version is the config schema version, stamped by "config migrate".
+	// Files without it predate versioning (version 0).
+	Version int `toml:"version"`
+
 	// Server configuration
 	Server ServerConfig `toml:"server"`
 
@@ -125,6 +152,21 @@ type Config struct {
 
 	// Java sidecar configuration (legacy .doc/.ppt extraction)
 	SidecarJava SidecarJavaConfig `toml:"sidecar_java"`
+
+	// Agent container resource limits
+	Container ContainerConfig `toml:"container"`
+
+	// Prometheus metrics endpoint
+	Metrics MetricsConfig `toml:"metrics"`
+
+	// Mobile push notifications (FCM/APNs)
+	Push PushConfig `toml:"push"`
+
+	// External adapter plugins
+	Plugins PluginsConfig `toml:"plugins"`
+
+	// Premium license validation
+	License LicenseConfig `toml:"license"`
 }
 
 // ServerConfig holds server-specific configuration
@@ -167,6 +209,10 @@ type KeystoreConfig struct {
 	// MasterKey is an optional master key (if not provided, derived from hardware)
 	MasterKey string `toml:"master_key" env:"ARMORCLAW_MASTER_KEY"`
 
+	// ExpiryGrace tolerates brief clock skew when enforcing credential
+	// expires_at (e.g. "2m"). Empty means expiry is enforced strictly.
+	ExpiryGrace string `toml:"expiry_grace" env:"ARMORCLAW_KEYSTORE_EXPIRY_GRACE"`
+
 	// Provider configuration
 	Providers []ProviderConfig `toml:"providers"`
 }
@@ -201,6 +247,10 @@ type MatrixConfig struct {
 	// SyncInterval is the interval between syncs in seconds
 	SyncInterval int `toml:"sync_interval" env:"ARMORCLAW_MATRIX_SYNC_INTERVAL"`
 
+	// TokenRefreshMargin is how long before expiry the access token is
+	// refreshed (e.g. "5m"). Empty uses the adapter default.
+	TokenRefreshMargin string `toml:"token_refresh_margin" env:"ARMORCLAW_MATRIX_TOKEN_REFRESH_MARGIN"`
+
 	// AutoRooms are rooms to automatically join on login
 	AutoRooms []string `toml:"auto_rooms"`
 
@@ -269,6 +319,10 @@ type WebRTCConfig struct {
 	// TURNServerURL is the TURN server URL
 	TURNServerURL string `toml:"turn_server_url" env:"ARMORCLAW_TURN_SERVER_URL"`
 
+	// TURNServerURLs lists additional TURN servers in priority order.
+	// TURNServerURL, if set, comes first.
+	TURNServerURLs []string `toml:"turn_server_urls" env:"ARMORCLAW_WEBRTC_TURN_URLS"`
+
 	// ICEServers is a list of ICE servers (STUN/TURN)
 	ICEServers []ICEServerConfig `toml:"ice_servers"`
 
@@ -281,6 +335,12 @@ type WebRTCConfig struct {
 	SignalingPath    string `toml:"signaling_path" env:"ARMORCLAW_SIGNALING_PATH"`
 	SignalingTLSCert string `toml:"signaling_tls_cert" env:"ARMORCLAW_SIGNALING_TLS_CERT"`
 	SignalingTLSKey  string `toml:"signaling_tls_key" env:"ARMORCLAW_SIGNALING_TLS_KEY"`
+
+	// SignalingPingInterval is how often signaling clients are pinged, and
+	// SignalingPongTimeout how long a ping may go unanswered before the
+	// client is disconnected (e.g., "30s")
+	SignalingPingInterval string `toml:"signaling_ping_interval" env:"ARMORCLAW_SIGNALING_PING_INTERVAL"`
+	SignalingPongTimeout  string `toml:"signaling_pong_timeout" env:"ARMORCLAW_SIGNALING_PONG_TIMEOUT"`
 }
 
 // ICEServerConfig represents an ICE server configuration
@@ -404,6 +464,10 @@ type NotificationsConfig struct {
 
 	// AlertThreshold is the percentage at which to send alerts (0.0-1.0)
 	AlertThreshold float64 `toml:"alert_threshold" env:"ARMORCLAW_ALERT_THRESHOLD"`
+
+	// Templates overrides alert messages by alert type or category
+	// (budget, security, container, system) using Go text/template syntax
+	Templates map[string]string `toml:"templates"`
 }
 
 // BrowserConfig holds browser service configuration
@@ -498,6 +562,16 @@ type EventBusConfig struct {
 	// InactivityTimeout is the timeout for inactive subscribers
 	InactivityTimeout string `toml:"inactivity_timeout" env:"ARMORCLAW_EVENTBUS_INACTIVITY_TIMEOUT"`
 
+	// PingInterval is how often WebSocket clients are pinged, and
+	// PongTimeout how long a ping may go unanswered before the client is
+	// disconnected (e.g., "30s")
+	PingInterval string `toml:"ping_interval" env:"ARMORCLAW_EVENTBUS_PING_INTERVAL"`
+	PongTimeout  string `toml:"pong_timeout" env:"ARMORCLAW_EVENTBUS_PONG_TIMEOUT"`
+
+	// ReplayBacklog is the number of recent events kept for clients that
+	// reconnect with last_seq (0 = default of 1000, negative = disabled)
+	ReplayBacklog int `toml:"replay_backlog" env:"ARMORCLAW_EVENTBUS_REPLAY_BACKLOG"`
+
 	// EnableDurableLog enables the append-only event log
 	EnableDurableLog bool `toml:"enable_durable_log" env:"ARMORCLAW_EVENTBUS_DURABLE_LOG_ENABLED"`
 
@@ -546,6 +620,15 @@ type DiscoveryConfig struct {
 
 	// Hardware describes the hardware platform (optional)
 	Hardware string `toml:"hardware" env:"ARMORCLAW_DISCOVERY_HARDWARE"`
+
+	// AnnounceInterval is how often the service is re-announced and the
+	// advertised IPs rechecked (default: 60s, "0s" disables)
+	AnnounceInterval string `toml:"announce_interval" env:"ARMORCLAW_DISCOVERY_ANNOUNCE_INTERVAL"`
+
+	// AdvertiseIP pins the IP address advertised to clients. If empty, the
+	// address of the default route is preferred and container bridge
+	// addresses (docker0, br-*, ...) are skipped.
+	AdvertiseIP string `toml:"advertise_ip" env:"ARMORCLAW_DISCOVERY_ADVERTISE_IP"`
 }
 
 // ComplianceConfig holds PII/PHI compliance settings
@@ -568,9 +651,27 @@ type ComplianceConfig struct {
 	// AuditEnabled logs all PII/PHI detections for compliance auditing
 	AuditEnabled bool `toml:"audit_enabled" env:"ARMORCLAW_COMPLIANCE_AUDIT"`
 
-	// AuditRetentionDays is how long to keep compliance audit logs
+	// AuditRetentionDays is how long to keep compliance audit logs and the
+	// bridge audit log. The audit-log license feature covers 30 days and
+	// audit-log-compliance 90; 0 keeps entries until the size cap.
 	AuditRetentionDays int `toml:"audit_retention_days" env:"ARMORCLAW_COMPLIANCE_AUDIT_DAYS"`
 
+	// PIIApprovalValidity is how long an approved PII access request lets
+	// profile.get read the approved sensitive fields (e.g., "15m")
+	PIIApprovalValidity string `toml:"pii_approval_validity" env:"ARMORCLAW_COMPLIANCE_PII_APPROVAL_VALIDITY"`
+
+	// HITLTimeout is how long a PII consent request waits for a decision
+	// before HITLTimeoutAction is applied (e.g., "60s")
+	HITLTimeout string `toml:"hitl_timeout" env:"ARMORCLAW_COMPLIANCE_HITL_TIMEOUT"`
+
+	// HITLTimeoutAction is applied to unanswered consent requests:
+	// expire, approve, reject, or escalate
+	HITLTimeoutAction string `toml:"hitl_timeout_action" env:"ARMORCLAW_COMPLIANCE_HITL_TIMEOUT_ACTION"`
+
+	// HITLTimeoutWarning is how long before the deadline the admin room is
+	// told about an unanswered consent request; "0s" disables the warning
+	HITLTimeoutWarning string `toml:"hitl_timeout_warning" env:"ARMORCLAW_COMPLIANCE_HITL_TIMEOUT_WARNING"`
+
 	// Tier is the compliance tier (basic, standard, full)
 	Tier string `toml:"tier" env:"ARMORCLAW_COMPLIANCE_TIER"`
 
@@ -792,6 +893,111 @@ type SidecarJavaConfig struct {
 	SocketPath string `toml:"socket_path" env:"ARMORCLAW_SIDECAR_JAVA_SOCKET_PATH" envDefault:"/run/armorclaw/sidecar-java/sidecar-java.sock"`
 }
 
+// ContainerConfig holds resource limits applied to agent containers that are
+// created without their own
+type ContainerConfig struct {
+	// MemoryMB is the memory limit in megabytes (0 = unlimited)
+	MemoryMB int64 `toml:"memory_mb" env:"ARMORCLAW_CONTAINER_MEMORY_MB"`
+
+	// CPUShares is the relative CPU weight; Docker's default is 1024 (0 = unset)
+	CPUShares int64 `toml:"cpu_shares" env:"ARMORCLAW_CONTAINER_CPU_SHARES"`
+
+	// RestartOnFailure restarts crashed agent containers before notifying the admin
+	RestartOnFailure bool `toml:"restart_on_failure" env:"ARMORCLAW_CONTAINER_RESTART_ON_FAILURE"`
+
+	// MaxRestarts is the number of restarts attempted before notifying the admin
+	MaxRestarts int `toml:"max_restarts"`
+
+	// RestartBackoff is the delay after the first restart, doubled for each
+	// further restart (e.g., "10s")
+	RestartBackoff string `toml:"restart_backoff"`
+}
+
+// MetricsConfig holds configuration for the Prometheus metrics endpoint
+type MetricsConfig struct {
+	// Enabled serves GET /metrics in Prometheus text format (default: false)
+	Enabled bool `toml:"enabled" env:"ARMORCLAW_METRICS_ENABLED"`
+
+	// ListenAddr is the host:port the metrics endpoint listens on. Keep it
+	// on loopback or a private network: it has no authentication.
+	ListenAddr string `toml:"listen_addr" env:"ARMORCLAW_METRICS_LISTEN_ADDR"`
+}
+
+// PushConfig holds configuration for push notification delivery to mobile
+// clients
+type PushConfig struct {
+	// Enabled turns on push delivery and the push.* RPC methods (default: false)
+	Enabled bool `toml:"enabled" env:"ARMORCLAW_PUSH_ENABLED"`
+
+	// FCMCredentialsFile is the Firebase service account JSON key used for
+	// the FCM HTTP v1 API
+	FCMCredentialsFile string `toml:"fcm_credentials_file" env:"ARMORCLAW_PUSH_FCM_CREDENTIALS_FILE"`
+
+	// FCMProjectID overrides the project ID read from the credentials file
+	FCMProjectID string `toml:"fcm_project_id" env:"ARMORCLAW_PUSH_FCM_PROJECT_ID"`
+
+	// APNSCertFile and APNSKeyFile are the PEM client certificate and key
+	// for APNs
+	APNSCertFile string `toml:"apns_cert_file" env:"ARMORCLAW_PUSH_APNS_CERT_FILE"`
+	APNSKeyFile  string `toml:"apns_key_file" env:"ARMORCLAW_PUSH_APNS_KEY_FILE"`
+
+	// APNSTopic is the iOS app bundle ID
+	APNSTopic string `toml:"apns_topic" env:"ARMORCLAW_PUSH_APNS_TOPIC"`
+
+	// APNSEnvironment is "production" or "sandbox" (default: production)
+	APNSEnvironment string `toml:"apns_environment" env:"ARMORCLAW_PUSH_APNS_ENVIRONMENT"`
+
+	// NotifyMessages pushes incoming messages in bridged rooms (default: true)
+	NotifyMessages bool `toml:"notify_messages" env:"ARMORCLAW_PUSH_NOTIFY_MESSAGES"`
+}
+
+// PluginsConfig holds configuration for external adapter plugins
+type PluginsConfig struct {
+	// Dir is the plugin directory; relative paths in ConfigFile are
+	// resolved against it (default: /var/lib/armorclaw/plugins)
+	Dir string `toml:"dir" env:"ARMORCLAW_PLUGINS_DIR"`
+
+	// ConfigFile is a JSON array of plugin configs (library_path,
+	// metadata_path, enabled, config, credentials, capabilities). Enabled
+	// plugins are loaded and started when the bridge starts.
+	ConfigFile string `toml:"config_file" env:"ARMORCLAW_PLUGINS_CONFIG_FILE"`
+}
+
+// LicenseConfig holds configuration for premium license validation
+type LicenseConfig struct {
+	// Key is the license key; without one the bridge runs unlicensed and
+	// no license client is created
+	Key string `toml:"key" env:"ARMORCLAW_LICENSE_KEY"`
+
+	// ServerURL is the license server (default: the production server)
+	ServerURL string `toml:"server_url" env:"ARMORCLAW_LICENSE_SERVER_URL"`
+
+	// CacheDir persists successful validations across restarts
+	// (default: /var/lib/armorclaw/license, empty = memory only)
+	CacheDir string `toml:"cache_dir" env:"ARMORCLAW_LICENSE_CACHE_DIR"`
+
+	// OfflineMode validates against OfflineToken instead of contacting the
+	// license server, for air-gapped deployments
+	OfflineMode bool `toml:"offline_mode" env:"ARMORCLAW_LICENSE_OFFLINE_MODE"`
+
+	// OfflineToken is the signed token issued by the license server
+	OfflineToken string `toml:"offline_token" env:"ARMORCLAW_LICENSE_OFFLINE_TOKEN"`
+
+	// PublicKey is the license server's base64 Ed25519 public key, used to
+	// verify OfflineToken
+	PublicKey string `toml:"public_key" env:"ARMORCLAW_LICENSE_PUBLIC_KEY"`
+}
+
+// FCMEnabled reports whether FCM credentials are configured
+func (p PushConfig) FCMEnabled() bool {
+	return p.FCMCredentialsFile != ""
+}
+
+// APNSEnabled reports whether an APNs certificate is configured
+func (p PushConfig) APNSEnabled() bool {
+	return p.APNSCertFile != ""
+}
+
 // VaultConfig holds configuration for the Rust Vault governance integration
 type VaultConfig struct {
 	// V6Microkernel enables the v6 microkernel architecture with vault governance,
@@ -833,9 +1039,16 @@ type ErrorSystemConfig struct {
 	// RateLimitWindow is the window for rate-limiting notifications (e.g., "5m")
 	RateLimitWindow string `toml:"rate_limit_window" env:"ARMORCLAW_ERRORS_RATE_LIMIT_WINDOW"`
 
+	// CodeWindows overrides the rate-limit window per exact code or code prefix
+	// (e.g., { "CTX" = "30m", "CTX-003" = "1h" })
+	CodeWindows map[string]string `toml:"code_windows"`
+
 	// RetentionPeriod is how long to keep error counts for sampling
 	RetentionPeriod string `toml:"retention_period" env:"ARMORCLAW_ERRORS_RETENTION_PERIOD"`
 
+	// DigestInterval batches non-critical notifications into a periodic digest (e.g., "15m", empty = disabled)
+	DigestInterval string `toml:"digest_interval" env:"ARMORCLAW_ERRORS_DIGEST_INTERVAL"`
+
 	// AdminMXID is the configured admin Matrix ID (highest priority)
 	AdminMXID string `toml:"admin_mxid" env:"ARMORCLAW_ERRORS_ADMIN_MXID"`
 
@@ -844,6 +1057,14 @@ type ErrorSystemConfig struct {
 
 	// AdminRoomID is the room ID to search for admins (third priority)
 	AdminRoomID string `toml:"admin_room_id" env:"ARMORCLAW_ERRORS_ADMIN_ROOM_ID"`
+
+	// EscalationMXIDs are secondary on-call admins, notified in order while a
+	// critical error stays unresolved
+	EscalationMXIDs []string `toml:"escalation_mxids" env:"ARMORCLAW_ERRORS_ESCALATION_MXIDS"`
+
+	// EscalationTimeout is how long a critical error may stay unresolved
+	// before the next escalation tier is notified (e.g., "15m")
+	EscalationTimeout string `toml:"escalation_timeout" env:"ARMORCLAW_ERRORS_ESCALATION_TIMEOUT"`
 }
 
 // DefaultConfig returns the default configuration
@@ -886,6 +1107,7 @@ func DefaultConfig() *Config {
 			MonthlyLimitUSD: 100.00, // $100/month default
 			AlertThreshold:  80.0,   // Warn at 80%
 			HardStop:        true,   // Prevent overages by default
+			ThrottleDelay:   "5s",
 			ProviderCosts:   make(map[string]float64),
 		},
 		WebRTC: WebRTCConfig{
@@ -910,6 +1132,9 @@ func DefaultConfig() *Config {
 			SignalingPath:    "/webrtc",
 			SignalingTLSCert: "",
 			SignalingTLSKey:  "",
+
+			SignalingPingInterval: "30s",
+			SignalingPongTimeout:  "30s",
 		},
 		Voice: VoiceConfig{
 			DefaultLifetime:   "30m",
@@ -983,6 +1208,9 @@ func DefaultConfig() *Config {
 			WebSocketPath:     "/events",
 			MaxSubscribers:    100,
 			InactivityTimeout: "30m",
+			ReplayBacklog:     1000,
+			PingInterval:      "30s",
+			PongTimeout:       "30s",
 		},
 		Discovery: DiscoveryConfig{
 			Enabled:          true,  // Enable mDNS discovery by default
@@ -994,6 +1222,7 @@ func DefaultConfig() *Config {
 			MatrixHomeserver: "", // Will use Matrix config if empty
 			PushGateway:      "", // Will derive from API URL if empty
 			Hardware:         "",
+			AnnounceInterval: "60s",
 		},
 		ErrorSystem: ErrorSystemConfig{
 			Enabled:         true,
@@ -1006,6 +1235,8 @@ func DefaultConfig() *Config {
 			AdminMXID:       "",
 			SetupUserMXID:   "",
 			AdminRoomID:     "",
+
+			EscalationTimeout: "15m",
 		},
 		Provisioning: ProvisioningConfig{
 			SigningSecret:        "", // Generated during container-setup.sh
@@ -1015,13 +1246,17 @@ func DefaultConfig() *Config {
 			DataDir:              "", // Set by container-setup.sh for persistence
 		},
 		Compliance: ComplianceConfig{
-			Enabled:            false, // Disabled by default for performance
-			StreamingMode:      true,  // Allow streaming when disabled
-			QuarantineEnabled:  false,
-			NotifyOnQuarantine: false,
-			AuditEnabled:       false,
-			AuditRetentionDays: 30,
-			Tier:               "basic",
+			Enabled:             false, // Disabled by default for performance
+			StreamingMode:       true,  // Allow streaming when disabled
+			QuarantineEnabled:   false,
+			NotifyOnQuarantine:  false,
+			AuditEnabled:        false,
+			AuditRetentionDays:  30,
+			PIIApprovalValidity: "15m",
+			HITLTimeout:         "60s",
+			HITLTimeoutAction:   "expire",
+			HITLTimeoutWarning:  "15s",
+			Tier:                "basic",
 			Patterns: PIIPatternConfig{
 				// Basic PII patterns enabled by default
 				SSN:        true,
@@ -1051,6 +1286,24 @@ func DefaultConfig() *Config {
 			V6AuditMode:   false,
 			SocketPath:    "/run/armorclaw/vault/keystore.sock",
 		},
+		Container: ContainerConfig{
+			MemoryMB:       512,
+			CPUShares:      1024,
+			MaxRestarts:    3,
+			RestartBackoff: "10s",
+		},
+		Metrics: MetricsConfig{
+			Enabled:    false,
+			ListenAddr: "127.0.0.1:9464",
+		},
+		Push: PushConfig{
+			Enabled:         false,
+			APNSEnvironment: "production",
+			NotifyMessages:  true,
+		},
+		License: LicenseConfig{
+			CacheDir: "/var/lib/armorclaw/license",
+		},
 	}
 }
 
@@ -1064,8 +1317,56 @@ func ConfigPaths() []string {
 	}
 }
 
-// Validate validates the configuration
+// FieldError describes one invalid setting found by Validate
+type FieldError struct {
+	// Field is the TOML path of the setting, e.g. "logging.level"
+	Field string
+	// Value is the offending value, or nil when the setting is missing
+	Value interface{}
+	// Reason explains what is wrong, phrased to follow the field name
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	if e.Value == nil {
+		return e.Field + " " + e.Reason
+	}
+	return fmt.Sprintf("%s %s, got '%v'", e.Field, e.Reason, e.Value)
+}
+
+// ValidationErrors lists every invalid setting in a configuration, in file
+// order. It matches ErrInvalidConfig with errors.Is.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	if len(e) == 1 {
+		return fmt.Sprintf("%v: %v", ErrInvalidConfig, e[0])
+	}
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return fmt.Sprintf("%v: %d problems: %s", ErrInvalidConfig, len(e), strings.Join(msgs, "; "))
+}
+
+func (e ValidationErrors) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// add records a problem with a setting
+func (e *ValidationErrors) add(field string, value interface{}, reason string) {
+	*e = append(*e, FieldError{Field: field, Value: value, Reason: reason})
+}
+
+// Validate validates the configuration. It reports every problem rather
+// than stopping at the first, as a ValidationErrors.
 func (c *Config) Validate() error {
+	var problems ValidationErrors
+
+	if c.Version < 0 || c.Version > CurrentConfigVersion {
+		problems.add("version", c.Version, fmt.Sprintf("must be between 0 and %d (this bridge's config version)", CurrentConfigVersion))
+	}
+
 	// Validate server configuration
 	if c.Server.Mode == "" {
 		c.Server.Mode = "native"
@@ -1076,24 +1377,25 @@ func (c *Config) Validate() error {
 		"sentinel": true,
 	}
 	if !validModes[c.Server.Mode] {
-		return fmt.Errorf("%w: server.mode must be 'native' or 'sentinel', got '%s'", ErrInvalidConfig, c.Server.Mode)
+		problems.add("server.mode", c.Server.Mode, "must be 'native' or 'sentinel'")
 	}
 
-	if c.Server.Mode == "sentinel" {
+	switch c.Server.Mode {
+	case "sentinel":
 		if c.Server.ListenAddr == "" {
-			return fmt.Errorf("%w: server.listen_addr is required when server.mode is 'sentinel'", ErrInvalidConfig)
+			problems.add("server.listen_addr", nil, "is required when server.mode is 'sentinel'")
 		}
 		if c.Server.PublicBaseURL == "" {
-			return fmt.Errorf("%w: server.public_base_url is required when server.mode is 'sentinel'", ErrInvalidConfig)
+			problems.add("server.public_base_url", nil, "is required when server.mode is 'sentinel'")
 		}
-	} else {
+	case "native":
 		if c.Server.SocketPath == "" {
-			return fmt.Errorf("%w: server.socket_path is required when server.mode is 'native'", ErrInvalidConfig)
+			problems.add("server.socket_path", nil, "is required when server.mode is 'native'")
+			break
 		}
-
 		socketDir := filepath.Dir(c.Server.SocketPath)
 		if err := validateDirectoryWritable(socketDir); err != nil {
-			return fmt.Errorf("%w: socket directory %s: %w", ErrInvalidConfig, socketDir, err)
+			problems.add("server.socket_path", c.Server.SocketPath, fmt.Sprintf("must be in a writable directory (%s: %v)", socketDir, err))
 		}
 	}
 
@@ -1107,42 +1409,97 @@ func (c *Config) Validate() error {
 	}
 
 	if !validAuthModes[c.Server.Auth] {
-		return fmt.Errorf("%w: server.auth must be 'token', got '%s'. auth: none is deprecated and not allowed for production", ErrInvalidConfig, c.Server.Auth)
+		problems.add("server.auth", c.Server.Auth, "must be 'token' (auth: none is deprecated and not allowed for production)")
 	}
 
 	// Validate keystore configuration
 	if c.Keystore.DBPath == "" {
-		return fmt.Errorf("%w: keystore.db_path is required", ErrInvalidConfig)
+		problems.add("keystore.db_path", nil, "is required")
+	} else {
+		// Validate keystore directory exists or can be created
+		keystoreDir := filepath.Dir(c.Keystore.DBPath)
+		if err := validateDirectoryWritable(keystoreDir); err != nil {
+			problems.add("keystore.db_path", c.Keystore.DBPath, fmt.Sprintf("must be in a writable directory (%s: %v)", keystoreDir, err))
+		}
 	}
 
-	// Validate keystore directory exists or can be created
-	keystoreDir := filepath.Dir(c.Keystore.DBPath)
-	if err := validateDirectoryWritable(keystoreDir); err != nil {
-		return fmt.Errorf("%w: keystore directory %s: %w", ErrInvalidConfig, keystoreDir, err)
+	if c.Keystore.ExpiryGrace != "" {
+		if d, err := time.ParseDuration(c.Keystore.ExpiryGrace); err != nil || d < 0 {
+			problems.add("keystore.expiry_grace", c.Keystore.ExpiryGrace, "must be a non-negative duration")
+		}
 	}
 
 	// Validate Matrix configuration if enabled
 	if c.Matrix.Enabled {
 		if c.Matrix.HomeserverURL == "" {
-			return fmt.Errorf("%w: matrix.homeserver_url is required when matrix is enabled", ErrInvalidConfig)
+			problems.add("matrix.homeserver_url", nil, "is required when matrix is enabled")
 		}
 
 		// Validate sync interval
 		if c.Matrix.SyncInterval < 1 {
-			return fmt.Errorf("%w: matrix.sync_interval must be at least 1 second", ErrInvalidConfig)
+			problems.add("matrix.sync_interval", c.Matrix.SyncInterval, "must be at least 1 second")
+		}
+
+		if c.Matrix.EventBufferSize < 0 {
+			problems.add("matrix.event_buffer_size", c.Matrix.EventBufferSize, "cannot be negative")
+		}
+
+		if c.Matrix.TokenRefreshMargin != "" {
+			if d, err := time.ParseDuration(c.Matrix.TokenRefreshMargin); err != nil || d <= 0 {
+				problems.add("matrix.token_refresh_margin", c.Matrix.TokenRefreshMargin, "must be a positive duration")
+			}
 		}
 
 		// Validate retry configuration
 		if c.Matrix.Retry.MaxRetries < 0 {
-			return fmt.Errorf("%w: matrix.retry.max_retries cannot be negative", ErrInvalidConfig)
+			problems.add("matrix.retry.max_retries", c.Matrix.Retry.MaxRetries, "cannot be negative")
 		}
 
 		if c.Matrix.Retry.RetryDelay < 0 {
-			return fmt.Errorf("%w: matrix.retry.retry_delay cannot be negative", ErrInvalidConfig)
+			problems.add("matrix.retry.retry_delay", c.Matrix.Retry.RetryDelay, "cannot be negative")
 		}
 
 		if c.Matrix.Retry.BackoffMultiplier < 1.0 {
-			return fmt.Errorf("%w: matrix.retry.backoff_multiplier must be at least 1.0", ErrInvalidConfig)
+			problems.add("matrix.retry.backoff_multiplier", c.Matrix.Retry.BackoffMultiplier, "must be at least 1.0")
+		}
+	}
+
+	if c.Compliance.AuditRetentionDays < 0 {
+		problems.add("compliance.audit_retention_days", c.Compliance.AuditRetentionDays, "cannot be negative")
+	}
+	if c.Compliance.PIIApprovalValidity != "" {
+		if d, err := time.ParseDuration(c.Compliance.PIIApprovalValidity); err != nil || d <= 0 {
+			problems.add("compliance.pii_approval_validity", c.Compliance.PIIApprovalValidity, "must be a positive duration")
+		}
+	}
+	if c.Compliance.HITLTimeout != "" {
+		if d, err := time.ParseDuration(c.Compliance.HITLTimeout); err != nil || d <= 0 {
+			problems.add("compliance.hitl_timeout", c.Compliance.HITLTimeout, "must be a positive duration")
+		}
+	}
+	switch c.Compliance.HITLTimeoutAction {
+	case "", "expire", "approve", "reject", "escalate":
+	default:
+		problems.add("compliance.hitl_timeout_action", c.Compliance.HITLTimeoutAction, "must be one of: expire, approve, reject, escalate")
+	}
+	if c.Compliance.HITLTimeoutWarning != "" {
+		if d, err := time.ParseDuration(c.Compliance.HITLTimeoutWarning); err != nil || d < 0 {
+			problems.add("compliance.hitl_timeout_warning", c.Compliance.HITLTimeoutWarning, "must be a non-negative duration")
+		}
+	}
+
+	// Validate WebSocket keepalives
+	for _, d := range []struct{ field, value string }{
+		{"eventbus.ping_interval", c.EventBus.PingInterval},
+		{"eventbus.pong_timeout", c.EventBus.PongTimeout},
+		{"webrtc.signaling_ping_interval", c.WebRTC.SignalingPingInterval},
+		{"webrtc.signaling_pong_timeout", c.WebRTC.SignalingPongTimeout},
+	} {
+		if d.value == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d.value); err != nil || v <= 0 {
+			problems.add(d.field, d.value, "must be a positive duration")
 		}
 	}
 
@@ -1154,7 +1511,7 @@ func (c *Config) Validate() error {
 		"error": true,
 	}
 	if !validLevels[c.Logging.Level] {
-		return fmt.Errorf("%w: logging.level must be one of: debug, info, warn, error", ErrInvalidConfig)
+		problems.add("logging.level", c.Logging.Level, "must be one of: debug, info, warn, error")
 	}
 
 	validFormats := map[string]bool{
@@ -1162,7 +1519,7 @@ func (c *Config) Validate() error {
 		"text": true,
 	}
 	if !validFormats[c.Logging.Format] {
-		return fmt.Errorf("%w: logging.format must be one of: json, text", ErrInvalidConfig)
+		problems.add("logging.format", c.Logging.Format, "must be one of: json, text")
 	}
 
 	validOutputs := map[string]bool{
@@ -1171,26 +1528,122 @@ func (c *Config) Validate() error {
 		"file":   true,
 	}
 	if !validOutputs[c.Logging.Output] {
-		return fmt.Errorf("%w: logging.output must be one of: stdout, stderr, file", ErrInvalidConfig)
+		problems.add("logging.output", c.Logging.Output, "must be one of: stdout, stderr, file")
 	}
 
 	if c.Logging.Output == "file" && c.Logging.File == "" {
-		return fmt.Errorf("%w: logging.file is required when logging.output is 'file'", ErrInvalidConfig)
+		problems.add("logging.file", nil, "is required when logging.output is 'file'")
 	}
 
 	// Validate budget configuration
 	if c.Budget.DailyLimitUSD < 0 {
-		return fmt.Errorf("%w: budget.daily_limit_usd cannot be negative", ErrInvalidConfig)
+		problems.add("budget.daily_limit_usd", c.Budget.DailyLimitUSD, "cannot be negative")
 	}
 
 	if c.Budget.MonthlyLimitUSD < 0 {
-		return fmt.Errorf("%w: budget.monthly_limit_usd cannot be negative", ErrInvalidConfig)
+		problems.add("budget.monthly_limit_usd", c.Budget.MonthlyLimitUSD, "cannot be negative")
 	}
 
 	if c.Budget.AlertThreshold < 0 || c.Budget.AlertThreshold > 100 {
-		return fmt.Errorf("%w: budget.alert_threshold must be between 0 and 100", ErrInvalidConfig)
+		problems.add("budget.alert_threshold", c.Budget.AlertThreshold, "must be between 0 and 100")
+	}
+
+	if _, err := budget.ParseSchedule(c.Budget.ResetSchedule); err != nil {
+		problems.add("budget.reset_schedule", c.Budget.ResetSchedule, fmt.Sprintf("is invalid: %v", err))
+	}
+
+	if _, err := budget.ParseMode(c.Budget.Mode, c.Budget.HardStop); err != nil {
+		problems.add("budget.mode", c.Budget.Mode, "must be alert, hard_stop or throttle")
+	}
+
+	if c.Budget.ThrottleDelay != "" {
+		if d, err := time.ParseDuration(c.Budget.ThrottleDelay); err != nil || d <= 0 {
+			problems.add("budget.throttle_delay", c.Budget.ThrottleDelay, "must be a positive duration (e.g. 5s)")
+		}
+	}
+
+	if c.Discovery.AnnounceInterval != "" {
+		if d, err := time.ParseDuration(c.Discovery.AnnounceInterval); err != nil || d < 0 {
+			problems.add("discovery.announce_interval", c.Discovery.AnnounceInterval, "must be a non-negative duration")
+		}
+	}
+
+	if c.Discovery.AdvertiseIP != "" && net.ParseIP(c.Discovery.AdvertiseIP) == nil {
+		problems.add("discovery.advertise_ip", c.Discovery.AdvertiseIP, "must be an IP address")
+	}
+
+	if err := c.ToContainerLimits().Validate(); err != nil {
+		problems.add("container", nil, fmt.Sprintf("resource limits are invalid: %v", err))
+	}
+
+	if c.Container.MaxRestarts < 0 {
+		problems.add("container.max_restarts", c.Container.MaxRestarts, "cannot be negative")
+	}
+
+	if c.Container.RestartBackoff != "" {
+		if d, err := time.ParseDuration(c.Container.RestartBackoff); err != nil || d < 0 {
+			problems.add("container.restart_backoff", c.Container.RestartBackoff, "must be a non-negative duration")
+		}
+	}
+
+	if c.Metrics.Enabled {
+		if _, port, err := net.SplitHostPort(c.Metrics.ListenAddr); err != nil || port == "" {
+			problems.add("metrics.listen_addr", c.Metrics.ListenAddr, "must be a host:port address when metrics are enabled")
+		}
+	}
+
+	if c.Push.Enabled {
+		if !c.Push.FCMEnabled() && !c.Push.APNSEnabled() {
+			problems.add("push.fcm_credentials_file", c.Push.FCMCredentialsFile, "FCM credentials or an APNs certificate are required when push is enabled")
+		}
+		if c.Push.APNSEnabled() {
+			if c.Push.APNSKeyFile == "" {
+				problems.add("push.apns_key_file", c.Push.APNSKeyFile, "is required with push.apns_cert_file")
+			}
+			if c.Push.APNSTopic == "" {
+				problems.add("push.apns_topic", c.Push.APNSTopic, "is required with push.apns_cert_file")
+			}
+		}
+		if c.Push.APNSEnvironment != "production" && c.Push.APNSEnvironment != "sandbox" {
+			problems.add("push.apns_environment", c.Push.APNSEnvironment, "must be production or sandbox")
+		}
+	}
+
+	// Validate TURN server URLs
+	for _, u := range c.WebRTC.turnServerURLs() {
+		if _, err := turn.ParseServerURL(u); err != nil {
+			problems.add("webrtc.turn_server_urls", u, fmt.Sprintf("must contain TURN server URLs (%v)", err))
+		}
 	}
 
+	// Validate error system rate-limit overrides, in a stable order
+	codes := make([]string, 0, len(c.ErrorSystem.CodeWindows))
+	for code := range c.ErrorSystem.CodeWindows {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		window := c.ErrorSystem.CodeWindows[code]
+		if d, err := time.ParseDuration(window); err != nil || d <= 0 {
+			problems.add("errors.code_windows."+code, window, "must be a positive duration")
+		}
+	}
+
+	// Validate error escalation tiers
+	for _, mxid := range c.ErrorSystem.EscalationMXIDs {
+		if !strings.HasPrefix(mxid, "@") || !strings.Contains(mxid, ":") {
+			problems.add("errors.escalation_mxids", mxid, "must contain Matrix user IDs (@user:server)")
+		}
+	}
+	if c.ErrorSystem.EscalationTimeout != "" {
+		if d, err := time.ParseDuration(c.ErrorSystem.EscalationTimeout); err != nil || d <= 0 {
+			problems.add("errors.escalation_timeout", c.ErrorSystem.EscalationTimeout, "must be a positive duration")
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
 	return nil
 }
 
@@ -1213,6 +1666,129 @@ func (c *Config) ToKeystoreConfig() keystore.Config {
 		cfg.MasterKey = []byte(c.Keystore.MasterKey)
 	}
 
+	if c.Keystore.ExpiryGrace != "" {
+		if d, err := time.ParseDuration(c.Keystore.ExpiryGrace); err == nil && d > 0 {
+			cfg.ExpiryGrace = d
+		}
+	}
+
+	return cfg
+}
+
+// turnServerURLs returns the configured TURN URLs in priority order,
+// without duplicates
+func (w WebRTCConfig) turnServerURLs() []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range append([]string{w.TURNServerURL}, w.TURNServerURLs...) {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// ToTURNConfig converts the Config to turn.Config. Servers keep their
+// configured order, which clients use as failover priority. Without any
+// configured URL the default server is kept.
+func (c *Config) ToTURNConfig() turn.Config {
+	cfg := turn.DefaultConfig()
+	cfg.Secret = c.WebRTC.TURNSharedSecret
+
+	var servers []turn.ServerConfig
+	for _, u := range c.WebRTC.turnServerURLs() {
+		// Invalid URLs are rejected by Validate
+		if server, err := turn.ParseServerURL(u); err == nil {
+			servers = append(servers, server)
+		}
+	}
+	if len(servers) > 0 {
+		cfg.Servers = servers
+	}
+
+	return cfg
+}
+
+// ToContainerLimits converts the container section to docker.ResourceLimits
+func (c *Config) ToContainerLimits() docker.ResourceLimits {
+	return docker.ResourceLimits{
+		Memory:    c.Container.MemoryMB * 1024 * 1024,
+		CPUShares: c.Container.CPUShares,
+	}
+}
+
+// EventBusKeepalive returns the ping/pong heartbeat for event WebSocket
+// clients. Unset or invalid values use the websocket package defaults.
+func (c *Config) EventBusKeepalive() websocket.Keepalive {
+	return websocket.Keepalive{
+		PingInterval: positiveDuration(c.EventBus.PingInterval),
+		PongTimeout:  positiveDuration(c.EventBus.PongTimeout),
+	}
+}
+
+// SignalingKeepalive returns the ping/pong heartbeat for WebRTC signaling
+// clients. Unset or invalid values use the websocket package defaults.
+func (c *Config) SignalingKeepalive() websocket.Keepalive {
+	return websocket.Keepalive{
+		PingInterval: positiveDuration(c.WebRTC.SignalingPingInterval),
+		PongTimeout:  positiveDuration(c.WebRTC.SignalingPongTimeout),
+	}
+}
+
+// positiveDuration parses s, returning 0 if it is empty, invalid or not
+// positive
+func positiveDuration(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// PIIApprovalValidityDuration returns the PII approval validity window, or
+// 0 to use the default
+func (c *Config) PIIApprovalValidityDuration() time.Duration {
+	d, err := time.ParseDuration(c.Compliance.PIIApprovalValidity)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// HITLTimeoutDuration returns the PII consent request timeout, or 0 to use
+// the default
+func (c *Config) HITLTimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(c.Compliance.HITLTimeout)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
+// HITLTimeoutWarningDuration returns how long before a consent request's
+// deadline the admin is warned, or 0 if the warning is disabled
+func (c *Config) HITLTimeoutWarningDuration() time.Duration {
+	d, err := time.ParseDuration(c.Compliance.HITLTimeoutWarning)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// ToHealthConfig converts the container section to health.MonitorConfig
+func (c *Config) ToHealthConfig() health.MonitorConfig {
+	cfg := health.DefaultMonitorConfig()
+	cfg.RestartOnFailure = c.Container.RestartOnFailure
+	if c.Container.MaxRestarts > 0 {
+		cfg.MaxRestarts = c.Container.MaxRestarts
+	}
+	if c.Container.RestartBackoff != "" {
+		if d, err := time.ParseDuration(c.Container.RestartBackoff); err == nil && d > 0 {
+			cfg.RestartBackoff = d
+		}
+	}
 	return cfg
 }
 
@@ -1225,6 +1801,12 @@ func (c *Config) ToMatrixConfig() adapter.Config {
 		TrustedSenders:  c.Matrix.ZeroTrust.TrustedSenders,
 		TrustedRooms:    c.Matrix.ZeroTrust.TrustedRooms,
 		RejectUntrusted: c.Matrix.ZeroTrust.RejectUntrusted,
+
+		TokenRefreshMargin: c.GetTokenRefreshMargin(),
+		MaxRetries:         c.Matrix.Retry.MaxRetries,
+		RetryDelay:         time.Duration(c.Matrix.Retry.RetryDelay) * time.Second,
+		BackoffMultiplier:  c.Matrix.Retry.BackoffMultiplier,
+		EventBufferSize:    c.Matrix.EventBufferSize,
 	}
 }
 
@@ -1238,6 +1820,19 @@ func (c *Config) IsMatrixEnabled() bool {
 	return c.Matrix.Enabled
 }
 
+// GetTokenRefreshMargin returns how long before expiry the Matrix access
+// token is refreshed, or zero to use the adapter default
+func (c *Config) GetTokenRefreshMargin() time.Duration {
+	if c.Matrix.TokenRefreshMargin == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.Matrix.TokenRefreshMargin)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
 // GetSyncInterval returns the Matrix sync interval as a Duration
 func (c *Config) GetSyncInterval() time.Duration {
 	return time.Duration(c.Matrix.SyncInterval) * time.Second
@@ -1254,12 +1849,19 @@ func (c *Config) GetLegacyURL() string {
 
 // ToBudgetConfig converts the Config to budget.BudgetConfig
 func (c *Config) ToBudgetConfig() budget.BudgetConfig {
+	// Validated in Validate; the tracker falls back to its default if unset
+	throttleDelay, _ := time.ParseDuration(c.Budget.ThrottleDelay)
+
 	return budget.BudgetConfig{
 		DailyLimitUSD:   c.Budget.DailyLimitUSD,
 		MonthlyLimitUSD: c.Budget.MonthlyLimitUSD,
 		AlertThreshold:  c.Budget.AlertThreshold,
 		HardStop:        c.Budget.HardStop,
 		ProviderCosts:   c.Budget.ProviderCosts,
+		ResetSchedule:   c.Budget.ResetSchedule,
+		Mode:            c.Budget.Mode,
+		ThrottleDelay:   throttleDelay,
+		ThrottleModel:   c.Budget.ThrottleModel,
 	}
 }
 
@@ -1269,7 +1871,9 @@ type ErrorSystemConfigResult struct {
 	StorePath       string
 	RetentionDays   int
 	RateLimitWindow string
+	CodeWindows     map[string]time.Duration
 	RetentionPeriod string
+	DigestInterval  string
 	ConfigAdminMXID string
 	SetupUserMXID   string
 	AdminRoomID     string
@@ -1277,15 +1881,28 @@ type ErrorSystemConfigResult struct {
 	Enabled         bool
 	StoreEnabled    bool
 	NotifyEnabled   bool
+
+	EscalationMXIDs   []string
+	EscalationTimeout string
 }
 
 // ToErrorSystemConfig converts the Config to error system config
 func (c *Config) ToErrorSystemConfig() ErrorSystemConfigResult {
+	// Invalid windows are rejected by Validate; skip them here
+	codeWindows := make(map[string]time.Duration, len(c.ErrorSystem.CodeWindows))
+	for code, window := range c.ErrorSystem.CodeWindows {
+		if d, err := time.ParseDuration(window); err == nil && d > 0 {
+			codeWindows[code] = d
+		}
+	}
+
 	return ErrorSystemConfigResult{
 		StorePath:       c.ErrorSystem.StorePath,
 		RetentionDays:   c.ErrorSystem.RetentionDays,
 		RateLimitWindow: c.ErrorSystem.RateLimitWindow,
+		CodeWindows:     codeWindows,
 		RetentionPeriod: c.ErrorSystem.RetentionPeriod,
+		DigestInterval:  c.ErrorSystem.DigestInterval,
 		ConfigAdminMXID: c.ErrorSystem.AdminMXID,
 		SetupUserMXID:   c.ErrorSystem.SetupUserMXID,
 		AdminRoomID:     c.ErrorSystem.AdminRoomID,
@@ -1293,5 +1910,8 @@ func (c *Config) ToErrorSystemConfig() ErrorSystemConfigResult {
 		Enabled:         c.ErrorSystem.Enabled,
 		StoreEnabled:    c.ErrorSystem.StoreEnabled,
 		NotifyEnabled:   c.ErrorSystem.NotifyEnabled,
+
+		EscalationMXIDs:   c.ErrorSystem.EscalationMXIDs,
+		EscalationTimeout: c.ErrorSystem.EscalationTimeout,
 	}
 }
@@ -2,9 +2,15 @@
 package config
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/armorclaw/bridge/pkg/budget"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -79,6 +85,132 @@ func TestValidate(t *testing.T) {
 	if err := cfg.Validate(); err == nil {
 		t.Error("Expected validation error for invalid log level")
 	}
+
+	// Test negative audit retention
+	cfg = DefaultConfig()
+	cfg.Compliance.AuditRetentionDays = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative audit retention")
+	}
+
+	// Test PII approval validity must be a positive duration
+	cfg = DefaultConfig()
+	cfg.Compliance.PIIApprovalValidity = "0s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for zero PII approval validity")
+	}
+
+	// Test HITL timeout settings
+	cfg = DefaultConfig()
+	cfg.Compliance.HITLTimeoutAction = "ignore"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown HITL timeout action")
+	}
+	cfg = DefaultConfig()
+	cfg.Compliance.HITLTimeout = "0s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for zero HITL timeout")
+	}
+	cfg = DefaultConfig()
+	cfg.Compliance.HITLTimeoutWarning = "0s"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Unexpected validation error for disabled HITL timeout warning: %v", err)
+	}
+
+	// Test budget mode and throttle delay
+	cfg = DefaultConfig()
+	cfg.Budget.Mode = "slow"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown budget mode")
+	}
+	cfg = DefaultConfig()
+	cfg.Budget.Mode = "throttle"
+	cfg.Budget.ThrottleDelay = "soon"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for invalid throttle delay")
+	}
+	cfg.Budget.ThrottleDelay = "2s"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Unexpected validation error for throttle mode: %v", err)
+	}
+	if bc := cfg.ToBudgetConfig(); bc.EnforcementMode() != budget.ModeThrottle || bc.ThrottleDelay != 2*time.Second {
+		t.Errorf("ToBudgetConfig() = %+v, want throttle mode with a 2s delay", bc)
+	}
+
+	// Test WebSocket keepalive intervals must be positive durations
+	cfg = DefaultConfig()
+	cfg.EventBus.PongTimeout = "-1s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative pong timeout")
+	}
+	cfg = DefaultConfig()
+	cfg.WebRTC.SignalingPingInterval = "often"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for invalid signaling ping interval")
+	}
+	cfg.WebRTC.SignalingPingInterval = "15s"
+	if k := cfg.SignalingKeepalive(); k.PingInterval != 15*time.Second || k.ReadTimeout() != 45*time.Second {
+		t.Errorf("SignalingKeepalive() = %+v, want a 15s ping and 45s read timeout", k)
+	}
+
+	// Test metrics listen address, only checked when metrics are enabled
+	cfg = DefaultConfig()
+	cfg.Metrics.ListenAddr = "9464"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Unexpected validation error with metrics disabled: %v", err)
+	}
+	cfg.Metrics.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for metrics listen address without a port")
+	}
+
+	// Test push requires a provider, and APNs needs its key and topic
+	cfg = DefaultConfig()
+	cfg.Push.Enabled = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for push without a provider")
+	}
+	cfg.Push.APNSCertFile = "/etc/armorclaw/apns.pem"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for APNs certificate without key and topic")
+	}
+	cfg.Push.APNSKeyFile = "/etc/armorclaw/apns.key"
+	cfg.Push.APNSTopic = "com.armorclaw.app"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Unexpected validation error with complete APNs config: %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Logging.Level = "verbose"
+	cfg.Budget.DailyLimitUSD = -5
+	cfg.ErrorSystem.CodeWindows = map[string]string{"RPC": "0s", "CTX": "soon"}
+
+	err := cfg.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("Validate() = %v, want ErrInvalidConfig", err)
+	}
+	var problems ValidationErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("Validate() returned %T, want ValidationErrors", err)
+	}
+
+	want := ValidationErrors{
+		{Field: "logging.level", Value: "verbose", Reason: "must be one of: debug, info, warn, error"},
+		{Field: "budget.daily_limit_usd", Value: -5.0, Reason: "cannot be negative"},
+		{Field: "errors.code_windows.CTX", Value: "soon", Reason: "must be a positive duration"},
+		{Field: "errors.code_windows.RPC", Value: "0s", Reason: "must be a positive duration"},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %+v, want %+v", problems, want)
+	}
+	if msg := problems[0].Error(); msg != "logging.level must be one of: debug, info, warn, error, got 'verbose'" {
+		t.Errorf("FieldError.Error() = %q", msg)
+	}
+	if !strings.HasPrefix(err.Error(), "invalid configuration: 4 problems: logging.level") {
+		t.Errorf("Error() = %q", err.Error())
+	}
 }
 
 func TestToMatrixConfig(t *testing.T) {
@@ -111,6 +243,78 @@ func TestToMatrixConfig(t *testing.T) {
 	}
 }
 
+func TestToKeystoreConfigExpiryGrace(t *testing.T) {
+	cfg := DefaultConfig()
+	if ksCfg := cfg.ToKeystoreConfig(); ksCfg.ExpiryGrace != 0 {
+		t.Errorf("Expected strict expiry by default, got grace %v", ksCfg.ExpiryGrace)
+	}
+
+	cfg.Keystore.ExpiryGrace = "2m"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid expiry_grace rejected: %v", err)
+	}
+	if ksCfg := cfg.ToKeystoreConfig(); ksCfg.ExpiryGrace != 2*time.Minute {
+		t.Errorf("Expected ExpiryGrace 2m, got %v", ksCfg.ExpiryGrace)
+	}
+
+	cfg.Keystore.ExpiryGrace = "soon"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for invalid expiry_grace")
+	}
+}
+
+func TestToMatrixConfigTokenRefreshMargin(t *testing.T) {
+	cfg := DefaultConfig()
+	if margin := cfg.ToMatrixConfig().TokenRefreshMargin; margin != 0 {
+		t.Errorf("Expected adapter default margin, got %v", margin)
+	}
+
+	cfg.Matrix.Enabled = true
+	cfg.Matrix.HomeserverURL = "https://matrix.example.com"
+	cfg.Matrix.TokenRefreshMargin = "10m"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Valid token_refresh_margin rejected: %v", err)
+	}
+	if margin := cfg.ToMatrixConfig().TokenRefreshMargin; margin != 10*time.Minute {
+		t.Errorf("Expected TokenRefreshMargin 10m, got %v", margin)
+	}
+
+	cfg.Matrix.TokenRefreshMargin = "0s"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for zero token_refresh_margin")
+	}
+}
+
+func TestToTURNConfigServerOrder(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.WebRTC.TURNSharedSecret = "secret"
+	if servers := cfg.ToTURNConfig().Servers; len(servers) != 1 {
+		t.Errorf("Expected the default TURN server, got %+v", servers)
+	}
+
+	cfg.WebRTC.TURNServerURL = "turn:primary.example.com:3478"
+	cfg.WebRTC.TURNServerURLs = []string{"turns:backup.example.com", "turn:primary.example.com:3478"}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Valid TURN servers rejected: %v", err)
+	}
+
+	turnCfg := cfg.ToTURNConfig()
+	if turnCfg.Secret != "secret" {
+		t.Errorf("Expected shared secret to be passed through")
+	}
+	if len(turnCfg.Servers) != 2 {
+		t.Fatalf("Expected 2 deduplicated servers, got %+v", turnCfg.Servers)
+	}
+	if turnCfg.Servers[0].Host != "primary.example.com" || turnCfg.Servers[1].Host != "backup.example.com" {
+		t.Errorf("Expected primary before backup, got %+v", turnCfg.Servers)
+	}
+
+	cfg.WebRTC.TURNServerURLs = []string{"stun:stun.example.com"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a STUN URL in turn_server_urls")
+	}
+}
+
 func TestToBudgetConfig(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Budget.DailyLimitUSD = 10.00
@@ -136,6 +340,104 @@ func TestToBudgetConfig(t *testing.T) {
 	}
 }
 
+func TestToContainerLimits(t *testing.T) {
+	cfg := DefaultConfig()
+
+	limits := cfg.ToContainerLimits()
+	if limits.Memory != 512*1024*1024 || limits.CPUShares != 1024 {
+		t.Errorf("Expected default limits of 512MB and 1024 shares, got %+v", limits)
+	}
+
+	cfg.Container.MemoryMB = 0
+	cfg.Container.CPUShares = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected zero limits to mean unlimited, got: %v", err)
+	}
+
+	cfg.Container.MemoryMB = 2
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for memory_mb below the Docker minimum")
+	}
+
+	cfg.Container.MemoryMB = 256
+	cfg.Container.CPUShares = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative cpu_shares")
+	}
+}
+
+func TestToHealthConfig(t *testing.T) {
+	cfg := DefaultConfig()
+
+	healthCfg := cfg.ToHealthConfig()
+	if healthCfg.RestartOnFailure {
+		t.Error("Expected restarts to be opt-in")
+	}
+	if healthCfg.MaxRestarts != 3 || healthCfg.RestartBackoff != 10*time.Second {
+		t.Errorf("Expected 3 restarts with 10s backoff, got %d/%s", healthCfg.MaxRestarts, healthCfg.RestartBackoff)
+	}
+
+	cfg.Container.RestartOnFailure = true
+	cfg.Container.MaxRestarts = 5
+	cfg.Container.RestartBackoff = "1m"
+	healthCfg = cfg.ToHealthConfig()
+	if !healthCfg.RestartOnFailure || healthCfg.MaxRestarts != 5 || healthCfg.RestartBackoff != time.Minute {
+		t.Errorf("Expected configured restart policy, got %+v", healthCfg)
+	}
+
+	cfg.Container.RestartBackoff = "soon"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for invalid restart_backoff")
+	}
+
+	cfg.Container.RestartBackoff = "10s"
+	cfg.Container.MaxRestarts = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for negative max_restarts")
+	}
+}
+
+func TestErrorEscalationConfig(t *testing.T) {
+	cfg := DefaultConfig()
+
+	cfg.ErrorSystem.EscalationMXIDs = []string{"@oncall:example.com", "@lead:example.com"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected escalation tiers to be valid, got: %v", err)
+	}
+	errorCfg := cfg.ToErrorSystemConfig()
+	if len(errorCfg.EscalationMXIDs) != 2 || errorCfg.EscalationTimeout != "15m" {
+		t.Errorf("Expected escalation settings to be passed through, got %v/%s", errorCfg.EscalationMXIDs, errorCfg.EscalationTimeout)
+	}
+
+	cfg.ErrorSystem.EscalationMXIDs = []string{"oncall"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a malformed escalation MXID")
+	}
+
+	cfg.ErrorSystem.EscalationMXIDs = nil
+	cfg.ErrorSystem.EscalationTimeout = "-5m"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for a negative escalation_timeout")
+	}
+}
+
+func TestBudgetResetSchedule(t *testing.T) {
+	cfg := DefaultConfig()
+
+	cfg.Budget.ResetSchedule = "weekly:sun"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected weekly schedule to be valid, got: %v", err)
+	}
+	if cfg.ToBudgetConfig().ResetSchedule != "weekly:sun" {
+		t.Error("Expected reset schedule to be passed through")
+	}
+
+	cfg.Budget.ResetSchedule = "fortnightly"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for an unknown reset schedule")
+	}
+}
+
 func TestRejectAuthNone(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -228,3 +530,39 @@ socket_path = "/custom/vault.sock"
 		t.Error("expected v6_audit_mode = true from TOML")
 	}
 }
+
+func TestLicenseOfflineConfig(t *testing.T) {
+	input := `
+[license]
+offline_mode = true
+offline_token = "toml-token"
+public_key = "dG9tbC1rZXk="
+`
+
+	cfg := DefaultConfig()
+	if _, err := toml.Decode(input, cfg); err != nil {
+		t.Fatalf("failed to parse license TOML: %v", err)
+	}
+
+	if !cfg.License.OfflineMode {
+		t.Error("expected offline_mode = true from TOML")
+	}
+	if cfg.License.OfflineToken != "toml-token" || cfg.License.PublicKey != "dG9tbC1rZXk=" {
+		t.Errorf("unexpected offline license fields: %+v", cfg.License)
+	}
+
+	cfg = DefaultConfig()
+	t.Setenv("ARMORCLAW_LICENSE_OFFLINE_MODE", "1")
+	t.Setenv("ARMORCLAW_LICENSE_OFFLINE_TOKEN", "env-token")
+	t.Setenv("ARMORCLAW_LICENSE_PUBLIC_KEY", "ZW52LWtleQ==")
+	if err := applyEnvOverrides(cfg); err != nil {
+		t.Fatalf("applyEnvOverrides: %v", err)
+	}
+
+	if !cfg.License.OfflineMode {
+		t.Error("expected ARMORCLAW_LICENSE_OFFLINE_MODE=1 to enable offline mode")
+	}
+	if cfg.License.OfflineToken != "env-token" || cfg.License.PublicKey != "ZW52LWtleQ==" {
+		t.Errorf("unexpected offline license fields from env: %+v", cfg.License)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 
@@ -45,10 +46,20 @@ func Load(path string) (*Config, error) {
 	}
 
 	// Parse TOML using BurntSushi/toml library
-	if err := toml.Unmarshal(data, cfg); err != nil {
+	meta, err := toml.Decode(string(data), cfg)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// Files written by this bridge still carry deprecated settings with
+	// their defaults, so only older files are warned about
+	for _, r := range configRenames {
+		if cfg.Version < r.Since && meta.IsDefined(strings.Split(r.From, ".")...) {
+			log := logger.Global().WithComponent("config")
+			log.Warn(fmt.Sprintf("%s is deprecated, use %s instead. Update the file with: armorclaw-bridge config migrate", r.From, r.To))
+		}
+	}
+
 	if cfg.Server.Auth == "none" {
 		log := logger.Global().WithComponent("config")
 		log.Warn("WARNING: auth: none is deprecated and will be removed in future versions. Use token-based authentication for production deployments")
@@ -115,6 +126,9 @@ func applyEnvOverrides(cfg *Config) error {
 	if v := os.Getenv("ARMORCLAW_MASTER_KEY"); v != "" {
 		cfg.Keystore.MasterKey = v
 	}
+	if v := os.Getenv("ARMORCLAW_KEYSTORE_EXPIRY_GRACE"); v != "" {
+		cfg.Keystore.ExpiryGrace = v
+	}
 
 	// Matrix overrides
 	if v := os.Getenv("ARMORCLAW_MATRIX_ENABLED"); v != "" {
@@ -139,6 +153,15 @@ func applyEnvOverrides(cfg *Config) error {
 			cfg.Matrix.SyncInterval = interval
 		}
 	}
+	if v := os.Getenv("ARMORCLAW_MATRIX_EVENT_BUFFER_SIZE"); v != "" {
+		var size int
+		if _, err := fmt.Sscanf(v, "%d", &size); err == nil {
+			cfg.Matrix.EventBufferSize = size
+		}
+	}
+	if v := os.Getenv("ARMORCLAW_MATRIX_TOKEN_REFRESH_MARGIN"); v != "" {
+		cfg.Matrix.TokenRefreshMargin = v
+	}
 
 	// WebRTC overrides
 	if v := os.Getenv("ARMORCLAW_WEBRTC_STUN_SERVER"); v != "" {
@@ -150,10 +173,98 @@ func applyEnvOverrides(cfg *Config) error {
 	if v := os.Getenv("ARMORCLAW_WEBRTC_TURN_URL"); v != "" {
 		cfg.WebRTC.TURNServerURL = v
 	}
+	if v := os.Getenv("ARMORCLAW_WEBRTC_TURN_URLS"); v != "" {
+		// Comma-separated, highest priority first
+		cfg.WebRTC.TURNServerURLs = nil
+		for _, u := range strings.Split(v, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				cfg.WebRTC.TURNServerURLs = append(cfg.WebRTC.TURNServerURLs, u)
+			}
+		}
+	}
 	if v := os.Getenv("ARMORCLAW_WEBRTC_TURN_SECRET"); v != "" {
 		cfg.WebRTC.TURNSharedSecret = v
 	}
 
+	// Container resource limit overrides
+	if v := os.Getenv("ARMORCLAW_CONTAINER_MEMORY_MB"); v != "" {
+		var mb int64
+		if _, err := fmt.Sscanf(v, "%d", &mb); err == nil {
+			cfg.Container.MemoryMB = mb
+		}
+	}
+	if v := os.Getenv("ARMORCLAW_CONTAINER_CPU_SHARES"); v != "" {
+		var shares int64
+		if _, err := fmt.Sscanf(v, "%d", &shares); err == nil {
+			cfg.Container.CPUShares = shares
+		}
+	}
+	if v := os.Getenv("ARMORCLAW_CONTAINER_RESTART_ON_FAILURE"); v != "" {
+		cfg.Container.RestartOnFailure = v == "true" || v == "1"
+	}
+
+	// Metrics overrides
+	if v := os.Getenv("ARMORCLAW_METRICS_ENABLED"); v != "" {
+		cfg.Metrics.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ARMORCLAW_METRICS_LISTEN_ADDR"); v != "" {
+		cfg.Metrics.ListenAddr = v
+	}
+
+	// Plugin overrides
+	if v := os.Getenv("ARMORCLAW_PLUGINS_DIR"); v != "" {
+		cfg.Plugins.Dir = v
+	}
+	if v := os.Getenv("ARMORCLAW_PLUGINS_CONFIG_FILE"); v != "" {
+		cfg.Plugins.ConfigFile = v
+	}
+
+	// License overrides
+	if v := os.Getenv("ARMORCLAW_LICENSE_KEY"); v != "" {
+		cfg.License.Key = v
+	}
+	if v := os.Getenv("ARMORCLAW_LICENSE_SERVER_URL"); v != "" {
+		cfg.License.ServerURL = v
+	}
+	if v := os.Getenv("ARMORCLAW_LICENSE_CACHE_DIR"); v != "" {
+		cfg.License.CacheDir = v
+	}
+	if v := os.Getenv("ARMORCLAW_LICENSE_OFFLINE_MODE"); v != "" {
+		cfg.License.OfflineMode = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ARMORCLAW_LICENSE_OFFLINE_TOKEN"); v != "" {
+		cfg.License.OfflineToken = v
+	}
+	if v := os.Getenv("ARMORCLAW_LICENSE_PUBLIC_KEY"); v != "" {
+		cfg.License.PublicKey = v
+	}
+
+	// Push overrides
+	if v := os.Getenv("ARMORCLAW_PUSH_ENABLED"); v != "" {
+		cfg.Push.Enabled = v == "true" || v == "1"
+	}
+	if v := os.Getenv("ARMORCLAW_PUSH_FCM_CREDENTIALS_FILE"); v != "" {
+		cfg.Push.FCMCredentialsFile = v
+	}
+	if v := os.Getenv("ARMORCLAW_PUSH_FCM_PROJECT_ID"); v != "" {
+		cfg.Push.FCMProjectID = v
+	}
+	if v := os.Getenv("ARMORCLAW_PUSH_APNS_CERT_FILE"); v != "" {
+		cfg.Push.APNSCertFile = v
+	}
+	if v := os.Getenv("ARMORCLAW_PUSH_APNS_KEY_FILE"); v != "" {
+		cfg.Push.APNSKeyFile = v
+	}
+	if v := os.Getenv("ARMORCLAW_PUSH_APNS_TOPIC"); v != "" {
+		cfg.Push.APNSTopic = v
+	}
+	if v := os.Getenv("ARMORCLAW_PUSH_APNS_ENVIRONMENT"); v != "" {
+		cfg.Push.APNSEnvironment = v
+	}
+	if v := os.Getenv("ARMORCLAW_PUSH_NOTIFY_MESSAGES"); v != "" {
+		cfg.Push.NotifyMessages = v == "true" || v == "1"
+	}
+
 	// Logging overrides
 	if v := os.Getenv("ARMORCLAW_LOG_LEVEL"); v != "" {
 		cfg.Logging.Level = v
@@ -193,6 +304,9 @@ func applyEnvOverrides(cfg *Config) error {
 	if v := os.Getenv("ARMORCLAW_ERRORS_RETENTION_PERIOD"); v != "" {
 		cfg.ErrorSystem.RetentionPeriod = v
 	}
+	if v := os.Getenv("ARMORCLAW_ERRORS_DIGEST_INTERVAL"); v != "" {
+		cfg.ErrorSystem.DigestInterval = v
+	}
 	if v := os.Getenv("ARMORCLAW_ERRORS_ADMIN_MXID"); v != "" {
 		cfg.ErrorSystem.AdminMXID = v
 	}
@@ -202,6 +316,18 @@ func applyEnvOverrides(cfg *Config) error {
 	if v := os.Getenv("ARMORCLAW_ERRORS_ADMIN_ROOM_ID"); v != "" {
 		cfg.ErrorSystem.AdminRoomID = v
 	}
+	if v := os.Getenv("ARMORCLAW_ERRORS_ESCALATION_MXIDS"); v != "" {
+		// Comma-separated, in escalation order
+		cfg.ErrorSystem.EscalationMXIDs = nil
+		for _, mxid := range strings.Split(v, ",") {
+			if mxid = strings.TrimSpace(mxid); mxid != "" {
+				cfg.ErrorSystem.EscalationMXIDs = append(cfg.ErrorSystem.EscalationMXIDs, mxid)
+			}
+		}
+	}
+	if v := os.Getenv("ARMORCLAW_ERRORS_ESCALATION_TIMEOUT"); v != "" {
+		cfg.ErrorSystem.EscalationTimeout = v
+	}
 
 	// Compliance/PII scrubbing overrides
 	if v := os.Getenv("ARMORCLAW_COMPLIANCE_ENABLED"); v != "" {
@@ -225,6 +351,18 @@ func applyEnvOverrides(cfg *Config) error {
 			cfg.Compliance.AuditRetentionDays = days
 		}
 	}
+	if v := os.Getenv("ARMORCLAW_COMPLIANCE_PII_APPROVAL_VALIDITY"); v != "" {
+		cfg.Compliance.PIIApprovalValidity = v
+	}
+	if v := os.Getenv("ARMORCLAW_COMPLIANCE_HITL_TIMEOUT"); v != "" {
+		cfg.Compliance.HITLTimeout = v
+	}
+	if v := os.Getenv("ARMORCLAW_COMPLIANCE_HITL_TIMEOUT_ACTION"); v != "" {
+		cfg.Compliance.HITLTimeoutAction = v
+	}
+	if v := os.Getenv("ARMORCLAW_COMPLIANCE_HITL_TIMEOUT_WARNING"); v != "" {
+		cfg.Compliance.HITLTimeoutWarning = v
+	}
 	if v := os.Getenv("ARMORCLAW_COMPLIANCE_TIER"); v != "" {
 		cfg.Compliance.Tier = v
 	}
@@ -304,6 +442,8 @@ func Save(cfg *Config, path string) error {
 	// Normalize paths for TOML compatibility (forward slashes, no backslashes)
 	// This fixes Windows path parsing issues where \U is interpreted as Unicode escape
 	cfgCopy := *cfg // Make a shallow copy
+	// Anything written here follows the current schema
+	cfgCopy.Version = CurrentConfigVersion
 	cfgCopy.Keystore.DBPath = filepath.ToSlash(cfg.Keystore.DBPath)
 	cfgCopy.Server.SocketPath = filepath.ToSlash(cfg.Server.SocketPath)
 	if cfgCopy.Server.PidFile != "" {
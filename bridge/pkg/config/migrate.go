@@ -0,0 +1,236 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CurrentConfigVersion is the config schema version this bridge writes.
+// Bump it when adding a rename to configRenames.
+const CurrentConfigVersion = 1
+
+// configRename maps a deprecated TOML key to its replacement, both as
+// dotted paths such as "browser.service_url"
+type configRename struct {
+	From string
+	To   string
+	// Since is the config version that introduced the rename
+	Since int
+}
+
+// configRenames lists renamed settings in the order they were renamed
+var configRenames = []configRename{
+	// LegacyURL has a default, so an old service_url was silently ignored
+	{From: "browser.service_url", To: "browser.legacy_url", Since: 1},
+}
+
+// MigrationReport describes what Migrate changed
+type MigrationReport struct {
+	FromVersion int
+	ToVersion   int
+	// Renamed lists moved settings as "old -> new"
+	Renamed []string
+	// Dropped lists deprecated settings discarded because the file also
+	// set their replacement
+	Dropped []string
+	// Added lists settings missing from the file that were filled with
+	// their defaults
+	Added []string
+	// Unknown lists settings this bridge does not recognise; they are not
+	// carried over
+	Unknown []string
+}
+
+// Changed reports whether migrating rewrites the file
+func (r *MigrationReport) Changed() bool {
+	return r.FromVersion != r.ToVersion || len(r.Renamed) > 0 || len(r.Dropped) > 0 ||
+		len(r.Added) > 0 || len(r.Unknown) > 0
+}
+
+// Migrate upgrades TOML config data to CurrentConfigVersion: deprecated
+// settings are moved to their replacements, missing settings get their
+// defaults and the result is stamped with the current version. Environment
+// overrides are not applied, so they are never written into the file.
+func Migrate(data []byte) (*Config, *MigrationReport, error) {
+	raw := make(map[string]interface{})
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	report := &MigrationReport{ToVersion: CurrentConfigVersion}
+	if v, ok := raw["version"].(int64); ok {
+		report.FromVersion = int(v)
+	}
+	if report.FromVersion > CurrentConfigVersion {
+		return nil, nil, fmt.Errorf("config version %d is newer than this bridge supports (%d)", report.FromVersion, CurrentConfigVersion)
+	}
+
+	for _, r := range configRenames {
+		if r.Since <= report.FromVersion {
+			continue
+		}
+		value, ok := takeKey(raw, r.From)
+		if !ok {
+			continue
+		}
+		if _, exists := lookupKey(raw, r.To); exists {
+			report.Dropped = append(report.Dropped, r.From+" (kept "+r.To+")")
+			continue
+		}
+		setKey(raw, r.To, value)
+		report.Renamed = append(report.Renamed, r.From+" -> "+r.To)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to re-encode config: %w", err)
+	}
+
+	cfg := DefaultConfig()
+	meta, err := toml.Decode(buf.String(), cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse migrated config: %w", err)
+	}
+	cfg.Version = CurrentConfigVersion
+
+	for _, key := range meta.Undecoded() {
+		report.Unknown = append(report.Unknown, key.String())
+	}
+
+	added, err := missingSettings(meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Added = added
+
+	return cfg, report, nil
+}
+
+// MigrateFile migrates the config file at path in place, keeping the
+// original as path+".bak". The file is left untouched when nothing changes
+// or when the migrated config does not validate.
+func MigrateFile(path string) (*MigrationReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg, report, err := Migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	if !report.Changed() {
+		return report, nil
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("migrated configuration is invalid: %w", err)
+	}
+	if err := os.WriteFile(path+".bak", data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config file: %w", err)
+	}
+	if err := Save(cfg, path); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// missingSettings returns the settings, by dotted key, that the current
+// schema defines but the decoded file did not set
+func missingSettings(meta toml.MetaData) ([]string, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(DefaultConfig()); err != nil {
+		return nil, fmt.Errorf("failed to encode default config: %w", err)
+	}
+	defaults, err := toml.Decode(buf.String(), &map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default config: %w", err)
+	}
+
+	var missing []string
+	for _, key := range defaults.Keys() {
+		// Tables are implied by their settings
+		if t := defaults.Type(key...); t == "Hash" || t == "ArrayHash" {
+			continue
+		}
+		if key.String() == "version" || meta.IsDefined(key...) || underArrayTable(defaults, key) || isRenamed(key.String()) {
+			continue
+		}
+		missing = append(missing, key.String())
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// isRenamed reports whether key is a deprecated setting
+func isRenamed(key string) bool {
+	for _, r := range configRenames {
+		if r.From == key {
+			return true
+		}
+	}
+	return false
+}
+
+// underArrayTable reports whether key belongs to an entry of an array of
+// tables, such as [[keystore.providers]], whose entries are not defaults
+func underArrayTable(meta toml.MetaData, key toml.Key) bool {
+	for i := 1; i < len(key); i++ {
+		if meta.Type(key[:i]...) == "ArrayHash" {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupKey returns the value at a dotted key in decoded TOML
+func lookupKey(raw map[string]interface{}, dotted string) (interface{}, bool) {
+	parts := strings.Split(dotted, ".")
+	table := raw
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := table[part].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		table = next
+	}
+	value, ok := table[parts[len(parts)-1]]
+	return value, ok
+}
+
+// takeKey removes and returns the value at a dotted key in decoded TOML
+func takeKey(raw map[string]interface{}, dotted string) (interface{}, bool) {
+	value, ok := lookupKey(raw, dotted)
+	if !ok {
+		return nil, false
+	}
+	parts := strings.Split(dotted, ".")
+	table := raw
+	for _, part := range parts[:len(parts)-1] {
+		table = table[part].(map[string]interface{})
+	}
+	delete(table, parts[len(parts)-1])
+	return value, true
+}
+
+// setKey stores a value at a dotted key in decoded TOML, creating tables
+// as needed
+func setKey(raw map[string]interface{}, dotted string, value interface{}) {
+	parts := strings.Split(dotted, ".")
+	table := raw
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := table[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			table[part] = next
+		}
+		table = next
+	}
+	table[parts[len(parts)-1]] = value
+}
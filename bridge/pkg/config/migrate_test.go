@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const legacyConfig = `
+[server]
+socket_path = "/run/armorclaw/bridge.sock"
+listen_port = 8080
+
+[browser]
+service_url = "http://browser.internal:3002"
+
+[[keystore.providers]]
+id = "openai-main"
+provider = "openai"
+token = "sk-test"
+`
+
+func TestMigrateRenamesAndFillsDefaults(t *testing.T) {
+	cfg, report, err := Migrate([]byte(legacyConfig))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	if report.FromVersion != 0 || report.ToVersion != CurrentConfigVersion || cfg.Version != CurrentConfigVersion {
+		t.Errorf("versions = %d -> %d (stamped %d), want 0 -> %d", report.FromVersion, report.ToVersion, cfg.Version, CurrentConfigVersion)
+	}
+	if want := []string{"browser.service_url -> browser.legacy_url"}; !reflect.DeepEqual(report.Renamed, want) {
+		t.Errorf("Renamed = %v, want %v", report.Renamed, want)
+	}
+	if cfg.Browser.LegacyURL != "http://browser.internal:3002" {
+		t.Errorf("LegacyURL = %q, want the old service_url", cfg.Browser.LegacyURL)
+	}
+	if want := []string{"server.listen_port"}; !reflect.DeepEqual(report.Unknown, want) {
+		t.Errorf("Unknown = %v, want %v", report.Unknown, want)
+	}
+
+	// Values from the file are kept, everything else is defaulted
+	if cfg.Server.SocketPath != "/run/armorclaw/bridge.sock" {
+		t.Errorf("SocketPath = %q, want value from file", cfg.Server.SocketPath)
+	}
+	if len(cfg.Keystore.Providers) != 1 || cfg.Keystore.Providers[0].ID != "openai-main" {
+		t.Errorf("Providers = %+v, want the openai-main entry", cfg.Keystore.Providers)
+	}
+	added := make(map[string]bool)
+	for _, key := range report.Added {
+		added[key] = true
+	}
+	for _, key := range []string{"logging.level", "matrix.token_refresh_margin", "eventbus.replay_backlog"} {
+		if !added[key] {
+			t.Errorf("%s not reported as added", key)
+		}
+	}
+	for _, key := range []string{"server.socket_path", "browser.legacy_url", "browser.service_url", "keystore.providers.id", "version"} {
+		if added[key] {
+			t.Errorf("%s reported as added", key)
+		}
+	}
+}
+
+func TestMigrateKeepsExplicitReplacement(t *testing.T) {
+	cfg, report, err := Migrate([]byte(`
+[browser]
+service_url = "http://old:3002"
+legacy_url = "http://new:3002"
+`))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if len(report.Renamed) != 0 || len(report.Dropped) != 1 {
+		t.Errorf("Renamed = %v, Dropped = %v; want none renamed, one dropped", report.Renamed, report.Dropped)
+	}
+	if cfg.Browser.LegacyURL != "http://new:3002" {
+		t.Errorf("LegacyURL = %q, want the explicit legacy_url", cfg.Browser.LegacyURL)
+	}
+}
+
+func TestMigrateRejectsNewerVersion(t *testing.T) {
+	if _, _, err := Migrate([]byte("version = 99\n")); err == nil {
+		t.Error("Migrate() accepted a config from a newer bridge")
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(legacyConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := MigrateFile(path)
+	if err != nil {
+		t.Fatalf("MigrateFile() error = %v", err)
+	}
+	if !report.Changed() {
+		t.Fatal("MigrateFile() reported no changes for a version 0 config")
+	}
+
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != legacyConfig {
+		t.Errorf("backup = %q, %v; want the original file", backup, err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of migrated file error = %v", err)
+	}
+	if cfg.Version != CurrentConfigVersion || cfg.Browser.LegacyURL != "http://browser.internal:3002" {
+		t.Errorf("migrated file has version %d, legacy_url %q", cfg.Version, cfg.Browser.LegacyURL)
+	}
+
+	// A second run finds nothing to do and leaves the file alone
+	before, _ := os.ReadFile(path)
+	report, err = MigrateFile(path)
+	if err != nil {
+		t.Fatalf("second MigrateFile() error = %v", err)
+	}
+	if report.Changed() {
+		t.Errorf("second migration changed: %+v", report)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Error("second migration rewrote the file")
+	}
+}
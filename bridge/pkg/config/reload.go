@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// hotReloadable lists the settings a running bridge applies on reload
+// (SIGHUP) without a restart, by TOML key
+var hotReloadable = map[string]bool{
+	"logging.level":            true,
+	"budget.daily_limit_usd":   true,
+	"budget.monthly_limit_usd": true,
+	"budget.alert_threshold":   true,
+	"budget.hard_stop":         true,
+	"budget.provider_costs":    true,
+	"errors.rate_limit_window": true,
+	"errors.code_windows":      true,
+	"errors.admin_mxid":        true,
+	// The schema stamp has nothing to apply
+	"version": true,
+}
+
+// IsHotReloadable reports whether a setting, by TOML key such as
+// "logging.level", is applied on reload without a restart
+func IsHotReloadable(key string) bool {
+	return hotReloadable[key]
+}
+
+// RestartRequired returns the TOML keys of settings that differ between the
+// running and reloaded configuration but only take effect after a restart,
+// in file order
+func RestartRequired(current, next *Config) []string {
+	var keys []string
+
+	cur := reflect.ValueOf(current).Elem()
+	nxt := reflect.ValueOf(next).Elem()
+	for i := 0; i < cur.NumField(); i++ {
+		section := tomlKey(cur.Type().Field(i))
+		curSection, nxtSection := cur.Field(i), nxt.Field(i)
+
+		if curSection.Kind() != reflect.Struct {
+			if !reflect.DeepEqual(curSection.Interface(), nxtSection.Interface()) && !hotReloadable[section] {
+				keys = append(keys, section)
+			}
+			continue
+		}
+
+		for j := 0; j < curSection.NumField(); j++ {
+			key := section + "." + tomlKey(curSection.Type().Field(j))
+			if hotReloadable[key] {
+				continue
+			}
+			if !reflect.DeepEqual(curSection.Field(j).Interface(), nxtSection.Field(j).Interface()) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+// tomlKey returns the TOML name of a struct field, falling back to the
+// lower-cased field name
+func tomlKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("toml"), ",")
+	if name == "" || name == "-" {
+		return strings.ToLower(field.Name)
+	}
+	return name
+}
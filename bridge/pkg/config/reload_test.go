@@ -0,0 +1,43 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRestartRequired(t *testing.T) {
+	current := DefaultConfig()
+	next := DefaultConfig()
+
+	if keys := RestartRequired(current, next); len(keys) != 0 {
+		t.Errorf("RestartRequired() for identical configs = %v, want none", keys)
+	}
+
+	// Hot-reloadable settings never require a restart
+	next.Logging.Level = "debug"
+	next.Budget.DailyLimitUSD = 42
+	next.Budget.ProviderCosts = map[string]float64{"gpt-4": 20}
+	next.ErrorSystem.RateLimitWindow = "10m"
+	next.ErrorSystem.CodeWindows = map[string]string{"CTX": "1m"}
+	next.ErrorSystem.AdminMXID = "@ops:example.com"
+	if keys := RestartRequired(current, next); len(keys) != 0 {
+		t.Errorf("RestartRequired() for hot-reloadable changes = %v, want none", keys)
+	}
+
+	next.Server.SocketPath = "/tmp/other.sock"
+	next.Logging.Format = "json"
+	next.Budget.ResetSchedule = "weekly"
+	want := []string{"server.socket_path", "budget.reset_schedule", "logging.format"}
+	if keys := RestartRequired(current, next); !reflect.DeepEqual(keys, want) {
+		t.Errorf("RestartRequired() = %v, want %v", keys, want)
+	}
+}
+
+func TestIsHotReloadable(t *testing.T) {
+	if !IsHotReloadable("logging.level") || !IsHotReloadable("errors.admin_mxid") {
+		t.Error("logging.level and errors.admin_mxid should be hot-reloadable")
+	}
+	if IsHotReloadable("server.socket_path") {
+		t.Error("server.socket_path should require a restart")
+	}
+}
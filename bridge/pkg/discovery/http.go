@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -22,6 +23,8 @@ type HTTPServer struct {
 	metrics  *rpc.Metrics
 	running  bool
 	shutdown context.CancelFunc
+
+	advertiseIP net.IP
 }
 
 // HTTPServerConfig configures the HTTP discovery server
@@ -34,6 +37,7 @@ type HTTPServerConfig struct {
 	APIPath          string
 	WSPath           string
 	Metrics          *rpc.Metrics
+	AdvertiseIP      net.IP // Pins the address in api_url/ws_url (default: the default route's)
 }
 
 // NewHTTPServer creates a new HTTP discovery server
@@ -76,8 +80,9 @@ func NewHTTPServer(config HTTPServerConfig) (*HTTPServer, error) {
 	}
 
 	return &HTTPServer{
-		info:    info,
-		metrics: config.Metrics,
+		info:        info,
+		metrics:     config.Metrics,
+		advertiseIP: config.AdvertiseIP,
 	}, nil
 }
 
@@ -175,9 +180,13 @@ func (s *HTTPServer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
 		protocol = "https"
 	}
 
+	// Prefer an address clients can reach over the instance name, which
+	// usually doesn't resolve
 	hostname := info.Name
 	if info.Host != "" {
 		hostname = info.Host
+	} else if ip, err := PrimaryIP(s.advertiseIP); err == nil {
+		hostname = URLHost(ip)
 	}
 
 	response := map[string]interface{}{
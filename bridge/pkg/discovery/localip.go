@@ -0,0 +1,151 @@
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// containerInterfacePrefixes name virtual interfaces created by container
+// runtimes and hypervisors. Their addresses are only reachable from the host,
+// so advertising them sends clients to e.g. 172.17.0.1.
+var containerInterfacePrefixes = []string{
+	"docker", "br-", "veth", "cni", "flannel", "cali", "virbr", "vmnet", "podman",
+}
+
+// routeProbeAddr is any routable address. Connecting a UDP socket to it
+// selects the default route without sending packets.
+const routeProbeAddr = "192.0.2.1:9"
+
+// localAddr is a local address and the interface it belongs to
+type localAddr struct {
+	ip    net.IP
+	iface string
+}
+
+// LocalIPs returns the addresses to advertise to clients, best first. A
+// pinned address is returned on its own. Otherwise the address of the default
+// route comes first and container bridge addresses are left out.
+func LocalIPs(pinned net.IP) ([]net.IP, error) {
+	if pinned != nil {
+		return []net.IP{pinned}, nil
+	}
+
+	addrs, err := interfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	ips := preferIPs(addrs, defaultRouteIP())
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no suitable IP addresses found")
+	}
+	return ips, nil
+}
+
+// PrimaryIP returns the single address clients should connect to
+func PrimaryIP(pinned net.IP) (net.IP, error) {
+	ips, err := LocalIPs(pinned)
+	if err != nil {
+		return nil, err
+	}
+	return ips[0], nil
+}
+
+// URLHost formats ip for the host part of a URL, bracketing IPv6 addresses
+func URLHost(ip net.IP) string {
+	if ip.To4() == nil {
+		return "[" + ip.String() + "]"
+	}
+	return ip.String()
+}
+
+// interfaceAddrs lists the non-loopback, non-link-local addresses of the
+// interfaces that are up
+func interfaceAddrs() ([]localAddr, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []localAddr
+	for _, iface := range interfaces {
+		// Skip loopback and interfaces that are down
+		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
+			continue
+		}
+
+		ifaceAddrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range ifaceAddrs {
+			var ip net.IP
+			switch v := addr.(type) {
+			case *net.IPNet:
+				ip = v.IP
+			case *net.IPAddr:
+				ip = v.IP
+			}
+
+			// Skip loopback and link-local
+			if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				continue
+			}
+
+			addrs = append(addrs, localAddr{ip: ip, iface: iface.Name})
+		}
+	}
+
+	return addrs, nil
+}
+
+// defaultRouteIP returns the local address the kernel uses for the default
+// route, or nil if there is none
+func defaultRouteIP() net.IP {
+	conn, err := net.Dial("udp4", routeProbeAddr)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
+
+// preferIPs orders addrs with primary first and drops container bridge
+// addresses. If only container addresses exist they are kept, since the
+// bridge may itself run in a container.
+func preferIPs(addrs []localAddr, primary net.IP) []net.IP {
+	var first, rest, container []net.IP
+	for _, addr := range addrs {
+		switch {
+		case primary != nil && addr.ip.Equal(primary):
+			first = append(first, addr.ip)
+		case isContainerInterface(addr.iface):
+			container = append(container, addr.ip)
+		default:
+			rest = append(rest, addr.ip)
+		}
+	}
+
+	ips := append(first, rest...)
+	if len(ips) == 0 {
+		return container
+	}
+	return ips
+}
+
+// isContainerInterface reports whether name looks like a container runtime or
+// hypervisor bridge
+func isContainerInterface(name string) bool {
+	for _, prefix := range containerInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
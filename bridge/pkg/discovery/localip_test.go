@@ -0,0 +1,71 @@
+package discovery
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPreferIPs(t *testing.T) {
+	addrs := []localAddr{
+		{ip: net.ParseIP("172.17.0.1"), iface: "docker0"},
+		{ip: net.ParseIP("172.18.0.1"), iface: "br-3f2a9c"},
+		{ip: net.ParseIP("10.0.0.5"), iface: "ens4"},
+		{ip: net.ParseIP("203.0.113.7"), iface: "eth0"},
+	}
+
+	ips := preferIPs(addrs, net.ParseIP("203.0.113.7"))
+	want := []string{"203.0.113.7", "10.0.0.5"}
+	if len(ips) != len(want) {
+		t.Fatalf("expected %v, got %v", want, ips)
+	}
+	for i, ip := range ips {
+		if ip.String() != want[i] {
+			t.Errorf("position %d: expected %s, got %s", i, want[i], ip)
+		}
+	}
+
+	// Without a default route the interface order is kept
+	if ips := preferIPs(addrs, nil); len(ips) != 2 || ips[0].String() != "10.0.0.5" {
+		t.Errorf("expected non-container addresses in order, got %v", ips)
+	}
+
+	// Inside a container only bridge-like addresses may exist
+	only := []localAddr{{ip: net.ParseIP("172.17.0.1"), iface: "docker0"}}
+	if ips := preferIPs(only, nil); len(ips) != 1 {
+		t.Errorf("expected container address kept as a last resort, got %v", ips)
+	}
+}
+
+func TestLocalIPsPinned(t *testing.T) {
+	pinned := net.ParseIP("198.51.100.20")
+
+	ips, err := LocalIPs(pinned)
+	if err != nil {
+		t.Fatalf("LocalIPs failed: %v", err)
+	}
+	if len(ips) != 1 || !ips[0].Equal(pinned) {
+		t.Errorf("expected only the pinned address, got %v", ips)
+	}
+}
+
+func TestIsContainerInterface(t *testing.T) {
+	for _, name := range []string{"docker0", "br-3f2a9c", "veth12ab", "cni0", "virbr0"} {
+		if !isContainerInterface(name) {
+			t.Errorf("expected %s to be a container interface", name)
+		}
+	}
+	for _, name := range []string{"eth0", "ens4", "wlan0", "enp3s0"} {
+		if isContainerInterface(name) {
+			t.Errorf("expected %s not to be a container interface", name)
+		}
+	}
+}
+
+func TestURLHost(t *testing.T) {
+	if got := URLHost(net.ParseIP("192.168.1.10")); got != "192.168.1.10" {
+		t.Errorf("expected plain IPv4, got %s", got)
+	}
+	if got := URLHost(net.ParseIP("2001:db8::1")); got != "[2001:db8::1]" {
+		t.Errorf("expected bracketed IPv6, got %s", got)
+	}
+}
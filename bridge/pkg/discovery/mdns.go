@@ -4,6 +4,7 @@ package discovery
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net"
 	"os"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/mdns"
+	"github.com/miekg/dns"
 )
 
 const (
@@ -29,6 +31,16 @@ const (
 
 	// DiscoveryTimeout is how long to wait for discovery responses
 	DiscoveryTimeout = 5 * time.Second
+
+	// DefaultAnnounceInterval is how often the service is re-announced. It is
+	// half the record TTL, so caches are refreshed before records go stale.
+	DefaultAnnounceInterval = 60 * time.Second
+)
+
+// mDNS multicast groups that announcements are sent to
+var (
+	mdnsIPv4Group = &net.UDPAddr{IP: net.ParseIP("224.0.0.251"), Port: 5353}
+	mdnsIPv6Group = &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: 5353}
 )
 
 // BridgeInfo contains discovered bridge information
@@ -54,9 +66,40 @@ type BridgeInfo struct {
 type Server struct {
 	mu       sync.RWMutex
 	server   *mdns.Server
+	zone     *serviceZone
 	info     *BridgeInfo
 	running  bool
 	shutdown context.CancelFunc
+
+	// Inputs needed to rebuild the service when the local IPs change
+	instanceName     string
+	port             int
+	txt              []string
+	announceInterval time.Duration
+
+	// Overridable in tests
+	localIPs func() ([]net.IP, error)
+	announce func(packet []byte) error
+}
+
+// serviceZone serves the current service records. The mdns server keeps the
+// zone it was created with, so records are swapped here instead.
+type serviceZone struct {
+	mu      sync.RWMutex
+	service *mdns.MDNSService
+}
+
+// Records implements mdns.Zone
+func (z *serviceZone) Records(q dns.Question) []dns.RR {
+	z.mu.RLock()
+	defer z.mu.RUnlock()
+	return z.service.Records(q)
+}
+
+func (z *serviceZone) set(service *mdns.MDNSService) {
+	z.mu.Lock()
+	z.service = service
+	z.mu.Unlock()
 }
 
 // ServerConfig contains configuration for the mDNS server
@@ -77,6 +120,11 @@ type ServerConfig struct {
 	WSPath string
 	// ExtraTXT contains additional TXT records
 	ExtraTXT map[string]string
+	// AnnounceInterval is how often the service is re-announced and the local
+	// IPs rechecked (default: DefaultAnnounceInterval, negative disables)
+	AnnounceInterval time.Duration
+	// AdvertiseIP pins the advertised address (default: the default route's)
+	AdvertiseIP net.IP
 }
 
 // NewServer creates a new mDNS advertisement server
@@ -111,7 +159,8 @@ func NewServerWithConfig(config ServerConfig) (*Server, error) {
 	}
 
 	// Get local IPs
-	ips, err := getLocalIPs()
+	localIPs := func() ([]net.IP, error) { return LocalIPs(config.AdvertiseIP) }
+	ips, err := localIPs()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get local IPs: %w", err)
 	}
@@ -145,21 +194,14 @@ func NewServerWithConfig(config ServerConfig) (*Server, error) {
 	}
 
 	// Create mDNS service
-	service, err := mdns.NewMDNSService(
-		instanceName,
-		ServiceName,
-		ServiceDomain,
-		"",
-		port,
-		ips,
-		txt,
-	)
+	service, err := newService(instanceName, port, ips, txt)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create mDNS service: %w", err)
+		return nil, err
 	}
+	zone := &serviceZone{service: service}
 
 	// Create server
-	server, err := mdns.NewServer(&mdns.Config{Zone: service})
+	server, err := mdns.NewServer(&mdns.Config{Zone: zone})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create mDNS server: %w", err)
 	}
@@ -200,13 +242,42 @@ func NewServerWithConfig(config ServerConfig) (*Server, error) {
 		}
 	}
 
+	announceInterval := config.AnnounceInterval
+	if announceInterval == 0 {
+		announceInterval = DefaultAnnounceInterval
+	}
+
 	return &Server{
-		server:  server,
-		info:    info,
-		running: false,
+		server:           server,
+		zone:             zone,
+		info:             info,
+		running:          false,
+		instanceName:     instanceName,
+		port:             port,
+		txt:              txt,
+		announceInterval: announceInterval,
+		localIPs:         localIPs,
+		announce:         multicastAnnounce,
 	}, nil
 }
 
+// newService builds the mDNS records for the bridge
+func newService(instanceName string, port int, ips []net.IP, txt []string) (*mdns.MDNSService, error) {
+	service, err := mdns.NewMDNSService(
+		instanceName,
+		ServiceName,
+		ServiceDomain,
+		"",
+		port,
+		ips,
+		txt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mDNS service: %w", err)
+	}
+	return service, nil
+}
+
 // Start begins advertising the bridge service
 func (s *Server) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -230,9 +301,125 @@ func (s *Server) Start(ctx context.Context) error {
 		s.mu.Unlock()
 	}()
 
+	if s.announceInterval > 0 {
+		go s.announceLoop(ctx)
+	}
+
 	return nil
 }
 
+// announceLoop periodically re-announces the service so networks that drop
+// stale records keep finding the bridge, and picks up IP changes such as a
+// DHCP renewal
+func (s *Server) announceLoop(ctx context.Context) {
+	s.refresh()
+
+	ticker := time.NewTicker(s.announceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.refresh()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refresh rebuilds the records if the local IPs changed, then announces them
+func (s *Server) refresh() {
+	ips, err := s.localIPs()
+	if err != nil {
+		slog.Warn("mDNS failed to get local IPs", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	if !sameIPs(ips, s.info.IPs) {
+		service, err := newService(s.instanceName, s.port, ips, s.txt)
+		if err != nil {
+			s.mu.Unlock()
+			slog.Warn("mDNS failed to update records", "error", err)
+			return
+		}
+		s.zone.set(service)
+		s.info.IPs = ips
+		slog.Info("mDNS local IPs changed", "ips", ips)
+	}
+	s.mu.Unlock()
+
+	packet, err := s.announcement()
+	if err != nil {
+		slog.Warn("mDNS failed to build announcement", "error", err)
+		return
+	}
+	if err := s.announce(packet); err != nil {
+		slog.Warn("mDNS announcement failed", "error", err)
+	}
+}
+
+// announcement builds an unsolicited mDNS response carrying the PTR, SRV, TXT
+// and address records, as a browse query for the service would receive
+func (s *Server) announcement() ([]byte, error) {
+	msg := new(dns.Msg)
+	msg.Response = true
+	msg.Authoritative = true
+	msg.Answer = s.zone.Records(dns.Question{
+		Name:   ServiceName + ServiceDomain,
+		Qtype:  dns.TypePTR,
+		Qclass: dns.ClassINET,
+	})
+	return msg.Pack()
+}
+
+// multicastAnnounce sends an announcement to the mDNS groups. It succeeds if
+// either address family gets through.
+func multicastAnnounce(packet []byte) error {
+	var errs []string
+	sent := false
+	for _, group := range []*net.UDPAddr{mdnsIPv4Group, mdnsIPv6Group} {
+		network := "udp4"
+		if group.IP.To4() == nil {
+			network = "udp6"
+		}
+		conn, err := net.DialUDP(network, nil, group)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		_, err = conn.Write(packet)
+		conn.Close()
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		sent = true
+	}
+	if !sent {
+		return fmt.Errorf("no multicast group reachable: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// sameIPs reports whether a and b hold the same addresses in any order
+func sameIPs(a, b []net.IP) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, ip := range a {
+		seen[ip.String()]++
+	}
+	for _, ip := range b {
+		if seen[ip.String()] == 0 {
+			return false
+		}
+		seen[ip.String()]--
+	}
+	return true
+}
+
 // Stop stops advertising the bridge service
 func (s *Server) Stop() error {
 	s.mu.Lock()
@@ -431,51 +618,6 @@ func parseEntry(entry *mdns.ServiceEntry) BridgeInfo {
 	return info
 }
 
-// getLocalIPs returns all local IP addresses
-func getLocalIPs() ([]net.IP, error) {
-	var ips []net.IP
-
-	interfaces, err := net.Interfaces()
-	if err != nil {
-		return nil, err
-	}
-
-	for _, iface := range interfaces {
-		// Skip loopback and interfaces that are down
-		if iface.Flags&net.FlagLoopback != 0 || iface.Flags&net.FlagUp == 0 {
-			continue
-		}
-
-		addrs, err := iface.Addrs()
-		if err != nil {
-			continue
-		}
-
-		for _, addr := range addrs {
-			var ip net.IP
-			switch v := addr.(type) {
-			case *net.IPNet:
-				ip = v.IP
-			case *net.IPAddr:
-				ip = v.IP
-			}
-
-			// Skip loopback and link-local
-			if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
-				continue
-			}
-
-			ips = append(ips, ip)
-		}
-	}
-
-	if len(ips) == 0 {
-		return nil, fmt.Errorf("no suitable IP addresses found")
-	}
-
-	return ips, nil
-}
-
 // ManualConnection represents a manually specified bridge connection
 type ManualConnection struct {
 	Host string `json:"host"`
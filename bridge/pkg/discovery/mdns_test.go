@@ -0,0 +1,136 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// newTestServer builds a Server without binding the mDNS sockets
+func newTestServer(t *testing.T, ips []net.IP) *Server {
+	t.Helper()
+
+	txt := []string{"version=0.2.0", "tls=false"}
+	service, err := newService("test-bridge", DefaultPort, ips, txt)
+	if err != nil {
+		t.Fatalf("newService failed: %v", err)
+	}
+
+	return &Server{
+		zone:             &serviceZone{service: service},
+		info:             &BridgeInfo{Name: "test-bridge", Port: DefaultPort, IPs: ips, TXT: map[string]string{}},
+		instanceName:     "test-bridge",
+		port:             DefaultPort,
+		txt:              txt,
+		announceInterval: 10 * time.Millisecond,
+		localIPs:         func() ([]net.IP, error) { return ips, nil },
+		announce:         func([]byte) error { return nil },
+	}
+}
+
+// addressRecords returns the A record addresses in an announcement
+func addressRecords(t *testing.T, packet []byte) []string {
+	t.Helper()
+
+	msg := new(dns.Msg)
+	if err := msg.Unpack(packet); err != nil {
+		t.Fatalf("announcement does not parse: %v", err)
+	}
+	if !msg.Response {
+		t.Error("announcement should be a response")
+	}
+
+	var addrs []string
+	hasPTR, hasTXT := false, false
+	for _, rr := range msg.Answer {
+		switch r := rr.(type) {
+		case *dns.PTR:
+			hasPTR = true
+		case *dns.TXT:
+			hasTXT = true
+		case *dns.A:
+			addrs = append(addrs, r.A.String())
+		}
+	}
+	if !hasPTR || !hasTXT {
+		t.Errorf("announcement missing PTR or TXT records: %v", msg.Answer)
+	}
+	return addrs
+}
+
+func TestAnnounceLoop(t *testing.T) {
+	server := newTestServer(t, []net.IP{net.ParseIP("192.168.1.10")})
+
+	packets := make(chan []byte, 10)
+	server.announce = func(packet []byte) error {
+		select {
+		case packets <- packet:
+		default:
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// One announcement on start, then one per interval
+	for i := 0; i < 2; i++ {
+		select {
+		case packet := <-packets:
+			if addrs := addressRecords(t, packet); len(addrs) != 1 || addrs[0] != "192.168.1.10" {
+				t.Errorf("expected A record for 192.168.1.10, got %v", addrs)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("announcement %d not sent", i+1)
+		}
+	}
+}
+
+func TestRefreshUpdatesRecordsOnIPChange(t *testing.T) {
+	server := newTestServer(t, []net.IP{net.ParseIP("192.168.1.10")})
+
+	var last []byte
+	server.announce = func(packet []byte) error {
+		last = packet
+		return nil
+	}
+
+	// Simulate a DHCP renewal handing out a new address
+	renewed := []net.IP{net.ParseIP("192.168.1.42")}
+	server.localIPs = func() ([]net.IP, error) { return renewed, nil }
+	server.refresh()
+
+	if addrs := addressRecords(t, last); len(addrs) != 1 || addrs[0] != "192.168.1.42" {
+		t.Errorf("expected records for the renewed address, got %v", addrs)
+	}
+	if ips := server.Info().IPs; !sameIPs(ips, renewed) {
+		t.Errorf("expected info to report %v, got %v", renewed, ips)
+	}
+
+	// TXT records survive the rebuild
+	txt := server.zone.Records(dns.Question{Name: "test-bridge." + ServiceName + ServiceDomain, Qtype: dns.TypeTXT})
+	if len(txt) != 1 || txt[0].(*dns.TXT).Txt[0] != "version=0.2.0" {
+		t.Errorf("expected TXT records to be kept, got %v", txt)
+	}
+}
+
+func TestSameIPs(t *testing.T) {
+	a := []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("fd00::1")}
+	b := []net.IP{net.ParseIP("fd00::1"), net.ParseIP("10.0.0.1")}
+
+	if !sameIPs(a, b) {
+		t.Error("expected equal sets in different order to match")
+	}
+	if sameIPs(a, a[:1]) {
+		t.Error("expected different lengths not to match")
+	}
+	if sameIPs(a, []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("fd00::2")}) {
+		t.Error("expected different addresses not to match")
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/armorclaw/bridge/pkg/audit"
@@ -17,8 +19,10 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -95,15 +99,17 @@ type Client struct {
 	client        *client.Client
 	scopes        map[Scope]bool
 	latencyTarget time.Duration
+	defaultLimits ResourceLimits
 	auditLogger   *audit.CriticalOperationLogger
 }
 
 // Config holds client configuration
 type Config struct {
-	Host          string        // Docker daemon address
-	APIVersion    string        // API version
-	Scopes        []Scope       // Allowed operations
-	LatencyTarget time.Duration // Target latency for operations
+	Host          string         // Docker daemon address
+	APIVersion    string         // API version
+	Scopes        []Scope        // Allowed operations
+	LatencyTarget time.Duration  // Target latency for operations
+	DefaultLimits ResourceLimits // Limits for containers created without their own
 }
 
 // New creates a new restricted Docker client
@@ -117,6 +123,9 @@ func New(cfg Config) (*Client, error) {
 	if cfg.LatencyTarget == 0 {
 		cfg.LatencyTarget = 15 * time.Millisecond
 	}
+	if err := cfg.DefaultLimits.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid default resource limits: %w", err)
+	}
 
 	// Create Docker client
 	cli, err := client.NewClientWithOpts(
@@ -144,6 +153,7 @@ func New(cfg Config) (*Client, error) {
 		client:        cli,
 		scopes:        scopes,
 		latencyTarget: cfg.LatencyTarget,
+		defaultLimits: cfg.DefaultLimits,
 	}, nil
 }
 
@@ -220,6 +230,9 @@ func (c *Client) CreateContainer(ctx context.Context, config *container.Config,
 		hostConfig.NetworkMode = "none"
 	}
 
+	// Cap memory and CPU so one agent can't starve the host
+	ApplyDefaultLimits(hostConfig, c.defaultLimits)
+
 	// Create container with timeout
 	ctx, cancel := context.WithTimeout(ctx, c.latencyTarget)
 	defer cancel()
@@ -666,6 +679,21 @@ func (c *Client) CreateAndStartContainer(ctx context.Context, config *container.
 		return "", err
 	}
 
+	// Pull outside CreateContainer's latency budget
+	if imageName != "" {
+		if err := c.EnsureImage(ctx, imageName); err != nil {
+			if c.auditLogger != nil {
+				code := "CTX-020"
+				var traced *errsys.TracedError
+				if errors.As(err, &traced) {
+					code = traced.Code
+				}
+				c.auditLogger.LogContainerError(ctx, "", err.Error(), code)
+			}
+			return "", err
+		}
+	}
+
 	containerID, err := c.CreateContainer(ctx, config, hostConfig, networkingConfig, platform)
 	if err != nil {
 		// Log container error to audit
@@ -783,6 +811,158 @@ func (c *Client) ImageExists(ctx context.Context, image string) (bool, error) {
 	return false, wrappedErr
 }
 
+const (
+	// imagePullTimeout bounds a single image pull; layers for agent images
+	// can be large, so this is far above latencyTarget
+	imagePullTimeout = 10 * time.Minute
+
+	// imagePullProgressInterval throttles pull progress reports
+	imagePullProgressInterval = 5 * time.Second
+)
+
+// PullProgress is a snapshot of an image pull summed across its layers
+type PullProgress struct {
+	Image   string // Image reference being pulled
+	Status  string // Latest status line from the daemon
+	Current int64  // Bytes downloaded so far
+	Total   int64  // Total bytes across layers with a known size
+	Layers  int    // Layers seen so far
+}
+
+// EnsureImage pulls image if it is not present locally, so container creation
+// does not fail on a missing image
+// Scope required: ScopeCreate
+func (c *Client) EnsureImage(ctx context.Context, image string) error {
+	exists, err := c.ImageExists(ctx, image)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return c.PullImage(ctx, image)
+}
+
+// PullImage pulls image from its registry, logging progress while layers
+// download. Registry authentication failures are reported as CTX-022.
+// Scope required: ScopeCreate
+func (c *Client) PullImage(ctx context.Context, ref string) error {
+	dockerTracker.Event("image_pull", map[string]any{"image": ref})
+
+	if c.client == nil {
+		err := errsys.NewBuilder("CTX-010").
+			Wrap(fmt.Errorf("docker client not initialized")).
+			WithFunction("PullImage").
+			WithInputs(map[string]any{"image": ref}).
+			Build()
+		dockerTracker.Failure("image_pull", err, map[string]any{"reason": "client_not_initialized"})
+		return err
+	}
+	if !c.hasScope(ScopeCreate) {
+		err := errsys.NewBuilder("CTX-001").
+			Wrap(ErrInvalidOperation).
+			WithFunction("PullImage").
+			WithInputs(map[string]any{"image": ref}).
+			Build()
+		dockerTracker.Failure("image_pull", err, map[string]any{"reason": "invalid_scope"})
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, imagePullTimeout)
+	defer cancel()
+
+	started := time.Now()
+	slog.Info("image_pull_started", "image", ref)
+
+	reader, err := c.client.ImagePull(ctx, ref, image.PullOptions{})
+	if err == nil {
+		err = readPullProgress(reader, ref, reportPullProgress)
+		reader.Close()
+	}
+	if err != nil {
+		code := pullErrorCode(err)
+		wrappedErr := errsys.NewBuilder(code).
+			Wrap(err).
+			WithFunction("PullImage").
+			WithInputs(map[string]any{"image": ref}).
+			WithState(map[string]any{"elapsed": time.Since(started).String()}).
+			Build()
+		dockerTracker.Failure("image_pull", wrappedErr, map[string]any{"reason": "pull_failed", "code": code})
+		slog.Error("image_pull_failed", "image", ref, "code", code, "error", err)
+		return wrappedErr
+	}
+
+	dockerTracker.Success("image_pull", map[string]any{"image": ref, "duration_ms": time.Since(started).Milliseconds()})
+	slog.Info("image_pull_completed", "image", ref, "duration", time.Since(started).Round(time.Millisecond).String())
+	return nil
+}
+
+// reportPullProgress logs a pull progress snapshot
+func reportPullProgress(p PullProgress) {
+	slog.Info("image_pull_progress", "image", p.Image, "status", p.Status, "layers", p.Layers, "current_bytes", p.Current, "total_bytes", p.Total)
+	dockerTracker.Event("image_pull_progress", map[string]any{"image": p.Image, "current": p.Current, "total": p.Total})
+}
+
+// readPullProgress decodes the daemon's JSON pull stream, calling report at
+// most once per imagePullProgressInterval. An error message in the stream
+// fails the pull even though the HTTP request itself succeeded.
+func readPullProgress(r io.Reader, ref string, report func(PullProgress)) error {
+	decoder := json.NewDecoder(r)
+	layers := make(map[string]*jsonmessage.JSONProgress)
+	var order []string
+	var lastReport time.Time
+
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read pull progress: %w", err)
+		}
+
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if msg.ErrorMessage != "" {
+			return errors.New(msg.ErrorMessage)
+		}
+
+		if msg.ID != "" && msg.Progress != nil {
+			if _, seen := layers[msg.ID]; !seen {
+				order = append(order, msg.ID)
+			}
+			progress := *msg.Progress
+			layers[msg.ID] = &progress
+		}
+
+		if time.Since(lastReport) < imagePullProgressInterval {
+			continue
+		}
+		lastReport = time.Now()
+
+		snapshot := PullProgress{Image: ref, Status: msg.Status, Layers: len(order)}
+		for _, id := range order {
+			snapshot.Current += layers[id].Current
+			snapshot.Total += layers[id].Total
+		}
+		report(snapshot)
+	}
+}
+
+// pullErrorCode maps a registry or daemon pull error to a CTX error code
+func pullErrorCode(err error) string {
+	errStr := strings.ToLower(err.Error())
+	switch {
+	case containsAny(errStr, "unauthorized", "authentication required", "no basic auth credentials", "access denied", "docker login"):
+		return "CTX-022"
+	case containsAny(errStr, "manifest unknown", "not found", "does not exist", "invalid reference format"):
+		return "CTX-021"
+	default:
+		return "CTX-020"
+	}
+}
+
 // isRetryableError checks if an error is retryable (transient network issues, temporary daemon issues)
 func isRetryableError(err error) bool {
 	if err == nil {
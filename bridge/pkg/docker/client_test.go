@@ -2,6 +2,8 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/docker/docker/api/types/container"
@@ -97,3 +99,109 @@ func TestTerminateContainer_NonExistent(t *testing.T) {
 		t.Error("Expected error when terminating non-existent container, got nil")
 	}
 }
+
+// TestReadPullProgress tests decoding of the daemon's pull stream
+func TestReadPullProgress(t *testing.T) {
+	stream := `{"status":"Pulling from library/alpine","id":"latest"}
+{"status":"Downloading","progressDetail":{"current":512,"total":2048},"id":"layer1"}
+{"status":"Downloading","progressDetail":{"current":1024,"total":4096},"id":"layer2"}
+{"status":"Downloading","progressDetail":{"current":2048,"total":2048},"id":"layer1"}
+{"status":"Status: Downloaded newer image for alpine:latest"}
+`
+	var reports []PullProgress
+	if err := readPullProgress(strings.NewReader(stream), "alpine:latest", func(p PullProgress) {
+		reports = append(reports, p)
+	}); err != nil {
+		t.Fatalf("readPullProgress failed: %v", err)
+	}
+
+	// Reports are throttled, so only the first message is reported
+	if len(reports) != 1 || reports[0].Image != "alpine:latest" {
+		t.Fatalf("expected one throttled report, got %+v", reports)
+	}
+}
+
+// TestReadPullProgress_StreamError tests that an error in the stream fails the pull
+func TestReadPullProgress_StreamError(t *testing.T) {
+	stream := `{"status":"Pulling from private/agent","id":"latest"}
+{"errorDetail":{"message":"unauthorized: authentication required"},"error":"unauthorized: authentication required"}
+`
+	err := readPullProgress(strings.NewReader(stream), "private/agent:latest", func(PullProgress) {})
+	if err == nil {
+		t.Fatal("expected stream error to fail the pull")
+	}
+	if code := pullErrorCode(err); code != "CTX-022" {
+		t.Errorf("expected CTX-022 for registry auth failure, got %s", code)
+	}
+}
+
+// TestPullErrorCode tests mapping of pull errors to error codes
+func TestPullErrorCode(t *testing.T) {
+	tests := []struct {
+		err  string
+		code string
+	}{
+		{"Error response from daemon: pull access denied for private/agent, repository does not exist or may require 'docker login'", "CTX-022"},
+		{"Error response from daemon: Get https://registry/v2/: unauthorized: authentication required", "CTX-022"},
+		{"Error response from daemon: manifest for alpine:nope not found: manifest unknown", "CTX-021"},
+		{"Error response from daemon: Get https://registry/v2/: dial tcp: lookup registry: no such host", "CTX-020"},
+	}
+
+	for _, tt := range tests {
+		if code := pullErrorCode(errors.New(tt.err)); code != tt.code {
+			t.Errorf("pullErrorCode(%q) = %s, want %s", tt.err, code, tt.code)
+		}
+	}
+}
+
+// TestApplyDefaultLimits tests that defaults fill only unset limits
+func TestApplyDefaultLimits(t *testing.T) {
+	defaults := ResourceLimits{Memory: 512 * 1024 * 1024, CPUShares: 1024, PidsLimit: 128}
+
+	hostConfig := &container.HostConfig{}
+	ApplyDefaultLimits(hostConfig, defaults)
+	if hostConfig.Memory != defaults.Memory || hostConfig.CPUShares != 1024 {
+		t.Errorf("expected defaults on an empty host config, got memory=%d cpu_shares=%d", hostConfig.Memory, hostConfig.CPUShares)
+	}
+	if hostConfig.PidsLimit == nil || *hostConfig.PidsLimit != 128 {
+		t.Errorf("expected pids limit 128, got %v", hostConfig.PidsLimit)
+	}
+
+	// Explicit limits win, and NanoCPUs excludes CPU shares
+	hostConfig = &container.HostConfig{}
+	hostConfig.Memory = 64 * 1024 * 1024
+	hostConfig.NanoCPUs = 500000000
+	ApplyDefaultLimits(hostConfig, defaults)
+	if hostConfig.Memory != 64*1024*1024 {
+		t.Errorf("expected explicit memory limit to be kept, got %d", hostConfig.Memory)
+	}
+	if hostConfig.CPUShares != 0 {
+		t.Errorf("expected no cpu shares alongside nano cpus, got %d", hostConfig.CPUShares)
+	}
+}
+
+// TestResourceLimitsValidate tests the Docker minimums
+func TestResourceLimitsValidate(t *testing.T) {
+	valid := []ResourceLimits{
+		{},
+		{Memory: MinMemoryBytes, CPUShares: MinCPUShares},
+		{Memory: 1024 * 1024 * 1024, CPUShares: 2048},
+	}
+	for _, limits := range valid {
+		if err := limits.Validate(); err != nil {
+			t.Errorf("expected %+v to be valid, got %v", limits, err)
+		}
+	}
+
+	invalid := []ResourceLimits{
+		{Memory: 1024 * 1024},
+		{Memory: -1},
+		{CPUShares: 1},
+		{PidsLimit: -1},
+	}
+	for _, limits := range invalid {
+		if err := limits.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", limits)
+		}
+	}
+}
@@ -107,6 +107,45 @@ func DefaultLimits(profile ResourceProfile) ResourceLimits {
 	}
 }
 
+// Docker rejects memory limits below 6MB and CPU shares below 2
+const (
+	MinMemoryBytes = 6 * 1024 * 1024
+	MinCPUShares   = 2
+)
+
+// Validate checks that limits are within what the Docker daemon accepts.
+// Zero values mean no limit and are always valid.
+func (l ResourceLimits) Validate() error {
+	if l.Memory < 0 || (l.Memory > 0 && l.Memory < MinMemoryBytes) {
+		return fmt.Errorf("memory limit must be at least %dMB", MinMemoryBytes/1024/1024)
+	}
+	if l.CPUShares < 0 || (l.CPUShares > 0 && l.CPUShares < MinCPUShares) {
+		return fmt.Errorf("cpu shares must be at least %d", MinCPUShares)
+	}
+	if l.PidsLimit < 0 {
+		return fmt.Errorf("pids limit cannot be negative")
+	}
+	return nil
+}
+
+// ApplyDefaultLimits fills in memory, CPU and PID limits the host config
+// leaves unset. Limits set by the caller are kept.
+func ApplyDefaultLimits(hostConfig *container.HostConfig, limits ResourceLimits) {
+	if hostConfig.Memory == 0 && limits.Memory > 0 {
+		hostConfig.Memory = limits.Memory
+		if hostConfig.MemorySwap == 0 && limits.MemorySwap > 0 {
+			hostConfig.MemorySwap = limits.MemorySwap
+		}
+	}
+	if hostConfig.CPUShares == 0 && hostConfig.NanoCPUs == 0 && limits.CPUShares > 0 {
+		hostConfig.CPUShares = limits.CPUShares
+	}
+	if hostConfig.PidsLimit == nil && limits.PidsLimit > 0 {
+		pids := limits.PidsLimit
+		hostConfig.PidsLimit = &pids
+	}
+}
+
 // ResourceUsage represents current resource usage of a container
 type ResourceUsage struct {
 	ContainerID    string    `json:"container_id"`
@@ -0,0 +1,27 @@
+package docker
+
+import (
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	// SecretSocketMountPath is where the secret delivery socket is mounted
+	// in the container; its entrypoint finds it via ARMORCLAW_SECRET_SOCKET
+	SecretSocketMountPath = "/run/armorclaw/secrets/socket.sock"
+
+	// SecretHostSocketDir is the host directory for secret delivery sockets
+	SecretHostSocketDir = "/run/armorclaw/secrets"
+)
+
+// PrepareSecretSocketMount creates a bind mount for a secret delivery socket
+func PrepareSecretSocketMount(socketPath string) mount.Mount {
+	return mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   socketPath,
+		Target:   SecretSocketMountPath,
+		ReadOnly: true,
+		BindOptions: &mount.BindOptions{
+			Propagation: mount.PropagationPrivate,
+		},
+	}
+}
@@ -11,7 +11,7 @@ import (
 // AdminTarget represents a resolved admin recipient
 type AdminTarget struct {
 	MXID   string `json:"mxid"`
-	Source string `json:"source"` // "config", "setup", "room", "fallback"
+	Source string `json:"source"` // "config", "setup", "room", "fallback", "escalation"
 }
 
 // AdminResolver determines the admin recipient for error notifications
@@ -29,6 +29,9 @@ type AdminResolver struct {
 	// Fallback (used if all else fails)
 	fallbackMXID string
 
+	// Escalation tiers notified in order when a critical error goes unresolved
+	escalationMXIDs []string
+
 	// Cache
 	cachedTarget *AdminTarget
 	cacheExpiry  time.Time
@@ -54,6 +57,7 @@ type AdminConfig struct {
 	AdminRoomID     string             // From notifier config
 	MatrixAdapter   MatrixAdminAdapter // For room membership lookup
 	FallbackMXID    string             // Last resort fallback
+	EscalationMXIDs []string           // Secondary on-call tiers, in escalation order
 	CacheTTL        time.Duration      // How long to cache resolved admin
 }
 
@@ -76,6 +80,7 @@ func NewAdminResolver(cfg AdminConfig) *AdminResolver {
 		adminRoomID:     cfg.AdminRoomID,
 		matrixAdapter:   cfg.MatrixAdapter,
 		fallbackMXID:    cfg.FallbackMXID,
+		escalationMXIDs: append([]string(nil), cfg.EscalationMXIDs...),
 		cacheTTL:        cfg.CacheTTL,
 	}
 }
@@ -116,6 +121,33 @@ func (r *AdminResolver) ResolveWithContext(ctx context.Context) (*AdminTarget, e
 	return target, nil
 }
 
+// ResolveTiers returns the primary admin followed by each escalation tier.
+// Tiers that repeat an earlier recipient are skipped.
+func (r *AdminResolver) ResolveTiers(ctx context.Context) ([]*AdminTarget, error) {
+	primary, err := r.ResolveWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tiers := []*AdminTarget{primary}
+	seen := map[string]bool{primary.MXID: true}
+	for _, mxid := range r.escalationMXIDs {
+		if mxid == "" || seen[mxid] {
+			continue
+		}
+		seen[mxid] = true
+		tiers = append(tiers, &AdminTarget{
+			MXID:   mxid,
+			Source: "escalation",
+		})
+	}
+
+	return tiers, nil
+}
+
 // resolveChain attempts each resolution method in priority order
 func (r *AdminResolver) resolveChain(ctx context.Context) (*AdminTarget, error) {
 	// 1. Check explicit config override
@@ -208,6 +240,13 @@ func (r *AdminResolver) SetFallback(mxid string) {
 	r.invalidateCache()
 }
 
+// SetEscalation sets the escalation tiers, in the order they are notified
+func (r *AdminResolver) SetEscalation(mxids []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.escalationMXIDs = append([]string(nil), mxids...)
+}
+
 // InvalidateCache clears the cached admin target
 func (r *AdminResolver) InvalidateCache() {
 	r.mu.Lock()
@@ -241,6 +280,13 @@ func (r *AdminResolver) GetAdminRoom() string {
 	return r.adminRoomID
 }
 
+// GetEscalation returns the escalation tier MXIDs
+func (r *AdminResolver) GetEscalation() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.escalationMXIDs...)
+}
+
 // SetupUserStorage persists the setup user to disk
 type SetupUserStorage struct {
 	path string
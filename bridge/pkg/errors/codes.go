@@ -1,6 +1,9 @@
 package errors
 
-import "sync"
+import (
+	"strings"
+	"sync"
+)
 
 // ErrorCodeDefinition defines an error code's properties
 type ErrorCodeDefinition struct {
@@ -17,6 +20,19 @@ var (
 	registryMu sync.RWMutex
 )
 
+// categories maps error code prefixes (e.g. "CTX") to category names
+var (
+	categories = map[string]string{
+		"CTX": "container",
+		"MAT": "matrix",
+		"RPC": "rpc",
+		"SYS": "system",
+		"BGT": "budget",
+		"VOX": "voice",
+	}
+	categoriesMu sync.RWMutex
+)
+
 // Default error code definitions
 var defaultCodes = map[string]ErrorCodeDefinition{
 	// Container errors (CTX-001 to CTX-099: lifecycle)
@@ -76,6 +92,13 @@ var defaultCodes = map[string]ErrorCodeDefinition{
 		Message:  "image not found",
 		Help:     "Verify image exists in registry and name is correct",
 	},
+	"CTX-022": {
+		Code:     "CTX-022",
+		Category: "container",
+		Severity: SeverityError,
+		Message:  "registry authentication failed",
+		Help:     "Run docker login for the image registry or check the configured credentials",
+	},
 
 	// Matrix errors (MAT-001 to MAT-099: connection)
 	"MAT-001": {
@@ -99,6 +122,13 @@ var defaultCodes = map[string]ErrorCodeDefinition{
 		Message:  "matrix sync timeout",
 		Help:     "Homeserver may be slow; will retry automatically",
 	},
+	"MAT-004": {
+		Code:     "MAT-004",
+		Category: "matrix",
+		Severity: SeverityError,
+		Message:  "matrix token refresh failed",
+		Help:     "Check the bridge's Matrix credentials; the session will end when the access token expires",
+	},
 	"MAT-010": {
 		Code:     "MAT-010",
 		Category: "matrix",
@@ -229,6 +259,27 @@ var defaultCodes = map[string]ErrorCodeDefinition{
 		Message:  "disk full",
 		Help:     "Free up disk space or increase storage",
 	},
+	"SYS-030": {
+		Code:     "SYS-030",
+		Category: "system",
+		Severity: SeverityWarning,
+		Message:  "license expiring soon",
+		Help:     "Renew the license before it expires to keep licensed features enabled",
+	},
+	"SYS-040": {
+		Code:     "SYS-040",
+		Category: "system",
+		Severity: SeverityWarning,
+		Message:  "account recovery cancelled",
+		Help:     "Confirm the cancellation was intended and store a new recovery phrase; the old phrase has been invalidated",
+	},
+	"SYS-041": {
+		Code:     "SYS-041",
+		Category: "system",
+		Severity: SeverityCritical,
+		Message:  "device fingerprint anomaly",
+		Help:     "Confirm with the user that the new device is theirs and verify it; otherwise revoke the user's sessions and rotate their credentials",
+	},
 
 	// Budget errors (BGT-001+)
 	"BGT-001": {
@@ -278,12 +329,64 @@ func init() {
 }
 
 // Register adds a new error code to the registry
+// If Category is empty it is derived from the code prefix
 func Register(def ErrorCodeDefinition) {
+	if def.Category == "" {
+		def.Category = CategoryForCode(def.Code)
+	}
+
 	registryMu.Lock()
 	defer registryMu.Unlock()
 	registry[def.Code] = def
 }
 
+// RegisterCategory maps an error code prefix to a category name, so codes
+// such as PLG-001 resolve to the category even when not individually
+// registered. Plugins should call this from init.
+func RegisterCategory(prefix, categoryName string) {
+	prefix = normalizePrefix(prefix)
+	if prefix == "" || categoryName == "" {
+		return
+	}
+
+	categoriesMu.Lock()
+	defer categoriesMu.Unlock()
+	categories[prefix] = categoryName
+}
+
+// CategoryForCode returns the category registered for a code's prefix,
+// or "unknown" if the prefix has not been registered
+func CategoryForCode(code string) string {
+	prefix := code
+	if i := strings.Index(code, "-"); i >= 0 {
+		prefix = code[:i]
+	}
+
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+
+	if name, ok := categories[normalizePrefix(prefix)]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// AllCategories returns all registered prefix to category mappings
+func AllCategories() map[string]string {
+	categoriesMu.RLock()
+	defer categoriesMu.RUnlock()
+
+	result := make(map[string]string, len(categories))
+	for k, v := range categories {
+		result[k] = v
+	}
+	return result
+}
+
+func normalizePrefix(prefix string) string {
+	return strings.ToUpper(strings.TrimSuffix(strings.TrimSpace(prefix), "-"))
+}
+
 // Lookup retrieves an error code definition
 func Lookup(code string) ErrorCodeDefinition {
 	registryMu.RLock()
@@ -293,10 +396,10 @@ func Lookup(code string) ErrorCodeDefinition {
 		return def
 	}
 
-	// Return unknown code definition
+	// Return unknown code definition, categorized by prefix if registered
 	return ErrorCodeDefinition{
 		Code:     code,
-		Category: "unknown",
+		Category: CategoryForCode(code),
 		Severity: SeverityError,
 		Message:  "unknown error",
 		Help:     "No additional help available for this error code",
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CommandPrefix starts error commands posted in the admin room
+const CommandPrefix = "!errors"
+
+// commandListLimit caps how many errors `!errors list` shows
+const commandListLimit = 10
+
+// HandleCommand runs an error command posted by sender in roomID and
+// returns the reply. Commands are only accepted in the admin room; handled
+// is false for any other message so it can be passed to other handlers.
+//
+//	!errors list [CODE|PREFIX|CATEGORY] [all]
+//	!errors resolve TRACE_ID
+func (s *System) HandleCommand(ctx context.Context, roomID, sender, text string) (reply string, handled bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || fields[0] != CommandPrefix {
+		return "", false
+	}
+	if adminRoom := s.resolver.GetAdminRoom(); adminRoom == "" || roomID != adminRoom {
+		return "", false
+	}
+
+	args := fields[1:]
+	if len(args) == 0 {
+		return commandHelp(), true
+	}
+
+	switch args[0] {
+	case "list":
+		return s.commandList(ctx, args[1:]), true
+	case "resolve":
+		if len(args) != 2 {
+			return "Usage: " + CommandPrefix + " resolve TRACE_ID", true
+		}
+		if err := s.Resolve(ctx, args[1], sender); err != nil {
+			return fmt.Sprintf("❌ Could not resolve %s: %v", args[1], err), true
+		}
+		return fmt.Sprintf("✅ Resolved %s", args[1]), true
+	default:
+		return commandHelp(), true
+	}
+}
+
+// commandList answers `!errors list`. The filter is an exact code
+// ("CTX-001"), a code prefix ("CTX") or a category name ("container").
+// Only unresolved errors are listed unless "all" is given.
+func (s *System) commandList(ctx context.Context, args []string) string {
+	unresolved := false
+	q := ErrorQuery{Resolved: &unresolved, Limit: commandListLimit, OrderDesc: true}
+	filter := ""
+
+	for _, arg := range args {
+		switch {
+		case arg == "all":
+			q.Resolved = nil
+		case strings.Contains(arg, "-"):
+			q.Code = strings.ToUpper(arg)
+			filter = q.Code
+		case AllCategories()[strings.ToUpper(arg)] != "":
+			q.Category = AllCategories()[strings.ToUpper(arg)]
+			filter = q.Category
+		default:
+			q.Category = strings.ToLower(arg)
+			filter = q.Category
+		}
+	}
+
+	results, err := s.Query(ctx, q)
+	if err != nil {
+		return fmt.Sprintf("❌ Could not query errors: %v", err)
+	}
+
+	scope := "unresolved errors"
+	if q.Resolved == nil {
+		scope = "errors"
+	}
+	if filter != "" {
+		scope += " in " + filter
+	}
+	if len(results) == 0 {
+		return "No " + scope + "."
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d most recent %s:\n", len(results), scope)
+	for _, e := range results {
+		fmt.Fprintf(&sb, "\n%s %s %s", severityEmoji(e.Severity), e.Code, e.TraceID)
+		if e.Occurrences > 1 {
+			fmt.Fprintf(&sb, " (×%d)", e.Occurrences)
+		}
+		fmt.Fprintf(&sb, " · last seen %s", e.LastSeen.UTC().Format("2006-01-02 15:04 UTC"))
+		if e.Resolved {
+			fmt.Fprintf(&sb, " · resolved by %s", e.ResolvedBy)
+		}
+		fmt.Fprintf(&sb, "\n  %s", e.Message)
+	}
+	if len(results) == commandListLimit {
+		fmt.Fprintf(&sb, "\n\nShowing the first %d; narrow the list with a code or category.", commandListLimit)
+	}
+	sb.WriteString("\n\nResolve one with: " + CommandPrefix + " resolve TRACE_ID")
+	return sb.String()
+}
+
+// commandHelp lists the error commands
+func commandHelp() string {
+	return strings.Join([]string{
+		"Error commands:",
+		"  " + CommandPrefix + " list [CODE|PREFIX|CATEGORY] [all] - recent unresolved errors (all: include resolved)",
+		"  " + CommandPrefix + " resolve TRACE_ID - mark an error resolved",
+	}, "\n")
+}
+
+// severityEmoji returns the emoji that marks a severity in messages
+func severityEmoji(severity Severity) string {
+	switch severity {
+	case SeverityCritical:
+		return "🔴"
+	case SeverityError:
+		return "❌"
+	case SeverityWarning:
+		return "⚠️"
+	default:
+		return "ℹ️"
+	}
+}
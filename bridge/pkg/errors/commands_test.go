@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSystem_HandleCommand(t *testing.T) {
+	storePath := testStorePath(t)
+	defer cleanupStore(t, storePath)
+
+	system, _ := Initialize(Config{StorePath: storePath, StoreEnabled: true, AdminRoomID: "!admin:example.com"})
+	defer system.Stop()
+
+	ctx := context.Background()
+	system.Store(ctx, &TracedError{Code: "CTX-001", Category: "container", Severity: SeverityCritical, Message: "container crashed", TraceID: "tr_a", Timestamp: time.Now()})
+	system.Store(ctx, &TracedError{Code: "MAT-001", Category: "matrix", Severity: SeverityError, Message: "sync failed", TraceID: "tr_b", Timestamp: time.Now()})
+
+	const room = "!admin:example.com"
+
+	// Only error commands in the admin room are handled
+	if _, handled := system.HandleCommand(ctx, "!other:example.com", "@admin:example.com", "!errors list"); handled {
+		t.Error("command handled outside the admin room")
+	}
+	if _, handled := system.HandleCommand(ctx, room, "@admin:example.com", "!agent list"); handled {
+		t.Error("non-error command handled")
+	}
+
+	reply, handled := system.HandleCommand(ctx, room, "@admin:example.com", "!errors list CTX")
+	if !handled || !strings.Contains(reply, "tr_a") || !strings.Contains(reply, "container crashed") || strings.Contains(reply, "tr_b") {
+		t.Errorf("list CTX reply = %q", reply)
+	}
+
+	reply, _ = system.HandleCommand(ctx, room, "@admin:example.com", "!errors list matrix")
+	if !strings.Contains(reply, "tr_b") || strings.Contains(reply, "tr_a") {
+		t.Errorf("list matrix reply = %q", reply)
+	}
+
+	reply, _ = system.HandleCommand(ctx, room, "@admin:example.com", "!errors resolve tr_a")
+	if !strings.Contains(reply, "Resolved tr_a") {
+		t.Errorf("resolve reply = %q", reply)
+	}
+	resolved, _ := system.Query(ctx, ErrorQuery{Code: "CTX-001"})
+	if len(resolved) != 1 || !resolved[0].Resolved || resolved[0].ResolvedBy != "@admin:example.com" {
+		t.Errorf("stored error after resolve = %+v", resolved)
+	}
+
+	// Resolved errors drop out of the default list but show with "all"
+	if reply, _ = system.HandleCommand(ctx, room, "@admin:example.com", "!errors list CTX"); !strings.HasPrefix(reply, "No unresolved errors") {
+		t.Errorf("list after resolve = %q", reply)
+	}
+	if reply, _ = system.HandleCommand(ctx, room, "@admin:example.com", "!errors list CTX all"); !strings.Contains(reply, "resolved by @admin:example.com") {
+		t.Errorf("list all = %q", reply)
+	}
+
+	if reply, _ = system.HandleCommand(ctx, room, "@admin:example.com", "!errors resolve tr_missing"); !strings.Contains(reply, "Could not resolve") {
+		t.Errorf("resolve unknown = %q", reply)
+	}
+	if reply, _ = system.HandleCommand(ctx, room, "@admin:example.com", "!errors"); !strings.Contains(reply, "!errors resolve TRACE_ID") {
+		t.Errorf("help = %q", reply)
+	}
+}
@@ -107,6 +107,43 @@ func (rb *RingBuffer) Count() int {
 	return rb.count
 }
 
+// Size returns the buffer capacity
+func (rb *RingBuffer) Size() int {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+	return rb.size
+}
+
+// Resize changes the buffer capacity, keeping the most recent events
+func (rb *RingBuffer) Resize(size int) {
+	if size <= 0 {
+		size = 10
+	}
+
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if size == rb.size {
+		return
+	}
+
+	keep := rb.count
+	if keep > size {
+		keep = size
+	}
+
+	events := make([]ComponentLogEntry, size)
+	for i := 0; i < keep; i++ {
+		idx := (rb.head - keep + i + rb.size) % rb.size
+		events[i] = rb.events[idx]
+	}
+
+	rb.events = events
+	rb.size = size
+	rb.count = keep
+	rb.head = keep % size
+}
+
 // ComponentTracker tracks events for a specific component
 type ComponentTracker struct {
 	name   string
@@ -157,11 +194,25 @@ func (ct *ComponentTracker) Start(operation string, data interface{}) {
 	ct.Event(operation+"_start", data)
 }
 
-// GetRecent returns the last n events
+// GetRecent returns up to the last n events, oldest first. n <= 0 returns
+// every buffered event.
 func (ct *ComponentTracker) GetRecent(n int) []ComponentLogEntry {
+	if n <= 0 {
+		return ct.buffer.GetAll()
+	}
 	return ct.buffer.GetLast(n)
 }
 
+// SetBufferSize changes how many events are retained, keeping the most recent
+func (ct *ComponentTracker) SetBufferSize(size int) {
+	ct.buffer.Resize(size)
+}
+
+// BufferSize returns how many events are retained
+func (ct *ComponentTracker) BufferSize() int {
+	return ct.buffer.Size()
+}
+
 // GetAll returns all events
 func (ct *ComponentTracker) GetAll() []ComponentLogEntry {
 	return ct.buffer.GetAll()
@@ -243,6 +294,12 @@ func GetComponentTracker(name string) *ComponentTracker {
 	return tracker
 }
 
+// SetComponentBufferSize sets how many events a component retains,
+// creating its tracker if needed
+func SetComponentBufferSize(name string, size int) {
+	GetComponentTracker(name).SetBufferSize(size)
+}
+
 // TrackEvent records an event for a component
 func TrackEvent(component, eventType string, data interface{}) {
 	GetComponentTracker(component).Event(eventType, data)
@@ -200,6 +200,73 @@ func TestComponentTracker_GetRecent(t *testing.T) {
 	if recent[2].Data.(int) != 5 {
 		t.Errorf("Most recent event should be 5, got %v", recent[2].Data)
 	}
+	if recent[2].Timestamp.IsZero() {
+		t.Error("GetRecent should include timestamps")
+	}
+}
+
+func TestComponentTracker_GetRecentAll(t *testing.T) {
+	ct := NewComponentTracker("test", 4)
+
+	for i := 1; i <= 6; i++ {
+		ct.Event("event", i)
+	}
+
+	// n <= 0 returns everything retained; buffer stays bounded
+	all := ct.GetRecent(0)
+	if len(all) != 4 || all[0].Data.(int) != 3 {
+		t.Errorf("GetRecent(0) = %+v, want events 3-6", all)
+	}
+}
+
+func TestComponentTracker_SetBufferSize(t *testing.T) {
+	ct := NewComponentTracker("test", 5)
+	for i := 1; i <= 7; i++ {
+		ct.Event("event", i)
+	}
+
+	// Shrinking keeps the most recent events
+	ct.SetBufferSize(3)
+	if ct.BufferSize() != 3 {
+		t.Errorf("BufferSize() = %d, want 3", ct.BufferSize())
+	}
+	events := ct.GetRecent(0)
+	for i, expected := range []int{5, 6, 7} {
+		if events[i].Data.(int) != expected {
+			t.Errorf("Event %d has data %v, want %d", i, events[i].Data, expected)
+		}
+	}
+
+	// Growing keeps existing events and accepts more
+	ct.SetBufferSize(6)
+	ct.Event("event", 8)
+	events = ct.GetRecent(0)
+	if len(events) != 4 || events[0].Data.(int) != 5 || events[3].Data.(int) != 8 {
+		t.Errorf("After growing, events = %+v, want 5-8", events)
+	}
+}
+
+func TestErrorBuilder_WithComponent(t *testing.T) {
+	tracker := GetComponentTracker("builder_test")
+	tracker.Clear()
+	defer tracker.Clear()
+
+	tracker.Event("pull_image", "alpine")
+	tracker.Event("create_container", nil)
+
+	err := NewBuilder("CTX-001").WithComponent("builder_test").Build()
+	if len(err.RecentLogs) != 2 {
+		t.Fatalf("RecentLogs has %d events, want 2", len(err.RecentLogs))
+	}
+	if err.RecentLogs[0].Event != "pull_image" || err.RecentLogs[1].Event != "create_container" {
+		t.Errorf("RecentLogs = %+v, want events in recorded order", err.RecentLogs)
+	}
+
+	// Component with no events leaves RecentLogs empty
+	err = NewBuilder("CTX-001").WithComponent("builder_test_empty").Build()
+	if err.RecentLogs != nil {
+		t.Errorf("RecentLogs = %+v, want nil", err.RecentLogs)
+	}
 }
 
 func TestComponentTracker_Clear(t *testing.T) {
@@ -0,0 +1,123 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DigestEntry aggregates batched notifications for a single error code
+type DigestEntry struct {
+	Code          string    `json:"code"`
+	Category      string    `json:"category"`
+	Severity      Severity  `json:"severity"`
+	Count         int       `json:"count"`
+	LatestTraceID string    `json:"latest_trace_id"`
+	LatestMessage string    `json:"latest_message"`
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
+}
+
+// DigestBuffer accumulates non-critical notifications between flushes
+type DigestBuffer struct {
+	mu      sync.Mutex
+	entries map[string]*DigestEntry // code -> entry
+}
+
+// NewDigestBuffer creates an empty digest buffer
+func NewDigestBuffer() *DigestBuffer {
+	return &DigestBuffer{
+		entries: make(map[string]*DigestEntry),
+	}
+}
+
+// Add folds an error into the digest, grouped by code
+func (d *DigestBuffer) Add(err *TracedError) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Count the notification itself plus any occurrences the
+	// sampling registry suppressed since the last one
+	count := 1
+	if err.RepeatCount > 1 {
+		count = err.RepeatCount
+	}
+
+	entry, exists := d.entries[err.Code]
+	if !exists {
+		d.entries[err.Code] = &DigestEntry{
+			Code:          err.Code,
+			Category:      err.Category,
+			Severity:      err.Severity,
+			Count:         count,
+			LatestTraceID: err.TraceID,
+			LatestMessage: err.Message,
+			FirstSeen:     err.Timestamp,
+			LastSeen:      err.Timestamp,
+		}
+		return
+	}
+
+	entry.Count += count
+	entry.Severity = err.Severity
+	entry.LatestTraceID = err.TraceID
+	entry.LatestMessage = err.Message
+	entry.LastSeen = err.Timestamp
+}
+
+// Drain returns all pending entries sorted by code and empties the buffer
+func (d *DigestBuffer) Drain() []DigestEntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.entries) == 0 {
+		return nil
+	}
+
+	result := make([]DigestEntry, 0, len(d.entries))
+	for _, entry := range d.entries {
+		result = append(result, *entry)
+	}
+	d.entries = make(map[string]*DigestEntry)
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+
+	return result
+}
+
+// Len returns the number of distinct codes pending
+func (d *DigestBuffer) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.entries)
+}
+
+// FormatDigest creates a single summarized notification for a set of entries
+func FormatDigest(entries []DigestEntry, now time.Time) string {
+	var sb strings.Builder
+
+	total := 0
+	for _, entry := range entries {
+		total += entry.Count
+	}
+
+	sb.WriteString(fmt.Sprintf("📊 ERROR DIGEST: %d codes, %d occurrences\n", len(entries), total))
+	sb.WriteString(fmt.Sprintf("⏰ %s\n\n", now.UTC().Format("2006-01-02 15:04:05 UTC")))
+
+	for _, entry := range entries {
+		emoji := "⚠️"
+		if entry.Severity == SeverityError {
+			emoji = "❌"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s (%s) ×%d: %s\n", emoji, entry.Code, entry.Category, entry.Count, entry.LatestMessage))
+		sb.WriteString(fmt.Sprintf("   🏷️ Latest trace: %s\n", entry.LatestTraceID))
+	}
+
+	sb.WriteString("\n🔍 Full traces are kept in the error store under each trace ID.")
+
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+package errors
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDigestBuffer_AddAndDrain(t *testing.T) {
+	d := NewDigestBuffer()
+
+	now := time.Now()
+	d.Add(&TracedError{Code: "MAT-003", Category: "matrix", Severity: SeverityWarning, TraceID: "tr_1", Timestamp: now})
+	d.Add(&TracedError{Code: "CTX-001", Category: "container", Severity: SeverityError, TraceID: "tr_2", Timestamp: now})
+	d.Add(&TracedError{Code: "CTX-001", Category: "container", Severity: SeverityError, TraceID: "tr_3", Timestamp: now.Add(time.Second)})
+
+	if d.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", d.Len())
+	}
+
+	entries := d.Drain()
+	if len(entries) != 2 {
+		t.Fatalf("Drain() returned %d entries, want 2", len(entries))
+	}
+
+	// Sorted by code
+	if entries[0].Code != "CTX-001" || entries[1].Code != "MAT-003" {
+		t.Errorf("Drain() order = %s, %s; want CTX-001, MAT-003", entries[0].Code, entries[1].Code)
+	}
+	if entries[0].Count != 2 {
+		t.Errorf("CTX-001 Count = %d, want 2", entries[0].Count)
+	}
+	if entries[0].LatestTraceID != "tr_3" {
+		t.Errorf("CTX-001 LatestTraceID = %q, want tr_3", entries[0].LatestTraceID)
+	}
+
+	if d.Len() != 0 {
+		t.Error("Drain() should empty the buffer")
+	}
+	if d.Drain() != nil {
+		t.Error("Drain() on empty buffer should return nil")
+	}
+}
+
+func TestDigestBuffer_RepeatCount(t *testing.T) {
+	d := NewDigestBuffer()
+	d.Add(&TracedError{Code: "CTX-001", Severity: SeverityError, RepeatCount: 7, Timestamp: time.Now()})
+
+	entries := d.Drain()
+	if entries[0].Count != 7 {
+		t.Errorf("Count = %d, want 7", entries[0].Count)
+	}
+}
+
+func TestFormatDigest(t *testing.T) {
+	entries := []DigestEntry{
+		{Code: "CTX-001", Category: "container", Severity: SeverityError, Count: 3, LatestTraceID: "tr_a", LatestMessage: "container start failed"},
+		{Code: "MAT-003", Category: "matrix", Severity: SeverityWarning, Count: 1, LatestTraceID: "tr_b", LatestMessage: "matrix sync timeout"},
+	}
+
+	msg := FormatDigest(entries, time.Date(2026, 2, 15, 18, 32, 5, 0, time.UTC))
+
+	checks := []string{
+		"📊 ERROR DIGEST: 2 codes, 4 occurrences",
+		"2026-02-15 18:32:05 UTC",
+		"❌ CTX-001 (container) ×3: container start failed",
+		"Latest trace: tr_a",
+		"⚠️ MAT-003 (matrix) ×1: matrix sync timeout",
+		"Latest trace: tr_b",
+	}
+	for _, check := range checks {
+		if !strings.Contains(msg, check) {
+			t.Errorf("Digest should contain %q, got:\n%s", check, msg)
+		}
+	}
+}
+
+func TestErrorNotifier_Digest(t *testing.T) {
+	mockSender := &mockMatrixSender{}
+
+	notifier := NewErrorNotifier(NotifierConfig{
+		Registry:     NewSamplingRegistry(DefaultSamplingConfig()),
+		Resolver:     NewAdminResolver(AdminConfig{SetupUserMXID: "@admin:example.com"}),
+		MatrixSender: mockSender,
+		Enabled:      true,
+		Digest:       true,
+	})
+
+	ctx := context.Background()
+
+	// Non-critical errors are batched
+	notifier.Notify(ctx, NewBuilder("CTX-001").Build())
+	notifier.Notify(ctx, NewBuilder("MAT-003").Build())
+
+	if mockSender.callCount != 0 {
+		t.Fatalf("SendMessage called %d times before flush, want 0", mockSender.callCount)
+	}
+
+	// Critical errors bypass the digest
+	notifier.Notify(ctx, NewBuilder("SYS-001").Build())
+	if mockSender.callCount != 1 {
+		t.Fatalf("Critical error should notify immediately, callCount = %d", mockSender.callCount)
+	}
+	if !strings.HasPrefix(mockSender.lastMessage, "🔴 CRITICAL: SYS-001") {
+		t.Errorf("Unexpected critical message header: %q", mockSender.lastMessage)
+	}
+
+	if err := notifier.FlushDigest(ctx); err != nil {
+		t.Fatalf("FlushDigest() error = %v", err)
+	}
+	if mockSender.callCount != 2 {
+		t.Fatalf("FlushDigest should send one message, callCount = %d", mockSender.callCount)
+	}
+	if !strings.Contains(mockSender.lastMessage, "CTX-001") || !strings.Contains(mockSender.lastMessage, "MAT-003") {
+		t.Errorf("Digest should list both codes, got:\n%s", mockSender.lastMessage)
+	}
+
+	// Nothing pending: no message
+	notifier.FlushDigest(ctx)
+	if mockSender.callCount != 2 {
+		t.Errorf("Empty flush should not send, callCount = %d", mockSender.callCount)
+	}
+}
+
+func TestErrorNotifier_SetDigestEnabled(t *testing.T) {
+	notifier := NewErrorNotifier(NotifierConfig{Enabled: true})
+
+	if notifier.IsDigestEnabled() {
+		t.Error("Digest should be disabled by default")
+	}
+
+	notifier.SetDigestEnabled(true)
+	if !notifier.IsDigestEnabled() {
+		t.Error("Digest should be enabled after SetDigestEnabled(true)")
+	}
+
+	notifier.SetDigestEnabled(false)
+	if notifier.IsDigestEnabled() {
+		t.Error("Digest should be disabled after SetDigestEnabled(false)")
+	}
+}
+
+func TestSystem_DigestLoop(t *testing.T) {
+	mockSender := &mockMatrixSender{}
+
+	system, err := Initialize(Config{
+		MatrixSender:   mockSender,
+		SetupUserMXID:  "@admin:example.com",
+		DigestInterval: "10ms",
+		Enabled:        true,
+		NotifyEnabled:  true,
+		StoreEnabled:   false,
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if !system.GetNotifier().IsDigestEnabled() {
+		t.Fatal("DigestInterval should enable digest mode")
+	}
+
+	ctx := context.Background()
+	system.Notify(ctx, NewBuilder("CTX-001").Build())
+
+	// Stop flushes any pending digest
+	system.Start(ctx)
+	if err := system.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if mockSender.callCount != 1 {
+		t.Fatalf("SendMessage called %d times, want 1", mockSender.callCount)
+	}
+	if !strings.Contains(mockSender.lastMessage, "ERROR DIGEST") {
+		t.Errorf("Expected digest message, got:\n%s", mockSender.lastMessage)
+	}
+}
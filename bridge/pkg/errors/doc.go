@@ -35,6 +35,11 @@
 //   - BGT-001+: Budget errors
 //   - VOX-001+: Voice/WebRTC errors
 //
+// Subsystems such as plugins can register their own prefix at init so
+// their codes are categorized in notifications and stats:
+//
+//	errors.RegisterCategory("PLG", "plugin")
+//
 // # Severity Levels
 //
 //   - Warning: Non-critical issues that don't break functionality
@@ -46,7 +51,18 @@
 //   - Critical errors: Always notify immediately
 //   - First occurrence of code: Notify
 //   - Repeats within 5-minute window: Count but don't notify
+//   - Noisy codes can use a longer window via Config.CodeWindows, keyed
+//     by exact code ("CTX-003") or prefix ("CTX"); exact codes win
 //   - After window expires: Notify with accumulated count
+//   - Digest mode (DigestInterval set): Warning/Error notifications are
+//     batched and sent as one summary per interval; Critical bypasses it
+//   - Failed deliveries are queued in the store and retried with backoff;
+//     notifications raised before a Matrix sender exists are queued too.
+//     Attaching a sender (System.SetMatrixSender) retries the whole queue
+//     at once, regardless of backoff. The queue keeps the newest 1000
+//     notifications and drops older ones
+//   - Dry-run mode (System.SetDryRun) captures formatted messages for
+//     System.DryRunMessages instead of sending them, for use in tests
 //
 // # Admin Resolution
 //
@@ -55,6 +71,11 @@
 //  2. First setup user (captured during wizard)
 //  3. Admin room members (first with power level >= 50)
 //
+// The resolved admin is the primary recipient. Config.EscalationMXIDs adds
+// secondary tiers: a critical error that is still unresolved after
+// EscalationTimeout (default 15m) is sent to the next tier, one tier per
+// timeout. System.Resolve cancels any pending escalation for the trace.
+//
 // # Message Format
 //
 // Notifications use a hybrid format for LLM consumption:
@@ -78,6 +99,10 @@
 //
 //	📋 Copy the JSON block above to analyze with an LLM.
 //
+// Senders that implement MatrixHTMLSender also receive an
+// org.matrix.custom.html rendering, with the JSON in a <pre><code> block;
+// the text above is kept as the plain-text fallback.
+//
 // # Component Tracking
 //
 // Each package can track events for trace context:
@@ -87,6 +112,11 @@
 //	tracker.Success("start_container", nil)
 //	tracker.Failure("start_container", err, nil)
 //
+// Each tracker keeps a bounded ring of events (SetBufferSize to change it).
+// GetRecent(n) reads them back, and the builder can attach them:
+//
+//	err := errors.NewBuilder("CTX-001").WithComponent("docker").Build()
+//
 // # Integration
 //
 // Initialize the system at startup:
@@ -106,7 +136,7 @@
 //
 // The system exposes RPC methods for error management:
 //   - GetErrors: Query stored errors
-//   - ResolveError: Mark an error as resolved
+//   - ResolveError: Mark an error as resolved and stop its escalation
 //
 // # Thread Safety
 //
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultDryRunCapacity is the number of dry-run messages retained
+const DefaultDryRunCapacity = 100
+
+// DryRunMessage is a formatted notification captured in dry-run mode
+// instead of being sent to Matrix
+type DryRunMessage struct {
+	RoomID    string    `json:"room_id"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	Code      string    `json:"code"`
+	Message   string    `json:"message"`
+	MsgType   string    `json:"msg_type"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// FormattedMessage is the HTML rendering sent to senders that support it
+	FormattedMessage string `json:"formatted_message,omitempty"`
+}
+
+// dryRunBuffer is a fixed-size ring of captured notifications
+type dryRunBuffer struct {
+	mu       sync.Mutex
+	messages []DryRunMessage
+	next     int
+	full     bool
+}
+
+func newDryRunBuffer(capacity int) *dryRunBuffer {
+	if capacity <= 0 {
+		capacity = DefaultDryRunCapacity
+	}
+	return &dryRunBuffer{
+		messages: make([]DryRunMessage, capacity),
+	}
+}
+
+// add records a message, overwriting the oldest when full
+func (b *dryRunBuffer) add(msg DryRunMessage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.messages[b.next] = msg
+	b.next = (b.next + 1) % len(b.messages)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// all returns captured messages, oldest first
+func (b *dryRunBuffer) all() []DryRunMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		result := make([]DryRunMessage, b.next)
+		copy(result, b.messages[:b.next])
+		return result
+	}
+
+	result := make([]DryRunMessage, 0, len(b.messages))
+	result = append(result, b.messages[b.next:]...)
+	result = append(result, b.messages[:b.next]...)
+	return result
+}
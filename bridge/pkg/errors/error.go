@@ -35,6 +35,53 @@ type ComponentLogEntry struct {
 	Data      interface{} `json:"data,omitempty"`
 }
 
+// BuildInfo identifies the bridge build that produced a trace
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+}
+
+// String formats the build as "v0.2.0 (abc1234, 2026-01-02T15:04:05Z)"
+func (b BuildInfo) String() string {
+	var details []string
+	if b.Commit != "" {
+		details = append(details, b.Commit)
+	}
+	if b.BuildTime != "" {
+		details = append(details, b.BuildTime)
+	}
+	if len(details) == 0 {
+		return "v" + b.Version
+	}
+	return fmt.Sprintf("v%s (%s)", b.Version, strings.Join(details, ", "))
+}
+
+var (
+	buildInfo   *BuildInfo
+	buildInfoMu sync.RWMutex
+)
+
+// SetBuildInfo records the running build so that every trace created
+// afterwards carries it. It is called once at startup with the values
+// set at compile time.
+func SetBuildInfo(version, commit, buildTime string) {
+	buildInfoMu.Lock()
+	defer buildInfoMu.Unlock()
+	if version == "" {
+		buildInfo = nil
+		return
+	}
+	buildInfo = &BuildInfo{Version: version, Commit: commit, BuildTime: buildTime}
+}
+
+// currentBuildInfo returns the recorded build, or nil if none was set
+func currentBuildInfo() *BuildInfo {
+	buildInfoMu.RLock()
+	defer buildInfoMu.RUnlock()
+	return buildInfo
+}
+
 // TracedError is a structured error with detailed context for debugging
 type TracedError struct {
 	// Identification
@@ -58,8 +105,9 @@ type TracedError struct {
 	RecentLogs []ComponentLogEntry    `json:"recent_logs,omitempty"`
 
 	// Tracking
-	Timestamp   time.Time `json:"timestamp"`
-	RepeatCount int       `json:"repeat_count,omitempty"`
+	Timestamp   time.Time  `json:"timestamp"`
+	RepeatCount int        `json:"repeat_count,omitempty"`
+	Build       *BuildInfo `json:"build,omitempty"`
 
 	// Wrapped error
 	cause error `json:"-"`
@@ -101,6 +149,9 @@ func (e *TracedError) FormatSummary() string {
 	sb.WriteString(fmt.Sprintf("📍 Location: %s @ %s:%d\n", e.Function, e.File, e.Line))
 	sb.WriteString(fmt.Sprintf("🏷️ Trace ID: %s\n", e.TraceID))
 	sb.WriteString(fmt.Sprintf("⏰ %s\n", e.Timestamp.UTC().Format("2006-01-02 15:04:05 UTC")))
+	if e.Build != nil {
+		sb.WriteString(fmt.Sprintf("🏗️ Build: %s\n", e.Build))
+	}
 
 	if e.RepeatCount > 0 {
 		sb.WriteString(fmt.Sprintf("🔁 Repeated %d times\n", e.RepeatCount))
@@ -225,6 +276,7 @@ func NewBuilder(code string) *ErrorBuilder {
 			State:      make(map[string]interface{}),
 			Stack:      captureStack(1),
 			RepeatCount: 0,
+			Build:      currentBuildInfo(),
 		},
 	}
 }
@@ -250,7 +302,8 @@ func (b *ErrorBuilder) WithMessagef(format string, args ...interface{}) *ErrorBu
 	return b
 }
 
-// WithSeverity overrides the default severity
+// WithSeverity overrides the severity inferred from the error code definition.
+// The override is carried through to the stored record and the notification.
 func (b *ErrorBuilder) WithSeverity(sev Severity) *ErrorBuilder {
 	b.err.Severity = sev
 	return b
@@ -303,6 +356,19 @@ func (b *ErrorBuilder) WithRecentLogs(logs []ComponentLogEntry) *ErrorBuilder {
 	return b
 }
 
+// WithComponent attaches the named component's buffered events to the
+// trace, showing what led up to the failure. May be called once per
+// component; events are merged in timestamp order.
+func (b *ErrorBuilder) WithComponent(name string) *ErrorBuilder {
+	events := GetComponentTracker(name).GetRecent(0)
+	if len(events) == 0 {
+		return b
+	}
+	b.err.RecentLogs = append(b.err.RecentLogs, events...)
+	sortEventsByTimestamp(b.err.RecentLogs)
+	return b
+}
+
 // WithRepeatCount sets the repeat count for rate-limited notifications
 func (b *ErrorBuilder) WithRepeatCount(count int) *ErrorBuilder {
 	b.err.RepeatCount = count
@@ -218,6 +218,29 @@ func TestErrorBuilder_Build(t *testing.T) {
 	}
 }
 
+func TestErrorBuilder_BuildInfo(t *testing.T) {
+	SetBuildInfo("1.4.0", "abc1234", "2026-02-15T18:00:00Z")
+	defer SetBuildInfo("", "", "")
+
+	err := NewBuilder("CTX-001").Build()
+	if err.Build == nil || err.Build.Version != "1.4.0" || err.Build.Commit != "abc1234" {
+		t.Fatalf("Build = %+v, want the recorded build info", err.Build)
+	}
+
+	json, _ := err.FormatJSON()
+	if !strings.Contains(json, `"version": "1.4.0"`) || !strings.Contains(json, `"commit": "abc1234"`) {
+		t.Errorf("JSON should contain the build, got %s", json)
+	}
+	if summary := err.FormatSummary(); !strings.Contains(summary, "Build: v1.4.0 (abc1234, 2026-02-15T18:00:00Z)") {
+		t.Errorf("summary should contain the build, got %q", summary)
+	}
+
+	SetBuildInfo("", "", "")
+	if err := NewBuilder("CTX-001").Build(); err.Build != nil {
+		t.Errorf("Build = %+v, want nil when no build info is set", err.Build)
+	}
+}
+
 func TestErrorBuilder_Wrap(t *testing.T) {
 	cause := errors.New("underlying error")
 	err := NewBuilder("CTX-001").
@@ -309,6 +332,26 @@ func TestErrorBuilder_WithSeverity(t *testing.T) {
 	}
 }
 
+func TestErrorBuilder_WithSeverity_OverridesCodeDefault(t *testing.T) {
+	// CTX-003 defaults to critical; override it to a warning
+	err := NewBuilder("CTX-003").
+		WithSeverity(SeverityWarning).
+		Build()
+
+	if err.Severity != SeverityWarning {
+		t.Errorf("Severity = %q, want %q", err.Severity, SeverityWarning)
+	}
+	if err.Category != "container" {
+		t.Errorf("Category = %q, want container", err.Category)
+	}
+
+	// Without the override the code default is kept
+	def := NewBuilder("CTX-003").Build()
+	if def.Severity != SeverityCritical {
+		t.Errorf("Default severity = %q, want %q", def.Severity, SeverityCritical)
+	}
+}
+
 func TestErrorBuilder_WithRepeatCount(t *testing.T) {
 	err := NewBuilder("CTX-001").
 		WithRepeatCount(10).
@@ -409,6 +452,41 @@ func TestLookupUnknownCode(t *testing.T) {
 	}
 }
 
+func TestRegisterCategory(t *testing.T) {
+	RegisterCategory("PLG", "plugin")
+
+	def := Lookup("PLG-001")
+	if def.Category != "plugin" {
+		t.Errorf("Category = %q, want 'plugin'", def.Category)
+	}
+	if def.Message != "unknown error" {
+		t.Errorf("Unregistered code should keep default message, got %q", def.Message)
+	}
+
+	// Builder and quick constructors pick up the category
+	err := New("PLG-042", "plugin failed")
+	if err.Category != "plugin" {
+		t.Errorf("Built error Category = %q, want 'plugin'", err.Category)
+	}
+
+	// Prefix normalization
+	RegisterCategory("ext-", "extension")
+	if got := CategoryForCode("EXT-7"); got != "extension" {
+		t.Errorf("CategoryForCode(EXT-7) = %q, want 'extension'", got)
+	}
+
+	// Unknown prefixes still degrade gracefully
+	if got := CategoryForCode("ZZZ-001"); got != "unknown" {
+		t.Errorf("CategoryForCode(ZZZ-001) = %q, want 'unknown'", got)
+	}
+
+	// Codes registered without a category inherit it from the prefix
+	Register(ErrorCodeDefinition{Code: "PLG-100", Severity: SeverityWarning, Message: "plugin slow"})
+	if got := Lookup("PLG-100").Category; got != "plugin" {
+		t.Errorf("Registered PLG-100 Category = %q, want 'plugin'", got)
+	}
+}
+
 func TestRegister(t *testing.T) {
 	customCode := ErrorCodeDefinition{
 		Code:     "CUSTOM-001",
@@ -0,0 +1,168 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"time"
+)
+
+// DefaultEscalationTimeout is how long a critical error may stay unresolved
+// before the next admin tier is notified
+const DefaultEscalationTimeout = 15 * time.Minute
+
+// escalation tracks a critical error that has been sent to some, but not
+// all, admin tiers
+type escalation struct {
+	err      *TracedError
+	tiers    []*AdminTarget
+	next     int // index of the next tier to notify
+	notified time.Time
+	timer    *time.Timer
+}
+
+// scheduleEscalation arms escalation for a critical error that has just
+// been delivered to the primary admin. Only one escalation runs per code,
+// matching the store's one-unresolved-record-per-code model.
+func (n *ErrorNotifier) scheduleEscalation(ctx context.Context, err *TracedError) {
+	if err.Severity != SeverityCritical || n.escalationTimeout <= 0 || n.resolver == nil {
+		return
+	}
+
+	tiers, resolveErr := n.resolver.ResolveTiers(ctx)
+	if resolveErr != nil || len(tiers) < 2 {
+		return
+	}
+
+	n.escMu.Lock()
+	defer n.escMu.Unlock()
+
+	for _, e := range n.escalations {
+		if e.err.Code == err.Code {
+			return
+		}
+	}
+
+	traceID := err.TraceID
+	n.escalations[traceID] = &escalation{
+		err:      err,
+		tiers:    tiers,
+		next:     1,
+		notified: time.Now(),
+		timer: time.AfterFunc(n.escalationTimeout, func() {
+			n.escalate(traceID)
+		}),
+	}
+}
+
+// escalate notifies the next tier for an unresolved error and re-arms the
+// timer while tiers remain
+func (n *ErrorNotifier) escalate(traceID string) {
+	n.escMu.Lock()
+	e, ok := n.escalations[traceID]
+	n.escMu.Unlock()
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	if n.isResolved(ctx, e.err) {
+		n.CancelEscalation(traceID)
+		return
+	}
+
+	target := e.tiers[e.next]
+	message, formatted := n.formatEscalation(e, target)
+
+	n.mu.RLock()
+	var sendErr error
+	if n.enabled {
+		sendErr = n.send(ctx, target.MXID, traceID, e.err.Code, message, formatted)
+	}
+	n.mu.RUnlock()
+	if sendErr != nil {
+		slog.Warn("error_escalation_failed", "code", e.err.Code, "trace_id", traceID, "admin", target.MXID, "error", sendErr)
+	}
+
+	n.escMu.Lock()
+	defer n.escMu.Unlock()
+
+	// Resolved while the message was being sent
+	if n.escalations[traceID] != e {
+		return
+	}
+
+	e.next++
+	e.notified = time.Now()
+	if e.next >= len(e.tiers) {
+		delete(n.escalations, traceID)
+		return
+	}
+	e.timer.Reset(n.escalationTimeout)
+}
+
+// isResolved reports whether the stored record for the error's code has
+// been resolved. Repeat occurrences share the record of the first trace, so
+// the lookup is by code rather than trace ID.
+func (n *ErrorNotifier) isResolved(ctx context.Context, err *TracedError) bool {
+	if n.store == nil {
+		return false
+	}
+
+	unresolved := false
+	results, qErr := n.store.Query(ctx, ErrorQuery{
+		Code:     err.Code,
+		Resolved: &unresolved,
+		Limit:    1,
+	})
+	return qErr == nil && len(results) == 0
+}
+
+// formatEscalation prefixes the standard notification, plain and HTML, with
+// the escalation tier and how long the error has gone unresolved
+func (n *ErrorNotifier) formatEscalation(e *escalation, target *AdminTarget) (message, formatted string) {
+	elapsed := time.Since(e.err.Timestamp).Round(time.Second)
+	header := fmt.Sprintf("⏫ ESCALATED (tier %d of %d): unresolved for %s", e.next+1, len(e.tiers), elapsed)
+	message = header + "\n" + n.formatMessage(e.err, target)
+	formatted = "<strong>" + html.EscapeString(header) + "</strong><br>\n" + n.formatHTMLMessage(e.err, target)
+	return message, formatted
+}
+
+// CancelEscalation stops any pending escalation for a trace. It returns
+// true if an escalation was pending.
+func (n *ErrorNotifier) CancelEscalation(traceID string) bool {
+	n.escMu.Lock()
+	defer n.escMu.Unlock()
+
+	e, ok := n.escalations[traceID]
+	if !ok {
+		return false
+	}
+	e.timer.Stop()
+	delete(n.escalations, traceID)
+	return true
+}
+
+// PendingEscalations returns the trace IDs with escalation still pending
+func (n *ErrorNotifier) PendingEscalations() []string {
+	n.escMu.Lock()
+	defer n.escMu.Unlock()
+
+	traceIDs := make([]string, 0, len(n.escalations))
+	for traceID := range n.escalations {
+		traceIDs = append(traceIDs, traceID)
+	}
+	return traceIDs
+}
+
+// stopEscalations cancels every pending escalation
+func (n *ErrorNotifier) stopEscalations() {
+	n.escMu.Lock()
+	defer n.escMu.Unlock()
+
+	for traceID, e := range n.escalations {
+		e.timer.Stop()
+		delete(n.escalations, traceID)
+	}
+}
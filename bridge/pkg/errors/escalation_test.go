@@ -0,0 +1,190 @@
+package errors
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newEscalationNotifier(store *ErrorStore, timeout time.Duration) *ErrorNotifier {
+	notifier := NewErrorNotifier(NotifierConfig{
+		Resolver: NewAdminResolver(AdminConfig{
+			ConfigAdminMXID: "@primary:example.com",
+			EscalationMXIDs: []string{"@secondary:example.com", "@tertiary:example.com"},
+		}),
+		Store:             store,
+		Enabled:           true,
+		EscalationTimeout: timeout,
+	})
+	notifier.SetDryRun(true)
+	return notifier
+}
+
+// waitForMessages polls the dry-run buffer until n messages are captured
+func waitForMessages(t *testing.T, notifier *ErrorNotifier, n int) []DryRunMessage {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		msgs := notifier.DryRunMessages()
+		if len(msgs) >= n {
+			return msgs
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d messages, want %d", len(msgs), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestAdminResolver_ResolveTiers(t *testing.T) {
+	resolver := NewAdminResolver(AdminConfig{
+		ConfigAdminMXID: "@primary:example.com",
+		EscalationMXIDs: []string{"@secondary:example.com", "@primary:example.com", "", "@secondary:example.com", "@tertiary:example.com"},
+	})
+
+	tiers, err := resolver.ResolveTiers(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveTiers() error = %v", err)
+	}
+
+	want := []string{"@primary:example.com", "@secondary:example.com", "@tertiary:example.com"}
+	if len(tiers) != len(want) {
+		t.Fatalf("ResolveTiers() returned %d tiers, want %d", len(tiers), len(want))
+	}
+	for i, mxid := range want {
+		if tiers[i].MXID != mxid {
+			t.Errorf("tier %d = %s, want %s", i, tiers[i].MXID, mxid)
+		}
+	}
+	if tiers[0].Source != "config" || tiers[1].Source != "escalation" {
+		t.Errorf("sources = %s, %s; want config, escalation", tiers[0].Source, tiers[1].Source)
+	}
+}
+
+func TestEscalation_NotifiesTiersInOrder(t *testing.T) {
+	notifier := newEscalationNotifier(nil, 20*time.Millisecond)
+	defer notifier.stopEscalations()
+
+	err := NewBuilder("SYS-001").WithSeverity(SeverityCritical).WithMessage("keystore unavailable").Build()
+	if notifyErr := notifier.Notify(context.Background(), err); notifyErr != nil {
+		t.Fatalf("Notify() error = %v", notifyErr)
+	}
+
+	msgs := waitForMessages(t, notifier, 3)
+	wantRooms := []string{"@primary:example.com", "@secondary:example.com", "@tertiary:example.com"}
+	for i, room := range wantRooms {
+		if msgs[i].RoomID != room {
+			t.Errorf("message %d sent to %s, want %s", i, msgs[i].RoomID, room)
+		}
+		if msgs[i].TraceID != err.TraceID {
+			t.Errorf("message %d trace = %s, want %s", i, msgs[i].TraceID, err.TraceID)
+		}
+	}
+	if strings.Contains(msgs[0].Message, "ESCALATED") {
+		t.Error("primary notification should not be marked escalated")
+	}
+	if !strings.Contains(msgs[1].Message, "ESCALATED (tier 2 of 3)") {
+		t.Errorf("secondary message missing escalation header: %s", msgs[1].Message)
+	}
+	if !strings.Contains(msgs[2].Message, "👤 Admin: @tertiary:example.com (via escalation)") {
+		t.Errorf("tertiary message missing admin line: %s", msgs[2].Message)
+	}
+
+	// All tiers notified; nothing left pending
+	if pending := notifier.PendingEscalations(); len(pending) != 0 {
+		t.Errorf("PendingEscalations() = %v, want none", pending)
+	}
+}
+
+func TestEscalation_OnlyCritical(t *testing.T) {
+	notifier := newEscalationNotifier(nil, 10*time.Millisecond)
+	defer notifier.stopEscalations()
+
+	err := NewBuilder("CTX-001").WithSeverity(SeverityError).Build()
+	if notifyErr := notifier.Notify(context.Background(), err); notifyErr != nil {
+		t.Fatalf("Notify() error = %v", notifyErr)
+	}
+
+	if pending := notifier.PendingEscalations(); len(pending) != 0 {
+		t.Errorf("PendingEscalations() = %v, want none for non-critical error", pending)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if msgs := notifier.DryRunMessages(); len(msgs) != 1 {
+		t.Errorf("got %d messages, want only the primary notification", len(msgs))
+	}
+}
+
+func TestEscalation_CancelledOnResolve(t *testing.T) {
+	storePath := testStorePath(t)
+	defer cleanupStore(t, storePath)
+
+	system, err := Initialize(Config{
+		StorePath:         storePath,
+		StoreEnabled:      true,
+		Enabled:           true,
+		NotifyEnabled:     true,
+		ConfigAdminMXID:   "@primary:example.com",
+		EscalationMXIDs:   []string{"@secondary:example.com"},
+		EscalationTimeout: "50ms",
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	defer system.Stop()
+	system.SetDryRun(true)
+
+	traced := NewBuilder("SYS-002").WithSeverity(SeverityCritical).Build()
+	if notifyErr := system.Notify(context.Background(), traced); notifyErr != nil {
+		t.Fatalf("Notify() error = %v", notifyErr)
+	}
+	if pending := system.GetNotifier().PendingEscalations(); len(pending) != 1 {
+		t.Fatalf("PendingEscalations() = %v, want one", pending)
+	}
+
+	if resolveErr := system.Resolve(context.Background(), traced.TraceID, "@primary:example.com"); resolveErr != nil {
+		t.Fatalf("Resolve() error = %v", resolveErr)
+	}
+	if pending := system.GetNotifier().PendingEscalations(); len(pending) != 0 {
+		t.Errorf("PendingEscalations() = %v after resolve, want none", pending)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	for _, msg := range system.DryRunMessages() {
+		if msg.RoomID == "@secondary:example.com" {
+			t.Error("secondary admin notified after error was resolved")
+		}
+	}
+}
+
+func TestEscalation_SkippedWhenResolvedInStore(t *testing.T) {
+	storePath := testStorePath(t)
+	defer cleanupStore(t, storePath)
+
+	store, err := NewErrorStore(StoreConfig{Path: storePath})
+	if err != nil {
+		t.Fatalf("NewErrorStore() error = %v", err)
+	}
+	defer store.Close()
+
+	notifier := newEscalationNotifier(store, 30*time.Millisecond)
+	defer notifier.stopEscalations()
+
+	traced := NewBuilder("SYS-003").WithSeverity(SeverityCritical).Build()
+	if notifyErr := notifier.Notify(context.Background(), traced); notifyErr != nil {
+		t.Fatalf("Notify() error = %v", notifyErr)
+	}
+
+	// Resolved directly in the store rather than through System.Resolve
+	if resolveErr := store.Resolve(context.Background(), traced.TraceID, "@primary:example.com"); resolveErr != nil {
+		t.Fatalf("Resolve() error = %v", resolveErr)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if msgs := notifier.DryRunMessages(); len(msgs) != 1 {
+		t.Errorf("got %d messages, want only the primary notification", len(msgs))
+	}
+	if pending := notifier.PendingEscalations(); len(pending) != 0 {
+		t.Errorf("PendingEscalations() = %v, want none", pending)
+	}
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ExportJSON streams all errors matching the query to w as a JSON array,
+// including the full trace, call stack, and state snapshots. Rows are
+// written as they are read so large stores are never held in memory.
+// A query Limit <= 0 exports every matching error.
+func (s *System) ExportJSON(ctx context.Context, w io.Writer, query ErrorQuery) error {
+	if s.store == nil {
+		return fmt.Errorf("error store not configured")
+	}
+	_, err := s.store.ExportJSON(ctx, w, query)
+	return err
+}
+
+// ExportJSON streams matching errors to w as a JSON array and returns the
+// number of errors written
+func (s *ErrorStore) ExportJSON(ctx context.Context, w io.Writer, query ErrorQuery) (int, error) {
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.Each(ctx, query, func(se StoredError) error {
+		data, err := json.Marshal(se)
+		if err != nil {
+			return fmt.Errorf("failed to serialize %s: %w", se.TraceID, err)
+		}
+
+		if count > 0 {
+			if _, err := io.WriteString(w, ",\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if _, err := io.WriteString(w, "\n]\n"); err != nil {
+		return count, err
+	}
+	return count, nil
+}
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestErrorStore_ExportJSON(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	// More rows than the default Query limit to ensure export is unbounded
+	for i := 0; i < 25; i++ {
+		err := NewBuilder(fmt.Sprintf("CTX-%03d", i)).
+			WithFunction("StartContainer").
+			WithInput("id", i).
+			WithStateValue("running", false).
+			Build()
+		store.Store(ctx, err)
+	}
+
+	var buf bytes.Buffer
+	count, err := store.ExportJSON(ctx, &buf, ErrorQuery{})
+	if err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	if count != 25 {
+		t.Errorf("ExportJSON() count = %d, want 25", count)
+	}
+
+	var exported []StoredError
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatalf("Export is not a valid JSON array: %v\n%s", err, buf.String())
+	}
+	if len(exported) != 25 {
+		t.Fatalf("Decoded %d errors, want 25", len(exported))
+	}
+
+	trace := exported[0].Trace
+	if trace == nil {
+		t.Fatal("Exported error should include the full trace")
+	}
+	if trace.Function != "StartContainer" || trace.State["running"] != false || len(trace.Stack) == 0 {
+		t.Errorf("Trace missing details: %+v", trace)
+	}
+}
+
+func TestErrorStore_EachReleasesLock(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	// More rows than one batch, so Each reloads between callbacks
+	for i := 0; i < eachBatchSize+5; i++ {
+		store.Store(ctx, NewBuilder(fmt.Sprintf("CTX-%03d", i)).Build())
+	}
+
+	// fn writes to the store; it would deadlock if Each held the lock
+	done := make(chan int, 1)
+	go func() {
+		n := 0
+		store.Each(ctx, ErrorQuery{OrderDesc: true}, func(se StoredError) error {
+			n++
+			return store.Resolve(ctx, se.TraceID, "@admin:example.com")
+		})
+		done <- n
+	}()
+
+	select {
+	case n := <-done:
+		if n != eachBatchSize+5 {
+			t.Errorf("Each visited %d errors, want %d", n, eachBatchSize+5)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Each held the store lock while calling fn")
+	}
+}
+
+func TestErrorStore_ExportJSON_Empty(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	var buf bytes.Buffer
+	count, err := store.ExportJSON(context.Background(), &buf, ErrorQuery{Code: "NONE-001"})
+	if err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("count = %d, want 0", count)
+	}
+
+	var exported []StoredError
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatalf("Empty export should be a valid JSON array: %v", err)
+	}
+}
+
+func TestSystem_ExportJSON(t *testing.T) {
+	storePath := testStorePath(t)
+	defer cleanupStore(t, storePath)
+
+	system, _ := Initialize(Config{StorePath: storePath, StoreEnabled: true})
+	defer system.Stop()
+
+	ctx := context.Background()
+	system.Store(ctx, &TracedError{Code: "CTX-001", Category: "container", Severity: SeverityError, Message: "a", TraceID: "tr_a", Timestamp: time.Now()})
+	system.Store(ctx, &TracedError{Code: "MAT-001", Category: "matrix", Severity: SeverityError, Message: "b", TraceID: "tr_b", Timestamp: time.Now()})
+
+	var buf bytes.Buffer
+	if err := system.ExportJSON(ctx, &buf, ErrorQuery{Category: "matrix"}); err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+
+	var exported []StoredError
+	json.Unmarshal(buf.Bytes(), &exported)
+	if len(exported) != 1 || exported[0].TraceID != "tr_b" {
+		t.Errorf("Filtered export = %+v, want only tr_b", exported)
+	}
+
+	// Store disabled
+	noStore, _ := Initialize(Config{StoreEnabled: false})
+	if err := noStore.ExportJSON(ctx, &buf, ErrorQuery{}); err == nil {
+		t.Error("ExportJSON should fail without a store")
+	}
+}
@@ -16,6 +16,13 @@ type System struct {
 	notifier  *ErrorNotifier
 	tracker   *ComponentTracker
 
+	// Background loops
+	digestInterval time.Duration
+	retryInterval  time.Duration
+	retryKick      chan struct{}
+	stopCh         chan struct{}
+	wg             sync.WaitGroup
+
 	mu       sync.RWMutex
 	started  bool
 }
@@ -31,8 +38,16 @@ type Config struct {
 	RetentionDays  int
 
 	// Sampling configuration
-	RateLimitWindow string // e.g., "5m"
-	RetentionPeriod string // e.g., "24h"
+	RateLimitWindow string                   // e.g., "5m"
+	CodeWindows     map[string]time.Duration // Per-code ("CTX-003") or per-prefix ("CTX") window overrides
+	RetentionPeriod string                   // e.g., "24h"
+
+	// Digest configuration
+	DigestInterval string // e.g., "15m"; empty disables digest mode
+
+	// Delivery retry configuration
+	RetryInterval    string // How often queued notifications are retried (default "30s")
+	MaxNotifyRetries int    // Attempts before a queued notification is marked failed (default 10)
 
 	// Admin configuration
 	ConfigAdminMXID string
@@ -40,6 +55,10 @@ type Config struct {
 	AdminRoomID     string
 	FallbackMXID    string
 
+	// Escalation configuration
+	EscalationMXIDs   []string // Notified in order while a critical error stays unresolved
+	EscalationTimeout string   // Time before each escalation step (default "15m")
+
 	// Matrix integration
 	MatrixSender   MatrixMessageSender
 	MatrixAdapter  MatrixAdminAdapter
@@ -75,10 +94,14 @@ func Initialize(cfg Config) (*System, error) {
 	// Parse durations
 	rateLimitWindow := parseDuration(cfg.RateLimitWindow, 5*60*1000) // 5 minutes default
 	retentionPeriod := parseDuration(cfg.RetentionPeriod, 24*60*60*1000) // 24 hours default
+	digestInterval := parseDuration(cfg.DigestInterval, 0)               // disabled by default
+	retryInterval := parseDuration(cfg.RetryInterval, 30*1000)           // 30 seconds default
+	escalationTimeout := parseDuration(cfg.EscalationTimeout, DefaultEscalationTimeout.Milliseconds())
 
 	// Create sampling registry
 	registry := NewSamplingRegistry(SamplingConfig{
 		RateLimitWindow: rateLimitWindow,
+		CodeWindows:     cfg.CodeWindows,
 		RetentionPeriod:  retentionPeriod,
 	})
 
@@ -89,6 +112,7 @@ func Initialize(cfg Config) (*System, error) {
 		AdminRoomID:     cfg.AdminRoomID,
 		MatrixAdapter:   cfg.MatrixAdapter,
 		FallbackMXID:    cfg.FallbackMXID,
+		EscalationMXIDs: cfg.EscalationMXIDs,
 	})
 
 	// Create store (optional)
@@ -111,6 +135,10 @@ func Initialize(cfg Config) (*System, error) {
 		Store:        store,
 		MatrixSender: cfg.MatrixSender,
 		Enabled:      cfg.Enabled && cfg.NotifyEnabled,
+		Digest:       digestInterval > 0,
+		MaxRetries:   cfg.MaxNotifyRetries,
+
+		EscalationTimeout: escalationTimeout,
 	})
 
 	// Create component tracker for errors package itself
@@ -123,6 +151,10 @@ func Initialize(cfg Config) (*System, error) {
 		store:    store,
 		notifier: notifier,
 		tracker:  tracker,
+
+		digestInterval: digestInterval,
+		retryInterval:  retryInterval,
+		retryKick:      make(chan struct{}, 1),
 	}
 
 	// Set as global
@@ -146,6 +178,18 @@ func (s *System) Start(ctx context.Context) error {
 	}
 
 	s.tracker.Event("system_start", nil)
+	s.stopCh = make(chan struct{})
+
+	if s.digestInterval > 0 {
+		s.wg.Add(1)
+		go s.digestLoop(s.stopCh)
+	}
+
+	if s.store != nil {
+		s.wg.Add(1)
+		go s.retryLoop(s.stopCh)
+	}
+
 	s.started = true
 
 	return nil
@@ -162,14 +206,84 @@ func (s *System) Stop() error {
 
 	s.tracker.Event("system_stop", nil)
 
+	// Stop background loops and flush anything still batched
+	close(s.stopCh)
+	s.wg.Wait()
+	s.notifier.stopEscalations()
+	if err := s.notifier.FlushDigest(context.Background()); err != nil {
+		s.tracker.Failure("digest_flush", err, nil)
+	}
+
+	s.started = false
+
 	if s.store != nil {
 		return s.store.Close()
 	}
 
-	s.started = false
 	return nil
 }
 
+// digestLoop periodically flushes batched notifications
+func (s *System) digestLoop(stopCh <-chan struct{}) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.digestInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := s.notifier.FlushDigest(context.Background()); err != nil {
+				s.tracker.Failure("digest_flush", err, nil)
+			}
+		}
+	}
+}
+
+// retryLoop periodically redelivers queued notifications that are due, and
+// all queued notifications when a new Matrix sender is attached
+func (s *System) retryLoop(stopCh <-chan struct{}) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		retry := s.notifier.RetryPending
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		case <-s.retryKick:
+			retry = s.notifier.RetryAllPending
+		}
+
+		delivered, failed, err := retry(context.Background())
+		if err != nil {
+			s.tracker.Failure("notification_retry", err, nil)
+			continue
+		}
+		if delivered > 0 || failed > 0 {
+			s.tracker.Event("notification_retry", map[string]interface{}{
+				"delivered": delivered,
+				"failed":    failed,
+			})
+		}
+	}
+}
+
+// RetryPending immediately attempts delivery of queued notifications
+func (s *System) RetryPending(ctx context.Context) (delivered, failed int, err error) {
+	return s.notifier.RetryPending(ctx)
+}
+
+// FlushDigest immediately sends any batched digest notifications
+func (s *System) FlushDigest(ctx context.Context) error {
+	return s.notifier.FlushDigest(ctx)
+}
+
 // Notify sends an error notification
 func (s *System) Notify(ctx context.Context, err *TracedError) error {
 	return s.notifier.Notify(ctx, err)
@@ -196,8 +310,9 @@ func (s *System) Query(ctx context.Context, q ErrorQuery) ([]StoredError, error)
 	return s.store.Query(ctx, q)
 }
 
-// Resolve marks an error as resolved
+// Resolve marks an error as resolved and cancels any pending escalation
 func (s *System) Resolve(ctx context.Context, traceID, resolvedBy string) error {
+	s.notifier.CancelEscalation(traceID)
 	if s.store == nil {
 		return fmt.Errorf("error store not configured")
 	}
@@ -226,6 +341,20 @@ func (s *System) Stats(ctx context.Context) SystemStats {
 	return stats
 }
 
+// ErrorStats returns store statistics for errors seen within the given
+// window (0 = all time), including the topN most frequent codes
+func (s *System) ErrorStats(ctx context.Context, window time.Duration, topN int) (StoreStats, error) {
+	if s.store == nil {
+		return StoreStats{}, fmt.Errorf("error store not configured")
+	}
+
+	var since time.Time
+	if window > 0 {
+		since = time.Now().Add(-window)
+	}
+	return s.store.WindowStats(ctx, since, topN)
+}
+
 // SystemStats holds statistics about the error system
 type SystemStats struct {
 	Sampling    SamplingStats `json:"sampling"`
@@ -233,9 +362,21 @@ type SystemStats struct {
 	AdminSource string        `json:"admin_source"`
 }
 
-// SetMatrixSender updates the Matrix sender
+// SetMatrixSender updates the Matrix sender and triggers redelivery of
+// any notifications queued while the previous sender was unavailable
 func (s *System) SetMatrixSender(sender MatrixMessageSender) {
 	s.notifier.SetMatrixSender(sender)
+
+	select {
+	case s.retryKick <- struct{}{}:
+	default:
+	}
+}
+
+// SetCriticalHook sets a function called for every critical error that
+// passes sampling, alongside the admin notification
+func (s *System) SetCriticalHook(hook func(code, message string)) {
+	s.notifier.SetCriticalHook(hook)
 }
 
 // SetMatrixAdapter updates the Matrix adapter for admin resolution
@@ -248,6 +389,20 @@ func (s *System) SetAdminMXID(mxid string) {
 	s.resolver.SetConfigAdmin(mxid)
 }
 
+// SetRateLimits replaces the notification rate limit window and the
+// per-code overrides, e.g. on a configuration reload. An empty or invalid
+// window falls back to the 5 minute default.
+func (s *System) SetRateLimits(window string, codeWindows map[string]time.Duration) {
+	s.registry.SetRateLimitWindow(parseDuration(window, 5*60*1000))
+	s.registry.SetCodeWindows(codeWindows)
+}
+
+// SetEscalation sets the admin tiers notified when a critical error stays
+// unresolved
+func (s *System) SetEscalation(mxids []string) {
+	s.resolver.SetEscalation(mxids)
+}
+
 // SetSetupUser sets the setup user MXID
 func (s *System) SetSetupUser(mxid string) {
 	s.resolver.SetSetupUser(mxid)
@@ -258,6 +413,17 @@ func (s *System) SetAdminRoom(roomID string) {
 	s.resolver.SetAdminRoom(roomID)
 }
 
+// SetDryRun routes notifications to an in-memory buffer instead of
+// Matrix, for asserting on notification content in tests
+func (s *System) SetDryRun(enabled bool) {
+	s.notifier.SetDryRun(enabled)
+}
+
+// DryRunMessages returns the notifications captured in dry-run mode
+func (s *System) DryRunMessages() []DryRunMessage {
+	return s.notifier.DryRunMessages()
+}
+
 // SetEnabled enables or disables notifications
 func (s *System) SetEnabled(enabled bool) {
 	s.notifier.SetEnabled(enabled)
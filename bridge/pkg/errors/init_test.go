@@ -2,9 +2,11 @@ package errors
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 )
@@ -165,6 +167,83 @@ func TestSystem_Notify(t *testing.T) {
 	system.Stop()
 }
 
+func TestSystem_DryRun(t *testing.T) {
+	mockSender := &mockMatrixSender{}
+
+	system, _ := Initialize(Config{
+		MatrixSender:  mockSender,
+		SetupUserMXID: "@admin:example.com",
+		Enabled:       true,
+		NotifyEnabled: true,
+		StoreEnabled:  false,
+	})
+	defer system.Stop()
+
+	system.SetDryRun(true)
+
+	err := &TracedError{
+		Code:      "CTX-001",
+		Category:  "container",
+		Severity:  SeverityError,
+		Message:   "test error",
+		Function:  "StartContainer",
+		TraceID:   "tr_dry",
+		Timestamp: time.Now(),
+	}
+	if notifyErr := system.Notify(context.Background(), err); notifyErr != nil {
+		t.Fatalf("Notify() error = %v", notifyErr)
+	}
+
+	if mockSender.callCount != 0 {
+		t.Errorf("SendMessage called %d times in dry-run mode, want 0", mockSender.callCount)
+	}
+
+	messages := system.DryRunMessages()
+	if len(messages) != 1 {
+		t.Fatalf("DryRunMessages() returned %d messages, want 1", len(messages))
+	}
+	msg := messages[0]
+	if msg.RoomID != "@admin:example.com" || msg.TraceID != "tr_dry" || msg.Code != "CTX-001" {
+		t.Errorf("Unexpected dry-run message: %+v", msg)
+	}
+	if !strings.Contains(msg.Message, "❌ ERROR: CTX-001") || !strings.Contains(msg.Message, "```json") {
+		t.Errorf("Dry-run message should use the hybrid format, got:\n%s", msg.Message)
+	}
+
+	// Disabling keeps captured messages and resumes real delivery
+	system.SetDryRun(false)
+	system.NotifyQuick(context.Background(), "MAT-001", "sync failed", SeverityError)
+	if mockSender.callCount != 1 {
+		t.Errorf("SendMessage called %d times after disabling dry-run, want 1", mockSender.callCount)
+	}
+	if len(system.DryRunMessages()) != 1 {
+		t.Error("Captured messages should remain readable after disabling dry-run")
+	}
+
+	// Re-enabling starts with an empty buffer
+	system.SetDryRun(true)
+	if len(system.DryRunMessages()) != 0 {
+		t.Error("Re-enabling dry-run should clear captured messages")
+	}
+}
+
+func TestDryRunBuffer_Wraps(t *testing.T) {
+	buf := newDryRunBuffer(3)
+	for i := 0; i < 5; i++ {
+		buf.add(DryRunMessage{Code: fmt.Sprintf("CTX-%03d", i)})
+	}
+
+	messages := buf.all()
+	if len(messages) != 3 {
+		t.Fatalf("all() returned %d messages, want 3", len(messages))
+	}
+	for i, want := range []string{"CTX-002", "CTX-003", "CTX-004"} {
+		if messages[i].Code != want {
+			t.Errorf("messages[%d].Code = %s, want %s", i, messages[i].Code, want)
+		}
+	}
+}
+
 func TestSystem_Store(t *testing.T) {
 	storePath := testStorePath(t)
 	defer cleanupStore(t, storePath)
@@ -296,6 +375,19 @@ func TestSystem_Setters(t *testing.T) {
 		t.Error("SetAdminRoom failed")
 	}
 
+	// Test SetRateLimits
+	system.SetRateLimits("10m", map[string]time.Duration{"CTX": time.Minute})
+	if got := system.registry.WindowFor("MAT-001"); got != 10*time.Minute {
+		t.Errorf("SetRateLimits window = %v, want 10m", got)
+	}
+	if got := system.registry.WindowFor("CTX-003"); got != time.Minute {
+		t.Errorf("SetRateLimits CTX override = %v, want 1m", got)
+	}
+	system.SetRateLimits("", nil)
+	if got := system.registry.WindowFor("CTX-003"); got != 5*time.Minute {
+		t.Errorf("SetRateLimits reset = %v, want the 5m default", got)
+	}
+
 	// Test SetEnabled
 	system.SetEnabled(false)
 	if system.IsEnabled() {
@@ -3,6 +3,8 @@ package errors
 import (
 	"context"
 	"fmt"
+	"html"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
@@ -20,8 +22,25 @@ type ErrorNotifier struct {
 	// Matrix sender
 	matrixSender MatrixMessageSender
 
+	// criticalHook is also told about critical errors, e.g. to push them
+	// to mobile devices
+	criticalHook func(code, message string)
+
+	// Digest buffer for batched non-critical notifications (nil = disabled)
+	digest *DigestBuffer
+
+	// Dry-run mode captures formatted messages instead of sending them
+	dryRun    bool
+	dryRunLog *dryRunBuffer
+
+	// Pending escalations of unresolved critical errors, by trace ID
+	escMu             sync.Mutex
+	escalations       map[string]*escalation
+	escalationTimeout time.Duration
+
 	// Configuration
-	enabled bool
+	enabled    bool
+	maxRetries int
 }
 
 // MatrixMessageSender is the interface for sending Matrix messages
@@ -29,6 +48,14 @@ type MatrixMessageSender interface {
 	SendMessage(ctx context.Context, roomID, message, msgType string) (string, error)
 }
 
+// MatrixHTMLSender is optionally implemented by a MatrixMessageSender that
+// can send an org.matrix.custom.html formatted_body with the plain message.
+// Notifications are sent as HTML to senders that implement it, so the JSON
+// block renders as a real code block.
+type MatrixHTMLSender interface {
+	SendHTMLMessage(ctx context.Context, roomID, message, formattedBody, msgType string) (string, error)
+}
+
 // NotifierConfig configures the error notifier
 type NotifierConfig struct {
 	Registry     *SamplingRegistry
@@ -36,17 +63,38 @@ type NotifierConfig struct {
 	Store        *ErrorStore
 	MatrixSender MatrixMessageSender
 	Enabled      bool
+	Digest       bool // Batch non-critical notifications until FlushDigest
+	MaxRetries   int  // Delivery attempts for queued notifications (default 10)
+
+	// EscalationTimeout is how long a critical error may stay unresolved
+	// before the next admin tier is notified (0 = DefaultEscalationTimeout)
+	EscalationTimeout time.Duration
 }
 
 // NewErrorNotifier creates a new error notifier
 func NewErrorNotifier(cfg NotifierConfig) *ErrorNotifier {
-	return &ErrorNotifier{
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxNotifyRetries
+	}
+	if cfg.EscalationTimeout <= 0 {
+		cfg.EscalationTimeout = DefaultEscalationTimeout
+	}
+
+	n := &ErrorNotifier{
 		registry:     cfg.Registry,
 		resolver:     cfg.Resolver,
 		store:        cfg.Store,
 		matrixSender: cfg.MatrixSender,
 		enabled:      cfg.Enabled,
+		maxRetries:   cfg.MaxRetries,
+
+		escalations:       make(map[string]*escalation),
+		escalationTimeout: cfg.EscalationTimeout,
 	}
+	if cfg.Digest {
+		n.digest = NewDigestBuffer()
+	}
+	return n
 }
 
 // Notify processes an error and sends notification if appropriate
@@ -67,8 +115,10 @@ func (n *ErrorNotifier) Notify(ctx context.Context, err *TracedError) error {
 		return nil
 	}
 
-	// Get recent logs from components
-	err.RecentLogs = n.getRecentLogs(err.Category)
+	// Get recent logs from components unless the builder attached them
+	if len(err.RecentLogs) == 0 {
+		err.RecentLogs = n.getRecentLogs(err.Category)
+	}
 
 	// Store the error
 	if n.store != nil {
@@ -78,6 +128,16 @@ func (n *ErrorNotifier) Notify(ctx context.Context, err *TracedError) error {
 		}
 	}
 
+	if err.Severity == SeverityCritical && n.criticalHook != nil {
+		n.criticalHook(err.Code, err.Message)
+	}
+
+	// In digest mode, batch non-critical errors for the next flush
+	if n.digest != nil && err.Severity != SeverityCritical {
+		n.digest.Add(err)
+		return nil
+	}
+
 	// Resolve admin
 	if n.resolver == nil {
 		return fmt.Errorf("no admin resolver configured")
@@ -90,19 +150,146 @@ func (n *ErrorNotifier) Notify(ctx context.Context, err *TracedError) error {
 
 	// Format message
 	message := n.formatMessage(err, admin)
+	formatted := n.formatHTMLMessage(err, admin)
 
 	// Send notification
-	if n.matrixSender != nil {
-		// Send as direct message (m.notice for less intrusive)
-		_, err2 = n.matrixSender.SendMessage(ctx, admin.MXID, message, "m.notice")
-		if err2 != nil {
-			return fmt.Errorf("failed to send notification: %w", err2)
-		}
+	if err2 = n.send(ctx, admin.MXID, err.TraceID, err.Code, message, formatted); err2 != nil {
+		return fmt.Errorf("failed to send notification: %w", err2)
 	}
+	n.scheduleEscalation(ctx, err)
 
 	return nil
 }
 
+// send delivers a message as a direct notice (less intrusive), persisting
+// it for later retry if delivery fails. The HTML rendering is used when the
+// sender supports it; queued retries carry only the plain message. With no
+// sender attached yet the message is queued without an attempt, to go out
+// when SetMatrixSender is called. In dry-run mode the message is captured
+// instead of sent.
+func (n *ErrorNotifier) send(ctx context.Context, roomID, traceID, code, message, formatted string) error {
+	if n.dryRun {
+		n.dryRunLog.add(DryRunMessage{
+			RoomID:           roomID,
+			TraceID:          traceID,
+			Code:             code,
+			Message:          message,
+			FormattedMessage: formatted,
+			MsgType:          "m.notice",
+			Timestamp:        time.Now(),
+		})
+		return nil
+	}
+
+	if n.matrixSender == nil {
+		if n.store == nil {
+			return nil
+		}
+		_, qErr := n.store.EnqueueNotification(ctx, PendingNotification{
+			TraceID:     traceID,
+			Code:        code,
+			RoomID:      roomID,
+			Message:     message,
+			MsgType:     "m.notice",
+			NextAttempt: time.Now(),
+		})
+		return qErr
+	}
+
+	var err error
+	if htmlSender, ok := n.matrixSender.(MatrixHTMLSender); ok && formatted != "" {
+		_, err = htmlSender.SendHTMLMessage(ctx, roomID, message, formatted, "m.notice")
+	} else {
+		_, err = n.matrixSender.SendMessage(ctx, roomID, message, "m.notice")
+	}
+	if err == nil || n.store == nil {
+		return err
+	}
+
+	_, qErr := n.store.EnqueueNotification(ctx, PendingNotification{
+		TraceID:   traceID,
+		Code:      code,
+		RoomID:    roomID,
+		Message:   message,
+		MsgType:   "m.notice",
+		Attempts:  1,
+		LastError: err.Error(),
+	})
+	if qErr != nil {
+		slog.Warn("error_notification_queue_failed", "code", code, "trace_id", traceID, "error", qErr)
+	}
+
+	return err
+}
+
+// RetryPending attempts delivery of queued notifications that are due.
+// Delivered notifications are marked notified; those that exhaust
+// MaxRetries are marked failed and logged.
+func (n *ErrorNotifier) RetryPending(ctx context.Context) (delivered, failed int, err error) {
+	return n.retryQueued(ctx, false)
+}
+
+// RetryAllPending attempts delivery of every queued notification, ignoring
+// backoff. It is used when a new Matrix sender is attached, since the
+// failures being backed off from belonged to the previous one.
+func (n *ErrorNotifier) RetryAllPending(ctx context.Context) (delivered, failed int, err error) {
+	return n.retryQueued(ctx, true)
+}
+
+// retryQueued redelivers queued notifications, all of them or only those due
+func (n *ErrorNotifier) retryQueued(ctx context.Context, all bool) (delivered, failed int, err error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	// Queued messages stay queued while in dry-run mode
+	if n.store == nil || n.matrixSender == nil || n.dryRun {
+		return 0, 0, nil
+	}
+
+	now := time.Now()
+	var due []PendingNotification
+	if all {
+		due, err = n.store.PendingNotifications(ctx)
+	} else {
+		due, err = n.store.DueNotifications(ctx, now, 0)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, p := range due {
+		_, sendErr := n.matrixSender.SendMessage(ctx, p.RoomID, p.Message, p.MsgType)
+		if sendErr == nil {
+			if err := n.store.MarkNotificationDelivered(ctx, p.ID); err != nil {
+				return delivered, failed, err
+			}
+			delivered++
+			continue
+		}
+
+		if p.Attempts+1 >= n.maxRetries {
+			if err := n.store.MarkNotificationFailed(ctx, p.ID, sendErr.Error()); err != nil {
+				return delivered, failed, err
+			}
+			slog.Error("error_notification_delivery_failed",
+				"code", p.Code,
+				"trace_id", p.TraceID,
+				"attempts", p.Attempts+1,
+				"error", sendErr,
+			)
+			failed++
+			continue
+		}
+
+		next := now.Add(notifyBackoff(p.Attempts))
+		if err := n.store.MarkNotificationRetry(ctx, p.ID, sendErr.Error(), next); err != nil {
+			return delivered, failed, err
+		}
+	}
+
+	return delivered, failed, nil
+}
+
 // getRecentLogs retrieves recent logs from relevant components
 func (n *ErrorNotifier) getRecentLogs(category string) []ComponentLogEntry {
 	// Get logs from the failing component plus related components
@@ -144,36 +331,56 @@ func (n *ErrorNotifier) formatMessage(err *TracedError, admin *AdminTarget) stri
 
 	// JSON block
 	sb.WriteString("```json\n")
-	jsonStr, jsonErr := err.FormatJSON()
-	if jsonErr != nil {
-		sb.WriteString(fmt.Sprintf(`{"error": "failed to serialize: %s"}`, jsonErr))
-	} else {
-		sb.WriteString(jsonStr)
-	}
+	sb.WriteString(formatJSONBlock(err))
 	sb.WriteString("\n```\n\n")
 
 	// Footer
-	sb.WriteString("📋 Copy the JSON block above to analyze with an LLM.")
+	sb.WriteString(notificationFooter)
 
 	return sb.String()
 }
 
-// formatHeader creates the notification header
-func (n *ErrorNotifier) formatHeader(err *TracedError) string {
-	var emoji string
-	switch err.Severity {
-	case SeverityCritical:
-		emoji = "🔴"
-	case SeverityError:
-		emoji = "❌"
-	case SeverityWarning:
-		emoji = "⚠️"
-	default:
-		emoji = "ℹ️"
+// formatHTMLMessage renders the same notification as formatMessage as
+// org.matrix.custom.html, with the JSON in a <pre><code> block
+func (n *ErrorNotifier) formatHTMLMessage(err *TracedError, admin *AdminTarget) string {
+	var sb strings.Builder
+
+	sb.WriteString("<strong>")
+	sb.WriteString(html.EscapeString(n.formatHeader(err)))
+	sb.WriteString("</strong><br>\n")
+
+	sb.WriteString(html.EscapeString(n.formatSummary(err)))
+	sb.WriteString("<br><br>\n")
+
+	metadata := html.EscapeString(n.formatMetadata(err, admin))
+	sb.WriteString(strings.ReplaceAll(metadata, "\n", "<br>\n"))
+	sb.WriteString("<br><br>\n")
+
+	sb.WriteString(`<pre><code class="language-json">`)
+	sb.WriteString(html.EscapeString(formatJSONBlock(err)))
+	sb.WriteString("</code></pre>\n")
+
+	sb.WriteString(html.EscapeString(notificationFooter))
+
+	return sb.String()
+}
+
+// notificationFooter closes every error notification
+const notificationFooter = "📋 Copy the JSON block above to analyze with an LLM."
+
+// formatJSONBlock returns the error's JSON for the notification code block
+func formatJSONBlock(err *TracedError) string {
+	jsonStr, jsonErr := err.FormatJSON()
+	if jsonErr != nil {
+		return fmt.Sprintf(`{"error": "failed to serialize: %s"}`, jsonErr)
 	}
+	return jsonStr
+}
 
+// formatHeader creates the notification header
+func (n *ErrorNotifier) formatHeader(err *TracedError) string {
 	severity := strings.ToUpper(string(err.Severity))
-	return fmt.Sprintf("%s %s: %s", emoji, severity, err.Code)
+	return fmt.Sprintf("%s %s: %s", severityEmoji(err.Severity), severity, err.Code)
 }
 
 // formatSummary creates a brief error summary
@@ -201,6 +408,10 @@ func (n *ErrorNotifier) formatMetadata(err *TracedError, admin *AdminTarget) str
 	lines = append(lines, fmt.Sprintf("🏷️ Trace ID: %s", err.TraceID))
 	lines = append(lines, fmt.Sprintf("⏰ %s", err.Timestamp.UTC().Format("2006-01-02 15:04:05 UTC")))
 
+	if err.Build != nil {
+		lines = append(lines, fmt.Sprintf("🏗️ Build: %s", err.Build))
+	}
+
 	if err.RepeatCount > 0 {
 		lines = append(lines, fmt.Sprintf("🔁 Repeated %d times since last notification", err.RepeatCount))
 	}
@@ -221,10 +432,97 @@ func (n *ErrorNotifier) NotifyQuick(ctx context.Context, code, message string, s
 		Message:   message,
 		TraceID:   generateTraceID(),
 		Timestamp: time.Now(),
+		Build:     currentBuildInfo(),
 	}
 	return n.Notify(ctx, err)
 }
 
+// FlushDigest sends all batched notifications as a single summary message
+func (n *ErrorNotifier) FlushDigest(ctx context.Context) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.digest == nil {
+		return nil
+	}
+
+	entries := n.digest.Drain()
+	if len(entries) == 0 || !n.enabled {
+		return nil
+	}
+
+	if n.resolver == nil {
+		return fmt.Errorf("no admin resolver configured")
+	}
+
+	admin, err := n.resolver.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve admin: %w", err)
+	}
+
+	err = n.send(ctx, admin.MXID, "", "DIGEST", FormatDigest(entries, time.Now()), "")
+	if err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+
+	return nil
+}
+
+// SetDigestEnabled enables or disables digest batching
+// Disabling drops any notifications that have not been flushed yet
+func (n *ErrorNotifier) SetDigestEnabled(enabled bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if !enabled {
+		n.digest = nil
+		return
+	}
+	if n.digest == nil {
+		n.digest = NewDigestBuffer()
+	}
+}
+
+// IsDigestEnabled returns whether digest batching is enabled
+func (n *ErrorNotifier) IsDigestEnabled() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.digest != nil
+}
+
+// SetDryRun enables or disables dry-run mode. While enabled, formatted
+// notifications are captured in memory instead of sent to Matrix.
+// Enabling starts with an empty capture buffer; disabling keeps the
+// captured messages readable.
+func (n *ErrorNotifier) SetDryRun(enabled bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if enabled && !n.dryRun {
+		n.dryRunLog = newDryRunBuffer(DefaultDryRunCapacity)
+	}
+	n.dryRun = enabled
+}
+
+// IsDryRun returns whether dry-run mode is enabled
+func (n *ErrorNotifier) IsDryRun() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.dryRun
+}
+
+// DryRunMessages returns the notifications captured in dry-run mode,
+// oldest first. Only the most recent DefaultDryRunCapacity are kept.
+func (n *ErrorNotifier) DryRunMessages() []DryRunMessage {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.dryRunLog == nil {
+		return nil
+	}
+	return n.dryRunLog.all()
+}
+
 // SetEnabled enables or disables notifications
 func (n *ErrorNotifier) SetEnabled(enabled bool) {
 	n.mu.Lock()
@@ -246,6 +544,14 @@ func (n *ErrorNotifier) SetMatrixSender(sender MatrixMessageSender) {
 	n.matrixSender = sender
 }
 
+// SetCriticalHook sets a function called for every critical error that
+// passes sampling. It must not block.
+func (n *ErrorNotifier) SetCriticalHook(hook func(code, message string)) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.criticalHook = hook
+}
+
 // SetResolver sets or updates the admin resolver
 func (n *ErrorNotifier) SetResolver(resolver *AdminResolver) {
 	n.mu.Lock()
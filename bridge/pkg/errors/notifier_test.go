@@ -27,6 +27,17 @@ func (m *mockMatrixSender) SendMessage(ctx context.Context, roomID, message, msg
 	return "event_id_123", nil
 }
 
+// mockHTMLSender also implements MatrixHTMLSender
+type mockHTMLSender struct {
+	mockMatrixSender
+	lastFormatted string
+}
+
+func (m *mockHTMLSender) SendHTMLMessage(ctx context.Context, roomID, message, formattedBody, msgType string) (string, error) {
+	m.lastFormatted = formattedBody
+	return m.SendMessage(ctx, roomID, message, msgType)
+}
+
 func TestErrorNotifier_Notify(t *testing.T) {
 	mockSender := &mockMatrixSender{}
 
@@ -173,6 +184,34 @@ func TestErrorNotifier_Notify_CriticalAlways(t *testing.T) {
 	}
 }
 
+func TestErrorNotifier_CriticalHook(t *testing.T) {
+	notifier := NewErrorNotifier(NotifierConfig{
+		Resolver:     NewAdminResolver(AdminConfig{SetupUserMXID: "@admin:example.com"}),
+		MatrixSender: &mockMatrixSender{},
+		Enabled:      true,
+	})
+
+	var codes []string
+	notifier.SetCriticalHook(func(code, message string) {
+		codes = append(codes, code)
+	})
+
+	for _, severity := range []Severity{SeverityError, SeverityCritical} {
+		notifier.Notify(context.Background(), &TracedError{
+			Code:      "SYS-001",
+			Category:  "system",
+			Severity:  severity,
+			Message:   "keystore unavailable",
+			TraceID:   "tr_" + string(severity),
+			Timestamp: time.Now(),
+		})
+	}
+
+	if len(codes) != 1 || codes[0] != "SYS-001" {
+		t.Errorf("critical hook calls = %v, want [SYS-001]", codes)
+	}
+}
+
 func TestErrorNotifier_FormatMessage(t *testing.T) {
 	notifier := NewErrorNotifier(NotifierConfig{Enabled: true})
 
@@ -248,6 +287,31 @@ func TestErrorNotifier_FormatHeader(t *testing.T) {
 	}
 }
 
+func TestErrorNotifier_Notify_SeverityOverride(t *testing.T) {
+	mockSender := &mockMatrixSender{}
+
+	notifier := NewErrorNotifier(NotifierConfig{
+		Registry:     NewSamplingRegistry(DefaultSamplingConfig()),
+		Resolver:     NewAdminResolver(AdminConfig{SetupUserMXID: "@admin:example.com"}),
+		MatrixSender: mockSender,
+		Enabled:      true,
+	})
+
+	// CTX-001 defaults to error; escalate it to critical
+	err := NewBuilder("CTX-001").WithSeverity(SeverityCritical).Build()
+
+	if notifyErr := notifier.Notify(context.Background(), err); notifyErr != nil {
+		t.Fatalf("Notify() error = %v", notifyErr)
+	}
+
+	if !strings.HasPrefix(mockSender.lastMessage, "🔴 CRITICAL: CTX-001") {
+		t.Errorf("Message header should reflect override, got %q", strings.SplitN(mockSender.lastMessage, "\n", 2)[0])
+	}
+	if !strings.Contains(mockSender.lastMessage, `"severity": "critical"`) {
+		t.Error("JSON block should contain overridden severity")
+	}
+}
+
 func TestErrorNotifier_FormatMetadata(t *testing.T) {
 	notifier := NewErrorNotifier(NotifierConfig{Enabled: true})
 
@@ -496,3 +560,56 @@ func TestErrorNotifier_FormatSummary_WithCause(t *testing.T) {
 		t.Error("Summary should contain cause")
 	}
 }
+
+func TestErrorNotifier_FormatHTMLMessage(t *testing.T) {
+	notifier := NewErrorNotifier(NotifierConfig{Enabled: true})
+
+	err := &TracedError{
+		Code:      "CTX-001",
+		Category:  "container",
+		Severity:  SeverityError,
+		Message:   "container start failed",
+		Function:  "StartContainer",
+		File:      "docker/client.go",
+		Line:      142,
+		TraceID:   "tr_test",
+		Timestamp: time.Date(2026, 2, 15, 18, 32, 5, 0, time.UTC),
+	}
+
+	message := notifier.formatHTMLMessage(err, &AdminTarget{MXID: "@admin:example.com", Source: "setup"})
+
+	for _, want := range []string{
+		"<strong>❌ ERROR: CTX-001</strong>",
+		`<pre><code class="language-json">`,
+		"&#34;trace_id&#34;: &#34;tr_test&#34;",
+		"</code></pre>",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("HTML message missing %q:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "```") {
+		t.Error("HTML message should not contain markdown fences")
+	}
+}
+
+func TestErrorNotifier_NotifySendsHTML(t *testing.T) {
+	sender := &mockHTMLSender{}
+	notifier := NewErrorNotifier(NotifierConfig{
+		Resolver:     NewAdminResolver(AdminConfig{SetupUserMXID: "@admin:example.com"}),
+		MatrixSender: sender,
+		Enabled:      true,
+	})
+
+	err := NewBuilder("CTX-001").Build()
+	if notifyErr := notifier.Notify(context.Background(), err); notifyErr != nil {
+		t.Fatalf("Notify() error = %v", notifyErr)
+	}
+
+	if !strings.Contains(sender.lastMessage, "```json") {
+		t.Error("plain fallback should keep the markdown JSON block")
+	}
+	if !strings.Contains(sender.lastFormatted, "<pre><code") {
+		t.Errorf("formatted body missing code block: %q", sender.lastFormatted)
+	}
+}
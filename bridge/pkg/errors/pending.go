@@ -0,0 +1,225 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Retry defaults for undelivered notifications
+const (
+	DefaultMaxNotifyRetries = 10
+	notifyRetryBaseBackoff  = 30 * time.Second
+	notifyRetryMaxBackoff   = 30 * time.Minute
+)
+
+// maxQueuedNotifications bounds the pending notifications table. Beyond it
+// the oldest rows are dropped, so a bridge that never gets a Matrix sender
+// cannot fill the database.
+const maxQueuedNotifications = 1000
+
+// PendingNotification is a notification that could not be delivered and
+// is waiting to be retried
+type PendingNotification struct {
+	ID          int64     `json:"id"`
+	TraceID     string    `json:"trace_id"`
+	Code        string    `json:"code"`
+	RoomID      string    `json:"room_id"`
+	Message     string    `json:"message"`
+	MsgType     string    `json:"msg_type"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	NextAttempt time.Time `json:"next_attempt"`
+	Notified    bool      `json:"notified"`
+	Failed      bool      `json:"failed"`
+}
+
+// migratePending creates the pending notifications table
+func (s *ErrorStore) migratePending() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS pending_notifications (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			trace_id      TEXT NOT NULL,
+			code          TEXT NOT NULL,
+			room_id       TEXT NOT NULL,
+			message       TEXT NOT NULL,
+			msg_type      TEXT NOT NULL,
+			attempts      INTEGER DEFAULT 0,
+			last_error    TEXT,
+			created_at    TIMESTAMP NOT NULL,
+			next_attempt  TIMESTAMP NOT NULL,
+			notified      BOOLEAN DEFAULT FALSE,
+			failed        BOOLEAN DEFAULT FALSE,
+			delivered_at  TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_pending_due ON pending_notifications(notified, failed, next_attempt);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create pending notifications schema: %w", err)
+	}
+	return nil
+}
+
+// EnqueueNotification persists an undelivered notification for retry
+func (s *ErrorStore) EnqueueNotification(ctx context.Context, p PendingNotification) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	if p.NextAttempt.IsZero() {
+		p.NextAttempt = now.Add(notifyRetryBaseBackoff)
+	}
+	if p.MsgType == "" {
+		p.MsgType = "m.notice"
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO pending_notifications (trace_id, code, room_id, message, msg_type, attempts, last_error, created_at, next_attempt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		p.TraceID,
+		p.Code,
+		p.RoomID,
+		p.Message,
+		p.MsgType,
+		p.Attempts,
+		p.LastError,
+		p.CreatedAt,
+		p.NextAttempt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue notification failed: %w", err)
+	}
+
+	trimmed, err := s.db.ExecContext(ctx, `
+		DELETE FROM pending_notifications WHERE id <= (
+			SELECT id FROM pending_notifications ORDER BY id DESC LIMIT 1 OFFSET ?
+		)
+	`, maxQueuedNotifications)
+	if err != nil {
+		return 0, fmt.Errorf("trim notification queue failed: %w", err)
+	}
+	if n, _ := trimmed.RowsAffected(); n > 0 {
+		slog.Warn("error_notification_queue_full", "dropped", n, "max", maxQueuedNotifications)
+	}
+
+	return result.LastInsertId()
+}
+
+// DueNotifications returns undelivered notifications whose next attempt is due
+func (s *ErrorStore) DueNotifications(ctx context.Context, now time.Time, limit int) ([]PendingNotification, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.queryPending(ctx,
+		"WHERE notified = FALSE AND failed = FALSE AND next_attempt <= ? ORDER BY next_attempt ASC LIMIT ?",
+		now, limit,
+	)
+}
+
+// PendingNotifications returns all notifications not yet delivered or failed
+func (s *ErrorStore) PendingNotifications(ctx context.Context) ([]PendingNotification, error) {
+	return s.queryPending(ctx, "WHERE notified = FALSE AND failed = FALSE ORDER BY created_at ASC")
+}
+
+// FailedNotifications returns notifications that exhausted their retries
+func (s *ErrorStore) FailedNotifications(ctx context.Context) ([]PendingNotification, error) {
+	return s.queryPending(ctx, "WHERE failed = TRUE ORDER BY created_at ASC")
+}
+
+func (s *ErrorStore) queryPending(ctx context.Context, clause string, args ...interface{}) ([]PendingNotification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, trace_id, code, room_id, message, msg_type, attempts, last_error, created_at, next_attempt, notified, failed FROM pending_notifications "+clause,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query pending notifications failed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []PendingNotification
+	for rows.Next() {
+		var p PendingNotification
+		var lastError sql.NullString
+		if err := rows.Scan(
+			&p.ID,
+			&p.TraceID,
+			&p.Code,
+			&p.RoomID,
+			&p.Message,
+			&p.MsgType,
+			&p.Attempts,
+			&lastError,
+			&p.CreatedAt,
+			&p.NextAttempt,
+			&p.Notified,
+			&p.Failed,
+		); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		if lastError.Valid {
+			p.LastError = lastError.String
+		}
+		results = append(results, p)
+	}
+
+	return results, rows.Err()
+}
+
+// MarkNotificationDelivered marks a pending notification as delivered
+func (s *ErrorStore) MarkNotificationDelivered(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE pending_notifications SET notified = TRUE, attempts = attempts + 1, delivered_at = ? WHERE id = ?",
+		time.Now(), id,
+	)
+	return err
+}
+
+// MarkNotificationRetry records a failed attempt and schedules the next one
+func (s *ErrorStore) MarkNotificationRetry(ctx context.Context, id int64, lastErr string, next time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE pending_notifications SET attempts = attempts + 1, last_error = ?, next_attempt = ? WHERE id = ?",
+		lastErr, next, id,
+	)
+	return err
+}
+
+// MarkNotificationFailed gives up on a pending notification
+func (s *ErrorStore) MarkNotificationFailed(ctx context.Context, id int64, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE pending_notifications SET failed = TRUE, attempts = attempts + 1, last_error = ? WHERE id = ?",
+		lastErr, id,
+	)
+	return err
+}
+
+// notifyBackoff returns the delay before the given retry attempt
+func notifyBackoff(attempts int) time.Duration {
+	d := notifyRetryBaseBackoff
+	for i := 0; i < attempts; i++ {
+		d *= 2
+		if d >= notifyRetryMaxBackoff {
+			return notifyRetryMaxBackoff
+		}
+	}
+	return d
+}
@@ -0,0 +1,308 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// makeAllDue moves every queued notification's next attempt into the past
+func makeAllDue(t *testing.T, store *ErrorStore) {
+	t.Helper()
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, err := store.db.Exec("UPDATE pending_notifications SET next_attempt = ?", time.Now().Add(-time.Second)); err != nil {
+		t.Fatalf("failed to update next_attempt: %v", err)
+	}
+}
+
+func TestErrorStore_PendingNotifications(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	id, err := store.EnqueueNotification(ctx, PendingNotification{
+		TraceID: "tr_1",
+		Code:    "CTX-001",
+		RoomID:  "@admin:example.com",
+		Message: "hello",
+	})
+	if err != nil {
+		t.Fatalf("EnqueueNotification() error = %v", err)
+	}
+
+	// Not due yet (initial backoff)
+	due, _ := store.DueNotifications(ctx, time.Now(), 10)
+	if len(due) != 0 {
+		t.Errorf("DueNotifications() returned %d, want 0 before backoff", len(due))
+	}
+
+	due, _ = store.DueNotifications(ctx, time.Now().Add(time.Hour), 10)
+	if len(due) != 1 || due[0].MsgType != "m.notice" {
+		t.Fatalf("DueNotifications() = %+v, want one m.notice", due)
+	}
+
+	if err := store.MarkNotificationDelivered(ctx, id); err != nil {
+		t.Fatalf("MarkNotificationDelivered() error = %v", err)
+	}
+
+	pending, _ := store.PendingNotifications(ctx)
+	if len(pending) != 0 {
+		t.Errorf("PendingNotifications() returned %d after delivery, want 0", len(pending))
+	}
+}
+
+func TestErrorStore_NotificationQueueIsBounded(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	var last int64
+	for i := 0; i < maxQueuedNotifications+10; i++ {
+		id, err := store.EnqueueNotification(ctx, PendingNotification{
+			TraceID: fmt.Sprintf("tr_%d", i),
+			Code:    "CTX-001",
+			RoomID:  "@admin:example.com",
+			Message: "hello",
+		})
+		if err != nil {
+			t.Fatalf("EnqueueNotification() error = %v", err)
+		}
+		last = id
+	}
+
+	pending, _ := store.PendingNotifications(ctx)
+	if len(pending) != maxQueuedNotifications {
+		t.Fatalf("PendingNotifications() returned %d, want %d", len(pending), maxQueuedNotifications)
+	}
+	for _, p := range pending {
+		if p.ID <= last-maxQueuedNotifications {
+			t.Fatalf("oldest notifications should be dropped, found id %d", p.ID)
+		}
+	}
+}
+
+func TestErrorNotifier_QueuesFailedSend(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	mockSender := &mockMatrixSender{err: fmt.Errorf("adapter offline")}
+
+	notifier := NewErrorNotifier(NotifierConfig{
+		Registry:     NewSamplingRegistry(DefaultSamplingConfig()),
+		Resolver:     NewAdminResolver(AdminConfig{SetupUserMXID: "@admin:example.com"}),
+		Store:        store,
+		MatrixSender: mockSender,
+		Enabled:      true,
+	})
+
+	ctx := context.Background()
+	if err := notifier.Notify(ctx, NewBuilder("SYS-001").Build()); err == nil {
+		t.Fatal("Notify() should report the send failure")
+	}
+
+	pending, _ := store.PendingNotifications(ctx)
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 pending notification, got %d", len(pending))
+	}
+	if pending[0].Code != "SYS-001" || pending[0].RoomID != "@admin:example.com" || pending[0].Attempts != 1 {
+		t.Errorf("Unexpected pending notification: %+v", pending[0])
+	}
+
+	// Adapter reconnects
+	mockSender.err = nil
+	makeAllDue(t, store)
+
+	delivered, failed, err := notifier.RetryPending(ctx)
+	if err != nil {
+		t.Fatalf("RetryPending() error = %v", err)
+	}
+	if delivered != 1 || failed != 0 {
+		t.Errorf("RetryPending() = (%d, %d), want (1, 0)", delivered, failed)
+	}
+	if mockSender.lastRoomID != "@admin:example.com" {
+		t.Errorf("Retry sent to %q, want @admin:example.com", mockSender.lastRoomID)
+	}
+
+	pending, _ = store.PendingNotifications(ctx)
+	if len(pending) != 0 {
+		t.Errorf("Expected no pending notifications after delivery, got %d", len(pending))
+	}
+}
+
+func TestErrorNotifier_RetryPending_MaxRetries(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	mockSender := &mockMatrixSender{err: fmt.Errorf("adapter offline")}
+
+	notifier := NewErrorNotifier(NotifierConfig{
+		Store:        store,
+		MatrixSender: mockSender,
+		Enabled:      true,
+		MaxRetries:   3,
+	})
+
+	ctx := context.Background()
+	store.EnqueueNotification(ctx, PendingNotification{
+		TraceID:  "tr_1",
+		Code:     "CTX-001",
+		RoomID:   "@admin:example.com",
+		Message:  "hello",
+		Attempts: 1,
+	})
+
+	// Second attempt fails and is rescheduled
+	makeAllDue(t, store)
+	_, failed, _ := notifier.RetryPending(ctx)
+	if failed != 0 {
+		t.Fatalf("Should not fail before max retries")
+	}
+
+	pending, _ := store.PendingNotifications(ctx)
+	if len(pending) != 1 || pending[0].Attempts != 2 || !pending[0].NextAttempt.After(time.Now()) {
+		t.Fatalf("Expected rescheduled notification with 2 attempts, got %+v", pending)
+	}
+
+	// Third attempt exhausts retries
+	makeAllDue(t, store)
+	_, failed, _ = notifier.RetryPending(ctx)
+	if failed != 1 {
+		t.Errorf("RetryPending() failed = %d, want 1", failed)
+	}
+
+	failedList, _ := store.FailedNotifications(ctx)
+	if len(failedList) != 1 || failedList[0].LastError != "adapter offline" {
+		t.Errorf("Expected one failed notification, got %+v", failedList)
+	}
+}
+
+func TestSystem_RetryOnNewSender(t *testing.T) {
+	storePath := testStorePath(t)
+	defer cleanupStore(t, storePath)
+
+	system, err := Initialize(Config{
+		StorePath:     storePath,
+		StoreEnabled:  true,
+		SetupUserMXID: "@admin:example.com",
+		Enabled:       true,
+		NotifyEnabled: true,
+		RetryInterval: "1h",
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	ctx := context.Background()
+	system.GetStore().EnqueueNotification(ctx, PendingNotification{
+		TraceID: "tr_1",
+		Code:    "CTX-001",
+		RoomID:  "@admin:example.com",
+		Message: "queued",
+	})
+	makeAllDue(t, system.GetStore())
+
+	system.Start(ctx)
+	defer system.Stop()
+
+	sender := &mockMatrixSender{}
+	system.SetMatrixSender(sender)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		pending, _ := system.GetStore().PendingNotifications(ctx)
+		if len(pending) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Queued notification should be delivered after a sender is attached")
+}
+
+func TestErrorNotifier_QueuesWithoutSender(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	notifier := NewErrorNotifier(NotifierConfig{
+		Registry: NewSamplingRegistry(DefaultSamplingConfig()),
+		Resolver: NewAdminResolver(AdminConfig{SetupUserMXID: "@admin:example.com"}),
+		Store:    store,
+		Enabled:  true,
+	})
+
+	ctx := context.Background()
+	if err := notifier.Notify(ctx, NewBuilder("SYS-001").Build()); err != nil {
+		t.Fatalf("Notify() without a sender error = %v", err)
+	}
+
+	pending, _ := store.PendingNotifications(ctx)
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 pending notification, got %d", len(pending))
+	}
+	if pending[0].Attempts != 0 {
+		t.Errorf("Attempts = %d, want 0 for a notification never sent", pending[0].Attempts)
+	}
+
+	// Due at once, so the first retry after a sender appears delivers it
+	due, _ := store.DueNotifications(ctx, time.Now(), 10)
+	if len(due) != 1 {
+		t.Errorf("DueNotifications() returned %d, want 1", len(due))
+	}
+}
+
+func TestSystem_RetryOnNewSenderIgnoresBackoff(t *testing.T) {
+	storePath := testStorePath(t)
+	defer cleanupStore(t, storePath)
+
+	system, err := Initialize(Config{
+		StorePath:     storePath,
+		StoreEnabled:  true,
+		SetupUserMXID: "@admin:example.com",
+		Enabled:       true,
+		NotifyEnabled: true,
+		RetryInterval: "1h",
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	// Queued after a failed send, so backing off for another 30s
+	ctx := context.Background()
+	system.GetStore().EnqueueNotification(ctx, PendingNotification{
+		TraceID:  "tr_1",
+		Code:     "CTX-001",
+		RoomID:   "@admin:example.com",
+		Message:  "queued",
+		Attempts: 1,
+	})
+
+	system.Start(ctx)
+	defer system.Stop()
+
+	system.SetMatrixSender(&mockMatrixSender{})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		pending, _ := system.GetStore().PendingNotifications(ctx)
+		if len(pending) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("Attaching a sender should deliver queued notifications still in backoff")
+}
+
+func TestNotifyBackoff(t *testing.T) {
+	if got := notifyBackoff(0); got != notifyRetryBaseBackoff {
+		t.Errorf("notifyBackoff(0) = %v, want %v", got, notifyRetryBaseBackoff)
+	}
+	if got := notifyBackoff(1); got != 2*notifyRetryBaseBackoff {
+		t.Errorf("notifyBackoff(1) = %v, want %v", got, 2*notifyRetryBaseBackoff)
+	}
+	if got := notifyBackoff(20); got != notifyRetryMaxBackoff {
+		t.Errorf("notifyBackoff(20) = %v, want %v", got, notifyRetryMaxBackoff)
+	}
+}
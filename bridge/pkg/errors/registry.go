@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"strings"
 	"sync"
 	"time"
 )
@@ -19,14 +20,16 @@ type SamplingRegistry struct {
 	seen           map[string]*ErrorRecord // code -> record
 	mu             sync.RWMutex
 	rateLimitWindow time.Duration
+	codeWindows     map[string]time.Duration // exact code or prefix -> window
 	retentionPeriod time.Duration
 	lastCleanup     time.Time
 }
 
 // SamplingConfig configures the sampling registry
 type SamplingConfig struct {
-	RateLimitWindow time.Duration // Window for rate limiting repeats (default 5m)
-	RetentionPeriod time.Duration // How long to keep records (default 24h)
+	RateLimitWindow time.Duration            // Window for rate limiting repeats (default 5m)
+	CodeWindows     map[string]time.Duration // Per-code or per-prefix window overrides (e.g. "CTX-003", "CTX")
+	RetentionPeriod time.Duration            // How long to keep records (default 24h)
 }
 
 // DefaultSamplingConfig returns default configuration
@@ -49,11 +52,42 @@ func NewSamplingRegistry(cfg SamplingConfig) *SamplingRegistry {
 	return &SamplingRegistry{
 		seen:            make(map[string]*ErrorRecord),
 		rateLimitWindow: cfg.RateLimitWindow,
+		codeWindows:     normalizeCodeWindows(cfg.CodeWindows),
 		retentionPeriod: cfg.RetentionPeriod,
 		lastCleanup:     time.Now(),
 	}
 }
 
+// normalizeCodeWindows upper-cases keys, strips trailing dashes from
+// prefixes, and drops non-positive windows
+func normalizeCodeWindows(windows map[string]time.Duration) map[string]time.Duration {
+	result := make(map[string]time.Duration, len(windows))
+	for key, window := range windows {
+		key = normalizePrefix(key)
+		if key == "" || window <= 0 {
+			continue
+		}
+		result[key] = window
+	}
+	return result
+}
+
+// windowFor returns the rate limit window for a code: an exact code
+// override first, then its prefix, then the global window.
+// Caller must hold r.mu.
+func (r *SamplingRegistry) windowFor(code string) time.Duration {
+	code = strings.ToUpper(code)
+	if window, ok := r.codeWindows[code]; ok {
+		return window
+	}
+	if i := strings.Index(code, "-"); i > 0 {
+		if window, ok := r.codeWindows[code[:i]]; ok {
+			return window
+		}
+	}
+	return r.rateLimitWindow
+}
+
 // ShouldNotify determines if an error should trigger a notification
 // based on severity and rate limiting rules:
 // - Critical: Always notify
@@ -104,7 +138,7 @@ func (r *SamplingRegistry) ShouldNotify(err *TracedError) bool {
 	timeSinceLast := err.Timestamp.Sub(record.LastSeen)
 
 	// Within rate limit window - don't notify, just count
-	if timeSinceLast < r.rateLimitWindow {
+	if timeSinceLast < r.windowFor(err.Code) {
 		record.Count++
 		record.LastSeen = err.Timestamp
 		return false
@@ -268,6 +302,39 @@ func (r *SamplingRegistry) SetRateLimitWindow(d time.Duration) {
 	r.rateLimitWindow = d
 }
 
+// SetCodeWindow overrides the rate limit window for an exact code or a
+// code prefix. A non-positive window removes the override.
+func (r *SamplingRegistry) SetCodeWindow(codeOrPrefix string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := normalizePrefix(codeOrPrefix)
+	if key == "" {
+		return
+	}
+	if d <= 0 {
+		delete(r.codeWindows, key)
+		return
+	}
+	r.codeWindows[key] = d
+}
+
+// SetCodeWindows replaces every per-code and per-prefix override
+func (r *SamplingRegistry) SetCodeWindows(windows map[string]time.Duration) {
+	normalized := normalizeCodeWindows(windows)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codeWindows = normalized
+}
+
+// WindowFor returns the effective rate limit window for a code
+func (r *SamplingRegistry) WindowFor(code string) time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.windowFor(code)
+}
+
 // SetRetentionPeriod updates the retention period
 func (r *SamplingRegistry) SetRetentionPeriod(d time.Duration) {
 	r.mu.Lock()
@@ -358,6 +358,55 @@ func TestSamplingRegistry_SetRateLimitWindow(t *testing.T) {
 	}
 }
 
+func TestSamplingRegistry_CodeWindows(t *testing.T) {
+	registry := NewSamplingRegistry(SamplingConfig{
+		RateLimitWindow: 5 * time.Minute,
+		CodeWindows: map[string]time.Duration{
+			"ctx-":    time.Hour,
+			"CTX-003": 2 * time.Hour,
+			"SYS":     0, // ignored
+		},
+	})
+
+	tests := []struct {
+		code string
+		want time.Duration
+	}{
+		{"CTX-003", 2 * time.Hour},   // exact code
+		{"CTX-001", time.Hour},       // prefix
+		{"SYS-001", 5 * time.Minute}, // non-positive override dropped
+		{"MAT-001", 5 * time.Minute}, // global fallback
+	}
+	for _, tt := range tests {
+		if got := registry.WindowFor(tt.code); got != tt.want {
+			t.Errorf("WindowFor(%s) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+
+	// A repeat 10 minutes later is outside the global window but
+	// inside the CTX prefix window
+	base := time.Now()
+	registry.ShouldNotify(&TracedError{Code: "CTX-001", Severity: SeverityError, Timestamp: base})
+	registry.ShouldNotify(&TracedError{Code: "MAT-001", Severity: SeverityError, Timestamp: base})
+
+	later := base.Add(10 * time.Minute)
+	if registry.ShouldNotify(&TracedError{Code: "CTX-001", Severity: SeverityError, Timestamp: later}) {
+		t.Error("CTX-001 repeat within prefix window should NOT notify")
+	}
+	if !registry.ShouldNotify(&TracedError{Code: "MAT-001", Severity: SeverityError, Timestamp: later}) {
+		t.Error("MAT-001 repeat after global window should notify")
+	}
+
+	registry.SetCodeWindow("CTX", 0)
+	if got := registry.WindowFor("CTX-001"); got != 5*time.Minute {
+		t.Errorf("WindowFor(CTX-001) after removing override = %v, want 5m", got)
+	}
+	registry.SetCodeWindow("MAT-001", 30*time.Minute)
+	if got := registry.WindowFor("MAT-001"); got != 30*time.Minute {
+		t.Errorf("WindowFor(MAT-001) = %v, want 30m", got)
+	}
+}
+
 func TestSamplingRegistry_SetRetentionPeriod(t *testing.T) {
 	registry := NewSamplingRegistry(DefaultSamplingConfig())
 	registry.SetRetentionPeriod(48 * time.Hour)
@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// migrateStacks creates the interned stack table and adds the stack
+// reference to the errors table. Rows stored before interning keep their
+// stack inline in trace_json and are read back unchanged.
+func (s *ErrorStore) migrateStacks() error {
+	_, err := s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS error_stacks (
+			hash        TEXT PRIMARY KEY,
+			frames_json TEXT NOT NULL,
+			created_at  TIMESTAMP NOT NULL
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create stack schema: %w", err)
+	}
+
+	if _, err := s.db.Exec("ALTER TABLE errors ADD COLUMN stack_hash TEXT"); err != nil {
+		if !strings.Contains(err.Error(), "duplicate column") {
+			return fmt.Errorf("failed to migrate errors: %w", err)
+		}
+	}
+	if _, err := s.db.Exec("CREATE INDEX IF NOT EXISTS idx_errors_stack_hash ON errors(stack_hash)"); err != nil {
+		return fmt.Errorf("failed to create stack hash index: %w", err)
+	}
+	return nil
+}
+
+// internStack stores the frames once under their content hash and returns
+// the hash. Identical stacks from repeated errors share a single row.
+// Callers must hold s.mu.
+func (s *ErrorStore) internStack(ctx context.Context, frames []StackFrame) (string, error) {
+	framesJSON, err := json.Marshal(frames)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize stack: %w", err)
+	}
+	sum := sha256.Sum256(framesJSON)
+	hash := hex.EncodeToString(sum[:])
+
+	_, err = s.db.ExecContext(ctx,
+		"INSERT OR IGNORE INTO error_stacks (hash, frames_json, created_at) VALUES (?, ?, ?)",
+		hash, string(framesJSON), time.Now(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to store stack: %w", err)
+	}
+	return hash, nil
+}
+
+// serializeTrace returns the trace JSON with the stack replaced by a
+// reference to its interned copy, or with the stack inline when it is empty.
+// Callers must hold s.mu.
+func (s *ErrorStore) serializeTrace(ctx context.Context, tracedErr *TracedError) (string, sql.NullString, error) {
+	var stackHash sql.NullString
+	trace := *tracedErr
+
+	if len(trace.Stack) > 0 {
+		hash, err := s.internStack(ctx, trace.Stack)
+		if err != nil {
+			return "", stackHash, err
+		}
+		stackHash = sql.NullString{String: hash, Valid: true}
+		trace.Stack = nil
+	}
+
+	traceJSON, err := json.Marshal(&trace)
+	if err != nil {
+		return "", stackHash, fmt.Errorf("failed to serialize trace: %w", err)
+	}
+	return string(traceJSON), stackHash, nil
+}
+
+// rehydrateStack restores an interned stack onto a trace read from the store
+func rehydrateStack(trace *TracedError, framesJSON sql.NullString) {
+	if trace == nil || !framesJSON.Valid || len(trace.Stack) > 0 {
+		return
+	}
+	var frames []StackFrame
+	if json.Unmarshal([]byte(framesJSON.String), &frames) == nil {
+		trace.Stack = frames
+	}
+}
+
+// pruneStacks removes interned stacks no longer referenced by any error.
+// Callers must hold s.mu.
+func (s *ErrorStore) pruneStacks(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM error_stacks
+		WHERE hash NOT IN (SELECT stack_hash FROM errors WHERE stack_hash IS NOT NULL)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prune stacks: %w", err)
+	}
+	return nil
+}
+
+// StackCount returns the number of distinct stacks held in the store
+func (s *ErrorStore) StackCount(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM error_stacks").Scan(&count)
+	return count, err
+}
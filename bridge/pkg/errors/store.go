@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -100,7 +101,10 @@ func (s *ErrorStore) migrate() error {
 		return fmt.Errorf("failed to create schema: %w", err)
 	}
 
-	return nil
+	if err := s.migratePending(); err != nil {
+		return err
+	}
+	return s.migrateStacks()
 }
 
 // StoredError represents an error retrieved from the store
@@ -124,47 +128,61 @@ func (s *ErrorStore) Store(ctx context.Context, tracedErr *TracedError) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Serialize full trace
-	traceJSON, err := json.Marshal(tracedErr)
+	// Serialize full trace, interning the stack so repeats share one copy
+	traceJSON, stackHash, err := s.serializeTrace(ctx, tracedErr)
 	if err != nil {
-		return fmt.Errorf("failed to serialize trace: %w", err)
+		return err
 	}
 
 	// Check if error with this code already exists (for updating occurrences)
 	var existingTraceID string
 	var existingOccurrences int
+	var existingStackHash sql.NullString
 	queryErr := s.db.QueryRowContext(ctx,
-		"SELECT trace_id, occurrences FROM errors WHERE code = ? AND resolved = FALSE ORDER BY last_seen DESC LIMIT 1",
+		"SELECT trace_id, occurrences, stack_hash FROM errors WHERE code = ? AND resolved = FALSE ORDER BY last_seen DESC LIMIT 1",
 		tracedErr.Code,
-	).Scan(&existingTraceID, &existingOccurrences)
+	).Scan(&existingTraceID, &existingOccurrences, &existingStackHash)
 
 	if queryErr == nil && existingTraceID != "" {
-		// Update existing unresolved error
+		// Update existing unresolved error, carrying over the latest
+		// severity so builder overrides are reflected in the record
 		_, err = s.db.ExecContext(ctx, `
 			UPDATE errors SET
+				severity = ?,
 				trace_json = ?,
+				stack_hash = ?,
 				last_seen = ?,
 				occurrences = occurrences + 1
 			WHERE trace_id = ?
 		`,
-			string(traceJSON),
+			string(tracedErr.Severity),
+			traceJSON,
+			stackHash,
 			tracedErr.Timestamp,
 			existingTraceID,
 		)
-		return err
+		if err != nil {
+			return err
+		}
+		// The previous occurrence's stack may no longer be referenced
+		if existingStackHash.Valid && existingStackHash != stackHash {
+			return s.pruneStacks(ctx)
+		}
+		return nil
 	}
 
 	// Insert new error
 	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO errors (trace_id, code, category, severity, message, trace_json, first_seen, last_seen, occurrences)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1)
+		INSERT INTO errors (trace_id, code, category, severity, message, trace_json, stack_hash, first_seen, last_seen, occurrences)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 1)
 	`,
 		tracedErr.TraceID,
 		tracedErr.Code,
 		tracedErr.Category,
 		string(tracedErr.Severity),
 		tracedErr.Message,
-		string(traceJSON),
+		traceJSON,
+		stackHash,
 		tracedErr.Timestamp,
 		tracedErr.Timestamp,
 	)
@@ -180,7 +198,7 @@ type ErrorQuery struct {
 	Resolved  *bool      // Filter by resolved status (nil = all)
 	Since     time.Time  // Only errors after this time
 	Until     time.Time  // Only errors before this time
-	Limit     int        // Max results (default 20, max 1000)
+	Limit     int        // Max results (Query: default 20, max 1000; Each: 0 = all)
 	Offset    int        // Pagination offset
 	OrderBy   string     // "first_seen", "last_seen", "occurrences" (default "last_seen")
 	OrderDesc bool       // Sort descending (default true)
@@ -188,9 +206,6 @@ type ErrorQuery struct {
 
 // Query retrieves errors matching the query parameters
 func (s *ErrorStore) Query(ctx context.Context, q ErrorQuery) ([]StoredError, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// Set defaults
 	if q.Limit <= 0 {
 		q.Limit = 20
@@ -199,8 +214,60 @@ func (s *ErrorStore) Query(ctx context.Context, q ErrorQuery) ([]StoredError, er
 		q.Limit = 1000
 	}
 
+	var results []StoredError
+	err := s.Each(ctx, q, func(se StoredError) error {
+		results = append(results, se)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// eachBatchSize is how many errors Each copies out of the store at a time
+const eachBatchSize = 100
+
+// Each streams errors matching the query to fn one row at a time, without
+// loading the full result set into memory. A Limit <= 0 means no limit.
+// Iteration stops at the first error returned by fn.
+//
+// The matching trace IDs are read first, then the errors are copied out in
+// batches; fn runs without the store lock held, so it may be slow (writing
+// an export, say) without blocking writers. An error deleted after the IDs
+// were read is skipped.
+func (s *ErrorStore) Each(ctx context.Context, q ErrorQuery, fn func(StoredError) error) error {
+	ids, err := s.matchingIDs(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	for len(ids) > 0 {
+		n := len(ids)
+		if n > eachBatchSize {
+			n = eachBatchSize
+		}
+		batch, err := s.loadBatch(ctx, ids[:n])
+		if err != nil {
+			return err
+		}
+		for _, se := range batch {
+			if err := fn(se); err != nil {
+				return err
+			}
+		}
+		ids = ids[n:]
+	}
+	return nil
+}
+
+// matchingIDs returns the trace IDs of errors matching the query, in order
+func (s *ErrorStore) matchingIDs(ctx context.Context, q ErrorQuery) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	// Build query
-	query := "SELECT trace_id, code, category, severity, message, trace_json, first_seen, last_seen, occurrences, resolved, resolved_by, resolved_at FROM errors WHERE 1=1"
+	query := "SELECT trace_id FROM errors WHERE 1=1"
 	args := []interface{}{}
 
 	if q.Code != "" {
@@ -241,8 +308,13 @@ func (s *ErrorStore) Query(ctx context.Context, q ErrorQuery) ([]StoredError, er
 	query += fmt.Sprintf(" ORDER BY %s %s", orderCol, orderDir)
 
 	// Pagination
-	query += " LIMIT ? OFFSET ?"
-	args = append(args, q.Limit, q.Offset)
+	if q.Limit > 0 {
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, q.Limit, q.Offset)
+	} else if q.Offset > 0 {
+		query += " LIMIT -1 OFFSET ?"
+		args = append(args, q.Offset)
+	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -250,10 +322,41 @@ func (s *ErrorStore) Query(ctx context.Context, q ErrorQuery) ([]StoredError, er
 	}
 	defer rows.Close()
 
-	var results []StoredError
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// loadBatch copies out the errors with the given trace IDs, in the order
+// given
+func (s *ErrorStore) loadBatch(ctx context.Context, ids []string) ([]StoredError, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	query := "SELECT trace_id, code, category, severity, message, trace_json, error_stacks.frames_json, first_seen, last_seen, occurrences, resolved, resolved_by, resolved_at FROM errors LEFT JOIN error_stacks ON error_stacks.hash = errors.stack_hash WHERE trace_id IN (" + placeholders + ")"
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[string]StoredError, len(ids))
 	for rows.Next() {
 		var se StoredError
 		var traceJSON string
+		var stackJSON sql.NullString
 		var resolvedAt sql.NullTime
 		var resolvedBy sql.NullString
 
@@ -264,6 +367,7 @@ func (s *ErrorStore) Query(ctx context.Context, q ErrorQuery) ([]StoredError, er
 			&se.Severity,
 			&se.Message,
 			&traceJSON,
+			&stackJSON,
 			&se.FirstSeen,
 			&se.LastSeen,
 			&se.Occurrences,
@@ -278,6 +382,7 @@ func (s *ErrorStore) Query(ctx context.Context, q ErrorQuery) ([]StoredError, er
 		// Parse full trace
 		var trace TracedError
 		if json.Unmarshal([]byte(traceJSON), &trace) == nil {
+			rehydrateStack(&trace, stackJSON)
 			se.Trace = &trace
 		}
 
@@ -288,10 +393,19 @@ func (s *ErrorStore) Query(ctx context.Context, q ErrorQuery) ([]StoredError, er
 			se.ResolvedAt = &resolvedAt.Time
 		}
 
-		results = append(results, se)
+		byID[se.TraceID] = se
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return results, rows.Err()
+	batch := make([]StoredError, 0, len(byID))
+	for _, id := range ids {
+		if se, ok := byID[id]; ok {
+			batch = append(batch, se)
+		}
+	}
+	return batch, nil
 }
 
 // Get retrieves a single error by trace ID
@@ -357,8 +471,10 @@ func (s *ErrorStore) Delete(ctx context.Context, traceID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	_, err := s.db.ExecContext(ctx, "DELETE FROM errors WHERE trace_id = ?", traceID)
-	return err
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM errors WHERE trace_id = ?", traceID); err != nil {
+		return err
+	}
+	return s.pruneStacks(ctx)
 }
 
 // Cleanup removes old resolved errors based on retention policy
@@ -375,77 +491,128 @@ func (s *ErrorStore) Cleanup(ctx context.Context) (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("cleanup failed: %w", err)
 	}
+	if err := s.pruneStacks(ctx); err != nil {
+		return 0, err
+	}
 
 	return result.RowsAffected()
 }
 
 // Stats returns statistics about stored errors
 func (s *ErrorStore) Stats(ctx context.Context) (StoreStats, error) {
+	return s.WindowStats(ctx, time.Time{}, 0)
+}
+
+// WindowStats returns statistics for errors seen since the given time
+// (zero = all time), including the topN most frequent codes (0 = none)
+func (s *ErrorStore) WindowStats(ctx context.Context, since time.Time, topN int) (StoreStats, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	var stats StoreStats
 
+	where := ""
+	args := []interface{}{}
+	if !since.IsZero() {
+		where = " WHERE last_seen >= ?"
+		args = append(args, since)
+	}
+	and := " WHERE "
+	if where != "" {
+		and = " AND "
+	}
+
 	err := s.db.QueryRowContext(ctx,
-		"SELECT COUNT(*) FROM errors",
+		"SELECT COUNT(*) FROM errors"+where, args...,
 	).Scan(&stats.TotalErrors)
 	if err != nil {
 		return stats, err
 	}
 
 	err = s.db.QueryRowContext(ctx,
-		"SELECT COUNT(*) FROM errors WHERE resolved = FALSE",
+		"SELECT COUNT(*) FROM errors"+where+and+"resolved = FALSE", args...,
 	).Scan(&stats.UnresolvedErrors)
 	if err != nil {
 		return stats, err
 	}
 
 	err = s.db.QueryRowContext(ctx,
-		"SELECT COUNT(DISTINCT code) FROM errors",
+		"SELECT COUNT(DISTINCT code) FROM errors"+where, args...,
 	).Scan(&stats.UniqueCodes)
 	if err != nil {
 		return stats, err
 	}
 
 	// Get counts by severity
-	rows, err := s.db.QueryContext(ctx,
-		"SELECT severity, COUNT(*) FROM errors GROUP BY severity",
-	)
+	stats.BySeverity = make(map[Severity]int)
+	err = s.groupCounts(ctx, "SELECT severity, COUNT(*) FROM errors"+where+" GROUP BY severity", args, func(key string, count int) {
+		stats.BySeverity[Severity(key)] = count
+	})
 	if err != nil {
 		return stats, err
 	}
-	defer rows.Close()
 
-	stats.BySeverity = make(map[Severity]int)
-	for rows.Next() {
-		var sev string
-		var count int
-		if err := rows.Scan(&sev, &count); err != nil {
+	// Get counts by category
+	stats.ByCategory = make(map[string]int)
+	err = s.groupCounts(ctx, "SELECT category, COUNT(*) FROM errors"+where+" GROUP BY category", args, func(key string, count int) {
+		stats.ByCategory[key] = count
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	// Get counts by resolved status
+	stats.ByResolved = map[string]int{"resolved": 0, "unresolved": 0}
+	err = s.groupCounts(ctx, "SELECT CASE WHEN resolved THEN 'resolved' ELSE 'unresolved' END, COUNT(*) FROM errors"+where+" GROUP BY resolved", args, func(key string, count int) {
+		stats.ByResolved[key] = count
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	// Get the most frequent codes by total occurrences
+	if topN > 0 {
+		rows, err := s.db.QueryContext(ctx,
+			"SELECT code, category, SUM(occurrences) AS total FROM errors"+where+" GROUP BY code, category ORDER BY total DESC, code ASC LIMIT ?",
+			append(args, topN)...,
+		)
+		if err != nil {
+			return stats, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var cc CodeCount
+			if err := rows.Scan(&cc.Code, &cc.Category, &cc.Occurrences); err != nil {
+				return stats, err
+			}
+			stats.TopCodes = append(stats.TopCodes, cc)
+		}
+		if err := rows.Err(); err != nil {
 			return stats, err
 		}
-		stats.BySeverity[Severity(sev)] = count
 	}
 
-	// Get counts by category
-	rows, err = s.db.QueryContext(ctx,
-		"SELECT category, COUNT(*) FROM errors GROUP BY category",
-	)
+	return stats, nil
+}
+
+// groupCounts runs a two-column (key, count) grouping query
+func (s *ErrorStore) groupCounts(ctx context.Context, query string, args []interface{}, fn func(key string, count int)) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return stats, err
+		return err
 	}
 	defer rows.Close()
 
-	stats.ByCategory = make(map[string]int)
 	for rows.Next() {
-		var cat string
+		var key string
 		var count int
-		if err := rows.Scan(&cat, &count); err != nil {
-			return stats, err
+		if err := rows.Scan(&key, &count); err != nil {
+			return err
 		}
-		stats.ByCategory[cat] = count
+		fn(key, count)
 	}
-
-	return stats, nil
+	return rows.Err()
 }
 
 // StoreStats holds statistics about the error store
@@ -455,6 +622,15 @@ type StoreStats struct {
 	UniqueCodes      int              `json:"unique_codes"`
 	BySeverity       map[Severity]int `json:"by_severity"`
 	ByCategory       map[string]int   `json:"by_category"`
+	ByResolved       map[string]int   `json:"by_resolved"`
+	TopCodes         []CodeCount      `json:"top_codes,omitempty"`
+}
+
+// CodeCount holds the total occurrences of a single error code
+type CodeCount struct {
+	Code        string `json:"code"`
+	Category    string `json:"category"`
+	Occurrences int    `json:"occurrences"`
 }
 
 // Close closes the database connection
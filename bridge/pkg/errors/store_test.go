@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -98,6 +99,37 @@ func TestErrorStore_Store_DuplicateCode(t *testing.T) {
 	}
 }
 
+func TestErrorStore_Store_SeverityOverride(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+
+	err1 := NewBuilder("CTX-001").WithSeverity(SeverityWarning).Build()
+	if err := store.Store(ctx, err1); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	results, _ := store.Query(ctx, ErrorQuery{Severity: SeverityWarning})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 warning result, got %d", len(results))
+	}
+
+	// A repeat with an escalated severity should update the record
+	err2 := NewBuilder("CTX-001").WithSeverity(SeverityCritical).Build()
+	if err := store.Store(ctx, err2); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	results, _ = store.Query(ctx, ErrorQuery{Code: "CTX-001"})
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].Severity != SeverityCritical {
+		t.Errorf("Severity = %q, want %q", results[0].Severity, SeverityCritical)
+	}
+}
+
 func TestErrorStore_Query(t *testing.T) {
 	store := newTestStore(t)
 	defer store.Close()
@@ -374,6 +406,58 @@ func TestErrorStore_Stats(t *testing.T) {
 	}
 }
 
+func TestErrorStore_WindowStats(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	// Old error outside the window
+	store.Store(ctx, &TracedError{Code: "SYS-003", Category: "system", Severity: SeverityError, Message: "old", TraceID: "tr_old", Timestamp: now.Add(-48 * time.Hour)})
+
+	// Recent errors: CTX-001 three times, MAT-001 once (resolved)
+	for i := 0; i < 3; i++ {
+		store.Store(ctx, &TracedError{Code: "CTX-001", Category: "container", Severity: SeverityError, Message: "ctx", TraceID: fmt.Sprintf("tr_ctx_%d", i), Timestamp: now})
+	}
+	store.Store(ctx, &TracedError{Code: "MAT-001", Category: "matrix", Severity: SeverityWarning, Message: "mat", TraceID: "tr_mat", Timestamp: now})
+	store.Resolve(ctx, "tr_mat", "@admin:example.com")
+
+	stats, err := store.WindowStats(ctx, now.Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("WindowStats() error = %v", err)
+	}
+
+	if stats.TotalErrors != 2 {
+		t.Errorf("TotalErrors = %d, want 2", stats.TotalErrors)
+	}
+	if stats.UnresolvedErrors != 1 {
+		t.Errorf("UnresolvedErrors = %d, want 1", stats.UnresolvedErrors)
+	}
+	if stats.ByResolved["resolved"] != 1 || stats.ByResolved["unresolved"] != 1 {
+		t.Errorf("ByResolved = %v, want 1 resolved and 1 unresolved", stats.ByResolved)
+	}
+	if stats.ByCategory["system"] != 0 {
+		t.Error("Errors outside the window should not be counted")
+	}
+
+	if len(stats.TopCodes) != 2 {
+		t.Fatalf("TopCodes has %d entries, want 2", len(stats.TopCodes))
+	}
+	if stats.TopCodes[0].Code != "CTX-001" || stats.TopCodes[0].Occurrences != 3 {
+		t.Errorf("TopCodes[0] = %+v, want CTX-001 with 3 occurrences", stats.TopCodes[0])
+	}
+
+	// Top N is respected
+	stats, _ = store.WindowStats(ctx, time.Time{}, 1)
+	if len(stats.TopCodes) != 1 {
+		t.Errorf("TopCodes has %d entries, want 1", len(stats.TopCodes))
+	}
+	if stats.TotalErrors != 3 {
+		t.Errorf("All-time TotalErrors = %d, want 3", stats.TotalErrors)
+	}
+}
+
 func TestErrorStore_Query_Pagination(t *testing.T) {
 	store := newTestStore(t)
 	defer store.Close()
@@ -540,3 +624,56 @@ func getCategoryFromCode(code string) string {
 		return "unknown"
 	}
 }
+
+func TestErrorStore_InternsStacks(t *testing.T) {
+	store := newTestStore(t)
+	defer store.Close()
+	ctx := context.Background()
+
+	stack := []StackFrame{
+		{Function: "docker.Start", File: "client.go", Line: 42},
+		{Function: "main.run", File: "main.go", Line: 10},
+	}
+	for i, code := range []string{"CTX-001", "CTX-002", "CTX-002"} {
+		err := store.Store(ctx, &TracedError{
+			Code:      code,
+			Category:  "container",
+			Severity:  SeverityError,
+			Message:   "start failed",
+			TraceID:   fmt.Sprintf("tr_stack_%d", i),
+			Stack:     stack,
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+	}
+
+	if n, _ := store.StackCount(ctx); n != 1 {
+		t.Errorf("StackCount() = %d, want 1 for identical stacks", n)
+	}
+
+	// The stack is kept out of the row and restored on read
+	var traceJSON string
+	store.db.QueryRow("SELECT trace_json FROM errors WHERE trace_id = ?", "tr_stack_0").Scan(&traceJSON)
+	if strings.Contains(traceJSON, "docker.Start") {
+		t.Errorf("trace_json should not contain the stack: %s", traceJSON)
+	}
+	results, err := store.Query(ctx, ErrorQuery{Code: "CTX-001"})
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Query() = %v, %v", results, err)
+	}
+	if got := results[0].Trace.Stack; len(got) != 2 || got[0] != stack[0] {
+		t.Errorf("Stack = %+v, want %+v", got, stack)
+	}
+
+	// Stacks are dropped once no error references them
+	store.Delete(ctx, "tr_stack_0")
+	if n, _ := store.StackCount(ctx); n != 1 {
+		t.Errorf("StackCount() = %d after deleting one user, want 1", n)
+	}
+	store.Delete(ctx, "tr_stack_1")
+	if n, _ := store.StackCount(ctx); n != 0 {
+		t.Errorf("StackCount() = %d after deleting all users, want 0", n)
+	}
+}
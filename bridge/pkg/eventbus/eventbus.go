@@ -3,7 +3,8 @@
 // Role: Pushes typed BridgeEvent structs to WebSocket clients and in-process handlers.
 // Used by: Vault events, Email events (via RegisterBridgeHandler).
 // NOT used for: Matrix sync events, workflow events, agent status (→ internal/events).
-// Key difference from internal/events: No cursor/sequence semantics, at-most-once delivery.
+// Key difference from internal/events: at-most-once delivery; bridge events carry a sequence
+// number and a bounded in-memory backlog lets reconnecting WebSocket clients catch up.
 package eventbus
 
 import (
@@ -11,6 +12,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/armorclaw/bridge/pkg/eventlog"
@@ -33,6 +35,21 @@ type EventBus struct {
 	// In-process handlers for BridgeEvents (separate from Matrix subscriber path)
 	bridgeHandlers  map[string][]func(BridgeEvent)
 	bridgeHandlerMu sync.RWMutex
+
+	// Bridge event sequence and replay backlog, see replay.go
+	seq         int64
+	backlog     []backlogEntry
+	backlogSize int
+	backlogMu   sync.Mutex
+
+	// Subscribers idle longer than this are removed
+	inactivityTimeout time.Duration
+
+	// Delivery metrics, see metrics.go
+	maxSubscribers int
+	published      atomic.Uint64
+	dropped        atomic.Uint64
+	lastPublish    atomic.Int64 // Unix nanoseconds
 }
 
 // Subscriber represents a client subscribed to receive events
@@ -51,6 +68,7 @@ type Subscriber struct {
 // EventFilter defines which events a subscriber wants to receive
 type EventFilter struct {
 	RoomID    string   // Only events from this room (empty = all rooms)
+	RoomIDs   []string // Only events from these rooms, in addition to RoomID
 	SenderID  string   // Only events from this sender (empty = all senders)
 	EventType []string // Only these event types (empty = all types)
 }
@@ -78,6 +96,7 @@ type Config struct {
 	WebSocketPath     string        // WebSocket path
 	MaxSubscribers    int           // Maximum concurrent subscribers
 	InactivityTimeout time.Duration // Disconnect inactive subscribers
+	BacklogSize       int           // Bridge events kept for replay (0 = DefaultBacklogSize, <0 = disabled)
 
 	// Durable log configuration
 	EnableLog      bool
@@ -93,6 +112,7 @@ func DefaultConfig() Config {
 		WebSocketPath:     "/events",
 		MaxSubscribers:    100,
 		InactivityTimeout: 30 * time.Minute,
+		BacklogSize:       DefaultBacklogSize,
 		EnableLog:         false,
 		LogDir:            "/var/lib/armorclaw/events",
 	}
@@ -102,12 +122,22 @@ func DefaultConfig() Config {
 func NewEventBus(config Config) *EventBus {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	if config.BacklogSize == 0 {
+		config.BacklogSize = DefaultBacklogSize
+	}
+	if config.InactivityTimeout <= 0 {
+		config.InactivityTimeout = DefaultConfig().InactivityTimeout
+	}
+
 	bus := &EventBus{
-		subscribers:    make(map[string]*Subscriber),
-		bridgeHandlers: make(map[string][]func(BridgeEvent)),
-		ctx:            ctx,
-		cancel:         cancel,
-		securityLog:    logger.NewSecurityLogger(logger.Global().WithComponent("eventbus")),
+		subscribers:       make(map[string]*Subscriber),
+		bridgeHandlers:    make(map[string][]func(BridgeEvent)),
+		ctx:               ctx,
+		cancel:            cancel,
+		securityLog:       logger.NewSecurityLogger(logger.Global().WithComponent("eventbus")),
+		backlogSize:       config.BacklogSize,
+		maxSubscribers:    config.MaxSubscribers,
+		inactivityTimeout: config.InactivityTimeout,
 	}
 
 	// Initialize durable log if enabled
@@ -249,6 +279,8 @@ func (b *EventBus) Publish(event *MatrixEvent) error {
 		}
 	}
 
+	b.recordPublish(droppedCount)
+
 	b.securityLog.LogSecurityEvent("event_published",
 		slog.String("event_type", event.Type),
 		slog.String("room_id", event.RoomID),
@@ -327,31 +359,15 @@ func (b *EventBus) Unsubscribe(subscriberID string) error {
 
 // matchesFilter checks if an event matches a subscriber's filter
 func (b *EventBus) matchesFilter(event *MatrixEvent, filter EventFilter) bool {
-	// Check room filter
-	if filter.RoomID != "" && event.RoomID != filter.RoomID {
+	// Matrix events always have a room and sender, so empty values never
+	// pass those filters
+	if event.RoomID == "" && filter.hasRooms() {
 		return false
 	}
-
-	// Check sender filter
-	if filter.SenderID != "" && event.Sender != filter.SenderID {
+	if event.Sender == "" && filter.SenderID != "" {
 		return false
 	}
-
-	// Check event type filter
-	if len(filter.EventType) > 0 {
-		match := false
-		for _, eventType := range filter.EventType {
-			if event.Type == eventType {
-				match = true
-				break
-			}
-		}
-		if !match {
-			return false
-		}
-	}
-
-	return true
+	return filter.Matches(event.Type, event.RoomID, event.Sender)
 }
 
 // handleWebSocketConnect handles new WebSocket connections
@@ -383,6 +399,7 @@ func (b *EventBus) handleWebSocketMessage(connID string, message []byte) error {
 			// Extract filter parameters
 			filter := EventFilter{
 				RoomID:    toString(msg["room_id"]),
+				RoomIDs:   toStringSlice(msg["room_ids"]),
 				SenderID:  toString(msg["sender_id"]),
 				EventType: toStringSlice(msg["event_types"]),
 			}
@@ -449,7 +466,7 @@ func (b *EventBus) sendToSubscriber(connID string, sub *Subscriber) {
 			}
 
 			if b.websocketServer != nil {
-				if broadcastErr := b.websocketServer.Broadcast(data); broadcastErr != nil {
+				if _, broadcastErr := b.websocketServer.BroadcastFiltered(wrapper.Event.Type, wrapper.Event.RoomID, data); broadcastErr != nil {
 					b.securityLog.LogSecurityEvent("subscriber_broadcast_failed",
 						slog.String("subscriber_id", sub.ID),
 						slog.String("error", broadcastErr.Error()))
@@ -480,7 +497,7 @@ func (b *EventBus) cleanupInactiveSubscribers() {
 			now := time.Now()
 			for id, sub := range b.subscribers {
 				sub.mu.RLock()
-				inactive := now.Sub(sub.LastActivity) > 30*time.Minute
+				inactive := now.Sub(sub.LastActivity) > b.inactivityTimeout
 				sub.mu.RUnlock()
 
 				if inactive {
@@ -511,8 +528,10 @@ func (b *EventBus) GetStats() map[string]interface{} {
 
 	stats := map[string]interface{}{
 		"active_subscribers": len(b.subscribers),
-		"max_subscribers":    100, // Should be configurable
+		"max_subscribers":    b.maxSubscribers,
 		"websocket_enabled":  b.websocketServer != nil,
+		"published_events":   b.published.Load(),
+		"dropped_events":     b.dropped.Load(),
 	}
 
 	if b.websocketServer != nil {
@@ -561,8 +580,17 @@ func (b *EventBus) PublishBridgeEvent(event BridgeEvent) error {
 		return wrapErr
 	}
 
+	// Sequence, retain and broadcast under one lock so replayed and live
+	// events can't interleave out of order
+	roomID := EventRoomID(event)
+	b.backlogMu.Lock()
+	b.seq++
+	wrapper.Sequence = b.seq
+
 	data, err := wrapper.ToJSON()
 	if err != nil {
+		b.seq--
+		b.backlogMu.Unlock()
 		serErr := ErrSerializeFailed(eventType, err)
 		b.securityLog.LogSecurityEvent("bridge_event_serialize_failed",
 			slog.String("domain", string(serErr.Domain)),
@@ -572,9 +600,13 @@ func (b *EventBus) PublishBridgeEvent(event BridgeEvent) error {
 		return serErr
 	}
 
-	// Broadcast to WebSocket server if available
+	b.appendBacklog(backlogEntry{seq: wrapper.Sequence, eventType: eventType, roomID: roomID, payload: data})
+
+	// Broadcast to WebSocket clients whose subscription filter matches
+	dropped := 0
 	if b.websocketServer != nil {
-		if broadcastErr := b.websocketServer.Broadcast(data); broadcastErr != nil {
+		var broadcastErr error
+		if dropped, broadcastErr = b.websocketServer.BroadcastFiltered(eventType, roomID, data); broadcastErr != nil {
 			bcErr := ErrBroadcastFailed(-1, broadcastErr)
 			b.securityLog.LogSecurityEvent("bridge_event_broadcast_failed",
 				slog.String("domain", string(bcErr.Domain)),
@@ -584,9 +616,13 @@ func (b *EventBus) PublishBridgeEvent(event BridgeEvent) error {
 			// Don't return error - event was still processed, just not broadcast
 		}
 	}
+	b.backlogMu.Unlock()
+	b.recordPublish(dropped)
 
 	b.securityLog.LogSecurityEvent("bridge_event_published",
 		slog.String("event_type", eventType),
+		slog.Int64("sequence", wrapper.Sequence),
+		slog.Int("clients_dropped", dropped),
 		slog.Bool("websocket_enabled", b.websocketServer != nil))
 
 	// Dispatch to in-process handlers registered via RegisterBridgeHandler
@@ -426,6 +426,36 @@ func TestEventBusPublishBridgeEventNoClients(t *testing.T) {
 	assert.Equal(t, EventTypeAgentStarted, wrapper["type"])
 }
 
+func TestEventBusPublishWebRTCSessionState(t *testing.T) {
+	broadcaster := &mockBroadcaster{}
+
+	bus := NewEventBus(Config{
+		WebSocketEnabled:  true,
+		WebSocketAddr:     "localhost:0",
+		WebSocketPath:     "/ws",
+		MaxSubscribers:    10,
+		InactivityTimeout: 5 * time.Minute,
+	})
+	bus.SetBroadcaster(broadcaster)
+	defer bus.Stop()
+
+	event := NewWebRTCSessionStateEvent("sess_1", "!room:example.com", "pending", "active")
+	require.NoError(t, bus.PublishBridgeEvent(event))
+	require.Len(t, broadcaster.calls, 1)
+
+	var wrapper struct {
+		Type string                 `json:"type"`
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(broadcaster.calls[0].payload, &wrapper))
+	assert.Equal(t, EventTypeWebRTCSessionState, wrapper.Type)
+	assert.Equal(t, "sess_1", wrapper.Data["session_id"])
+	assert.Equal(t, "!room:example.com", wrapper.Data["room_id"])
+	assert.Equal(t, "pending", wrapper.Data["old_state"])
+	assert.Equal(t, "active", wrapper.Data["new_state"])
+	assert.NotEmpty(t, wrapper.Data["timestamp"])
+}
+
 func TestEventBusPublishMultipleEventTypes(t *testing.T) {
 	broadcaster := &mockBroadcaster{}
 
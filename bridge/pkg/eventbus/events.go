@@ -56,6 +56,9 @@ const (
 	// Bridge events
 	EventTypeBridgeStatus   = "bridge.status"
 	EventTypeSessionExpired = "session.expired"
+
+	// WebRTC events
+	EventTypeWebRTCSessionState = "webrtc.session_state"
 )
 
 // BridgeEvent is the base event interface
@@ -452,6 +455,40 @@ func NewPlatformDisconnectedEvent(platform, reason string) *PlatformDisconnected
 	}
 }
 
+// ============================================================================
+// WebRTC Events
+// ============================================================================
+
+// WebRTCSessionStateEvent is emitted when a voice session changes state.
+// OldState is empty for a newly created session.
+type WebRTCSessionStateEvent struct {
+	BaseEvent
+	SessionID string `json:"session_id"`
+	RoomID    string `json:"room_id"`
+	OldState  string `json:"old_state"`
+	NewState  string `json:"new_state"`
+}
+
+// NewWebRTCSessionStateEvent creates a new session state event
+func NewWebRTCSessionStateEvent(sessionID, roomID, oldState, newState string) *WebRTCSessionStateEvent {
+	return &WebRTCSessionStateEvent{
+		BaseEvent: BaseEvent{
+			Type: EventTypeWebRTCSessionState,
+			Ts:   time.Now(),
+		},
+		SessionID: sessionID,
+		RoomID:    roomID,
+		OldState:  oldState,
+		NewState:  newState,
+	}
+}
+
+// ToJSON serializes the full event; the promoted BaseEvent method would
+// only include the type and timestamp
+func (e *WebRTCSessionStateEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
+
 // ============================================================================
 // Event Wrapper for WebSocket Transmission
 // ============================================================================
@@ -460,6 +497,7 @@ func NewPlatformDisconnectedEvent(platform, reason string) *PlatformDisconnected
 type EventWrapper struct {
 	Type      string          `json:"type"`
 	Timestamp time.Time       `json:"timestamp"`
+	Sequence  int64           `json:"sequence,omitempty"` // Set by EventBus.PublishBridgeEvent
 	Data      json.RawMessage `json:"data"`
 }
 
@@ -0,0 +1,108 @@
+package eventbus
+
+import (
+	"net/url"
+	"strings"
+)
+
+// RoomEvent is implemented by bridge events that belong to a Matrix room.
+// Room-scoped events are only delivered to subscribers of that room.
+type RoomEvent interface {
+	EventRoomID() string
+}
+
+// EventRoomID returns the room of a bridge event, or "" if it is not room-scoped
+func EventRoomID(event BridgeEvent) string {
+	if re, ok := event.(RoomEvent); ok {
+		return re.EventRoomID()
+	}
+	return ""
+}
+
+// EventRoomID returns the room the agent was started for
+func (e *AgentStartedEvent) EventRoomID() string {
+	return e.RoomID
+}
+
+// EventRoomID returns the room of the voice session
+func (e *WebRTCSessionStateEvent) EventRoomID() string {
+	return e.RoomID
+}
+
+// IsEmpty reports whether the filter matches every event
+func (f EventFilter) IsEmpty() bool {
+	return !f.hasRooms() && f.SenderID == "" && len(f.EventType) == 0
+}
+
+// Matches reports whether an event with the given type, room and sender
+// passes the filter. Events without a room (bridge-wide events such as
+// budget alerts) are not subject to the room filter; events without a
+// sender are not subject to the sender filter.
+func (f EventFilter) Matches(eventType, roomID, sender string) bool {
+	if roomID != "" && f.hasRooms() && !f.hasRoom(roomID) {
+		return false
+	}
+
+	if sender != "" && f.SenderID != "" && sender != f.SenderID {
+		return false
+	}
+
+	if len(f.EventType) > 0 {
+		match := false
+		for _, t := range f.EventType {
+			if eventType == t {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasRooms reports whether the filter restricts rooms
+func (f EventFilter) hasRooms() bool {
+	return f.RoomID != "" || len(f.RoomIDs) > 0
+}
+
+// hasRoom reports whether roomID is one of the filter's rooms
+func (f EventFilter) hasRoom(roomID string) bool {
+	if f.RoomID == roomID {
+		return true
+	}
+	for _, id := range f.RoomIDs {
+		if id == roomID {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterFromQuery builds a subscription filter from WebSocket handshake
+// query parameters. room_id and event_type may be repeated or hold a
+// comma-separated list; sender_id restricts the sender.
+//
+//	/ws?room_id=!abc:example.com,!def:example.com&event_type=agent.started
+func FilterFromQuery(query url.Values) EventFilter {
+	return EventFilter{
+		RoomIDs:   splitQueryList(query["room_id"]),
+		SenderID:  strings.TrimSpace(query.Get("sender_id")),
+		EventType: splitQueryList(query["event_type"]),
+	}
+}
+
+// splitQueryList flattens repeated and comma-separated query values
+func splitQueryList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+	}
+	return out
+}
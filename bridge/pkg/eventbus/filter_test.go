@@ -0,0 +1,159 @@
+package eventbus
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filteringBroadcaster delivers to clients by filter, like the HTTP server
+type filteringBroadcaster struct {
+	mu        sync.Mutex
+	clients   map[string]EventFilter
+	delivered map[string][]string
+}
+
+func (f *filteringBroadcaster) BroadcastEvent(eventType string, payload []byte) {
+	f.BroadcastFiltered(eventType, "", payload)
+}
+
+func (f *filteringBroadcaster) BroadcastFiltered(eventType, roomID string, payload []byte) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	dropped := 0
+	for id, filter := range f.clients {
+		if !filter.Matches(eventType, roomID, "") {
+			continue
+		}
+		if id == "slow" {
+			dropped++
+			continue
+		}
+		f.delivered[id] = append(f.delivered[id], eventType)
+	}
+	return dropped
+}
+
+func (f *filteringBroadcaster) ClientCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.clients)
+}
+
+func TestEventFilterMatches(t *testing.T) {
+	filter := EventFilter{
+		RoomIDs:   []string{"!a:example.com", "!b:example.com"},
+		EventType: []string{EventTypeAgentStarted},
+	}
+
+	assert.True(t, filter.Matches(EventTypeAgentStarted, "!b:example.com", ""))
+	assert.False(t, filter.Matches(EventTypeAgentStarted, "!c:example.com", ""), "other room")
+	assert.False(t, filter.Matches(EventTypeAgentStopped, "!a:example.com", ""), "other type")
+	assert.True(t, filter.Matches(EventTypeAgentStarted, "", ""), "bridge-wide events skip the room filter")
+
+	assert.True(t, EventFilter{}.IsEmpty())
+	assert.True(t, EventFilter{}.Matches("anything", "!x:example.com", "@u:example.com"))
+	assert.False(t, EventFilter{SenderID: "@a:example.com"}.Matches("m.room.message", "", "@b:example.com"))
+}
+
+func TestFilterFromQuery(t *testing.T) {
+	query, err := url.ParseQuery("room_id=!a:example.com,%20!b:example.com&room_id=!c:example.com&event_type=agent.started,&sender_id=@u:example.com")
+	require.NoError(t, err)
+
+	filter := FilterFromQuery(query)
+	assert.Equal(t, []string{"!a:example.com", "!b:example.com", "!c:example.com"}, filter.RoomIDs)
+	assert.Equal(t, []string{"agent.started"}, filter.EventType)
+	assert.Equal(t, "@u:example.com", filter.SenderID)
+
+	assert.True(t, FilterFromQuery(url.Values{}).IsEmpty())
+}
+
+func TestEventBusPublishBridgeEventFiltered(t *testing.T) {
+	broadcaster := &filteringBroadcaster{
+		clients: map[string]EventFilter{
+			"all":    {},
+			"room-a": {RoomIDs: []string{"!a:example.com"}},
+			"agents": {EventType: []string{EventTypeAgentStarted}},
+		},
+		delivered: make(map[string][]string),
+	}
+
+	bus := NewEventBus(Config{
+		WebSocketEnabled:  true,
+		WebSocketAddr:     "localhost:0",
+		WebSocketPath:     "/ws",
+		MaxSubscribers:    10,
+		InactivityTimeout: 5 * time.Minute,
+	})
+	bus.SetBroadcaster(broadcaster)
+	defer bus.Stop()
+
+	require.NoError(t, bus.PublishBridgeEvent(NewWebRTCSessionStateEvent("sess_1", "!b:example.com", "", "active")))
+	require.NoError(t, bus.PublishBridgeEvent(NewAgentStartedEvent("agent_1", "helper", "openclaw", WithRoomID("!a:example.com"))))
+
+	assert.Equal(t, []string{EventTypeWebRTCSessionState, EventTypeAgentStarted}, broadcaster.delivered["all"])
+	assert.Equal(t, []string{EventTypeAgentStarted}, broadcaster.delivered["room-a"])
+	assert.Equal(t, []string{EventTypeAgentStarted}, broadcaster.delivered["agents"])
+}
+
+func TestEventBusSubscribeRoomIDs(t *testing.T) {
+	bus := NewEventBus(DefaultConfig())
+	defer bus.Stop()
+
+	sub, err := bus.Subscribe(EventFilter{RoomIDs: []string{"!a:example.com", "!b:example.com"}})
+	require.NoError(t, err)
+
+	for _, room := range []string{"!a:example.com", "!c:example.com", "!b:example.com"} {
+		require.NoError(t, bus.Publish(&MatrixEvent{Type: "m.room.message", RoomID: room, Sender: "@u:example.com"}))
+	}
+
+	var rooms []string
+	for len(sub.EventChannel) > 0 {
+		rooms = append(rooms, (<-sub.EventChannel).Event.RoomID)
+	}
+	assert.Equal(t, []string{"!a:example.com", "!b:example.com"}, rooms)
+}
+
+func TestEventBusMetrics(t *testing.T) {
+	broadcaster := &filteringBroadcaster{
+		clients: map[string]EventFilter{
+			"fast": {},
+			"slow": {},
+		},
+		delivered: make(map[string][]string),
+	}
+
+	bus := NewEventBus(Config{
+		WebSocketEnabled: true,
+		WebSocketPath:    "/ws",
+		MaxSubscribers:   10,
+	})
+	bus.SetBroadcaster(broadcaster)
+	defer bus.Stop()
+
+	metrics := bus.Metrics()
+	assert.Nil(t, metrics.LastPublish)
+	assert.Equal(t, 2, metrics.WebSocketClients)
+	assert.Equal(t, 10, metrics.MaxSubscribers)
+
+	// An in-process subscriber whose buffer is full also counts as a drop
+	sub, err := bus.Subscribe(EventFilter{})
+	require.NoError(t, err)
+	for i := 0; i < cap(sub.EventChannel); i++ {
+		sub.EventChannel <- &MatrixEventWrapper{}
+	}
+	require.NoError(t, bus.Publish(&MatrixEvent{Type: "m.room.message", RoomID: "!a:example.com", Sender: "@u:example.com"}))
+	require.NoError(t, bus.PublishBridgeEvent(NewWebRTCSessionStateEvent("sess", "!a:example.com", "", "active")))
+
+	metrics = bus.Metrics()
+	assert.Equal(t, 1, metrics.Subscribers)
+	assert.Equal(t, uint64(2), metrics.PublishedEvents)
+	assert.Equal(t, uint64(2), metrics.DroppedEvents, "one full subscriber channel and one slow WebSocket client")
+	assert.Equal(t, int64(1), metrics.LastSequence)
+	require.NotNil(t, metrics.LastPublish)
+	assert.WithinDuration(t, time.Now(), *metrics.LastPublish, time.Minute)
+}
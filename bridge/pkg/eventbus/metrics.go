@@ -0,0 +1,48 @@
+package eventbus
+
+import "time"
+
+// Metrics summarizes event bus delivery for health reporting
+type Metrics struct {
+	Subscribers      int        `json:"subscribers"`       // In-process subscribers
+	WebSocketClients int        `json:"websocket_clients"` // Connected WebSocket clients, if the broadcaster reports them
+	MaxSubscribers   int        `json:"max_subscribers"`
+	PublishedEvents  uint64     `json:"published_events"`
+	DroppedEvents    uint64     `json:"dropped_events"` // Deliveries skipped because a consumer's buffer was full
+	LastPublish      *time.Time `json:"last_publish,omitempty"`
+	LastSequence     int64      `json:"last_sequence"`
+}
+
+// Metrics returns subscriber counts and delivery counters
+func (b *EventBus) Metrics() Metrics {
+	b.mu.RLock()
+	subscribers := len(b.subscribers)
+	b.mu.RUnlock()
+
+	m := Metrics{
+		Subscribers:     subscribers,
+		MaxSubscribers:  b.maxSubscribers,
+		PublishedEvents: b.published.Load(),
+		DroppedEvents:   b.dropped.Load(),
+		LastSequence:    b.LastSequence(),
+	}
+	if b.websocketServer != nil {
+		if n, ok := b.websocketServer.ClientCount(); ok {
+			m.WebSocketClients = n
+		}
+	}
+	if ns := b.lastPublish.Load(); ns != 0 {
+		t := time.Unix(0, ns)
+		m.LastPublish = &t
+	}
+	return m
+}
+
+// recordPublish updates the delivery counters after an event is published
+func (b *EventBus) recordPublish(dropped int) {
+	b.published.Add(1)
+	if dropped > 0 {
+		b.dropped.Add(uint64(dropped))
+	}
+	b.lastPublish.Store(time.Now().UnixNano())
+}
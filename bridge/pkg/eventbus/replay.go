@@ -0,0 +1,68 @@
+package eventbus
+
+// DefaultBacklogSize is the number of published bridge events kept for replay
+const DefaultBacklogSize = 1000
+
+// backlogEntry is a bridge event retained for replay to reconnecting clients
+type backlogEntry struct {
+	seq       int64
+	eventType string
+	roomID    string
+	payload   []byte
+}
+
+// ReplayAttachFunc receives the retained events published after a cursor,
+// oldest first. complete is false when events after the cursor have already
+// been evicted from the backlog (or the bus restarted since), in which case
+// the client must resynchronize its state.
+type ReplayAttachFunc func(missed [][]byte, complete bool)
+
+// appendBacklog assigns the next sequence number to an event and retains it,
+// evicting the oldest entry once the backlog is full. Callers must hold
+// b.backlogMu.
+func (b *EventBus) appendBacklog(entry backlogEntry) {
+	if b.backlogSize <= 0 {
+		return
+	}
+	if len(b.backlog) >= b.backlogSize {
+		copy(b.backlog, b.backlog[len(b.backlog)-b.backlogSize+1:])
+		b.backlog = b.backlog[:b.backlogSize-1]
+	}
+	b.backlog = append(b.backlog, entry)
+}
+
+// ReplaySince hands attach the retained events published after lastSeq that
+// match filter. Publishing is held while attach runs, so a client that
+// registers for live delivery inside attach neither misses nor duplicates
+// events between the replay and the live stream.
+func (b *EventBus) ReplaySince(lastSeq int64, filter EventFilter, attach ReplayAttachFunc) {
+	b.backlogMu.Lock()
+	defer b.backlogMu.Unlock()
+
+	// The cursor is too old if the event right after it was evicted, and
+	// from a previous run if it is ahead of the current sequence
+	complete := lastSeq <= b.seq
+	if len(b.backlog) > 0 && b.backlog[0].seq > lastSeq+1 {
+		complete = false
+	}
+	if len(b.backlog) == 0 && lastSeq < b.seq {
+		complete = false
+	}
+
+	var missed [][]byte
+	for _, entry := range b.backlog {
+		if (entry.seq <= lastSeq && complete) || !filter.Matches(entry.eventType, entry.roomID, "") {
+			continue
+		}
+		missed = append(missed, entry.payload)
+	}
+
+	attach(missed, complete)
+}
+
+// LastSequence returns the sequence number of the most recent bridge event
+func (b *EventBus) LastSequence() int64 {
+	b.backlogMu.Lock()
+	defer b.backlogMu.Unlock()
+	return b.seq
+}
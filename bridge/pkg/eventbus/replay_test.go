@@ -0,0 +1,90 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replaySequences decodes the sequence numbers of replayed payloads
+func replaySequences(t *testing.T, payloads [][]byte) []int64 {
+	t.Helper()
+	seqs := make([]int64, 0, len(payloads))
+	for _, payload := range payloads {
+		var wrapper EventWrapper
+		require.NoError(t, json.Unmarshal(payload, &wrapper))
+		seqs = append(seqs, wrapper.Sequence)
+	}
+	return seqs
+}
+
+func replay(bus *EventBus, lastSeq int64, filter EventFilter) ([][]byte, bool) {
+	var missed [][]byte
+	var complete bool
+	bus.ReplaySince(lastSeq, filter, func(m [][]byte, c bool) {
+		missed, complete = m, c
+	})
+	return missed, complete
+}
+
+func TestPublishBridgeEventSequence(t *testing.T) {
+	broadcaster := &mockBroadcaster{}
+	bus := NewEventBus(Config{WebSocketEnabled: true, WebSocketPath: "/ws"})
+	bus.SetBroadcaster(broadcaster)
+	defer bus.Stop()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, bus.PublishBridgeEvent(NewWebRTCSessionStateEvent("sess", "!a:example.com", "", "active")))
+	}
+
+	payloads := make([][]byte, 0, len(broadcaster.calls))
+	for _, call := range broadcaster.calls {
+		payloads = append(payloads, call.payload)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, replaySequences(t, payloads))
+	assert.Equal(t, int64(3), bus.LastSequence())
+}
+
+func TestReplaySince(t *testing.T) {
+	bus := NewEventBus(Config{BacklogSize: 3})
+	defer bus.Stop()
+
+	rooms := []string{"!a:example.com", "!b:example.com", "!a:example.com", "!b:example.com", "!a:example.com"}
+	for _, room := range rooms {
+		require.NoError(t, bus.PublishBridgeEvent(NewWebRTCSessionStateEvent("sess", room, "", "active")))
+	}
+
+	// Backlog holds 3..5
+	missed, complete := replay(bus, 3, EventFilter{})
+	assert.True(t, complete)
+	assert.Equal(t, []int64{4, 5}, replaySequences(t, missed))
+
+	missed, complete = replay(bus, 2, EventFilter{RoomIDs: []string{"!a:example.com"}})
+	assert.True(t, complete, "cursor right before the oldest retained event")
+	assert.Equal(t, []int64{3, 5}, replaySequences(t, missed))
+
+	missed, complete = replay(bus, 5, EventFilter{})
+	assert.True(t, complete)
+	assert.Empty(t, missed)
+
+	missed, complete = replay(bus, 1, EventFilter{})
+	assert.False(t, complete, "event 2 was evicted")
+	assert.Equal(t, []int64{3, 4, 5}, replaySequences(t, missed))
+
+	missed, complete = replay(bus, 42, EventFilter{})
+	assert.False(t, complete, "cursor from before a restart")
+	assert.Equal(t, []int64{3, 4, 5}, replaySequences(t, missed))
+}
+
+func TestReplayBacklogDisabled(t *testing.T) {
+	bus := NewEventBus(Config{BacklogSize: -1})
+	defer bus.Stop()
+
+	require.NoError(t, bus.PublishBridgeEvent(NewWebRTCSessionStateEvent("sess", "!a:example.com", "", "active")))
+
+	missed, complete := replay(bus, 0, EventFilter{})
+	assert.False(t, complete)
+	assert.Empty(t, missed)
+}
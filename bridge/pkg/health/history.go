@@ -0,0 +1,48 @@
+package health
+
+import "time"
+
+// DefaultHistorySize is the number of health checks kept per container
+const DefaultHistorySize = 50
+
+// HealthCheck is one recorded health check result
+type HealthCheck struct {
+	Timestamp time.Time `json:"timestamp"`
+	State     string    `json:"state"`
+	Healthy   bool      `json:"healthy"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// recordCheck appends a check to the container's history, dropping the
+// oldest entry once size is reached. Callers must hold h.mu.
+func (h *ContainerHealth) recordCheck(size int, check HealthCheck) {
+	if size <= 0 {
+		return
+	}
+	if len(h.history) >= size {
+		copy(h.history, h.history[len(h.history)-size+1:])
+		h.history = h.history[:size-1]
+	}
+	h.history = append(h.history, check)
+}
+
+// History returns up to limit of the most recent health checks for a
+// container, oldest first. A limit of zero or less returns the whole history.
+func (m *Monitor) History(containerID string, limit int) ([]HealthCheck, bool) {
+	m.mu.RLock()
+	health, exists := m.containers[containerID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+
+	health.mu.RLock()
+	defer health.mu.RUnlock()
+
+	checks := health.history
+	if limit > 0 && len(checks) > limit {
+		checks = checks[len(checks)-limit:]
+	}
+	return append([]HealthCheck(nil), checks...), true
+}
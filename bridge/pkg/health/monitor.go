@@ -13,11 +13,19 @@ import (
 	"log/slog"
 )
 
+// ContainerRuntime is the subset of the Docker client used by the monitor
+type ContainerRuntime interface {
+	IsRunning(containerID string) (bool, error)
+	StartContainer(ctx context.Context, containerID string) error
+}
+
 // Monitor tracks container health and takes recovery actions
 type Monitor struct {
-	dockerClient *docker.Client
+	dockerClient ContainerRuntime
 	checkInterval time.Duration
 	maxFailures  int
+	restart      RestartPolicy
+	historySize  int
 	containers   map[string]*ContainerHealth
 	mu           sync.RWMutex
 	ctx          context.Context
@@ -35,6 +43,11 @@ type ContainerHealth struct {
 	FailureCount int
 	LastCheck    time.Time
 	LastHealthy  time.Time
+	RestartCount int // Restarts since the container was last stable
+	LastRestart  time.Time
+	NextRestart  time.Time // Earliest time the next restart may be attempted
+	Escalated    bool      // Failure handler called after restarts ran out
+	history      []HealthCheck
 	mu           sync.RWMutex
 }
 
@@ -49,6 +62,10 @@ func (h *ContainerHealth) Copy() *ContainerHealth {
 		FailureCount: h.FailureCount,
 		LastCheck:    h.LastCheck,
 		LastHealthy:  h.LastHealthy,
+		RestartCount: h.RestartCount,
+		LastRestart:  h.LastRestart,
+		NextRestart:  h.NextRestart,
+		Escalated:    h.Escalated,
 	}
 }
 
@@ -61,6 +78,23 @@ type MonitorConfig struct {
 	MaxFailures     int           // Max consecutive failures before action
 	MaxStaleness    time.Duration // Max time since last health check
 	RestartOnFailure bool         // Automatically restart failed containers
+
+	// Restart policy, used when RestartOnFailure is set
+	MaxRestarts       int           // Restarts before escalating to the failure handler
+	RestartBackoff    time.Duration // Delay after the first restart, doubled for each further one
+	MaxRestartBackoff time.Duration // Upper bound on the restart delay
+	RestartResetAfter time.Duration // Time running after a restart before the count resets
+
+	HistorySize int // Health checks kept per container for diagnosis
+}
+
+// RestartPolicy controls automatic restarts of failed containers
+type RestartPolicy struct {
+	Enabled     bool
+	MaxRestarts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+	ResetAfter  time.Duration
 }
 
 // DefaultMonitorConfig returns default monitoring configuration
@@ -70,6 +104,11 @@ func DefaultMonitorConfig() MonitorConfig {
 		MaxFailures:      3,
 		MaxStaleness:     5 * time.Minute,
 		RestartOnFailure: false, // Manual intervention by default
+		MaxRestarts:       3,
+		RestartBackoff:    10 * time.Second,
+		MaxRestartBackoff: 5 * time.Minute,
+		RestartResetAfter: 10 * time.Minute,
+		HistorySize:       DefaultHistorySize,
 	}
 }
 
@@ -83,11 +122,34 @@ func NewMonitor(dockerClient *docker.Client, config MonitorConfig) *Monitor {
 	if config.MaxFailures == 0 {
 		config.MaxFailures = DefaultMonitorConfig().MaxFailures
 	}
+	if config.MaxRestarts == 0 {
+		config.MaxRestarts = DefaultMonitorConfig().MaxRestarts
+	}
+	if config.RestartBackoff == 0 {
+		config.RestartBackoff = DefaultMonitorConfig().RestartBackoff
+	}
+	if config.MaxRestartBackoff == 0 {
+		config.MaxRestartBackoff = DefaultMonitorConfig().MaxRestartBackoff
+	}
+	if config.RestartResetAfter == 0 {
+		config.RestartResetAfter = DefaultMonitorConfig().RestartResetAfter
+	}
+	if config.HistorySize == 0 {
+		config.HistorySize = DefaultMonitorConfig().HistorySize
+	}
 
 	return &Monitor{
 		dockerClient: dockerClient,
 		checkInterval: config.CheckInterval,
 		maxFailures:  config.MaxFailures,
+		restart:      RestartPolicy{
+			Enabled:     config.RestartOnFailure,
+			MaxRestarts: config.MaxRestarts,
+			Backoff:     config.RestartBackoff,
+			MaxBackoff:  config.MaxRestartBackoff,
+			ResetAfter:  config.RestartResetAfter,
+		},
+		historySize:  config.HistorySize,
 		containers:   make(map[string]*ContainerHealth),
 		ctx:          ctx,
 		cancel:       cancel,
@@ -112,7 +174,9 @@ func (m *Monitor) Start() error {
 
 	m.securityLog.LogSecurityEvent("health_monitor_started",
 		slog.Duration("check_interval", m.checkInterval),
-		slog.Int("max_failures", m.maxFailures))
+		slog.Int("max_failures", m.maxFailures),
+		slog.Bool("restart_on_failure", m.restart.Enabled),
+		slog.Int("max_restarts", m.restart.MaxRestarts))
 
 	return nil
 }
@@ -170,6 +234,11 @@ func (m *Monitor) UpdateHealth(containerID, state string, isHealthy bool) {
 
 	health.State = state
 	health.LastCheck = time.Now()
+	health.recordCheck(m.historySize, HealthCheck{
+		Timestamp: health.LastCheck,
+		State:     state,
+		Healthy:   isHealthy,
+	})
 
 	if isHealthy {
 		health.FailureCount = 0
@@ -262,6 +331,11 @@ func (m *Monitor) checkContainer(containerID string) {
 		// Error checking container status
 		health.FailureCount++
 		health.State = "error"
+		health.recordCheck(m.historySize, HealthCheck{
+			Timestamp: health.LastCheck,
+			State:     health.State,
+			Reason:    err.Error(),
+		})
 
 		m.securityLog.LogSecurityEvent("container_health_check_error",
 			slog.String("container_id", containerID),
@@ -269,7 +343,7 @@ func (m *Monitor) checkContainer(containerID string) {
 			slog.Int("failure_count", health.FailureCount))
 
 		if health.FailureCount >= m.maxFailures {
-			m.handleFailure(containerID, health.Name, "health_check_error")
+			m.recoverContainer(health, "health_check_error")
 		}
 		return
 	}
@@ -278,13 +352,18 @@ func (m *Monitor) checkContainer(containerID string) {
 		// Container is not running
 		health.FailureCount++
 		health.State = "stopped"
+		health.recordCheck(m.historySize, HealthCheck{
+			Timestamp: health.LastCheck,
+			State:     health.State,
+			Reason:    "container_stopped",
+		})
 
 		m.securityLog.LogSecurityEvent("container_not_running",
 			slog.String("container_id", containerID),
 			slog.Int("failure_count", health.FailureCount))
 
 		if health.FailureCount >= m.maxFailures {
-			m.handleFailure(containerID, health.Name, "container_stopped")
+			m.recoverContainer(health, "container_stopped")
 		}
 		return
 	}
@@ -293,6 +372,77 @@ func (m *Monitor) checkContainer(containerID string) {
 	health.State = "running"
 	health.FailureCount = 0
 	health.LastHealthy = time.Now()
+	health.recordCheck(m.historySize, HealthCheck{
+		Timestamp: health.LastCheck,
+		State:     health.State,
+		Healthy:   true,
+	})
+
+	// Forget restarts once the container has stayed up long enough, so a
+	// crash loop keeps counting towards MaxRestarts
+	if health.RestartCount > 0 && health.LastHealthy.Sub(health.LastRestart) >= m.restart.ResetAfter {
+		health.RestartCount = 0
+		health.NextRestart = time.Time{}
+		health.Escalated = false
+	}
+}
+
+// recoverContainer restarts a failed container under the restart policy,
+// escalating to the failure handler once restarts are exhausted. Without a
+// restart policy the failure handler is called directly.
+// Callers must hold health.mu.
+func (m *Monitor) recoverContainer(health *ContainerHealth, reason string) {
+	if !m.restart.Enabled {
+		m.handleFailure(health.ID, health.Name, reason)
+		return
+	}
+
+	if health.RestartCount >= m.restart.MaxRestarts {
+		if !health.Escalated {
+			health.Escalated = true
+			m.handleFailure(health.ID, health.Name,
+				fmt.Sprintf("%s after %d restarts", reason, health.RestartCount))
+		}
+		return
+	}
+
+	now := time.Now()
+	if now.Before(health.NextRestart) {
+		return
+	}
+
+	health.RestartCount++
+	health.LastRestart = now
+	health.NextRestart = now.Add(m.restart.backoff(health.RestartCount))
+
+	err := m.dockerClient.StartContainer(m.ctx, health.ID)
+	attrs := []slog.Attr{
+		slog.String("container_id", health.ID),
+		slog.String("reason", reason),
+		slog.Int("restart_count", health.RestartCount),
+		slog.Time("next_restart", health.NextRestart),
+	}
+	if err != nil {
+		m.securityLog.LogSecurityEvent("container_restart_failed",
+			append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+
+	health.State = "restarting"
+	health.FailureCount = 0
+	m.securityLog.LogSecurityEvent("container_restarted", attrs...)
+}
+
+// backoff returns the delay before the restart after the given one
+func (p RestartPolicy) backoff(restarts int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < restarts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
 }
 
 // handleFailure handles a container failure
@@ -0,0 +1,183 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRuntime reports a fixed running state and records start calls
+type fakeRuntime struct {
+	mu       sync.Mutex
+	running  bool
+	starts   int
+	startErr error
+}
+
+func (f *fakeRuntime) IsRunning(containerID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.running, nil
+}
+
+func (f *fakeRuntime) StartContainer(ctx context.Context, containerID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.starts++
+	return f.startErr
+}
+
+func newTestMonitor(t *testing.T, config MonitorConfig, runtime *fakeRuntime) *Monitor {
+	t.Helper()
+	m := NewMonitor(nil, config)
+	m.dockerClient = runtime
+	t.Cleanup(m.Stop)
+	return m
+}
+
+func TestRestartPolicyBackoff(t *testing.T) {
+	p := RestartPolicy{Backoff: 10 * time.Second, MaxBackoff: time.Minute}
+
+	for restarts, want := range map[int]time.Duration{
+		1: 10 * time.Second,
+		2: 20 * time.Second,
+		3: 40 * time.Second,
+		4: time.Minute,
+		9: time.Minute,
+	} {
+		if got := p.backoff(restarts); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", restarts, got, want)
+		}
+	}
+}
+
+func TestMonitorNotifiesWithoutRestartPolicy(t *testing.T) {
+	runtime := &fakeRuntime{running: false}
+	m := newTestMonitor(t, MonitorConfig{MaxFailures: 1}, runtime)
+
+	var reasons []string
+	m.SetFailureHandler(func(containerID, containerName, reason string) {
+		reasons = append(reasons, reason)
+	})
+	m.Register("abc123", "agent")
+	m.checkContainer("abc123")
+
+	if runtime.starts != 0 {
+		t.Errorf("expected no restart without a policy, got %d", runtime.starts)
+	}
+	if len(reasons) != 1 {
+		t.Errorf("expected one failure notification, got %v", reasons)
+	}
+}
+
+func TestMonitorRestartsBeforeEscalating(t *testing.T) {
+	runtime := &fakeRuntime{running: false}
+	m := newTestMonitor(t, MonitorConfig{
+		MaxFailures:       1,
+		RestartOnFailure:  true,
+		MaxRestarts:       2,
+		RestartBackoff:    time.Nanosecond,
+		MaxRestartBackoff: time.Nanosecond,
+	}, runtime)
+
+	var reasons []string
+	m.SetFailureHandler(func(containerID, containerName, reason string) {
+		reasons = append(reasons, reason)
+	})
+	m.Register("abc123", "agent")
+
+	// The container crashes again after every restart
+	for i := 0; i < 5; i++ {
+		m.checkContainer("abc123")
+		time.Sleep(time.Millisecond)
+	}
+
+	if runtime.starts != 2 {
+		t.Errorf("expected 2 restarts, got %d", runtime.starts)
+	}
+	if len(reasons) != 1 || !strings.Contains(reasons[0], "after 2 restarts") {
+		t.Errorf("expected a single escalation after restarts ran out, got %v", reasons)
+	}
+
+	health, _ := m.GetHealth("abc123")
+	if health.RestartCount != 2 || !health.Escalated {
+		t.Errorf("expected restart count 2 and escalated, got %+v", health)
+	}
+}
+
+func TestMonitorRestartBackoffDelaysRetries(t *testing.T) {
+	runtime := &fakeRuntime{running: false}
+	m := newTestMonitor(t, MonitorConfig{
+		MaxFailures:      1,
+		RestartOnFailure: true,
+		MaxRestarts:      3,
+		RestartBackoff:   time.Hour,
+	}, runtime)
+	m.Register("abc123", "agent")
+
+	m.checkContainer("abc123")
+	m.checkContainer("abc123")
+
+	if runtime.starts != 1 {
+		t.Errorf("expected the second restart to wait for the backoff, got %d starts", runtime.starts)
+	}
+}
+
+func TestMonitorResetsRestartsWhenStable(t *testing.T) {
+	runtime := &fakeRuntime{running: false}
+	m := newTestMonitor(t, MonitorConfig{
+		MaxFailures:       1,
+		RestartOnFailure:  true,
+		RestartBackoff:    time.Nanosecond,
+		RestartResetAfter: time.Hour,
+	}, runtime)
+	m.Register("abc123", "agent")
+
+	m.checkContainer("abc123")
+	runtime.running = true
+
+	// Running, but not yet for RestartResetAfter
+	m.checkContainer("abc123")
+	if health, _ := m.GetHealth("abc123"); health.RestartCount != 1 {
+		t.Fatalf("expected restart count to survive a short recovery, got %d", health.RestartCount)
+	}
+
+	m.containers["abc123"].LastRestart = time.Now().Add(-2 * time.Hour)
+	m.checkContainer("abc123")
+	if health, _ := m.GetHealth("abc123"); health.RestartCount != 0 {
+		t.Errorf("expected restart count to reset once stable, got %d", health.RestartCount)
+	}
+}
+
+func TestMonitorHistory(t *testing.T) {
+	runtime := &fakeRuntime{running: true}
+	m := newTestMonitor(t, MonitorConfig{MaxFailures: 10, HistorySize: 2}, runtime)
+	m.Register("abc123", "agent")
+
+	m.checkContainer("abc123")
+	runtime.running = false
+	m.checkContainer("abc123")
+	m.checkContainer("abc123")
+
+	checks, ok := m.History("abc123", 0)
+	if !ok {
+		t.Fatal("expected history for a registered container")
+	}
+	if len(checks) != 2 {
+		t.Fatalf("expected history bounded to 2 checks, got %d", len(checks))
+	}
+	for _, check := range checks {
+		if check.Healthy || check.State != "stopped" || check.Reason != "container_stopped" {
+			t.Errorf("expected the stopped checks to remain, got %+v", check)
+		}
+	}
+
+	if checks, _ := m.History("abc123", 1); len(checks) != 1 {
+		t.Errorf("expected limit to return 1 check, got %d", len(checks))
+	}
+	if _, ok := m.History("missing", 0); ok {
+		t.Error("expected no history for an unknown container")
+	}
+}
@@ -23,13 +23,17 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/armorclaw/bridge/pkg/auth"
+	"github.com/armorclaw/bridge/pkg/discovery"
+	"github.com/armorclaw/bridge/pkg/eventbus"
 	"github.com/armorclaw/bridge/pkg/qr"
 	"github.com/armorclaw/bridge/pkg/rpc"
 	"github.com/armorclaw/bridge/pkg/securerandom"
+	wsadapter "github.com/armorclaw/bridge/pkg/websocket"
 	"github.com/gorilla/websocket"
 )
 
@@ -51,6 +55,12 @@ type ServerConfig struct {
 	APIPath     string
 	WSPath      string
 	Metrics     *rpc.Metrics
+	AdvertiseIP net.IP // Pins the address reported by /discover
+	// AdminRoomID is the room whose power levels grant Matrix callers
+	// access to admin RPC methods
+	AdminRoomID string
+	// WSKeepalive sets the ping/pong heartbeat on /ws connections
+	WSKeepalive wsadapter.Keepalive
 }
 
 // Server is the HTTPS server for the bridge
@@ -70,6 +80,20 @@ type Server struct {
 	apiPath               string
 	wsPath                string
 	metrics               *rpc.Metrics
+	eventReplayer         EventReplayer
+}
+
+// EventReplayer replays bridge events missed by a reconnecting WebSocket
+// client. It is implemented by eventbus.EventBus.
+type EventReplayer interface {
+	ReplaySince(lastSeq int64, filter eventbus.EventFilter, attach eventbus.ReplayAttachFunc)
+}
+
+// SetEventReplayer enables the last_seq handshake parameter on /ws
+func (s *Server) SetEventReplayer(replayer EventReplayer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eventReplayer = replayer
 }
 
 // SetOwnerClaimed allows the provisioning manager to update owner status
@@ -86,6 +110,8 @@ func (s *Server) SetProvisioningAvailable(available bool) {
 	s.provisioningAvailable = available
 }
 
+// SetAuthMiddleware sets how remote RPC callers are authenticated. Until it
+// is set every /api request is rejected.
 func (s *Server) SetAuthMiddleware(middleware *auth.RPCAuthMiddleware) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -108,6 +134,7 @@ type WebSocketClient struct {
 	ID       string
 	DeviceID string
 	Send     chan []byte
+	Filter   eventbus.EventFilter // Subscription filter from the handshake
 }
 
 // NewServer creates a new HTTPS server
@@ -521,31 +548,70 @@ func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
-	var req rpc.Request
-	if err := json.Unmarshal(body, &req); err != nil {
-		s.writeError(w, nil, -32700, "Invalid JSON")
-		return
-	}
-
 	s.mu.RLock()
 	middleware := s.authMiddleware
 	s.mu.RUnlock()
+	bearerToken := auth.ExtractBearerToken(r.Header.Get("Authorization"))
+
+	// handle authenticates one request, which for a batch is each entry,
+	// since admin gating depends on the method called
+	handle := func(ctx context.Context, req *rpc.Request) *rpc.Response {
+		// Unlike the local socket, remote callers are not vouched for by file
+		// permissions, so requests are rejected unless they authenticate
+		if middleware == nil {
+			return unauthorizedResponse(req.ID, "unauthorized: remote RPC authentication is not configured")
+		}
 
-	if middleware != nil {
-		bearerToken := auth.ExtractBearerToken(r.Header.Get("Authorization"))
-		result := middleware.Authenticate(r.Context(), req.Method, bearerToken, "")
+		result := middleware.Authenticate(ctx, req.Method, bearerToken, s.config.AdminRoomID)
 		if !result.Authenticated {
-			s.writeError(w, req.ID, -32001, "unauthorized")
+			return unauthorizedResponse(req.ID, "unauthorized")
+		}
+
+		caller := result.AdminUserID
+		if result.UserInfo != nil {
+			caller = result.UserInfo.UserID
+		}
+		return s.rpcServer.Handle(rpc.WithCaller(ctx, caller), req)
+	}
+
+	var response interface{}
+	if rpc.IsBatch(body) {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			s.writeError(w, nil, -32700, "Invalid JSON")
 			return
 		}
+		response = s.rpcServer.HandleBatchWith(r.Context(), batch, handle)
+	} else {
+		var req rpc.Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			s.writeError(w, nil, -32700, "Invalid JSON")
+			return
+		}
+		if resp := handle(r.Context(), &req); resp != nil {
+			response = resp
+		}
 	}
 
-	response := s.rpcServer.Handle(r.Context(), &req)
+	// Notifications, alone or as a whole batch, get no response
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// unauthorizedResponse rejects a request that failed authentication
+func unauthorizedResponse(id interface{}, message string) *rpc.Response {
+	return &rpc.Response{
+		JSONRPC: rpc.JSONRPCVersion,
+		ID:      id,
+		Error:   &rpc.ErrorObj{Code: -32001, Message: message},
+	}
+}
+
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	serverName := s.config.ServerName
 	if serverName == "" {
@@ -621,7 +687,11 @@ func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleDiscover(w http.ResponseWriter, r *http.Request) {
 	hostname, _ := os.Hostname()
-	ips, _ := getLocalIPs()
+	// Best address first, without container bridge addresses clients can't reach
+	ips, err := discovery.LocalIPs(s.config.AdvertiseIP)
+	if err != nil {
+		ips, _ = getLocalIPs()
+	}
 	fingerprint, _ := s.GetCertificateFingerprint()
 
 	bridgeURL := fmt.Sprintf("https://%s", s.config.Hostname)
@@ -636,7 +706,7 @@ func (s *Server) handleDiscover(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	info := map[string]interface{}{
 		"name":              hostname,
 		"hostname":          s.config.Hostname,
 		"port":              s.config.Port,
@@ -653,7 +723,12 @@ func (s *Server) handleDiscover(w http.ResponseWriter, r *http.Request) {
 			"ws":     "/ws",
 			"health": "/health",
 		},
-	})
+	}
+	// Omitted when no address could be found
+	if len(ips) > 0 {
+		info["primary_ip"] = ips[0]
+	}
+	json.NewEncoder(w).Encode(info)
 }
 
 func (s *Server) handleFingerprint(w http.ResponseWriter, r *http.Request) {
@@ -836,6 +911,17 @@ var upgrader = websocket.Upgrader{
 }
 
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var lastSeq int64
+	if v := query.Get("last_seq"); v != "" {
+		seq, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || seq < 0 {
+			http.Error(w, "last_seq must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		lastSeq = seq
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("[WS] Upgrade error: %v", err)
@@ -844,16 +930,49 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 	defer conn.Close()
 
 	clientID := generateClientID()
-	client := &WebSocketClient{
-		ID:   clientID,
-		Send: make(chan []byte, 256),
+	filter := eventbus.FilterFromQuery(query)
+
+	// Queue any missed events ahead of live ones; the send buffer is sized
+	// so the replay never blocks the publisher
+	var client *WebSocketClient
+	register := func(missed [][]byte, complete bool) {
+		client = &WebSocketClient{
+			ID:     clientID,
+			Send:   make(chan []byte, 256+len(missed)+1),
+			Filter: filter,
+		}
+		if !complete {
+			client.Send <- replayIncompleteMessage(lastSeq)
+		}
+		for _, payload := range missed {
+			client.Send <- payload
+		}
+
+		s.mu.Lock()
+		s.clients[clientID] = client
+		s.mu.Unlock()
+
+		if query.Has("last_seq") {
+			log.Printf("[WS] Client %s replayed %d events after sequence %d (complete=%t)",
+				clientID, len(missed), lastSeq, complete)
+		}
 	}
 
-	s.mu.Lock()
-	s.clients[clientID] = client
-	s.mu.Unlock()
+	s.mu.RLock()
+	replayer := s.eventReplayer
+	s.mu.RUnlock()
+	if replayer != nil && query.Has("last_seq") {
+		replayer.ReplaySince(lastSeq, filter, register)
+	} else {
+		register(nil, true)
+	}
 
-	log.Printf("[WS] Client connected: %s", clientID)
+	if client.Filter.IsEmpty() {
+		log.Printf("[WS] Client connected: %s", clientID)
+	} else {
+		log.Printf("[WS] Client connected: %s (rooms=%v event_types=%v)",
+			clientID, client.Filter.RoomIDs, client.Filter.EventType)
+	}
 
 	defer func() {
 		s.mu.Lock()
@@ -869,11 +988,7 @@ func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) readPump(conn *websocket.Conn, client *WebSocketClient) {
 	conn.SetReadLimit(512 * 1024)
-	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		return nil
-	})
+	s.config.WSKeepalive.Arm(conn)
 
 	for {
 		_, message, err := conn.ReadMessage()
@@ -889,7 +1004,8 @@ func (s *Server) readPump(conn *websocket.Conn, client *WebSocketClient) {
 }
 
 func (s *Server) writePump(conn *websocket.Conn, client *WebSocketClient) {
-	ticker := time.NewTicker(30 * time.Second)
+	keepalive := s.config.WSKeepalive
+	ticker := time.NewTicker(keepalive.Interval())
 	defer func() {
 		ticker.Stop()
 		conn.Close()
@@ -921,8 +1037,7 @@ func (s *Server) writePump(conn *websocket.Conn, client *WebSocketClient) {
 			}
 
 		case <-ticker.C:
-			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := keepalive.Ping(conn); err != nil {
 				return
 			}
 		}
@@ -962,6 +1077,17 @@ func (s *Server) handleWSMessage(client *WebSocketClient, message []byte) {
 	}
 }
 
+// replayIncompleteMessage tells a reconnecting client that events after
+// its cursor are no longer retained, so it must refetch state over RPC
+func replayIncompleteMessage(lastSeq int64) []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":      "replay.incomplete",
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"payload":   map[string]int64{"last_seq": lastSeq},
+	})
+	return data
+}
+
 func (s *Server) sendToClient(client *WebSocketClient, msg interface{}) {
 	data, err := json.Marshal(msg)
 	if err != nil {
@@ -1267,6 +1393,35 @@ func (s *Server) BroadcastEvent(eventType string, payload []byte) {
 	}
 }
 
+// BroadcastFiltered sends a raw JSON event to the WebSocket clients whose
+// handshake subscription filter matches the event type and room. It returns
+// the number of matching clients skipped because their buffer was full.
+func (s *Server) BroadcastFiltered(eventType, roomID string, payload []byte) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dropped := 0
+	for _, client := range s.clients {
+		if !client.Filter.Matches(eventType, roomID, "") {
+			continue
+		}
+		select {
+		case client.Send <- payload:
+		default:
+			// Client buffer full — skip to avoid blocking
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// ClientCount returns the number of connected WebSocket clients
+func (s *Server) ClientCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.clients)
+}
+
 func getLocalIPs() ([]net.IP, error) {
 	var ips []net.IP
 
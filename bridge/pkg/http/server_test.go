@@ -0,0 +1,226 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/auth"
+	"github.com/armorclaw/bridge/pkg/rpc"
+	wsadapter "github.com/armorclaw/bridge/pkg/websocket"
+	"github.com/gorilla/websocket"
+)
+
+// staticAdminTokens accepts a fixed set of admin tokens
+type staticAdminTokens map[string]string
+
+func (t staticAdminTokens) ValidateAdminToken(token string) (string, string, bool) {
+	role, ok := t[token]
+	return "@admin:example.com", role, ok
+}
+
+// postRPC sends method to the /api handler with an optional bearer token
+// and returns the JSON-RPC error code, or 0 when the call was not rejected
+func postRPC(t *testing.T, s *Server, method, token string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"`+method+`"}`))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.handleRPC(rec, req)
+
+	var resp struct {
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v (%s)", err, rec.Body.String())
+	}
+	if resp.Error == nil {
+		return 0
+	}
+	return resp.Error.Code
+}
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	rpcServer, err := rpc.New(rpc.Config{})
+	if err != nil {
+		t.Fatalf("rpc.New() error = %v", err)
+	}
+	return NewServer(ServerConfig{}, rpcServer)
+}
+
+func TestHandleRPCRejectsWithoutAuthMiddleware(t *testing.T) {
+	s := newTestServer(t)
+
+	if code := postRPC(t, s, "pii.stats", "any-token"); code != -32001 {
+		t.Errorf("code = %d, want -32001 without an auth middleware", code)
+	}
+}
+
+func TestHandleRPCAuthentication(t *testing.T) {
+	s := newTestServer(t)
+	s.SetAuthMiddleware(auth.NewRPCAuthMiddleware(auth.RPCAuthMiddlewareConfig{
+		AdminTokenValidator: staticAdminTokens{"aat_member": "MEMBER"},
+		PublicMethods:       []string{"system.health"},
+		AdminMethods:        []string{"device.list"},
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		denied bool
+	}{
+		{"no token", "pii.stats", "", true},
+		{"unknown token", "pii.stats", "aat_unknown", true},
+		{"valid token", "pii.stats", "aat_member", false},
+		{"admin method needs admin role", "device.list", "aat_member", true},
+		{"public method", "system.health", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := postRPC(t, s, tt.method, tt.token)
+			if denied := code == -32001; denied != tt.denied {
+				t.Errorf("code = %d, denied = %v, want denied = %v", code, denied, tt.denied)
+			}
+		})
+	}
+}
+
+func TestHandleRPCDefaultAdminMethods(t *testing.T) {
+	s := newTestServer(t)
+	s.SetAuthMiddleware(auth.NewRPCAuthMiddleware(auth.RPCAuthMiddlewareConfig{
+		AdminTokenValidator: staticAdminTokens{"aat_member": "MEMBER", "aat_admin": "ADMIN"},
+		PublicMethods:       auth.DefaultPublicMethods,
+		AdminMethods:        auth.DefaultAdminMethods,
+	}))
+
+	for _, method := range []string{
+		"rotate_key",
+		"list_keys",
+		"find_duplicate_keys",
+		"list_key_versions",
+		"log.set_level",
+		"plugin.reload",
+		"recovery.cancel",
+		"resolve_error",
+		"export_errors",
+		"container.logs",
+		"container.terminate",
+	} {
+		t.Run(method, func(t *testing.T) {
+			if code := postRPC(t, s, method, "aat_member"); code != -32001 {
+				t.Errorf("member: code = %d, want -32001", code)
+			}
+			if code := postRPC(t, s, method, "aat_admin"); code == -32001 {
+				t.Error("admin: rejected, want the call to reach the method")
+			}
+		})
+	}
+}
+
+func TestHandleRPCBatchAuthenticatesEachEntry(t *testing.T) {
+	s := newTestServer(t)
+	s.SetAuthMiddleware(auth.NewRPCAuthMiddleware(auth.RPCAuthMiddlewareConfig{
+		AdminTokenValidator: staticAdminTokens{"aat_member": "MEMBER"},
+		PublicMethods:       []string{"system.health"},
+		AdminMethods:        []string{"device.list"},
+	}))
+
+	body := `[
+		{"jsonrpc":"2.0","id":1,"method":"pii.stats"},
+		{"jsonrpc":"2.0","id":2,"method":"device.list"},
+		{"jsonrpc":"2.0","method":"system.health"}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer aat_member")
+	rec := httptest.NewRecorder()
+	s.handleRPC(rec, req)
+
+	var resps []struct {
+		ID    float64 `json:"id"`
+		Error *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resps); err != nil {
+		t.Fatalf("decode response: %v (%s)", err, rec.Body.String())
+	}
+	if len(resps) != 2 {
+		t.Fatalf("got %d responses, want 2 (the notification gets none)", len(resps))
+	}
+	if resps[0].ID != 1 || (resps[0].Error != nil && resps[0].Error.Code == -32001) {
+		t.Errorf("member call was rejected: %+v", resps[0])
+	}
+	if resps[1].ID != 2 || resps[1].Error == nil || resps[1].Error.Code != -32001 {
+		t.Errorf("admin call by a member should be rejected: %+v", resps[1])
+	}
+}
+
+func TestHandleRPCNotificationBatchHasNoBody(t *testing.T) {
+	s := newTestServer(t)
+	s.SetAuthMiddleware(auth.NewRPCAuthMiddleware(auth.RPCAuthMiddlewareConfig{
+		PublicMethods: []string{"system.health"},
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api", strings.NewReader(`[{"jsonrpc":"2.0","method":"system.health"}]`))
+	rec := httptest.NewRecorder()
+	s.handleRPC(rec, req)
+
+	if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+		t.Errorf("status = %d, body = %q, want 204 with no body", rec.Code, rec.Body.String())
+	}
+}
+
+func TestWebSocketKeepalive(t *testing.T) {
+	s := newTestServer(t)
+	s.config.WSKeepalive = wsadapter.Keepalive{PingInterval: 20 * time.Millisecond, PongTimeout: 50 * time.Millisecond}
+	ts := httptest.NewServer(http.HandlerFunc(s.handleWebSocket))
+	defer ts.Close()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http")
+
+	// A client that keeps reading answers pings and stays connected
+	live, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer live.Close()
+	go func() {
+		for {
+			if _, _, err := live.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// A client that never reads never answers, like one behind a dead NAT
+	// mapping, and is dropped once its pongs are overdue
+	silent, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer silent.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.ClientCount() != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	for s.ClientCount() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := s.ClientCount(); n != 1 {
+		t.Fatalf("ClientCount() = %d, want only the responsive client left", n)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if n := s.ClientCount(); n != 1 {
+		t.Errorf("ClientCount() = %d after several ping intervals, want 1", n)
+	}
+}
@@ -0,0 +1,125 @@
+package keystore
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DuplicateGroup is a set of credentials that share the same token
+type DuplicateGroup struct {
+	IDs       []string   `json:"ids"`
+	Providers []Provider `json:"providers"`
+}
+
+// tokenHash fingerprints a token so duplicates can be found without
+// decrypting every credential. The database itself is encrypted, so the
+// hash never leaves the keystore in the clear.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// migrateTokenHashes adds the token_hash column and fills it in for
+// credentials stored before duplicate detection existed
+func (ks *Keystore) migrateTokenHashes(db *sql.DB) error {
+	if _, err := db.Exec("ALTER TABLE credentials ADD COLUMN token_hash TEXT"); err != nil {
+		if !strings.Contains(err.Error(), "duplicate column") {
+			return fmt.Errorf("failed to migrate credentials: %w", err)
+		}
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_credentials_token_hash ON credentials(token_hash)"); err != nil {
+		return fmt.Errorf("failed to create token hash index: %w", err)
+	}
+
+	rows, err := db.Query("SELECT id, token_encrypted, nonce FROM credentials WHERE token_hash IS NULL")
+	if err != nil {
+		return fmt.Errorf("failed to read credentials: %w", err)
+	}
+	hashes := make(map[string]string)
+	for rows.Next() {
+		var id string
+		var encrypted, nonce []byte
+		if err := rows.Scan(&id, &encrypted, &nonce); err != nil {
+			continue
+		}
+		token, err := ks.decrypt(encrypted, nonce)
+		if err != nil {
+			continue
+		}
+		hashes[id] = tokenHash(string(token))
+	}
+	rows.Close()
+
+	for id, hash := range hashes {
+		if _, err := db.Exec("UPDATE credentials SET token_hash = ? WHERE id = ?", hash, id); err != nil {
+			return fmt.Errorf("failed to backfill token hash: %w", err)
+		}
+	}
+	return nil
+}
+
+// duplicatesOf returns the IDs of other credentials whose token hash matches.
+// Callers must hold ks.mu.
+func (ks *Keystore) duplicatesOf(id, hash string) ([]string, error) {
+	rows, err := ks.db.Query("SELECT id FROM credentials WHERE token_hash = ? AND id != ? ORDER BY id", hash, id)
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var other string
+		if err := rows.Scan(&other); err != nil {
+			return nil, fmt.Errorf("failed to read credential: %w", err)
+		}
+		ids = append(ids, other)
+	}
+	return ids, rows.Err()
+}
+
+// FindDuplicates reports groups of credentials that share the same token
+func (ks *Keystore) FindDuplicates() ([]DuplicateGroup, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if !ks.isOpen {
+		return nil, errors.New("keystore is not open")
+	}
+
+	rows, err := ks.db.Query(`
+	SELECT token_hash, id, provider FROM credentials
+	WHERE token_hash IN (
+		SELECT token_hash FROM credentials
+		WHERE token_hash IS NOT NULL
+		GROUP BY token_hash HAVING COUNT(*) > 1
+	)
+	ORDER BY token_hash, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []DuplicateGroup{}
+	lastHash := ""
+	for rows.Next() {
+		var hash, id string
+		var provider Provider
+		if err := rows.Scan(&hash, &id, &provider); err != nil {
+			return nil, fmt.Errorf("failed to read credential: %w", err)
+		}
+		if hash != lastHash {
+			groups = append(groups, DuplicateGroup{})
+			lastHash = hash
+		}
+		group := &groups[len(groups)-1]
+		group.IDs = append(group.IDs, id)
+		group.Providers = append(group.Providers, provider)
+	}
+	return groups, rows.Err()
+}
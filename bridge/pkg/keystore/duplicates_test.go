@@ -0,0 +1,145 @@
+//go:build cgo
+
+package keystore
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+func newDuplicatesTestKeystore(t *testing.T) *Keystore {
+	t.Helper()
+
+	masterKey := make([]byte, 32)
+	for i := range masterKey {
+		masterKey[i] = byte(i)
+	}
+
+	ks, err := New(Config{
+		DBPath:    filepath.Join(t.TempDir(), "test.db"),
+		MasterKey: masterKey,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("Failed to open keystore: %v", err)
+	}
+	t.Cleanup(func() { ks.Close() })
+	return ks
+}
+
+func TestStoreWithDuplicateCheck(t *testing.T) {
+	ks := newDuplicatesTestKeystore(t)
+	now := time.Now().Unix()
+
+	dups, err := ks.StoreWithDuplicateCheck(Credential{ID: "openai-a", Provider: ProviderOpenAI, Token: "sk-shared", CreatedAt: now})
+	if err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if len(dups) != 0 {
+		t.Errorf("Expected no duplicates for first key, got %v", dups)
+	}
+
+	dups, err = ks.StoreWithDuplicateCheck(Credential{ID: "openai-b", Provider: ProviderOpenAI, Token: "sk-shared", CreatedAt: now})
+	if err != nil {
+		t.Fatalf("Duplicate store should not be blocked: %v", err)
+	}
+	if len(dups) != 1 || dups[0] != "openai-a" {
+		t.Errorf("Expected duplicate_of [openai-a], got %v", dups)
+	}
+
+	// Re-storing the same ID is not a duplicate of itself
+	dups, err = ks.StoreWithDuplicateCheck(Credential{ID: "openai-c", Provider: ProviderOpenAI, Token: "sk-unique", CreatedAt: now})
+	if err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	dups, err = ks.StoreWithDuplicateCheck(Credential{ID: "openai-c", Provider: ProviderOpenAI, Token: "sk-unique", CreatedAt: now})
+	if err != nil || len(dups) != 0 {
+		t.Errorf("Expected overwrite without duplicates, got %v, %v", dups, err)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	ks := newDuplicatesTestKeystore(t)
+	now := time.Now().Unix()
+
+	for _, c := range []Credential{
+		{ID: "a", Provider: ProviderOpenAI, Token: "sk-1", CreatedAt: now},
+		{ID: "b", Provider: ProviderOpenRouter, Token: "sk-1", CreatedAt: now},
+		{ID: "c", Provider: ProviderOpenAI, Token: "sk-2", CreatedAt: now},
+		{ID: "d", Provider: ProviderAnthropic, Token: "sk-ant-3", CreatedAt: now},
+		{ID: "e", Provider: ProviderAnthropic, Token: "sk-ant-3", CreatedAt: now},
+		{ID: "f", Provider: ProviderAnthropic, Token: "sk-ant-3", CreatedAt: now},
+	} {
+		if err := ks.Store(c); err != nil {
+			t.Fatalf("Store(%s) failed: %v", c.ID, err)
+		}
+	}
+
+	groups, err := ks.FindDuplicates()
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 duplicate groups, got %+v", groups)
+	}
+	sizes := map[int]bool{}
+	for _, g := range groups {
+		sizes[len(g.IDs)] = true
+		if len(g.Providers) != len(g.IDs) {
+			t.Errorf("Providers and IDs out of step: %+v", g)
+		}
+	}
+	if !sizes[2] || !sizes[3] {
+		t.Errorf("Expected groups of 2 and 3, got %+v", groups)
+	}
+
+	// Rotating one copy away resolves its group
+	if err := ks.Rotate("b", "sk-new"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	groups, err = ks.FindDuplicates()
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 1 || len(groups[0].IDs) != 3 {
+		t.Errorf("Expected only the anthropic group after rotation, got %+v", groups)
+	}
+}
+
+func TestTokenHashBackfill(t *testing.T) {
+	ks := newDuplicatesTestKeystore(t)
+	now := time.Now().Unix()
+
+	for _, id := range []string{"old-1", "old-2"} {
+		if err := ks.Store(Credential{ID: id, Provider: ProviderOpenAI, Token: "sk-legacy", CreatedAt: now}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	// Simulate credentials written before token hashes existed
+	if _, err := ks.db.Exec("UPDATE credentials SET token_hash = NULL"); err != nil {
+		t.Fatalf("Failed to clear hashes: %v", err)
+	}
+	if err := ks.migrateTokenHashes(ks.db); err != nil {
+		t.Fatalf("migrateTokenHashes failed: %v", err)
+	}
+
+	var hash sql.NullString
+	if err := ks.db.QueryRow("SELECT token_hash FROM credentials WHERE id = 'old-1'").Scan(&hash); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if !hash.Valid || hash.String != tokenHash("sk-legacy") {
+		t.Errorf("Expected backfilled hash, got %+v", hash)
+	}
+
+	groups, err := ks.FindDuplicates()
+	if err != nil || len(groups) != 1 {
+		t.Errorf("Expected backfilled keys to be reported as duplicates, got %+v, %v", groups, err)
+	}
+}
@@ -0,0 +1,206 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	exportFormat  = "armorclaw-keystore-export"
+	exportVersion = 1
+	exportKDF     = "pbkdf2-sha512"
+
+	// MinExportPassphraseLength is the shortest passphrase accepted for bundles
+	MinExportPassphraseLength = 8
+)
+
+var (
+	ErrWeakPassphrase = errors.New("export passphrase is too short")
+	ErrInvalidBundle  = errors.New("invalid keystore export bundle")
+	ErrBundleDecrypt  = errors.New("failed to decrypt export bundle (wrong passphrase?)")
+	ErrBundleVersion  = errors.New("unsupported keystore export version")
+)
+
+// exportBundle is the portable on-disk form of an export. The credentials are
+// re-encrypted under a passphrase-derived key so the bundle can be imported on
+// a different machine, where the hardware-derived master key differs.
+type exportBundle struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+	CreatedAt  int64  `json:"created_at"`
+	Count      int    `json:"count"`
+}
+
+// Export writes every stored credential to w as a passphrase-encrypted bundle.
+// Expired credentials are included so the bundle is a complete copy; rotation
+// history is not.
+func (ks *Keystore) Export(w io.Writer, passphrase string) error {
+	if len(passphrase) < MinExportPassphraseLength {
+		return ErrWeakPassphrase
+	}
+
+	creds, err := ks.exportCredentials()
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("failed to serialize credentials: %w", err)
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	aead, err := chacha20poly1305.NewX(deriveExportKey(passphrase, salt, pbkdf2Iterations))
+	if err != nil {
+		return fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	bundle := exportBundle{
+		Format:     exportFormat,
+		Version:    exportVersion,
+		KDF:        exportKDF,
+		Iterations: pbkdf2Iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(exportFormat)),
+		CreatedAt:  time.Now().Unix(),
+		Count:      len(creds),
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bundle); err != nil {
+		return fmt.Errorf("failed to write export bundle: %w", err)
+	}
+	return nil
+}
+
+// Import reads a bundle produced by Export and stores each credential,
+// overwriting any existing credential with the same ID. The bundle is fully
+// decrypted and validated before anything is written.
+func (ks *Keystore) Import(r io.Reader, passphrase string) error {
+	var bundle exportBundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	if bundle.Format != exportFormat || bundle.KDF != exportKDF {
+		return ErrInvalidBundle
+	}
+	if bundle.Version != exportVersion {
+		return fmt.Errorf("%w: %d", ErrBundleVersion, bundle.Version)
+	}
+	if bundle.Iterations <= 0 || len(bundle.Nonce) != chacha20poly1305.NonceSizeX {
+		return ErrInvalidBundle
+	}
+
+	aead, err := chacha20poly1305.NewX(deriveExportKey(passphrase, bundle.Salt, bundle.Iterations))
+	if err != nil {
+		return fmt.Errorf("failed to create cipher: %w", err)
+	}
+	plaintext, err := aead.Open(nil, bundle.Nonce, bundle.Ciphertext, []byte(exportFormat))
+	if err != nil {
+		return ErrBundleDecrypt
+	}
+
+	var creds []Credential
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	for _, cred := range creds {
+		if cred.ID == "" || cred.Token == "" || !isValidProvider(cred.Provider) {
+			return fmt.Errorf("%w: credential %q is malformed", ErrInvalidBundle, cred.ID)
+		}
+	}
+
+	for _, cred := range creds {
+		if err := ks.Store(cred); err != nil {
+			return fmt.Errorf("failed to import credential %s: %w", cred.ID, err)
+		}
+	}
+	return nil
+}
+
+// exportCredentials reads and decrypts every credential, bypassing the expiry
+// and environment-variable handling in Retrieve
+func (ks *Keystore) exportCredentials() ([]Credential, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if !ks.isOpen {
+		return nil, errors.New("keystore is not open")
+	}
+
+	rows, err := ks.db.Query(`
+	SELECT id, provider, token_encrypted, nonce, base_url, display_name, created_at, expires_at, tags
+	FROM credentials ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	creds := []Credential{}
+	for rows.Next() {
+		var cred Credential
+		var encryptedToken, nonce []byte
+		var tagsJSON string
+		var baseURL sql.NullString
+
+		if err := rows.Scan(
+			&cred.ID,
+			&cred.Provider,
+			&encryptedToken,
+			&nonce,
+			&baseURL,
+			&cred.DisplayName,
+			&cred.CreatedAt,
+			&cred.ExpiresAt,
+			&tagsJSON,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read credential: %w", err)
+		}
+		cred.BaseURL = baseURL.String
+
+		token, err := ks.decrypt(encryptedToken, nonce)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt credential %s: %w", cred.ID, err)
+		}
+		cred.Token = string(token)
+
+		if tagsJSON != "[]" {
+			json.Unmarshal([]byte(tagsJSON), &cred.Tags)
+		}
+		creds = append(creds, cred)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	return creds, nil
+}
+
+// deriveExportKey stretches an export passphrase into a bundle encryption key
+func deriveExportKey(passphrase string, salt []byte, iterations int) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, iterations, keyLength, sha512.New)
+}
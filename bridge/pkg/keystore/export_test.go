@@ -0,0 +1,129 @@
+//go:build cgo
+
+package keystore
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+func newExportTestKeystore(t *testing.T, seed byte) *Keystore {
+	t.Helper()
+
+	masterKey := make([]byte, 32)
+	for i := range masterKey {
+		masterKey[i] = byte(i) + seed
+	}
+
+	ks, err := New(Config{
+		DBPath:    filepath.Join(t.TempDir(), "test.db"),
+		MasterKey: masterKey,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("Failed to open keystore: %v", err)
+	}
+	t.Cleanup(func() { ks.Close() })
+	return ks
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := newExportTestKeystore(t, 0)
+	now := time.Now().Unix()
+
+	creds := []Credential{
+		{ID: "openai-main", Provider: ProviderOpenAI, Token: "sk-openai", DisplayName: "Main", CreatedAt: now, Tags: []string{"prod"}},
+		{ID: "zhipu", Provider: ProviderOpenAI, Token: "zk-token", BaseURL: "https://open.bigmodel.cn/api/paas/v4", CreatedAt: now},
+		{ID: "old-anthropic", Provider: ProviderAnthropic, Token: "sk-ant-old", CreatedAt: now - 7200, ExpiresAt: now - 3600},
+	}
+	for _, c := range creds {
+		if err := src.Store(c); err != nil {
+			t.Fatalf("Store(%s) failed: %v", c.ID, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(&buf, "correct horse battery"); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "sk-openai") {
+		t.Fatal("Export bundle contains a plaintext token")
+	}
+
+	// A keystore with a different master key stands in for another machine
+	dst := newExportTestKeystore(t, 100)
+	if err := dst.Import(bytes.NewReader(buf.Bytes()), "correct horse battery"); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	got, err := dst.Retrieve("zhipu")
+	if err != nil {
+		t.Fatalf("Retrieve after import failed: %v", err)
+	}
+	if got.Token != "zk-token" || got.BaseURL != creds[1].BaseURL {
+		t.Errorf("Imported credential mismatch: %+v", got)
+	}
+
+	got, err = dst.Retrieve("openai-main")
+	if err != nil {
+		t.Fatalf("Retrieve after import failed: %v", err)
+	}
+	if got.DisplayName != "Main" || len(got.Tags) != 1 || got.Tags[0] != "prod" {
+		t.Errorf("Imported metadata mismatch: %+v", got)
+	}
+
+	// Expired credentials travel with the bundle and stay expired
+	if _, err := dst.Retrieve("old-anthropic"); !errors.Is(err, ErrKeyExpired) {
+		t.Errorf("Expected expired credential to be imported as expired, got %v", err)
+	}
+}
+
+func TestImportWrongPassphrase(t *testing.T) {
+	src := newExportTestKeystore(t, 0)
+	if err := src.Store(Credential{ID: "k", Provider: ProviderOpenAI, Token: "sk-x", CreatedAt: time.Now().Unix()}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.Export(&buf, "correct horse battery"); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	dst := newExportTestKeystore(t, 100)
+	if err := dst.Import(&buf, "wrong passphrase"); !errors.Is(err, ErrBundleDecrypt) {
+		t.Fatalf("Expected ErrBundleDecrypt, got %v", err)
+	}
+	if keys, _ := dst.List(""); len(keys) != 0 {
+		t.Errorf("Expected nothing imported, got %d keys", len(keys))
+	}
+}
+
+func TestExportRejectsWeakPassphrase(t *testing.T) {
+	ks := newExportTestKeystore(t, 0)
+
+	var buf bytes.Buffer
+	if err := ks.Export(&buf, "short"); !errors.Is(err, ErrWeakPassphrase) {
+		t.Fatalf("Expected ErrWeakPassphrase, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Error("Expected nothing written for a rejected export")
+	}
+}
+
+func TestImportRejectsInvalidBundle(t *testing.T) {
+	ks := newExportTestKeystore(t, 0)
+
+	for _, input := range []string{"not json", `{"format":"other","version":1}`} {
+		if err := ks.Import(strings.NewReader(input), "correct horse battery"); !errors.Is(err, ErrInvalidBundle) {
+			t.Errorf("Import(%q): expected ErrInvalidBundle, got %v", input, err)
+		}
+	}
+}
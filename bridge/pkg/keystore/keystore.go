@@ -11,7 +11,9 @@ package keystore
 import (
 	"bufio"
 	"context"
+	"crypto/hmac"
 	cryptorand "crypto/rand"
+	"crypto/sha256"
 	"crypto/sha512"
 	"database/sql"
 	"encoding/base64"
@@ -25,6 +27,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -85,6 +88,8 @@ type Credential struct {
 	CreatedAt   int64    `json:"created_at"`
 	ExpiresAt   int64    `json:"expires_at,omitempty"` // Unix timestamp
 	Tags        []string `json:"tags,omitempty"`
+	Version     int      `json:"version,omitempty"`    // Incremented by Rotate
+	RotatedAt   int64    `json:"rotated_at,omitempty"` // Unix timestamp of the last rotation
 }
 
 // KeyInfo is the public information about a stored key
@@ -96,6 +101,8 @@ type KeyInfo struct {
 	CreatedAt   int64    `json:"created_at"`
 	ExpiresAt   int64    `json:"expires_at,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+	Version     int      `json:"version,omitempty"`
+	RotatedAt   int64    `json:"rotated_at,omitempty"`
 }
 
 // Keystore manages encrypted credential storage
@@ -107,12 +114,17 @@ type Keystore struct {
 	salt        []byte
 	isOpen      bool
 	auditLogger *audit.CriticalOperationLogger
+
+	rotationGrace time.Duration
+	expiryGrace   time.Duration
 }
 
 // Config holds keystore configuration
 type Config struct {
-	DBPath    string // Path to the SQLite database file
-	MasterKey []byte // Optional master key (if nil, will derive from hardware)
+	DBPath        string        // Path to the SQLite database file
+	MasterKey     []byte        // Optional master key (if nil, will derive from hardware)
+	RotationGrace time.Duration // How long a rotated-out token stays retrievable (default 24h)
+	ExpiryGrace   time.Duration // Tolerance past ExpiresAt for clock skew (default 0, strict)
 }
 
 // New creates a new Keystore instance
@@ -126,8 +138,14 @@ func New(cfg Config) (*Keystore, error) {
 		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
 	}
 
+	if cfg.RotationGrace <= 0 {
+		cfg.RotationGrace = DefaultRotationGrace
+	}
+
 	ks := &Keystore{
-		dbPath: cfg.DBPath,
+		dbPath:        cfg.DBPath,
+		rotationGrace: cfg.RotationGrace,
+		expiryGrace:   cfg.ExpiryGrace,
 	}
 
 	// Load or generate salt (persists across reboots)
@@ -575,7 +593,20 @@ func (ks *Keystore) initSchema(db *sql.DB) error {
 		display_name TEXT NOT NULL,
 		created_at INTEGER NOT NULL,
 		expires_at INTEGER,
-		tags TEXT
+		tags TEXT,
+		version INTEGER NOT NULL DEFAULT 1,
+		rotated_at INTEGER
+	);
+
+	CREATE TABLE IF NOT EXISTS credential_versions (
+		credential_id TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		token_encrypted BLOB NOT NULL,
+		nonce BLOB NOT NULL,
+		issued_at INTEGER NOT NULL,
+		retired_at INTEGER NOT NULL,
+		valid_until INTEGER NOT NULL,
+		PRIMARY KEY (credential_id, version)
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_provider ON credentials(provider);
@@ -633,8 +664,14 @@ func (ks *Keystore) initSchema(db *sql.DB) error {
 	INSERT OR IGNORE INTO metadata (key, value) VALUES ('created_at', ?);
 	`
 
-	_, err := db.Exec(query, time.Now().Unix())
-	return err
+	if _, err := db.Exec(query, time.Now().Unix()); err != nil {
+		return err
+	}
+
+	if err := migrateCredentialVersioning(db); err != nil {
+		return err
+	}
+	return ks.migrateTokenHashes(db)
 }
 
 // WipeAllData removes all data from the keystore (secrets, devices, profiles, hardening state)
@@ -663,7 +700,7 @@ func (ks *Keystore) WipeAllData() error {
 	// Delete from all data tables
 	// We clear: secrets, profiles, devices, matrix_refresh_tokens, hardening_state
 	// Note: This is a destructive system-wide operation for admin reset
-	tables := []string{"credentials", "user_profiles", "hardware_binding", "matrix_refresh_tokens", "hardening_state"}
+	tables := []string{"credentials", "credential_versions", "user_profiles", "hardware_binding", "matrix_refresh_tokens", "hardening_state"}
 
 	for _, table := range tables {
 		_, err := ks.db.Exec("DELETE FROM " + table)
@@ -710,28 +747,37 @@ func (ks *Keystore) GetDBPath() string {
 
 // Store stores an encrypted credential
 func (ks *Keystore) Store(cred Credential) error {
+	_, err := ks.StoreWithDuplicateCheck(cred)
+	return err
+}
+
+// StoreWithDuplicateCheck stores a credential like Store and also returns the
+// IDs of other credentials holding the same token. A duplicate does not block
+// the store; it is logged as a warning and left for the caller to surface.
+func (ks *Keystore) StoreWithDuplicateCheck(cred Credential) ([]string, error) {
 	ks.mu.Lock()
 	defer ks.mu.Unlock()
 
 	if !ks.isOpen {
-		return errors.New("keystore is not open")
+		return nil, errors.New("keystore is not open")
 	}
 
 	// Validate provider
 	if !isValidProvider(cred.Provider) {
-		return ErrInvalidProvider
+		return nil, ErrInvalidProvider
 	}
 
 	// Validate token format
 	if cred.Token == "" {
-		return ErrInvalidCredential
+		return nil, ErrInvalidCredential
 	}
 
 	// Encrypt the token using XChaCha20-Poly1305
 	encrypted, nonce, err := ks.encrypt([]byte(cred.Token))
 	if err != nil {
-		return fmt.Errorf("encryption failed: %w", err)
+		return nil, fmt.Errorf("encryption failed: %w", err)
 	}
+	hash := tokenHash(cred.Token)
 
 	// Serialize tags
 	tagsJSON := "[]"
@@ -741,11 +787,14 @@ func (ks *Keystore) Store(cred Credential) error {
 		}
 	}
 
-	// Insert into database
+	// Insert into database. Overwriting an existing ID keeps its version
+	// counter so it stays consistent with any rotation history.
 	query := `
 	INSERT OR REPLACE INTO credentials
-	(id, provider, token_encrypted, nonce, base_url, display_name, created_at, expires_at, tags)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	(id, provider, token_encrypted, nonce, base_url, display_name, created_at, expires_at, tags, token_hash, version, rotated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
+		COALESCE((SELECT version FROM credentials WHERE id = ?), 1),
+		(SELECT rotated_at FROM credentials WHERE id = ?))
 	`
 
 	_, err = ks.db.Exec(query,
@@ -758,9 +807,25 @@ func (ks *Keystore) Store(cred Credential) error {
 		cred.CreatedAt,
 		cred.ExpiresAt,
 		tagsJSON,
+		hash,
+		cred.ID,
+		cred.ID,
 	)
+	if err != nil {
+		return nil, err
+	}
 
-	return err
+	duplicates, err := ks.duplicatesOf(cred.ID, hash)
+	if err != nil {
+		// The credential is stored; a failed lookup only loses the warning
+		return nil, nil
+	}
+	if len(duplicates) > 0 {
+		logger.Global().Warn("credential token already stored under another id",
+			"id", cred.ID, "duplicate_of", duplicates)
+	}
+
+	return duplicates, nil
 }
 
 // Retrieve retrieves and decrypts a credential
@@ -780,7 +845,7 @@ func (ks *Keystore) Retrieve(id string) (*Credential, error) {
 	}
 
 	query := `
-	SELECT id, provider, token_encrypted, nonce, base_url, display_name, created_at, expires_at, tags
+	SELECT id, provider, token_encrypted, nonce, base_url, display_name, created_at, expires_at, tags, version, rotated_at
 	FROM credentials WHERE id = ?
 	`
 
@@ -790,6 +855,7 @@ func (ks *Keystore) Retrieve(id string) (*Credential, error) {
 	var encryptedToken, nonce []byte
 	var tagsJSON string
 	var baseURL sql.NullString
+	var rotatedAt sql.NullInt64
 
 	err := row.Scan(
 		&cred.ID,
@@ -801,11 +867,14 @@ func (ks *Keystore) Retrieve(id string) (*Credential, error) {
 		&cred.CreatedAt,
 		&cred.ExpiresAt,
 		&tagsJSON,
+		&cred.Version,
+		&rotatedAt,
 	)
 
 	if baseURL.Valid {
 		cred.BaseURL = baseURL.String
 	}
+	cred.RotatedAt = rotatedAt.Int64
 
 	if err == sql.ErrNoRows {
 		// Log failed access to audit
@@ -821,9 +890,9 @@ func (ks *Keystore) Retrieve(id string) (*Credential, error) {
 		return nil, fmt.Errorf("database query failed: %w", err)
 	}
 
-	// Check expiration
+	// Check expiration, allowing the configured grace for clock skew
 	if cred.ExpiresAt > 0 {
-		if time.Now().Unix() > cred.ExpiresAt {
+		if time.Now().Unix() > cred.ExpiresAt+int64(ks.expiryGrace/time.Second) {
 			if ks.auditLogger != nil {
 				ks.auditLogger.LogKeyAccess(context.Background(), id, "system", "retrieve", false)
 			}
@@ -892,7 +961,7 @@ func (ks *Keystore) List(provider Provider) ([]KeyInfo, error) {
 	}
 
 	query := `
-	SELECT id, provider, base_url, display_name, created_at, expires_at, tags
+	SELECT id, provider, base_url, display_name, created_at, expires_at, tags, version, rotated_at
 	FROM credentials
 	`
 
@@ -913,6 +982,7 @@ func (ks *Keystore) List(provider Provider) ([]KeyInfo, error) {
 		var info KeyInfo
 		var tagsJSON string
 		var baseURL sql.NullString
+		var rotatedAt sql.NullInt64
 
 		err := rows.Scan(
 			&info.ID,
@@ -922,6 +992,8 @@ func (ks *Keystore) List(provider Provider) ([]KeyInfo, error) {
 			&info.CreatedAt,
 			&info.ExpiresAt,
 			&tagsJSON,
+			&info.Version,
+			&rotatedAt,
 		)
 		if err != nil {
 			continue
@@ -930,6 +1002,7 @@ func (ks *Keystore) List(provider Provider) ([]KeyInfo, error) {
 		if baseURL.Valid {
 			info.BaseURL = baseURL.String
 		}
+		info.RotatedAt = rotatedAt.Int64
 
 		if tagsJSON != "[]" {
 			json.Unmarshal([]byte(tagsJSON), &info.Tags)
@@ -941,6 +1014,29 @@ func (ks *Keystore) List(provider Provider) ([]KeyInfo, error) {
 	return keys, nil
 }
 
+// ListByTag returns stored key information for credentials tagged with tag
+func (ks *Keystore) ListByTag(tag string) ([]KeyInfo, error) {
+	keys, err := ks.List("")
+	if err != nil {
+		return nil, err
+	}
+	return filterKeysByTag(keys, tag), nil
+}
+
+// filterKeysByTag keeps the keys whose Tags contain tag (exact match)
+func filterKeysByTag(keys []KeyInfo, tag string) []KeyInfo {
+	var tagged []KeyInfo
+	for _, key := range keys {
+		for _, t := range key.Tags {
+			if t == tag {
+				tagged = append(tagged, key)
+				break
+			}
+		}
+	}
+	return tagged
+}
+
 // Delete removes a credential from the keystore
 func (ks *Keystore) Delete(id string) error {
 	ks.mu.Lock()
@@ -951,6 +1047,9 @@ func (ks *Keystore) Delete(id string) error {
 	}
 
 	_, err := ks.db.Exec("DELETE FROM credentials WHERE id = ?", id)
+	if err == nil {
+		_, err = ks.db.Exec("DELETE FROM credential_versions WHERE credential_id = ?", id)
+	}
 
 	// Log deletion to audit
 	if ks.auditLogger != nil {
@@ -1022,6 +1121,15 @@ func (ks *Keystore) Decrypt(encrypted, nonce []byte) ([]byte, error) {
 	return ks.decrypt(encrypted, nonce)
 }
 
+// DeriveKey returns a 32-byte key for the named purpose, derived from the
+// master key with HMAC-SHA256. The same purpose always yields the same key
+// for this keystore, and the master key cannot be recovered from it.
+func (ks *Keystore) DeriveKey(purpose string) []byte {
+	mac := hmac.New(sha256.New, ks.masterKey)
+	mac.Write([]byte("armorclaw/" + purpose))
+	return mac.Sum(nil)
+}
+
 // isValidProvider checks if a provider is valid
 func isValidProvider(p Provider) bool {
 	switch p {
@@ -1288,6 +1396,13 @@ func (ks *Keystore) StoreProfile(id, profileName, profileType string, data []byt
 		return errors.New("profile name is required")
 	}
 
+	// Fields the schema flags as sensitive are encrypted individually as
+	// well, so each one is decrypted (and audited) on its own
+	data, err := ks.sealProfileFields(id, data, sensitiveProfileFields(fieldSchema))
+	if err != nil {
+		return fmt.Errorf("field encryption failed: %w", err)
+	}
+
 	// Encrypt the profile data using XChaCha20-Poly1305
 	encrypted, nonce, err := ks.encrypt(data)
 	if err != nil {
@@ -1339,6 +1454,49 @@ func (ks *Keystore) StoreProfile(id, profileName, profileType string, data []byt
 
 // RetrieveProfile retrieves and decrypts a user profile
 func (ks *Keystore) RetrieveProfile(id string) (*UserProfileData, error) {
+	return ks.retrieveProfile(id, nil)
+}
+
+// RetrieveProfileFields retrieves a user profile, decrypting only the named
+// sensitive fields. Other sensitive fields are left out of the data.
+func (ks *Keystore) RetrieveProfileFields(id string, sensitiveFields []string) (*UserProfileData, error) {
+	allowed := make(map[string]bool, len(sensitiveFields))
+	for _, f := range sensitiveFields {
+		allowed[f] = true
+	}
+	return ks.retrieveProfile(id, func(field string) bool { return allowed[field] })
+}
+
+// ProfileSensitiveFields returns the fields a profile's schema flags as
+// sensitive, without decrypting the profile
+func (ks *Keystore) ProfileSensitiveFields(id string) ([]string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if !ks.isOpen {
+		return nil, errors.New("keystore is not open")
+	}
+
+	var fieldSchema string
+	err := ks.db.QueryRow("SELECT field_schema FROM user_profiles WHERE id = ?", id).Scan(&fieldSchema)
+	if err == sql.ErrNoRows {
+		return nil, ErrProfileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	fields := make([]string, 0)
+	for field := range sensitiveProfileFields(fieldSchema) {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// retrieveProfile decrypts a profile and the sealed fields open accepts;
+// a nil open decrypts every field
+func (ks *Keystore) retrieveProfile(id string, open func(field string) bool) (*UserProfileData, error) {
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
 
@@ -1391,6 +1549,13 @@ func (ks *Keystore) RetrieveProfile(id string) (*UserProfileData, error) {
 		}
 		return nil, fmt.Errorf("decryption failed: %w", err)
 	}
+	decrypted, decryptedFields, err := ks.openProfileFields(id, decrypted, open)
+	if err != nil {
+		if ks.auditLogger != nil {
+			ks.auditLogger.LogProfileAccess(context.Background(), id, "retrieve", false)
+		}
+		return nil, err
+	}
 	profile.Data = decrypted
 
 	if lastAccessed.Valid {
@@ -1406,9 +1571,9 @@ func (ks *Keystore) RetrieveProfile(id string) (*UserProfileData, error) {
 		}
 	}()
 
-	// Log successful access
+	// Log successful access, naming the sensitive fields that were decrypted
 	if ks.auditLogger != nil {
-		ks.auditLogger.LogProfileAccess(context.Background(), id, "retrieve", true)
+		ks.auditLogger.LogProfileRead(context.Background(), id, decryptedFields)
 	}
 
 	return &profile, nil
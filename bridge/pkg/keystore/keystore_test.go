@@ -74,6 +74,36 @@ func TestKeystoreEncryption(t *testing.T) {
 	}
 }
 
+// TestDeriveKeyPurpose tests that derived keys are stable per purpose and master key
+func TestDeriveKeyPurpose(t *testing.T) {
+	newKeystore := func(fill byte) *Keystore {
+		masterKey := make([]byte, 32)
+		for i := range masterKey {
+			masterKey[i] = fill
+		}
+		ks, err := New(Config{DBPath: filepath.Join(t.TempDir(), "test.db"), MasterKey: masterKey})
+		if err != nil {
+			t.Fatalf("Failed to create keystore: %v", err)
+		}
+		return ks
+	}
+
+	ks := newKeystore(1)
+	key := ks.DeriveKey("audit-chain")
+	if len(key) != 32 {
+		t.Fatalf("DeriveKey length = %d, want 32", len(key))
+	}
+	if string(ks.DeriveKey("audit-chain")) != string(key) {
+		t.Error("DeriveKey is not stable for the same purpose")
+	}
+	if string(ks.DeriveKey("other")) == string(key) {
+		t.Error("DeriveKey returned the same key for different purposes")
+	}
+	if string(newKeystore(2).DeriveKey("audit-chain")) == string(key) {
+		t.Error("DeriveKey returned the same key for different master keys")
+	}
+}
+
 // TestStoreAndRetrieve tests storing and retrieving credentials
 func TestStoreAndRetrieve(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -211,6 +241,59 @@ func TestListKeys(t *testing.T) {
 	}
 }
 
+// TestListByTag tests filtering credentials by tag
+func TestListByTag(t *testing.T) {
+	masterKey := make([]byte, 32)
+	for i := range masterKey {
+		masterKey[i] = byte(i)
+	}
+
+	ks, err := New(Config{
+		DBPath:    filepath.Join(t.TempDir(), "test.db"),
+		MasterKey: masterKey,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("Failed to open keystore: %v", err)
+	}
+	defer ks.Close()
+
+	credentials := []Credential{
+		{ID: "prod-openai", Provider: ProviderOpenAI, Token: "sk-1", CreatedAt: time.Now().Unix(), Tags: []string{"production"}},
+		{ID: "prod-anthropic", Provider: ProviderAnthropic, Token: "sk-ant-2", CreatedAt: time.Now().Unix(), Tags: []string{"setup-wizard", "production"}},
+		{ID: "scratch", Provider: ProviderOpenAI, Token: "sk-3", CreatedAt: time.Now().Unix(), Tags: []string{"production-like"}},
+		{ID: "untagged", Provider: ProviderOpenAI, Token: "sk-4", CreatedAt: time.Now().Unix()},
+	}
+	for _, cred := range credentials {
+		if err := ks.Store(cred); err != nil {
+			t.Fatalf("Failed to store credential %s: %v", cred.ID, err)
+		}
+	}
+
+	keys, err := ks.ListByTag("production")
+	if err != nil {
+		t.Fatalf("ListByTag failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 production keys, got %d", len(keys))
+	}
+	for _, key := range keys {
+		if key.ID != "prod-openai" && key.ID != "prod-anthropic" {
+			t.Errorf("Unexpected key in production listing: %s", key.ID)
+		}
+	}
+
+	keys, err = ks.ListByTag("missing")
+	if err != nil {
+		t.Fatalf("ListByTag failed: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Expected no keys for unknown tag, got %d", len(keys))
+	}
+}
+
 // TestDelete tests credential deletion
 func TestDelete(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -309,6 +392,47 @@ func TestExpiredKey(t *testing.T) {
 	}
 }
 
+// TestExpiredKeyGrace tests the clock-skew grace window on expiry
+func TestExpiredKeyGrace(t *testing.T) {
+	masterKey := make([]byte, 32)
+	for i := range masterKey {
+		masterKey[i] = byte(i)
+	}
+
+	ks, err := New(Config{
+		DBPath:      filepath.Join(t.TempDir(), "test.db"),
+		MasterKey:   masterKey,
+		ExpiryGrace: 2 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("Failed to open keystore: %v", err)
+	}
+	defer ks.Close()
+
+	creds := []Credential{
+		{ID: "just-expired", ExpiresAt: time.Now().Add(-30 * time.Second).Unix()},
+		{ID: "long-expired", ExpiresAt: time.Now().Add(-5 * time.Minute).Unix()},
+	}
+	for _, cred := range creds {
+		cred.Provider = ProviderOpenAI
+		cred.Token = "sk-test"
+		cred.CreatedAt = time.Now().Add(-24 * time.Hour).Unix()
+		if err := ks.Store(cred); err != nil {
+			t.Fatalf("Failed to store credential: %v", err)
+		}
+	}
+
+	if _, err := ks.Retrieve("just-expired"); err != nil {
+		t.Errorf("Key within grace window should be retrievable, got %v", err)
+	}
+	if _, err := ks.Retrieve("long-expired"); err != ErrKeyExpired {
+		t.Errorf("Expected ErrKeyExpired past the grace window, got %v", err)
+	}
+}
+
 // TestHardeningTableCreated verifies the hardening_state table exists with correct schema
 func TestHardeningTableCreated(t *testing.T) {
 	tmpDir := t.TempDir()
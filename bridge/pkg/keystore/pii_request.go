@@ -41,8 +41,15 @@ var (
 	ErrRequestAlreadyClosed = errors.New("PII request already closed")
 	ErrNoApprovedFields     = errors.New("no fields were approved")
 	ErrAgentNotPaused       = errors.New("agent is not in paused state")
+	ErrRequestPending       = errors.New("PII request is awaiting approval")
+	ErrRequestDenied        = errors.New("PII request was denied")
+	ErrApprovalExpired      = errors.New("PII approval has expired")
+	ErrProfileMismatch      = errors.New("PII request is for a different profile")
 )
 
+// DefaultApprovalValidity is how long an approved request grants access
+const DefaultApprovalValidity = 15 * time.Minute
+
 // PIIRequest represents a pending request for PII access
 type PIIRequest struct {
 	// Unique identifier for this request
@@ -146,6 +153,9 @@ type PIIRequestManager struct {
 	log      *logger.Logger
 	counter  int64 // Counter for unique ID generation
 
+	// approvalValidity is how long an approval can be used after it is given
+	approvalValidity time.Duration
+
 	// Callbacks for integration
 	onRequestCreated  func(ctx context.Context, req *PIIRequest) error
 	onRequestApproved func(ctx context.Context, req *PIIRequest) error
@@ -157,6 +167,9 @@ type PIIRequestManager struct {
 type PIIRequestManagerConfig struct {
 	// Default TTL for requests
 	DefaultTTL time.Duration
+	// ApprovalValidity is how long an approved request grants access
+	// (default: DefaultApprovalValidity)
+	ApprovalValidity time.Duration
 	// Logger for audit trail
 	Logger *logger.Logger
 }
@@ -172,9 +185,14 @@ func NewPIIRequestManager(cfg PIIRequestManagerConfig) *PIIRequestManager {
 		log = logger.Global().WithComponent("pii_request")
 	}
 
+	if cfg.ApprovalValidity <= 0 {
+		cfg.ApprovalValidity = DefaultApprovalValidity
+	}
+
 	return &PIIRequestManager{
-		requests: make(map[string]*PIIRequest),
-		log:      log,
+		requests:         make(map[string]*PIIRequest),
+		log:              log,
+		approvalValidity: cfg.ApprovalValidity,
 	}
 }
 
@@ -314,6 +332,40 @@ func (m *PIIRequestManager) ApproveRequest(ctx context.Context,
 	return req, nil
 }
 
+// AuthorizeProfileAccess checks that a request grants access to profileID
+// and returns the fields it approved. An approval can be used repeatedly
+// until the approval validity window has passed.
+func (m *PIIRequestManager) AuthorizeProfileAccess(requestID, profileID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	req, exists := m.requests[requestID]
+	if !exists {
+		return nil, ErrRequestNotFound
+	}
+	if req.ProfileID != profileID {
+		return nil, ErrProfileMismatch
+	}
+
+	switch req.Status {
+	case StatusPending:
+		if req.IsExpired() {
+			req.Status = StatusExpired
+			return nil, ErrRequestExpired
+		}
+		return nil, ErrRequestPending
+	case StatusApproved, StatusFulfilled:
+		if req.ApprovedAt == nil || time.Since(*req.ApprovedAt) > m.approvalValidity {
+			return nil, ErrApprovalExpired
+		}
+		return append([]string(nil), req.ApprovedFields...), nil
+	case StatusDenied:
+		return nil, ErrRequestDenied
+	default:
+		return nil, ErrRequestAlreadyClosed
+	}
+}
+
 // DenyRequest denies a PII request
 func (m *PIIRequestManager) DenyRequest(ctx context.Context,
 	requestID string,
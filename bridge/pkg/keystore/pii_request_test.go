@@ -2,6 +2,7 @@ package keystore
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -133,6 +134,45 @@ func TestApprovePIIRequest(t *testing.T) {
 	}
 }
 
+// TestAuthorizeProfileAccess tests gating profile reads on approval
+func TestAuthorizeProfileAccess(t *testing.T) {
+	mgr := NewPIIRequestManager(PIIRequestManagerConfig{ApprovalValidity: time.Minute})
+	ctx := context.Background()
+	fields := []PIIFieldRequest{{Key: "ssn", DisplayName: "SSN", Sensitive: true}}
+
+	created, _ := mgr.CreateRequest(ctx, "agent-001", "skill-001", "Test Skill", "profile-001", fields, "Testing", "", 5*time.Minute)
+	if _, err := mgr.AuthorizeProfileAccess(created.ID, "profile-001"); !errors.Is(err, ErrRequestPending) {
+		t.Errorf("pending request: got %v, want ErrRequestPending", err)
+	}
+
+	mgr.ApproveRequest(ctx, created.ID, "user-001", []string{"ssn"})
+	if _, err := mgr.AuthorizeProfileAccess(created.ID, "profile-002"); !errors.Is(err, ErrProfileMismatch) {
+		t.Errorf("other profile: got %v, want ErrProfileMismatch", err)
+	}
+	// An approval can be used more than once within its validity window
+	for i := 0; i < 2; i++ {
+		granted, err := mgr.AuthorizeProfileAccess(created.ID, "profile-001")
+		if err != nil || len(granted) != 1 || granted[0] != "ssn" {
+			t.Errorf("approved request: got %v, %v; want [ssn]", granted, err)
+		}
+	}
+
+	past := time.Now().Add(-2 * time.Minute)
+	created.ApprovedAt = &past
+	if _, err := mgr.AuthorizeProfileAccess(created.ID, "profile-001"); !errors.Is(err, ErrApprovalExpired) {
+		t.Errorf("stale approval: got %v, want ErrApprovalExpired", err)
+	}
+
+	denied, _ := mgr.CreateRequest(ctx, "agent-001", "skill-001", "Test Skill", "profile-001", fields, "Testing", "", 5*time.Minute)
+	mgr.DenyRequest(ctx, denied.ID, "user-001", "no")
+	if _, err := mgr.AuthorizeProfileAccess(denied.ID, "profile-001"); !errors.Is(err, ErrRequestDenied) {
+		t.Errorf("denied request: got %v, want ErrRequestDenied", err)
+	}
+	if _, err := mgr.AuthorizeProfileAccess("missing", "profile-001"); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("unknown request: got %v, want ErrRequestNotFound", err)
+	}
+}
+
 // TestDenyPIIRequest tests request denial
 func TestDenyPIIRequest(t *testing.T) {
 	mgr := NewPIIRequestManager(PIIRequestManagerConfig{})
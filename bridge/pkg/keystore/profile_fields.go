@@ -0,0 +1,155 @@
+package keystore
+
+import (
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// sealedFieldPrefix marks a profile field value that is encrypted on its own
+const sealedFieldPrefix = "armorclaw:sealed:v1:"
+
+// profileFieldKeyInfo separates the field key from other keys derived from
+// the master key
+const profileFieldKeyInfo = "armorclaw-profile-field-v1"
+
+// sensitiveProfileFields returns the keys the field schema flags as
+// sensitive. Schemas that are not in the pii.ProfileFieldSchema format flag
+// nothing.
+func sensitiveProfileFields(fieldSchema string) map[string]bool {
+	var schema struct {
+		Fields []struct {
+			Key       string `json:"key"`
+			Sensitive bool   `json:"sensitive"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(fieldSchema), &schema); err != nil {
+		return nil
+	}
+
+	sensitive := make(map[string]bool)
+	for _, f := range schema.Fields {
+		if f.Sensitive {
+			sensitive[f.Key] = true
+		}
+	}
+	return sensitive
+}
+
+// profileFieldAEAD returns the cipher for individually sealed fields, keyed
+// separately from the whole-profile encryption
+func (ks *Keystore) profileFieldAEAD() (cipher.AEAD, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ks.masterKey, nil, []byte(profileFieldKeyInfo)), key); err != nil {
+		return nil, fmt.Errorf("failed to derive field key: %w", err)
+	}
+	return chacha20poly1305.NewX(key)
+}
+
+// profileFieldAD binds a sealed value to its profile and field, so sealed
+// values cannot be swapped between fields or profiles
+func profileFieldAD(profileID, field string) []byte {
+	return []byte(profileID + "\x00" + field)
+}
+
+// sealProfileFields encrypts each sensitive string field of a JSON object
+// on its own. Data that is not a JSON object is returned unchanged.
+func (ks *Keystore) sealProfileFields(profileID string, data []byte, sensitive map[string]bool) ([]byte, error) {
+	if len(sensitive) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data, nil
+	}
+
+	aead, err := ks.profileFieldAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	for key, raw := range fields {
+		if !sensitive[key] {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil || value == "" || strings.HasPrefix(value, sealedFieldPrefix) {
+			continue
+		}
+
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(cryptorand.Reader, nonce); err != nil {
+			return nil, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		sealed := aead.Seal(nonce, nonce, []byte(value), profileFieldAD(profileID, key))
+
+		fields[key], err = json.Marshal(sealedFieldPrefix + base64.StdEncoding.EncodeToString(sealed))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// openProfileFields decrypts the individually sealed fields open accepts
+// (all of them when open is nil) and drops the rest. It returns the data
+// together with the sorted names of the fields it decrypted.
+func (ks *Keystore) openProfileFields(profileID string, data []byte, open func(field string) bool) ([]byte, []string, error) {
+	if !strings.Contains(string(data), sealedFieldPrefix) {
+		return data, nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data, nil, nil
+	}
+
+	aead, err := ks.profileFieldAEAD()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var opened []string
+	for key, raw := range fields {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil || !strings.HasPrefix(value, sealedFieldPrefix) {
+			continue
+		}
+		if open != nil && !open(key) {
+			delete(fields, key)
+			continue
+		}
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedFieldPrefix))
+		if err != nil || len(sealed) < aead.NonceSize() {
+			return nil, nil, fmt.Errorf("field %s is corrupted", key)
+		}
+		plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], profileFieldAD(profileID, key))
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %s decryption failed (data may be tampered or corrupted): %w", key, err)
+		}
+
+		if fields[key], err = json.Marshal(string(plaintext)); err != nil {
+			return nil, nil, err
+		}
+		opened = append(opened, key)
+	}
+	sort.Strings(opened)
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, opened, nil
+}
@@ -0,0 +1,138 @@
+package keystore
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/armorclaw/bridge/pkg/audit"
+)
+
+const testProfileSchema = `{"profile_type":"personal","fields":[
+	{"key":"email","label":"Email","type":"email","sensitive":false},
+	{"key":"ssn","label":"SSN","type":"text","sensitive":true},
+	{"key":"date_of_birth","label":"Date of Birth","type":"date","sensitive":true}
+]}`
+
+// storedProfileData returns a profile's data with only the whole-profile
+// encryption removed, as a field-level reader would see it
+func storedProfileData(t *testing.T, ks *Keystore, id string) map[string]string {
+	t.Helper()
+	var encrypted, nonce []byte
+	if err := ks.db.QueryRow("SELECT data_encrypted, data_nonce FROM user_profiles WHERE id = ?", id).Scan(&encrypted, &nonce); err != nil {
+		t.Fatalf("read stored profile: %v", err)
+	}
+	data, err := ks.decrypt(encrypted, nonce)
+	if err != nil {
+		t.Fatalf("decrypt stored profile: %v", err)
+	}
+	var fields map[string]string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("parse stored profile: %v", err)
+	}
+	return fields
+}
+
+func TestProfileSensitiveFieldsEncryptedIndividually(t *testing.T) {
+	ks := createTestKeystore(t)
+	defer ks.Close()
+
+	auditLog := audit.NewTamperEvidentLog(audit.TamperEvidentConfig{Enabled: true, MaxEntries: 100})
+	ks.SetAuditLogger(audit.NewCriticalOperationLogger(auditLog))
+
+	data := []byte(`{"email":"john@example.com","ssn":"123-45-6789","date_of_birth":""}`)
+	if err := ks.StoreProfile("profile-001", "Personal", "personal", data, testProfileSchema, false); err != nil {
+		t.Fatalf("StoreProfile() error = %v", err)
+	}
+
+	stored := storedProfileData(t, ks, "profile-001")
+	if !strings.HasPrefix(stored["ssn"], sealedFieldPrefix) || strings.Contains(stored["ssn"], "6789") {
+		t.Errorf("stored ssn = %q, want it sealed", stored["ssn"])
+	}
+	if stored["email"] != "john@example.com" || stored["date_of_birth"] != "" {
+		t.Errorf("stored non-sensitive or empty fields changed: %v", stored)
+	}
+
+	profile, err := ks.RetrieveProfile("profile-001")
+	if err != nil {
+		t.Fatalf("RetrieveProfile() error = %v", err)
+	}
+	var got map[string]string
+	json.Unmarshal(profile.Data, &got)
+	if got["ssn"] != "123-45-6789" || got["email"] != "john@example.com" {
+		t.Errorf("retrieved data = %v, want the original values", got)
+	}
+
+	reads := auditLog.GetEntries(audit.EntryFilter{EventType: "pii_profile_read", ResourceID: "profile-001"})
+	if len(reads) != 1 {
+		t.Fatalf("pii_profile_read entries = %d, want 1", len(reads))
+	}
+	if fields := reads[0].Details["decrypted_fields"]; !reflect.DeepEqual(fields, []string{"ssn"}) {
+		t.Errorf("decrypted_fields = %v, want [ssn]", fields)
+	}
+}
+
+func TestProfileSealedFieldsBoundToField(t *testing.T) {
+	ks := createTestKeystore(t)
+	defer ks.Close()
+
+	data := []byte(`{"ssn":"123-45-6789","date_of_birth":"1990-01-01"}`)
+	if err := ks.StoreProfile("profile-001", "Personal", "personal", data, testProfileSchema, false); err != nil {
+		t.Fatalf("StoreProfile() error = %v", err)
+	}
+
+	// Swap the two sealed values and store the result back
+	stored := storedProfileData(t, ks, "profile-001")
+	stored["ssn"], stored["date_of_birth"] = stored["date_of_birth"], stored["ssn"]
+	swapped, _ := json.Marshal(stored)
+	encrypted, nonce, err := ks.encrypt(swapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.db.Exec("UPDATE user_profiles SET data_encrypted = ?, data_nonce = ? WHERE id = ?", encrypted, nonce, "profile-001"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ks.RetrieveProfile("profile-001"); err == nil {
+		t.Error("RetrieveProfile() accepted sealed values swapped between fields")
+	}
+}
+
+func TestProfileWithoutSchemaStoredWhole(t *testing.T) {
+	ks := createTestKeystore(t)
+	defer ks.Close()
+
+	data := []byte(`{"name":"John","ssn":"123-45-6789"}`)
+	if err := ks.StoreProfile("profile-001", "Personal", "personal", data, `{"name":"text"}`, false); err != nil {
+		t.Fatalf("StoreProfile() error = %v", err)
+	}
+	if stored := storedProfileData(t, ks, "profile-001"); stored["ssn"] != "123-45-6789" {
+		t.Errorf("stored ssn = %q, want it unsealed without a schema flag", stored["ssn"])
+	}
+}
+
+func TestRetrieveProfileFieldsOpensOnlyNamedFields(t *testing.T) {
+	ks := createTestKeystore(t)
+	defer ks.Close()
+
+	data := []byte(`{"email":"john@example.com","ssn":"123-45-6789","date_of_birth":"1990-01-01"}`)
+	if err := ks.StoreProfile("profile-001", "Personal", "personal", data, testProfileSchema, false); err != nil {
+		t.Fatalf("StoreProfile() error = %v", err)
+	}
+
+	if fields, err := ks.ProfileSensitiveFields("profile-001"); err != nil || !reflect.DeepEqual(fields, []string{"date_of_birth", "ssn"}) {
+		t.Errorf("ProfileSensitiveFields() = %v, %v; want [date_of_birth ssn]", fields, err)
+	}
+
+	profile, err := ks.RetrieveProfileFields("profile-001", []string{"date_of_birth"})
+	if err != nil {
+		t.Fatalf("RetrieveProfileFields() error = %v", err)
+	}
+	var got map[string]string
+	json.Unmarshal(profile.Data, &got)
+	want := map[string]string{"email": "john@example.com", "date_of_birth": "1990-01-01"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("retrieved data = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+package keystore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultRotationGrace is how long a rotated-out token stays retrievable
+const DefaultRotationGrace = 24 * time.Hour
+
+// ErrKeyVersionRetired is returned for a previous version whose grace period has ended
+var ErrKeyVersionRetired = errors.New("key version has been retired")
+
+// KeyVersion describes one version of a credential's token (never the token itself)
+type KeyVersion struct {
+	Version     int   `json:"version"`
+	IssuedAt    int64 `json:"issued_at"`
+	RetiredAt   int64 `json:"retired_at,omitempty"`
+	ValidUntil  int64 `json:"valid_until,omitempty"`
+	Current     bool  `json:"current"`
+	Retrievable bool  `json:"retrievable"`
+}
+
+// migrateCredentialVersioning adds the rotation columns to keystores created
+// before versioning existed
+func migrateCredentialVersioning(db *sql.DB) error {
+	migrations := []string{
+		"ALTER TABLE credentials ADD COLUMN version INTEGER NOT NULL DEFAULT 1",
+		"ALTER TABLE credentials ADD COLUMN rotated_at INTEGER",
+	}
+	for _, stmt := range migrations {
+		if _, err := db.Exec(stmt); err != nil {
+			if !strings.Contains(err.Error(), "duplicate column") {
+				return fmt.Errorf("failed to migrate credentials: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Rotate replaces a credential's token and bumps its version. The previous
+// token stays retrievable through RetrieveVersion for the rotation grace
+// period so running containers can keep using it until they re-fetch.
+// An expiry belongs to the token it was set for, so rotation clears it;
+// rotating is how an expired key is put back into service.
+func (ks *Keystore) Rotate(id string, newToken string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if !ks.isOpen {
+		return errors.New("keystore is not open")
+	}
+
+	if newToken == "" {
+		return ErrInvalidCredential
+	}
+
+	tx, err := ks.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin rotation: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldToken, oldNonce []byte
+	var createdAt int64
+	var version int
+	var rotatedAt sql.NullInt64
+	err = tx.QueryRow(
+		"SELECT token_encrypted, nonce, created_at, version, rotated_at FROM credentials WHERE id = ?",
+		id,
+	).Scan(&oldToken, &oldNonce, &createdAt, &version, &rotatedAt)
+	if err == sql.ErrNoRows {
+		return ErrKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("database query failed: %w", err)
+	}
+
+	// The outgoing token was issued at creation or at the last rotation
+	issuedAt := createdAt
+	if rotatedAt.Valid && rotatedAt.Int64 > 0 {
+		issuedAt = rotatedAt.Int64
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(`
+	INSERT OR REPLACE INTO credential_versions
+	(credential_id, version, token_encrypted, nonce, issued_at, retired_at, valid_until)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, id, version, oldToken, oldNonce, issuedAt, now.Unix(), now.Add(ks.rotationGrace).Unix())
+	if err != nil {
+		return fmt.Errorf("failed to record previous version: %w", err)
+	}
+
+	encrypted, nonce, err := ks.encrypt([]byte(newToken))
+	if err != nil {
+		return fmt.Errorf("encryption failed: %w", err)
+	}
+
+	_, err = tx.Exec(
+		"UPDATE credentials SET token_encrypted = ?, nonce = ?, token_hash = ?, version = ?, rotated_at = ?, expires_at = 0 WHERE id = ?",
+		encrypted, nonce, tokenHash(newToken), version+1, now.Unix(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store rotated token: %w", err)
+	}
+
+	// Drop token material for versions past their grace period; the
+	// history rows stay for auditing
+	_, err = tx.Exec(
+		"UPDATE credential_versions SET token_encrypted = X'', nonce = X'' WHERE valid_until < ? AND length(token_encrypted) > 0",
+		now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prune retired versions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit rotation: %w", err)
+	}
+
+	if ks.auditLogger != nil {
+		ks.auditLogger.LogKeyAccess(context.Background(), id, "system", "rotate", true)
+	}
+
+	return nil
+}
+
+// RetrieveVersion returns a specific version of a credential. Version 0 or the
+// current version behaves like Retrieve; older versions are only available
+// until their grace period ends.
+func (ks *Keystore) RetrieveVersion(id string, version int) (*Credential, error) {
+	cred, err := ks.Retrieve(id)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 || version == cred.Version {
+		return cred, nil
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if !ks.isOpen {
+		return nil, errors.New("keystore is not open")
+	}
+
+	var encryptedToken, nonce []byte
+	var retiredAt, validUntil int64
+	err = ks.db.QueryRow(
+		"SELECT token_encrypted, nonce, retired_at, valid_until FROM credential_versions WHERE credential_id = ? AND version = ?",
+		id, version,
+	).Scan(&encryptedToken, &nonce, &retiredAt, &validUntil)
+	if err == sql.ErrNoRows {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	if len(encryptedToken) == 0 || time.Now().Unix() > validUntil {
+		return nil, ErrKeyVersionRetired
+	}
+
+	token, err := ks.decrypt(encryptedToken, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("decryption failed: %w", err)
+	}
+
+	if ks.auditLogger != nil {
+		ks.auditLogger.LogKeyAccess(context.Background(), id, "system", "retrieve_version", true)
+	}
+
+	cred.Token = string(token)
+	cred.Version = version
+	cred.RotatedAt = retiredAt
+	return cred, nil
+}
+
+// ListKeyVersions returns the rotation history of a credential, newest first
+func (ks *Keystore) ListKeyVersions(id string) ([]KeyVersion, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if !ks.isOpen {
+		return nil, errors.New("keystore is not open")
+	}
+
+	var createdAt int64
+	var version int
+	var rotatedAt sql.NullInt64
+	err := ks.db.QueryRow(
+		"SELECT created_at, version, rotated_at FROM credentials WHERE id = ?",
+		id,
+	).Scan(&createdAt, &version, &rotatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+
+	current := KeyVersion{
+		Version:     version,
+		IssuedAt:    createdAt,
+		Current:     true,
+		Retrievable: true,
+	}
+	if rotatedAt.Valid && rotatedAt.Int64 > 0 {
+		current.IssuedAt = rotatedAt.Int64
+	}
+	versions := []KeyVersion{current}
+
+	rows, err := ks.db.Query(
+		"SELECT version, issued_at, retired_at, valid_until, length(token_encrypted) > 0 FROM credential_versions WHERE credential_id = ? ORDER BY version DESC",
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("database query failed: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now().Unix()
+	for rows.Next() {
+		var v KeyVersion
+		var hasToken bool
+		if err := rows.Scan(&v.Version, &v.IssuedAt, &v.RetiredAt, &v.ValidUntil, &hasToken); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		v.Retrievable = hasToken && now <= v.ValidUntil
+		versions = append(versions, v)
+	}
+
+	return versions, rows.Err()
+}
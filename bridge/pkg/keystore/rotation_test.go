@@ -0,0 +1,230 @@
+//go:build cgo
+
+package keystore
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mutecomm/go-sqlcipher/v4"
+)
+
+func newRotationTestKeystore(t *testing.T, grace time.Duration) *Keystore {
+	t.Helper()
+
+	masterKey := make([]byte, 32)
+	for i := range masterKey {
+		masterKey[i] = byte(i)
+	}
+
+	ks, err := New(Config{
+		DBPath:        filepath.Join(t.TempDir(), "test.db"),
+		MasterKey:     masterKey,
+		RotationGrace: grace,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("Failed to open keystore: %v", err)
+	}
+	t.Cleanup(func() { ks.Close() })
+
+	err = ks.Store(Credential{
+		ID:        "openai-main",
+		Provider:  ProviderOpenAI,
+		Token:     "sk-original",
+		CreatedAt: time.Now().Add(-time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store credential: %v", err)
+	}
+
+	return ks
+}
+
+func TestRotate(t *testing.T) {
+	ks := newRotationTestKeystore(t, time.Hour)
+
+	if err := ks.Rotate("openai-main", "sk-rotated"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	cred, err := ks.Retrieve("openai-main")
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if cred.Token != "sk-rotated" {
+		t.Errorf("Token = %q, want sk-rotated", cred.Token)
+	}
+	if cred.Version != 2 {
+		t.Errorf("Version = %d, want 2", cred.Version)
+	}
+	if cred.RotatedAt == 0 {
+		t.Error("RotatedAt should be set after rotation")
+	}
+
+	// Previous version stays retrievable within the grace period
+	old, err := ks.RetrieveVersion("openai-main", 1)
+	if err != nil {
+		t.Fatalf("RetrieveVersion(1) failed: %v", err)
+	}
+	if old.Token != "sk-original" {
+		t.Errorf("old Token = %q, want sk-original", old.Token)
+	}
+
+	if _, err := ks.RetrieveVersion("openai-main", 7); err != ErrKeyNotFound {
+		t.Errorf("RetrieveVersion(7) error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestRotate_Errors(t *testing.T) {
+	ks := newRotationTestKeystore(t, time.Hour)
+
+	if err := ks.Rotate("missing", "sk-new"); err != ErrKeyNotFound {
+		t.Errorf("Rotate(missing) error = %v, want ErrKeyNotFound", err)
+	}
+	if err := ks.Rotate("openai-main", ""); err != ErrInvalidCredential {
+		t.Errorf("Rotate with empty token error = %v, want ErrInvalidCredential", err)
+	}
+}
+
+func TestRotate_GraceExpiry(t *testing.T) {
+	ks := newRotationTestKeystore(t, time.Hour)
+
+	if err := ks.Rotate("openai-main", "sk-second"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	// Age the retired version past its grace period
+	if _, err := ks.db.Exec("UPDATE credential_versions SET valid_until = ?", time.Now().Add(-time.Minute).Unix()); err != nil {
+		t.Fatalf("failed to age version: %v", err)
+	}
+
+	if _, err := ks.RetrieveVersion("openai-main", 1); err != ErrKeyVersionRetired {
+		t.Errorf("RetrieveVersion(1) error = %v, want ErrKeyVersionRetired", err)
+	}
+
+	// The next rotation scrubs the expired token but keeps its history row
+	if err := ks.Rotate("openai-main", "sk-third"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	var tokenLen int
+	if err := ks.db.QueryRow("SELECT length(token_encrypted) FROM credential_versions WHERE version = 1").Scan(&tokenLen); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if tokenLen != 0 {
+		t.Errorf("expired version still holds %d bytes of token material", tokenLen)
+	}
+}
+
+func TestRotate_ClearsExpiry(t *testing.T) {
+	ks := newRotationTestKeystore(t, time.Hour)
+
+	if _, err := ks.db.Exec("UPDATE credentials SET expires_at = ? WHERE id = ?", time.Now().Add(-time.Minute).Unix(), "openai-main"); err != nil {
+		t.Fatalf("failed to expire credential: %v", err)
+	}
+	if _, err := ks.Retrieve("openai-main"); err != ErrKeyExpired {
+		t.Fatalf("Retrieve error = %v, want ErrKeyExpired", err)
+	}
+
+	if err := ks.Rotate("openai-main", "sk-rotated"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	cred, err := ks.Retrieve("openai-main")
+	if err != nil {
+		t.Fatalf("Retrieve after rotation failed: %v", err)
+	}
+	if cred.ExpiresAt != 0 {
+		t.Errorf("ExpiresAt = %d, want 0 after rotation", cred.ExpiresAt)
+	}
+}
+
+func TestListKeyVersions(t *testing.T) {
+	ks := newRotationTestKeystore(t, time.Hour)
+
+	for i := 2; i <= 3; i++ {
+		if err := ks.Rotate("openai-main", fmt.Sprintf("sk-v%d", i)); err != nil {
+			t.Fatalf("Rotate failed: %v", err)
+		}
+	}
+
+	versions, err := ks.ListKeyVersions("openai-main")
+	if err != nil {
+		t.Fatalf("ListKeyVersions failed: %v", err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("got %d versions, want 3", len(versions))
+	}
+
+	if !versions[0].Current || versions[0].Version != 3 {
+		t.Errorf("versions[0] = %+v, want current version 3", versions[0])
+	}
+	for i, v := range versions[1:] {
+		if v.Current || v.Version != 2-i || !v.Retrievable || v.RetiredAt == 0 {
+			t.Errorf("versions[%d] = %+v, want retired retrievable version %d", i+1, v, 2-i)
+		}
+	}
+
+	if _, err := ks.ListKeyVersions("missing"); err != ErrKeyNotFound {
+		t.Errorf("ListKeyVersions(missing) error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestStorePreservesVersion(t *testing.T) {
+	ks := newRotationTestKeystore(t, time.Hour)
+
+	if err := ks.Rotate("openai-main", "sk-rotated"); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+
+	err := ks.Store(Credential{
+		ID:        "openai-main",
+		Provider:  ProviderOpenAI,
+		Token:     "sk-overwritten",
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	cred, err := ks.Retrieve("openai-main")
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if cred.Version != 2 {
+		t.Errorf("Version after overwrite = %d, want 2", cred.Version)
+	}
+}
+
+func TestMigrateCredentialVersioning(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "legacy.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	// Pre-versioning schema
+	_, err = db.Exec(`CREATE TABLE credentials (
+		id TEXT PRIMARY KEY, provider TEXT NOT NULL, token_encrypted BLOB NOT NULL,
+		nonce BLOB NOT NULL, base_url TEXT, display_name TEXT NOT NULL,
+		created_at INTEGER NOT NULL, expires_at INTEGER, tags TEXT)`)
+	if err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+
+	// Running twice must be harmless
+	for i := 0; i < 2; i++ {
+		if err := migrateCredentialVersioning(db); err != nil {
+			t.Fatalf("migration %d failed: %v", i, err)
+		}
+	}
+
+	if _, err := db.Exec("SELECT version, rotated_at FROM credentials"); err != nil {
+		t.Errorf("versioning columns missing after migration: %v", err)
+	}
+}
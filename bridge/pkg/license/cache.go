@@ -0,0 +1,114 @@
+package license
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// cacheFileName is the on-disk cache file inside ClientConfig.CacheDir
+const cacheFileName = "license-cache.json"
+
+// diskCache is the persisted form of the validation cache. Entries are
+// bound to a hash of the license key so a key change invalidates them
+// without storing the key itself.
+type diskCache struct {
+	KeyHash  string                    `json:"key_hash"`
+	Licenses map[string]*CachedLicense `json:"licenses"`
+}
+
+// licenseKeyHash returns a stable, non-reversible identifier for a key
+func licenseKeyHash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// cachePath returns the cache file path, or "" if persistence is disabled
+func (c *Client) cachePath() string {
+	if c.config.CacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.config.CacheDir, cacheFileName)
+}
+
+// loadDiskCache restores successful validations persisted by a previous run.
+// Caller must hold c.mu.
+func (c *Client) loadDiskCache() error {
+	path := c.cachePath()
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read license cache: %w", err)
+	}
+
+	var dc diskCache
+	if err := json.Unmarshal(data, &dc); err != nil {
+		return fmt.Errorf("failed to parse license cache: %w", err)
+	}
+
+	if dc.KeyHash != licenseKeyHash(c.config.LicenseKey) {
+		return nil
+	}
+
+	for feature, cached := range dc.Licenses {
+		if cached != nil {
+			c.cache[feature] = cached
+		}
+	}
+	return nil
+}
+
+// saveDiskCache persists valid cache entries atomically.
+// Caller must hold c.mu.
+func (c *Client) saveDiskCache() error {
+	path := c.cachePath()
+	if path == "" {
+		return nil
+	}
+
+	dc := diskCache{
+		KeyHash:  licenseKeyHash(c.config.LicenseKey),
+		Licenses: make(map[string]*CachedLicense),
+	}
+	for feature, cached := range c.cache {
+		// Only successful validations are worth surviving an outage
+		if cached != nil && cached.Valid {
+			dc.Licenses[feature] = cached
+		}
+	}
+
+	data, err := json.Marshal(dc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal license cache: %w", err)
+	}
+
+	if err := os.MkdirAll(c.config.CacheDir, 0700); err != nil {
+		return fmt.Errorf("failed to create license cache dir: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write license cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace license cache: %w", err)
+	}
+	return nil
+}
+
+// removeDiskCache deletes the persisted cache. Caller must hold c.mu.
+func (c *Client) removeDiskCache() {
+	if path := c.cachePath(); path != "" {
+		os.Remove(path)
+	}
+}
@@ -1,10 +1,20 @@
 // Package license provides license validation for ArmorClaw premium features.
 // It implements an offline-first caching strategy with grace period support.
+//
+// Grace period: each server answer, valid or not, is cached with GraceUntil
+// set to the license's ExpiresAt (24h from validation if the server gives
+// none) plus GracePeriodDays. Validate answers from the cache until it is
+// due a refresh, DefaultRefreshInterval after the last check or at
+// ExpiresAt, whichever comes first, and then asks the server again. If the
+// server cannot be reached, a cached valid license is still honored until
+// GraceUntil; after that Validate fails. With CacheDir set the cache is
+// persisted, so this also holds across bridge restarts.
 package license
 
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -118,7 +128,8 @@ func (c *CachedLicense) HasFeature(feature string) bool {
 	return false
 }
 
-// ShouldRefresh determines if the license should be re-validated
+// ShouldRefresh reports whether the license is due to be re-validated: at
+// ExpiresAt, or DefaultRefreshInterval after it was last checked
 func (c *CachedLicense) ShouldRefresh() bool {
 	if c == nil {
 		return true
@@ -126,18 +137,18 @@ func (c *CachedLicense) ShouldRefresh() bool {
 
 	now := time.Now()
 
-	// If still valid per server, no refresh needed
-	if now.Before(c.ExpiresAt) {
-		return false
+	// Expired licenses are re-checked; the grace period only covers a
+	// server that cannot be reached
+	if !now.Before(c.ExpiresAt) {
+		return true
 	}
 
-	// Within grace period? No refresh required yet
-	if now.Before(c.GraceUntil) {
-		return false
+	// Otherwise re-check periodically so revocations are noticed
+	lastChecked := c.LastChecked
+	if lastChecked.IsZero() {
+		lastChecked = c.CachedAt
 	}
-
-	// Grace period expired, must refresh
-	return true
+	return !now.Before(lastChecked.Add(DefaultRefreshInterval))
 }
 
 // ClientConfig configures the license client
@@ -157,12 +168,24 @@ type ClientConfig struct {
 	// HTTP client timeout
 	Timeout time.Duration
 
-	// Grace period in days (default: 3)
+	// Grace period in days past license expiry during which a cached
+	// successful validation is still honored (default: 3)
 	GracePeriodDays int
 
+	// Directory for the persistent validation cache (empty = memory only)
+	CacheDir string
+
 	// Enable offline mode (never contact server)
 	OfflineMode bool
 
+	// Signed offline license token issued by the license server. In
+	// offline mode it is verified locally with PublicKey and takes the
+	// place of the server round trip.
+	OfflineToken string
+
+	// License server's Ed25519 public key for verifying OfflineToken
+	PublicKey ed25519.PublicKey
+
 	// Logger
 	Logger *slog.Logger
 }
@@ -205,14 +228,21 @@ func NewClient(config ClientConfig) (*Client, error) {
 		}))
 	}
 
-	return &Client{
+	c := &Client{
 		config: config,
 		client: &http.Client{
 			Timeout: config.Timeout,
 		},
 		cache:  make(map[string]*CachedLicense),
 		logger: logger,
-	}, nil
+	}
+
+	// A corrupt or unreadable cache only costs a server round trip
+	if err := c.loadDiskCache(); err != nil {
+		logger.Warn("ignoring persisted license cache", "error", err)
+	}
+
+	return c, nil
 }
 
 // Validate checks if a feature is available under the current license
@@ -230,8 +260,16 @@ func (c *Client) Validate(ctx context.Context, feature string) (bool, error) {
 		return cached.Valid, nil
 	}
 
-	// In offline mode, use cache only
+	// In offline mode, verify the offline token or fall back to the cache
 	if c.config.OfflineMode {
+		if c.config.OfflineToken != "" {
+			result, err := c.validateOffline(feature)
+			if err != nil {
+				return false, fmt.Errorf("offline mode: %w", err)
+			}
+			c.SetCached(feature, result)
+			return result.Valid, nil
+		}
 		if cached != nil {
 			c.logger.Warn("offline mode: using cached license",
 				"feature", feature,
@@ -245,13 +283,21 @@ func (c *Client) Validate(ctx context.Context, feature string) (bool, error) {
 	// Validate with server
 	result, err := c.validateWithServer(ctx, feature)
 	if err != nil {
-		// Server unreachable, use cache if available
-		if cached != nil {
+		// Server unreachable, use cache if still within its grace period
+		if cached != nil && cached.IsValid() {
 			c.logger.Warn("server unreachable, using cached license",
 				"feature", feature,
+				"grace_until", cached.GraceUntil,
 				"error", err,
 			)
-			return cached.Valid, nil
+			return true, nil
+		}
+		if cached != nil && cached.Valid {
+			return false, fmt.Errorf("cached license grace period ended %s and server unreachable: %w",
+				cached.GraceUntil.Format(time.RFC3339), err)
+		}
+		if cached != nil {
+			return false, fmt.Errorf("cached license is not valid and server unreachable: %w", err)
 		}
 		return false, fmt.Errorf("no cached license and server unreachable: %w", err)
 	}
@@ -378,6 +424,10 @@ func (c *Client) SetCached(feature string, cached *CachedLicense) {
 	defer c.mu.Unlock()
 
 	c.cache[feature] = cached
+
+	if err := c.saveDiskCache(); err != nil {
+		c.logger.Warn("failed to persist license cache", "error", err)
+	}
 }
 
 // HasFeature checks if a specific feature is available
@@ -455,6 +505,7 @@ func (c *Client) ClearCache() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.cache = make(map[string]*CachedLicense)
+	c.removeDiskCache()
 }
 
 // GetInstanceID returns the client's instance ID
@@ -471,6 +522,7 @@ func (c *Client) SetLicenseKey(key string) {
 		c.config.LicenseKey = key
 		// Clear cache when key changes
 		c.cache = make(map[string]*CachedLicense)
+		c.removeDiskCache()
 	}
 }
 
@@ -0,0 +1,246 @@
+package license
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestLicenseServer(t *testing.T, up *atomic.Bool, expiresAt time.Time) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"error": "unavailable"})
+			return
+		}
+		json.NewEncoder(w).Encode(ValidationResponse{
+			Valid:           true,
+			Tier:            TierPro,
+			Features:        []string{"slack-adapter"},
+			ExpiresAt:       expiresAt.Format(time.RFC3339),
+			GracePeriodDays: 3,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient_PersistentCacheSurvivesRestart(t *testing.T) {
+	var up atomic.Bool
+	up.Store(true)
+	srv := newTestLicenseServer(t, &up, time.Now().Add(24*time.Hour))
+
+	cfg := ClientConfig{
+		ServerURL:  srv.URL,
+		LicenseKey: "SCLW-PRO-0123456789abcdef",
+		InstanceID: "instance-1",
+		CacheDir:   t.TempDir(),
+	}
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if valid, err := client.Validate(context.Background(), "slack-adapter"); err != nil || !valid {
+		t.Fatalf("Validate() = %v, %v; want true, nil", valid, err)
+	}
+
+	info, err := os.Stat(filepath.Join(cfg.CacheDir, cacheFileName))
+	if err != nil {
+		t.Fatalf("cache file not written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("cache file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Restart during an outage: the persisted validation is still honored
+	up.Store(false)
+	restarted, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if valid, err := restarted.Validate(context.Background(), "slack-adapter"); err != nil || !valid {
+		t.Errorf("Validate() after restart = %v, %v; want true, nil", valid, err)
+	}
+
+	// A different license key must not reuse the cache
+	cfg.LicenseKey = "SCLW-PRO-fedcba9876543210"
+	other, _ := NewClient(cfg)
+	if other.GetCached("slack-adapter") != nil {
+		t.Error("cache for a different license key should be ignored")
+	}
+}
+
+func TestClient_ServerErrorHonorsGracePeriod(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientConfig{
+		ServerURL:  srv.URL,
+		LicenseKey: "SCLW-PRO-0123456789abcdef",
+		InstanceID: "instance-1",
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	day := 24 * time.Hour
+	tests := []struct {
+		name      string
+		expiresAt time.Duration // relative to now
+		valid     bool
+	}{
+		// Due a periodic re-check; the server is down so the cache answers
+		{"before expiry", 30 * day, true},
+		{"inside grace", -1 * day, true},
+		// Past grace the server error is not masked
+		{"after grace", -5 * day, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expiresAt := time.Now().Add(tt.expiresAt)
+			client.SetCached("sso", &CachedLicense{
+				Valid:       true,
+				Tier:        TierPro,
+				ExpiresAt:   expiresAt,
+				GraceUntil:  expiresAt.Add(3 * day),
+				CachedAt:    time.Now().Add(-2 * DefaultRefreshInterval),
+				LastChecked: time.Now().Add(-2 * DefaultRefreshInterval),
+			})
+
+			before := hits.Load()
+			valid, err := client.Validate(context.Background(), "sso")
+			if hits.Load() == before {
+				t.Fatal("Validate() did not re-check with the server")
+			}
+			if tt.valid && (!valid || err != nil) {
+				t.Errorf("Validate() = %v, %v; want true from the cache", valid, err)
+			}
+			if !tt.valid && (valid || err == nil) {
+				t.Errorf("Validate() = %v, %v; want false with error", valid, err)
+			}
+		})
+	}
+}
+
+func TestCachedLicense_ShouldRefresh(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name   string
+		cached *CachedLicense
+		want   bool
+	}{
+		{"nil", nil, true},
+		{"recently checked", &CachedLicense{ExpiresAt: now.Add(time.Hour * 48), LastChecked: now}, false},
+		{"check interval passed", &CachedLicense{ExpiresAt: now.Add(time.Hour * 48), LastChecked: now.Add(-2 * DefaultRefreshInterval)}, true},
+		{"expired within grace", &CachedLicense{ExpiresAt: now.Add(-time.Minute), GraceUntil: now.Add(time.Hour * 48), LastChecked: now}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cached.ShouldRefresh(); got != tt.want {
+				t.Errorf("ShouldRefresh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ClearCacheRemovesFile(t *testing.T) {
+	dir := t.TempDir()
+	client, _ := NewClient(ClientConfig{
+		LicenseKey: "SCLW-PRO-0123456789abcdef",
+		InstanceID: "instance-1",
+		CacheDir:   dir,
+	})
+
+	client.SetCached("slack-adapter", &CachedLicense{Valid: true, ExpiresAt: time.Now().Add(time.Hour)})
+	if _, err := os.Stat(filepath.Join(dir, cacheFileName)); err != nil {
+		t.Fatalf("cache file not written: %v", err)
+	}
+
+	client.ClearCache()
+	if _, err := os.Stat(filepath.Join(dir, cacheFileName)); !os.IsNotExist(err) {
+		t.Errorf("cache file should be removed, stat err = %v", err)
+	}
+}
+
+// signTestToken issues an offline token the way the license server does
+func signTestToken(t *testing.T, key ed25519.PrivateKey, claims OfflineClaims) string {
+	t.Helper()
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	input := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA","typ":"JWT"}`)) +
+		"." + base64.RawURLEncoding.EncodeToString(payload)
+	return input + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(input)))
+}
+
+func TestClient_OfflineToken(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	claims := OfflineClaims{
+		Issuer:     OfflineTokenIssuer,
+		LicenseKey: "SCLW-PRO-0123456789abcdef",
+		Tier:       TierPro,
+		Features:   []string{"slack-adapter"},
+		IssuedAt:   time.Now().Unix(),
+		ExpiresAt:  time.Now().Add(24 * time.Hour).Unix(),
+	}
+	token := signTestToken(t, priv, claims)
+
+	parsed, err := ParsePublicKey(base64.StdEncoding.EncodeToString(pub))
+	if err != nil {
+		t.Fatalf("ParsePublicKey: %v", err)
+	}
+
+	// The server URL is unreachable: offline validation must not need it
+	client, err := NewClient(ClientConfig{
+		ServerURL:    "http://127.0.0.1:1",
+		LicenseKey:   claims.LicenseKey,
+		InstanceID:   "instance-1",
+		OfflineMode:  true,
+		OfflineToken: token,
+		PublicKey:    parsed,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if valid, err := client.Validate(context.Background(), "slack-adapter"); err != nil || !valid {
+		t.Errorf("Validate(slack-adapter) = %v, %v; want true, nil", valid, err)
+	}
+	if valid, err := client.Validate(context.Background(), "sso"); err != nil || valid {
+		t.Errorf("Validate(sso) = %v, %v; want false, nil", valid, err)
+	}
+	if tier, err := client.GetTier(context.Background()); err != nil || tier != TierPro {
+		t.Errorf("GetTier() = %v, %v; want %v, nil", tier, err, TierPro)
+	}
+
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if _, err := VerifyOfflineToken(token, otherPub, time.Now()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("wrong key: err = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := VerifyOfflineToken(token, pub, time.Now().Add(48*time.Hour)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("after exp: err = %v, want ErrTokenExpired", err)
+	}
+	tampered := strings.Replace(token, ".", ".x", 1)
+	if _, err := VerifyOfflineToken(tampered, pub, time.Now()); err == nil {
+		t.Error("tampered token should not verify")
+	}
+}
@@ -0,0 +1,132 @@
+package license
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OfflineTokenIssuer is the iss claim set by the license server
+const OfflineTokenIssuer = "armorclaw-license-server"
+
+// Offline token errors
+var (
+	ErrMalformedToken   = errors.New("malformed offline license token")
+	ErrInvalidSignature = errors.New("offline license token signature is invalid")
+	ErrTokenExpired     = errors.New("offline license token has expired")
+)
+
+// OfflineClaims are the claims carried by an offline license token
+type OfflineClaims struct {
+	Issuer       string   `json:"iss"`
+	LicenseKey   string   `json:"sub"`
+	Tier         Tier     `json:"tier"`
+	Features     []string `json:"features"`
+	MaxInstances int      `json:"max_instances"`
+	IssuedAt     int64    `json:"iat"`
+	ExpiresAt    int64    `json:"exp"`
+}
+
+// Expiry returns the exp claim as a time
+func (c *OfflineClaims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// ParsePublicKey decodes a base64 Ed25519 public key, as logged by the
+// license server when offline tokens are enabled
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("public key is not valid base64: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key is %d bytes, want %d", len(raw), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// VerifyOfflineToken checks an offline license token's signature and expiry
+// against the license server's public key. It never contacts the server.
+func VerifyOfflineToken(token string, publicKey ed25519.PublicKey, now time.Time) (*OfflineClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrMalformedToken, err)
+	}
+	var hdr struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &hdr); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrMalformedToken, err)
+	}
+	// Pin the algorithm so a token can't pick a weaker one
+	if hdr.Alg != "EdDSA" {
+		return nil, fmt.Errorf("%w: unsupported alg %q", ErrMalformedToken, hdr.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrMalformedToken, err)
+	}
+	if len(publicKey) != ed25519.PublicKeySize || !ed25519.Verify(publicKey, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrMalformedToken, err)
+	}
+	var claims OfflineClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrMalformedToken, err)
+	}
+	if claims.Issuer != OfflineTokenIssuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrMalformedToken, claims.Issuer)
+	}
+	if !now.Before(claims.Expiry()) {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+// validateOffline answers a feature check from the configured offline token
+func (c *Client) validateOffline(feature string) (*CachedLicense, error) {
+	claims, err := VerifyOfflineToken(c.config.OfflineToken, c.config.PublicKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if c.config.LicenseKey != "" && claims.LicenseKey != c.config.LicenseKey {
+		return nil, fmt.Errorf("offline license token was issued for a different license key")
+	}
+
+	// Unlike a server validation, the token's expiry is final: there is no
+	// server to fall back to, so no grace period is added on top of it
+	expiresAt := claims.Expiry()
+	valid := feature == "" || feature == "license-info"
+	for _, f := range claims.Features {
+		if f == feature {
+			valid = true
+		}
+	}
+
+	now := time.Now()
+	return &CachedLicense{
+		Valid:       valid,
+		Tier:        claims.Tier,
+		Features:    claims.Features,
+		ExpiresAt:   expiresAt,
+		CachedAt:    now,
+		GraceUntil:  expiresAt,
+		InstanceID:  c.config.InstanceID,
+		LastChecked: now,
+	}, nil
+}
@@ -9,7 +9,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -32,6 +34,96 @@ const (
 type Logger struct {
 	*slog.Logger
 	component string
+	level     *slog.LevelVar // shared by loggers derived with With*
+	output    *outputState   // shared by loggers derived with With*
+}
+
+// outputState holds the format the logger tree writes in. Changing it
+// bumps gen so every formatHandler rebuilds its handler chain.
+type outputState struct {
+	writer io.Writer
+	opts   *slog.HandlerOptions
+
+	mu     sync.RWMutex
+	format string
+	base   slog.Handler
+	gen    uint64
+}
+
+func newOutputState(writer io.Writer, opts *slog.HandlerOptions, format string) *outputState {
+	o := &outputState{writer: writer, opts: opts}
+	o.setFormat(format)
+	return o
+}
+
+func (o *outputState) setFormat(format string) {
+	var base slog.Handler
+	if format == "json" {
+		base = slog.NewJSONHandler(o.writer, o.opts)
+	} else {
+		format = "text"
+		base = slog.NewTextHandler(o.writer, o.opts)
+	}
+
+	o.mu.Lock()
+	o.format = format
+	o.base = base
+	o.gen++
+	o.mu.Unlock()
+}
+
+func (o *outputState) current() (slog.Handler, uint64, string) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.base, o.gen, o.format
+}
+
+// formatHandler is the slog.Handler behind every Logger. It replays the
+// attributes and groups added with With/WithGroup onto the current base
+// handler, so a format change also reaches already derived loggers.
+type formatHandler struct {
+	output *outputState
+	ops    []func(slog.Handler) slog.Handler
+	cache  atomic.Pointer[cachedHandler]
+}
+
+type cachedHandler struct {
+	gen     uint64
+	handler slog.Handler
+}
+
+func (h *formatHandler) handler() slog.Handler {
+	base, gen, _ := h.output.current()
+	if c := h.cache.Load(); c != nil && c.gen == gen {
+		return c.handler
+	}
+	for _, op := range h.ops {
+		base = op(base)
+	}
+	h.cache.Store(&cachedHandler{gen: gen, handler: base})
+	return base
+}
+
+func (h *formatHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.output.opts.Level.Level()
+}
+
+func (h *formatHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+func (h *formatHandler) with(op func(slog.Handler) slog.Handler) *formatHandler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &formatHandler{output: h.output, ops: append(ops, op)}
+}
+
+func (h *formatHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(base slog.Handler) slog.Handler { return base.WithAttrs(attrs) })
+}
+
+func (h *formatHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(base slog.Handler) slog.Handler { return base.WithGroup(name) })
 }
 
 // Config holds logger configuration
@@ -44,19 +136,10 @@ type Config struct {
 
 // New creates a new logger instance
 func New(cfg Config) (*Logger, error) {
-	// Parse log level
-	var level slog.Level
-	switch cfg.Level {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		level = slog.LevelInfo
+	// Parse log level, defaulting to info
+	level := new(slog.LevelVar)
+	if l, ok := parseLevel(cfg.Level); ok {
+		level.Set(l)
 	}
 
 	// Determine output writer
@@ -88,16 +171,11 @@ func New(cfg Config) (*Logger, error) {
 		Level: level,
 	}
 
-	// Create handler based on format
-	var handler slog.Handler
-	if cfg.Format == "json" {
-		handler = slog.NewJSONHandler(writer, opts)
-	} else {
-		handler = slog.NewTextHandler(writer, opts)
-	}
+	// Create handler based on format; SetFormat can switch it later
+	out := newOutputState(writer, opts, cfg.Format)
 
 	// Create logger with default attributes
-	logger := slog.New(handler)
+	logger := slog.New(&formatHandler{output: out})
 	logger = logger.With(
 		"service", "armorclaw",
 		"component", cfg.Component,
@@ -107,9 +185,54 @@ func New(cfg Config) (*Logger, error) {
 	return &Logger{
 		Logger:    logger,
 		component: cfg.Component,
+		level:     level,
+		output:    out,
 	}, nil
 }
 
+// parseLevel converts a level name to a slog level
+func parseLevel(level string) (slog.Level, bool) {
+	switch LogLevel(level) {
+	case LevelDebug:
+		return slog.LevelDebug, true
+	case LevelInfo:
+		return slog.LevelInfo, true
+	case LevelWarn:
+		return slog.LevelWarn, true
+	case LevelError:
+		return slog.LevelError, true
+	default:
+		return slog.LevelInfo, false
+	}
+}
+
+// SetLevel changes the minimum level of this logger and every logger
+// derived from it
+func (l *Logger) SetLevel(level string) error {
+	lvl, ok := parseLevel(level)
+	if !ok {
+		return fmt.Errorf("invalid log level %q", level)
+	}
+	l.level.Set(lvl)
+	return nil
+}
+
+// SetFormat switches this logger and every logger derived from it between
+// "json" and "text" output, starting with the next log line
+func (l *Logger) SetFormat(format string) error {
+	if format != "json" && format != "text" {
+		return fmt.Errorf("invalid log format %q (must be json or text)", format)
+	}
+	l.output.setFormat(format)
+	return nil
+}
+
+// Settings returns the current level and format of this logger
+func (l *Logger) Settings() (level, format string) {
+	_, _, format = l.output.current()
+	return strings.ToLower(l.level.Level().String()), format
+}
+
 // Initialize sets up the global logger with configuration
 func Initialize(level, format, output string) error {
 	var onceErr error
@@ -149,6 +272,29 @@ func Initialize(level, format, output string) error {
 	return onceErr
 }
 
+// SetLevel changes the level of the global logger at runtime, e.g. on a
+// configuration reload
+func SetLevel(level string) error {
+	if globalLogger == nil {
+		return fmt.Errorf("logger not initialized")
+	}
+	return globalLogger.SetLevel(level)
+}
+
+// SetFormat changes the format of the global logger at runtime, e.g. to
+// switch to JSON while debugging an incident
+func SetFormat(format string) error {
+	if globalLogger == nil {
+		return fmt.Errorf("logger not initialized")
+	}
+	return globalLogger.SetFormat(format)
+}
+
+// Settings returns the current level and format of the global logger
+func Settings() (level, format string) {
+	return Global().Settings()
+}
+
 // Global returns the global logger instance
 func Global() *Logger {
 	if globalLogger == nil {
@@ -169,6 +315,8 @@ func (l *Logger) WithComponent(component string) *Logger {
 	return &Logger{
 		Logger:    l.Logger.With("component", component),
 		component: component,
+		level:     l.level,
+		output:    l.output,
 	}
 }
 
@@ -177,6 +325,8 @@ func (l *Logger) WithRequestID(requestID string) *Logger {
 	return &Logger{
 		Logger:    l.Logger.With("request_id", requestID),
 		component: l.component,
+		level:     l.level,
+		output:    l.output,
 	}
 }
 
@@ -185,6 +335,8 @@ func (l *Logger) WithSessionID(sessionID string) *Logger {
 	return &Logger{
 		Logger:    l.Logger.With("session_id", sessionID),
 		component: l.component,
+		level:     l.level,
+		output:    l.output,
 	}
 }
 
@@ -193,6 +345,8 @@ func (l *Logger) WithContainerID(containerID string) *Logger {
 	return &Logger{
 		Logger:    l.Logger.With("container_id", containerID),
 		component: l.component,
+		level:     l.level,
+		output:    l.output,
 	}
 }
 
@@ -628,3 +628,73 @@ func BenchmarkLoggerText(b *testing.B) {
 		logger.Info("bench message", "iteration", i)
 	}
 }
+
+// TestSetLevel tests changing the level at runtime
+func TestSetLevel(t *testing.T) {
+	logger, err := New(Config{Level: "info", Format: "json", Component: "test"})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	derived := logger.WithComponent("child")
+	ctx := context.Background()
+
+	if derived.Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("debug should be disabled at info level")
+	}
+	if err := logger.SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel() failed: %v", err)
+	}
+	if !logger.Enabled(ctx, slog.LevelDebug) || !derived.Enabled(ctx, slog.LevelDebug) {
+		t.Error("SetLevel(debug) should apply to the logger and its derived loggers")
+	}
+	if err := logger.SetLevel("error"); err != nil || derived.Enabled(ctx, slog.LevelWarn) {
+		t.Errorf("SetLevel(error) = %v; warn still enabled", err)
+	}
+
+	if err := logger.SetLevel("verbose"); err == nil {
+		t.Error("SetLevel() should reject unknown levels")
+	}
+}
+
+// TestSetFormat tests switching the output format at runtime
+func TestSetFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bridge.log")
+	logger, err := New(Config{Level: "info", Format: "text", Output: path, Component: "test"})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	derived := logger.WithComponent("child").WithRequestID("req-1")
+
+	derived.Info("before switch")
+	if err := logger.SetFormat("json"); err != nil {
+		t.Fatalf("SetFormat() failed: %v", err)
+	}
+	derived.Info("after switch", "key", "value")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d log lines, want 2:\n%s", len(lines), data)
+	}
+	if json.Valid([]byte(lines[0])) {
+		t.Errorf("line before the switch is JSON: %s", lines[0])
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatalf("line after the switch is not JSON: %s", lines[1])
+	}
+	if entry["component"] != "child" || entry["request_id"] != "req-1" || entry["key"] != "value" {
+		t.Errorf("derived logger lost its attributes: %v", entry)
+	}
+
+	if level, format := logger.Settings(); level != "info" || format != "json" {
+		t.Errorf("Settings() = %s, %s; want info, json", level, format)
+	}
+	if err := logger.SetFormat("xml"); err == nil {
+		t.Error("SetFormat() should reject unknown formats")
+	}
+}
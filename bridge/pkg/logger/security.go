@@ -22,6 +22,7 @@ const (
 	ContainerStop       SecurityEventType = "container_stop"
 	ContainerError      SecurityEventType = "container_error"
 	ContainerTimeout    SecurityEventType = "container_timeout"
+	ContainerExec       SecurityEventType = "container_exec"
 
 	// Secret access events
 	SecretAccess        SecurityEventType = "secret_access"
@@ -130,6 +131,17 @@ func (sl *SecurityLogger) LogContainerError(ctx context.Context, sessionID, cont
 	sl.logger.SecurityEvent(ctx, string(ContainerError), append(baseAttrs, attrs...)...)
 }
 
+// LogContainerExec logs a command run inside a container on behalf of a user
+func (sl *SecurityLogger) LogContainerExec(ctx context.Context, containerID, userID string, cmd []string, attrs ...slog.Attr) {
+	baseAttrs := []slog.Attr{
+		slog.String("container_id", containerID),
+		slog.String("user_id", userID),
+		slog.Any("cmd", cmd),
+		slog.String("timestamp", time.Now().UTC().Format(time.RFC3339)),
+	}
+	sl.logger.SecurityEvent(ctx, string(ContainerExec), append(baseAttrs, attrs...)...)
+}
+
 // LogSecretAccess logs a secret access event
 func (sl *SecurityLogger) LogSecretAccess(ctx context.Context, keyID, keyType string, attrs ...slog.Attr) {
 	baseAttrs := []slog.Attr{
@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"text/template"
 
 	"github.com/armorclaw/bridge/internal/adapter"
 	"github.com/armorclaw/bridge/pkg/logger"
@@ -21,6 +22,7 @@ type Notifier struct {
 	ctx           context.Context
 	cancel        context.CancelFunc
 	securityLog   *logger.SecurityLogger
+	templates     map[string]*template.Template
 }
 
 // Config holds notification configuration
@@ -80,16 +82,14 @@ func (n *Notifier) SendBudgetAlert(alertType string, sessionID string, current,
 		return nil
 	}
 
-	percentage := (current / limit) * 100
-
-	message := fmt.Sprintf("🔔 **Budget Alert**\n\n"+
-		"**Type:** %s\n"+
-		"**Session:** %s\n"+
-		"**Current:** $%.2f\n"+
-		"**Limit:** $%.2f\n"+
-		"**Usage:** %.1f%%\n\n"+
-		"Action may be required if this is not expected.",
-		alertType, sessionID, current, limit, percentage)
+	message := n.render(AlertData{
+		Category:  CategoryBudget,
+		Type:      alertType,
+		SessionID: sessionID,
+		Current:   current,
+		Limit:     limit,
+		Percent:   (current / limit) * 100,
+	})
 
 	_, err := n.matrixAdapter.SendMessage(n.adminRoomID, message, "m.notice")
 	if err != nil {
@@ -120,16 +120,12 @@ func (n *Notifier) SendSecurityAlert(eventType, message string, metadata map[str
 		return nil
 	}
 
-	// Build message with metadata
-	msgText := fmt.Sprintf("🚨 **Security Alert**\n\n**Type:** %s\n\n%s\n", eventType, message)
-
-	// Add metadata if present
-	if len(metadata) > 0 {
-		msgText += "\n**Details:**\n"
-		for key, value := range metadata {
-			msgText += fmt.Sprintf("- %s: %v\n", key, value)
-		}
-	}
+	msgText := n.render(AlertData{
+		Category: CategorySecurity,
+		Type:     eventType,
+		Message:  message,
+		Metadata: metadata,
+	})
 
 	_, err := n.matrixAdapter.SendMessage(n.adminRoomID, msgText, "m.notice")
 	if err != nil {
@@ -160,26 +156,14 @@ func (n *Notifier) SendContainerAlert(eventType, containerID, containerName, rea
 		return nil
 	}
 
-	var emoji string
-	switch eventType {
-	case "container_started":
-		emoji = "✅"
-	case "container_stopped":
-		emoji = "⏹️"
-	case "container_failed":
-		emoji = "❌"
-	case "container_restarted":
-		emoji = "🔄"
-	default:
-		emoji = "ℹ️"
-	}
-
-	message := fmt.Sprintf("%s **Container Event**\n\n"+
-		"**Type:** %s\n"+
-		"**Container:** %s\n"+
-		"**ID:** %s\n"+
-		"**Reason:** %s",
-		emoji, eventType, containerName, containerID, reason)
+	message := n.render(AlertData{
+		Category:      CategoryContainer,
+		Type:          eventType,
+		Emoji:         containerEmoji(eventType),
+		ContainerID:   containerID,
+		ContainerName: containerName,
+		Reason:        reason,
+	})
 
 	_, err := n.matrixAdapter.SendMessage(n.adminRoomID, message, "m.notice")
 	if err != nil {
@@ -210,7 +194,11 @@ func (n *Notifier) SendSystemAlert(eventType, message string) error {
 		return nil
 	}
 
-	msgText := fmt.Sprintf("⚠️ **System Alert**\n\n**Type:** %s\n\n%s", eventType, message)
+	msgText := n.render(AlertData{
+		Category: CategorySystem,
+		Type:     eventType,
+		Message:  message,
+	})
 
 	_, err := n.matrixAdapter.SendMessage(n.adminRoomID, msgText, "m.notice")
 	if err != nil {
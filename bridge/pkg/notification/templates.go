@@ -0,0 +1,160 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/template"
+)
+
+// Alert categories. Each has a built-in message template and can be
+// overridden as a whole, while individual alert types such as
+// "container_failed" can be overridden on their own.
+const (
+	CategoryBudget    = "budget"
+	CategorySecurity  = "security"
+	CategoryContainer = "container"
+	CategorySystem    = "system"
+)
+
+// AlertData is the data passed to message templates. Fields that do not
+// apply to an alert's category are left at their zero value.
+type AlertData struct {
+	Category string
+	Type     string
+	Emoji    string
+	Message  string
+
+	// Budget alerts
+	SessionID string
+	Current   float64
+	Limit     float64
+	Percent   float64
+
+	// Container alerts
+	ContainerID   string
+	ContainerName string
+	Reason        string
+
+	// Security alerts
+	Metadata map[string]interface{}
+}
+
+// defaultTemplates holds the built-in English message for each category
+var defaultTemplates = map[string]*template.Template{
+	CategoryBudget: template.Must(template.New(CategoryBudget).Parse(
+		"🔔 **Budget Alert**\n\n" +
+			"**Type:** {{.Type}}\n" +
+			"**Session:** {{.SessionID}}\n" +
+			"**Current:** ${{printf \"%.2f\" .Current}}\n" +
+			"**Limit:** ${{printf \"%.2f\" .Limit}}\n" +
+			"**Usage:** {{printf \"%.1f\" .Percent}}%\n\n" +
+			"Action may be required if this is not expected.")),
+	CategorySecurity: template.Must(template.New(CategorySecurity).Parse(
+		"🚨 **Security Alert**\n\n**Type:** {{.Type}}\n\n{{.Message}}\n" +
+			"{{if .Metadata}}\n**Details:**\n{{range $key, $value := .Metadata}}- {{$key}}: {{$value}}\n{{end}}{{end}}")),
+	CategoryContainer: template.Must(template.New(CategoryContainer).Parse(
+		"{{.Emoji}} **Container Event**\n\n" +
+			"**Type:** {{.Type}}\n" +
+			"**Container:** {{.ContainerName}}\n" +
+			"**ID:** {{.ContainerID}}\n" +
+			"**Reason:** {{.Reason}}")),
+	CategorySystem: template.Must(template.New(CategorySystem).Parse(
+		"⚠️ **System Alert**\n\n**Type:** {{.Type}}\n\n{{.Message}}")),
+}
+
+// sampleAlertData is used to check that a template executes before it is
+// registered, so mistakes surface at startup rather than mid-incident
+var sampleAlertData = AlertData{
+	Category:      CategorySystem,
+	Type:          "sample",
+	Emoji:         "ℹ️",
+	Message:       "sample message",
+	SessionID:     "session",
+	Current:       8,
+	Limit:         10,
+	Percent:       80,
+	ContainerID:   "container-id",
+	ContainerName: "container",
+	Reason:        "reason",
+	Metadata:      map[string]interface{}{"key": "value"},
+}
+
+// SetTemplate registers a text/template for an alert type or category.
+// Lookups try the exact alert type first, then the category, then the
+// built-in default. The template is parsed and executed against sample data
+// before it replaces anything; an empty template removes the override.
+func (n *Notifier) SetTemplate(alertType, tmpl string) error {
+	if alertType == "" {
+		return fmt.Errorf("alert type is required")
+	}
+
+	var parsed *template.Template
+	if tmpl != "" {
+		var err error
+		parsed, err = template.New(alertType).Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return fmt.Errorf("invalid template for %s: %w", alertType, err)
+		}
+		if err := parsed.Execute(io.Discard, sampleAlertData); err != nil {
+			return fmt.Errorf("invalid template for %s: %w", alertType, err)
+		}
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if parsed == nil {
+		delete(n.templates, alertType)
+		return nil
+	}
+	if n.templates == nil {
+		n.templates = make(map[string]*template.Template)
+	}
+	n.templates[alertType] = parsed
+	return nil
+}
+
+// render formats an alert with the most specific registered template. If a
+// custom template fails at send time the built-in default is used instead,
+// so a bad override never suppresses an alert. Callers must hold n.mu.
+func (n *Notifier) render(data AlertData) string {
+	tmpl, ok := n.templates[data.Type]
+	if !ok {
+		tmpl, ok = n.templates[data.Category]
+	}
+
+	var buf bytes.Buffer
+	if ok {
+		err := tmpl.Execute(&buf, data)
+		if err == nil {
+			return buf.String()
+		}
+		n.securityLog.LogSecurityEvent("notification_template_failed",
+			slog.String("template", tmpl.Name()),
+			slog.String("error", err.Error()))
+		buf.Reset()
+	}
+
+	if err := defaultTemplates[data.Category].Execute(&buf, data); err != nil {
+		return fmt.Sprintf("%s: %s", data.Type, data.Message)
+	}
+	return buf.String()
+}
+
+// containerEmoji returns the emoji shown for a container event type
+func containerEmoji(eventType string) string {
+	switch eventType {
+	case "container_started":
+		return "✅"
+	case "container_stopped":
+		return "⏹️"
+	case "container_failed":
+		return "❌"
+	case "container_restarted":
+		return "🔄"
+	default:
+		return "ℹ️"
+	}
+}
@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDefaultTemplates(t *testing.T) {
+	n := NewNotifier(nil, Config{})
+
+	got := n.render(AlertData{
+		Category:  CategoryBudget,
+		Type:      "warning",
+		SessionID: "sess-1",
+		Current:   8,
+		Limit:     10,
+		Percent:   80,
+	})
+	want := "🔔 **Budget Alert**\n\n" +
+		"**Type:** warning\n" +
+		"**Session:** sess-1\n" +
+		"**Current:** $8.00\n" +
+		"**Limit:** $10.00\n" +
+		"**Usage:** 80.0%\n\n" +
+		"Action may be required if this is not expected."
+	if got != want {
+		t.Errorf("budget message = %q, want %q", got, want)
+	}
+
+	got = n.render(AlertData{
+		Category:      CategoryContainer,
+		Type:          "container_failed",
+		Emoji:         containerEmoji("container_failed"),
+		ContainerID:   "abc123",
+		ContainerName: "agent",
+		Reason:        "exited",
+	})
+	want = "❌ **Container Event**\n\n" +
+		"**Type:** container_failed\n" +
+		"**Container:** agent\n" +
+		"**ID:** abc123\n" +
+		"**Reason:** exited"
+	if got != want {
+		t.Errorf("container message = %q, want %q", got, want)
+	}
+
+	got = n.render(AlertData{
+		Category: CategorySecurity,
+		Type:     "auth_failure",
+		Message:  "bad token",
+		Metadata: map[string]interface{}{"b": 2, "a": "x"},
+	})
+	want = "🚨 **Security Alert**\n\n**Type:** auth_failure\n\nbad token\n" +
+		"\n**Details:**\n- a: x\n- b: 2\n"
+	if got != want {
+		t.Errorf("security message = %q, want %q", got, want)
+	}
+
+	got = n.render(AlertData{Category: CategorySystem, Type: "restart", Message: "bridge restarting"})
+	want = "⚠️ **System Alert**\n\n**Type:** restart\n\nbridge restarting"
+	if got != want {
+		t.Errorf("system message = %q, want %q", got, want)
+	}
+}
+
+func TestSetTemplateOverrides(t *testing.T) {
+	n := NewNotifier(nil, Config{})
+
+	if err := n.SetTemplate(CategoryContainer, "Conteneur {{.ContainerName}} : {{.Reason}}"); err != nil {
+		t.Fatalf("SetTemplate(category) error = %v", err)
+	}
+	if err := n.SetTemplate("container_failed", "{{.Emoji}} {{.ContainerName}} en échec"); err != nil {
+		t.Fatalf("SetTemplate(type) error = %v", err)
+	}
+
+	failed := AlertData{Category: CategoryContainer, Type: "container_failed", Emoji: "❌", ContainerName: "agent", Reason: "exited"}
+	if got := n.render(failed); got != "❌ agent en échec" {
+		t.Errorf("type template = %q", got)
+	}
+
+	stopped := AlertData{Category: CategoryContainer, Type: "container_stopped", ContainerName: "agent", Reason: "manual"}
+	if got := n.render(stopped); got != "Conteneur agent : manual" {
+		t.Errorf("category template = %q", got)
+	}
+
+	// Removing the type override falls back to the category template
+	if err := n.SetTemplate("container_failed", ""); err != nil {
+		t.Fatalf("SetTemplate(empty) error = %v", err)
+	}
+	if got := n.render(failed); got != "Conteneur agent : exited" {
+		t.Errorf("after removal = %q", got)
+	}
+
+	// Removing the category override restores the built-in message
+	if err := n.SetTemplate(CategoryContainer, ""); err != nil {
+		t.Fatalf("SetTemplate(empty) error = %v", err)
+	}
+	if got := n.render(failed); !strings.HasPrefix(got, "❌ **Container Event**") {
+		t.Errorf("after category removal = %q", got)
+	}
+}
+
+func TestSetTemplateValidation(t *testing.T) {
+	n := NewNotifier(nil, Config{})
+
+	tests := []struct {
+		name      string
+		alertType string
+		tmpl      string
+	}{
+		{"missing alert type", "", "{{.Type}}"},
+		{"parse error", CategoryBudget, "{{.Type"},
+		{"unknown field", CategoryBudget, "{{.Sesion}}"},
+		{"unknown function", CategoryBudget, "{{upper .Type}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := n.SetTemplate(tt.alertType, tt.tmpl); err == nil {
+				t.Error("SetTemplate() error = nil, want error")
+			}
+		})
+	}
+
+	// A rejected template must not replace the built-in default
+	got := n.render(AlertData{Category: CategoryBudget, Type: "warning", Limit: 10})
+	if !strings.HasPrefix(got, "🔔 **Budget Alert**") {
+		t.Errorf("render after rejected template = %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -31,6 +32,37 @@ var (
 // DefaultApprovalTimeout is the default time to wait for user approval
 const DefaultApprovalTimeout = 60 * time.Second
 
+// TimeoutDecider is recorded as the approver or rejecter of requests
+// decided by their timeout action
+const TimeoutDecider = "system:timeout"
+
+// TimeoutAction is the decision applied to a request nobody answered in time
+type TimeoutAction string
+
+const (
+	// TimeoutActionExpire marks the request expired (the default)
+	TimeoutActionExpire TimeoutAction = "expire"
+
+	// TimeoutActionApprove approves the request's required fields only
+	TimeoutActionApprove TimeoutAction = "approve"
+
+	// TimeoutActionReject rejects the request
+	TimeoutActionReject TimeoutAction = "reject"
+
+	// TimeoutActionEscalate notifies the escalation callback and gives the
+	// request one more timeout period; if still unanswered it is rejected
+	TimeoutActionEscalate TimeoutAction = "escalate"
+)
+
+// IsValid reports whether the action is one of the known timeout actions
+func (a TimeoutAction) IsValid() bool {
+	switch a {
+	case TimeoutActionExpire, TimeoutActionApprove, TimeoutActionReject, TimeoutActionEscalate:
+		return true
+	}
+	return false
+}
+
 // AccessRequest represents a pending PII access request
 type AccessRequest struct {
 	// ID is the unique identifier for this request
@@ -91,19 +123,44 @@ type AccessRequest struct {
 	// This is populated when the request includes card_number, card_cvv, or card_expiry
 	PCIWarnings []map[string]string `json:"pci_warnings,omitempty"`
 
+	// TimeoutAction is the decision applied when ExpiresAt passes unanswered
+	TimeoutAction TimeoutAction `json:"timeout_action"`
+
+	// EscalatedAt is when the request was escalated (if applicable)
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+
+	// AutoDecided is true when the outcome came from the timeout action
+	AutoDecided bool `json:"auto_decided,omitempty"`
+
 	// approvalChan is used to signal approval completion
 	approvalChan chan approvalResult `json:"-"`
 
+	// decided is set once a result has been sent on approvalChan
+	decided bool `json:"-"`
+
+	// timer fires the timeout warning and the timeout action
+	timer *time.Timer `json:"-"`
+
+	// warned is set once the timeout warning was sent for the current deadline
+	warned bool `json:"-"`
+
 	// mu protects concurrent access
 	mu sync.RWMutex `json:"-"`
 }
 
 // approvalResult carries the result of an approval decision
 type approvalResult struct {
-	approved      bool
-	approvedBy    string
+	approved       bool
+	approvedBy     string
 	approvedFields []string
-	reason        string
+	reason         string
+
+	// expired is set when the request ran out of time without a decision
+	expired bool
+
+	// auto is set when the timeout action produced the result; the
+	// request has already been updated by the time it is received
+	auto bool
 }
 
 // AccessRequestStatus represents the status of an access request
@@ -128,18 +185,65 @@ func (r *AccessRequest) IsPending() bool {
 	return r.Status == StatusPending
 }
 
+// decide queues a result for WaitForApproval. It reports false if the
+// request already has a decision. The caller must hold r.mu.
+func (r *AccessRequest) decide(result approvalResult) bool {
+	if r.decided {
+		return false
+	}
+	r.decided = true
+	r.approvalChan <- result
+	return true
+}
+
+// snapshot copies r's exported fields, for use once r.mu is released. The
+// caller must hold r.mu.
+func (r *AccessRequest) snapshot() *AccessRequest {
+	return &AccessRequest{
+		ID:              r.ID,
+		SkillID:         r.SkillID,
+		SkillName:       r.SkillName,
+		ProfileID:       r.ProfileID,
+		RequestedFields: append([]string(nil), r.RequestedFields...),
+		RequiredFields:  append([]string(nil), r.RequiredFields...),
+		Status:          r.Status,
+		CreatedAt:       r.CreatedAt,
+		ExpiresAt:       r.ExpiresAt,
+		ApprovedAt:      r.ApprovedAt,
+		ApprovedBy:      r.ApprovedBy,
+		ApprovedFields:  append([]string(nil), r.ApprovedFields...),
+		RejectedAt:      r.RejectedAt,
+		RejectedBy:      r.RejectedBy,
+		RejectionReason: r.RejectionReason,
+		RoomID:          r.RoomID,
+		EventID:         r.EventID,
+		Manifest:        r.Manifest,
+		PCIWarnings:     r.PCIWarnings,
+		TimeoutAction:   r.TimeoutAction,
+		EscalatedAt:     r.EscalatedAt,
+		AutoDecided:     r.AutoDecided,
+	}
+}
+
 // HITLConsentManager manages Human-in-the-Loop consent for PII access.
 // It coordinates consent requests between skills, the Matrix interface, and users.
 type HITLConsentManager struct {
-	requests     map[string]*AccessRequest
-	mu           sync.RWMutex
-	timeout      time.Duration
-	auditLogger  *audit.CriticalOperationLogger
-	securityLog  *logger.SecurityLogger
-	log          *logger.Logger
+	requests          map[string]*AccessRequest
+	mu                sync.RWMutex
+	timeout           time.Duration
+	timeoutAction     TimeoutAction
+	warnBefore        time.Duration
+	escalationTimeout time.Duration
+	auditLogger       *audit.CriticalOperationLogger
+	securityLog       *logger.SecurityLogger
+	log               *logger.Logger
 
 	// Notification callback for sending consent requests to users
 	notifyCallback func(ctx context.Context, request *AccessRequest) error
+
+	// Admin callbacks for requests that are about to time out or were escalated
+	timeoutWarningCallback func(ctx context.Context, request *AccessRequest) error
+	escalationCallback     func(ctx context.Context, request *AccessRequest) error
 }
 
 // HITLConfig configures the HITL consent manager
@@ -155,6 +259,35 @@ type HITLConfig struct {
 
 	// NotifyCallback is called to send consent request notifications
 	NotifyCallback func(ctx context.Context, request *AccessRequest) error
+
+	// TimeoutAction is applied when a request is not answered in time
+	// (default: TimeoutActionExpire)
+	TimeoutAction TimeoutAction
+
+	// WarnBefore is how long before the deadline TimeoutWarningCallback is
+	// called; zero disables the warning
+	WarnBefore time.Duration
+
+	// TimeoutWarningCallback notifies an admin that a request is about to
+	// time out. It and EscalationCallback get a copy of the request taken
+	// when the callback was due, so they need no locking.
+	TimeoutWarningCallback func(ctx context.Context, request *AccessRequest) error
+
+	// EscalationTimeout is how long an escalated request waits before it is
+	// rejected (default: Timeout)
+	EscalationTimeout time.Duration
+
+	// EscalationCallback notifies an admin that a request was escalated
+	EscalationCallback func(ctx context.Context, request *AccessRequest) error
+}
+
+// RequestOptions overrides the manager defaults for a single access request
+type RequestOptions struct {
+	// Timeout is how long to wait for approval (default: manager timeout)
+	Timeout time.Duration
+
+	// TimeoutAction is applied when Timeout elapses (default: manager action)
+	TimeoutAction TimeoutAction
 }
 
 // NewHITLConsentManager creates a new HITL consent manager
@@ -162,14 +295,25 @@ func NewHITLConsentManager(cfg HITLConfig) *HITLConsentManager {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = DefaultApprovalTimeout
 	}
+	if !cfg.TimeoutAction.IsValid() {
+		cfg.TimeoutAction = TimeoutActionExpire
+	}
+	if cfg.EscalationTimeout == 0 {
+		cfg.EscalationTimeout = cfg.Timeout
+	}
 
 	return &HITLConsentManager{
-		requests:      make(map[string]*AccessRequest),
-		timeout:       cfg.Timeout,
-		auditLogger:   cfg.AuditLogger,
-		securityLog:   cfg.SecurityLog,
-		log:           logger.Global().WithComponent("hitl_consent"),
-		notifyCallback: cfg.NotifyCallback,
+		requests:               make(map[string]*AccessRequest),
+		timeout:                cfg.Timeout,
+		timeoutAction:          cfg.TimeoutAction,
+		warnBefore:             cfg.WarnBefore,
+		escalationTimeout:      cfg.EscalationTimeout,
+		auditLogger:            cfg.AuditLogger,
+		securityLog:            cfg.SecurityLog,
+		log:                    logger.Global().WithComponent("hitl_consent"),
+		notifyCallback:         cfg.NotifyCallback,
+		timeoutWarningCallback: cfg.TimeoutWarningCallback,
+		escalationCallback:     cfg.EscalationCallback,
 	}
 }
 
@@ -180,6 +324,20 @@ func (m *HITLConsentManager) SetNotifyCallback(callback func(ctx context.Context
 	m.notifyCallback = callback
 }
 
+// SetTimeoutWarningCallback sets the callback for requests about to time out
+func (m *HITLConsentManager) SetTimeoutWarningCallback(callback func(ctx context.Context, request *AccessRequest) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeoutWarningCallback = callback
+}
+
+// SetEscalationCallback sets the callback for escalated requests
+func (m *HITLConsentManager) SetEscalationCallback(callback func(ctx context.Context, request *AccessRequest) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.escalationCallback = callback
+}
+
 // RequestAccess creates a new PII access request and sends notification
 func (m *HITLConsentManager) RequestAccess(
 	ctx context.Context,
@@ -187,6 +345,31 @@ func (m *HITLConsentManager) RequestAccess(
 	profileID string,
 	roomID string,
 ) (*AccessRequest, error) {
+	return m.RequestAccessWithOptions(ctx, manifest, profileID, roomID, RequestOptions{})
+}
+
+// RequestAccessWithOptions creates a new PII access request with its own
+// timeout and timeout action, and sends notification
+func (m *HITLConsentManager) RequestAccessWithOptions(
+	ctx context.Context,
+	manifest *SkillManifest,
+	profileID string,
+	roomID string,
+	opts RequestOptions,
+) (*AccessRequest, error) {
+	if opts.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout %s", opts.Timeout)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = m.timeout
+	}
+	if opts.TimeoutAction == "" {
+		opts.TimeoutAction = m.timeoutAction
+	}
+	if !opts.TimeoutAction.IsValid() {
+		return nil, fmt.Errorf("invalid timeout action %q", opts.TimeoutAction)
+	}
+
 	// Generate unique request ID
 	requestID := GenerateRequestID()
 
@@ -200,17 +383,22 @@ func (m *HITLConsentManager) RequestAccess(
 		RequiredFields:  manifest.GetRequiredFields(),
 		Status:          StatusPending,
 		CreatedAt:       time.Now(),
-		ExpiresAt:       time.Now().Add(m.timeout),
+		ExpiresAt:       time.Now().Add(opts.Timeout),
 		RoomID:          roomID,
 		Manifest:        manifest,
+		TimeoutAction:   opts.TimeoutAction,
 		approvalChan:    make(chan approvalResult, 1),
 	}
 
-	// Store the request
+	// Store the request and start its deadline
 	m.mu.Lock()
 	m.requests[requestID] = request
 	m.mu.Unlock()
 
+	request.mu.Lock()
+	m.armTimer(request)
+	request.mu.Unlock()
+
 	// Log the access request
 	m.log.Info("pii_access_requested",
 		"request_id", requestID,
@@ -219,6 +407,7 @@ func (m *HITLConsentManager) RequestAccess(
 		"profile_id", profileID,
 		"requested_fields", request.RequestedFields,
 		"required_fields", request.RequiredFields,
+		"timeout_action", string(request.TimeoutAction),
 	)
 
 	// Audit logging
@@ -245,7 +434,8 @@ func (m *HITLConsentManager) RequestAccess(
 	return request, nil
 }
 
-// WaitForApproval blocks until the request is approved, rejected, or expires
+// WaitForApproval blocks until the request is approved, rejected, or expires.
+// The request's timeout action decides it if nobody answers before ExpiresAt.
 func (m *HITLConsentManager) WaitForApproval(ctx context.Context, requestID string) (*AccessRequest, error) {
 	m.mu.RLock()
 	request, exists := m.requests[requestID]
@@ -255,70 +445,254 @@ func (m *HITLConsentManager) WaitForApproval(ctx context.Context, requestID stri
 		return nil, ErrRequestNotFound
 	}
 
-	// Create timeout context
-	timeoutCtx, cancel := context.WithTimeout(ctx, m.timeout)
-	defer cancel()
+	// A request decided before we started waiting has nothing left to receive
+	request.mu.RLock()
+	status := request.Status
+	request.mu.RUnlock()
+	if status != StatusPending {
+		return decidedRequest(request, status)
+	}
 
-	// Wait for approval, rejection, or timeout
+	// Wait for approval, rejection, or the timeout action
 	select {
 	case result := <-request.approvalChan:
 		request.mu.Lock()
 		defer request.mu.Unlock()
 
-		if result.approved {
-			request.Status = StatusApproved
-			now := time.Now()
-			request.ApprovedAt = &now
-			request.ApprovedBy = result.approvedBy
-			request.ApprovedFields = result.approvedFields
+		if !result.auto {
+			m.applyResult(ctx, request, result)
+		}
 
-			m.log.Info("pii_access_approved",
-				"request_id", requestID,
-				"approved_by", result.approvedBy,
-				"approved_fields", result.approvedFields,
-			)
+		return decidedRequest(request, request.Status)
 
-			if m.auditLogger != nil {
-				_ = m.auditLogger.LogPIIAccessGranted(ctx, requestID, request.SkillID, result.approvedBy, result.approvedFields)
-			}
-		} else {
-			request.Status = StatusRejected
-			now := time.Now()
-			request.RejectedAt = &now
-			request.RejectedBy = result.approvedBy
-			request.RejectionReason = result.reason
-
-			m.log.Info("pii_access_rejected",
-				"request_id", requestID,
-				"rejected_by", result.approvedBy,
-				"reason", result.reason,
-			)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
 
-			if m.auditLogger != nil {
-				_ = m.auditLogger.LogPIIAccessRejected(ctx, requestID, request.SkillID, result.approvedBy, result.reason)
-			}
-		}
+// decidedRequest maps a decided request to WaitForApproval's return values
+func decidedRequest(request *AccessRequest, status AccessRequestStatus) (*AccessRequest, error) {
+	if status == StatusExpired {
+		return nil, ErrApprovalTimeout
+	}
+	return request, nil
+}
 
-		return request, nil
+// applyResult records a decision on the request. The caller must hold request.mu.
+func (m *HITLConsentManager) applyResult(ctx context.Context, request *AccessRequest, result approvalResult) {
+	now := time.Now()
+	request.AutoDecided = result.auto
+	if request.timer != nil {
+		request.timer.Stop()
+	}
 
-	case <-timeoutCtx.Done():
-		request.mu.Lock()
+	switch {
+	case result.expired:
 		request.Status = StatusExpired
-		request.mu.Unlock()
 
 		m.log.Warn("pii_access_expired",
-			"request_id", requestID,
+			"request_id", request.ID,
 		)
 
 		if m.auditLogger != nil {
-			_ = m.auditLogger.LogPIIAccessExpired(ctx, requestID, request.SkillID)
+			_ = m.auditLogger.LogPIIAccessExpired(ctx, request.ID, request.SkillID)
 		}
 
-		return nil, ErrApprovalTimeout
+	case result.approved:
+		request.Status = StatusApproved
+		request.ApprovedAt = &now
+		request.ApprovedBy = result.approvedBy
+		request.ApprovedFields = result.approvedFields
 
-	case <-ctx.Done():
-		return nil, ctx.Err()
+		m.log.Info("pii_access_approved",
+			"request_id", request.ID,
+			"approved_by", result.approvedBy,
+			"approved_fields", result.approvedFields,
+		)
+
+		if m.auditLogger != nil {
+			_ = m.auditLogger.LogPIIAccessGranted(ctx, request.ID, request.SkillID, result.approvedBy, result.approvedFields)
+		}
+
+	default:
+		request.Status = StatusRejected
+		request.RejectedAt = &now
+		request.RejectedBy = result.approvedBy
+		request.RejectionReason = result.reason
+
+		m.log.Info("pii_access_rejected",
+			"request_id", request.ID,
+			"rejected_by", result.approvedBy,
+			"reason", result.reason,
+		)
+
+		if m.auditLogger != nil {
+			_ = m.auditLogger.LogPIIAccessRejected(ctx, request.ID, request.SkillID, result.approvedBy, result.reason)
+		}
+	}
+
+	if result.auto && m.securityLog != nil {
+		m.securityLog.LogHITLTimeout(ctx, request.ID, request.SkillName,
+			slog.String("timeout_action", string(request.TimeoutAction)),
+			slog.String("status", string(request.Status)),
+		)
+	}
+}
+
+// ExtendRequest moves a pending request's deadline back by the given duration
+// and returns the new deadline
+func (m *HITLConsentManager) ExtendRequest(requestID string, extension time.Duration) (time.Time, error) {
+	if extension <= 0 {
+		return time.Time{}, fmt.Errorf("invalid extension %s", extension)
+	}
+
+	m.mu.RLock()
+	request, exists := m.requests[requestID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return time.Time{}, ErrRequestNotFound
+	}
+
+	request.mu.Lock()
+	defer request.mu.Unlock()
+
+	if request.Status != StatusPending || request.decided {
+		return time.Time{}, ErrRequestAlreadyProcessed
+	}
+
+	request.ExpiresAt = request.ExpiresAt.Add(extension)
+	request.warned = false
+	m.armTimer(request)
+
+	m.log.Info("pii_access_extended",
+		"request_id", requestID,
+		"expires_at", request.ExpiresAt,
+	)
+
+	return request.ExpiresAt, nil
+}
+
+// armTimer schedules the next timeout warning or deadline for a request.
+// The caller must hold request.mu.
+func (m *HITLConsentManager) armTimer(request *AccessRequest) {
+	if request.timer != nil {
+		request.timer.Stop()
+	}
+
+	at := request.ExpiresAt
+	if warnAt := at.Add(-m.warnBefore); m.warnBefore > 0 && !request.warned && time.Now().Before(warnAt) {
+		at = warnAt
+	}
+
+	request.timer = time.AfterFunc(time.Until(at), func() {
+		m.handleTimer(request)
+	})
+}
+
+// handleTimer sends the timeout warning or applies the timeout action,
+// whichever is due
+func (m *HITLConsentManager) handleTimer(request *AccessRequest) {
+	ctx := context.Background()
+
+	request.mu.Lock()
+	if request.Status != StatusPending || request.decided {
+		request.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+
+	// Not yet expired: either the warning point or a deadline that was extended
+	if now.Before(request.ExpiresAt) {
+		warn := m.warnBefore > 0 && !request.warned && !now.Before(request.ExpiresAt.Add(-m.warnBefore))
+		if warn {
+			request.warned = true
+		}
+		m.armTimer(request)
+		snap := request.snapshot()
+		request.mu.Unlock()
+
+		if warn {
+			m.log.Warn("pii_access_expiring",
+				"request_id", snap.ID,
+				"expires_at", snap.ExpiresAt,
+				"timeout_action", string(snap.TimeoutAction),
+			)
+			m.runAdminCallback(ctx, "timeout_warning", m.getTimeoutWarningCallback(), snap)
+		}
+		return
 	}
+
+	// First expiry of an escalating request: hand it to an admin for one more period
+	if request.TimeoutAction == TimeoutActionEscalate && request.EscalatedAt == nil {
+		request.EscalatedAt = &now
+		request.ExpiresAt = now.Add(m.escalationTimeout)
+		request.warned = false
+		m.armTimer(request)
+		snap := request.snapshot()
+		request.mu.Unlock()
+
+		m.log.Warn("pii_access_escalated",
+			"request_id", snap.ID,
+			"expires_at", snap.ExpiresAt,
+		)
+		m.runAdminCallback(ctx, "escalation", m.getEscalationCallback(), snap)
+		return
+	}
+
+	result := timeoutResult(request)
+	request.decide(result)
+	m.applyResult(ctx, request, result)
+	request.mu.Unlock()
+}
+
+// timeoutResult builds the decision for a request whose deadline passed.
+// Escalated requests that are still unanswered are rejected.
+func timeoutResult(request *AccessRequest) approvalResult {
+	switch request.TimeoutAction {
+	case TimeoutActionApprove:
+		return approvalResult{
+			approved:       true,
+			approvedBy:     TimeoutDecider,
+			approvedFields: append([]string(nil), request.RequiredFields...),
+			auto:           true,
+		}
+	case TimeoutActionReject, TimeoutActionEscalate:
+		return approvalResult{
+			approvedBy: TimeoutDecider,
+			reason:     "no decision before the approval deadline",
+			auto:       true,
+		}
+	default:
+		return approvalResult{expired: true, auto: true}
+	}
+}
+
+// runAdminCallback calls an admin notification callback and logs failures
+func (m *HITLConsentManager) runAdminCallback(ctx context.Context, kind string, callback func(ctx context.Context, request *AccessRequest) error, request *AccessRequest) {
+	if callback == nil {
+		return
+	}
+	if err := callback(ctx, request); err != nil {
+		m.log.Error("consent_admin_notification_failed",
+			"request_id", request.ID,
+			"kind", kind,
+			"error", err.Error(),
+		)
+	}
+}
+
+func (m *HITLConsentManager) getTimeoutWarningCallback() func(ctx context.Context, request *AccessRequest) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.timeoutWarningCallback
+}
+
+func (m *HITLConsentManager) getEscalationCallback() func(ctx context.Context, request *AccessRequest) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.escalationCallback
 }
 
 // ApproveRequest approves an access request with specific fields
@@ -363,14 +737,11 @@ func (m *HITLConsentManager) ApproveRequest(
 	}
 
 	// Send approval result
-	select {
-	case request.approvalChan <- approvalResult{
+	if !request.decide(approvalResult{
 		approved:       true,
 		approvedBy:     userID,
 		approvedFields: approvedFields,
-	}:
-	default:
-		// Channel full - request might have been processed
+	}) {
 		return ErrRequestAlreadyProcessed
 	}
 
@@ -407,14 +778,11 @@ func (m *HITLConsentManager) RejectRequest(
 	}
 
 	// Send rejection result
-	select {
-	case request.approvalChan <- approvalResult{
-		approved: false,
+	if !request.decide(approvalResult{
+		approved:   false,
 		approvedBy: userID,
-		reason:    reason,
-	}:
-	default:
-		// Channel full - request might have been processed
+		reason:     reason,
+	}) {
 		return ErrRequestAlreadyProcessed
 	}
 
@@ -519,6 +887,9 @@ func (m *HITLConsentManager) CleanupExpired() int {
 	for id, req := range m.requests {
 		req.mu.RLock()
 		if req.Status != StatusPending || req.IsExpired() {
+			if req.timer != nil {
+				req.timer.Stop()
+			}
 			delete(m.requests, id)
 			count++
 		}
@@ -367,6 +367,245 @@ func TestHITLConsentManager_WaitForApproval_Timeout(t *testing.T) {
 	}
 }
 
+func TestHITLConsentManager_TimeoutAction(t *testing.T) {
+	manifest := NewSkillManifest("skill-123", "Test Skill", []VariableRequest{
+		{Key: "full_name", Description: "Your name", Required: true},
+		{Key: "email", Description: "Your email", Required: false},
+	})
+
+	t.Run("approve grants required fields only", func(t *testing.T) {
+		manager := NewHITLConsentManager(HITLConfig{
+			Timeout:       50 * time.Millisecond,
+			TimeoutAction: TimeoutActionApprove,
+		})
+
+		request, _ := manager.RequestAccess(context.Background(), manifest, "profile-456", "!room:matrix.example.com")
+
+		result, err := manager.WaitForApproval(context.Background(), request.ID)
+		if err != nil {
+			t.Fatalf("WaitForApproval failed: %v", err)
+		}
+		if result.Status != StatusApproved || !result.AutoDecided {
+			t.Errorf("Expected auto-approved request, got status '%s' (auto=%v)", result.Status, result.AutoDecided)
+		}
+		if result.ApprovedBy != TimeoutDecider {
+			t.Errorf("Expected approved_by %q, got %q", TimeoutDecider, result.ApprovedBy)
+		}
+		if len(result.ApprovedFields) != 1 || result.ApprovedFields[0] != "full_name" {
+			t.Errorf("Expected only required fields approved, got %v", result.ApprovedFields)
+		}
+	})
+
+	t.Run("reject fires without a waiter", func(t *testing.T) {
+		manager := NewHITLConsentManager(HITLConfig{
+			Timeout: 50 * time.Millisecond,
+		})
+
+		request, _ := manager.RequestAccessWithOptions(context.Background(), manifest, "profile-456", "!room:matrix.example.com", RequestOptions{
+			TimeoutAction: TimeoutActionReject,
+		})
+
+		time.Sleep(100 * time.Millisecond)
+
+		if request.IsPending() {
+			t.Fatal("Request should have been decided by its timeout action")
+		}
+		request.mu.RLock()
+		status, rejectedBy := request.Status, request.RejectedBy
+		request.mu.RUnlock()
+		if status != StatusRejected || rejectedBy != TimeoutDecider {
+			t.Errorf("Expected rejection by %q, got status '%s' by %q", TimeoutDecider, status, rejectedBy)
+		}
+
+		err := manager.ApproveRequest(context.Background(), request.ID, "@user:matrix.example.com", []string{"full_name"})
+		if !errors.Is(err, ErrRequestAlreadyProcessed) {
+			t.Errorf("Expected ErrRequestAlreadyProcessed after timeout, got: %v", err)
+		}
+
+		result, err := manager.WaitForApproval(context.Background(), request.ID)
+		if err != nil || result.Status != StatusRejected {
+			t.Errorf("Expected rejected request from late WaitForApproval, got %v, %v", result, err)
+		}
+	})
+
+	t.Run("escalate notifies and then rejects", func(t *testing.T) {
+		escalated := make(chan string, 1)
+		manager := NewHITLConsentManager(HITLConfig{
+			Timeout:           50 * time.Millisecond,
+			TimeoutAction:     TimeoutActionEscalate,
+			EscalationTimeout: 50 * time.Millisecond,
+			EscalationCallback: func(ctx context.Context, request *AccessRequest) error {
+				escalated <- request.ID
+				return nil
+			},
+		})
+
+		request, _ := manager.RequestAccess(context.Background(), manifest, "profile-456", "!room:matrix.example.com")
+
+		select {
+		case id := <-escalated:
+			if id != request.ID {
+				t.Errorf("Escalated request %q, want %q", id, request.ID)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("EscalationCallback was not called")
+		}
+
+		result, err := manager.WaitForApproval(context.Background(), request.ID)
+		if err != nil {
+			t.Fatalf("WaitForApproval failed: %v", err)
+		}
+		if result.Status != StatusRejected || result.EscalatedAt == nil {
+			t.Errorf("Expected escalated then rejected request, got status '%s'", result.Status)
+		}
+	})
+
+	t.Run("escalated request can still be approved", func(t *testing.T) {
+		escalated := make(chan struct{}, 1)
+		manager := NewHITLConsentManager(HITLConfig{
+			Timeout:           50 * time.Millisecond,
+			TimeoutAction:     TimeoutActionEscalate,
+			EscalationTimeout: 5 * time.Second,
+			EscalationCallback: func(ctx context.Context, request *AccessRequest) error {
+				escalated <- struct{}{}
+				return nil
+			},
+		})
+
+		request, _ := manager.RequestAccess(context.Background(), manifest, "profile-456", "!room:matrix.example.com")
+		<-escalated
+
+		if err := manager.ApproveRequest(context.Background(), request.ID, "@admin:matrix.example.com", []string{"full_name"}); err != nil {
+			t.Fatalf("ApproveRequest after escalation failed: %v", err)
+		}
+
+		result, err := manager.WaitForApproval(context.Background(), request.ID)
+		if err != nil {
+			t.Fatalf("WaitForApproval failed: %v", err)
+		}
+		if result.Status != StatusApproved || result.AutoDecided {
+			t.Errorf("Expected manual approval, got status '%s' (auto=%v)", result.Status, result.AutoDecided)
+		}
+	})
+
+	t.Run("invalid action is refused", func(t *testing.T) {
+		manager := NewHITLConsentManager(HITLConfig{})
+
+		_, err := manager.RequestAccessWithOptions(context.Background(), manifest, "profile-456", "!room:matrix.example.com", RequestOptions{
+			TimeoutAction: "ignore",
+		})
+		if err == nil {
+			t.Error("Expected error for unknown timeout action")
+		}
+	})
+}
+
+func TestHITLConsentManager_TimeoutWarning(t *testing.T) {
+	warned := make(chan time.Time, 1)
+	manager := NewHITLConsentManager(HITLConfig{
+		Timeout:    200 * time.Millisecond,
+		WarnBefore: 150 * time.Millisecond,
+		TimeoutWarningCallback: func(ctx context.Context, request *AccessRequest) error {
+			warned <- time.Now()
+			return nil
+		},
+	})
+
+	manifest := NewSkillManifest("skill-123", "Test Skill", []VariableRequest{
+		{Key: "full_name", Description: "Your name", Required: true},
+	})
+
+	request, _ := manager.RequestAccess(context.Background(), manifest, "profile-456", "!room:matrix.example.com")
+
+	select {
+	case at := <-warned:
+		if !at.Before(request.ExpiresAt) {
+			t.Error("Warning should be sent before the request expires")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TimeoutWarningCallback was not called")
+	}
+
+	if _, err := manager.WaitForApproval(context.Background(), request.ID); !errors.Is(err, ErrApprovalTimeout) {
+		t.Errorf("Expected ErrApprovalTimeout, got: %v", err)
+	}
+
+	select {
+	case <-warned:
+		t.Error("Warning should be sent only once per deadline")
+	default:
+	}
+}
+
+func TestHITLConsentManager_TimeoutWarningGetsCopy(t *testing.T) {
+	type seen struct{ before, after time.Time }
+	warned := make(chan seen, 1)
+
+	var manager *HITLConsentManager
+	manager = NewHITLConsentManager(HITLConfig{
+		Timeout:    200 * time.Millisecond,
+		WarnBefore: 150 * time.Millisecond,
+		TimeoutWarningCallback: func(ctx context.Context, request *AccessRequest) error {
+			// An extension while the callback runs must not change its copy
+			before := request.ExpiresAt
+			manager.ExtendRequest(request.ID, time.Second)
+			warned <- seen{before, request.ExpiresAt}
+			return nil
+		},
+	})
+
+	manifest := NewSkillManifest("skill-123", "Test Skill", []VariableRequest{
+		{Key: "full_name", Description: "Your name", Required: true},
+	})
+	manager.RequestAccess(context.Background(), manifest, "profile-456", "!room:matrix.example.com")
+
+	select {
+	case s := <-warned:
+		if !s.after.Equal(s.before) {
+			t.Errorf("callback's ExpiresAt changed from %v to %v", s.before, s.after)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TimeoutWarningCallback was not called")
+	}
+}
+
+func TestHITLConsentManager_ExtendRequest(t *testing.T) {
+	manager := NewHITLConsentManager(HITLConfig{
+		Timeout: 100 * time.Millisecond,
+	})
+
+	manifest := NewSkillManifest("skill-123", "Test Skill", []VariableRequest{
+		{Key: "full_name", Description: "Your name", Required: true},
+	})
+
+	request, _ := manager.RequestAccess(context.Background(), manifest, "profile-456", "!room:matrix.example.com")
+	original := request.ExpiresAt
+
+	deadline, err := manager.ExtendRequest(request.ID, 5*time.Second)
+	if err != nil {
+		t.Fatalf("ExtendRequest failed: %v", err)
+	}
+	if !deadline.Equal(original.Add(5 * time.Second)) {
+		t.Errorf("Expected deadline %v, got %v", original.Add(5*time.Second), deadline)
+	}
+
+	// The original deadline passes without firing the timeout action
+	time.Sleep(150 * time.Millisecond)
+	if !request.IsPending() {
+		t.Fatal("Extended request should still be pending")
+	}
+
+	if err := manager.ApproveRequest(context.Background(), request.ID, "@user:matrix.example.com", []string{"full_name"}); err != nil {
+		t.Fatalf("ApproveRequest failed: %v", err)
+	}
+	if _, err := manager.ExtendRequest(request.ID, time.Second); !errors.Is(err, ErrRequestAlreadyProcessed) {
+		t.Errorf("Expected ErrRequestAlreadyProcessed for decided request, got: %v", err)
+	}
+	if _, err := manager.ExtendRequest("nonexistent", time.Second); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("Expected ErrRequestNotFound, got: %v", err)
+	}
+}
+
 func TestHITLConsentManager_RequestAccessAndWait(t *testing.T) {
 	manager := NewHITLConsentManager(HITLConfig{
 		Timeout: 5 * time.Second,
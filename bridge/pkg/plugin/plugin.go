@@ -13,9 +13,12 @@ import (
 	"time"
 
 	"github.com/armorclaw/bridge/internal/sdtw"
+	"github.com/armorclaw/bridge/pkg/logger"
 )
 
-// PluginVersion defines the current plugin API version
+// PluginAPIVersion is the plugin ABI version this bridge provides. Bump the
+// minor version (major once at 1.0+) whenever PluginInterface, PluginConfig
+// or the types they expose change; see CheckAPIVersion.
 const PluginAPIVersion = "0.2.0"
 
 // PluginType defines the type of plugin
@@ -32,7 +35,7 @@ type PluginMetadata struct {
 	// Required fields
 	Name         string     `json:"name"`          // Unique plugin name (e.g., "telegram-adapter")
 	Version      string     `json:"version"`       // Plugin version (semver)
-	APIVersion   string     `json:"api_version"`   // Required API version (must match PluginAPIVersion)
+	APIVersion   string     `json:"api_version"`   // Plugin API version the plugin was built against (see CheckAPIVersion)
 	Type         PluginType `json:"type"`          // Plugin type
 	Description  string     `json:"description"`   // Human-readable description
 	Author       string     `json:"author"`        // Plugin author
@@ -45,6 +48,9 @@ type PluginMetadata struct {
 	// Dependencies
 	Dependencies []string   `json:"dependencies,omitempty"` // Required other plugins
 
+	// Privileges the plugin needs; each must be granted in PluginConfig
+	Requires CapabilityManifest `json:"requires,omitempty"`
+
 	// Configuration
 	ConfigSchema json.RawMessage `json:"config_schema,omitempty"` // JSON Schema for configuration
 }
@@ -63,8 +69,12 @@ type PluginConfig struct {
 	// Plugin-specific configuration (validated against ConfigSchema)
 	Config map[string]interface{} `json:"config,omitempty"`
 
-	// Credentials (injected from keystore)
+	// Credentials (injected from keystore; requires the keystore capability)
 	Credentials map[string]string `json:"credentials,omitempty"`
+
+	// Capabilities granted to the plugin. Loading fails if the plugin
+	// requests anything not granted here.
+	Capabilities CapabilityManifest `json:"capabilities,omitempty"`
 }
 
 // PluginState represents the current state of a plugin
@@ -86,6 +96,11 @@ type PluginInfo struct {
 	LastError string         `json:"last_error,omitempty"`
 	LoadTime  time.Time      `json:"load_time,omitempty"`
 	StartTime time.Time      `json:"start_time,omitempty"`
+
+	// Reloads counts hot reloads; LoadedPath is the versioned copy of the
+	// library currently in use (see ReloadPlugin)
+	Reloads    int    `json:"reloads,omitempty"`
+	LoadedPath string `json:"loaded_path,omitempty"`
 }
 
 // PluginInterface is the interface that all plugins must implement
@@ -117,9 +132,13 @@ type AdapterPlugin interface {
 
 // PluginManager manages plugin lifecycle
 type PluginManager struct {
-	mu      sync.RWMutex
-	plugins map[string]*loadedPlugin
-	config  ManagerConfig
+	mu          sync.RWMutex
+	plugins     map[string]*loadedPlugin
+	config      ManagerConfig
+	securityLog *logger.SecurityLogger
+
+	// openLibrary opens a shared library and returns its Plugin symbol
+	openLibrary func(path string) (*plugin.Plugin, PluginInterface, error)
 }
 
 // ManagerConfig configures the plugin manager
@@ -155,8 +174,10 @@ func NewPluginManager(config ManagerConfig) *PluginManager {
 	}
 
 	return &PluginManager{
-		plugins: make(map[string]*loadedPlugin),
-		config:  config,
+		plugins:     make(map[string]*loadedPlugin),
+		config:      config,
+		securityLog: logger.NewSecurityLogger(logger.Global().WithComponent("plugin")),
+		openLibrary: openLibrary,
 	}
 }
 
@@ -214,28 +235,34 @@ func (pm *PluginManager) LoadPlugin(config PluginConfig) error {
 		metadata = loadedMeta
 	}
 
-	// Verify API version
-	if metadata.APIVersion != "" && metadata.APIVersion != PluginAPIVersion {
-		return fmt.Errorf("plugin API version mismatch: plugin=%s, bridge=%s",
-			metadata.APIVersion, PluginAPIVersion)
+	// Verify API version before opening the library, if the file declares one
+	if metadata.APIVersion != "" {
+		if err := CheckAPIVersion(metadata.APIVersion); err != nil {
+			return fmt.Errorf("plugin %s: %w", config.LibraryPath, err)
+		}
+	}
+
+	// Verify requested capabilities before any plugin code is opened
+	if err := pm.checkManifest(config, metadata); err != nil {
+		return err
 	}
 
 	// Load the shared library
-	rawLib, err := plugin.Open(config.LibraryPath)
+	rawLib, pluginInstance, err := pm.openLibrary(config.LibraryPath)
 	if err != nil {
-		return fmt.Errorf("failed to load plugin library: %w", err)
+		return err
 	}
 
-	// Look up the plugin symbol
-	sym, err := rawLib.Lookup("Plugin")
-	if err != nil {
-		return fmt.Errorf("plugin does not export 'Plugin' symbol: %w", err)
+	// The compiled-in metadata is authoritative: a stale library can sit
+	// next to an updated metadata file
+	builtMeta := pluginInstance.Metadata()
+	if err := CheckAPIVersion(builtMeta.APIVersion); err != nil {
+		return fmt.Errorf("plugin %s: %w", config.LibraryPath, err)
 	}
 
-	// Type assertion to PluginInterface
-	pluginInstance, ok := sym.(PluginInterface)
-	if !ok {
-		return fmt.Errorf("plugin does not implement PluginInterface")
+	// The compiled-in metadata may request more than the metadata file
+	if err := pm.checkManifest(config, builtMeta); err != nil {
+		return err
 	}
 
 	// Store the loaded plugin
@@ -263,6 +290,14 @@ func (pm *PluginManager) InitializePlugin(name string, config PluginConfig) erro
 		return fmt.Errorf("plugin not found: %s", name)
 	}
 
+	// Grants are fixed at load time and cannot be widened on initialize
+	config.Capabilities = plugin.config.Capabilities
+	if len(config.Credentials) > 0 {
+		if err := pm.checkGranted(plugin, CapabilityKeystore, ""); err != nil {
+			return err
+		}
+	}
+
 	if err := plugin.instance.Initialize(context.Background(), config); err != nil {
 		plugin.info.State = PluginStateError
 		plugin.info.LastError = err.Error()
@@ -402,6 +437,28 @@ func (pm *PluginManager) HealthCheck() map[string]error {
 	return results
 }
 
+// openLibrary loads a shared library and looks up its Plugin symbol
+func openLibrary(path string) (*plugin.Plugin, PluginInterface, error) {
+	rawLib, err := plugin.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load plugin library: %w", err)
+	}
+
+	// Look up the plugin symbol
+	sym, err := rawLib.Lookup("Plugin")
+	if err != nil {
+		return nil, nil, fmt.Errorf("plugin does not export 'Plugin' symbol: %w", err)
+	}
+
+	// Type assertion to PluginInterface
+	pluginInstance, ok := sym.(PluginInterface)
+	if !ok {
+		return nil, nil, fmt.Errorf("plugin does not implement PluginInterface")
+	}
+
+	return rawLib, pluginInstance, nil
+}
+
 // loadMetadata loads plugin metadata from a JSON file
 func (pm *PluginManager) loadMetadata(path string) (PluginMetadata, error) {
 	var metadata PluginMetadata
@@ -0,0 +1,206 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// reloadDir holds the versioned library copies opened by ReloadPlugin. It
+// is created beside the plugin library.
+const reloadDir = ".reload"
+
+// ReloadPlugin replaces a loaded plugin with a fresh build of its library
+// without restarting the bridge. libraryPath optionally points at a new
+// library; by default the original path is re-read.
+//
+// Go cannot unload a plugin, and plugin.Open returns the already-loaded
+// plugin when given a path it has opened before. The new library is
+// therefore copied to a unique path under .reload/ and opened from there,
+// so the runtime treats it as a different file. The previous code stays
+// mapped in the process until exit; each reload leaks one library's worth
+// of memory. The runtime also refuses two plugins with the same plugin
+// path, so every build must set a unique one (for example
+// -ldflags=-pluginpath=telegram-1.4.2).
+//
+// The new instance is opened and checked before the old one is touched.
+// It is then initialized with the same config and credentials and started
+// if the old instance was running. If either step fails, the old instance
+// is restarted and stays active.
+func (pm *PluginManager) ReloadPlugin(name, libraryPath string) (*PluginInfo, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	old, exists := pm.plugins[name]
+	if !exists {
+		return nil, fmt.Errorf("plugin not found: %s", name)
+	}
+
+	config := old.config
+	if libraryPath != "" {
+		config.LibraryPath = libraryPath
+	}
+	if _, err := os.Stat(config.LibraryPath); err != nil {
+		return nil, fmt.Errorf("plugin library not found: %s", config.LibraryPath)
+	}
+
+	// Re-read metadata, which may have changed alongside the library
+	var metadata PluginMetadata
+	if config.MetadataPath != "" {
+		loadedMeta, err := pm.loadMetadata(config.MetadataPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metadata: %w", err)
+		}
+		metadata = loadedMeta
+		if metadata.APIVersion != "" {
+			if err := CheckAPIVersion(metadata.APIVersion); err != nil {
+				return nil, fmt.Errorf("plugin %s: %w", config.LibraryPath, err)
+			}
+		}
+		if err := pm.checkManifest(config, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	versioned, err := versionedCopy(config.LibraryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage plugin library: %w", err)
+	}
+
+	// A failed reload leaves nothing behind on disk; a library that was
+	// opened stays mapped, so removing its file is safe
+	swapped := false
+	defer func() {
+		if !swapped {
+			os.Remove(versioned)
+		}
+	}()
+
+	rawLib, instance, err := pm.openLibrary(versioned)
+	if err != nil {
+		if strings.Contains(err.Error(), "plugin already loaded") {
+			return nil, fmt.Errorf("%w (rebuild the plugin with a unique -pluginpath)", err)
+		}
+		return nil, err
+	}
+
+	built := instance.Metadata()
+	if err := CheckAPIVersion(built.APIVersion); err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", config.LibraryPath, err)
+	}
+	if err := pm.checkManifest(config, built); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	wasRunning := old.info.State == PluginStateRunning
+	wasInitialized := wasRunning || old.info.State == PluginStateInit
+
+	if wasRunning {
+		if err := old.instance.Stop(ctx); err != nil {
+			old.info.LastError = err.Error()
+			return nil, fmt.Errorf("failed to stop plugin: %w", err)
+		}
+	}
+
+	next := &loadedPlugin{
+		info: PluginInfo{
+			Metadata:   metadata,
+			State:      PluginStateLoaded,
+			LoadTime:   time.Now(),
+			Reloads:    old.info.Reloads + 1,
+			LoadedPath: versioned,
+		},
+		config:   config,
+		instance: instance,
+		rawLib:   rawLib,
+	}
+
+	if wasInitialized {
+		if err := instance.Initialize(ctx, config); err != nil {
+			return nil, pm.restoreAfterReload(old, wasRunning, fmt.Errorf("failed to initialize reloaded plugin: %w", err))
+		}
+		next.info.State = PluginStateInit
+	}
+	if wasRunning {
+		if err := instance.Start(ctx); err != nil {
+			return nil, pm.restoreAfterReload(old, wasRunning, fmt.Errorf("failed to start reloaded plugin: %w", err))
+		}
+		next.info.State = PluginStateRunning
+		next.info.StartTime = time.Now()
+	}
+
+	pm.plugins[name] = next
+	swapped = true
+
+	// The previous copy is already mapped, so its file is no longer needed
+	if old.info.LoadedPath != "" && old.info.LoadedPath != versioned {
+		os.Remove(old.info.LoadedPath)
+	}
+
+	pm.securityLog.LogSecurityEvent("plugin_reloaded",
+		slog.String("plugin", pluginLabel(metadata.Name, name)),
+		slog.String("library_path", config.LibraryPath),
+		slog.String("loaded_path", versioned),
+		slog.Int("reloads", next.info.Reloads))
+
+	info := next.info
+	return &info, nil
+}
+
+// restoreAfterReload restarts the previous instance after a failed reload
+// so the plugin keeps serving. Callers must hold pm.mu.
+func (pm *PluginManager) restoreAfterReload(old *loadedPlugin, wasRunning bool, cause error) error {
+	old.info.LastError = cause.Error()
+	if !wasRunning {
+		return cause
+	}
+
+	if err := old.instance.Start(context.Background()); err != nil {
+		old.info.State = PluginStateError
+		old.info.LastError = err.Error()
+		return fmt.Errorf("%w; restarting previous instance also failed: %v", cause, err)
+	}
+	old.info.StartTime = time.Now()
+	return cause
+}
+
+// versionedCopy copies a plugin library to a unique path under reloadDir,
+// e.g. plugins/.reload/telegram.1739612345678901234.so
+func versionedCopy(libraryPath string) (string, error) {
+	dir := filepath.Join(filepath.Dir(libraryPath), reloadDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(libraryPath)
+	stem := strings.TrimSuffix(filepath.Base(libraryPath), ext)
+	dst := filepath.Join(dir, fmt.Sprintf("%s.%d%s", stem, time.Now().UnixNano(), ext))
+
+	src, err := os.Open(libraryPath)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0700)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return "", err
+	}
+
+	return dst, nil
+}
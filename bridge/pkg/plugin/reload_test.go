@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	goplugin "plugin"
+)
+
+// reloadablePlugin is a stub that records lifecycle calls
+type reloadablePlugin struct {
+	stubPlugin
+	started, stopped int
+	initErr          error
+	startErr         error
+}
+
+func (p *reloadablePlugin) Initialize(ctx context.Context, config PluginConfig) error {
+	if p.initErr != nil {
+		return p.initErr
+	}
+	return p.stubPlugin.Initialize(ctx, config)
+}
+
+func (p *reloadablePlugin) Start(ctx context.Context) error {
+	if p.startErr != nil {
+		return p.startErr
+	}
+	p.started++
+	return nil
+}
+
+func (p *reloadablePlugin) Stop(ctx context.Context) error {
+	p.stopped++
+	return nil
+}
+
+// newReloadManager returns a manager with one running plugin whose library
+// lives in a temp dir, and an opener that returns next for any path
+func newReloadManager(t *testing.T, next *reloadablePlugin) (*PluginManager, *reloadablePlugin, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	libPath := filepath.Join(dir, "telegram.so")
+	if err := os.WriteFile(libPath, []byte("v1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pm := NewPluginManager(ManagerConfig{PluginDir: dir})
+	pm.openLibrary = func(path string) (*goplugin.Plugin, PluginInterface, error) {
+		if !strings.Contains(path, string(filepath.Separator)+reloadDir+string(filepath.Separator)) {
+			t.Errorf("reload opened %s, want a versioned copy", path)
+		}
+		return nil, next, nil
+	}
+
+	old := &reloadablePlugin{}
+	pm.plugins[libPath] = &loadedPlugin{
+		info: PluginInfo{State: PluginStateRunning},
+		config: PluginConfig{
+			LibraryPath:  libPath,
+			Config:       map[string]interface{}{"webhook_url": "https://example.com/hook"},
+			Credentials:  map[string]string{"bot_token": "secret"},
+			Capabilities: CapabilityManifest{Keystore: true},
+		},
+		instance: old,
+	}
+	return pm, old, libPath
+}
+
+func TestReloadPluginSwapsInstance(t *testing.T) {
+	next := &reloadablePlugin{stubPlugin: stubPlugin{metadata: PluginMetadata{APIVersion: PluginAPIVersion}}}
+	pm, old, libPath := newReloadManager(t, next)
+
+	info, err := pm.ReloadPlugin(libPath, "")
+	if err != nil {
+		t.Fatalf("ReloadPlugin() error = %v", err)
+	}
+
+	if old.stopped != 1 {
+		t.Errorf("old instance stopped %d times, want 1", old.stopped)
+	}
+	if next.started != 1 || info.State != PluginStateRunning {
+		t.Errorf("new instance started %d times, state %s; want running", next.started, info.State)
+	}
+	if next.config.Credentials["bot_token"] != "secret" || next.config.Config["webhook_url"] == nil {
+		t.Errorf("new instance initialized with %+v, want the previous config", next.config)
+	}
+	if info.Reloads != 1 || !strings.Contains(info.LoadedPath, reloadDir) {
+		t.Errorf("info = %+v, want first reload from a versioned path", info)
+	}
+	if _, err := os.Stat(info.LoadedPath); err != nil {
+		t.Errorf("versioned copy missing: %v", err)
+	}
+
+	// A second reload gets a new path and removes the previous copy
+	second, err := pm.ReloadPlugin(libPath, "")
+	if err != nil {
+		t.Fatalf("second ReloadPlugin() error = %v", err)
+	}
+	if second.LoadedPath == info.LoadedPath || second.Reloads != 2 {
+		t.Errorf("second reload = %+v, want a new versioned path", second)
+	}
+	if _, err := os.Stat(info.LoadedPath); !os.IsNotExist(err) {
+		t.Error("previous versioned copy should be removed")
+	}
+}
+
+func TestReloadPluginKeepsOldInstanceOnFailure(t *testing.T) {
+	next := &reloadablePlugin{
+		stubPlugin: stubPlugin{metadata: PluginMetadata{APIVersion: PluginAPIVersion}},
+		startErr:   errors.New("bind: address in use"),
+	}
+	pm, old, libPath := newReloadManager(t, next)
+
+	if _, err := pm.ReloadPlugin(libPath, ""); err == nil {
+		t.Fatal("ReloadPlugin() should fail when the new instance cannot start")
+	}
+
+	if old.started != 1 {
+		t.Errorf("old instance restarted %d times, want 1", old.started)
+	}
+	if pm.plugins[libPath].instance != old {
+		t.Error("failed reload replaced the active instance")
+	}
+	entries, _ := os.ReadDir(filepath.Join(filepath.Dir(libPath), reloadDir))
+	if len(entries) != 0 {
+		t.Errorf("failed reload left %d staged copies", len(entries))
+	}
+}
+
+func TestReloadPluginRejectsIncompatibleBuild(t *testing.T) {
+	next := &reloadablePlugin{stubPlugin: stubPlugin{metadata: PluginMetadata{APIVersion: "0.1.0"}}}
+	pm, old, libPath := newReloadManager(t, next)
+
+	_, err := pm.ReloadPlugin(libPath, "")
+	if !errors.Is(err, ErrIncompatibleAPI) {
+		t.Fatalf("ReloadPlugin() error = %v, want ErrIncompatibleAPI", err)
+	}
+	if old.stopped != 0 {
+		t.Error("old instance should not be stopped when the new build is rejected")
+	}
+}
+
+func TestReloadPluginNotFound(t *testing.T) {
+	pm := NewPluginManager(ManagerConfig{})
+	if _, err := pm.ReloadPlugin("missing", ""); err == nil {
+		t.Error("ReloadPlugin() should fail for an unknown plugin")
+	}
+}
@@ -0,0 +1,165 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+)
+
+// Capability names a privilege a plugin must be granted before the manager
+// hands it the corresponding resource
+type Capability string
+
+const (
+	CapabilityNetwork    Capability = "network"    // Outbound network access
+	CapabilityKeystore   Capability = "keystore"   // Credentials from the keystore
+	CapabilityFilesystem Capability = "filesystem" // Access to specific paths
+)
+
+// CapabilityManifest lists the privileges granted to (in PluginConfig) or
+// requested by (in PluginMetadata) a plugin. Go plugins share the bridge
+// process, so the manifest is enforced wherever the manager hands out a
+// resource rather than by the operating system.
+type CapabilityManifest struct {
+	// Network allows the plugin to open outbound connections
+	Network bool `json:"network,omitempty"`
+
+	// Keystore allows credentials to be injected into the plugin
+	Keystore bool `json:"keystore,omitempty"`
+
+	// FilesystemPaths are the directories or files the plugin may use;
+	// a directory grant covers everything beneath it
+	FilesystemPaths []string `json:"filesystem_paths,omitempty"`
+}
+
+// Undeclared returns the capabilities in requested that m does not grant,
+// formatted as "network", "keystore" or "filesystem:<path>"
+func (m CapabilityManifest) Undeclared(requested CapabilityManifest) []string {
+	var denied []string
+	if requested.Network && !m.Network {
+		denied = append(denied, string(CapabilityNetwork))
+	}
+	if requested.Keystore && !m.Keystore {
+		denied = append(denied, string(CapabilityKeystore))
+	}
+	for _, path := range requested.FilesystemPaths {
+		if !m.AllowsPath(path) {
+			denied = append(denied, string(CapabilityFilesystem)+":"+path)
+		}
+	}
+	return denied
+}
+
+// AllowsPath reports whether path is one of the granted paths or lies
+// beneath a granted directory. Relative paths are never granted.
+func (m CapabilityManifest) AllowsPath(path string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	path = filepath.Clean(path)
+
+	for _, granted := range m.FilesystemPaths {
+		if !filepath.IsAbs(granted) {
+			continue
+		}
+		rel, err := filepath.Rel(filepath.Clean(granted), path)
+		if err != nil {
+			continue
+		}
+		if rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkManifest rejects a plugin whose metadata requests capabilities the
+// configuration does not grant. Callers must hold pm.mu.
+func (pm *PluginManager) checkManifest(config PluginConfig, metadata PluginMetadata) error {
+	denied := config.Capabilities.Undeclared(metadata.Requires)
+	if len(denied) == 0 {
+		return nil
+	}
+
+	pm.logDenied(metadata.Name, config.LibraryPath, strings.Join(denied, ","), "capability not declared in plugin config")
+	return fmt.Errorf("plugin %s requests undeclared capabilities: %s", pluginLabel(metadata.Name, config.LibraryPath), strings.Join(denied, ", "))
+}
+
+// CheckCapability reports whether a loaded plugin was granted a capability.
+// For CapabilityFilesystem, target is the path being accessed. Denials are
+// recorded in the security log.
+func (pm *PluginManager) CheckCapability(name string, capability Capability, target string) error {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	plugin, exists := pm.plugins[name]
+	if !exists {
+		return fmt.Errorf("plugin not found: %s", name)
+	}
+	return pm.checkGranted(plugin, capability, target)
+}
+
+// checkGranted enforces a plugin's granted manifest. Callers must hold pm.mu.
+func (pm *PluginManager) checkGranted(plugin *loadedPlugin, capability Capability, target string) error {
+	granted := plugin.config.Capabilities
+
+	var allowed bool
+	switch capability {
+	case CapabilityNetwork:
+		allowed = granted.Network
+	case CapabilityKeystore:
+		allowed = granted.Keystore
+	case CapabilityFilesystem:
+		allowed = granted.AllowsPath(target)
+	default:
+		return fmt.Errorf("unknown capability: %s", capability)
+	}
+	if allowed {
+		return nil
+	}
+
+	resource := string(capability)
+	if target != "" {
+		resource += ":" + target
+	}
+	pm.logDenied(plugin.info.Metadata.Name, plugin.config.LibraryPath, resource, "capability not granted")
+	return fmt.Errorf("plugin %s is not granted %s access", pluginLabel(plugin.info.Metadata.Name, plugin.config.LibraryPath), resource)
+}
+
+// ResolveCredential returns a credential injected for the plugin. It only
+// succeeds if the plugin was granted keystore access.
+func (pm *PluginManager) ResolveCredential(name, key string) (string, error) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	plugin, exists := pm.plugins[name]
+	if !exists {
+		return "", fmt.Errorf("plugin not found: %s", name)
+	}
+	if err := pm.checkGranted(plugin, CapabilityKeystore, ""); err != nil {
+		return "", err
+	}
+
+	value, ok := plugin.config.Credentials[key]
+	if !ok {
+		return "", fmt.Errorf("credential not found: %s", key)
+	}
+	return value, nil
+}
+
+// logDenied records a capability denial in the security log
+func (pm *PluginManager) logDenied(name, libraryPath, resource, reason string) {
+	pm.securityLog.LogAccessDenied(context.Background(), "plugin."+resource, pluginLabel(name, libraryPath), reason,
+		slog.String("library_path", libraryPath))
+}
+
+// pluginLabel identifies a plugin by name, or by library path before its
+// metadata is known
+func pluginLabel(name, libraryPath string) string {
+	if name != "" {
+		return name
+	}
+	return libraryPath
+}
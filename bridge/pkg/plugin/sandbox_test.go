@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// stubPlugin is a PluginInterface that records the config it was given
+type stubPlugin struct {
+	metadata PluginMetadata
+	config   PluginConfig
+}
+
+func (p *stubPlugin) Metadata() PluginMetadata { return p.metadata }
+func (p *stubPlugin) Initialize(ctx context.Context, config PluginConfig) error {
+	p.config = config
+	return nil
+}
+func (p *stubPlugin) Start(ctx context.Context) error { return nil }
+func (p *stubPlugin) Stop(ctx context.Context) error  { return nil }
+func (p *stubPlugin) HealthCheck() error              { return nil }
+
+// addStub registers a plugin instance without opening a shared library
+func addStub(pm *PluginManager, name string, granted CapabilityManifest) *stubPlugin {
+	stub := &stubPlugin{metadata: PluginMetadata{Name: name}}
+	pm.plugins[name] = &loadedPlugin{
+		info:     PluginInfo{Metadata: stub.metadata, State: PluginStateLoaded},
+		config:   PluginConfig{LibraryPath: name, Capabilities: granted, Credentials: map[string]string{"token": "secret"}},
+		instance: stub,
+	}
+	return stub
+}
+
+func TestCapabilityManifestUndeclared(t *testing.T) {
+	granted := CapabilityManifest{
+		Network:         true,
+		FilesystemPaths: []string{"/var/lib/armorclaw/plugins/telegram"},
+	}
+
+	tests := []struct {
+		name      string
+		requested CapabilityManifest
+		want      []string
+	}{
+		{"nothing requested", CapabilityManifest{}, nil},
+		{"granted network", CapabilityManifest{Network: true}, nil},
+		{"keystore not granted", CapabilityManifest{Keystore: true}, []string{"keystore"}},
+		{"path beneath grant", CapabilityManifest{FilesystemPaths: []string{"/var/lib/armorclaw/plugins/telegram/cache"}}, nil},
+		{"sibling path", CapabilityManifest{FilesystemPaths: []string{"/var/lib/armorclaw/plugins/telegram-evil"}}, []string{"filesystem:/var/lib/armorclaw/plugins/telegram-evil"}},
+		{"path escape", CapabilityManifest{FilesystemPaths: []string{"/var/lib/armorclaw/plugins/telegram/../../keystore.db"}}, []string{"filesystem:/var/lib/armorclaw/plugins/telegram/../../keystore.db"}},
+		{"relative path", CapabilityManifest{FilesystemPaths: []string{"cache"}}, []string{"filesystem:cache"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := granted.Undeclared(tt.requested)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Undeclared() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadPluginRejectsUndeclaredCapabilities(t *testing.T) {
+	dir := t.TempDir()
+	libPath := filepath.Join(dir, "telegram.so")
+	if err := os.WriteFile(libPath, []byte("not a real plugin"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, _ := json.Marshal(PluginMetadata{
+		Name:     "telegram-adapter",
+		Version:  "1.0.0",
+		Type:     PluginTypeAdapter,
+		Requires: CapabilityManifest{Network: true, Keystore: true},
+	})
+	metaPath := filepath.Join(dir, "telegram.json")
+	if err := os.WriteFile(metaPath, metadata, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pm := NewPluginManager(ManagerConfig{PluginDir: dir})
+	err := pm.LoadPlugin(PluginConfig{
+		LibraryPath:  libPath,
+		MetadataPath: metaPath,
+		Capabilities: CapabilityManifest{Network: true},
+	})
+	if err == nil || !strings.Contains(err.Error(), "undeclared capabilities: keystore") {
+		t.Fatalf("LoadPlugin() error = %v, want undeclared keystore", err)
+	}
+	if len(pm.ListPlugins()) != 0 {
+		t.Error("rejected plugin should not be registered")
+	}
+}
+
+func TestResolveCredentialRequiresKeystore(t *testing.T) {
+	pm := NewPluginManager(ManagerConfig{})
+	addStub(pm, "with-keystore", CapabilityManifest{Keystore: true})
+	addStub(pm, "without-keystore", CapabilityManifest{})
+
+	value, err := pm.ResolveCredential("with-keystore", "token")
+	if err != nil || value != "secret" {
+		t.Errorf("ResolveCredential() = %q, %v; want secret", value, err)
+	}
+
+	if _, err := pm.ResolveCredential("without-keystore", "token"); err == nil {
+		t.Error("ResolveCredential() should fail without keystore capability")
+	}
+}
+
+func TestInitializePluginCapabilities(t *testing.T) {
+	pm := NewPluginManager(ManagerConfig{})
+	stub := addStub(pm, "adapter", CapabilityManifest{})
+
+	// Credentials are not handed to a plugin without keystore access
+	err := pm.InitializePlugin("adapter", PluginConfig{
+		Credentials: map[string]string{"token": "secret"},
+	})
+	if err == nil {
+		t.Fatal("InitializePlugin() should reject credentials without keystore capability")
+	}
+	if stub.config.Credentials != nil {
+		t.Error("credentials reached the plugin")
+	}
+
+	// Grants cannot be widened after load
+	err = pm.InitializePlugin("adapter", PluginConfig{
+		Capabilities: CapabilityManifest{Keystore: true, Network: true},
+	})
+	if err != nil {
+		t.Fatalf("InitializePlugin() error = %v", err)
+	}
+	if stub.config.Capabilities.Keystore || stub.config.Capabilities.Network {
+		t.Error("InitializePlugin() widened the granted capabilities")
+	}
+	if err := pm.CheckCapability("adapter", CapabilityNetwork, ""); err == nil {
+		t.Error("CheckCapability() should deny network after initialize")
+	}
+}
+
+func TestCheckCapabilityFilesystem(t *testing.T) {
+	pm := NewPluginManager(ManagerConfig{})
+	addStub(pm, "adapter", CapabilityManifest{FilesystemPaths: []string{"/tmp/adapter"}})
+
+	if err := pm.CheckCapability("adapter", CapabilityFilesystem, "/tmp/adapter/state.db"); err != nil {
+		t.Errorf("CheckCapability() error = %v for granted path", err)
+	}
+	if err := pm.CheckCapability("adapter", CapabilityFilesystem, "/etc/passwd"); err == nil {
+		t.Error("CheckCapability() should deny an ungranted path")
+	}
+	if err := pm.CheckCapability("missing", CapabilityNetwork, ""); err == nil {
+		t.Error("CheckCapability() should fail for an unknown plugin")
+	}
+}
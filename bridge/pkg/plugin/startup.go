@@ -0,0 +1,113 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// ReadConfigFile reads a JSON array of plugin configs, the format of
+// PluginConfig. Relative library and metadata paths are resolved against
+// the plugin directory.
+func (pm *PluginManager) ReadConfigFile(path string) ([]PluginConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin config: %w", err)
+	}
+
+	var configs []PluginConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin config %s: %w", path, err)
+	}
+
+	for i := range configs {
+		if configs[i].LibraryPath == "" {
+			return nil, fmt.Errorf("plugin config %s: entry %d has no library_path", path, i)
+		}
+		configs[i].LibraryPath = pm.resolvePath(configs[i].LibraryPath)
+		if configs[i].MetadataPath != "" {
+			configs[i].MetadataPath = pm.resolvePath(configs[i].MetadataPath)
+		}
+	}
+
+	return configs, nil
+}
+
+// resolvePath makes a relative path relative to the plugin directory
+func (pm *PluginManager) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(pm.config.PluginDir, path)
+}
+
+// LoadConfigured loads, initializes and starts every enabled plugin and
+// returns how many are running. A plugin that fails is unloaded and
+// reported, so one bad library does not keep the others from starting.
+// Plugins are registered under their library path, which is the name the
+// other PluginManager methods take.
+func (pm *PluginManager) LoadConfigured(configs []PluginConfig) (int, map[string]error) {
+	failed := make(map[string]error)
+	started := 0
+
+	for _, config := range configs {
+		if !config.Enabled {
+			continue
+		}
+		if err := pm.startConfigured(config); err != nil {
+			failed[config.LibraryPath] = err
+			continue
+		}
+		started++
+	}
+
+	return started, failed
+}
+
+// startConfigured takes one plugin from its library to running
+func (pm *PluginManager) startConfigured(config PluginConfig) error {
+	if err := pm.LoadPlugin(config); err != nil {
+		return err
+	}
+
+	name := config.LibraryPath
+	if err := pm.InitializePlugin(name, config); err != nil {
+		pm.UnloadPlugin(name)
+		return err
+	}
+	if err := pm.StartPlugin(name); err != nil {
+		pm.UnloadPlugin(name)
+		return err
+	}
+
+	pm.securityLog.LogSecurityEvent("plugin_started",
+		slog.String("plugin", name))
+	return nil
+}
+
+// StopAll stops every running plugin, for bridge shutdown
+func (pm *PluginManager) StopAll() {
+	for _, name := range pm.runningPlugins() {
+		if err := pm.StopPlugin(name); err != nil {
+			pm.securityLog.LogSecurityEvent("plugin_stop_failed",
+				slog.String("plugin", name),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// runningPlugins returns the names of running plugins
+func (pm *PluginManager) runningPlugins() []string {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	var names []string
+	for name, plugin := range pm.plugins {
+		if plugin.info.State == PluginStateRunning {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	goplugin "plugin"
+)
+
+func TestReadConfigFileResolvesRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "plugins.json")
+	data := `[{"library_path": "telegram.so", "metadata_path": "telegram.json", "enabled": true},
+		{"library_path": "/opt/plugins/slack.so"}]`
+	if err := os.WriteFile(configPath, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pm := NewPluginManager(ManagerConfig{PluginDir: dir})
+	configs, err := pm.ReadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("ReadConfigFile() error = %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("got %d configs, want 2", len(configs))
+	}
+	if configs[0].LibraryPath != filepath.Join(dir, "telegram.so") || configs[0].MetadataPath != filepath.Join(dir, "telegram.json") {
+		t.Errorf("relative paths not resolved: %+v", configs[0])
+	}
+	if configs[1].LibraryPath != "/opt/plugins/slack.so" {
+		t.Errorf("absolute path changed: %s", configs[1].LibraryPath)
+	}
+
+	if err := os.WriteFile(configPath, []byte(`[{"enabled": true}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pm.ReadConfigFile(configPath); err == nil {
+		t.Error("ReadConfigFile() should reject an entry without library_path")
+	}
+}
+
+func TestLoadConfiguredStartsEnabledPlugins(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.so")
+	bad := filepath.Join(dir, "bad.so")
+	disabled := filepath.Join(dir, "disabled.so")
+	for _, path := range []string{good, bad, disabled} {
+		if err := os.WriteFile(path, []byte("lib"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	instances := map[string]*reloadablePlugin{
+		good: {stubPlugin: stubPlugin{metadata: PluginMetadata{Name: "good", APIVersion: PluginAPIVersion}}},
+		bad: {
+			stubPlugin: stubPlugin{metadata: PluginMetadata{Name: "bad", APIVersion: PluginAPIVersion}},
+			startErr:   errors.New("no connection"),
+		},
+	}
+
+	pm := NewPluginManager(ManagerConfig{PluginDir: dir})
+	pm.openLibrary = func(path string) (*goplugin.Plugin, PluginInterface, error) {
+		return nil, instances[path], nil
+	}
+
+	started, failed := pm.LoadConfigured([]PluginConfig{
+		{LibraryPath: good, Enabled: true},
+		{LibraryPath: bad, Enabled: true},
+		{LibraryPath: disabled},
+	})
+	if started != 1 || len(failed) != 1 || failed[bad] == nil {
+		t.Fatalf("LoadConfigured() = %d, %v; want 1 started and bad failed", started, failed)
+	}
+
+	if info, err := pm.GetPlugin(good); err != nil || info.State != PluginStateRunning {
+		t.Errorf("good plugin = %+v, %v; want running", info, err)
+	}
+	if _, err := pm.GetPlugin(bad); err == nil {
+		t.Error("failed plugin should be unloaded")
+	}
+
+	pm.StopAll()
+	if instances[good].stopped != 1 {
+		t.Errorf("StopAll() stopped good %d times, want 1", instances[good].stopped)
+	}
+}
@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrIncompatibleAPI is returned when a plugin was built against a plugin
+// API version this bridge cannot load
+var ErrIncompatibleAPI = errors.New("incompatible plugin API version")
+
+// apiVersion is a parsed MAJOR.MINOR.PATCH plugin API version
+type apiVersion struct {
+	major, minor, patch int
+}
+
+// parseAPIVersion parses a version such as "0.2.0" or "v1.3"; a missing
+// patch or minor component is treated as zero
+func parseAPIVersion(s string) (apiVersion, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) == 0 || len(parts) > 3 || parts[0] == "" {
+		return apiVersion{}, fmt.Errorf("invalid API version %q", s)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return apiVersion{}, fmt.Errorf("invalid API version %q", s)
+		}
+		nums[i] = n
+	}
+	return apiVersion{major: nums[0], minor: nums[1], patch: nums[2]}, nil
+}
+
+// CheckAPIVersion reports whether a plugin built against the given API
+// version can be loaded by this bridge. The major version must match and
+// the plugin's minor version may not be newer than the bridge's. Before
+// 1.0 every minor release may break the ABI, so the minor must match too.
+func CheckAPIVersion(version string) error {
+	if version == "" {
+		return fmt.Errorf("%w: plugin does not declare an API version, bridge provides %s (rebuild the plugin against this bridge)",
+			ErrIncompatibleAPI, PluginAPIVersion)
+	}
+
+	pluginVer, err := parseAPIVersion(version)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrIncompatibleAPI, err)
+	}
+	bridgeVer, err := parseAPIVersion(PluginAPIVersion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrIncompatibleAPI, err)
+	}
+
+	compatible := pluginVer.major == bridgeVer.major
+	if compatible && bridgeVer.major == 0 {
+		compatible = pluginVer.minor == bridgeVer.minor
+	} else if compatible {
+		compatible = pluginVer.minor <= bridgeVer.minor
+	}
+	if !compatible {
+		return fmt.Errorf("%w: plugin built for API %s, bridge provides %s (rebuild the plugin against this bridge)",
+			ErrIncompatibleAPI, version, PluginAPIVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{PluginAPIVersion, false},
+		{"v" + PluginAPIVersion, false},
+		{"0.2.7", false}, // patch releases are compatible
+		{"0.2", false},
+		{"0.1.0", true}, // pre-1.0 minor releases break the ABI
+		{"0.3.0", true},
+		{"1.0.0", true},
+		{"", true},
+		{"latest", true},
+		{"0.2.0.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			err := CheckAPIVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckAPIVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrIncompatibleAPI) {
+				t.Errorf("CheckAPIVersion(%q) error = %v, want ErrIncompatibleAPI", tt.version, err)
+			}
+		})
+	}
+}
+
+func TestParseAPIVersion(t *testing.T) {
+	v, err := parseAPIVersion("1.4.2")
+	if err != nil || v != (apiVersion{1, 4, 2}) {
+		t.Errorf("parseAPIVersion(1.4.2) = %+v, %v", v, err)
+	}
+	if _, err := parseAPIVersion("1.-1"); err == nil {
+		t.Error("parseAPIVersion should reject negative components")
+	}
+}
+
+func TestLoadPluginRejectsIncompatibleAPIVersion(t *testing.T) {
+	dir := t.TempDir()
+	libPath := filepath.Join(dir, "stale.so")
+	if err := os.WriteFile(libPath, []byte("not a real plugin"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, _ := json.Marshal(PluginMetadata{
+		Name:       "stale-adapter",
+		Version:    "1.0.0",
+		APIVersion: "0.1.0",
+		Type:       PluginTypeAdapter,
+	})
+	metaPath := filepath.Join(dir, "stale.json")
+	if err := os.WriteFile(metaPath, metadata, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pm := NewPluginManager(ManagerConfig{PluginDir: dir})
+	err := pm.LoadPlugin(PluginConfig{LibraryPath: libPath, MetadataPath: metaPath})
+	if !errors.Is(err, ErrIncompatibleAPI) {
+		t.Fatalf("LoadPlugin() error = %v, want ErrIncompatibleAPI", err)
+	}
+}
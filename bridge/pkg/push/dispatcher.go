@@ -0,0 +1,173 @@
+package push
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/eventbus"
+)
+
+// dispatchTimeout bounds one notification, retries included
+const dispatchTimeout = time.Minute
+
+// DispatcherConfig configures which events a Dispatcher pushes
+type DispatcherConfig struct {
+	// NotifyMessages pushes incoming room messages
+	NotifyMessages bool
+
+	// IgnoreSenders lists Matrix users whose messages never trigger a push,
+	// normally the bridge's own user
+	IgnoreSenders []string
+
+	Logger *slog.Logger
+}
+
+// Dispatcher sends push notifications for bridge events: device approval,
+// incoming room messages and critical errors. Message text never leaves
+// the bridge; notifications carry the room and event IDs and the app
+// fetches the content itself.
+type Dispatcher struct {
+	gateway        *Gateway
+	notifyMessages bool
+	ignoreSenders  map[string]bool
+	logger         *slog.Logger
+}
+
+// NewDispatcher creates a dispatcher that delivers through gateway
+func NewDispatcher(gateway *Gateway, cfg DispatcherConfig) *Dispatcher {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default().With("component", "push_dispatcher")
+	}
+
+	ignore := make(map[string]bool, len(cfg.IgnoreSenders))
+	for _, sender := range cfg.IgnoreSenders {
+		ignore[sender] = true
+	}
+
+	return &Dispatcher{
+		gateway:        gateway,
+		notifyMessages: cfg.NotifyMessages,
+		ignoreSenders:  ignore,
+		logger:         cfg.Logger,
+	}
+}
+
+// NotifyDeviceApproved tells a device that an admin approved it. Devices
+// without a push registration are skipped. Delivery is asynchronous.
+func (d *Dispatcher) NotifyDeviceApproved(deviceID string) {
+	if _, err := d.gateway.GetDevice(deviceID); err != nil {
+		return
+	}
+
+	d.dispatch(func(ctx context.Context) {
+		_, err := d.gateway.SendToDevice(ctx, deviceID, &Notification{
+			Title:    "Device approved",
+			Body:     "This device can now connect to ArmorClaw.",
+			Priority: PriorityHigh,
+			Data: map[string]interface{}{
+				"type":      "device.approved",
+				"device_id": deviceID,
+			},
+		})
+		if err != nil {
+			d.logger.Warn("device_approval_push_failed", "device_id", deviceID, "error", err)
+		}
+	})
+}
+
+// NotifyCriticalError pushes a critical bridge error to every device.
+// Delivery is asynchronous.
+func (d *Dispatcher) NotifyCriticalError(code, message string) {
+	d.dispatch(func(ctx context.Context) {
+		d.gateway.Broadcast(ctx, &Notification{
+			Title:    "ArmorClaw critical error " + code,
+			Body:     truncateContent(message, 200),
+			Priority: PriorityHigh,
+			Data: map[string]interface{}{
+				"type": "error.critical",
+				"code": code,
+			},
+		}, nil)
+	})
+}
+
+// NotifyMessage pushes a room message to every device except the
+// sender's own. Delivery is asynchronous.
+func (d *Dispatcher) NotifyMessage(roomID, eventID, sender string) {
+	if !d.notifyMessages || d.ignoreSenders[sender] {
+		return
+	}
+
+	d.dispatch(func(ctx context.Context) {
+		d.gateway.Broadcast(ctx, &Notification{
+			Title:    formatSenderName(sender),
+			Body:     "New message",
+			Priority: PriorityHigh,
+			Sound:    "default",
+			Tag:      roomID,
+			Data: map[string]interface{}{
+				"type":     "m.room.message",
+				"room_id":  roomID,
+				"event_id": eventID,
+				"sender":   sender,
+			},
+		}, func(device *DeviceRegistration) bool {
+			return device.UserID == sender
+		})
+	})
+}
+
+// Run pushes room messages published on bus until ctx is done. It does
+// nothing when message notifications are off.
+func (d *Dispatcher) Run(ctx context.Context, bus *eventbus.EventBus) error {
+	if !d.notifyMessages {
+		return nil
+	}
+
+	for {
+		sub, err := bus.Subscribe(eventbus.EventFilter{EventType: []string{"m.room.message"}})
+		if err != nil {
+			return err
+		}
+
+		if done := d.consume(ctx, sub); done {
+			bus.Unsubscribe(sub.ID)
+			return nil
+		}
+
+		// The bus closes subscriptions that stay idle; subscribe again
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// consume handles events until ctx is done, which it reports, or the
+// subscription is closed
+func (d *Dispatcher) consume(ctx context.Context, sub *eventbus.Subscriber) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case wrapper, ok := <-sub.EventChannel:
+			if !ok {
+				return ctx.Err() != nil
+			}
+			if event := wrapper.Event; event != nil {
+				d.NotifyMessage(event.RoomID, event.EventID, event.Sender)
+			}
+		}
+	}
+}
+
+// dispatch runs send in the background with a bounded context
+func (d *Dispatcher) dispatch(send func(ctx context.Context)) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), dispatchTimeout)
+		defer cancel()
+		send(ctx)
+	}()
+}
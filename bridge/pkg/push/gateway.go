@@ -5,8 +5,10 @@ package push
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"sync"
 	"time"
 
@@ -91,6 +93,9 @@ type Config struct {
 	FCMEnabled    bool
 	FCMServerKey  string
 	FCMProjectID  string
+	// FCMCredentialsFile is a service account JSON key; when set the HTTP v1
+	// API is used instead of the legacy server key
+	FCMCredentialsFile string
 
 	// APNS configuration
 	APNSEnabled     bool
@@ -114,6 +119,9 @@ type Config struct {
 	SygnalURL    string
 	SygnalAPIKey string
 
+	// Store persists registrations across restarts (optional)
+	Store *DeviceStore
+
 	// Logger
 	Logger *slog.Logger
 }
@@ -163,9 +171,35 @@ func NewGateway(config Config) (*Gateway, error) {
 		providers:   make(map[Platform]PushProvider),
 	}
 
+	if config.Store != nil {
+		devices, err := config.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for _, device := range devices {
+			gw.devices[device.ID] = device
+			gw.userDevices[device.UserID] = append(gw.userDevices[device.UserID], device.ID)
+		}
+		if len(devices) > 0 {
+			gw.logger.Info("push_devices_loaded", "count", len(devices))
+		}
+	}
+
 	// Initialize providers
 	if config.FCMEnabled {
-		gw.providers[PlatformFCM] = NewFCMProvider(config.FCMServerKey, config.FCMProjectID)
+		if config.FCMCredentialsFile != "" {
+			credentials, err := os.ReadFile(config.FCMCredentialsFile)
+			if err != nil {
+				return nil, fmt.Errorf("read FCM credentials: %w", err)
+			}
+			provider, err := NewFCMV1Provider(credentials, config.FCMProjectID)
+			if err != nil {
+				return nil, err
+			}
+			gw.providers[PlatformFCM] = provider
+		} else {
+			gw.providers[PlatformFCM] = NewFCMProvider(config.FCMServerKey, config.FCMProjectID)
+		}
 	}
 
 	if config.APNSEnabled {
@@ -199,6 +233,9 @@ func (g *Gateway) RegisterDevice(userID string, platform Platform, deviceToken,
 			// Update existing device
 			dev.UpdatedAt = time.Now()
 			dev.Enabled = true
+			if err := g.saveDevice(dev); err != nil {
+				return nil, err
+			}
 			g.logger.Info("device_reregistered", "device_id", dev.ID, "user_id", userID)
 			return dev, nil
 		}
@@ -218,6 +255,10 @@ func (g *Gateway) RegisterDevice(userID string, platform Platform, deviceToken,
 		UpdatedAt:   time.Now(),
 	}
 
+	if err := g.saveDevice(device); err != nil {
+		return nil, err
+	}
+
 	g.devices[deviceID] = device
 	g.userDevices[userID] = append(g.userDevices[userID], deviceID)
 
@@ -231,6 +272,60 @@ func (g *Gateway) RegisterDevice(userID string, platform Platform, deviceToken,
 	return device, nil
 }
 
+// RegisterToken registers or refreshes the push token of a client device.
+// The registration takes the client's own device ID, so events about that
+// device, such as its approval, can be pushed to it.
+func (g *Gateway) RegisterToken(deviceID, userID string, platform Platform, token string) (*DeviceRegistration, error) {
+	provider, exists := g.providers[platform]
+	if !exists {
+		return nil, fmt.Errorf("no provider for platform: %s", platform)
+	}
+	if !provider.ValidateToken(token) {
+		return nil, fmt.Errorf("invalid device token for platform: %s", platform)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	device, exists := g.devices[deviceID]
+	if !exists {
+		device = &DeviceRegistration{
+			ID:        deviceID,
+			AppID:     "com.armorclaw.bridge",
+			CreatedAt: now,
+		}
+	}
+
+	updated := *device
+	updated.UserID = userID
+	updated.Platform = platform
+	updated.DeviceToken = token
+	updated.Enabled = true
+	updated.LastError = ""
+	updated.UpdatedAt = now
+	if err := g.saveDevice(&updated); err != nil {
+		return nil, err
+	}
+
+	if exists && device.UserID != userID {
+		g.removeUserDevice(device.UserID, deviceID)
+	}
+	if !exists || device.UserID != userID {
+		g.userDevices[userID] = append(g.userDevices[userID], deviceID)
+	}
+	*device = updated
+	g.devices[deviceID] = device
+
+	g.logger.Info("push_token_registered",
+		"device_id", deviceID,
+		"user_id", userID,
+		"platform", platform,
+	)
+
+	return device, nil
+}
+
 // UnregisterDevice removes a device registration
 func (g *Gateway) UnregisterDevice(deviceID string) error {
 	g.mu.Lock()
@@ -241,15 +336,14 @@ func (g *Gateway) UnregisterDevice(deviceID string) error {
 		return fmt.Errorf("device not found: %s", deviceID)
 	}
 
-	// Remove from user devices list
-	userDevs := g.userDevices[device.UserID]
-	for i, id := range userDevs {
-		if id == deviceID {
-			g.userDevices[device.UserID] = append(userDevs[:i], userDevs[i+1:]...)
-			break
+	if g.config.Store != nil {
+		if err := g.config.Store.Delete(deviceID); err != nil {
+			return err
 		}
 	}
 
+	g.removeUserDevice(device.UserID, deviceID)
+
 	// Delete device
 	delete(g.devices, deviceID)
 
@@ -257,56 +351,142 @@ func (g *Gateway) UnregisterDevice(deviceID string) error {
 	return nil
 }
 
+// removeUserDevice drops deviceID from a user's device list. Callers must
+// hold g.mu.
+func (g *Gateway) removeUserDevice(userID, deviceID string) {
+	userDevs := g.userDevices[userID]
+	for i, id := range userDevs {
+		if id == deviceID {
+			g.userDevices[userID] = append(userDevs[:i], userDevs[i+1:]...)
+			break
+		}
+	}
+	if len(g.userDevices[userID]) == 0 {
+		delete(g.userDevices, userID)
+	}
+}
+
+// saveDevice persists a registration when a store is configured. Callers
+// must hold g.mu.
+func (g *Gateway) saveDevice(device *DeviceRegistration) error {
+	if g.config.Store == nil {
+		return nil
+	}
+	return g.config.Store.Save(device)
+}
+
 // SendToUser sends a notification to all devices for a user
 func (g *Gateway) SendToUser(ctx context.Context, userID string, notification *Notification) ([]*PushResult, error) {
 	g.mu.RLock()
-	deviceIDs := g.userDevices[userID]
+	devices := make([]*DeviceRegistration, 0, len(g.userDevices[userID]))
+	for _, deviceID := range g.userDevices[userID] {
+		if device, exists := g.devices[deviceID]; exists {
+			devices = append(devices, device)
+		}
+	}
 	g.mu.RUnlock()
 
-	if len(deviceIDs) == 0 {
+	if len(devices) == 0 {
 		return nil, fmt.Errorf("no devices registered for user: %s", userID)
 	}
 
-	results := make([]*PushResult, 0, len(deviceIDs))
+	return g.sendToDevices(ctx, devices, notification), nil
+}
 
-	for _, deviceID := range deviceIDs {
-		device, exists := g.devices[deviceID]
-		if !exists || !device.Enabled {
+// SendToDevice sends a notification to one registered device
+func (g *Gateway) SendToDevice(ctx context.Context, deviceID string, notification *Notification) (*PushResult, error) {
+	device, err := g.GetDevice(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	results := g.sendToDevices(ctx, []*DeviceRegistration{device}, notification)
+	if len(results) == 0 {
+		return nil, fmt.Errorf("device is disabled: %s", deviceID)
+	}
+	if !results[0].Success {
+		return results[0], fmt.Errorf("push failed: %s", results[0].Error)
+	}
+	return results[0], nil
+}
+
+// Broadcast sends a notification to every enabled device for which skip,
+// if set, returns false
+func (g *Gateway) Broadcast(ctx context.Context, notification *Notification, skip func(*DeviceRegistration) bool) []*PushResult {
+	g.mu.RLock()
+	devices := make([]*DeviceRegistration, 0, len(g.devices))
+	for _, device := range g.devices {
+		if skip == nil || !skip(device) {
+			devices = append(devices, device)
+		}
+	}
+	g.mu.RUnlock()
+
+	return g.sendToDevices(ctx, devices, notification)
+}
+
+// sendToDevices sends a copy of notification to each enabled device.
+// Devices whose token the push service rejects as unregistered are
+// disabled so they are not tried again until the app registers anew.
+func (g *Gateway) sendToDevices(ctx context.Context, devices []*DeviceRegistration, notification *Notification) []*PushResult {
+	results := make([]*PushResult, 0, len(devices))
+
+	for _, device := range devices {
+		g.mu.RLock()
+		enabled, platform, token := device.Enabled, device.Platform, device.DeviceToken
+		g.mu.RUnlock()
+		if !enabled {
 			continue
 		}
 
 		// Create device-specific notification
 		deviceNotif := *notification
-		deviceNotif.Platform = device.Platform
-		deviceNotif.DeviceToken = device.DeviceToken
+		deviceNotif.Platform = platform
+		deviceNotif.DeviceToken = token
 		deviceNotif.ID = generateNotificationID()
 
 		result, err := g.Send(ctx, &deviceNotif)
 		if err != nil {
 			g.logger.Warn("push_failed",
-				"device_id", deviceID,
+				"device_id", device.ID,
 				"error", err,
 			)
+			if errors.Is(err, ErrTokenUnregistered) {
+				g.disableDevice(device, err)
+			}
 			results = append(results, &PushResult{
 				NotificationID: deviceNotif.ID,
-				DeviceID:       deviceID,
+				DeviceID:       device.ID,
 				Success:        false,
 				Error:          err.Error(),
 			})
 			continue
 		}
 
+		result.DeviceID = device.ID
 		results = append(results, result)
 
 		// Update device last push time
 		g.mu.Lock()
-		if dev, ok := g.devices[deviceID]; ok {
-			dev.LastPushAt = time.Now()
-		}
+		device.LastPushAt = time.Now()
 		g.mu.Unlock()
 	}
 
-	return results, nil
+	return results
+}
+
+// disableDevice stops pushing to a device whose token is no longer valid
+func (g *Gateway) disableDevice(device *DeviceRegistration, cause error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	device.Enabled = false
+	device.LastError = cause.Error()
+	device.UpdatedAt = time.Now()
+	if err := g.saveDevice(device); err != nil {
+		g.logger.Warn("push_device_disable_not_persisted", "device_id", device.ID, "error", err)
+	}
+
+	g.logger.Info("push_device_disabled", "device_id", device.ID, "reason", cause.Error())
 }
 
 // Send sends a notification through the appropriate provider
@@ -348,6 +528,9 @@ func (g *Gateway) Send(ctx context.Context, notification *Notification) (*PushRe
 		}
 
 		lastErr = err
+		if errors.Is(err, ErrTokenUnregistered) {
+			return nil, err
+		}
 		g.logger.Warn("push_retry",
 			"attempt", i+1,
 			"error", err,
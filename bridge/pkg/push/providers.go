@@ -8,19 +8,43 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// ErrTokenUnregistered is returned by a provider when the push service
+// reports that a device token is no longer valid, typically because the
+// app was uninstalled. Retrying will not help; the token should be dropped.
+var ErrTokenUnregistered = errors.New("device token is no longer registered")
+
+// FCM endpoints and OAuth scope
+const (
+	fcmLegacyEndpoint = "https://fcm.googleapis.com/fcm/send"
+	fcmV1Endpoint     = "https://fcm.googleapis.com"
+	fcmScope          = "https://www.googleapis.com/auth/firebase.messaging"
 )
 
-// FCMProvider implements Firebase Cloud Messaging
+// FCMProvider implements Firebase Cloud Messaging. It uses the HTTP v1 API
+// when created with service account credentials, and the legacy server key
+// API otherwise.
 type FCMProvider struct {
 	serverKey string
 	projectID string
 	client    *http.Client
+	// v1 is set when client authenticates with a service account
+	v1       bool
+	endpoint string
 }
 
 // NewFCMProvider creates a new FCM provider
@@ -34,8 +58,36 @@ func NewFCMProvider(serverKey, projectID string) *FCMProvider {
 	}
 }
 
+// NewFCMV1Provider creates an FCM provider for the HTTP v1 API from a
+// service account JSON key. projectID defaults to the key's project.
+func NewFCMV1Provider(credentialsJSON []byte, projectID string) (*FCMProvider, error) {
+	creds, err := google.CredentialsFromJSON(context.Background(), credentialsJSON, fcmScope)
+	if err != nil {
+		return nil, fmt.Errorf("parse FCM credentials: %w", err)
+	}
+	if projectID == "" {
+		projectID = creds.ProjectID
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("FCM project ID not configured and not found in credentials")
+	}
+
+	client := oauth2.NewClient(context.Background(), creds.TokenSource)
+	client.Timeout = 10 * time.Second
+
+	return &FCMProvider{
+		projectID: projectID,
+		client:    client,
+		v1:        true,
+		endpoint:  fcmV1Endpoint,
+	}, nil
+}
+
 // Send sends a notification via FCM
 func (f *FCMProvider) Send(ctx context.Context, notification *Notification) (*PushResult, error) {
+	if f.v1 {
+		return f.sendV1(ctx, notification)
+	}
 	if f.serverKey == "" {
 		return nil, fmt.Errorf("FCM server key not configured")
 	}
@@ -65,8 +117,11 @@ func (f *FCMProvider) Send(ctx context.Context, notification *Notification) (*Pu
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		"https://fcm.googleapis.com/fcm/send", bytes.NewReader(body))
+	endpoint := f.endpoint
+	if endpoint == "" {
+		endpoint = fcmLegacyEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
@@ -106,11 +161,15 @@ func (f *FCMProvider) Send(ctx context.Context, notification *Notification) (*Pu
 	}
 
 	if len(fcmResp.Results) > 0 && fcmResp.Results[0].Error != "" {
+		fcmErr := fmt.Errorf("FCM error: %s", fcmResp.Results[0].Error)
+		if code := fcmResp.Results[0].Error; code == "NotRegistered" || code == "InvalidRegistration" {
+			fcmErr = fmt.Errorf("%w: FCM %s", ErrTokenUnregistered, code)
+		}
 		return &PushResult{
 			NotificationID: notification.ID,
 			Success:        false,
 			Error:          fcmResp.Results[0].Error,
-		}, fmt.Errorf("FCM error: %s", fcmResp.Results[0].Error)
+		}, fcmErr
 	}
 
 	return &PushResult{
@@ -120,6 +179,77 @@ func (f *FCMProvider) Send(ctx context.Context, notification *Notification) (*Pu
 	}, nil
 }
 
+// sendV1 sends a notification through the FCM HTTP v1 API
+func (f *FCMProvider) sendV1(ctx context.Context, notification *Notification) (*PushResult, error) {
+	message := map[string]interface{}{
+		"token": notification.DeviceToken,
+		"notification": map[string]string{
+			"title": notification.Title,
+			"body":  notification.Body,
+		},
+		"android": map[string]interface{}{
+			"priority": strings.ToUpper(mapPriorityToFCM(notification.Priority)),
+		},
+	}
+	if len(notification.Data) > 0 {
+		// v1 data values must be strings
+		data := make(map[string]string, len(notification.Data))
+		for k, v := range notification.Data {
+			data[k] = fmt.Sprint(v)
+		}
+		message["data"] = data
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"message": message})
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", f.endpoint, f.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode == http.StatusOK {
+		return &PushResult{
+			NotificationID: notification.ID,
+			Success:        true,
+			DeliveredAt:    time.Now(),
+		}, nil
+	}
+
+	var fcmErr struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(respBody, &fcmErr)
+
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return nil, fmt.Errorf("%w: FCM UNREGISTERED", ErrTokenUnregistered)
+		}
+	}
+	if fcmErr.Error.Message != "" {
+		return nil, fmt.Errorf("FCM error (%d %s): %s", resp.StatusCode, fcmErr.Error.Status, fcmErr.Error.Message)
+	}
+	return nil, fmt.Errorf("FCM error (%d): %s", resp.StatusCode, string(respBody))
+}
+
 // ValidateToken validates an FCM token
 func (f *FCMProvider) ValidateToken(token string) bool {
 	return len(token) > 100 // FCM tokens are typically long strings
@@ -137,28 +267,68 @@ func mapPriorityToFCM(p Priority) string {
 	return "normal"
 }
 
-// APNSProvider implements Apple Push Notification Service
+// APNs endpoints
+const (
+	apnsProductionEndpoint = "https://api.push.apple.com"
+	apnsSandboxEndpoint    = "https://api.sandbox.push.apple.com"
+)
+
+// APNSProvider implements Apple Push Notification Service using
+// certificate-based authentication
 type APNSProvider struct {
 	certFile    string
 	keyFile     string
 	topic       string
 	environment string
-	client      *http.Client
+	endpoint    string
+
+	// client is built on first use so a missing certificate surfaces as a
+	// send error rather than failing gateway startup
+	client     *http.Client
+	clientErr  error
+	clientOnce sync.Once
 }
 
 // NewAPNSProvider creates a new APNS provider
 func NewAPNSProvider(certFile, keyFile, topic, environment string) *APNSProvider {
+	endpoint := apnsProductionEndpoint
+	if environment == "sandbox" {
+		endpoint = apnsSandboxEndpoint
+	}
 	return &APNSProvider{
 		certFile:    certFile,
 		keyFile:     keyFile,
 		topic:       topic,
 		environment: environment,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		endpoint:    endpoint,
 	}
 }
 
+// httpClient returns an HTTP/2 client presenting the APNs certificate
+func (a *APNSProvider) httpClient() (*http.Client, error) {
+	a.clientOnce.Do(func() {
+		if a.client != nil {
+			return
+		}
+		cert, err := tls.LoadX509KeyPair(a.certFile, a.keyFile)
+		if err != nil {
+			a.clientErr = fmt.Errorf("load APNS certificate: %w", err)
+			return
+		}
+		a.client = &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					Certificates: []tls.Certificate{cert},
+					MinVersion:   tls.VersionTLS12,
+				},
+				ForceAttemptHTTP2: true,
+			},
+		}
+	})
+	return a.client, a.clientErr
+}
+
 // Send sends a notification via APNS
 func (a *APNSProvider) Send(ctx context.Context, notification *Notification) (*PushResult, error) {
 	if a.certFile == "" || a.keyFile == "" {
@@ -192,13 +362,12 @@ func (a *APNSProvider) Send(ctx context.Context, notification *Notification) (*P
 		return nil, fmt.Errorf("marshal payload: %w", err)
 	}
 
-	// Select APNS endpoint based on environment
-	endpoint := "https://api.push.apple.com"
-	if a.environment == "sandbox" {
-		endpoint = "https://api.sandbox.push.apple.com"
+	client, err := a.httpClient()
+	if err != nil {
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/3/device/%s", endpoint, notification.DeviceToken)
+	url := fmt.Sprintf("%s/3/device/%s", a.endpoint, notification.DeviceToken)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
 	if err != nil {
@@ -209,16 +378,41 @@ func (a *APNSProvider) Send(ctx context.Context, notification *Notification) (*P
 	req.Header.Set("apns-topic", a.topic)
 	req.Header.Set("apns-priority", mapPriorityToAPNS(notification.Priority))
 	req.Header.Set("apns-push-type", "alert")
+	if !notification.ExpiresAt.IsZero() {
+		req.Header.Set("apns-expiration", fmt.Sprint(notification.ExpiresAt.Unix()))
+	}
 
-	// Note: In production, this would include the JWT authentication header
-	// For now, we simulate success
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
 
-	// Simulated success for development
-	return &PushResult{
-		NotificationID: notification.ID,
-		Success:        true,
-		DeliveredAt:    time.Now(),
-	}, nil
+	if resp.StatusCode == http.StatusOK {
+		return &PushResult{
+			NotificationID: notification.ID,
+			Success:        true,
+			DeliveredAt:    time.Now(),
+		}, nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	_ = json.Unmarshal(respBody, &apnsErr)
+
+	switch {
+	case resp.StatusCode == http.StatusGone,
+		apnsErr.Reason == "BadDeviceToken",
+		apnsErr.Reason == "Unregistered",
+		apnsErr.Reason == "DeviceTokenNotForTopic":
+		return nil, fmt.Errorf("%w: APNS %s", ErrTokenUnregistered, apnsErr.Reason)
+	case apnsErr.Reason != "":
+		return nil, fmt.Errorf("APNS error (%d): %s", resp.StatusCode, apnsErr.Reason)
+	default:
+		return nil, fmt.Errorf("APNS error (%d): %s", resp.StatusCode, string(respBody))
+	}
 }
 
 // ValidateToken validates an APNS token
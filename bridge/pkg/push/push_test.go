@@ -3,8 +3,17 @@ package push
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/armorclaw/bridge/pkg/eventbus"
+	_ "modernc.org/sqlite"
 )
 
 func TestNewGateway(t *testing.T) {
@@ -431,3 +440,270 @@ func TestNotificationTimeout(t *testing.T) {
 		// Some other error is acceptable
 	}
 }
+
+func TestDeviceRegistrationsPersist(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	store, err := NewDeviceStore(db)
+	if err != nil {
+		t.Fatalf("NewDeviceStore() error = %v", err)
+	}
+
+	gw, _ := NewGateway(Config{Store: store})
+	kept, err := gw.RegisterDevice("@user:example.com", PlatformFCM, "token-1", "android", "Phone")
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	removed, _ := gw.RegisterDevice("@user:example.com", PlatformAPNS, "token-2", "ios", "Tablet")
+	if err := gw.UnregisterDevice(removed.ID); err != nil {
+		t.Fatalf("UnregisterDevice() error = %v", err)
+	}
+
+	// A new gateway on the same database stands in for a restart
+	restarted, err := NewGateway(Config{Store: store})
+	if err != nil {
+		t.Fatalf("NewGateway() after restart error = %v", err)
+	}
+	devices := restarted.GetUserDevices("@user:example.com")
+	if len(devices) != 1 || devices[0].ID != kept.ID || devices[0].DeviceToken != "token-1" {
+		t.Fatalf("devices after restart = %+v, want only %s", devices, kept.ID)
+	}
+
+	// Re-registering the same token reuses the stored registration
+	again, _ := restarted.RegisterDevice("@user:example.com", PlatformFCM, "token-1", "android", "Phone")
+	if again.ID != kept.ID {
+		t.Errorf("re-registration got ID %s, want %s", again.ID, kept.ID)
+	}
+}
+
+func TestFCMV1Send(t *testing.T) {
+	var got struct {
+		Message struct {
+			Token string            `json:"token"`
+			Data  map[string]string `json:"data"`
+		} `json:"message"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/armorclaw-test/messages:send" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		if got.Message.Token == "stale-token" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"name":"projects/armorclaw-test/messages/1"}`))
+	}))
+	defer srv.Close()
+
+	provider := &FCMProvider{projectID: "armorclaw-test", client: srv.Client(), v1: true, endpoint: srv.URL}
+
+	result, err := provider.Send(context.Background(), &Notification{
+		DeviceToken: "fresh-token",
+		Title:       "Hi",
+		Data:        map[string]interface{}{"room_id": "!r:example.com", "count": 2},
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("Send() = %+v, %v; want success", result, err)
+	}
+	if got.Message.Token != "fresh-token" || got.Message.Data["count"] != "2" {
+		t.Errorf("sent message = %+v, want token and string data", got.Message)
+	}
+
+	_, err = provider.Send(context.Background(), &Notification{DeviceToken: "stale-token"})
+	if !errors.Is(err, ErrTokenUnregistered) {
+		t.Errorf("Send(stale) error = %v, want ErrTokenUnregistered", err)
+	}
+}
+
+func TestAPNSSend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("apns-topic") != "com.armorclaw.app" {
+			t.Errorf("apns-topic = %q", r.Header.Get("apns-topic"))
+		}
+		if strings.HasSuffix(r.URL.Path, "/stale") {
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"reason":"Unregistered"}`))
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/throttled") {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"reason":"TooManyRequests"}`))
+			return
+		}
+	}))
+	defer srv.Close()
+
+	provider := NewAPNSProvider("cert.pem", "key.pem", "com.armorclaw.app", "sandbox")
+	provider.endpoint = srv.URL
+	provider.client = srv.Client()
+
+	if result, err := provider.Send(context.Background(), &Notification{DeviceToken: "fresh"}); err != nil || !result.Success {
+		t.Errorf("Send() = %+v, %v; want success", result, err)
+	}
+	if _, err := provider.Send(context.Background(), &Notification{DeviceToken: "stale"}); !errors.Is(err, ErrTokenUnregistered) {
+		t.Errorf("Send(stale) error = %v, want ErrTokenUnregistered", err)
+	}
+	if _, err := provider.Send(context.Background(), &Notification{DeviceToken: "throttled"}); err == nil || errors.Is(err, ErrTokenUnregistered) {
+		t.Errorf("Send(throttled) error = %v, want a retryable error", err)
+	}
+}
+
+func TestGatewayDisablesUnregisteredTokens(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	provider := NewAPNSProvider("cert.pem", "key.pem", "com.armorclaw.app", "production")
+	provider.endpoint = srv.URL
+	provider.client = srv.Client()
+
+	gw, _ := NewGateway(Config{})
+	gw.providers[PlatformAPNS] = provider
+
+	token := strings.Repeat("a", 64)
+	if _, err := gw.RegisterToken("phone-1", "@user:example.com", PlatformAPNS, token); err != nil {
+		t.Fatalf("RegisterToken() error = %v", err)
+	}
+
+	// Unregistered tokens are not retried, so this returns without waiting
+	// out the retry delay
+	if _, err := gw.SendToDevice(context.Background(), "phone-1", &Notification{Title: "Hi"}); err == nil {
+		t.Fatal("SendToDevice() succeeded, want an error")
+	}
+	device, _ := gw.GetDevice("phone-1")
+	if device.Enabled || device.LastError == "" {
+		t.Errorf("device after unregistered token = enabled %v, last error %q; want disabled", device.Enabled, device.LastError)
+	}
+	if results := gw.Broadcast(context.Background(), &Notification{Title: "Hi"}, nil); len(results) != 0 {
+		t.Errorf("Broadcast() reached %d devices, want none", len(results))
+	}
+
+	// Registering a new token re-enables the device
+	if _, err := gw.RegisterToken("phone-1", "@user:example.com", PlatformAPNS, strings.Repeat("b", 64)); err != nil {
+		t.Fatalf("RegisterToken() error = %v", err)
+	}
+	if device, _ := gw.GetDevice("phone-1"); !device.Enabled {
+		t.Error("device still disabled after registering a new token")
+	}
+}
+
+// recordingProvider reports every notification it is asked to send
+type recordingProvider struct {
+	sent chan *Notification
+}
+
+func (r *recordingProvider) Send(ctx context.Context, n *Notification) (*PushResult, error) {
+	r.sent <- n
+	return &PushResult{NotificationID: n.ID, Success: true, DeliveredAt: time.Now()}, nil
+}
+
+func (r *recordingProvider) ValidateToken(token string) bool { return token != "" }
+
+func (r *recordingProvider) Platform() Platform { return PlatformFCM }
+
+func newRecordingGateway(t *testing.T) (*Gateway, *recordingProvider) {
+	t.Helper()
+	gw, _ := NewGateway(Config{})
+	provider := &recordingProvider{sent: make(chan *Notification, 10)}
+	gw.providers[PlatformFCM] = provider
+
+	gw.RegisterToken("alice-phone", "@alice:example.com", PlatformFCM, "alice-token")
+	gw.RegisterToken("bob-phone", "@bob:example.com", PlatformFCM, "bob-token")
+	return gw, provider
+}
+
+func expectPush(t *testing.T, provider *recordingProvider) *Notification {
+	t.Helper()
+	select {
+	case n := <-provider.sent:
+		return n
+	case <-time.After(2 * time.Second):
+		t.Fatal("no push notification sent")
+		return nil
+	}
+}
+
+func expectNoPush(t *testing.T, provider *recordingProvider) {
+	t.Helper()
+	select {
+	case n := <-provider.sent:
+		t.Errorf("unexpected push to %s: %q", n.DeviceToken, n.Title)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDispatcherNotifications(t *testing.T) {
+	gw, provider := newRecordingGateway(t)
+	d := NewDispatcher(gw, DispatcherConfig{
+		NotifyMessages: true,
+		IgnoreSenders:  []string{"@bridge:example.com"},
+	})
+
+	d.NotifyDeviceApproved("bob-phone")
+	if n := expectPush(t, provider); n.DeviceToken != "bob-token" || n.Data["type"] != "device.approved" {
+		t.Errorf("approval push = %s %v, want bob-token device.approved", n.DeviceToken, n.Data)
+	}
+	d.NotifyDeviceApproved("unregistered-device")
+	expectNoPush(t, provider)
+
+	// Senders do not get pushes for their own messages
+	d.NotifyMessage("!room:example.com", "$event", "@alice:example.com")
+	n := expectPush(t, provider)
+	if n.DeviceToken != "bob-token" || n.Data["event_id"] != "$event" {
+		t.Errorf("message push = %s %v, want bob-token with the event ID", n.DeviceToken, n.Data)
+	}
+	expectNoPush(t, provider)
+
+	d.NotifyMessage("!room:example.com", "$echo", "@bridge:example.com")
+	expectNoPush(t, provider)
+
+	d.NotifyCriticalError("SYS-001", "keystore unavailable")
+	expectPush(t, provider)
+	expectPush(t, provider)
+}
+
+func TestDispatcherRun(t *testing.T) {
+	gw, provider := newRecordingGateway(t)
+	d := NewDispatcher(gw, DispatcherConfig{NotifyMessages: true})
+	bus := eventbus.NewEventBus(eventbus.Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx, bus) }()
+
+	// Publish until the dispatcher has subscribed
+	deadline := time.Now().Add(2 * time.Second)
+	var n *Notification
+	for n == nil && time.Now().Before(deadline) {
+		bus.Publish(&eventbus.MatrixEvent{
+			Type:    "m.room.message",
+			RoomID:  "!room:example.com",
+			Sender:  "@alice:example.com",
+			EventID: "$event",
+		})
+		select {
+		case n = <-provider.sent:
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	if n == nil || n.DeviceToken != "bob-token" {
+		t.Fatalf("push from bus = %+v, want one to bob-token", n)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() did not stop after cancel")
+	}
+}
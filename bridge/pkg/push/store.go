@@ -0,0 +1,90 @@
+package push
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// DeviceStore persists push registrations so devices keep receiving
+// notifications after a bridge restart
+type DeviceStore struct {
+	db *sql.DB
+}
+
+// NewDeviceStore opens a DeviceStore against db, creating the schema if
+// needed
+func NewDeviceStore(db *sql.DB) (*DeviceStore, error) {
+	s := &DeviceStore{db: db}
+	if err := s.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize push device schema: %w", err)
+	}
+	return s, nil
+}
+
+// initSchema creates the push_devices table if it does not already exist
+func (s *DeviceStore) initSchema() error {
+	const ddl = `
+	CREATE TABLE IF NOT EXISTS push_devices (
+		id         TEXT PRIMARY KEY,
+		user_id    TEXT NOT NULL,
+		data       TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_push_devices_user ON push_devices(user_id);
+	`
+	_, err := s.db.Exec(ddl)
+	return err
+}
+
+// Save stores or replaces a registration
+func (s *DeviceStore) Save(d *DeviceRegistration) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to encode push device: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO push_devices (id, user_id, data, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET user_id = excluded.user_id, data = excluded.data
+	`, d.ID, d.UserID, string(data), d.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save push device: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a registration; deleting an unknown device is not an error
+func (s *DeviceStore) Delete(id string) error {
+	if _, err := s.db.Exec(`DELETE FROM push_devices WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete push device: %w", err)
+	}
+	return nil
+}
+
+// Load returns every stored registration, oldest first
+func (s *DeviceStore) Load() ([]*DeviceRegistration, error) {
+	rows, err := s.db.Query(`SELECT data FROM push_devices ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load push devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []*DeviceRegistration
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var d DeviceRegistration
+		if err := json.Unmarshal([]byte(data), &d); err != nil {
+			return nil, fmt.Errorf("failed to decode push device: %w", err)
+		}
+		devices = append(devices, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate push devices: %w", err)
+	}
+	return devices, nil
+}
@@ -406,22 +406,45 @@ func (m *QRManager) GenerateConfigURL(expiration time.Duration) (string, *Config
 	return url, config, nil
 }
 
-// ValidateConfig validates a signed configuration payload
-func (m *QRManager) ValidateConfig(config *ConfigPayload) error {
-	// Check expiration
-	if time.Now().Unix() > config.ExpiresAt {
-		return errors.New("config expired")
-	}
+// Errors returned when validating a signed configuration payload
+var (
+	ErrConfigSignature = errors.New("invalid config signature")
+	ErrConfigExpired   = errors.New("config expired")
+)
 
+// ValidateConfig validates a signed configuration payload. The signature is
+// checked first, so a tampered payload is reported as ErrConfigSignature even
+// when its expiry has also passed.
+func (m *QRManager) ValidateConfig(config *ConfigPayload) error {
 	// Verify signature
 	expectedSig := m.signConfig(config)
 	if !hmac.Equal([]byte(config.Signature), []byte(expectedSig)) {
-		return errors.New("invalid config signature")
+		return ErrConfigSignature
+	}
+
+	// Check expiration
+	if time.Now().Unix() > config.ExpiresAt {
+		return ErrConfigExpired
 	}
 
 	return nil
 }
 
+// VerifyConfigURL parses a config deep link produced by this manager and
+// validates its signature and expiry, returning the decoded payload. Use
+// errors.Is with ErrConfigSignature or ErrConfigExpired to tell tampering
+// from expiry.
+func (m *QRManager) VerifyConfigURL(configURL string) (*ConfigPayload, error) {
+	config, err := ParseConfigURL(configURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ValidateConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // ParseConfigURL parses a config URL and returns the payload
 func ParseConfigURL(configURL string) (*ConfigPayload, error) {
 	// Parse armorclaw://config?d=...
@@ -518,6 +541,28 @@ func (r *QRResult) ToTerminal() (string, error) {
 	return qrCode.ToSmallString(false), nil
 }
 
+// ToPNG returns the deep link QR code as a PNG of size pixels square; a
+// size of zero or less uses the default QR size
+func (r *QRResult) ToPNG(size int) ([]byte, error) {
+	if strings.TrimSpace(r.DeepLink) == "" {
+		return nil, fmt.Errorf("deep link is empty")
+	}
+	if size <= 0 {
+		size = DefaultQRConfig().QRSize
+	}
+	return qrcode.Encode(r.DeepLink, qrcode.Medium, size)
+}
+
+// WritePNG writes the deep link QR code to a PNG file at path. A new file
+// is readable only by its owner since setup and invite codes grant access.
+func (r *QRResult) WritePNG(path string, size int) error {
+	data, err := r.ToPNG(size)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
 // createToken creates a new one-time token
 func (m *QRManager) createToken(tokenType TokenType, payload string, expiration time.Duration, maxUses int, metadata map[string]string) (*OneTimeToken, error) {
 	m.mu.Lock()
@@ -1,8 +1,14 @@
 package qr
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestToTerminal(t *testing.T) {
@@ -47,3 +53,112 @@ func TestToTerminal_WhitespaceDeepLink(t *testing.T) {
 		t.Errorf("Expected error to contain 'deep link is empty', got: %v", err)
 	}
 }
+
+func TestToPNG(t *testing.T) {
+	qrResult := &QRResult{DeepLink: "armorclaw://config?d=testdata"}
+	data, err := qrResult.ToPNG(0)
+	if err != nil {
+		t.Fatalf("ToPNG() failed: %v", err)
+	}
+	img, err := QRToImage(data)
+	if err != nil {
+		t.Fatalf("ToPNG() did not return a PNG: %v", err)
+	}
+	if size := img.Bounds().Dx(); size != DefaultQRConfig().QRSize {
+		t.Errorf("ToPNG(0) width = %d, want default %d", size, DefaultQRConfig().QRSize)
+	}
+
+	if _, err := (&QRResult{}).ToPNG(256); err == nil {
+		t.Fatal("ToPNG() should fail with empty deep link")
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qr.png")
+	qrResult := &QRResult{DeepLink: "armorclaw://config?d=testdata"}
+	if err := qrResult.WritePNG(path, 512); err != nil {
+		t.Fatalf("WritePNG() failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("WritePNG() mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	img, err := QRToImage(data)
+	if err != nil || img.Bounds().Dx() != 512 {
+		t.Errorf("WritePNG() wrote %v, err %v; want a 512px PNG", img, err)
+	}
+}
+
+func newTestQRManager(key string) *QRManager {
+	return NewQRManager([]byte(key), DefaultQRConfig(), "https://matrix.example.com", "https://bridge.example.com", "example.com")
+}
+
+func TestVerifyConfigURL(t *testing.T) {
+	m := newTestQRManager("signing-key")
+	configURL, want, err := m.GenerateConfigURL(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := m.VerifyConfigURL(configURL)
+	if err != nil {
+		t.Fatalf("VerifyConfigURL() failed: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("VerifyConfigURL() = %+v, want %+v", got, want)
+	}
+}
+
+func TestVerifyConfigURL_Tampered(t *testing.T) {
+	m := newTestQRManager("signing-key")
+	configURL, config, _ := m.GenerateConfigURL(time.Hour)
+
+	// Point the client at another server, keeping the signature
+	config.RpcURL = "https://attacker.example.com/api"
+	data, _ := json.Marshal(config)
+	tampered := "armorclaw://config?d=" + base64.URLEncoding.EncodeToString(data)
+	if _, err := m.VerifyConfigURL(tampered); !errors.Is(err, ErrConfigSignature) {
+		t.Errorf("tampered config: err = %v, want ErrConfigSignature", err)
+	}
+
+	// A config signed with another key is rejected the same way
+	if _, err := newTestQRManager("other-key").VerifyConfigURL(configURL); !errors.Is(err, ErrConfigSignature) {
+		t.Errorf("foreign config: err = %v, want ErrConfigSignature", err)
+	}
+}
+
+func TestVerifyConfigURL_Expired(t *testing.T) {
+	m := newTestQRManager("signing-key")
+	configURL, config, _ := m.GenerateConfigURL(-time.Minute)
+	if _, err := m.VerifyConfigURL(configURL); !errors.Is(err, ErrConfigExpired) {
+		t.Errorf("expired config: err = %v, want ErrConfigExpired", err)
+	}
+
+	// Extending the expiry of an expired config is tampering
+	config.ExpiresAt = time.Now().Add(time.Hour).Unix()
+	data, _ := json.Marshal(config)
+	extended := "armorclaw://config?d=" + base64.URLEncoding.EncodeToString(data)
+	if _, err := m.VerifyConfigURL(extended); !errors.Is(err, ErrConfigSignature) {
+		t.Errorf("extended config: err = %v, want ErrConfigSignature", err)
+	}
+}
+
+func TestVerifyConfigURL_Malformed(t *testing.T) {
+	m := newTestQRManager("signing-key")
+	for _, configURL := range []string{
+		"https://example.com/config?d=abc",
+		"armorclaw://config",
+		"armorclaw://config?d=%%%",
+		"armorclaw://config?d=bm90IGpzb24=",
+	} {
+		_, err := m.VerifyConfigURL(configURL)
+		if err == nil || errors.Is(err, ErrConfigSignature) || errors.Is(err, ErrConfigExpired) {
+			t.Errorf("%s: err = %v, want a parse error", configURL, err)
+		}
+	}
+}
@@ -8,20 +8,27 @@
 // 4. System verifies phrase and initiates 24-48 hour recovery window
 // 5. During recovery: Read-only access, limited operations
 // 6. After recovery: Full access restored, old devices invalidated
+//
+// A recovery started by someone else can be cancelled from a device that is
+// still valid. Cancelling ends read-only mode at once, invalidates the
+// recovering device and the recovery phrase that was used.
 package recovery
 
 import (
+	"context"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/crypto/chacha20poly1305"
 
+	"github.com/armorclaw/bridge/pkg/logger"
 	"github.com/armorclaw/bridge/pkg/securerandom"
 )
 
@@ -303,11 +310,12 @@ const (
 type RecoveryStatus string
 
 const (
-	RecoveryStatusNone     RecoveryStatus = "none"
-	RecoveryStatusPending  RecoveryStatus = "pending"
-	RecoveryStatusActive   RecoveryStatus = "active"
-	RecoveryStatusComplete RecoveryStatus = "complete"
-	RecoveryStatusExpired  RecoveryStatus = "expired"
+	RecoveryStatusNone      RecoveryStatus = "none"
+	RecoveryStatusPending   RecoveryStatus = "pending"
+	RecoveryStatusActive    RecoveryStatus = "active"
+	RecoveryStatusComplete  RecoveryStatus = "complete"
+	RecoveryStatusExpired   RecoveryStatus = "expired"
+	RecoveryStatusCancelled RecoveryStatus = "cancelled"
 )
 
 // RecoveryState tracks an ongoing recovery process
@@ -325,18 +333,21 @@ type RecoveryState struct {
 
 // Manager handles account recovery operations
 type Manager struct {
-	db        *sql.DB
-	mu        sync.RWMutex
-	encryptKey []byte
+	db          *sql.DB
+	mu          sync.RWMutex
+	encryptKey  []byte
+	securityLog *logger.SecurityLogger
 }
 
 var (
-	ErrInvalidPhrase      = errors.New("invalid recovery phrase")
-	ErrRecoveryNotFound   = errors.New("recovery not found")
-	ErrRecoveryExpired    = errors.New("recovery window expired")
-	ErrRecoveryAlready    = errors.New("recovery already in progress")
-	ErrPhraseNotSet       = errors.New("recovery phrase not set")
-	ErrTooManyAttempts    = errors.New("too many recovery attempts")
+	ErrInvalidPhrase     = errors.New("invalid recovery phrase")
+	ErrRecoveryNotFound  = errors.New("recovery not found")
+	ErrRecoveryExpired   = errors.New("recovery window expired")
+	ErrRecoveryAlready   = errors.New("recovery already in progress")
+	ErrPhraseNotSet      = errors.New("recovery phrase not set")
+	ErrTooManyAttempts   = errors.New("too many recovery attempts")
+	ErrRecoveryNotActive = errors.New("recovery not in active state")
+	ErrDeviceNotAllowed  = errors.New("device not allowed to cancel recovery")
 )
 
 // NewManager creates a new recovery manager
@@ -346,8 +357,9 @@ func NewManager(db *sql.DB, encryptKey []byte) (*Manager, error) {
 	}
 
 	m := &Manager{
-		db:        db,
-		encryptKey: encryptKey,
+		db:          db,
+		encryptKey:  encryptKey,
+		securityLog: logger.NewSecurityLogger(logger.Global().WithComponent("recovery")),
 	}
 
 	if err := m.initSchema(); err != nil {
@@ -403,27 +415,81 @@ func GeneratePhrase() (string, error) {
 		words[i] = wordList[index%len(wordList)]
 	}
 
-	return strings.Join(words, " "), nil
+	phrase := strings.Join(words, " ")
+
+	// A random draw occasionally repeats a word, which StorePhrase would
+	// reject; draw again rather than hand out an unusable phrase
+	if err := ValidatePhrase(phrase); err != nil {
+		return GeneratePhrase()
+	}
+
+	return phrase, nil
 }
 
-// ValidatePhrase checks if a phrase has the correct format
+// wordIndex maps each word to its position in wordList
+var wordIndex = func() map[string]int {
+	index := make(map[string]int, len(wordList))
+	for i, w := range wordList {
+		index[w] = i
+	}
+	return index
+}()
+
+// maxSequentialRun is the longest run of words that may appear in wordlist
+// order (e.g. "abandon ability able"). A random phrase contains a run of four
+// with probability below one in a hundred million.
+const maxSequentialRun = 3
+
+// ValidatePhrase checks that a phrase has PhraseLength distinct words from
+// the wordlist and no obvious low-entropy pattern, such as words copied in
+// order from the list
 func ValidatePhrase(phrase string) error {
-	words := strings.Fields(phrase)
+	words := strings.Fields(strings.ToLower(phrase))
 	if len(words) != PhraseLength {
-		return fmt.Errorf("phrase must have %d words, got %d", PhraseLength, len(words))
+		return fmt.Errorf("%w: phrase must have %d words, got %d", ErrInvalidPhrase, PhraseLength, len(words))
 	}
 
-	wordSet := make(map[string]bool)
-	for _, w := range wordList {
-		wordSet[w] = true
+	indices := make([]int, len(words))
+	seen := make(map[string]int, len(words))
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return fmt.Errorf("%w: word %d (%q) is not in the recovery wordlist", ErrInvalidPhrase, i+1, word)
+		}
+		if first, dup := seen[word]; dup {
+			return fmt.Errorf("%w: word %q is repeated at positions %d and %d", ErrInvalidPhrase, word, first+1, i+1)
+		}
+		seen[word] = i
+		indices[i] = index
 	}
 
-	for _, word := range words {
-		if !wordSet[strings.ToLower(word)] {
-			return fmt.Errorf("invalid word in phrase: %s", word)
+	// Reject runs of neighbouring wordlist entries, in either direction
+	run := 1
+	for i := 1; i < len(indices); i++ {
+		step := indices[i] - indices[i-1]
+		if step == 1 || step == -1 {
+			if i > 1 && step != indices[i-1]-indices[i-2] {
+				run = 1
+			}
+			run++
+		} else {
+			run = 1
+		}
+		if run > maxSequentialRun {
+			return fmt.Errorf("%w: words %d-%d follow the wordlist order", ErrInvalidPhrase, i+2-run, i+1)
 		}
 	}
 
+	// Reject phrases picked alphabetically from the list
+	ascending, descending := true, true
+	for i := 1; i < len(indices); i++ {
+		ascending = ascending && indices[i] > indices[i-1]
+		descending = descending && indices[i] < indices[i-1]
+	}
+	if ascending || descending {
+		return fmt.Errorf("%w: words are in alphabetical order", ErrInvalidPhrase)
+	}
+
 	return nil
 }
 
@@ -606,7 +672,7 @@ func (m *Manager) CompleteRecovery(recoveryID string, oldDevices []string) error
 	}
 
 	if status != "active" {
-		return errors.New("recovery not in active state")
+		return ErrRecoveryNotActive
 	}
 
 	if time.Now().After(time.Unix(expiresAt, 0)) {
@@ -632,6 +698,120 @@ func (m *Manager) CompleteRecovery(recoveryID string, oldDevices []string) error
 	return err
 }
 
+// AuthorizeCancel checks that deviceID may cancel a recovery. Only a device
+// that is still valid may cancel, and never the device being recovered to,
+// since that is the device an attacker holding the phrase would be using.
+// Denials are recorded in the security log.
+func (m *Manager) AuthorizeCancel(recoveryID, deviceID string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var newDeviceID sql.NullString
+	err := m.db.QueryRow(`
+		SELECT new_device_id FROM recovery_sessions WHERE id = ?
+	`, recoveryID).Scan(&newDeviceID)
+
+	if err == sql.ErrNoRows {
+		return ErrRecoveryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	reason := ""
+	if deviceID == "" {
+		reason = "no device"
+	} else if deviceID == newDeviceID.String {
+		reason = "device is the one being recovered"
+	} else {
+		var invalidated int
+		err := m.db.QueryRow(`
+			SELECT COUNT(*) FROM invalidated_devices WHERE device_id = ?
+		`, deviceID).Scan(&invalidated)
+		if err != nil {
+			return err
+		}
+		if invalidated > 0 {
+			reason = "device has been invalidated"
+		}
+	}
+
+	if reason != "" {
+		m.securityLog.LogAccessDenied(context.Background(), "recovery.cancel", deviceID, reason,
+			slog.String("recovery_id", recoveryID))
+		return fmt.Errorf("%w: %s", ErrDeviceNotAllowed, reason)
+	}
+	return nil
+}
+
+// CancelRecovery aborts an active recovery before its window ends. Read-only
+// mode ends immediately, the recovering device is invalidated, and the active
+// recovery phrase is invalidated because whoever started the recovery knows
+// it; a new phrase must be stored. Callers should check AuthorizeCancel first.
+func (m *Manager) CancelRecovery(recoveryID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var status string
+	var expiresAt int64
+	var newDeviceID sql.NullString
+	err := m.db.QueryRow(`
+		SELECT status, expires_at, new_device_id FROM recovery_sessions WHERE id = ?
+	`, recoveryID).Scan(&status, &expiresAt, &newDeviceID)
+
+	if err == sql.ErrNoRows {
+		return ErrRecoveryNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if status != string(RecoveryStatusActive) && status != string(RecoveryStatusPending) {
+		return ErrRecoveryNotActive
+	}
+
+	if time.Now().After(time.Unix(expiresAt, 0)) {
+		return ErrRecoveryExpired
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// completed_at records when the session ended, however it ended
+	now := time.Now().Unix()
+	if _, err := tx.Exec(`
+		UPDATE recovery_sessions SET status = 'cancelled', completed_at = ? WHERE id = ?
+	`, now, recoveryID); err != nil {
+		return err
+	}
+
+	if newDeviceID.String != "" {
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO invalidated_devices (device_id, invalidated_at, reason)
+			VALUES (?, ?, 'recovery_cancelled')
+		`, newDeviceID.String, now); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE recovery_phrases SET is_active = 0 WHERE is_active = 1`); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	m.securityLog.LogSecurityEvent("recovery_cancelled",
+		slog.String("recovery_id", recoveryID),
+		slog.String("new_device_id", newDeviceID.String))
+
+	return nil
+}
+
 // IsDeviceValid checks if a device is still valid (not invalidated)
 func (m *Manager) IsDeviceValid(deviceID string) (bool, error) {
 	m.mu.RLock()
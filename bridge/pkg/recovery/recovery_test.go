@@ -0,0 +1,141 @@
+package recovery
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+const testPhrase = "velvet anchor wisdom album voyage airport unique wolf actual vintage alarm wheat"
+
+// newTestManager returns a manager with a stored phrase and a recovery
+// started from device-new
+func newTestManager(t *testing.T) (*Manager, *RecoveryState) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	m, err := NewManager(db, make([]byte, 32))
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+	if err := m.StorePhrase(testPhrase); err != nil {
+		t.Fatalf("StorePhrase() error = %v", err)
+	}
+	state, err := m.VerifyPhrase(testPhrase, "device-new")
+	if err != nil {
+		t.Fatalf("VerifyPhrase() error = %v", err)
+	}
+	return m, state
+}
+
+func TestValidatePhrase(t *testing.T) {
+	tests := []struct {
+		name    string
+		phrase  string
+		wantErr string
+	}{
+		{"valid", "velvet anchor wisdom album voyage airport unique wolf actual vintage alarm wheat", ""},
+		{"mixed case and spacing", "  Velvet ANCHOR wisdom album voyage airport unique wolf actual vintage alarm\twheat ", ""},
+		{"three in wordlist order", "abandon ability able wisdom album voyage airport unique wolf actual vintage alarm", ""},
+		{"too short", "velvet anchor wisdom", "must have 12 words, got 3"},
+		{"too long", "velvet anchor wisdom album voyage airport unique wolf actual vintage alarm wheat zebra", "must have 12 words, got 13"},
+		{"unknown word", "velvet anchor wisdom album voyage airport unique wolf actual vintage alarm password", `word 12 ("password") is not in the recovery wordlist`},
+		{"duplicate word", "velvet anchor wisdom album voyage velvet unique wolf actual vintage alarm wheat", `"velvet" is repeated at positions 1 and 6`},
+		{"duplicate differing case", "velvet anchor wisdom album voyage VELVET unique wolf actual vintage alarm wheat", "is repeated"},
+		{"bip39 test vector", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "is repeated"},
+		{"ascending run", "velvet anchor abandon ability able about wisdom album voyage airport unique wolf", "words 3-6 follow the wordlist order"},
+		{"descending run", "velvet about able ability abandon anchor wisdom album voyage airport unique wolf", "words 2-5 follow the wordlist order"},
+		{"alphabetical", "actual airport alarm album anchor unique velvet vintage voyage wheat wisdom wolf", "alphabetical order"},
+		{"reverse alphabetical", "wolf wisdom wheat voyage vintage velvet unique anchor album alarm airport actual", "alphabetical order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePhrase(tt.phrase)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidatePhrase() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidatePhrase() error = %v, want %q", err, tt.wantErr)
+			}
+			if !errors.Is(err, ErrInvalidPhrase) {
+				t.Errorf("ValidatePhrase() error = %v, want ErrInvalidPhrase", err)
+			}
+		})
+	}
+}
+
+func TestGeneratePhraseValidates(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		phrase, err := GeneratePhrase()
+		if err != nil {
+			t.Fatalf("GeneratePhrase() error = %v", err)
+		}
+		if err := ValidatePhrase(phrase); err != nil {
+			t.Fatalf("generated phrase %q failed validation: %v", phrase, err)
+		}
+	}
+}
+
+func TestAuthorizeCancel(t *testing.T) {
+	m, state := newTestManager(t)
+	if _, err := m.db.Exec(`INSERT INTO invalidated_devices (device_id, invalidated_at, reason) VALUES ('device-lost', 0, 'recovery')`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.AuthorizeCancel(state.ID, "device-owner"); err != nil {
+		t.Errorf("AuthorizeCancel() from a valid device error = %v", err)
+	}
+	for _, device := range []string{"device-new", "device-lost", ""} {
+		if err := m.AuthorizeCancel(state.ID, device); !errors.Is(err, ErrDeviceNotAllowed) {
+			t.Errorf("AuthorizeCancel(%q) error = %v, want ErrDeviceNotAllowed", device, err)
+		}
+	}
+	if err := m.AuthorizeCancel("missing", "device-owner"); !errors.Is(err, ErrRecoveryNotFound) {
+		t.Errorf("AuthorizeCancel() for unknown recovery error = %v, want ErrRecoveryNotFound", err)
+	}
+}
+
+func TestCancelRecovery(t *testing.T) {
+	m, state := newTestManager(t)
+
+	if err := m.CancelRecovery(state.ID); err != nil {
+		t.Fatalf("CancelRecovery() error = %v", err)
+	}
+
+	var status string
+	if err := m.db.QueryRow(`SELECT status FROM recovery_sessions WHERE id = ?`, state.ID).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status != string(RecoveryStatusCancelled) {
+		t.Errorf("status = %q, want cancelled", status)
+	}
+
+	if valid, _ := m.IsDeviceValid("device-new"); valid {
+		t.Error("recovering device should be invalidated")
+	}
+	if set, _ := m.IsRecoveryPhraseSet(); set {
+		t.Error("recovery phrase should be invalidated")
+	}
+	if _, err := m.VerifyPhrase(testPhrase, "device-other"); !errors.Is(err, ErrInvalidPhrase) {
+		t.Errorf("VerifyPhrase() after cancel error = %v, want ErrInvalidPhrase", err)
+	}
+
+	if err := m.CancelRecovery(state.ID); !errors.Is(err, ErrRecoveryNotActive) {
+		t.Errorf("second CancelRecovery() error = %v, want ErrRecoveryNotActive", err)
+	}
+	if err := m.CompleteRecovery(state.ID, nil); !errors.Is(err, ErrRecoveryNotActive) {
+		t.Errorf("CompleteRecovery() after cancel error = %v, want ErrRecoveryNotActive", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/armorclaw/bridge/internal/ai"
+	"github.com/armorclaw/bridge/pkg/budget"
 	"github.com/google/uuid"
 )
 
@@ -31,6 +32,23 @@ func (s *Server) handleAIChat(ctx context.Context, req *Request) (interface{}, *
 		return nil, &ErrorObj{Code: TooManyRequests, Message: "AI rate limit exceeded"}
 	}
 
+	// Past the limit in throttle mode, hold the request back before it takes
+	// a slot and switch it to the cheaper model
+	var throttle budget.Throttle
+	if s.budget != nil {
+		throttle = s.budget.Throttle()
+	}
+	if throttle.Active {
+		select {
+		case <-time.After(throttle.Delay):
+		case <-ctx.Done():
+			return nil, &ErrorObj{
+				Code:    RequestCancelled,
+				Message: "request cancelled",
+			}
+		}
+	}
+
 	// Acquire slot with context cancellation support (prevents goroutine leak)
 	select {
 	case s.aiSemaphore <- struct{}{}:
@@ -54,6 +72,13 @@ func (s *Server) handleAIChat(ctx context.Context, req *Request) (interface{}, *
 	if model == "" {
 		model = "gpt-4o"
 	}
+	if throttle.Active {
+		requested := model
+		if throttle.Model != "" {
+			model = throttle.Model
+		}
+		slog.Info("ai.chat throttled by budget", "requested_model", requested, "model", model, "delay_ms", throttle.Delay.Milliseconds())
+	}
 
 	chatReq := ai.ChatRequest{
 		Model:       model,
@@ -90,6 +115,21 @@ func (s *Server) handleAIChat(ctx context.Context, req *Request) (interface{}, *
 	}
 
 	latency := time.Since(start)
+
+	// Attribute spend to the key so budget.breakdown can single it out
+	if s.budget != nil {
+		if err := s.budget.RecordUsage(budget.UsageRecord{
+			SessionID:    chatReq.RequestID,
+			KeyID:        keyID,
+			Provider:     resp.Provider,
+			Model:        resp.Model,
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		}); err != nil {
+			slog.Warn("ai.chat budget record failed", "request_id", chatReq.RequestID, "key_id", keyID, "error", err)
+		}
+	}
+
 	slog.Info("ai.chat completed",
 		"request_id", chatReq.RequestID,
 		"model", model,
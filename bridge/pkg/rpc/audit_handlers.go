@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/audit"
+)
+
+// AuditExportRequest holds parameters for audit.export
+type AuditExportRequest struct {
+	EventType string `json:"event_type,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	RoomID    string `json:"room_id,omitempty"`
+	Since     string `json:"since,omitempty"` // RFC3339
+	Until     string `json:"until,omitempty"` // RFC3339
+	Limit     int    `json:"limit,omitempty"` // most recent N; 0 = all
+	File      string `json:"file,omitempty"`  // write to this file in the export directory instead of returning data
+}
+
+// handleAuditExport exports matching audit entries as CSV, oldest first,
+// along with a verification of the audit hash chain. The export is either
+// written to a new file in the export directory or returned base64 encoded.
+func (s *Server) handleAuditExport(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	if s.auditLog == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "audit log not configured",
+		}
+	}
+
+	var params AuditExportRequest
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+
+	query := audit.QueryParams{
+		Limit:     params.Limit,
+		EventType: audit.EventType(params.EventType),
+		SessionID: params.SessionID,
+		RoomID:    params.RoomID,
+	}
+	if params.Since != "" {
+		t, err := time.Parse(time.RFC3339, params.Since)
+		if err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "since must be an RFC3339 timestamp",
+			}
+		}
+		query.Since = t
+	}
+	if params.Until != "" {
+		t, err := time.Parse(time.RFC3339, params.Until)
+		if err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "until must be an RFC3339 timestamp",
+			}
+		}
+		query.Until = t
+	}
+
+	entries := 0
+	result, errObj := s.writeExport(params.File, "audit log", func(w io.Writer) error {
+		n, err := s.auditLog.ExportCSV(w, query)
+		entries = n
+		return err
+	})
+	if errObj != nil {
+		return nil, errObj
+	}
+
+	result["format"] = "csv"
+	result["entries"] = entries
+	result["chain"] = s.auditLog.VerifyChain()
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/armorclaw/bridge/pkg/audit"
+)
+
+func newTestAuditLog(t *testing.T) *audit.AuditLog {
+	t.Helper()
+
+	al, err := audit.NewAuditLog(audit.Config{Path: filepath.Join(t.TempDir(), "audit.json"), HashChain: true, ChainKey: []byte("0123456789abcdef0123456789abcdef")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	al.LogEvent(audit.EventDeviceApproved, "", "", "@admin:example.com", map[string]interface{}{"device_id": "dev-1"})
+	al.LogEvent(audit.EventInviteCreated, "", "", "@admin:example.com", map[string]interface{}{"role": "user"})
+	return al
+}
+
+func TestAuditExport(t *testing.T) {
+	server := &Server{auditLog: newTestAuditLog(t)}
+
+	result, errObj := server.handleAuditExport(context.Background(), &Request{
+		Params: json.RawMessage(`{"event_type": "device.approved"}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+	res := result.(map[string]interface{})
+	if res["entries"] != 1 || res["format"] != "csv" {
+		t.Errorf("result = %+v, want 1 csv entry", res)
+	}
+	if chain := res["chain"].(*audit.VerificationResult); !chain.Valid {
+		t.Errorf("chain = %+v, want valid", chain)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(res["data"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil || len(records) != 2 || records[1][1] != string(audit.EventDeviceApproved) {
+		t.Errorf("records = %v, err = %v; want the device approval", records, err)
+	}
+}
+
+func TestAuditExportToPath(t *testing.T) {
+	server := &Server{auditLog: newTestAuditLog(t), exportDir: t.TempDir()}
+	path := filepath.Join(server.exportDir, "audit.csv")
+
+	result, errObj := server.handleAuditExport(context.Background(), &Request{
+		Params: json.RawMessage(`{"file": "audit.csv"}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+	if res := result.(map[string]interface{}); res["path"] != path || res["entries"] != 2 {
+		t.Errorf("result = %+v, want 2 entries at %s", res, path)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("export file = %v, %v; want mode 0600", info, err)
+	}
+}
+
+func TestAuditExportErrors(t *testing.T) {
+	_, errObj := (&Server{}).handleAuditExport(context.Background(), &Request{})
+	if errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError without an audit log, got %+v", errObj)
+	}
+
+	server := &Server{auditLog: newTestAuditLog(t), exportDir: t.TempDir()}
+	for _, params := range []string{
+		`{"file": "/etc/cron.d/audit"}`,
+		`{"file": "../audit.csv"}`,
+		`{"file": ".."}`,
+		`{"since": "yesterday"}`,
+		`{"until": "2026-13-45"}`,
+	} {
+		_, errObj := server.handleAuditExport(context.Background(), &Request{
+			Params: json.RawMessage(params),
+		})
+		if errObj == nil || errObj.Code != InvalidParams {
+			t.Errorf("%s: expected InvalidParams, got %+v", params, errObj)
+		}
+	}
+}
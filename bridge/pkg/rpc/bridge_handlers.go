@@ -83,7 +83,14 @@ func (s *Server) handleBridgeStatus(ctx context.Context, req *Request) (interfac
 		}
 	}
 
-	// Always include TLS metadata (mandatory in all modes)
+	// Uptime and build info let operators spot restarts and correlate deploys
+	if !s.startTime.IsZero() {
+		stats["started_at"] = s.startTime.UTC().Format(time.RFC3339)
+		stats["uptime_seconds"] = int64(time.Since(s.startTime).Seconds())
+	}
+	stats["build_time"] = s.buildTime
+
+		// Always include TLS metadata (mandatory in all modes)
 	if s.tlsInfoProvider != nil {
 		stats["tls"] = s.tlsInfoProvider.GetTLSInfo()
 	} else {
@@ -101,7 +108,9 @@ func (s *Server) handleBridgeStatus(ctx context.Context, req *Request) (interfac
 	return stats, nil
 }
 
-// handleBridgeChannel creates a bridge between Matrix room and platform channel
+// handleBridgeChannel creates a bridge between Matrix room and platform channel.
+// With backfill_limit set, up to that many recent platform messages are then
+// copied into the room; a failed backfill is reported but keeps the bridge.
 func (s *Server) handleBridgeChannel(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	if isInterfaceNil(s.bridgeMgr) {
 		return nil, &ErrorObj{
@@ -111,9 +120,10 @@ func (s *Server) handleBridgeChannel(ctx context.Context, req *Request) (interfa
 	}
 
 	var params struct {
-		MatrixRoomID string `json:"matrix_room_id"`
-		Platform     string `json:"platform"`
-		ChannelID    string `json:"channel_id"`
+		MatrixRoomID  string `json:"matrix_room_id"`
+		Platform      string `json:"platform"`
+		ChannelID     string `json:"channel_id"`
+		BackfillLimit int    `json:"backfill_limit,omitempty"`
 	}
 
 	if len(req.Params) == 0 {
@@ -137,20 +147,37 @@ func (s *Server) handleBridgeChannel(ctx context.Context, req *Request) (interfa
 		}
 	}
 
+	if params.BackfillLimit < 0 {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "backfill_limit must not be negative",
+		}
+	}
+
 	platform := appservice.Platform(params.Platform)
 	if err := s.bridgeMgr.BridgeChannel(params.MatrixRoomID, platform, params.ChannelID); err != nil {
 		return nil, &ErrorObj{
 			Code:    InternalError,
-			Message: "Bridge manager not configured",
+			Message: fmt.Sprintf("failed to bridge channel: %s", err.Error()),
 		}
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"status":      "bridged",
 		"matrix_room": params.MatrixRoomID,
 		"platform":    params.Platform,
 		"channel_id":  params.ChannelID,
-	}, nil
+	}
+
+	if params.BackfillLimit > 0 {
+		backfilled, err := s.bridgeMgr.Backfill(ctx, platform, params.ChannelID, params.BackfillLimit)
+		result["backfilled"] = backfilled
+		if err != nil {
+			result["backfill_error"] = err.Error()
+		}
+	}
+
+	return result, nil
 }
 
 // handleUnbridgeChannel removes a bridge
@@ -238,6 +265,27 @@ func (s *Server) handleGhostUserList(ctx context.Context, req *Request) (interfa
 	}, nil
 }
 
+// handleSyncGhostProfiles refreshes ghost display names and avatars from
+// their platforms without waiting for the periodic sync
+func (s *Server) handleSyncGhostProfiles(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	if isInterfaceNil(s.bridgeMgr) {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "Bridge manager not configured",
+		}
+	}
+
+	result, err := s.bridgeMgr.SyncGhostProfiles(ctx)
+	if err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: fmt.Sprintf("failed to sync ghost profiles: %s", err.Error()),
+		}
+	}
+
+	return result, nil
+}
+
 // handleAppServiceStatus returns AppService status
 func (s *Server) handleAppServiceStatus(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	if isInterfaceNil(s.appService) {
@@ -3,8 +3,11 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
+
+	"github.com/armorclaw/bridge/pkg/appservice"
 )
 
 func TestHeartbeatEndpoint(t *testing.T) {
@@ -160,3 +163,131 @@ func TestHeartbeatTracking(t *testing.T) {
 		t.Errorf("GetLastHeartbeat for empty userID should return zero time, got %v", emptyUserHeartbeat)
 	}
 }
+
+func TestBridgeStatusUptime(t *testing.T) {
+	started := time.Now().Add(-90 * time.Second)
+	server := &Server{
+		startTime: started,
+		buildTime: "2026-01-02T03:04:05Z",
+	}
+
+	result, errObj := server.handleBridgeStatus(context.Background(), &Request{})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj)
+	}
+
+	stats, ok := result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected map[string]interface{}, got %T", result)
+	}
+
+	if uptime, ok := stats["uptime_seconds"].(int64); !ok || uptime < 90 {
+		t.Errorf("expected uptime_seconds >= 90, got %v", stats["uptime_seconds"])
+	}
+	if stats["started_at"] != started.UTC().Format(time.RFC3339) {
+		t.Errorf("unexpected started_at: %v", stats["started_at"])
+	}
+	if stats["build_time"] != "2026-01-02T03:04:05Z" {
+		t.Errorf("unexpected build_time: %v", stats["build_time"])
+	}
+}
+
+// fakeBridgeManager records bridges and returns a canned backfill result
+type fakeBridgeManager struct {
+	bridged     map[string]string
+	backfilled  int
+	backfillErr error
+	lastLimit   int
+	syncResult  appservice.ProfileSyncResult
+}
+
+func (f *fakeBridgeManager) Start() error { return nil }
+func (f *fakeBridgeManager) Stop() error  { return nil }
+func (f *fakeBridgeManager) RegisterAdapter(platform appservice.Platform, adapter interface{}) error {
+	return nil
+}
+func (f *fakeBridgeManager) BridgeChannel(matrixRoomID string, platform appservice.Platform, channelID string) error {
+	key := string(platform) + ":" + channelID
+	if _, exists := f.bridged[key]; exists {
+		return errors.New("channel " + key + " already bridged")
+	}
+	f.bridged[key] = matrixRoomID
+	return nil
+}
+func (f *fakeBridgeManager) UnbridgeChannel(platform appservice.Platform, channelID string) error {
+	return nil
+}
+func (f *fakeBridgeManager) Backfill(ctx context.Context, platform appservice.Platform, channelID string, limit int) (int, error) {
+	f.lastLimit = limit
+	return f.backfilled, f.backfillErr
+}
+func (f *fakeBridgeManager) SyncGhostProfiles(ctx context.Context) (appservice.ProfileSyncResult, error) {
+	return f.syncResult, nil
+}
+func (f *fakeBridgeManager) GetBridgedChannels() []*appservice.BridgedChannel { return nil }
+func (f *fakeBridgeManager) GetStats() map[string]interface{}                 { return nil }
+
+func TestBridgeChannelBackfill(t *testing.T) {
+	mgr := &fakeBridgeManager{bridged: map[string]string{}, backfilled: 3}
+	server := &Server{bridgeMgr: mgr}
+
+	result, errObj := server.handleBridgeChannel(context.Background(), &Request{
+		Params: json.RawMessage(`{"matrix_room_id": "!room:example.com", "platform": "slack", "channel_id": "C1", "backfill_limit": 25}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+	res := result.(map[string]interface{})
+	if res["backfilled"] != 3 || mgr.lastLimit != 25 {
+		t.Errorf("result = %+v, limit = %d; want 3 backfilled with limit 25", res, mgr.lastLimit)
+	}
+
+	// A failed backfill keeps the bridge and reports the error
+	mgr.backfilled, mgr.backfillErr = 1, errors.New("rate limited")
+	result, errObj = server.handleBridgeChannel(context.Background(), &Request{
+		Params: json.RawMessage(`{"matrix_room_id": "!other:example.com", "platform": "slack", "channel_id": "C2", "backfill_limit": 10}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+	res = result.(map[string]interface{})
+	if res["status"] != "bridged" || res["backfilled"] != 1 || res["backfill_error"] != "rate limited" {
+		t.Errorf("result = %+v, want bridged with the backfill error", res)
+	}
+}
+
+func TestBridgeChannelErrors(t *testing.T) {
+	mgr := &fakeBridgeManager{bridged: map[string]string{"slack:C1": "!room:example.com"}}
+	server := &Server{bridgeMgr: mgr}
+
+	_, errObj := server.handleBridgeChannel(context.Background(), &Request{
+		Params: json.RawMessage(`{"matrix_room_id": "!room:example.com", "platform": "slack", "channel_id": "C2", "backfill_limit": -1}`),
+	})
+	if errObj == nil || errObj.Code != InvalidParams {
+		t.Errorf("expected InvalidParams for a negative backfill_limit, got %+v", errObj)
+	}
+
+	_, errObj = server.handleBridgeChannel(context.Background(), &Request{
+		Params: json.RawMessage(`{"matrix_room_id": "!room:example.com", "platform": "slack", "channel_id": "C1"}`),
+	})
+	if errObj == nil || errObj.Message != "failed to bridge channel: channel slack:C1 already bridged" {
+		t.Errorf("expected the bridge failure to be reported, got %+v", errObj)
+	}
+}
+
+func TestSyncGhostProfilesHandler(t *testing.T) {
+	_, errObj := (&Server{}).handleSyncGhostProfiles(context.Background(), &Request{})
+	if errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError without a bridge manager, got %+v", errObj)
+	}
+
+	want := appservice.ProfileSyncResult{Checked: 4, Updated: 2, Failed: 1}
+	server := &Server{bridgeMgr: &fakeBridgeManager{syncResult: want}}
+	result, errObj := server.handleSyncGhostProfiles(context.Background(), &Request{})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+	if result != want {
+		t.Errorf("result = %+v, want %+v", result, want)
+	}
+}
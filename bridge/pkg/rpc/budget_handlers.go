@@ -0,0 +1,96 @@
+// Package rpc provides budget reporting RPC methods.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// budgetPeriodStart maps a breakdown period to the time it starts at
+func budgetPeriodStart(period string, now time.Time) (time.Time, bool) {
+	switch period {
+	case "day":
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), true
+	case "", "month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), true
+	case "all":
+		return time.Time{}, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// handleBudgetBreakdown returns spend for the current day or month grouped
+// by provider and by keystore credential, so a runaway key shows up before
+// the global limit is reached
+func (s *Server) handleBudgetBreakdown(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		Period string `json:"period,omitempty"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+		}
+	}
+
+	since, ok := budgetPeriodStart(params.Period, time.Now())
+	if !ok {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "period must be one of: day, month, all"}
+	}
+	if params.Period == "" {
+		params.Period = "month"
+	}
+
+	if s.budget == nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "budget tracking not configured"}
+	}
+
+	breakdown := s.budget.Breakdown(since)
+	return map[string]interface{}{
+		"period":            params.Period,
+		"since":             breakdown.Since.Unix(),
+		"total_usd":         breakdown.TotalUSD,
+		"unattributed_usd":  breakdown.UnattributedUSD,
+		"by_provider":       breakdown.ByProvider,
+		"by_key":            breakdown.ByKey,
+		"daily_limit_usd":   s.budget.GetDailyLimit(),
+		"monthly_limit_usd": s.budget.GetMonthlyLimit(),
+	}, nil
+}
+
+// remainingBudget returns what is left of limit, or nil when there is no limit
+func remainingBudget(spent, limit float64) interface{} {
+	if limit <= 0 {
+		return nil
+	}
+	return math.Max(limit-spent, 0)
+}
+
+// handleBudgetStatus reports spend against the period and daily limits. The
+// period window follows the configured reset schedule, so clients can show
+// when the budget resets instead of assuming calendar months.
+func (s *Server) handleBudgetStatus(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	if s.budget == nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "budget tracking not configured"}
+	}
+
+	status := s.budget.Status()
+	return map[string]interface{}{
+		"schedule":            status.Schedule,
+		"window_start":        status.Window.Start.Unix(),
+		"window_end":          status.Window.End.Unix(),
+		"spent_usd":           status.PeriodSpentUSD,
+		"limit_usd":           status.PeriodLimitUSD,
+		"remaining_usd":       remainingBudget(status.PeriodSpentUSD, status.PeriodLimitUSD),
+		"daily_spent_usd":     status.DailySpentUSD,
+		"daily_limit_usd":     status.DailyLimitUSD,
+		"daily_remaining_usd": remainingBudget(status.DailySpentUSD, status.DailyLimitUSD),
+		"state":               s.budget.GetWorkflowState().String(),
+		"mode":                string(status.Mode),
+		"throttled":           status.Throttle.Active,
+		"throttle_delay_ms":   status.Throttle.Delay.Milliseconds(),
+		"throttle_model":      status.Throttle.Model,
+	}, nil
+}
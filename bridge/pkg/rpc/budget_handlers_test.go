@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/budget"
+)
+
+func TestBudgetBreakdown(t *testing.T) {
+	server := &Server{}
+	breakdown := func(params string) (map[string]interface{}, *ErrorObj) {
+		result, errObj := server.handleBudgetBreakdown(context.Background(), &Request{Params: json.RawMessage(params)})
+		if errObj != nil {
+			return nil, errObj
+		}
+		return result.(map[string]interface{}), nil
+	}
+
+	if _, errObj := breakdown(`{}`); errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError without a budget tracker, got %+v", errObj)
+	}
+
+	tracker, err := budget.NewBudgetTracker(budget.BudgetConfig{DailyLimitUSD: 10, MonthlyLimitUSD: 100})
+	if err != nil {
+		t.Fatalf("failed to create budget tracker: %v", err)
+	}
+	server.budget = tracker
+	tracker.RecordKeyUsage("openai-main", "openai", 2.5)
+	tracker.RecordKeyUsage("anthropic-main", "anthropic", 1.0)
+
+	if _, errObj := breakdown(`{"period":"week"}`); errObj == nil || errObj.Code != InvalidParams {
+		t.Errorf("expected InvalidParams for an unknown period, got %+v", errObj)
+	}
+
+	result, errObj := breakdown(`{}`)
+	if errObj != nil {
+		t.Fatalf("budget.breakdown failed: %+v", errObj)
+	}
+	if result["period"] != "month" || result["total_usd"] != 3.5 {
+		t.Errorf("expected month totals of 3.5, got %+v", result)
+	}
+	keys := result["by_key"].([]budget.KeySpend)
+	if len(keys) != 2 || keys[0].KeyID != "openai-main" {
+		t.Errorf("expected openai-main to lead keys, got %+v", keys)
+	}
+
+	result, errObj = breakdown(`{"period":"day"}`)
+	if errObj != nil {
+		t.Fatalf("budget.breakdown failed: %+v", errObj)
+	}
+	if since := time.Unix(result["since"].(int64), 0); since.After(time.Now()) || time.Since(since) > 24*time.Hour {
+		t.Errorf("expected since to be the start of today, got %v", since)
+	}
+}
+
+func TestBudgetStatus(t *testing.T) {
+	server := &Server{}
+	if _, errObj := server.handleBudgetStatus(context.Background(), &Request{}); errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError without a budget tracker, got %+v", errObj)
+	}
+
+	tracker, err := budget.NewBudgetTracker(budget.BudgetConfig{MonthlyLimitUSD: 20, ResetSchedule: "weekly:sun"})
+	if err != nil {
+		t.Fatalf("failed to create budget tracker: %v", err)
+	}
+	server.budget = tracker
+	tracker.RecordKeyUsage("openai-main", "openai", 5)
+
+	result, errObj := server.handleBudgetStatus(context.Background(), &Request{})
+	if errObj != nil {
+		t.Fatalf("budget.status failed: %+v", errObj)
+	}
+	status := result.(map[string]interface{})
+
+	if status["schedule"] != "weekly:sun" || status["remaining_usd"] != 15.0 {
+		t.Errorf("expected 15 remaining on a weekly schedule, got %+v", status)
+	}
+	if status["daily_remaining_usd"] != nil {
+		t.Errorf("expected no daily remaining without a daily limit, got %v", status["daily_remaining_usd"])
+	}
+	start, end := time.Unix(status["window_start"].(int64), 0), time.Unix(status["window_end"].(int64), 0)
+	if start.Weekday() != time.Sunday || !time.Now().Before(end) || time.Now().Before(start) {
+		t.Errorf("expected the current Sunday-based window, got [%v, %v)", start, end)
+	}
+}
+
+func TestBudgetStatusThrottled(t *testing.T) {
+	tracker, err := budget.NewBudgetTracker(budget.BudgetConfig{
+		DailyLimitUSD: 10,
+		Mode:          "throttle",
+		ThrottleDelay: 2 * time.Second,
+		ThrottleModel: "gpt-3.5-turbo",
+	})
+	if err != nil {
+		t.Fatalf("failed to create budget tracker: %v", err)
+	}
+	server := &Server{budget: tracker}
+	tracker.RecordKeyUsage("openai-main", "openai", 15)
+
+	result, errObj := server.handleBudgetStatus(context.Background(), &Request{})
+	if errObj != nil {
+		t.Fatalf("budget.status failed: %+v", errObj)
+	}
+	status := result.(map[string]interface{})
+
+	if status["mode"] != "throttle" || status["state"] != "throttled" || status["throttled"] != true {
+		t.Errorf("expected an active throttle, got %+v", status)
+	}
+	if status["throttle_delay_ms"] != int64(3000) || status["throttle_model"] != "gpt-3.5-turbo" {
+		t.Errorf("expected a 3s delay on gpt-3.5-turbo at 150%% of the limit, got %+v", status)
+	}
+}
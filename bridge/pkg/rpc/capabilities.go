@@ -0,0 +1,141 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/license"
+)
+
+const (
+	// ProtocolVersion is the RPC protocol revision this bridge speaks. It is
+	// bumped when a change breaks existing clients, such as a removed method
+	// or a renamed field.
+	ProtocolVersion = 1
+	// MinProtocolVersion is the oldest client protocol still served
+	MinProtocolVersion = 1
+)
+
+// MethodSchema versions the params and result of one RPC method. A version
+// is bumped whenever the shape changes, so a client can tell whether the
+// fields it relies on are present before calling.
+type MethodSchema struct {
+	Name          string `json:"name"`
+	ParamsVersion int    `json:"params_version"`
+	ResultVersion int    `json:"result_version"`
+}
+
+// methodSchemaRevisions lists methods whose schema moved past version 1.
+// Methods not listed are at version 1 for both params and result.
+var methodSchemaRevisions = map[string]MethodSchema{
+	// graceful, timeout_seconds and container_name
+	"container.terminate": {ParamsVersion: 2, ResultVersion: 2},
+	// limit/offset paging with total
+	"container.list": {ParamsVersion: 2, ResultVersion: 2},
+	// mode, throttled, throttle_delay_ms and throttle_model
+	"budget.status": {ParamsVersion: 1, ResultVersion: 2},
+}
+
+// methodSchemas returns the schema versions of every registered method,
+// sorted by name
+func (s *Server) methodSchemas() []MethodSchema {
+	methods := make([]MethodSchema, 0, len(s.handlers))
+	for name := range s.handlers {
+		schema := MethodSchema{Name: name, ParamsVersion: 1, ResultVersion: 1}
+		if rev, ok := methodSchemaRevisions[name]; ok {
+			schema.ParamsVersion = rev.ParamsVersion
+			schema.ResultVersion = rev.ResultVersion
+		}
+		methods = append(methods, schema)
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return methods
+}
+
+// capabilityFeatures reports which optional subsystems are configured.
+// Their methods are always registered, but return errors when the
+// subsystem is missing, so clients should check these flags first.
+func (s *Server) capabilityFeatures() map[string]bool {
+	return map[string]bool{
+		"matrix":     !isInterfaceNil(s.matrix),
+		"ai":         s.aiService != nil,
+		"containers": s.dockerClient != nil,
+		"budget":     s.budget != nil,
+		"webrtc":     s.webrtcEngine != nil,
+		"push":       s.pushGateway != nil,
+		"audit":      s.auditLog != nil,
+		"plugins":    s.pluginManager != nil,
+		"recovery":   s.recoveryManager != nil,
+		"devices":    s.deviceStore != nil,
+		"invites":    s.inviteStore != nil,
+		"workflows":  !isInterfaceNil(s.secretaryHandler),
+		"pii":        s.piiRequestManager != nil,
+		"events":     s.eventBus != nil,
+		"bridges":    !isInterfaceNil(s.bridgeMgr),
+	}
+}
+
+// capabilityLicense summarizes the cached license. Without a license client
+// or a cached validation the bridge reports the free tier, so clients hide
+// premium features rather than offering calls that would be refused.
+func (s *Server) capabilityLicense() map[string]interface{} {
+	var cached *license.CachedLicense
+	if s.licenseClient != nil {
+		cached = s.licenseClient.GetCached("license-info")
+		if cached == nil {
+			cached = s.licenseClient.GetCached("")
+		}
+	}
+
+	if cached == nil || !cached.IsValid() {
+		return map[string]interface{}{
+			"tier":     string(license.TierFree),
+			"features": []string{},
+			"verified": false,
+		}
+	}
+
+	features := cached.Features
+	if features == nil {
+		features = []string{}
+	}
+	return map[string]interface{}{
+		"tier":       string(cached.Tier),
+		"features":   features,
+		"expires_at": cached.ExpiresAt.UTC().Format(time.RFC3339),
+		"verified":   true,
+	}
+}
+
+// handleBridgeCapabilities returns the protocol version, every method with
+// its schema versions, configured features and the license tier. A client
+// may pass its own protocol_version; compatible then says whether the
+// bridge still serves it.
+func (s *Server) handleBridgeCapabilities(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		ProtocolVersion int `json:"protocol_version,omitempty"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+
+	result := map[string]interface{}{
+		"version":              BridgeVersion,
+		"protocol_version":     ProtocolVersion,
+		"min_protocol_version": MinProtocolVersion,
+		"methods":              s.methodSchemas(),
+		"features":             s.capabilityFeatures(),
+		"license":              s.capabilityLicense(),
+	}
+	if params.ProtocolVersion != 0 {
+		result["compatible"] = params.ProtocolVersion >= MinProtocolVersion && params.ProtocolVersion <= ProtocolVersion
+	}
+	return result, nil
+}
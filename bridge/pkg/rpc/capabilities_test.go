@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/license"
+)
+
+func callCapabilities(t *testing.T, server *Server, params string) map[string]interface{} {
+	t.Helper()
+	req := &Request{JSONRPC: JSONRPCVersion, ID: 1, Method: "bridge.capabilities"}
+	if params != "" {
+		req.Params = json.RawMessage(params)
+	}
+	result, rpcErr := server.handleBridgeCapabilities(context.Background(), req)
+	if rpcErr != nil {
+		t.Fatalf("bridge.capabilities error: %s", rpcErr.Message)
+	}
+	return result.(map[string]interface{})
+}
+
+func TestBridgeCapabilities_Methods(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	result := callCapabilities(t, server, "")
+	if result["protocol_version"] != ProtocolVersion {
+		t.Errorf("protocol_version = %v, want %d", result["protocol_version"], ProtocolVersion)
+	}
+	if _, ok := result["compatible"]; ok {
+		t.Error("compatible should only be reported when the client sends its version")
+	}
+
+	methods := result["methods"].([]MethodSchema)
+	if len(methods) != len(server.handlers) {
+		t.Fatalf("got %d methods, want %d", len(methods), len(server.handlers))
+	}
+	byName := make(map[string]MethodSchema)
+	for i, m := range methods {
+		if i > 0 && methods[i-1].Name >= m.Name {
+			t.Errorf("methods not sorted: %s before %s", methods[i-1].Name, m.Name)
+		}
+		byName[m.Name] = m
+	}
+	if m := byName["bridge.capabilities"]; m.ParamsVersion != 1 || m.ResultVersion != 1 {
+		t.Errorf("bridge.capabilities schema = %+v, want 1/1", m)
+	}
+	if m := byName["budget.status"]; m.ResultVersion != 2 {
+		t.Errorf("budget.status result version = %d, want 2", m.ResultVersion)
+	}
+	for name := range methodSchemaRevisions {
+		if _, ok := server.handlers[name]; !ok {
+			t.Errorf("schema revision for unregistered method %s", name)
+		}
+	}
+
+	features := result["features"].(map[string]bool)
+	if features["containers"] || features["budget"] {
+		t.Errorf("unconfigured subsystems reported as available: %v", features)
+	}
+}
+
+func TestBridgeCapabilities_ProtocolNegotiation(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	if got := callCapabilities(t, server, `{"protocol_version": 1}`)["compatible"]; got != true {
+		t.Errorf("compatible for current protocol = %v, want true", got)
+	}
+	if got := callCapabilities(t, server, `{"protocol_version": 99}`)["compatible"]; got != false {
+		t.Errorf("compatible for future protocol = %v, want false", got)
+	}
+
+	_, rpcErr := server.handleBridgeCapabilities(context.Background(), &Request{
+		JSONRPC: JSONRPCVersion,
+		ID:      1,
+		Method:  "bridge.capabilities",
+		Params:  json.RawMessage(`{"protocol_version": "one"}`),
+	})
+	if rpcErr == nil || rpcErr.Code != InvalidParams {
+		t.Errorf("expected InvalidParams for a malformed version, got %v", rpcErr)
+	}
+}
+
+func TestBridgeCapabilities_License(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	lic := callCapabilities(t, server, "")["license"].(map[string]interface{})
+	if lic["tier"] != string(license.TierFree) || lic["verified"] != false {
+		t.Errorf("license without client = %v, want unverified free tier", lic)
+	}
+
+	expires := time.Now().Add(30 * 24 * time.Hour)
+	server.licenseClient = &stubLicenseCache{cached: &license.CachedLicense{
+		Valid:     true,
+		Tier:      license.TierPro,
+		Features:  []string{"slack-adapter"},
+		ExpiresAt: expires,
+	}}
+	lic = callCapabilities(t, server, "")["license"].(map[string]interface{})
+	if lic["tier"] != string(license.TierPro) || lic["verified"] != true {
+		t.Errorf("license = %v, want verified pro tier", lic)
+	}
+	if features := lic["features"].([]string); len(features) != 1 || features[0] != "slack-adapter" {
+		t.Errorf("features = %v, want [slack-adapter]", features)
+	}
+
+	// An expired license past its grace period falls back to free
+	server.licenseClient = &stubLicenseCache{cached: &license.CachedLicense{
+		Valid:     true,
+		Tier:      license.TierEnterprise,
+		ExpiresAt: time.Now().Add(-time.Hour),
+	}}
+	if lic := callCapabilities(t, server, "")["license"].(map[string]interface{}); lic["tier"] != string(license.TierFree) {
+		t.Errorf("expired license tier = %v, want free", lic["tier"])
+	}
+}
@@ -2,22 +2,45 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/armorclaw/bridge/pkg/docker"
+	"github.com/armorclaw/bridge/pkg/logger"
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
 var _ = docker.ScopeCreate
 
-// handleTerminateContainer terminates a container immediately (SIGKILL)
+// Graceful stop timeouts for container.terminate
+const (
+	defaultGracefulStopTimeout = 10
+	maxGracefulStopTimeout     = 300
+)
+
+// handleTerminateContainer terminates a container immediately (SIGKILL) by
+// default. With graceful set it sends SIGTERM, waits up to timeout_seconds
+// for the container to exit, then force removes it.
+// The container is identified by container_id or container_name (not both)
 // Requires authentication and container ownership verification
 func (s *Server) handleTerminateContainer(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	var params struct {
-		ContainerID string `json:"container_id"`
-		UserID      string `json:"user_id"`
+		ContainerID    string `json:"container_id"`
+		ContainerName  string `json:"container_name"`
+		UserID         string `json:"user_id"`
+		Graceful       bool   `json:"graceful,omitempty"`
+		TimeoutSeconds *int   `json:"timeout_seconds,omitempty"`
 	}
 
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -27,10 +50,17 @@ func (s *Server) handleTerminateContainer(ctx context.Context, req *Request) (in
 		}
 	}
 
-	if params.ContainerID == "" {
+	if params.ContainerID != "" && params.ContainerName != "" {
 		return nil, &ErrorObj{
 			Code:    InvalidParams,
-			Message: "container_id is required",
+			Message: "container_id and container_name are mutually exclusive",
+		}
+	}
+
+	if params.ContainerID == "" && params.ContainerName == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "container_id or container_name is required",
 		}
 	}
 
@@ -41,6 +71,23 @@ func (s *Server) handleTerminateContainer(ctx context.Context, req *Request) (in
 		}
 	}
 
+	timeout := defaultGracefulStopTimeout
+	if params.TimeoutSeconds != nil {
+		if !params.Graceful {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "timeout_seconds requires graceful",
+			}
+		}
+		timeout = *params.TimeoutSeconds
+		if timeout < 0 || timeout > maxGracefulStopTimeout {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "timeout_seconds must be between 0 and " + strconv.Itoa(maxGracefulStopTimeout),
+			}
+		}
+	}
+
 	if s.dockerClient == nil {
 		return nil, &ErrorObj{
 			Code:    InternalError,
@@ -48,47 +95,115 @@ func (s *Server) handleTerminateContainer(ctx context.Context, req *Request) (in
 		}
 	}
 
-	inspect, err := s.dockerClient.InspectContainer(ctx, params.ContainerID)
-	if err != nil {
+	if params.ContainerName != "" {
+		id, errObj := s.resolveContainerName(ctx, params.ContainerName)
+		if errObj != nil {
+			return nil, errObj
+		}
+		params.ContainerID = id
+	}
+
+	if _, errObj := s.inspectBridgeContainer(ctx, params.ContainerID); errObj != nil {
+		return nil, errObj
+	}
+
+	result := map[string]interface{}{
+		"success":      true,
+		"container_id": params.ContainerID,
+	}
+
+	if params.Graceful {
+		stopped := true
+		if err := s.dockerClient.StopContainer(ctx, params.ContainerID, container.StopOptions{Timeout: &timeout}); err != nil {
+			// Fall through to the force remove so the container still goes away
+			slog.Warn("graceful_stop_failed", "container_id", params.ContainerID, "error", err)
+			stopped = false
+		}
+
+		if err := s.dockerClient.RemoveContainer(ctx, params.ContainerID, true); err != nil {
+			return nil, &ErrorObj{
+				Code:    InternalError,
+				Message: "failed to remove container: " + err.Error(),
+			}
+		}
+
+		result["graceful"] = true
+		result["stopped_gracefully"] = stopped
+		result["timeout_seconds"] = timeout
+	} else if err := s.dockerClient.TerminateContainer(ctx, params.ContainerID); err != nil {
 		return nil, &ErrorObj{
 			Code:    InternalError,
-			Message: "container not found: " + err.Error(),
+			Message: "failed to terminate container: " + err.Error(),
 		}
 	}
+	if params.ContainerName != "" {
+		result["container_name"] = params.ContainerName
+	}
+	return result, nil
+}
 
-	if inspect.Config == nil || inspect.Config.Labels == nil {
-		return nil, &ErrorObj{
+// resolveContainerName maps a human-readable container name to its ID.
+// Docker's name filter matches substrings, so only an exact name is accepted.
+func (s *Server) resolveContainerName(ctx context.Context, name string) (string, *ErrorObj) {
+	name = strings.TrimPrefix(name, "/")
+
+	containers, err := s.dockerClient.ListContainers(ctx, true, filters.NewArgs(filters.Arg("name", name)))
+	if err != nil {
+		return "", &ErrorObj{
 			Code:    InternalError,
-			Message: "container is not managed by Bridge",
+			Message: "failed to resolve container name: " + err.Error(),
 		}
 	}
 
-	hasArmorClawLabels := false
-	for key := range inspect.Config.Labels {
-		if containsArmorClawLabel(key) {
-			hasArmorClawLabels = true
-			break
+	if id := matchContainerName(containers, name); id != "" {
+		return id, nil
+	}
+
+	return "", &ErrorObj{
+		Code:    NotFoundError,
+		Message: "container not found: " + name,
+	}
+}
+
+// matchContainerName returns the ID of the container with exactly the given name
+func matchContainerName(containers []types.Container, name string) string {
+	for _, c := range containers {
+		for _, n := range c.Names {
+			if strings.TrimPrefix(n, "/") == name {
+				return c.ID
+			}
 		}
 	}
+	return ""
+}
 
-	if !hasArmorClawLabels {
-		return nil, &ErrorObj{
+// inspectBridgeContainer inspects a container and verifies Bridge ownership
+func (s *Server) inspectBridgeContainer(ctx context.Context, containerID string) (types.ContainerJSON, *ErrorObj) {
+	inspect, err := s.dockerClient.InspectContainer(ctx, containerID)
+	if err != nil {
+		return inspect, &ErrorObj{
 			Code:    InternalError,
-			Message: "container is not managed by Bridge",
+			Message: "container not found: " + err.Error(),
 		}
 	}
 
-	if err := s.dockerClient.TerminateContainer(ctx, params.ContainerID); err != nil {
-		return nil, &ErrorObj{
+	if inspect.Config == nil || inspect.Config.Labels == nil {
+		return inspect, &ErrorObj{
 			Code:    InternalError,
-			Message: "failed to terminate container: " + err.Error(),
+			Message: "container is not managed by Bridge",
 		}
 	}
 
-	return map[string]interface{}{
-		"success":      true,
-		"container_id": params.ContainerID,
-	}, nil
+	for key := range inspect.Config.Labels {
+		if containsArmorClawLabel(key) {
+			return inspect, nil
+		}
+	}
+
+	return inspect, &ErrorObj{
+		Code:    InternalError,
+		Message: "container is not managed by Bridge",
+	}
 }
 
 // containsArmorClawLabel checks if a label key indicates Bridge ownership
@@ -114,6 +229,7 @@ func (s *Server) handleListContainers(ctx context.Context, req *Request) (interf
 	var params struct {
 		UserID string `json:"user_id"`
 		All    bool   `json:"all,omitempty"`
+		PageParams
 	}
 
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -129,6 +245,9 @@ func (s *Server) handleListContainers(ctx context.Context, req *Request) (interf
 			Message: "user_id is required for authentication",
 		}
 	}
+	if errObj := params.validate(); errObj != nil {
+		return nil, errObj
+	}
 
 	if s.dockerClient == nil {
 		return nil, &ErrorObj{
@@ -157,8 +276,431 @@ func (s *Server) handleListContainers(ctx context.Context, req *Request) (interf
 		}
 	}
 
+	page, info := paginate(bridgeContainers, params.PageParams)
+	return map[string]interface{}{
+		"containers": page,
+		"count":      len(page),
+		"total":      info.Total,
+		"limit":      info.Limit,
+		"offset":     info.Offset,
+	}, nil
+}
+
+// Limits for container.logs responses
+const (
+	defaultContainerLogTail = 100
+	maxContainerLogTail     = 1000
+	maxContainerLogBytes    = 1 << 20 // 1 MiB of log text per response
+	containerLogsTimeout    = 30 * time.Second
+)
+
+// handleContainerLogs returns recent stdout/stderr output of a Bridge-managed
+// container as an array of lines. At most maxContainerLogTail lines and
+// maxContainerLogBytes bytes are returned; when the byte cap is hit the oldest
+// lines are dropped and truncated is set.
+func (s *Server) handleContainerLogs(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		ContainerID string `json:"container_id"`
+		UserID      string `json:"user_id"`
+		Tail        int    `json:"tail,omitempty"`
+		Since       string `json:"since,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.ContainerID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "container_id is required",
+		}
+	}
+
+	if params.UserID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "user_id is required for authentication",
+		}
+	}
+
+	if params.Tail < 0 {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "tail must be positive",
+		}
+	}
+	if params.Tail == 0 {
+		params.Tail = defaultContainerLogTail
+	}
+	if params.Tail > maxContainerLogTail {
+		params.Tail = maxContainerLogTail
+	}
+
+	if params.Since != "" {
+		if _, err := time.Parse(time.RFC3339, params.Since); err != nil {
+			if _, err := time.ParseDuration(params.Since); err != nil {
+				return nil, &ErrorObj{
+					Code:    InvalidParams,
+					Message: "since must be an RFC3339 timestamp or a duration like 10m",
+				}
+			}
+		}
+	}
+
+	if s.dockerClient == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "docker client not configured",
+		}
+	}
+
+	inspect, errObj := s.inspectBridgeContainer(ctx, params.ContainerID)
+	if errObj != nil {
+		return nil, errObj
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, containerLogsTimeout)
+	defer cancel()
+
+	reader, err := s.dockerClient.ContainerLogs(ctx, params.ContainerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(params.Tail),
+		Since:      params.Since,
+	})
+	if err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to get container logs: " + err.Error(),
+		}
+	}
+	defer reader.Close()
+
+	// Non-TTY containers multiplex stdout/stderr; both go to the same buffer
+	// so lines stay in the order Docker emitted them
+	buf := &tailBuffer{max: maxContainerLogBytes}
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(buf, reader)
+	} else {
+		_, err = stdcopy.StdCopy(buf, buf, reader)
+	}
+	if err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to read container logs: " + err.Error(),
+		}
+	}
+
+	lines := buf.Lines()
+	return map[string]interface{}{
+		"container_id": params.ContainerID,
+		"lines":        lines,
+		"count":        len(lines),
+		"truncated":    buf.truncated,
+	}, nil
+}
+
+// Limits for container.exec
+const (
+	defaultContainerExecTimeout = 30
+	maxContainerExecTimeout     = 300
+	maxContainerExecOutput      = 1 << 20 // 1 MiB per stream
+)
+
+// handleContainerExec runs a one-off command inside a running Bridge-managed
+// container and returns its output and exit code. The command runs without a
+// shell; wrap it in ["sh", "-c", ...] for pipes. Each stream keeps its last
+// maxContainerExecOutput bytes. Every exec is written to the security log.
+func (s *Server) handleContainerExec(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		ContainerID    string   `json:"container_id"`
+		Cmd            []string `json:"cmd"`
+		WorkingDir     string   `json:"working_dir,omitempty"`
+		TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.ContainerID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "container_id is required",
+		}
+	}
+
+	if len(params.Cmd) == 0 || params.Cmd[0] == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "cmd is required",
+		}
+	}
+
+	if params.TimeoutSeconds < 0 || params.TimeoutSeconds > maxContainerExecTimeout {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "timeout_seconds must be between 0 and " + strconv.Itoa(maxContainerExecTimeout),
+		}
+	}
+	if params.TimeoutSeconds == 0 {
+		params.TimeoutSeconds = defaultContainerExecTimeout
+	}
+
+	if s.dockerClient == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "docker client not configured",
+		}
+	}
+
+	inspect, errObj := s.inspectBridgeContainer(ctx, params.ContainerID)
+	if errObj != nil {
+		return nil, errObj
+	}
+	if inspect.State == nil || !inspect.State.Running {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "container is not running",
+		}
+	}
+
+	// Record who ran the command from the authenticated session, not params
+	caller := CallerFromContext(ctx)
+	if caller == "" {
+		caller = "local"
+	}
+
+	securityLog := logger.NewSecurityLogger(logger.Global().WithComponent("rpc"))
+	start := time.Now()
+
+	result, err := s.execInContainer(ctx, params.ContainerID, container.ExecOptions{
+		Cmd:          params.Cmd,
+		WorkingDir:   params.WorkingDir,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          false,
+	}, time.Duration(params.TimeoutSeconds)*time.Second)
+
+	duration := time.Since(start)
+	if err != nil {
+		securityLog.LogContainerExec(ctx, params.ContainerID, caller, params.Cmd,
+			slog.String("error", err.Error()),
+			slog.Int64("duration_ms", duration.Milliseconds()),
+		)
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "exec failed: " + err.Error(),
+		}
+	}
+
+	securityLog.LogContainerExec(ctx, params.ContainerID, caller, params.Cmd,
+		slog.Int("exit_code", result.exitCode),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	)
+
 	return map[string]interface{}{
-		"containers": bridgeContainers,
-		"count":      len(bridgeContainers),
+		"container_id":     params.ContainerID,
+		"exit_code":        result.exitCode,
+		"stdout":           result.stdout.String(),
+		"stderr":           result.stderr.String(),
+		"stdout_truncated": result.stdout.truncated,
+		"stderr_truncated": result.stderr.truncated,
+		"duration_ms":      duration.Milliseconds(),
 	}, nil
 }
+
+// execResult is the outcome of a finished container exec
+type execResult struct {
+	exitCode int
+	stdout   *tailBuffer
+	stderr   *tailBuffer
+}
+
+// execInContainer creates and attaches to an exec instance and waits for it
+// to finish or for timeout. On timeout the attach is closed; Docker offers no
+// way to kill the exec'd process, so it may keep running in the container.
+func (s *Server) execInContainer(ctx context.Context, containerID string, opts container.ExecOptions, timeout time.Duration) (*execResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	created, err := s.dockerClient.ContainerExecCreate(ctx, containerID, opts)
+	if err != nil {
+		if errors.Is(err, docker.ErrInvalidOperation) {
+			return nil, fmt.Errorf("docker client lacks the exec scope")
+		}
+		return nil, fmt.Errorf("create: %w", err)
+	}
+
+	attach, err := s.dockerClient.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("attach: %w", err)
+	}
+	defer attach.Close()
+
+	result := &execResult{
+		stdout: &tailBuffer{max: maxContainerExecOutput},
+		stderr: &tailBuffer{max: maxContainerExecOutput},
+	}
+
+	// Output ends when the process exits and Docker closes the stream
+	copied := make(chan error, 1)
+	go func() {
+		_, err := stdcopy.StdCopy(result.stdout, result.stderr, attach.Reader)
+		copied <- err
+	}()
+
+	select {
+	case err := <-copied:
+		if err != nil {
+			return nil, fmt.Errorf("read output: %w", err)
+		}
+	case <-ctx.Done():
+		attach.Close()
+		<-copied
+		return nil, fmt.Errorf("timed out after %s", timeout)
+	}
+
+	execInspect, err := s.dockerClient.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return nil, fmt.Errorf("inspect: %w", err)
+	}
+	result.exitCode = execInspect.ExitCode
+	return result, nil
+}
+
+// Default number of checks returned by container.health_history
+const defaultHealthHistoryLimit = 20
+
+// handleContainerHealthHistory returns the most recent health checks the
+// health monitor recorded for a container, oldest first, so intermittent
+// failures can be told apart from a single bad snapshot
+func (s *Server) handleContainerHealthHistory(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		ContainerID string `json:"container_id"`
+		UserID      string `json:"user_id"`
+		Limit       int    `json:"limit,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.ContainerID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "container_id is required",
+		}
+	}
+
+	if params.UserID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "user_id is required for authentication",
+		}
+	}
+
+	if params.Limit < 0 {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "limit must be positive",
+		}
+	}
+	if params.Limit == 0 {
+		params.Limit = defaultHealthHistoryLimit
+	}
+
+	if s.healthMonitor == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "health monitor not configured",
+		}
+	}
+
+	status, ok := s.healthMonitor.GetHealth(params.ContainerID)
+	if !ok {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "container is not monitored: " + params.ContainerID,
+		}
+	}
+	checks, _ := s.healthMonitor.History(params.ContainerID, params.Limit)
+
+	failures := 0
+	for _, check := range checks {
+		if !check.Healthy {
+			failures++
+		}
+	}
+
+	return map[string]interface{}{
+		"container_id":   params.ContainerID,
+		"container_name": status.Name,
+		"state":          status.State,
+		"checks":         checks,
+		"count":          len(checks),
+		"failures":       failures,
+	}, nil
+}
+
+// tailBuffer keeps only the last max bytes written to it
+type tailBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n, _ := b.buf.Write(p)
+	// Compact lazily so steady writes don't copy on every call
+	if b.buf.Len() > 2*b.max {
+		b.trim()
+	}
+	return n, nil
+}
+
+// String returns the buffered output. When the buffer was truncated the
+// leading partial line is dropped.
+func (b *tailBuffer) String() string {
+	b.trim()
+	text := b.buf.String()
+	if b.truncated {
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+		}
+	}
+	return text
+}
+
+func (b *tailBuffer) trim() {
+	if b.buf.Len() <= b.max {
+		return
+	}
+	b.truncated = true
+	b.buf.Next(b.buf.Len() - b.max)
+}
+
+// Lines returns the buffered output split into lines. When the buffer was
+// truncated the leading partial line is dropped.
+func (b *tailBuffer) Lines() []string {
+	text := strings.TrimRight(b.String(), "\n")
+	if text == "" {
+		return []string{}
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
@@ -3,7 +3,12 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/armorclaw/bridge/pkg/health"
+	"github.com/docker/docker/api/types"
 )
 
 func TestTerminateContainer_Validation(t *testing.T) {
@@ -19,14 +24,49 @@ func TestTerminateContainer_Validation(t *testing.T) {
 			params:        map[string]interface{}{"user_id": "user-456"},
 			wantError:     true,
 			errorCode:     InvalidParams,
-			errorContains: "container_id is required",
+			errorContains: "container_id or container_name is required",
 		},
 		{
 			name:          "empty container_id",
 			params:        map[string]interface{}{"container_id": "", "user_id": "user-456"},
 			wantError:     true,
 			errorCode:     InvalidParams,
-			errorContains: "container_id is required",
+			errorContains: "container_id or container_name is required",
+		},
+		{
+			name:          "both container_id and container_name",
+			params:        map[string]interface{}{"container_id": "abc", "container_name": "agent-1", "user_id": "user-456"},
+			wantError:     true,
+			errorCode:     InvalidParams,
+			errorContains: "mutually exclusive",
+		},
+		{
+			name:          "timeout_seconds without graceful",
+			params:        map[string]interface{}{"container_id": "abc", "user_id": "user-456", "timeout_seconds": 5},
+			wantError:     true,
+			errorCode:     InvalidParams,
+			errorContains: "timeout_seconds requires graceful",
+		},
+		{
+			name:          "timeout_seconds out of range",
+			params:        map[string]interface{}{"container_id": "abc", "user_id": "user-456", "graceful": true, "timeout_seconds": 301},
+			wantError:     true,
+			errorCode:     InvalidParams,
+			errorContains: "timeout_seconds must be between",
+		},
+		{
+			name:          "graceful without docker client",
+			params:        map[string]interface{}{"container_id": "abc", "user_id": "user-456", "graceful": true, "timeout_seconds": 30},
+			wantError:     true,
+			errorCode:     InternalError,
+			errorContains: "docker client not configured",
+		},
+		{
+			name:          "container_name without docker client",
+			params:        map[string]interface{}{"container_name": "agent-1", "user_id": "user-456"},
+			wantError:     true,
+			errorCode:     InternalError,
+			errorContains: "docker client not configured",
 		},
 		{
 			name:          "missing user_id",
@@ -106,6 +146,20 @@ func TestTerminateContainer_Validation(t *testing.T) {
 	}
 }
 
+func TestMatchContainerName(t *testing.T) {
+	containers := []types.Container{
+		{ID: "id-1", Names: []string{"/agent-10"}},
+		{ID: "id-2", Names: []string{"/agent-1"}},
+	}
+
+	if got := matchContainerName(containers, "agent-1"); got != "id-2" {
+		t.Errorf("matchContainerName(agent-1) = %q, want id-2", got)
+	}
+	if got := matchContainerName(containers, "agent"); got != "" {
+		t.Errorf("matchContainerName(agent) = %q, want no substring match", got)
+	}
+}
+
 func TestContainsArmorClawLabel(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -242,3 +296,233 @@ func TestListContainers_Validation(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerLogs_Validation(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]interface{}
+		errorCode     int
+		errorContains string
+	}{
+		{
+			name:          "missing container_id",
+			params:        map[string]interface{}{"user_id": "user-456"},
+			errorCode:     InvalidParams,
+			errorContains: "container_id is required",
+		},
+		{
+			name:          "missing user_id",
+			params:        map[string]interface{}{"container_id": "abc"},
+			errorCode:     InvalidParams,
+			errorContains: "user_id is required",
+		},
+		{
+			name:          "negative tail",
+			params:        map[string]interface{}{"container_id": "abc", "user_id": "user-456", "tail": -1},
+			errorCode:     InvalidParams,
+			errorContains: "tail must be positive",
+		},
+		{
+			name:          "invalid since",
+			params:        map[string]interface{}{"container_id": "abc", "user_id": "user-456", "since": "yesterday"},
+			errorCode:     InvalidParams,
+			errorContains: "since must be",
+		},
+		{
+			name:          "docker client not configured",
+			params:        map[string]interface{}{"container_id": "abc", "user_id": "user-456", "since": "10m"},
+			errorCode:     InternalError,
+			errorContains: "docker client not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+
+			paramsJSON, err := json.Marshal(tt.params)
+			if err != nil {
+				t.Fatalf("failed to marshal params: %v", err)
+			}
+
+			_, rpcErr := server.handleContainerLogs(context.Background(), &Request{
+				JSONRPC: JSONRPCVersion,
+				ID:      1,
+				Method:  "container.logs",
+				Params:  paramsJSON,
+			})
+			if rpcErr == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if rpcErr.Code != tt.errorCode {
+				t.Errorf("expected error code %d, got %d", tt.errorCode, rpcErr.Code)
+			}
+			if !strings.Contains(rpcErr.Message, tt.errorContains) {
+				t.Errorf("expected error message to contain %q, got %q", tt.errorContains, rpcErr.Message)
+			}
+		})
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	buf := &tailBuffer{max: 1024}
+	buf.Write([]byte("first\r\nsecond\n"))
+	buf.Write([]byte("third\n"))
+
+	lines := buf.Lines()
+	if len(lines) != 3 || lines[0] != "first" || lines[2] != "third" {
+		t.Errorf("Lines() = %q, want [first second third]", lines)
+	}
+	if buf.truncated {
+		t.Error("small output should not be truncated")
+	}
+
+	empty := &tailBuffer{max: 1024}
+	if lines := empty.Lines(); lines == nil || len(lines) != 0 {
+		t.Errorf("empty buffer Lines() = %#v, want empty slice", lines)
+	}
+}
+
+func TestTailBuffer_KeepsMostRecent(t *testing.T) {
+	buf := &tailBuffer{max: 16}
+	for i := 0; i < 20; i++ {
+		buf.Write([]byte("line-" + strconv.Itoa(i) + "\n"))
+	}
+
+	lines := buf.Lines()
+	if !buf.truncated {
+		t.Error("expected truncated to be set")
+	}
+	if len(lines) == 0 || lines[len(lines)-1] != "line-19" {
+		t.Fatalf("Lines() = %q, want the most recent line last", lines)
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "line-") {
+			t.Errorf("partial line %q should have been dropped", line)
+		}
+	}
+}
+
+func TestContainerHealthHistory(t *testing.T) {
+	monitor := health.NewMonitor(nil, health.MonitorConfig{HistorySize: 3})
+	defer monitor.Stop()
+	monitor.Register("abc123", "armorclaw-agent")
+	monitor.UpdateHealth("abc123", "running", true)
+	monitor.UpdateHealth("abc123", "stopped", false)
+	monitor.UpdateHealth("abc123", "running", true)
+	monitor.UpdateHealth("abc123", "stopped", false)
+
+	server := &Server{healthMonitor: monitor}
+	call := func(params map[string]interface{}) (interface{}, *ErrorObj) {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			t.Fatalf("failed to marshal params: %v", err)
+		}
+		return server.handleContainerHealthHistory(context.Background(), &Request{
+			JSONRPC: JSONRPCVersion,
+			ID:      1,
+			Method:  "container.health_history",
+			Params:  paramsJSON,
+		})
+	}
+
+	result, rpcErr := call(map[string]interface{}{"container_id": "abc123", "user_id": "user-456"})
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr.Message)
+	}
+	resp := result.(map[string]interface{})
+	checks := resp["checks"].([]health.HealthCheck)
+	if len(checks) != 3 || resp["failures"] != 2 {
+		t.Fatalf("expected the last 3 checks with 2 failures, got %+v", resp)
+	}
+	if checks[0].State != "stopped" || checks[2].State != "stopped" || !checks[1].Healthy {
+		t.Errorf("expected checks oldest first, got %+v", checks)
+	}
+	if resp["container_name"] != "armorclaw-agent" || resp["state"] != "stopped" {
+		t.Errorf("expected current container status, got %+v", resp)
+	}
+
+	result, rpcErr = call(map[string]interface{}{"container_id": "abc123", "user_id": "user-456", "limit": 1})
+	if rpcErr != nil || result.(map[string]interface{})["count"] != 1 {
+		t.Errorf("expected limit to cap the checks, got %+v / %v", result, rpcErr)
+	}
+
+	if _, rpcErr := call(map[string]interface{}{"container_id": "missing", "user_id": "user-456"}); rpcErr == nil || rpcErr.Code != InvalidParams {
+		t.Errorf("expected InvalidParams for an unmonitored container, got %v", rpcErr)
+	}
+	if _, rpcErr := call(map[string]interface{}{"container_id": "abc123"}); rpcErr == nil || rpcErr.Code != InvalidParams {
+		t.Errorf("expected InvalidParams without user_id, got %v", rpcErr)
+	}
+
+	server.healthMonitor = nil
+	if _, rpcErr := call(map[string]interface{}{"container_id": "abc123", "user_id": "user-456"}); rpcErr == nil || rpcErr.Code != InternalError {
+		t.Errorf("expected InternalError without a health monitor, got %v", rpcErr)
+	}
+}
+
+func TestContainerExec_Validation(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]interface{}
+		errorCode     int
+		errorContains string
+	}{
+		{
+			name:          "missing container_id",
+			params:        map[string]interface{}{"cmd": []string{"ls"}},
+			errorCode:     InvalidParams,
+			errorContains: "container_id is required",
+		},
+		{
+			name:          "missing cmd",
+			params:        map[string]interface{}{"container_id": "abc"},
+			errorCode:     InvalidParams,
+			errorContains: "cmd is required",
+		},
+		{
+			name:          "empty command name",
+			params:        map[string]interface{}{"container_id": "abc", "cmd": []string{""}},
+			errorCode:     InvalidParams,
+			errorContains: "cmd is required",
+		},
+		{
+			name:          "timeout out of range",
+			params:        map[string]interface{}{"container_id": "abc", "cmd": []string{"ls"}, "timeout_seconds": 301},
+			errorCode:     InvalidParams,
+			errorContains: "timeout_seconds must be between",
+		},
+		{
+			name:          "docker client not configured",
+			params:        map[string]interface{}{"container_id": "abc", "cmd": []string{"ls"}},
+			errorCode:     InternalError,
+			errorContains: "docker client not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+
+			paramsJSON, err := json.Marshal(tt.params)
+			if err != nil {
+				t.Fatalf("failed to marshal params: %v", err)
+			}
+
+			_, rpcErr := server.handleContainerExec(context.Background(), &Request{
+				JSONRPC: JSONRPCVersion,
+				ID:      1,
+				Method:  "container.exec",
+				Params:  paramsJSON,
+			})
+			if rpcErr == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if rpcErr.Code != tt.errorCode {
+				t.Errorf("expected error code %d, got %d", tt.errorCode, rpcErr.Code)
+			}
+			if !strings.Contains(rpcErr.Message, tt.errorContains) {
+				t.Errorf("expected error message to contain %q, got %q", tt.errorContains, rpcErr.Message)
+			}
+		})
+	}
+}
@@ -10,7 +10,8 @@ import (
 	"github.com/armorclaw/bridge/pkg/trust"
 )
 
-// handleDeviceList returns all registered devices.
+// handleDeviceList returns a page of registered devices, most recently seen
+// first.
 func (s *Server) handleDeviceList(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	if s.deviceStore == nil {
 		return nil, &ErrorObj{
@@ -19,6 +20,19 @@ func (s *Server) handleDeviceList(ctx context.Context, req *Request) (interface{
 		}
 	}
 
+	var params DeviceListRequest
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+	if errObj := params.validate(); errObj != nil {
+		return nil, errObj
+	}
+
 	devices, err := s.deviceStore.ListDevices()
 	if err != nil {
 		return nil, &ErrorObj{
@@ -27,11 +41,8 @@ func (s *Server) handleDeviceList(ctx context.Context, req *Request) (interface{
 		}
 	}
 
-	if devices == nil {
-		devices = []*trust.DeviceRecord{}
-	}
-
-	return devices, nil
+	page, info := paginate(devices, params.PageParams)
+	return DeviceListResponse{Devices: page, PageInfo: info}, nil
 }
 
 // handleDeviceGet returns a single device by ID.
@@ -139,6 +150,10 @@ func (s *Server) handleDeviceApprove(ctx context.Context, req *Request) (interfa
 
 	s.emitDeviceEvent(EventDeviceApproved, params.DeviceID, params.ApprovedBy)
 
+	if s.pushDispatcher != nil {
+		s.pushDispatcher.NotifyDeviceApproved(params.DeviceID)
+	}
+
 	return SuccessResponse{Success: true}, nil
 }
 
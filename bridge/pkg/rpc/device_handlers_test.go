@@ -76,12 +76,12 @@ func TestDeviceList(t *testing.T) {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
 
-	devices, ok := result.([]*trust.DeviceRecord)
+	list, ok := result.(DeviceListResponse)
 	if !ok {
-		t.Fatalf("expected []*trust.DeviceRecord, got %T", result)
+		t.Fatalf("expected DeviceListResponse, got %T", result)
 	}
-	if len(devices) != 0 {
-		t.Fatalf("expected 0 devices, got %d", len(devices))
+	if len(list.Devices) != 0 || list.Total != 0 {
+		t.Fatalf("expected 0 devices, got %d (total %d)", len(list.Devices), list.Total)
 	}
 
 	seedDevice(t, store, "dev-1", trust.StateUnverified)
@@ -93,9 +93,36 @@ func TestDeviceList(t *testing.T) {
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
-	devices = result.([]*trust.DeviceRecord)
-	if len(devices) != 2 {
-		t.Fatalf("expected 2 devices, got %d", len(devices))
+	list = result.(DeviceListResponse)
+	if len(list.Devices) != 2 || list.Total != 2 {
+		t.Fatalf("expected 2 devices, got %d (total %d)", len(list.Devices), list.Total)
+	}
+}
+
+func TestDeviceListPaging(t *testing.T) {
+	store := newTestDeviceStore(t)
+	s := newServerWithDeviceStore(t, store)
+	for _, id := range []string{"dev-1", "dev-2", "dev-3"} {
+		seedDevice(t, store, id, trust.StateUnverified)
+	}
+
+	result, rpcErr := s.handleDeviceList(context.Background(), &Request{
+		Params: json.RawMessage(`{"limit":2,"offset":1}`),
+	})
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	list := result.(DeviceListResponse)
+	if len(list.Devices) != 2 || list.Total != 3 || list.Limit != 2 || list.Offset != 1 {
+		t.Errorf("page = %d devices, total %d, limit %d, offset %d; want 2, 3, 2, 1",
+			len(list.Devices), list.Total, list.Limit, list.Offset)
+	}
+
+	_, rpcErr = s.handleDeviceList(context.Background(), &Request{
+		Params: json.RawMessage(`{"offset":-1}`),
+	})
+	if rpcErr == nil || rpcErr.Code != InvalidParams {
+		t.Errorf("expected InvalidParams for a negative offset, got %v", rpcErr)
 	}
 }
 
@@ -0,0 +1,267 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+)
+
+const (
+	defaultErrorStatsTop = 10
+	maxErrorStatsTop     = 100
+)
+
+// ErrorStatsRequest holds parameters for get_error_stats
+type ErrorStatsRequest struct {
+	Window string `json:"window,omitempty"` // e.g. "24h"; empty = all time
+	Top    int    `json:"top,omitempty"`    // number of top codes (default 10)
+}
+
+// handleGetErrorStats returns aggregate error counts without trace payloads.
+func (s *Server) handleGetErrorStats(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	if s.errorSystem == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "error system not configured",
+		}
+	}
+
+	var params ErrorStatsRequest
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+
+	var window time.Duration
+	if params.Window != "" {
+		d, err := time.ParseDuration(params.Window)
+		if err != nil || d <= 0 {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "window must be a positive duration (e.g. \"24h\")",
+			}
+		}
+		window = d
+	}
+
+	top := params.Top
+	if top <= 0 {
+		top = defaultErrorStatsTop
+	}
+	if top > maxErrorStatsTop {
+		top = maxErrorStatsTop
+	}
+
+	stats, err := s.errorSystem.ErrorStats(ctx, window, top)
+	if err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to compute error stats: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"window": params.Window,
+		"stats":  stats,
+	}, nil
+}
+
+// ResolveErrorRequest holds parameters for resolve_error
+type ResolveErrorRequest struct {
+	TraceID    string `json:"trace_id"`
+	ResolvedBy string `json:"resolved_by,omitempty"`
+}
+
+// handleResolveError marks a stored error as resolved, which also stops any
+// pending escalation to secondary admins.
+func (s *Server) handleResolveError(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	if s.errorSystem == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "error system not configured",
+		}
+	}
+
+	var params ResolveErrorRequest
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+	if params.TraceID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "trace_id is required",
+		}
+	}
+
+	if err := s.errorSystem.Resolve(ctx, params.TraceID, params.ResolvedBy); err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to resolve error: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"trace_id":  params.TraceID,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// ExportErrorsRequest holds parameters for export_errors
+type ExportErrorsRequest struct {
+	Code     string `json:"code,omitempty"`
+	Category string `json:"category,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Resolved *bool  `json:"resolved,omitempty"`
+	Since    string `json:"since,omitempty"` // RFC3339
+	Until    string `json:"until,omitempty"` // RFC3339
+	File     string `json:"file,omitempty"`  // write to this file in the export directory instead of returning data
+}
+
+// handleExportErrors exports matching errors with full traces as a JSON array.
+// The export is either written to a new file in the export directory or
+// returned base64 encoded.
+func (s *Server) handleExportErrors(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	if s.errorSystem == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "error system not configured",
+		}
+	}
+
+	var params ExportErrorsRequest
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+
+	query := errsys.ErrorQuery{
+		Code:      params.Code,
+		Category:  params.Category,
+		Severity:  errsys.Severity(params.Severity),
+		Resolved:  params.Resolved,
+		OrderBy:   "last_seen",
+		OrderDesc: true,
+	}
+	if params.Since != "" {
+		t, err := time.Parse(time.RFC3339, params.Since)
+		if err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "since must be an RFC3339 timestamp",
+			}
+		}
+		query.Since = t
+	}
+	if params.Until != "" {
+		t, err := time.Parse(time.RFC3339, params.Until)
+		if err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "until must be an RFC3339 timestamp",
+			}
+		}
+		query.Until = t
+	}
+
+	return s.writeExport(params.File, "errors", func(w io.Writer) error {
+		return s.errorSystem.ExportJSON(ctx, w, query)
+	})
+}
+
+// writeExport runs export against a new file named file in the export
+// directory, or returns its output base64 encoded if file is empty. Callers
+// only choose the name, so an export cannot be written anywhere else. what
+// names the exported data in errors.
+func (s *Server) writeExport(file, what string, export func(w io.Writer) error) (map[string]interface{}, *ErrorObj) {
+	if file == "" {
+		var buf bytes.Buffer
+		if err := export(&buf); err != nil {
+			return nil, &ErrorObj{
+				Code:    InternalError,
+				Message: "failed to export " + what + ": " + err.Error(),
+			}
+		}
+		return map[string]interface{}{
+			"encoding": "base64",
+			"bytes":    buf.Len(),
+			"data":     base64.StdEncoding.EncodeToString(buf.Bytes()),
+		}, nil
+	}
+
+	if file != filepath.Base(file) || file == "." || file == ".." {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "file must be a file name, without a directory",
+		}
+	}
+	if s.exportDir == "" {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "export directory not configured",
+		}
+	}
+	if err := os.MkdirAll(s.exportDir, 0700); err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to create export directory: " + err.Error(),
+		}
+	}
+
+	// Never overwrite an existing file; exports contain sensitive state
+	f, err := os.OpenFile(filepath.Join(s.exportDir, file), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "failed to create export file: " + err.Error(),
+		}
+	}
+
+	if err := export(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to export " + what + ": " + err.Error(),
+		}
+	}
+	if err := f.Close(); err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to write export file: " + err.Error(),
+		}
+	}
+
+	info, err := os.Stat(f.Name())
+	if err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to stat export file: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"path":  f.Name(),
+		"bytes": info.Size(),
+	}, nil
+}
@@ -0,0 +1,244 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+)
+
+func newTestErrorSystem(t *testing.T) *errsys.System {
+	t.Helper()
+	system, err := errsys.Initialize(errsys.Config{
+		StorePath:    filepath.Join(t.TempDir(), "errors.db"),
+		StoreEnabled: true,
+	})
+	if err != nil {
+		t.Fatalf("initialize error system: %v", err)
+	}
+	t.Cleanup(func() { system.Stop() })
+	return system
+}
+
+func TestGetErrorStatsRegistered(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	if _, ok := server.handlers["get_error_stats"]; !ok {
+		t.Fatal("get_error_stats not registered")
+	}
+}
+
+func TestGetErrorStatsNotConfigured(t *testing.T) {
+	server := &Server{}
+
+	_, errObj := server.handleGetErrorStats(context.Background(), &Request{})
+	if errObj == nil || errObj.Code != InternalError {
+		t.Fatalf("expected InternalError, got %+v", errObj)
+	}
+}
+
+func TestGetErrorStats(t *testing.T) {
+	system := newTestErrorSystem(t)
+	ctx := context.Background()
+
+	system.Store(ctx, errsys.New("CTX-001", "start failed"))
+	system.Store(ctx, errsys.New("CTX-001", "start failed again"))
+	system.Store(ctx, errsys.New("MAT-003", "sync timeout"))
+
+	server := &Server{errorSystem: system}
+
+	result, errObj := server.handleGetErrorStats(ctx, &Request{
+		Params: json.RawMessage(`{"window": "1h", "top": 1}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+
+	stats := result.(map[string]interface{})["stats"].(errsys.StoreStats)
+	if stats.TotalErrors != 2 {
+		t.Errorf("TotalErrors = %d, want 2", stats.TotalErrors)
+	}
+	if stats.ByCategory["container"] != 1 || stats.ByCategory["matrix"] != 1 {
+		t.Errorf("ByCategory = %v", stats.ByCategory)
+	}
+	if len(stats.TopCodes) != 1 || stats.TopCodes[0].Code != "CTX-001" {
+		t.Errorf("TopCodes = %+v, want CTX-001 only", stats.TopCodes)
+	}
+}
+
+func TestGetErrorStatsInvalidWindow(t *testing.T) {
+	server := &Server{errorSystem: newTestErrorSystem(t)}
+
+	_, errObj := server.handleGetErrorStats(context.Background(), &Request{
+		Params: json.RawMessage(`{"window": "soon"}`),
+	})
+	if errObj == nil || errObj.Code != InvalidParams {
+		t.Fatalf("expected InvalidParams, got %+v", errObj)
+	}
+}
+
+func TestExportErrorsRegistered(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	if _, ok := server.handlers["export_errors"]; !ok {
+		t.Fatal("export_errors not registered")
+	}
+}
+
+func TestExportErrorsBase64(t *testing.T) {
+	system := newTestErrorSystem(t)
+	ctx := context.Background()
+
+	system.Store(ctx, errsys.NewBuilder("CTX-001").WithFunction("StartContainer").Build())
+	system.Store(ctx, errsys.New("MAT-003", "sync timeout"))
+
+	server := &Server{errorSystem: system}
+
+	result, errObj := server.handleExportErrors(ctx, &Request{
+		Params: json.RawMessage(`{"category": "container"}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(result.(map[string]interface{})["data"].(string))
+	if err != nil {
+		t.Fatalf("data is not base64: %v", err)
+	}
+
+	var exported []errsys.StoredError
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("export is not a JSON array: %v", err)
+	}
+	if len(exported) != 1 || exported[0].Code != "CTX-001" {
+		t.Fatalf("exported = %+v, want CTX-001 only", exported)
+	}
+	if exported[0].Trace == nil || exported[0].Trace.Function != "StartContainer" {
+		t.Errorf("trace not exported: %+v", exported[0].Trace)
+	}
+}
+
+func TestExportErrorsToPath(t *testing.T) {
+	system := newTestErrorSystem(t)
+	ctx := context.Background()
+	system.Store(ctx, errsys.New("CTX-001", "start failed"))
+
+	server := &Server{errorSystem: system, exportDir: t.TempDir()}
+	path := filepath.Join(server.exportDir, "export.json")
+
+	result, errObj := server.handleExportErrors(ctx, &Request{
+		Params: json.RawMessage(`{"file": "export.json"}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+	if result.(map[string]interface{})["path"] != path {
+		t.Errorf("path = %v, want %s", result.(map[string]interface{})["path"], path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	var exported []errsys.StoredError
+	if err := json.Unmarshal(data, &exported); err != nil || len(exported) != 1 {
+		t.Fatalf("exported = %+v, err = %v", exported, err)
+	}
+
+	// Existing files are never overwritten
+	_, errObj = server.handleExportErrors(ctx, &Request{
+		Params: json.RawMessage(`{"file": "export.json"}`),
+	})
+	if errObj == nil {
+		t.Error("expected error when export file already exists")
+	}
+}
+
+func TestExportErrorsInvalidParams(t *testing.T) {
+	server := &Server{errorSystem: newTestErrorSystem(t), exportDir: t.TempDir()}
+
+	for _, params := range []string{
+		`{"file": "/etc/cron.d/export"}`,
+		`{"file": "subdir/export.json"}`,
+		`{"file": "../export.json"}`,
+		`{"since": "yesterday"}`,
+		`{"until": "2026-13-45"}`,
+	} {
+		_, errObj := server.handleExportErrors(context.Background(), &Request{
+			Params: json.RawMessage(params),
+		})
+		if errObj == nil || errObj.Code != InvalidParams {
+			t.Errorf("%s: expected InvalidParams, got %+v", params, errObj)
+		}
+	}
+}
+
+func TestResolveError(t *testing.T) {
+	system, err := errsys.Initialize(errsys.Config{
+		StorePath:         filepath.Join(t.TempDir(), "errors.db"),
+		StoreEnabled:      true,
+		Enabled:           true,
+		NotifyEnabled:     true,
+		ConfigAdminMXID:   "@primary:example.com",
+		EscalationMXIDs:   []string{"@secondary:example.com"},
+		EscalationTimeout: "1h",
+	})
+	if err != nil {
+		t.Fatalf("initialize error system: %v", err)
+	}
+	t.Cleanup(func() { system.Stop() })
+	system.SetDryRun(true)
+
+	ctx := context.Background()
+	traced := errsys.NewBuilder("SYS-001").WithSeverity(errsys.SeverityCritical).Build()
+	if err := system.Notify(ctx, traced); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if pending := system.GetNotifier().PendingEscalations(); len(pending) != 1 {
+		t.Fatalf("pending escalations = %v, want one", pending)
+	}
+
+	server := &Server{errorSystem: system}
+	result, errObj := server.handleResolveError(ctx, &Request{
+		Params: json.RawMessage(`{"trace_id": "` + traced.TraceID + `", "resolved_by": "@primary:example.com"}`),
+	})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj.Message)
+	}
+	if result.(map[string]interface{})["trace_id"] != traced.TraceID {
+		t.Errorf("trace_id = %v, want %s", result.(map[string]interface{})["trace_id"], traced.TraceID)
+	}
+
+	if pending := system.GetNotifier().PendingEscalations(); len(pending) != 0 {
+		t.Errorf("pending escalations = %v after resolve, want none", pending)
+	}
+	resolved := true
+	results, _ := system.Query(ctx, errsys.ErrorQuery{Resolved: &resolved})
+	if len(results) != 1 {
+		t.Errorf("resolved errors = %d, want 1", len(results))
+	}
+}
+
+func TestResolveErrorInvalidParams(t *testing.T) {
+	server := &Server{errorSystem: newTestErrorSystem(t)}
+
+	_, errObj := server.handleResolveError(context.Background(), &Request{
+		Params: json.RawMessage(`{"resolved_by": "@admin:example.com"}`),
+	})
+	if errObj == nil || errObj.Code != InvalidParams {
+		t.Errorf("expected InvalidParams, got %+v", errObj)
+	}
+
+	_, errObj = server.handleResolveError(context.Background(), &Request{
+		Params: json.RawMessage(`{"trace_id": "tr_missing"}`),
+	})
+	if errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError for unknown trace, got %+v", errObj)
+	}
+}
@@ -1,9 +1,22 @@
 package rpc
 
+import (
+	"github.com/armorclaw/bridge/pkg/invite"
+	"github.com/armorclaw/bridge/pkg/trust"
+)
+
 // Device governance request types.
 
-// DeviceListRequest is the request for device.list (no parameters).
-type DeviceListRequest struct{}
+// DeviceListRequest is the request for device.list.
+type DeviceListRequest struct {
+	PageParams
+}
+
+// DeviceListResponse is the response for device.list.
+type DeviceListResponse struct {
+	Devices []*trust.DeviceRecord `json:"devices"`
+	PageInfo
+}
 
 // DeviceGetRequest is the request for device.get.
 type DeviceGetRequest struct {
@@ -34,8 +47,16 @@ type InviteCreateRequest struct {
 	CreatedBy      string `json:"created_by"`
 }
 
-// InviteListRequest is the request for invite.list (no parameters).
-type InviteListRequest struct{}
+// InviteListRequest is the request for invite.list.
+type InviteListRequest struct {
+	PageParams
+}
+
+// InviteListResponse is the response for invite.list.
+type InviteListResponse struct {
+	Invites []*invite.InviteRecord `json:"invites"`
+	PageInfo
+}
 
 // InviteRevokeRequest is the request for invite.revoke.
 type InviteRevokeRequest struct {
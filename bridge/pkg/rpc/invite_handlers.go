@@ -10,7 +10,7 @@ import (
 	"github.com/armorclaw/bridge/pkg/invite"
 )
 
-// handleInviteList returns all invites ordered by created_at descending.
+// handleInviteList returns a page of invites ordered by created_at descending.
 func (s *Server) handleInviteList(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	if s.inviteStore == nil {
 		return nil, &ErrorObj{
@@ -19,6 +19,19 @@ func (s *Server) handleInviteList(ctx context.Context, req *Request) (interface{
 		}
 	}
 
+	var params InviteListRequest
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+	if errObj := params.validate(); errObj != nil {
+		return nil, errObj
+	}
+
 	invites, err := s.inviteStore.ListInvites()
 	if err != nil {
 		return nil, &ErrorObj{
@@ -27,11 +40,8 @@ func (s *Server) handleInviteList(ctx context.Context, req *Request) (interface{
 		}
 	}
 
-	if invites == nil {
-		invites = []*invite.InviteRecord{}
-	}
-
-	return invites, nil
+	page, info := paginate(invites, params.PageParams)
+	return InviteListResponse{Invites: page, PageInfo: info}, nil
 }
 
 // handleInviteCreate creates a new invite with a crypto/rand code.
@@ -77,12 +77,12 @@ func TestInviteList(t *testing.T) {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
 
-	invites, ok := result.([]*invite.InviteRecord)
+	list, ok := result.(InviteListResponse)
 	if !ok {
-		t.Fatalf("expected []*invite.InviteRecord, got %T", result)
+		t.Fatalf("expected InviteListResponse, got %T", result)
 	}
-	if len(invites) != 0 {
-		t.Fatalf("expected 0 invites, got %d", len(invites))
+	if len(list.Invites) != 0 || list.Total != 0 {
+		t.Fatalf("expected 0 invites, got %d (total %d)", len(list.Invites), list.Total)
 	}
 
 	seedInvite(t, store, invite.RoleUser, "")
@@ -94,9 +94,9 @@ func TestInviteList(t *testing.T) {
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
-	invites = result.([]*invite.InviteRecord)
-	if len(invites) != 2 {
-		t.Fatalf("expected 2 invites, got %d", len(invites))
+	list = result.(InviteListResponse)
+	if len(list.Invites) != 2 || list.Total != 2 {
+		t.Fatalf("expected 2 invites, got %d (total %d)", len(list.Invites), list.Total)
 	}
 }
 
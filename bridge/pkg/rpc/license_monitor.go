@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+	"github.com/armorclaw/bridge/pkg/license"
+)
+
+const (
+	// licenseExpiryWarnWindow is how far ahead of expiry the admin is warned
+	licenseExpiryWarnWindow = 7 * 24 * time.Hour
+	// defaultLicenseCheckInterval is how often the cached license is inspected
+	defaultLicenseCheckInterval = time.Hour
+)
+
+// LicenseCache exposes the cached license for expiry monitoring
+type LicenseCache interface {
+	GetCached(feature string) *license.CachedLicense
+}
+
+// licenseExpiryWarning builds a warning for a cached license that expires
+// within the warning window, or returns nil if no warning is due
+func licenseExpiryWarning(cached *license.CachedLicense, now time.Time) *errsys.TracedError {
+	if cached == nil || cached.ExpiresAt.IsZero() {
+		return nil
+	}
+
+	remaining := cached.ExpiresAt.Sub(now)
+	if remaining > licenseExpiryWarnWindow {
+		return nil
+	}
+
+	days := licenseDaysRemaining(cached.ExpiresAt, now)
+	msg := fmt.Sprintf("%s license expires in %d day(s)", cached.Tier, days)
+	if remaining <= 0 {
+		msg = fmt.Sprintf("%s license expired on %s", cached.Tier, cached.ExpiresAt.UTC().Format("2006-01-02"))
+	}
+
+	return errsys.NewBuilder("SYS-030").
+		WithMessage(msg).
+		WithFunction("checkLicenseExpiry").
+		WithStateValue("tier", string(cached.Tier)).
+		WithStateValue("days_remaining", days).
+		WithStateValue("expires_at", cached.ExpiresAt.UTC().Format(time.RFC3339)).
+		WithStateValue("instance_id", cached.InstanceID).
+		Build()
+}
+
+// licenseDaysRemaining rounds the time until expiry up to whole days
+func licenseDaysRemaining(expiresAt, now time.Time) int {
+	remaining := expiresAt.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	days := int(remaining / (24 * time.Hour))
+	if remaining%(24*time.Hour) != 0 {
+		days++
+	}
+	return days
+}
+
+// checkLicenseExpiry notifies the admin when the cached license is close to
+// expiry. Each license is warned at most once per day remaining.
+func (s *Server) checkLicenseExpiry(ctx context.Context, now time.Time) {
+	if s.licenseClient == nil {
+		return
+	}
+
+	cached := s.licenseClient.GetCached("")
+	warning := licenseExpiryWarning(cached, now)
+	if warning == nil {
+		return
+	}
+
+	key := fmt.Sprintf("%s/%d", cached.ExpiresAt.UTC().Format(time.RFC3339), licenseDaysRemaining(cached.ExpiresAt, now))
+	s.licenseWarnMu.Lock()
+	if s.licenseWarned == key {
+		s.licenseWarnMu.Unlock()
+		return
+	}
+	s.licenseWarned = key
+	s.licenseWarnMu.Unlock()
+
+	var err error
+	if s.errorSystem != nil {
+		err = s.errorSystem.Notify(ctx, warning)
+	} else {
+		err = errsys.GlobalNotify(ctx, warning)
+	}
+	if err != nil {
+		slog.Warn("license_expiry_notify_failed", "tier", cached.Tier, "error", err)
+	}
+}
+
+// runLicenseExpiryChecker inspects the cached license until stop is closed
+func (s *Server) runLicenseExpiryChecker(stop <-chan struct{}) {
+	interval := s.licenseCheckInterval
+	if interval <= 0 {
+		interval = defaultLicenseCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.checkLicenseExpiry(context.Background(), time.Now())
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.checkLicenseExpiry(context.Background(), time.Now())
+		}
+	}
+}
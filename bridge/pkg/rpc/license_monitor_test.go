@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+	"github.com/armorclaw/bridge/pkg/license"
+)
+
+type stubLicenseCache struct {
+	cached *license.CachedLicense
+}
+
+func (s *stubLicenseCache) GetCached(feature string) *license.CachedLicense {
+	return s.cached
+}
+
+func TestLicenseExpiryWarning(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if licenseExpiryWarning(nil, now) != nil {
+		t.Error("nil license should not warn")
+	}
+	if licenseExpiryWarning(&license.CachedLicense{Tier: license.TierPro, ExpiresAt: now.Add(30 * 24 * time.Hour)}, now) != nil {
+		t.Error("license expiring in 30 days should not warn")
+	}
+
+	warning := licenseExpiryWarning(&license.CachedLicense{
+		Tier:      license.TierPro,
+		ExpiresAt: now.Add(3*24*time.Hour - time.Hour),
+	}, now)
+	if warning == nil {
+		t.Fatal("license expiring in 3 days should warn")
+	}
+	if warning.Code != "SYS-030" || warning.Severity != "warning" {
+		t.Errorf("warning = %s/%s, want SYS-030/warning", warning.Code, warning.Severity)
+	}
+	if warning.State["days_remaining"] != 3 || warning.State["tier"] != "pro" {
+		t.Errorf("state = %v, want days_remaining=3 tier=pro", warning.State)
+	}
+	if !strings.Contains(warning.Message, "3 day") {
+		t.Errorf("message = %q, want days remaining", warning.Message)
+	}
+
+	expired := licenseExpiryWarning(&license.CachedLicense{Tier: license.TierPro, ExpiresAt: now.Add(-time.Hour)}, now)
+	if expired == nil || expired.State["days_remaining"] != 0 {
+		t.Errorf("expired license should warn with 0 days remaining, got %+v", expired)
+	}
+}
+
+func TestCheckLicenseExpiry(t *testing.T) {
+	system, err := errsys.Initialize(errsys.Config{
+		StoreEnabled:    false,
+		ConfigAdminMXID: "@admin:example.com",
+		Enabled:         true,
+		NotifyEnabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("initialize error system: %v", err)
+	}
+	defer system.Stop()
+	system.SetDryRun(true)
+
+	now := time.Now()
+	cache := &stubLicenseCache{cached: &license.CachedLicense{
+		Tier:      license.TierEnterprise,
+		ExpiresAt: now.Add(2 * 24 * time.Hour),
+	}}
+	server := &Server{errorSystem: system, licenseClient: cache}
+
+	server.checkLicenseExpiry(context.Background(), now)
+	server.checkLicenseExpiry(context.Background(), now.Add(time.Hour))
+
+	messages := system.DryRunMessages()
+	if len(messages) != 1 {
+		t.Fatalf("sent %d notifications, want 1 per day remaining", len(messages))
+	}
+	if !strings.Contains(messages[0].Message, "SYS-030") || !strings.Contains(messages[0].Message, "ent") {
+		t.Errorf("notification missing code or tier:\n%s", messages[0].Message)
+	}
+
+	// A renewed license stops warnings
+	cache.cached = &license.CachedLicense{Tier: license.TierEnterprise, ExpiresAt: now.Add(365 * 24 * time.Hour)}
+	server.checkLicenseExpiry(context.Background(), now.Add(25*time.Hour))
+	if len(system.DryRunMessages()) != 1 {
+		t.Error("renewed license should not warn")
+	}
+}
@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/armorclaw/bridge/pkg/logger"
+)
+
+// handleLogSetLevel changes the global logger's level and/or format while
+// the bridge runs, e.g. to switch to debug JSON output while reproducing an
+// error. Changes apply from the next log line and last until restart or a
+// config reload that changes logging.level.
+func (s *Server) handleLogSetLevel(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		Level  string `json:"level,omitempty"`
+		Format string `json:"format,omitempty"`
+	}
+
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: "invalid parameters: " + err.Error(),
+			}
+		}
+	}
+
+	if params.Level == "" && params.Format == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "level or format is required",
+		}
+	}
+
+	previousLevel, previousFormat := logger.Settings()
+
+	if params.Level != "" {
+		if err := logger.SetLevel(params.Level); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: err.Error() + " (must be debug, info, warn or error)",
+			}
+		}
+	}
+	if params.Format != "" {
+		if err := logger.SetFormat(params.Format); err != nil {
+			// Don't leave a half-applied change behind
+			logger.SetLevel(previousLevel)
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	level, format := logger.Settings()
+	logger.Global().WithComponent("rpc").Info("log settings changed",
+		"level", level,
+		"format", format,
+		"previous_level", previousLevel,
+		"previous_format", previousFormat,
+	)
+
+	return map[string]interface{}{
+		"level":           level,
+		"format":          format,
+		"previous_level":  previousLevel,
+		"previous_format": previousFormat,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/armorclaw/bridge/pkg/logger"
+)
+
+func TestLogSetLevel(t *testing.T) {
+	logger.Initialize("info", "text", "stdout")
+	t.Cleanup(func() {
+		logger.SetLevel("info")
+		logger.SetFormat("text")
+	})
+
+	server := &Server{}
+	server.registerHandlers()
+	if _, ok := server.handlers["log.set_level"]; !ok {
+		t.Fatal("log.set_level not registered")
+	}
+
+	result, errObj := server.handleLogSetLevel(context.Background(), &Request{
+		Params: json.RawMessage(`{"level": "debug", "format": "json"}`),
+	})
+	if errObj != nil {
+		t.Fatalf("handleLogSetLevel() error = %+v", errObj)
+	}
+	got := result.(map[string]interface{})
+	if got["level"] != "debug" || got["format"] != "json" {
+		t.Errorf("result = %v, want level debug, format json", got)
+	}
+	if level, format := logger.Settings(); level != "debug" || format != "json" {
+		t.Errorf("logger settings = %s, %s; want debug, json", level, format)
+	}
+}
+
+func TestLogSetLevelErrors(t *testing.T) {
+	logger.Initialize("info", "text", "stdout")
+	t.Cleanup(func() {
+		logger.SetLevel("info")
+		logger.SetFormat("text")
+	})
+	logger.SetLevel("warn")
+
+	tests := []struct {
+		name   string
+		params string
+	}{
+		{"no params", ``},
+		{"empty", `{}`},
+		{"bad level", `{"level": "verbose"}`},
+		{"bad format", `{"level": "debug", "format": "xml"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errObj := (&Server{}).handleLogSetLevel(context.Background(), &Request{
+				Params: json.RawMessage(tt.params),
+			})
+			if errObj == nil || errObj.Code != InvalidParams {
+				t.Errorf("expected error code %d, got %+v", InvalidParams, errObj)
+			}
+			if level, _ := logger.Settings(); level != "warn" {
+				t.Errorf("level changed to %s by a rejected request", level)
+			}
+		})
+	}
+}
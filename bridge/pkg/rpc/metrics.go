@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -107,3 +108,68 @@ func (m *Metrics) Export() string {
 
 	return output
 }
+
+// MethodStats tracks calls to a single RPC method
+type MethodStats struct {
+	Calls    uint64    `json:"calls"`
+	Errors   uint64    `json:"errors"`
+	LastCall time.Time `json:"last_call"`
+}
+
+// methodMetrics is an in-process per-method call counter. Unlike Metrics it
+// is always on and only covers registered methods, so the map stays bounded.
+type methodMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*MethodStats
+}
+
+// record counts one call to method, and an error if failed is set
+func (m *methodMetrics) record(method string, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.methods == nil {
+		m.methods = make(map[string]*MethodStats)
+	}
+
+	stats, ok := m.methods[method]
+	if !ok {
+		stats = &MethodStats{}
+		m.methods[method] = stats
+	}
+
+	stats.Calls++
+	if failed {
+		stats.Errors++
+	}
+	stats.LastCall = time.Now()
+}
+
+// snapshot returns a copy of the per-method stats
+func (m *methodMetrics) snapshot() map[string]MethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[string]MethodStats, len(m.methods))
+	for method, stats := range m.methods {
+		result[method] = *stats
+	}
+	return result
+}
+
+// handleMethodMetrics returns call, error and last-call stats per RPC method
+func (s *Server) handleMethodMetrics(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	methods := s.methodStats.snapshot()
+
+	var totalCalls, totalErrors uint64
+	for _, stats := range methods {
+		totalCalls += stats.Calls
+		totalErrors += stats.Errors
+	}
+
+	return map[string]interface{}{
+		"methods":      methods,
+		"total_calls":  totalCalls,
+		"total_errors": totalErrors,
+	}, nil
+}
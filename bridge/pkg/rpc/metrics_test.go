@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"strings"
 	"testing"
 )
@@ -138,3 +139,47 @@ func TestMetricsSetGauge(t *testing.T) {
 		}
 	})
 }
+
+func TestMethodMetrics(t *testing.T) {
+	server := &Server{
+		handlers: map[string]HandlerFunc{
+			"ok": func(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+				return "fine", nil
+			},
+			"fail": func(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+				return nil, &ErrorObj{Code: InternalError, Message: "boom"}
+			},
+			"panic": func(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+				panic("boom")
+			},
+		},
+	}
+
+	ctx := context.Background()
+	for _, method := range []string{"ok", "ok", "fail", "panic", "unknown"} {
+		server.Handle(ctx, &Request{JSONRPC: JSONRPCVersion, ID: 1, Method: method})
+	}
+
+	result, errObj := server.handleMethodMetrics(ctx, &Request{})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj)
+	}
+	resultMap := result.(map[string]interface{})
+	methods := resultMap["methods"].(map[string]MethodStats)
+
+	if got := methods["ok"]; got.Calls != 2 || got.Errors != 0 || got.LastCall.IsZero() {
+		t.Errorf("ok stats = %+v, want 2 calls, 0 errors", got)
+	}
+	if got := methods["fail"]; got.Calls != 1 || got.Errors != 1 {
+		t.Errorf("fail stats = %+v, want 1 call, 1 error", got)
+	}
+	if got := methods["panic"]; got.Calls != 1 || got.Errors != 1 {
+		t.Errorf("panic stats = %+v, want 1 call, 1 error", got)
+	}
+	if _, ok := methods["unknown"]; ok {
+		t.Error("unregistered methods should not be tracked")
+	}
+	if resultMap["total_calls"] != uint64(4) || resultMap["total_errors"] != uint64(2) {
+		t.Errorf("totals = %v/%v, want 4/2", resultMap["total_calls"], resultMap["total_errors"])
+	}
+}
@@ -0,0 +1,59 @@
+package rpc
+
+import "fmt"
+
+// Page sizes for list methods
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// PageParams are the optional paging parameters accepted by list methods.
+// A zero Limit means defaultPageLimit.
+type PageParams struct {
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+// PageInfo describes the page returned by a list method. Total counts every
+// item, not just the ones on the page.
+type PageInfo struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// validate checks the paging parameters and fills in the default limit
+func (p *PageParams) validate() *ErrorObj {
+	if p.Limit < 0 || p.Limit > maxPageLimit {
+		return &ErrorObj{
+			Code:    InvalidParams,
+			Message: fmt.Sprintf("limit must be between 0 and %d", maxPageLimit),
+		}
+	}
+	if p.Offset < 0 {
+		return &ErrorObj{
+			Code:    InvalidParams,
+			Message: "offset cannot be negative",
+		}
+	}
+	if p.Limit == 0 {
+		p.Limit = defaultPageLimit
+	}
+	return nil
+}
+
+// paginate returns the items selected by p, which must have been validated,
+// and the matching PageInfo. An offset past the end yields an empty page.
+func paginate[T any](items []T, p PageParams) ([]T, PageInfo) {
+	info := PageInfo{Total: len(items), Limit: p.Limit, Offset: p.Offset}
+
+	if p.Offset >= len(items) {
+		return []T{}, info
+	}
+	end := len(items)
+	if p.Limit < end-p.Offset {
+		end = p.Offset + p.Limit
+	}
+	return items[p.Offset:end], info
+}
@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name   string
+		params PageParams
+		want   []int
+	}{
+		{"default limit", PageParams{}, []int{1, 2, 3, 4, 5}},
+		{"first page", PageParams{Limit: 2}, []int{1, 2}},
+		{"middle page", PageParams{Limit: 2, Offset: 2}, []int{3, 4}},
+		{"short last page", PageParams{Limit: 2, Offset: 4}, []int{5}},
+		{"offset past end", PageParams{Limit: 2, Offset: 10}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if errObj := params.validate(); errObj != nil {
+				t.Fatalf("validate() = %v", errObj)
+			}
+			page, info := paginate(items, params)
+			if !reflect.DeepEqual(page, tt.want) {
+				t.Errorf("page = %v, want %v", page, tt.want)
+			}
+			if info.Total != len(items) || info.Limit != params.Limit || info.Offset != params.Offset {
+				t.Errorf("info = %+v", info)
+			}
+		})
+	}
+
+	if page, _ := paginate([]int(nil), PageParams{Limit: defaultPageLimit}); page == nil {
+		t.Error("paginate() of a nil slice returned nil, want an empty page")
+	}
+}
+
+func TestPageParamsValidate(t *testing.T) {
+	for _, p := range []PageParams{{Limit: -1}, {Limit: maxPageLimit + 1}, {Offset: -1}} {
+		if errObj := p.validate(); errObj == nil || errObj.Code != InvalidParams {
+			t.Errorf("validate(%+v) = %v, want InvalidParams", p, errObj)
+		}
+	}
+
+	p := PageParams{}
+	if errObj := p.validate(); errObj != nil || p.Limit != defaultPageLimit {
+		t.Errorf("validate() of empty params = %v, limit %d; want nil, %d", errObj, p.Limit, defaultPageLimit)
+	}
+}
@@ -83,21 +83,7 @@ func (s *Server) handlePIIRequest(ctx context.Context, req *Request) (interface{
 	piiMgr := s.getOrCreatePIIRequestManager()
 
 	// Set up callback to emit Matrix event
-	piiMgr.SetCallbacks(
-		func(ctx context.Context, r *keystore.PIIRequest) error {
-			// On request created - emit Matrix event
-			return s.emitPIIRequestEvent(ctx, r)
-		},
-		func(ctx context.Context, r *keystore.PIIRequest) error {
-			// On approved - emit approval event
-			return s.emitPIIApprovalEvent(ctx, r)
-		},
-		func(ctx context.Context, r *keystore.PIIRequest) error {
-			// On denied - emit denial event
-			return s.emitPIIDenialEvent(ctx, r)
-		},
-		nil, // on expired
-	)
+	s.setPIICallbacks(piiMgr)
 
 	piiReq, err := piiMgr.CreateRequest(
 		ctx,
@@ -329,6 +315,26 @@ func (s *Server) handlePIIStats(ctx context.Context, req *Request) (interface{},
 	return stats, nil
 }
 
+// setPIICallbacks emits Matrix events as piiMgr's requests are created,
+// approved and denied
+func (s *Server) setPIICallbacks(piiMgr *keystore.PIIRequestManager) {
+	piiMgr.SetCallbacks(
+		func(ctx context.Context, r *keystore.PIIRequest) error {
+			// On request created - emit Matrix event
+			return s.emitPIIRequestEvent(ctx, r)
+		},
+		func(ctx context.Context, r *keystore.PIIRequest) error {
+			// On approved - emit approval event
+			return s.emitPIIApprovalEvent(ctx, r)
+		},
+		func(ctx context.Context, r *keystore.PIIRequest) error {
+			// On denied - emit denial event
+			return s.emitPIIDenialEvent(ctx, r)
+		},
+		nil, // on expired
+	)
+}
+
 // getOrCreatePIIRequestManager returns the singleton PII request manager
 // initialized during Server creation. Falls back to creating a new one only
 // if the Server was constructed outside of New() (should not happen).
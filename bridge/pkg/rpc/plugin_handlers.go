@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// handlePluginReload swaps a loaded plugin for a fresh build of its library
+// without restarting the bridge. See plugin.PluginManager.ReloadPlugin for
+// how the new library is loaded while the old one stays mapped.
+func (s *Server) handlePluginReload(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		Name        string `json:"name"`
+		LibraryPath string `json:"library_path,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.Name == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "name is required",
+		}
+	}
+
+	if s.pluginManager == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "plugin manager not configured",
+		}
+	}
+
+	info, err := s.pluginManager.ReloadPlugin(params.Name, params.LibraryPath)
+	if err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to reload plugin: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"status":      "reloaded",
+		"name":        params.Name,
+		"state":       info.State,
+		"reloads":     info.Reloads,
+		"loaded_path": info.LoadedPath,
+		"load_time":   info.LoadTime,
+	}, nil
+}
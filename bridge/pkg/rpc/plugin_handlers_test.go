@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/armorclaw/bridge/pkg/plugin"
+)
+
+func TestPluginReloadRegistered(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	if _, ok := server.handlers["plugin.reload"]; !ok {
+		t.Fatal("plugin.reload not registered")
+	}
+}
+
+func TestPluginReloadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  *Server
+		params  string
+		wantErr int
+	}{
+		{"missing name", &Server{pluginManager: plugin.NewPluginManager(plugin.ManagerConfig{})}, `{}`, InvalidParams},
+		{"not configured", &Server{}, `{"name": "telegram-adapter"}`, InternalError},
+		{"unknown plugin", &Server{pluginManager: plugin.NewPluginManager(plugin.ManagerConfig{})}, `{"name": "telegram-adapter"}`, InternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errObj := tt.server.handlePluginReload(context.Background(), &Request{
+				Params: json.RawMessage(tt.params),
+			})
+			if errObj == nil || errObj.Code != tt.wantErr {
+				t.Errorf("expected error code %d, got %+v", tt.wantErr, errObj)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/armorclaw/bridge/pkg/keystore"
+)
+
+// handleProfileGet returns a profile with its decrypted values. Profiles
+// whose schema flags sensitive fields need an approved PII access request:
+// without one a request is opened and a PIIAccessRequired error carrying its
+// ID is returned, so the caller can wait for approval and retry with it.
+// Only the sensitive fields the approval covers are returned.
+func (s *Server) handleProfileGet(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		ID        string `json:"id"`
+		RequestID string `json:"request_id,omitempty"`
+		AgentID   string `json:"agent_id,omitempty"`
+		SkillID   string `json:"skill_id,omitempty"`
+		Context   string `json:"context,omitempty"`
+		RoomID    string `json:"room_id,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.ID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "id is required",
+		}
+	}
+
+	ks, errObj := s.openKeystore()
+	if errObj != nil {
+		return nil, errObj
+	}
+	defer ks.Close()
+
+	sensitive, err := ks.ProfileSensitiveFields(params.ID)
+	if err != nil {
+		return nil, profileError(err)
+	}
+
+	var granted []string
+	if len(sensitive) > 0 {
+		piiMgr := s.getOrCreatePIIRequestManager()
+
+		if params.RequestID == "" {
+			fields := make([]keystore.PIIFieldRequest, 0, len(sensitive))
+			for _, key := range sensitive {
+				fields = append(fields, keystore.PIIFieldRequest{Key: key, DisplayName: key, Sensitive: true})
+			}
+			if params.AgentID == "" {
+				params.AgentID = "rpc"
+			}
+			if params.SkillID == "" {
+				params.SkillID = "profile.get"
+			}
+			s.setPIICallbacks(piiMgr)
+			piiReq, err := piiMgr.CreateRequest(ctx, params.AgentID, params.SkillID, params.SkillID,
+				params.ID, fields, params.Context, params.RoomID, 0)
+			if err != nil {
+				return nil, &ErrorObj{
+					Code:    InternalError,
+					Message: "failed to create PII request: " + err.Error(),
+				}
+			}
+			return nil, piiAccessRequired(piiReq.ID, string(piiReq.Status), sensitive)
+		}
+
+		granted, err = piiMgr.AuthorizeProfileAccess(params.RequestID, params.ID)
+		if err != nil {
+			return nil, piiAccessError(params.RequestID, err, sensitive)
+		}
+	}
+
+	var profile *keystore.UserProfileData
+	if len(sensitive) > 0 {
+		profile, err = ks.RetrieveProfileFields(params.ID, granted)
+	} else {
+		profile, err = ks.RetrieveProfile(params.ID)
+	}
+	if err != nil {
+		return nil, profileError(err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(profile.Data, &data); err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to parse profile data: " + err.Error(),
+		}
+	}
+
+	result := map[string]interface{}{
+		"id":           profile.ID,
+		"profile_name": profile.ProfileName,
+		"profile_type": profile.ProfileType,
+		"data":         data,
+		"is_default":   profile.IsDefault,
+		"created_at":   profile.CreatedAt,
+		"updated_at":   profile.UpdatedAt,
+	}
+	var schema interface{}
+	if json.Unmarshal([]byte(profile.FieldSchema), &schema) == nil {
+		result["field_schema"] = schema
+	}
+	if withheld := withheldFields(sensitive, granted); len(withheld) > 0 {
+		result["withheld_fields"] = withheld
+	}
+	return result, nil
+}
+
+// withheldFields returns the sensitive fields missing from granted
+func withheldFields(sensitive, granted []string) []string {
+	allowed := make(map[string]bool, len(granted))
+	for _, f := range granted {
+		allowed[f] = true
+	}
+	var withheld []string
+	for _, f := range sensitive {
+		if !allowed[f] {
+			withheld = append(withheld, f)
+		}
+	}
+	return withheld
+}
+
+// piiAccessRequired reports that a sensitive profile read needs approval
+// of the given request
+func piiAccessRequired(requestID, status string, fields []string) *ErrorObj {
+	return &ErrorObj{
+		Code:    PIIAccessRequired,
+		Message: "PII access approval required",
+		Data: map[string]interface{}{
+			"request_id": requestID,
+			"status":     status,
+			"fields":     fields,
+		},
+	}
+}
+
+// piiAccessError maps an access check failure for requestID to an RPC
+// error. Requests that can no longer be approved ask for a new one.
+func piiAccessError(requestID string, err error, fields []string) *ErrorObj {
+	switch {
+	case errors.Is(err, keystore.ErrRequestPending):
+		return piiAccessRequired(requestID, string(keystore.StatusPending), fields)
+	case errors.Is(err, keystore.ErrRequestNotFound),
+		errors.Is(err, keystore.ErrProfileMismatch):
+		return &ErrorObj{Code: NotFoundError, Message: err.Error()}
+	default:
+		return &ErrorObj{
+			Code:    PIIAccessRequired,
+			Message: err.Error() + "; request access again",
+			Data: map[string]interface{}{
+				"request_id": requestID,
+				"fields":     fields,
+			},
+		}
+	}
+}
+
+// profileError maps keystore profile errors to RPC errors
+func profileError(err error) *ErrorObj {
+	if errors.Is(err, keystore.ErrProfileNotFound) {
+		return &ErrorObj{Code: NotFoundError, Message: "profile not found"}
+	}
+	return &ErrorObj{Code: InternalError, Message: "failed to read profile: " + err.Error()}
+}
@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/keystore"
+)
+
+const testSensitiveSchema = `{"profile_type":"personal","fields":[
+	{"key":"email","label":"Email","type":"email","sensitive":false},
+	{"key":"ssn","label":"SSN","type":"text","sensitive":true},
+	{"key":"date_of_birth","label":"Date of Birth","type":"date","sensitive":true}
+]}`
+
+// newProfileTestServer returns a server whose keystore holds a sensitive
+// profile "personal" and a plain profile "work"
+func newProfileTestServer(t *testing.T) *Server {
+	t.Helper()
+	ks, err := keystore.New(keystore.Config{
+		DBPath:    filepath.Join(t.TempDir(), "keystore.db"),
+		MasterKey: make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatalf("failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("failed to open keystore: %v", err)
+	}
+	defer ks.Close()
+
+	data := []byte(`{"email":"john@example.com","ssn":"123-45-6789","date_of_birth":"1990-01-01"}`)
+	if err := ks.StoreProfile("personal", "Personal", "personal", data, testSensitiveSchema, true); err != nil {
+		t.Fatalf("failed to store profile: %v", err)
+	}
+	if err := ks.StoreProfile("work", "Work", "business", []byte(`{"company":"Acme"}`), `{"company":"text"}`, false); err != nil {
+		t.Fatalf("failed to store profile: %v", err)
+	}
+
+	return &Server{
+		keystore: ks,
+		piiRequestManager: keystore.NewPIIRequestManager(keystore.PIIRequestManagerConfig{
+			DefaultTTL:       5 * time.Minute,
+			ApprovalValidity: time.Minute,
+		}),
+	}
+}
+
+func profileGetRequest(params map[string]string) *Request {
+	raw, _ := json.Marshal(params)
+	return &Request{Params: raw}
+}
+
+func TestProfileGetRegistered(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	if _, ok := server.handlers["profile.get"]; !ok {
+		t.Error("profile.get not registered")
+	}
+}
+
+func TestProfileGetRequiresApproval(t *testing.T) {
+	server := newProfileTestServer(t)
+	ctx := context.Background()
+
+	_, errObj := server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "personal"}))
+	if errObj == nil || errObj.Code != PIIAccessRequired {
+		t.Fatalf("expected PIIAccessRequired, got %+v", errObj)
+	}
+	data := errObj.Data.(map[string]interface{})
+	requestID, _ := data["request_id"].(string)
+	if requestID == "" || data["status"] != string(keystore.StatusPending) {
+		t.Fatalf("unexpected error data: %v", data)
+	}
+
+	// Retrying before approval reports the same pending request
+	_, errObj = server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "personal", "request_id": requestID}))
+	if errObj == nil || errObj.Code != PIIAccessRequired || errObj.Data.(map[string]interface{})["request_id"] != requestID {
+		t.Fatalf("expected pending PIIAccessRequired, got %+v", errObj)
+	}
+
+	if _, err := server.piiRequestManager.ApproveRequest(ctx, requestID, "@admin:example.com", []string{"ssn"}); err != nil {
+		t.Fatalf("ApproveRequest() error = %v", err)
+	}
+
+	result, errObj := server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "personal", "request_id": requestID}))
+	if errObj != nil {
+		t.Fatalf("profile.get after approval failed: %+v", errObj)
+	}
+	profile := result.(map[string]interface{})
+	got := profile["data"].(map[string]interface{})
+	if got["ssn"] != "123-45-6789" || got["email"] != "john@example.com" {
+		t.Errorf("unexpected data: %v", got)
+	}
+	if _, ok := got["date_of_birth"]; ok {
+		t.Errorf("unapproved field returned: %v", got)
+	}
+	if withheld := profile["withheld_fields"].([]string); len(withheld) != 1 || withheld[0] != "date_of_birth" {
+		t.Errorf("withheld_fields = %v, want [date_of_birth]", withheld)
+	}
+
+	// Profiles without sensitive fields ignore request_id
+	_, errObj = server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "work", "request_id": requestID}))
+	if errObj != nil {
+		t.Errorf("non-sensitive profile should ignore request_id, got %+v", errObj)
+	}
+}
+
+func TestProfileGetDeniedRequest(t *testing.T) {
+	server := newProfileTestServer(t)
+	ctx := context.Background()
+
+	_, errObj := server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "personal"}))
+	requestID := errObj.Data.(map[string]interface{})["request_id"].(string)
+
+	if _, err := server.piiRequestManager.DenyRequest(ctx, requestID, "@admin:example.com", "not needed"); err != nil {
+		t.Fatalf("DenyRequest() error = %v", err)
+	}
+
+	_, errObj = server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "personal", "request_id": requestID}))
+	if errObj == nil || errObj.Code != PIIAccessRequired {
+		t.Errorf("expected PIIAccessRequired for denied request, got %+v", errObj)
+	}
+
+	_, errObj = server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "personal", "request_id": "missing"}))
+	if errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("expected NotFoundError for unknown request, got %+v", errObj)
+	}
+}
+
+func TestProfileGetWithoutSensitiveFields(t *testing.T) {
+	server := newProfileTestServer(t)
+	ctx := context.Background()
+
+	result, errObj := server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "work"}))
+	if errObj != nil {
+		t.Fatalf("profile.get failed: %+v", errObj)
+	}
+	profile := result.(map[string]interface{})
+	if profile["data"].(map[string]interface{})["company"] != "Acme" {
+		t.Errorf("unexpected profile: %v", profile)
+	}
+	if _, ok := profile["withheld_fields"]; ok {
+		t.Errorf("unexpected withheld_fields: %v", profile)
+	}
+
+	_, errObj = server.handleProfileGet(ctx, profileGetRequest(map[string]string{"id": "missing"}))
+	if errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("expected NotFoundError for missing profile, got %+v", errObj)
+	}
+}
@@ -0,0 +1,122 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+)
+
+// metricsDockerTimeout bounds the container count lookup during a scrape
+const metricsDockerTimeout = 5 * time.Second
+
+// MetricsHandler serves GET /metrics in Prometheus text format: the RPC
+// counters, per-method call and error counts, and the current state of the
+// containers, WebRTC sessions, budget and event bus the server was
+// configured with. Components that are not configured are left out.
+func (s *Server) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write([]byte(s.exportPrometheus(r.Context())))
+	})
+}
+
+// exportPrometheus renders all bridge metrics in Prometheus text format
+func (s *Server) exportPrometheus(ctx context.Context) string {
+	var b strings.Builder
+
+	if s.metrics != nil {
+		s.metrics.UpdateUptime()
+		b.WriteString(s.metrics.Export())
+	}
+
+	methods := s.methodStats.snapshot()
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writeMetricHeader(&b, "armorclaw_rpc_calls_total", "counter", "Calls to each registered RPC method since startup")
+	for _, name := range names {
+		fmt.Fprintf(&b, "armorclaw_rpc_calls_total{method=%q} %d\n", name, methods[name].Calls)
+	}
+	writeMetricHeader(&b, "armorclaw_rpc_errors_total", "counter", "Calls to each registered RPC method that returned an error")
+	for _, name := range names {
+		fmt.Fprintf(&b, "armorclaw_rpc_errors_total{method=%q} %d\n", name, methods[name].Errors)
+	}
+
+	if s.dockerClient != nil {
+		if running, err := s.countBridgeContainers(ctx); err != nil {
+			slog.Debug("metrics: failed to count containers", "error", err)
+		} else {
+			writeMetricHeader(&b, "armorclaw_containers_running", "gauge", "Running containers managed by the bridge")
+			fmt.Fprintf(&b, "armorclaw_containers_running %d\n", running)
+		}
+	}
+
+	if s.webrtcSessions != nil {
+		writeMetricHeader(&b, "armorclaw_webrtc_sessions_active", "gauge", "WebRTC voice sessions that have not ended")
+		fmt.Fprintf(&b, "armorclaw_webrtc_sessions_active %d\n", s.webrtcSessions.Count())
+	}
+
+	if s.budget != nil {
+		writeMetricHeader(&b, "armorclaw_budget_spend_usd", "gauge", "AI spend in the current budget window")
+		fmt.Fprintf(&b, "armorclaw_budget_spend_usd{window=\"daily\"} %.4f\n", s.budget.GetDailyUsage())
+		fmt.Fprintf(&b, "armorclaw_budget_spend_usd{window=\"monthly\"} %.4f\n", s.budget.GetMonthlyUsage())
+		writeMetricHeader(&b, "armorclaw_budget_limit_usd", "gauge", "Configured budget limit (0 = unlimited)")
+		fmt.Fprintf(&b, "armorclaw_budget_limit_usd{window=\"daily\"} %.4f\n", s.budget.GetDailyLimit())
+		fmt.Fprintf(&b, "armorclaw_budget_limit_usd{window=\"monthly\"} %.4f\n", s.budget.GetMonthlyLimit())
+	}
+
+	if s.eventBus != nil {
+		m := s.eventBus.Metrics()
+		writeMetricHeader(&b, "armorclaw_eventbus_subscribers", "gauge", "In-process event bus subscribers")
+		fmt.Fprintf(&b, "armorclaw_eventbus_subscribers %d\n", m.Subscribers)
+		writeMetricHeader(&b, "armorclaw_eventbus_websocket_clients", "gauge", "Connected event WebSocket clients")
+		fmt.Fprintf(&b, "armorclaw_eventbus_websocket_clients %d\n", m.WebSocketClients)
+		writeMetricHeader(&b, "armorclaw_eventbus_published_events_total", "counter", "Events published on the event bus")
+		fmt.Fprintf(&b, "armorclaw_eventbus_published_events_total %d\n", m.PublishedEvents)
+		writeMetricHeader(&b, "armorclaw_eventbus_dropped_events_total", "counter", "Event deliveries skipped because a consumer's buffer was full")
+		fmt.Fprintf(&b, "armorclaw_eventbus_dropped_events_total %d\n", m.DroppedEvents)
+	}
+
+	return b.String()
+}
+
+// countBridgeContainers returns the number of running containers carrying
+// an ArmorClaw label
+func (s *Server) countBridgeContainers(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, metricsDockerTimeout)
+	defer cancel()
+
+	containers, err := s.dockerClient.ListContainers(ctx, false, filters.Args{})
+	if err != nil {
+		return 0, err
+	}
+
+	running := 0
+	for _, container := range containers {
+		for key := range container.Labels {
+			if containsArmorClawLabel(key) {
+				running++
+				break
+			}
+		}
+	}
+	return running, nil
+}
+
+func writeMetricHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/armorclaw/bridge/pkg/budget"
+	"github.com/armorclaw/bridge/pkg/eventbus"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	tracker, err := budget.NewBudgetTracker(budget.BudgetConfig{DailyLimitUSD: 10, MonthlyLimitUSD: 100})
+	if err != nil {
+		t.Fatalf("failed to create budget tracker: %v", err)
+	}
+	tracker.RecordKeyUsage("openai-main", "openai", 2.5)
+
+	bus := eventbus.NewEventBus(eventbus.Config{MaxSubscribers: 25})
+	defer bus.Stop()
+	if _, err := bus.Subscribe(eventbus.EventFilter{}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	server := &Server{metrics: NewMetrics(), budget: tracker, eventBus: bus}
+	server.methodStats.record("status", false)
+	server.methodStats.record("status", true)
+
+	rec := httptest.NewRecorder()
+	server.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"armorclaw_uptime_seconds",
+		`armorclaw_rpc_calls_total{method="status"} 2`,
+		`armorclaw_rpc_errors_total{method="status"} 1`,
+		`armorclaw_budget_spend_usd{window="daily"} 2.5000`,
+		`armorclaw_budget_limit_usd{window="monthly"} 100.0000`,
+		"armorclaw_eventbus_subscribers 1",
+		"# TYPE armorclaw_eventbus_dropped_events_total counter",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+
+	// Components that are not configured are left out
+	for _, absent := range []string{"armorclaw_containers_running", "armorclaw_webrtc_sessions_active"} {
+		if strings.Contains(body, absent) {
+			t.Errorf("metrics output has %s without the component configured", absent)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	server.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
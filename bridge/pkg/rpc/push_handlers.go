@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/armorclaw/bridge/pkg/push"
+)
+
+// pushPlatforms maps the platform names clients send to push platforms
+var pushPlatforms = map[string]push.Platform{
+	"android": push.PlatformFCM,
+	"fcm":     push.PlatformFCM,
+	"ios":     push.PlatformAPNS,
+	"apns":    push.PlatformAPNS,
+	"web":     push.PlatformWebPush,
+	"webpush": push.PlatformWebPush,
+}
+
+// handlePushRegisterToken registers or refreshes a device's push token.
+// Registering again with a new token replaces the old one.
+func (s *Server) handlePushRegisterToken(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		DeviceID string `json:"device_id"`
+		Token    string `json:"token"`
+		Platform string `json:"platform"`
+		UserID   string `json:"user_id,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.DeviceID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "device_id is required",
+		}
+	}
+	if params.Token == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "token is required",
+		}
+	}
+	platform, ok := pushPlatforms[strings.ToLower(params.Platform)]
+	if !ok {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "platform must be android, ios or web",
+		}
+	}
+
+	if s.pushGateway == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "push notifications not configured",
+		}
+	}
+
+	if _, err := s.pushGateway.RegisterToken(params.DeviceID, params.UserID, platform, params.Token); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "failed to register push token: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"message":   "push token registered",
+		"device_id": params.DeviceID,
+	}, nil
+}
+
+// handlePushUnregisterToken stops push notifications to a device. When a
+// token is given it must match the registered one, so a stale client cannot
+// remove a newer registration.
+func (s *Server) handlePushUnregisterToken(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		DeviceID string `json:"device_id"`
+		Token    string `json:"token,omitempty"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.DeviceID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "device_id is required",
+		}
+	}
+
+	if s.pushGateway == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "push notifications not configured",
+		}
+	}
+
+	device, err := s.pushGateway.GetDevice(params.DeviceID)
+	if err != nil || (params.Token != "" && device.DeviceToken != params.Token) {
+		return nil, &ErrorObj{
+			Code:    NotFoundError,
+			Message: "push registration not found",
+		}
+	}
+
+	if err := s.pushGateway.UnregisterDevice(params.DeviceID); err != nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to unregister push token: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success":   true,
+		"message":   "push token unregistered",
+		"device_id": params.DeviceID,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/armorclaw/bridge/pkg/push"
+)
+
+func pushRequest(params map[string]string) *Request {
+	raw, _ := json.Marshal(params)
+	return &Request{Params: raw}
+}
+
+func TestPushHandlersRegistered(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	for _, method := range []string{"push.register_token", "push.unregister_token"} {
+		if _, ok := server.handlers[method]; !ok {
+			t.Errorf("%s not registered", method)
+		}
+	}
+}
+
+func TestPushRegisterToken(t *testing.T) {
+	gw, err := push.NewGateway(push.Config{FCMEnabled: true, FCMServerKey: "test-key"})
+	if err != nil {
+		t.Fatalf("NewGateway() error = %v", err)
+	}
+	server := &Server{pushGateway: gw}
+	token := strings.Repeat("f", 152)
+
+	for _, params := range []map[string]string{
+		{"token": token, "platform": "android"},
+		{"device_id": "phone-1", "platform": "android"},
+		{"device_id": "phone-1", "token": token, "platform": "blackberry"},
+		{"device_id": "phone-1", "token": "short", "platform": "android"},
+		{"device_id": "phone-1", "token": token, "platform": "ios"},
+	} {
+		if _, errObj := server.handlePushRegisterToken(context.Background(), pushRequest(params)); errObj == nil || errObj.Code != InvalidParams {
+			t.Errorf("register %v: expected InvalidParams, got %+v", params, errObj)
+		}
+	}
+
+	result, errObj := server.handlePushRegisterToken(context.Background(), pushRequest(map[string]string{
+		"device_id": "phone-1",
+		"token":     token,
+		"platform":  "Android",
+		"user_id":   "@alice:example.com",
+	}))
+	if errObj != nil {
+		t.Fatalf("register: unexpected error %+v", errObj)
+	}
+	if resp := result.(map[string]interface{}); resp["success"] != true || resp["device_id"] != "phone-1" {
+		t.Errorf("register response = %v", resp)
+	}
+	device, err := gw.GetDevice("phone-1")
+	if err != nil || device.DeviceToken != token || device.Platform != push.PlatformFCM || device.UserID != "@alice:example.com" {
+		t.Fatalf("registered device = %+v, %v", device, err)
+	}
+
+	// A stale token does not remove a newer registration
+	_, errObj = server.handlePushUnregisterToken(context.Background(), pushRequest(map[string]string{
+		"device_id": "phone-1",
+		"token":     strings.Repeat("e", 152),
+	}))
+	if errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("unregister stale token: expected NotFoundError, got %+v", errObj)
+	}
+
+	if _, errObj := server.handlePushUnregisterToken(context.Background(), pushRequest(map[string]string{
+		"device_id": "phone-1",
+		"token":     token,
+	})); errObj != nil {
+		t.Fatalf("unregister: unexpected error %+v", errObj)
+	}
+	if _, err := gw.GetDevice("phone-1"); err == nil {
+		t.Error("device still registered after unregister")
+	}
+}
+
+func TestPushHandlersNotConfigured(t *testing.T) {
+	server := &Server{}
+
+	_, errObj := server.handlePushRegisterToken(context.Background(), pushRequest(map[string]string{
+		"device_id": "phone-1",
+		"token":     "token",
+		"platform":  "android",
+	}))
+	if errObj == nil || errObj.Code != InternalError {
+		t.Errorf("register: expected InternalError when not configured, got %+v", errObj)
+	}
+
+	_, errObj = server.handlePushUnregisterToken(context.Background(), pushRequest(map[string]string{"device_id": "phone-1"}))
+	if errObj == nil || errObj.Code != InternalError {
+		t.Errorf("unregister: expected InternalError when not configured, got %+v", errObj)
+	}
+}
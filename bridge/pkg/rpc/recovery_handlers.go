@@ -0,0 +1,116 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+	"github.com/armorclaw/bridge/pkg/recovery"
+	"github.com/armorclaw/bridge/pkg/trust"
+)
+
+// handleRecoveryCancel aborts a recovery in progress, for example one
+// started by someone who obtained the recovery phrase. It must come from a
+// device that is still valid; when a device store is configured the device
+// must also be verified. The admin is notified of every cancellation.
+func (s *Server) handleRecoveryCancel(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		RecoveryID string `json:"recovery_id"`
+		DeviceID   string `json:"device_id"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.RecoveryID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "recovery_id is required",
+		}
+	}
+	if params.DeviceID == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "device_id is required",
+		}
+	}
+
+	if s.recoveryManager == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "recovery manager not configured",
+		}
+	}
+
+	if s.deviceStore != nil {
+		device, err := s.deviceStore.GetDevice(params.DeviceID)
+		if err != nil || device.TrustState != trust.StateVerified {
+			slog.Warn("recovery_cancel_denied",
+				"recovery_id", params.RecoveryID,
+				"device_id", params.DeviceID,
+				"reason", "device not verified")
+			return nil, &ErrorObj{
+				Code:    InvalidRequest,
+				Message: "recovery can only be cancelled from a verified device",
+			}
+		}
+	}
+
+	if err := s.recoveryManager.AuthorizeCancel(params.RecoveryID, params.DeviceID); err != nil {
+		return nil, recoveryError(err)
+	}
+	if err := s.recoveryManager.CancelRecovery(params.RecoveryID); err != nil {
+		return nil, recoveryError(err)
+	}
+
+	s.notifyRecoveryCancelled(ctx, params.RecoveryID, params.DeviceID)
+
+	return map[string]interface{}{
+		"success":     true,
+		"recovery_id": params.RecoveryID,
+		"status":      recovery.RecoveryStatusCancelled,
+		"message":     "Recovery cancelled. Full access restored; store a new recovery phrase.",
+	}, nil
+}
+
+// recoveryError maps recovery manager errors to RPC errors
+func recoveryError(err error) *ErrorObj {
+	switch {
+	case errors.Is(err, recovery.ErrRecoveryNotFound):
+		return &ErrorObj{Code: NotFoundError, Message: err.Error()}
+	case errors.Is(err, recovery.ErrDeviceNotAllowed),
+		errors.Is(err, recovery.ErrRecoveryNotActive),
+		errors.Is(err, recovery.ErrRecoveryExpired):
+		return &ErrorObj{Code: InvalidRequest, Message: err.Error()}
+	default:
+		return &ErrorObj{Code: InternalError, Message: "recovery failed: " + err.Error()}
+	}
+}
+
+// notifyRecoveryCancelled tells the admin that a recovery was cancelled so
+// an unexpected cancellation, or the recovery attempt itself, is noticed
+func (s *Server) notifyRecoveryCancelled(ctx context.Context, recoveryID, deviceID string) {
+	traced := errsys.NewBuilder("SYS-040").
+		WithMessage(fmt.Sprintf("recovery %s cancelled from device %s", recoveryID, deviceID)).
+		WithFunction("handleRecoveryCancel").
+		WithStateValue("recovery_id", recoveryID).
+		WithStateValue("device_id", deviceID).
+		Build()
+
+	var err error
+	if s.errorSystem != nil {
+		err = s.errorSystem.Notify(ctx, traced)
+	} else if errsys.GetGlobalNotifier() != nil {
+		err = errsys.GlobalNotify(ctx, traced)
+	}
+	if err != nil {
+		slog.Warn("recovery_cancel_notify_failed", "recovery_id", recoveryID, "error", err)
+	}
+}
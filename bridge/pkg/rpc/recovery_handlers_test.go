@@ -0,0 +1,128 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+	"github.com/armorclaw/bridge/pkg/recovery"
+	"github.com/armorclaw/bridge/pkg/trust"
+)
+
+// newTestRecovery returns a recovery manager with a recovery in progress
+// from device-new
+func newTestRecovery(t *testing.T) (*recovery.Manager, string) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	mgr, err := recovery.NewManager(db, make([]byte, 32))
+	if err != nil {
+		t.Fatalf("new recovery manager: %v", err)
+	}
+	phrase, err := recovery.GeneratePhrase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mgr.StorePhrase(phrase); err != nil {
+		t.Fatalf("store phrase: %v", err)
+	}
+	state, err := mgr.VerifyPhrase(phrase, "device-new")
+	if err != nil {
+		t.Fatalf("verify phrase: %v", err)
+	}
+	return mgr, state.ID
+}
+
+func cancelRequest(recoveryID, deviceID string) *Request {
+	params, _ := json.Marshal(map[string]string{"recovery_id": recoveryID, "device_id": deviceID})
+	return &Request{Params: params}
+}
+
+func TestRecoveryCancelRegistered(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	if _, ok := server.handlers["recovery.cancel"]; !ok {
+		t.Fatal("recovery.cancel not registered")
+	}
+}
+
+func TestRecoveryCancelValidation(t *testing.T) {
+	server := &Server{}
+
+	for _, req := range []*Request{cancelRequest("", "device-owner"), cancelRequest("recovery-1", "")} {
+		if _, errObj := server.handleRecoveryCancel(context.Background(), req); errObj == nil || errObj.Code != InvalidParams {
+			t.Errorf("expected InvalidParams, got %+v", errObj)
+		}
+	}
+
+	_, errObj := server.handleRecoveryCancel(context.Background(), cancelRequest("recovery-1", "device-owner"))
+	if errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError when not configured, got %+v", errObj)
+	}
+}
+
+func TestRecoveryCancelRequiresValidDevice(t *testing.T) {
+	mgr, recoveryID := newTestRecovery(t)
+	store := newTestDeviceStore(t)
+	seedDevice(t, store, "device-owner", trust.StateVerified)
+	seedDevice(t, store, "device-pending", trust.StatePendingApproval)
+	seedDevice(t, store, "device-new", trust.StateVerified)
+	server := &Server{recoveryManager: mgr, deviceStore: store}
+
+	for _, device := range []string{"device-pending", "device-unknown", "device-new"} {
+		_, errObj := server.handleRecoveryCancel(context.Background(), cancelRequest(recoveryID, device))
+		if errObj == nil || errObj.Code != InvalidRequest {
+			t.Errorf("cancel from %s: expected InvalidRequest, got %+v", device, errObj)
+		}
+	}
+
+	_, errObj := server.handleRecoveryCancel(context.Background(), cancelRequest("missing", "device-owner"))
+	if errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("expected NotFoundError for unknown recovery, got %+v", errObj)
+	}
+}
+
+func TestRecoveryCancelNotifiesAdmin(t *testing.T) {
+	mgr, recoveryID := newTestRecovery(t)
+	system, err := errsys.Initialize(errsys.Config{
+		ConfigAdminMXID: "@admin:example.com",
+		Enabled:         true,
+		NotifyEnabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("initialize error system: %v", err)
+	}
+	defer system.Stop()
+	system.SetDryRun(true)
+	server := &Server{recoveryManager: mgr, errorSystem: system}
+
+	result, errObj := server.handleRecoveryCancel(context.Background(), cancelRequest(recoveryID, "device-owner"))
+	if errObj != nil {
+		t.Fatalf("unexpected error: %+v", errObj)
+	}
+	if result.(map[string]interface{})["status"] != recovery.RecoveryStatusCancelled {
+		t.Errorf("result = %+v, want cancelled status", result)
+	}
+
+	messages := system.DryRunMessages()
+	if len(messages) != 1 || !strings.Contains(messages[0].Message, "SYS-040") || !strings.Contains(messages[0].Message, recoveryID) {
+		t.Errorf("admin notifications = %+v, want one SYS-040 for %s", messages, recoveryID)
+	}
+
+	// The recovery is over, so a second cancel is rejected
+	_, errObj = server.handleRecoveryCancel(context.Background(), cancelRequest(recoveryID, "device-owner"))
+	if errObj == nil || errObj.Code != InvalidRequest {
+		t.Errorf("expected InvalidRequest for a cancelled recovery, got %+v", errObj)
+	}
+}
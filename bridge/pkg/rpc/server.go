@@ -2,8 +2,10 @@ package rpc
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -24,19 +26,27 @@ import (
 	"github.com/armorclaw/bridge/internal/skills"
 	"github.com/armorclaw/bridge/pkg/appservice"
 	"github.com/armorclaw/bridge/pkg/browser"
+	"github.com/armorclaw/bridge/pkg/budget"
 	"github.com/armorclaw/bridge/pkg/docker"
+	errsys "github.com/armorclaw/bridge/pkg/errors"
 	"github.com/armorclaw/bridge/pkg/eventbus"
 	"github.com/armorclaw/bridge/pkg/eventlog"
+	"github.com/armorclaw/bridge/pkg/health"
 	"github.com/armorclaw/bridge/pkg/interfaces"
 	"github.com/armorclaw/bridge/pkg/keystore"
 	"github.com/armorclaw/bridge/pkg/mcp"
+	"github.com/armorclaw/bridge/pkg/plugin"
 	"github.com/armorclaw/bridge/pkg/provisioning"
+	"github.com/armorclaw/bridge/pkg/push"
+	"github.com/armorclaw/bridge/pkg/recovery"
 	"github.com/armorclaw/bridge/pkg/secretary"
 	"github.com/armorclaw/bridge/pkg/studio"
 	"github.com/armorclaw/bridge/pkg/translator"
 	"github.com/armorclaw/bridge/pkg/audit"
 	"github.com/armorclaw/bridge/pkg/invite"
 	"github.com/armorclaw/bridge/pkg/trust"
+	"github.com/armorclaw/bridge/pkg/turn"
+	"github.com/armorclaw/bridge/pkg/webrtc"
 )
 
 const (
@@ -50,14 +60,31 @@ const (
 	NotFoundError    = -32000
 	TooManyRequests  = -32001
 	RequestCancelled = -32002
+	// PIIAccessRequired means a sensitive read needs an approved PII request;
+	// the error data carries the request ID
+	PIIAccessRequired = -32003
 )
 
+// DefaultRequestTimeout bounds how long a connection may take to deliver its request
+const DefaultRequestTimeout = 30 * time.Second
+
+// DefaultMaxConnections caps concurrently handled RPC connections
+const DefaultMaxConnections = 256
+
+// DefaultExportDir is where export_errors and audit.export write files
+const DefaultExportDir = "/var/lib/armorclaw/exports"
+
+// connLimitLogInterval throttles connection limit warnings
+const connLimitLogInterval = 10 * time.Second
+
 type BridgeManager interface {
 	Start() error
 	Stop() error
 	RegisterAdapter(platform appservice.Platform, adapter interface{}) error
 	BridgeChannel(matrixRoomID string, platform appservice.Platform, channelID string) error
 	UnbridgeChannel(platform appservice.Platform, channelID string) error
+	Backfill(ctx context.Context, platform appservice.Platform, channelID string, limit int) (int, error)
+	SyncGhostProfiles(ctx context.Context) (appservice.ProfileSyncResult, error)
 	GetBridgedChannels() []*appservice.BridgedChannel
 	GetStats() map[string]interface{}
 }
@@ -96,11 +123,67 @@ type Request struct {
 	ID      interface{}     `json:"id,omitempty"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
+
+	// hasID is set when the decoded payload carried an id member, so an
+	// explicit "id": null (a request) is not mistaken for a notification
+	hasID bool
+}
+
+// UnmarshalJSON decodes a request and records whether id was present
+func (r *Request) UnmarshalJSON(data []byte) error {
+	type plain Request
+	var raw struct {
+		plain
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = Request(raw.plain)
+	r.hasID = raw.ID != nil
+	if r.hasID {
+		if err := json.Unmarshal(raw.ID, &r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsNotification reports whether the request has no id member
+func (r *Request) IsNotification() bool {
+	return r.ID == nil && !r.hasID
+}
+
+// callerKey is the context key for the authenticated caller of a request
+type callerKey struct{}
+
+// WithCaller returns a context recording who authenticated the request
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the authenticated caller of a request, or ""
+// for local socket requests, which are vouched for by file permissions
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+// validID reports whether id is a string, number, or null as the spec requires
+func validID(id interface{}) bool {
+	switch id.(type) {
+	case nil, string, float64, json.Number,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32:
+		return true
+	}
+	return false
 }
 
 type Response struct {
 	JSONRPC string      `json:"jsonrpc"`
-	ID      interface{} `json:"id,omitempty"`
+	ID      interface{} `json:"id"`
 	Result  interface{} `json:"result,omitempty"`
 	Error   *ErrorObj   `json:"error,omitempty"`
 }
@@ -135,6 +218,7 @@ type MatrixHealthResult struct {
 type HealthCheckResponse struct {
 	Status     string            `json:"status"`
 	Components map[string]string `json:"components"`
+	EventBus   *eventbus.Metrics `json:"eventbus,omitempty"`
 }
 
 type HandlerFunc func(ctx context.Context, req *Request) (interface{}, *ErrorObj)
@@ -160,9 +244,11 @@ type Server struct {
 	hardeningStore  trust.Store
 	deviceStore     *trust.DeviceStore
 	inviteStore     *invite.InviteStore
+	senderAllowlist *trust.SenderAllowlist
 	secretaryHandler secretaryRPCHandler
 	heartbeats      sync.Map
 	metrics         *Metrics
+	methodStats     methodMetrics
 	listener        net.Listener
 	shutdownCh      chan struct{}
 	rpcTransport    string
@@ -170,8 +256,30 @@ type Server struct {
 	dockerClient    *docker.Client
 	guard           *trust.TrustedProxyGuard
 	auditLog        *audit.AuditLog
+	errorSystem     *errsys.System
+	exportDir       string
+	licenseClient   LicenseCache
+	licenseCheckInterval time.Duration
+	licenseWarnMu   sync.Mutex
+	licenseWarned   string // expiry/days key of the last warning sent
+	startTime       time.Time
+	buildTime       string
+	requestTimeout  time.Duration
+	connSem         chan struct{} // one slot per in-flight connection
+	connRejected    int           // rejections since the last limit warning (accept loop only)
+	connLimitLogged time.Time
 	governanceRoomID string
 	tlsInfoProvider   TLSInfoProvider
+	webrtcEngine      *webrtc.Engine
+	webrtcSessions    *webrtc.SessionManager
+	webrtcTokens      *webrtc.TokenManager
+	turnManager       *turn.Manager
+	budget            *budget.BudgetTracker
+	healthMonitor     *health.Monitor
+	pluginManager     *plugin.PluginManager
+	recoveryManager   *recovery.Manager
+	pushGateway       *push.Gateway
+	pushDispatcher    *push.Dispatcher
 	piiRequestManager *keystore.PIIRequestManager
 }
 
@@ -194,20 +302,45 @@ type Config struct {
 	HardeningStore  trust.Store
 	DeviceStore     *trust.DeviceStore
 	InviteStore     *invite.InviteStore
+	SenderAllowlist *trust.SenderAllowlist // Optional; enables trust.add_sender, trust.remove_sender and trust.list
 	Metrics         *Metrics
 	DockerClient    *docker.Client
 	Guard           *trust.TrustedProxyGuard
-	AuditLog        *audit.AuditLog
+	AuditLog        *audit.AuditLog // Optional; records governance changes and enables audit.export
+	ErrorSystem     *errsys.System
+	ExportDir       string // Directory export_errors and audit.export write files to (default DefaultExportDir)
+	LicenseClient   LicenseCache  // Optional; enables license expiry warnings
+	LicenseCheckInterval time.Duration // How often to check license expiry (default 1h)
+	BuildTime       string // Compile-time build timestamp, reported by bridge.status
+	RequestTimeout  time.Duration // Max time to read a request from a connection (default 30s)
+	MaxConnections  int           // Max concurrently handled connections (default 256)
 	MCPRouter       *mcp.MCPRouter
 	GovernanceRoomID string
 	Translator      *translator.RPCToMCPTranslator
 	SecretaryHandler secretaryRPCHandler
+	WebRTCEngine    *webrtc.Engine         // Optional; enables webrtc.* methods
+	WebRTCSessions  *webrtc.SessionManager // Required with WebRTCEngine
+	WebRTCTokens    *webrtc.TokenManager   // Optional; adds a signaling token to webrtc.start
+	TURNManager     *turn.Manager          // Optional; enables webrtc.refresh_turn
+	Budget          *budget.BudgetTracker  // Optional; enables budget.* methods and ai.chat spend tracking
+	HealthMonitor   *health.Monitor        // Optional; enables container.health_history
+	PluginManager   *plugin.PluginManager  // Optional; enables plugin.reload
+	RecoveryManager *recovery.Manager      // Optional; enables recovery.cancel
+	PushGateway     *push.Gateway          // Optional; enables push.register_token and push.unregister_token
+	PushDispatcher  *push.Dispatcher       // Optional; pushes device approvals
+	PIIApprovalValidity time.Duration      // How long an approved PII request grants access (default 15m)
 }
 
 func New(cfg Config) (*Server, error) {
 	if cfg.AIMaxConcurrent <= 0 {
 		cfg.AIMaxConcurrent = 4
 	}
+	if cfg.MaxConnections <= 0 {
+		cfg.MaxConnections = DefaultMaxConnections
+	}
+	if cfg.ExportDir == "" {
+		cfg.ExportDir = DefaultExportDir
+	}
 
 	s := &Server{
 		keystore:        cfg.Keystore,
@@ -227,6 +360,7 @@ func New(cfg Config) (*Server, error) {
 		hardeningStore:  cfg.HardeningStore,
 		deviceStore:     cfg.DeviceStore,
 		inviteStore:     cfg.InviteStore,
+		senderAllowlist: cfg.SenderAllowlist,
 		metrics:         cfg.Metrics,
 		shutdownCh:      make(chan struct{}),
 		rpcTransport:    cfg.RPCTransport,
@@ -234,14 +368,33 @@ func New(cfg Config) (*Server, error) {
 		dockerClient:    cfg.DockerClient,
 		guard:           cfg.Guard,
 		auditLog:        cfg.AuditLog,
+		errorSystem:     cfg.ErrorSystem,
+		exportDir:       cfg.ExportDir,
+		licenseClient:   cfg.LicenseClient,
+		licenseCheckInterval: cfg.LicenseCheckInterval,
+		startTime:       time.Now(),
+		buildTime:       cfg.BuildTime,
+		requestTimeout:  cfg.RequestTimeout,
+		connSem:         make(chan struct{}, cfg.MaxConnections),
 		mcpRouter:       cfg.MCPRouter,
 		translator:      cfg.Translator,
 		secretaryHandler: cfg.SecretaryHandler,
 		governanceRoomID: cfg.GovernanceRoomID,
+		webrtcEngine:    cfg.WebRTCEngine,
+		webrtcSessions:  cfg.WebRTCSessions,
+		webrtcTokens:    cfg.WebRTCTokens,
+		turnManager:     cfg.TURNManager,
+		budget:          cfg.Budget,
+		healthMonitor:   cfg.HealthMonitor,
+		pluginManager:   cfg.PluginManager,
+		recoveryManager: cfg.RecoveryManager,
+		pushGateway:     cfg.PushGateway,
+		pushDispatcher:  cfg.PushDispatcher,
 	}
 
 	s.piiRequestManager = keystore.NewPIIRequestManager(keystore.PIIRequestManagerConfig{
-		DefaultTTL: 5 * time.Minute,
+		DefaultTTL:       5 * time.Minute,
+		ApprovalValidity: cfg.PIIApprovalValidity,
 	})
 
 	s.registerHandlers()
@@ -274,6 +427,9 @@ func (s *Server) Handle(ctx context.Context, req *Request) (resp *Response) {
 				"id", id,
 				"recover", r,
 			)
+			if _, ok := s.handlers[method]; ok {
+				s.methodStats.record(method, true)
+			}
 			resp = errorResponse(id, InternalError, "internal server error")
 		}
 	}()
@@ -286,7 +442,11 @@ func (s *Server) Handle(ctx context.Context, req *Request) (resp *Response) {
 		s.metrics.IncrementCounter("armorclaw_rpc_requests_total", req.Method)
 	}
 
-	isNotification := req.ID == nil
+	if !validID(req.ID) {
+		return errorResponse(nil, InvalidRequest, "id must be a string, number, or null")
+	}
+
+	isNotification := req.IsNotification()
 
 	if req.JSONRPC != JSONRPCVersion {
 		if isNotification {
@@ -312,6 +472,7 @@ func (s *Server) Handle(ctx context.Context, req *Request) (resp *Response) {
 	}
 
 	result, rpcErr := handler(ctx, req)
+	s.methodStats.record(req.Method, rpcErr != nil)
 
 	if isNotification {
 		return nil
@@ -332,6 +493,40 @@ func (s *Server) Handle(ctx context.Context, req *Request) (resp *Response) {
 	}
 }
 
+// HandleBatch dispatches a JSON-RPC 2.0 batch through Handle. Responses keep
+// the order of their requests and notifications get none. Per the spec an
+// empty batch is answered with a single Invalid Request error, and nil is
+// returned when every element was a notification.
+func (s *Server) HandleBatch(ctx context.Context, batch []json.RawMessage) interface{} {
+	return s.HandleBatchWith(ctx, batch, s.Handle)
+}
+
+// HandleBatchWith is HandleBatch with each entry dispatched through handle,
+// for transports that must authenticate every entry before it reaches
+// Handle. handle returns nil for requests that get no response.
+func (s *Server) HandleBatchWith(ctx context.Context, batch []json.RawMessage, handle func(context.Context, *Request) *Response) interface{} {
+	if len(batch) == 0 {
+		return errorResponse(nil, InvalidRequest, "empty batch")
+	}
+
+	responses := make([]*Response, 0, len(batch))
+	for _, raw := range batch {
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			responses = append(responses, errorResponse(nil, InvalidRequest, "invalid request"))
+			continue
+		}
+		if resp := handle(ctx, &req); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
 func errorResponse(id interface{}, code int, msg string) *Response {
 	return &Response{
 		JSONRPC: JSONRPCVersion,
@@ -386,6 +581,14 @@ func (s *Server) handleHealthCheck(ctx context.Context, req *Request) (interface
 		components["keystore"] = "error"
 	}
 
+	// The event bus is optional, so it only reports and never degrades status
+	var busMetrics *eventbus.Metrics
+	if s.eventBus != nil {
+		m := s.eventBus.Metrics()
+		busMetrics = &m
+		components["eventbus"] = "ok"
+	}
+
 	if components["bridge"] == "ok" && components["matrix"] == "connected" && components["keystore"] == "initialized" {
 		status = "healthy"
 	} else if components["bridge"] == "ok" {
@@ -395,6 +598,7 @@ func (s *Server) handleHealthCheck(ctx context.Context, req *Request) (interface
 	return HealthCheckResponse{
 		Status:     status,
 		Components: components,
+		EventBus:   busMetrics,
 	}, nil
 }
 
@@ -463,9 +667,11 @@ func (s *Server) handleMatrixLogin(ctx context.Context, req *Request) (interface
 
 func (s *Server) handleMatrixSend(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	var params struct {
-		RoomID  string `json:"room_id"`
-		Message string `json:"message"`
-		MsgType string `json:"msgtype"`
+		RoomID        string `json:"room_id"`
+		Message       string `json:"message"`
+		MsgType       string `json:"msgtype"`
+		FormattedBody string `json:"formatted_body,omitempty"`
+		Format        string `json:"format,omitempty"`
 	}
 
 	if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -475,6 +681,19 @@ func (s *Server) handleMatrixSend(ctx context.Context, req *Request) (interface{
 		}
 	}
 
+	if params.Format != "" && params.Format != adapter.HTMLFormat {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: fmt.Sprintf("unsupported format %q (only %s is supported)", params.Format, adapter.HTMLFormat),
+		}
+	}
+	if params.Format != "" && params.FormattedBody == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "format requires formatted_body",
+		}
+	}
+
 	matrix, ok := s.matrix.(*adapter.MatrixAdapter)
 	if !ok || matrix == nil {
 		return nil, &ErrorObj{
@@ -483,7 +702,7 @@ func (s *Server) handleMatrixSend(ctx context.Context, req *Request) (interface{
 		}
 	}
 
-	eventID, err := matrix.SendMessage(params.RoomID, params.Message, params.MsgType)
+	eventID, err := matrix.SendHTMLMessage(params.RoomID, params.Message, params.FormattedBody, params.MsgType)
 	if err != nil {
 		return nil, &ErrorObj{
 			Code:    InternalError,
@@ -504,6 +723,7 @@ func (s *Server) handleMatrixSend(ctx context.Context, req *Request) (interface{
 func (s *Server) handleMatrixReceive(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	var params struct {
 		Cursor    string `json:"cursor"`
+		Limit     int    `json:"limit"`
 		TimeoutMs int    `json:"timeout_ms"`
 	}
 
@@ -517,6 +737,23 @@ func (s *Server) handleMatrixReceive(ctx context.Context, req *Request) (interfa
 	if params.TimeoutMs <= 0 {
 		params.TimeoutMs = 30000
 	}
+	if params.Limit < 0 || params.Limit > events.MaxBatchSize {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: fmt.Sprintf("limit must be between 1 and %d", events.MaxBatchSize),
+		}
+	}
+
+	cursor := uint64(0)
+	if params.Cursor != "" {
+		var err error
+		if cursor, err = strconv.ParseUint(params.Cursor, 10, 64); err != nil {
+			return nil, &ErrorObj{
+				Code:    InvalidParams,
+				Message: fmt.Sprintf("invalid cursor %q", params.Cursor),
+			}
+		}
+	}
 
 	matrix, ok := s.matrix.(*adapter.MatrixAdapter)
 	if !ok || matrix == nil {
@@ -526,13 +763,41 @@ func (s *Server) handleMatrixReceive(ctx context.Context, req *Request) (interfa
 		}
 	}
 
-	// If event bus is configured, use it for streaming
-	if matrix.GetEventBus() != nil {
-		return s.handleMatrixReceiveWithEventBus(ctx, req, &params, matrix)
+	eventBus := matrix.GetEventBus()
+	if eventBus == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "matrix event buffer not configured",
+		}
+	}
+
+	timeout := time.Duration(params.TimeoutMs) * time.Millisecond
+	recvCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	page := eventBus.WaitForEvents(recvCtx, cursor, params.Limit)
+
+	if page.Dropped > 0 {
+		slog.Warn("matrix_receive_events_dropped",
+			"cursor", cursor,
+			"dropped", page.Dropped,
+			"resumed_at", page.Next,
+		)
+	}
+
+	evs := page.Events
+	if evs == nil {
+		evs = []events.MatrixEvent{}
 	}
 
-	// Fallback to polling old event queue
-	return s.handleMatrixReceivePolling(ctx, req, &params, matrix)
+	return map[string]interface{}{
+		"events":       evs,
+		"cursor":       strconv.FormatUint(page.Next, 10),
+		"count":        len(evs),
+		"has_more":     page.HasMore,
+		"dropped":      page.Dropped,
+		"cursor_reset": page.Reset,
+	}, nil
 }
 
 // handleMatrixJoinRoom joins an existing Matrix room
@@ -576,81 +841,6 @@ func (s *Server) handleMatrixJoinRoom(ctx context.Context, req *Request) (interf
 	return map[string]string{"room_id": joinedRoom}, nil
 }
 
-func (s *Server) handleMatrixReceiveWithEventBus(ctx context.Context, req *Request, params *struct {
-	Cursor    string `json:"cursor"`
-	TimeoutMs int    `json:"timeout_ms"`
-}, matrix *adapter.MatrixAdapter) (interface{}, *ErrorObj) {
-	cursor, err := strconv.ParseUint(params.Cursor, 10, 64)
-	if err != nil {
-		cursor = 0
-	}
-
-	eventBus := matrix.GetEventBus()
-	if eventBus == nil {
-		return map[string]interface{}{
-			"events":       []events.MatrixEvent{},
-			"cursor":       strconv.FormatUint(cursor, 10),
-			"count":        0,
-			"cursor_reset": false,
-		}, nil
-	}
-
-	timeout := time.Duration(params.TimeoutMs) * time.Millisecond
-	recvCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	evs, next, reset := eventBus.WaitForEvents(recvCtx, cursor)
-
-	if recvCtx.Err() != nil && len(evs) == 0 && !reset {
-		return map[string]interface{}{
-			"events":       []events.MatrixEvent{},
-			"cursor":       strconv.FormatUint(cursor, 10),
-			"count":        0,
-			"cursor_reset": false,
-		}, nil
-	}
-
-	return map[string]interface{}{
-		"events":       evs,
-		"cursor":       strconv.FormatUint(next, 10),
-		"count":        len(evs),
-		"cursor_reset": reset,
-	}, nil
-}
-
-func (s *Server) handleMatrixReceivePolling(ctx context.Context, req *Request, params *struct {
-	Cursor    string `json:"cursor"`
-	TimeoutMs int    `json:"timeout_ms"`
-}, matrix *adapter.MatrixAdapter) (interface{}, *ErrorObj) {
-	timeout := time.Duration(params.TimeoutMs) * time.Millisecond
-	select {
-	case <-ctx.Done():
-		return nil, &ErrorObj{
-			Code:    RequestCancelled,
-			Message: ctx.Err().Error(),
-		}
-	case <-time.After(timeout):
-		// Poll the event queue (backward compatibility)
-		events := make([]*adapter.MatrixEvent, 0, 10)
-		timeoutChan := time.After(timeout)
-
-		// Try to get events
-		select {
-		case e, ok := <-matrix.ReceiveEvents():
-			if ok {
-				events = append(events, e)
-			}
-		case <-timeoutChan:
-			// Timed out
-		}
-
-		return map[string]interface{}{
-			"events": events,
-			"count":  len(events),
-		}, nil
-	}
-}
-
 func (s *Server) handleEventsReplay(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
 	if s.eventBus == nil {
 		return nil, &ErrorObj{Code: InternalError, Message: "event bus not initialized"}
@@ -769,7 +959,8 @@ func (s *Server) handleStoreKey(ctx context.Context, req *Request) (interface{},
 		CreatedAt:   time.Now().Unix(),
 	}
 
-	if err := ks.Store(cred); err != nil {
+	duplicates, err := ks.StoreWithDuplicateCheck(cred)
+	if err != nil {
 		return nil, &ErrorObj{Code: InternalError, Message: "failed to store key: " + err.Error()}
 	}
 
@@ -777,11 +968,180 @@ func (s *Server) handleStoreKey(ctx context.Context, req *Request) (interface{},
 		s.metrics.IncrementCounter("armorclaw_keystore_operations_total", "store")
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"success":      true,
 		"id":           params.ID,
 		"provider":     params.Provider,
 		"display_name": params.DisplayName,
+	}
+	if len(duplicates) > 0 {
+		result["duplicate_of"] = duplicates
+		result["warning"] = "this token is already stored under another key id"
+	}
+	return result, nil
+}
+
+// openKeystore opens the configured keystore; callers must Close it
+func (s *Server) openKeystore() (*keystore.Keystore, *ErrorObj) {
+	if s.keystore == nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "keystore not configured"}
+	}
+
+	ks, ok := s.keystore.(*keystore.Keystore)
+	if !ok {
+		return nil, &ErrorObj{Code: InternalError, Message: "keystore not available"}
+	}
+
+	if err := ks.Open(); err != nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to open keystore: " + err.Error()}
+	}
+	return ks, nil
+}
+
+// handleListKeys lists stored key metadata, optionally filtered by provider
+// and/or tag. Tokens are never returned.
+func (s *Server) handleListKeys(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		Provider string `json:"provider"`
+		Tag      string `json:"tag"`
+	}
+
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+		}
+	}
+
+	ks, errObj := s.openKeystore()
+	if errObj != nil {
+		return nil, errObj
+	}
+	defer ks.Close()
+
+	var keys []keystore.KeyInfo
+	var err error
+	if params.Tag != "" {
+		keys, err = ks.ListByTag(params.Tag)
+	} else {
+		keys, err = ks.List(keystore.Provider(params.Provider))
+	}
+	if err != nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to list keys: " + err.Error()}
+	}
+
+	result := make([]keystore.KeyInfo, 0, len(keys))
+	for _, key := range keys {
+		if params.Tag != "" && params.Provider != "" && key.Provider != keystore.Provider(params.Provider) {
+			continue
+		}
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
+// handleFindDuplicateKeys reports groups of stored keys that share a token
+func (s *Server) handleFindDuplicateKeys(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	ks, errObj := s.openKeystore()
+	if errObj != nil {
+		return nil, errObj
+	}
+	defer ks.Close()
+
+	groups, err := ks.FindDuplicates()
+	if err != nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to find duplicate keys: " + err.Error()}
+	}
+
+	return map[string]interface{}{
+		"groups": groups,
+		"count":  len(groups),
+	}, nil
+}
+
+// handleRotateKey replaces a stored key's token, keeping the previous
+// version retrievable for the keystore's rotation grace period
+func (s *Server) handleRotateKey(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+	}
+
+	if params.ID == "" {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "id is required"}
+	}
+	if params.Token == "" {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "token is required"}
+	}
+
+	ks, errObj := s.openKeystore()
+	if errObj != nil {
+		return nil, errObj
+	}
+	defer ks.Close()
+
+	if err := ks.Rotate(params.ID, params.Token); err != nil {
+		if errors.Is(err, keystore.ErrKeyNotFound) {
+			return nil, &ErrorObj{Code: NotFoundError, Message: "key not found: " + params.ID}
+		}
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to rotate key: " + err.Error()}
+	}
+
+	if s.metrics != nil {
+		s.metrics.IncrementCounter("armorclaw_keystore_operations_total", "rotate")
+	}
+
+	versions, err := ks.ListKeyVersions(params.ID)
+	if err != nil || len(versions) < 2 {
+		return map[string]interface{}{"success": true, "id": params.ID}, nil
+	}
+
+	return map[string]interface{}{
+		"success":              true,
+		"id":                   params.ID,
+		"version":              versions[0].Version,
+		"rotated_at":           versions[0].IssuedAt,
+		"previous_version":     versions[1].Version,
+		"previous_valid_until": versions[1].ValidUntil,
+	}, nil
+}
+
+// handleListKeyVersions returns the rotation history of a stored key
+func (s *Server) handleListKeyVersions(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+	}
+
+	if params.ID == "" {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "id is required"}
+	}
+
+	ks, errObj := s.openKeystore()
+	if errObj != nil {
+		return nil, errObj
+	}
+	defer ks.Close()
+
+	versions, err := ks.ListKeyVersions(params.ID)
+	if err != nil {
+		if errors.Is(err, keystore.ErrKeyNotFound) {
+			return nil, &ErrorObj{Code: NotFoundError, Message: "key not found: " + params.ID}
+		}
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to list key versions: " + err.Error()}
+	}
+
+	return map[string]interface{}{
+		"id":              params.ID,
+		"current_version": versions[0].Version,
+		"versions":        versions,
 	}, nil
 }
 
@@ -889,7 +1249,9 @@ func (s *Server) registerHandlers() {
 		"bridge.unchannel":          s.handleUnbridgeChannel,
 		"bridge.list":               s.handleListBridgedChannels,
 		"bridge.ghost_list":         s.handleGhostUserList,
+		"bridge.sync_ghost_profiles": s.handleSyncGhostProfiles,
 		"bridge.appservice_status":  s.handleAppServiceStatus,
+		"bridge.capabilities":       s.handleBridgeCapabilities,
 		"pii.request":               s.handlePIIRequest,
 		"pii.approve":               s.handlePIIApprove,
 		"pii.deny":                  s.handlePIIDeny,
@@ -899,6 +1261,7 @@ func (s *Server) registerHandlers() {
 		"pii.cancel":                s.handlePIICancel,
 		"pii.fulfill":               s.handlePIIFulfill,
 		"pii.wait_for_approval":     s.handlePIIWaitForApproval,
+		"profile.get":               s.handleProfileGet,
 		"skills.execute":            s.handleSkillsExecute,
 		"skills.list":               s.handleSkillsList,
 		"skills.get_schema":         s.handleSkillsGetSchema,
@@ -923,16 +1286,41 @@ func (s *Server) registerHandlers() {
 		"studio.deploy":             s.handleStudio,
 		"studio.stats":              s.handleStudioStats,
 		"store_key":                 s.handleStoreKey,
+		"list_keys":                 s.handleListKeys,
+		"find_duplicate_keys":       s.handleFindDuplicateKeys,
+		"webrtc.start":              s.handleWebRTCStart,
+		"webrtc.end":                s.handleWebRTCEnd,
+		"webrtc.refresh_turn":       s.handleWebRTCRefreshTURN,
+		"budget.status":             s.handleBudgetStatus,
+		"budget.breakdown":          s.handleBudgetBreakdown,
+		"rotate_key":                s.handleRotateKey,
+		"list_key_versions":         s.handleListKeyVersions,
 		"provisioning.start":        s.handleProvisioningStart,
 		"provisioning.claim":        s.handleProvisioningClaim,
 		"hardening.status":          s.handleHardeningStatus,
 		"hardening.ack":             s.handleHardeningAck,
 		"hardening.rotate_password": s.handleHardeningRotatePassword,
 		"health.check":              s.handleHealthCheck,
+		"log.set_level":             s.handleLogSetLevel,
 		"mobile.heartbeat":          s.handleMobileHeartbeat,
 		"container.terminate":       s.handleTerminateContainer,
 		"container.list":            s.handleListContainers,
+		"container.logs":            s.handleContainerLogs,
+		"container.exec":            s.handleContainerExec,
+		"container.health_history":  s.handleContainerHealthHistory,
+		"plugin.reload":             s.handlePluginReload,
+		"recovery.cancel":           s.handleRecoveryCancel,
+		"push.register_token":       s.handlePushRegisterToken,
+		"push.unregister_token":     s.handlePushUnregisterToken,
+		"trust.add_sender":          s.handleTrustAddSender,
+		"trust.remove_sender":       s.handleTrustRemoveSender,
+		"trust.list":                s.handleTrustList,
 		"resolve_blocker":           s.handleResolveBlocker,
+		"resolve_error":             s.handleResolveError,
+		"get_error_stats":           s.handleGetErrorStats,
+		"export_errors":             s.handleExportErrors,
+		"audit.export":              s.handleAuditExport,
+		"metrics":                   s.handleMethodMetrics,
 		"approve_email":             s.handleApproveEmail,
 		"deny_email":                s.handleDenyEmail,
 		"email_approval_status":     s.handleEmailApprovalStatus,
@@ -1113,6 +1501,14 @@ func (s *Server) Run(socketPath string) error {
 		close(shutdown)
 	}()
 
+	if s.licenseClient != nil {
+		go s.runLicenseExpiryChecker(shutdown)
+	}
+
+	if s.connSem == nil {
+		s.connSem = make(chan struct{}, DefaultMaxConnections)
+	}
+
 	for {
 		select {
 		case <-shutdown:
@@ -1131,11 +1527,44 @@ func (s *Server) Run(socketPath string) error {
 				}
 			}
 
-			go s.handleConnection(conn)
+			s.dispatchConnection(conn)
 		}
 	}
 }
 
+// dispatchConnection hands conn to a handler goroutine, or rejects it when
+// MaxConnections handlers are already running
+func (s *Server) dispatchConnection(conn net.Conn) {
+	select {
+	case s.connSem <- struct{}{}:
+		go func() {
+			defer func() { <-s.connSem }()
+			s.handleConnection(conn)
+		}()
+	default:
+		s.rejectConnection(conn)
+	}
+}
+
+// rejectConnection answers an over-limit connection with an error and closes it.
+// Warnings are throttled so a flood of connections doesn't flood the log.
+func (s *Server) rejectConnection(conn net.Conn) {
+	defer conn.Close()
+
+	s.connRejected++
+	if now := time.Now(); now.Sub(s.connLimitLogged) >= connLimitLogInterval {
+		slog.Warn("rpc_connection_limit_reached",
+			"max_connections", cap(s.connSem),
+			"rejected", s.connRejected,
+		)
+		s.connLimitLogged = now
+		s.connRejected = 0
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(time.Second))
+	json.NewEncoder(conn).Encode(errorResponse(nil, TooManyRequests, "too many connections"))
+}
+
 func (s *Server) handleConnection(conn net.Conn) {
 	if s.guard != nil {
 		if err := s.guard.Check(conn.RemoteAddr()); err != nil {
@@ -1147,6 +1576,16 @@ func (s *Server) handleConnection(conn net.Conn) {
 
 	defer conn.Close()
 
+	// Bound how long a client may take to send its request so stalled or
+	// malicious connections can't pin goroutines indefinitely
+	timeout := s.requestTimeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		slog.Warn("rpc_set_deadline_error", "error", err)
+	}
+
 	br := bufio.NewReader(conn)
 
 	// Intercept HTTP requests before JSON-RPC decode
@@ -1201,16 +1640,43 @@ func (s *Server) handleConnection(conn net.Conn) {
 		}
 	}
 
-	// Read JSON-RPC request
-	var req Request
+	// Read JSON-RPC request (single object or batch array)
+	var raw json.RawMessage
 	decoder := json.NewDecoder(br)
-	if err := decoder.Decode(&req); err != nil {
+	if err := decoder.Decode(&raw); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			slog.Warn("rpc_read_timeout", "remote", conn.RemoteAddr(), "timeout", timeout)
+			return
+		}
 		slog.Warn("rpc_decode_error", "error", err)
 		return
 	}
 
+	// The request is in; handlers may legitimately run longer than the read timeout
+	conn.SetReadDeadline(time.Time{})
+
 	// Handle request
-	resp := s.Handle(context.Background(), &req)
+	var resp interface{}
+	if IsBatch(raw) {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			slog.Warn("rpc_decode_error", "error", err)
+			return
+		}
+		resp = s.HandleBatch(context.Background(), batch)
+		if resp == nil {
+			// Batch of notifications only: nothing to send back
+			return
+		}
+	} else {
+		var req Request
+		if err := json.Unmarshal(raw, &req); err != nil {
+			slog.Warn("rpc_decode_error", "error", err)
+			return
+		}
+		resp = s.Handle(context.Background(), &req)
+	}
 
 	// Write response
 	encoder := json.NewEncoder(conn)
@@ -1219,3 +1685,9 @@ func (s *Server) handleConnection(conn net.Conn) {
 		slog.Warn("rpc_write_error", "error", err)
 	}
 }
+
+// IsBatch reports whether a raw JSON-RPC payload is a batch array
+func IsBatch(raw json.RawMessage) bool {
+	trimmed := bytes.TrimLeft(raw, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
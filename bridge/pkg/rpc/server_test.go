@@ -3,8 +3,14 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/armorclaw/bridge/pkg/eventbus"
+	"github.com/armorclaw/bridge/pkg/keystore"
 	"github.com/armorclaw/bridge/pkg/secretary"
 )
 
@@ -84,6 +90,43 @@ func TestMatrixJoinRoomMissingRoomID(t *testing.T) {
 	}
 }
 
+func TestMatrixSendRejectsUnsupportedFormat(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	handler := server.handlers["matrix.send"]
+	if handler == nil {
+		t.Fatalf("matrix.send handler not registered")
+	}
+
+	for _, params := range []string{
+		`{"room_id": "!r:example.com", "message": "hi", "formatted_body": "hi", "format": "text/markdown"}`,
+		`{"room_id": "!r:example.com", "message": "hi", "format": "org.matrix.custom.html"}`,
+	} {
+		_, errObj := handler(context.Background(), &Request{Params: json.RawMessage(params)})
+		if errObj == nil || errObj.Code != InvalidParams {
+			t.Errorf("params %s: expected InvalidParams, got %+v", params, errObj)
+		}
+	}
+}
+
+func TestMatrixReceiveValidatesParams(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	handler := server.handlers["matrix.receive"]
+	for _, params := range []string{
+		`{"limit": 500}`,
+		`{"limit": -1}`,
+		`{"cursor": "abc"}`,
+	} {
+		_, errObj := handler(context.Background(), &Request{Params: json.RawMessage(params)})
+		if errObj == nil || errObj.Code != InvalidParams {
+			t.Errorf("params %s: expected InvalidParams, got %+v", params, errObj)
+		}
+	}
+}
+
 func TestHealthCheckHandler(t *testing.T) {
 	server := &Server{}
 	server.registerHandlers()
@@ -145,6 +188,36 @@ func TestHealthCheckHandler(t *testing.T) {
 	}
 }
 
+func TestHealthCheckEventBusMetrics(t *testing.T) {
+	bus := eventbus.NewEventBus(eventbus.Config{MaxSubscribers: 25})
+	defer bus.Stop()
+	if _, err := bus.Subscribe(eventbus.EventFilter{}); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	server := &Server{}
+	result, errObj := server.handleHealthCheck(context.Background(), &Request{Params: json.RawMessage(`{}`)})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj)
+	}
+	if resp := result.(HealthCheckResponse); resp.EventBus != nil {
+		t.Errorf("expected no eventbus metrics without an event bus, got %+v", resp.EventBus)
+	}
+
+	server.eventBus = bus
+	result, errObj = server.handleHealthCheck(context.Background(), &Request{Params: json.RawMessage(`{}`)})
+	if errObj != nil {
+		t.Fatalf("unexpected error: %v", errObj)
+	}
+	resp := result.(HealthCheckResponse)
+	if resp.EventBus == nil || resp.EventBus.Subscribers != 1 || resp.EventBus.MaxSubscribers != 25 {
+		t.Errorf("expected eventbus metrics with 1 subscriber, got %+v", resp.EventBus)
+	}
+	if resp.Components["eventbus"] != "ok" {
+		t.Errorf("expected eventbus component, got %v", resp.Components)
+	}
+}
+
 func TestResolveBlocker_DeliversResponse(t *testing.T) {
 	server := &Server{}
 	server.registerHandlers()
@@ -249,3 +322,421 @@ func TestResolveBlocker_MissingFields(t *testing.T) {
 		})
 	}
 }
+
+func newBatchTestServer() *Server {
+	return &Server{
+		handlers: map[string]HandlerFunc{
+			"echo": func(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+				return string(req.Params), nil
+			},
+		},
+	}
+}
+
+func TestHandleBatch_PreservesOrderAndSkipsNotifications(t *testing.T) {
+	server := newBatchTestServer()
+
+	batch := []json.RawMessage{
+		json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"echo","params":"a"}`),
+		json.RawMessage(`{"jsonrpc":"2.0","method":"echo","params":"notify"}`),
+		json.RawMessage(`{"jsonrpc":"2.0","id":2,"method":"missing"}`),
+		json.RawMessage(`42`),
+		json.RawMessage(`{"jsonrpc":"2.0","id":3,"method":"echo","params":"c"}`),
+	}
+
+	result := server.HandleBatch(context.Background(), batch)
+	responses, ok := result.([]*Response)
+	if !ok {
+		t.Fatalf("expected []*Response, got %T", result)
+	}
+	if len(responses) != 4 {
+		t.Fatalf("expected 4 responses (notification omitted), got %d", len(responses))
+	}
+
+	if responses[0].ID != float64(1) || responses[0].Result != `"a"` {
+		t.Errorf("response 0 = %+v, want id 1 echoing \"a\"", responses[0])
+	}
+	if responses[1].ID != float64(2) || responses[1].Error == nil || responses[1].Error.Code != MethodNotFound {
+		t.Errorf("response 1 = %+v, want id 2 method not found", responses[1])
+	}
+	if responses[2].ID != nil || responses[2].Error == nil || responses[2].Error.Code != InvalidRequest {
+		t.Errorf("response 2 = %+v, want invalid request with null id", responses[2])
+	}
+	if responses[3].ID != float64(3) || responses[3].Result != `"c"` {
+		t.Errorf("response 3 = %+v, want id 3 echoing \"c\"", responses[3])
+	}
+}
+
+func TestHandleBatch_Empty(t *testing.T) {
+	server := newBatchTestServer()
+
+	resp, ok := server.HandleBatch(context.Background(), nil).(*Response)
+	if !ok {
+		t.Fatal("expected a single error response for an empty batch")
+	}
+	if resp.Error == nil || resp.Error.Code != InvalidRequest {
+		t.Errorf("expected InvalidRequest, got %+v", resp.Error)
+	}
+}
+
+func TestHandleBatch_AllNotifications(t *testing.T) {
+	server := newBatchTestServer()
+
+	batch := []json.RawMessage{
+		json.RawMessage(`{"jsonrpc":"2.0","method":"echo"}`),
+		json.RawMessage(`{"jsonrpc":"2.0","method":"echo"}`),
+	}
+	if result := server.HandleBatch(context.Background(), batch); result != nil {
+		t.Errorf("expected no response for an all-notification batch, got %v", result)
+	}
+}
+
+func TestIsBatch(t *testing.T) {
+	cases := map[string]bool{
+		`[{"jsonrpc":"2.0"}]`: true,
+		"  \n[]":              true,
+		`{"jsonrpc":"2.0"}`:   false,
+		``:                    false,
+	}
+	for in, want := range cases {
+		if got := IsBatch(json.RawMessage(in)); got != want {
+			t.Errorf("IsBatch(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestHandle_IDValidation(t *testing.T) {
+	server := newBatchTestServer()
+
+	tests := []struct {
+		name         string
+		payload      string
+		wantResponse bool
+		wantCode     int
+	}{
+		{"string id", `{"jsonrpc":"2.0","id":"abc","method":"echo"}`, true, 0},
+		{"number id", `{"jsonrpc":"2.0","id":7,"method":"echo"}`, true, 0},
+		{"explicit null id is a request", `{"jsonrpc":"2.0","id":null,"method":"echo"}`, true, 0},
+		{"absent id is a notification", `{"jsonrpc":"2.0","method":"echo"}`, false, 0},
+		{"object id", `{"jsonrpc":"2.0","id":{"a":1},"method":"echo"}`, true, InvalidRequest},
+		{"array id", `{"jsonrpc":"2.0","id":[1],"method":"echo"}`, true, InvalidRequest},
+		{"bool id", `{"jsonrpc":"2.0","id":true,"method":"echo"}`, true, InvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req Request
+			if err := json.Unmarshal([]byte(tt.payload), &req); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+
+			resp := server.Handle(context.Background(), &req)
+			if !tt.wantResponse {
+				if resp != nil {
+					t.Errorf("expected no response, got %+v", resp)
+				}
+				return
+			}
+			if resp == nil {
+				t.Fatal("expected a response, got nil")
+			}
+
+			if tt.wantCode != 0 {
+				if resp.Error == nil || resp.Error.Code != tt.wantCode {
+					t.Errorf("expected error code %d, got %+v", tt.wantCode, resp.Error)
+				}
+				if resp.ID != nil {
+					t.Errorf("invalid id should be answered with null id, got %v", resp.ID)
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Errorf("unexpected error: %+v", resp.Error)
+			}
+		})
+	}
+}
+
+func TestResponse_NullIDSerialized(t *testing.T) {
+	data, err := json.Marshal(errorResponse(nil, InvalidRequest, "bad"))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"id":null`) {
+		t.Errorf("expected explicit null id in %s", data)
+	}
+}
+
+func TestHandleConnection_ReadTimeout(t *testing.T) {
+	server := &Server{requestTimeout: 50 * time.Millisecond}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	done := make(chan struct{})
+	go func() {
+		server.handleConnection(serverConn)
+		close(done)
+	}()
+
+	// Client never sends anything
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handleConnection did not return after the read timeout")
+	}
+
+	clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := clientConn.Read(make([]byte, 1)); err == nil {
+		t.Error("expected the connection to be closed after timeout")
+	}
+}
+
+func TestHandleConnection_SlowHandlerNotCutOff(t *testing.T) {
+	server := &Server{
+		requestTimeout: 50 * time.Millisecond,
+		handlers: map[string]HandlerFunc{
+			"slow": func(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+				time.Sleep(150 * time.Millisecond)
+				return "done", nil
+			},
+		},
+	}
+
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+	go server.handleConnection(serverConn)
+
+	clientConn.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := clientConn.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"slow"}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(clientConn).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Result != "done" {
+		t.Errorf("expected result done, got %+v", resp)
+	}
+}
+
+func TestDispatchConnection_Limit(t *testing.T) {
+	server := &Server{
+		requestTimeout: 5 * time.Second,
+		connSem:        make(chan struct{}, 1),
+		handlers: map[string]HandlerFunc{
+			"echo": func(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+				return "ok", nil
+			},
+		},
+	}
+
+	// First connection occupies the only slot while it waits for a request
+	server1, client1 := net.Pipe()
+	server.dispatchConnection(server1)
+
+	// Second connection is rejected with an error response
+	server2, client2 := net.Pipe()
+	defer client2.Close()
+	go server.dispatchConnection(server2)
+
+	client2.SetDeadline(time.Now().Add(2 * time.Second))
+	var resp Response
+	if err := json.NewDecoder(client2).Decode(&resp); err != nil {
+		t.Fatalf("decode rejection: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != TooManyRequests {
+		t.Errorf("expected TooManyRequests, got %+v", resp)
+	}
+
+	// Closing the first client frees the slot
+	client1.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(server.connSem) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("connection slot was not released")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	server3, client3 := net.Pipe()
+	defer client3.Close()
+	server.dispatchConnection(server3)
+
+	client3.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := client3.Write([]byte(`{"jsonrpc":"2.0","id":1,"method":"echo"}`)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	resp = Response{}
+	if err := json.NewDecoder(client3).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Result != "ok" {
+		t.Errorf("expected ok after slot freed, got %+v", resp)
+	}
+}
+
+func TestKeyRotationHandlers_Validation(t *testing.T) {
+	server := &Server{}
+
+	tests := []struct {
+		name    string
+		handler HandlerFunc
+		params  string
+		code    int
+	}{
+		{"rotate missing id", server.handleRotateKey, `{"token":"sk-new"}`, InvalidParams},
+		{"rotate missing token", server.handleRotateKey, `{"id":"openai-main"}`, InvalidParams},
+		{"rotate no keystore", server.handleRotateKey, `{"id":"openai-main","token":"sk-new"}`, InternalError},
+		{"versions missing id", server.handleListKeyVersions, `{}`, InvalidParams},
+		{"versions no keystore", server.handleListKeyVersions, `{"id":"openai-main"}`, InternalError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errObj := tt.handler(context.Background(), &Request{Params: json.RawMessage(tt.params)})
+			if errObj == nil || errObj.Code != tt.code {
+				t.Errorf("expected error code %d, got %+v", tt.code, errObj)
+			}
+		})
+	}
+}
+
+func TestKeyRotationHandlers(t *testing.T) {
+	ks, err := keystore.New(keystore.Config{
+		DBPath:    filepath.Join(t.TempDir(), "keystore.db"),
+		MasterKey: make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatalf("failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("failed to open keystore: %v", err)
+	}
+	err = ks.Store(keystore.Credential{
+		ID:        "openai-main",
+		Provider:  keystore.ProviderOpenAI,
+		Token:     "sk-original",
+		CreatedAt: time.Now().Unix(),
+	})
+	ks.Close()
+	if err != nil {
+		t.Fatalf("failed to store key: %v", err)
+	}
+
+	server := &Server{keystore: ks}
+	ctx := context.Background()
+
+	result, errObj := server.handleRotateKey(ctx, &Request{Params: json.RawMessage(`{"id":"openai-main","token":"sk-rotated"}`)})
+	if errObj != nil {
+		t.Fatalf("rotate_key failed: %+v", errObj)
+	}
+	rotated := result.(map[string]interface{})
+	if rotated["version"] != 2 || rotated["previous_version"] != 1 {
+		t.Errorf("unexpected rotate result: %v", rotated)
+	}
+
+	result, errObj = server.handleListKeyVersions(ctx, &Request{Params: json.RawMessage(`{"id":"openai-main"}`)})
+	if errObj != nil {
+		t.Fatalf("list_key_versions failed: %+v", errObj)
+	}
+	versions := result.(map[string]interface{})["versions"].([]keystore.KeyVersion)
+	if len(versions) != 2 || !versions[0].Current || !versions[1].Retrievable {
+		t.Errorf("unexpected versions: %+v", versions)
+	}
+
+	_, errObj = server.handleListKeyVersions(ctx, &Request{Params: json.RawMessage(`{"id":"missing"}`)})
+	if errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("expected NotFoundError for missing key, got %+v", errObj)
+	}
+}
+
+func TestListKeysHandler(t *testing.T) {
+	ks, err := keystore.New(keystore.Config{
+		DBPath:    filepath.Join(t.TempDir(), "keystore.db"),
+		MasterKey: make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatalf("failed to create keystore: %v", err)
+	}
+	if err := ks.Open(); err != nil {
+		t.Fatalf("failed to open keystore: %v", err)
+	}
+	for _, cred := range []keystore.Credential{
+		{ID: "prod-openai", Provider: keystore.ProviderOpenAI, Token: "sk-1", CreatedAt: time.Now().Unix(), Tags: []string{"production"}},
+		{ID: "prod-anthropic", Provider: keystore.ProviderAnthropic, Token: "sk-ant-2", CreatedAt: time.Now().Unix(), Tags: []string{"production"}},
+		{ID: "dev-openai", Provider: keystore.ProviderOpenAI, Token: "sk-3", CreatedAt: time.Now().Unix(), Tags: []string{"experimental"}},
+	} {
+		if err := ks.Store(cred); err != nil {
+			t.Fatalf("failed to store key: %v", err)
+		}
+	}
+	ks.Close()
+
+	server := &Server{keystore: ks}
+
+	tests := []struct {
+		name   string
+		params string
+		want   int
+	}{
+		{"all", ``, 3},
+		{"by provider", `{"provider":"openai"}`, 2},
+		{"by tag", `{"tag":"production"}`, 2},
+		{"by tag and provider", `{"tag":"production","provider":"openai"}`, 1},
+		{"unknown tag", `{"tag":"staging"}`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, errObj := server.handleListKeys(context.Background(), &Request{Params: json.RawMessage(tt.params)})
+			if errObj != nil {
+				t.Fatalf("list_keys failed: %+v", errObj)
+			}
+			keys := result.([]keystore.KeyInfo)
+			if len(keys) != tt.want {
+				t.Errorf("expected %d keys, got %d", tt.want, len(keys))
+			}
+		})
+	}
+}
+
+func TestDuplicateKeyHandlers(t *testing.T) {
+	ks, err := keystore.New(keystore.Config{
+		DBPath:    filepath.Join(t.TempDir(), "keystore.db"),
+		MasterKey: make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatalf("failed to create keystore: %v", err)
+	}
+
+	server := &Server{keystore: ks}
+	ctx := context.Background()
+
+	result, errObj := server.handleStoreKey(ctx, &Request{Params: json.RawMessage(`{"id":"openai-a","provider":"openai","token":"sk-shared"}`)})
+	if errObj != nil {
+		t.Fatalf("store_key failed: %+v", errObj)
+	}
+	if _, ok := result.(map[string]interface{})["duplicate_of"]; ok {
+		t.Errorf("unexpected duplicate warning on first store: %v", result)
+	}
+
+	result, errObj = server.handleStoreKey(ctx, &Request{Params: json.RawMessage(`{"id":"openai-b","provider":"openai","token":"sk-shared"}`)})
+	if errObj != nil {
+		t.Fatalf("duplicate store_key should succeed: %+v", errObj)
+	}
+	dups, _ := result.(map[string]interface{})["duplicate_of"].([]string)
+	if len(dups) != 1 || dups[0] != "openai-a" {
+		t.Errorf("expected duplicate_of [openai-a], got %v", result)
+	}
+
+	result, errObj = server.handleFindDuplicateKeys(ctx, &Request{})
+	if errObj != nil {
+		t.Fatalf("find_duplicate_keys failed: %+v", errObj)
+	}
+	groups := result.(map[string]interface{})["groups"].([]keystore.DuplicateGroup)
+	if len(groups) != 1 || len(groups[0].IDs) != 2 {
+		t.Errorf("unexpected duplicate groups: %+v", groups)
+	}
+}
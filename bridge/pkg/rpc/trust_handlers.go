@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/armorclaw/bridge/pkg/trust"
+)
+
+// trustSenderParams are the parameters for trust.add_sender and
+// trust.remove_sender
+type trustSenderParams struct {
+	Sender    string `json:"sender"`
+	ChangedBy string `json:"changed_by,omitempty"`
+}
+
+// parseTrustSenderParams decodes and checks the sender parameters
+func (s *Server) parseTrustSenderParams(req *Request) (*trustSenderParams, *ErrorObj) {
+	var params trustSenderParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "invalid parameters: " + err.Error(),
+		}
+	}
+
+	if params.Sender == "" {
+		return nil, &ErrorObj{
+			Code:    InvalidParams,
+			Message: "sender is required",
+		}
+	}
+
+	if s.senderAllowlist == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "sender allowlist not configured",
+		}
+	}
+
+	return &params, nil
+}
+
+// handleTrustAddSender adds a sender pattern to the zero-trust allowlist
+// without a restart. The change is persisted and security logged.
+func (s *Server) handleTrustAddSender(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	params, errObj := s.parseTrustSenderParams(req)
+	if errObj != nil {
+		return nil, errObj
+	}
+
+	added, err := s.senderAllowlist.Add(params.Sender, params.ChangedBy)
+	if err != nil {
+		if errors.Is(err, trust.ErrInvalidSenderPattern) {
+			return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+		}
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to add sender: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"sender":  params.Sender,
+		"changed": added,
+		"senders": s.senderAllowlist.Senders(),
+	}, nil
+}
+
+// handleTrustRemoveSender removes a sender pattern from the zero-trust
+// allowlist. The last pattern cannot be removed, since an empty allowlist
+// trusts every sender.
+func (s *Server) handleTrustRemoveSender(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	params, errObj := s.parseTrustSenderParams(req)
+	if errObj != nil {
+		return nil, errObj
+	}
+
+	removed, err := s.senderAllowlist.Remove(params.Sender, params.ChangedBy)
+	if err != nil {
+		if errors.Is(err, trust.ErrLastTrustedSender) {
+			return nil, &ErrorObj{Code: InvalidRequest, Message: err.Error()}
+		}
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "failed to remove sender: " + err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success": true,
+		"sender":  params.Sender,
+		"changed": removed,
+		"senders": s.senderAllowlist.Senders(),
+	}, nil
+}
+
+// handleTrustList returns the sender and room allowlists in effect. An empty
+// list means that kind of filtering is off.
+func (s *Server) handleTrustList(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	if s.senderAllowlist == nil {
+		return nil, &ErrorObj{
+			Code:    InternalError,
+			Message: "sender allowlist not configured",
+		}
+	}
+
+	senders := s.senderAllowlist.Senders()
+	rooms := s.senderAllowlist.Rooms()
+	return map[string]interface{}{
+		"senders":          senders,
+		"rooms":            rooms,
+		"sender_filtering": len(senders) > 0,
+		"room_filtering":   len(rooms) > 0,
+	}, nil
+}
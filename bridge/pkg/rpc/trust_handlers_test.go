@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/armorclaw/bridge/pkg/trust"
+)
+
+// stubAllowlistTarget stands in for the Matrix adapter
+type stubAllowlistTarget struct {
+	senders []string
+	rooms   []string
+}
+
+func (s *stubAllowlistTarget) GetTrustedSenders() []string { return append([]string{}, s.senders...) }
+func (s *stubAllowlistTarget) SetTrustedSenders(senders []string) {
+	s.senders = senders
+}
+func (s *stubAllowlistTarget) GetTrustedRooms() []string { return append([]string{}, s.rooms...) }
+
+func newTrustServer(t *testing.T, senders ...string) (*Server, *stubAllowlistTarget) {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	target := &stubAllowlistTarget{senders: senders, rooms: []string{"!ops:example.com"}}
+	allowlist, err := trust.NewSenderAllowlist(db, target)
+	if err != nil {
+		t.Fatalf("new sender allowlist: %v", err)
+	}
+	return &Server{senderAllowlist: allowlist}, target
+}
+
+func senderRequest(sender string) *Request {
+	params, _ := json.Marshal(map[string]string{"sender": sender, "changed_by": "@admin:example.com"})
+	return &Request{Params: params}
+}
+
+func TestTrustHandlersRegistered(t *testing.T) {
+	server := &Server{}
+	server.registerHandlers()
+
+	for _, m := range []string{"trust.add_sender", "trust.remove_sender", "trust.list"} {
+		if _, ok := server.handlers[m]; !ok {
+			t.Errorf("%s not registered", m)
+		}
+	}
+}
+
+func TestTrustHandlersNotConfigured(t *testing.T) {
+	server := &Server{}
+	ctx := context.Background()
+
+	if _, errObj := server.handleTrustAddSender(ctx, senderRequest("@alice:example.com")); errObj == nil || errObj.Code != InternalError {
+		t.Errorf("add_sender: expected InternalError, got %+v", errObj)
+	}
+	if _, errObj := server.handleTrustList(ctx, &Request{}); errObj == nil || errObj.Code != InternalError {
+		t.Errorf("list: expected InternalError, got %+v", errObj)
+	}
+}
+
+func TestTrustAddAndRemoveSender(t *testing.T) {
+	server, target := newTrustServer(t, "@admin:example.com")
+	ctx := context.Background()
+
+	result, errObj := server.handleTrustAddSender(ctx, senderRequest("@alice:example.com"))
+	if errObj != nil {
+		t.Fatalf("add_sender: unexpected error %+v", errObj)
+	}
+	if result.(map[string]interface{})["changed"] != true || len(target.senders) != 2 {
+		t.Errorf("add_sender result = %+v, senders = %v", result, target.senders)
+	}
+
+	if _, errObj := server.handleTrustAddSender(ctx, senderRequest("alice")); errObj == nil || errObj.Code != InvalidParams {
+		t.Errorf("add_sender with invalid pattern: expected InvalidParams, got %+v", errObj)
+	}
+	if _, errObj := server.handleTrustAddSender(ctx, senderRequest("")); errObj == nil || errObj.Code != InvalidParams {
+		t.Errorf("add_sender without sender: expected InvalidParams, got %+v", errObj)
+	}
+
+	if _, errObj := server.handleTrustRemoveSender(ctx, senderRequest("@admin:example.com")); errObj != nil {
+		t.Fatalf("remove_sender: unexpected error %+v", errObj)
+	}
+	if _, errObj := server.handleTrustRemoveSender(ctx, senderRequest("@alice:example.com")); errObj == nil || errObj.Code != InvalidRequest {
+		t.Errorf("remove_sender of the last sender: expected InvalidRequest, got %+v", errObj)
+	}
+
+	result, errObj = server.handleTrustList(ctx, &Request{})
+	if errObj != nil {
+		t.Fatalf("list: unexpected error %+v", errObj)
+	}
+	list := result.(map[string]interface{})
+	senders := list["senders"].([]string)
+	if len(senders) != 1 || senders[0] != "@alice:example.com" || list["sender_filtering"] != true || list["room_filtering"] != true {
+		t.Errorf("list = %+v", list)
+	}
+}
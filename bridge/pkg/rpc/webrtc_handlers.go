@@ -0,0 +1,259 @@
+// Package rpc provides WebRTC voice session RPC methods.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/webrtc"
+	pion "github.com/pion/webrtc/v3"
+)
+
+const (
+	// webrtcICEGatherTimeout bounds how long webrtc.start waits for ICE
+	// gathering before returning the answer with the candidates found so far
+	webrtcICEGatherTimeout = 5 * time.Second
+
+	// maxSDPOfferSize rejects offers far larger than any real audio session
+	maxSDPOfferSize = 64 * 1024
+)
+
+// WebRTCStartParams are parameters for webrtc.start
+type WebRTCStartParams struct {
+	RoomID         string `json:"room_id"`          // Matrix room for authorization
+	SDPOffer       string `json:"sdp_offer"`        // Client's SDP offer
+	TTL            string `json:"ttl"`              // Optional TTL duration (e.g., "10m", "1h")
+	Codec          string `json:"codec"`            // Optional audio codec: "opus" (default) or "pcmu"
+	MaxBitrateKbps int    `json:"max_bitrate_kbps"` // Optional Opus bitrate cap; 0 means no cap
+}
+
+// WebRTCStartResult is the result of webrtc.start
+type WebRTCStartResult struct {
+	SessionID string `json:"session_id"`
+	SDPAnswer string `json:"sdp_answer"`
+	ExpiresAt int64  `json:"expires_at"`
+	TTL       string `json:"ttl"`             // Effective TTL after clamping to the server maximum
+	Token     string `json:"token,omitempty"` // Call session token for signaling
+}
+
+// WebRTCICEServer is an ICE server entry in the shape of RTCIceServer, so
+// clients can pass it to setConfiguration unchanged
+type WebRTCICEServer struct {
+	URLs       []string `json:"urls"`
+	Username   string   `json:"username"`
+	Credential string   `json:"credential"`
+}
+
+// WebRTCRefreshTURNResult is the result of webrtc.refresh_turn
+type WebRTCRefreshTURNResult struct {
+	SessionID  string            `json:"session_id"`
+	ICEServers []WebRTCICEServer `json:"ice_servers"`
+	ExpiresAt  int64             `json:"expires_at"`
+}
+
+// handleWebRTCStart creates a voice session, applies the client's SDP offer to
+// a new peer connection and returns the bridge's SDP answer. ICE candidates
+// are gathered before answering, so the answer is complete without trickle.
+func (s *Server) handleWebRTCStart(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params WebRTCStartParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+	}
+
+	if params.RoomID == "" {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "room_id is required"}
+	}
+	if strings.TrimSpace(params.SDPOffer) == "" {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "sdp_offer is required"}
+	}
+	if len(params.SDPOffer) > maxSDPOfferSize {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "sdp_offer is too large"}
+	}
+
+	var ttl time.Duration
+	if params.TTL != "" {
+		parsed, err := time.ParseDuration(params.TTL)
+		if err != nil || parsed <= 0 {
+			return nil, &ErrorObj{Code: InvalidParams, Message: "invalid ttl: must be a positive duration such as 30m"}
+		}
+		ttl = parsed
+	}
+
+	mediaOpts := webrtc.PeerConnectionOptions{
+		Codec:          strings.ToLower(params.Codec),
+		MaxBitrateKbps: params.MaxBitrateKbps,
+	}
+	if err := mediaOpts.Validate(); err != nil {
+		return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+	}
+
+	if s.webrtcEngine == nil || s.webrtcSessions == nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "webrtc not configured"}
+	}
+
+	// A zero TTL uses the session manager's default; longer requests are
+	// clamped to its maximum
+	ttl = s.webrtcSessions.EffectiveTTL(ttl)
+	session, err := s.webrtcSessions.Create("", params.RoomID, ttl)
+	if err != nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to create session: " + err.Error()}
+	}
+
+	pc, err := s.webrtcEngine.CreatePeerConnectionWithOptions(session.ID, mediaOpts)
+	if err != nil {
+		s.webrtcSessions.Fail(session.ID, "peer_connection_failed")
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to create peer connection: " + err.Error()}
+	}
+
+	// Mirror the peer connection into the session state so transitions
+	// reach event bus subscribers
+	sessionID := session.ID
+	pc.OnConnectionStateChange(func(state pion.PeerConnectionState) {
+		switch state {
+		case pion.PeerConnectionStateConnected:
+			s.webrtcSessions.UpdateState(sessionID, webrtc.SessionActive)
+		case pion.PeerConnectionStateFailed:
+			if s.webrtcSessions.Fail(sessionID, "peer_connection_failed") == nil {
+				go s.webrtcEngine.ClosePeerConnection(sessionID)
+			}
+		}
+	})
+
+	answer, err := pc.AnswerOffer(params.SDPOffer, webrtcICEGatherTimeout)
+	if err != nil {
+		s.webrtcEngine.ClosePeerConnection(session.ID)
+		s.webrtcSessions.Fail(session.ID, "invalid_offer")
+		return nil, &ErrorObj{Code: InvalidParams, Message: "invalid sdp_offer: " + err.Error()}
+	}
+
+	session.PeerConnectionID = session.ID
+	session.SDPOffer = params.SDPOffer
+	session.SDPAnswer = answer
+	session.RemoteSDP = params.SDPOffer
+
+	result := WebRTCStartResult{
+		SessionID: session.ID,
+		SDPAnswer: answer,
+		ExpiresAt: session.ExpiresAt.Unix(),
+		TTL:       ttl.String(),
+	}
+
+	if s.webrtcTokens != nil {
+		token, err := s.webrtcTokens.Generate(session.ID, params.RoomID)
+		if err == nil {
+			result.Token, err = token.ToJSON()
+		}
+		if err != nil {
+			s.webrtcEngine.ClosePeerConnection(session.ID)
+			s.webrtcSessions.Fail(session.ID, "token_failed")
+			return nil, &ErrorObj{Code: InternalError, Message: "failed to generate session token: " + err.Error()}
+		}
+	}
+
+	slog.Info("webrtc_session_started", "session_id", session.ID, "room_id", params.RoomID, "ttl", ttl.String())
+
+	return result, nil
+}
+
+// handleWebRTCEnd closes a voice session's peer connection and ends the session
+func (s *Server) handleWebRTCEnd(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		SessionID string `json:"session_id"`
+		Reason    string `json:"reason,omitempty"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+	}
+
+	if params.SessionID == "" {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "session_id is required"}
+	}
+
+	if s.webrtcEngine == nil || s.webrtcSessions == nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "webrtc not configured"}
+	}
+
+	session, ok := s.webrtcSessions.Get(params.SessionID)
+	if !ok {
+		return nil, &ErrorObj{Code: NotFoundError, Message: "session not found: " + params.SessionID}
+	}
+	duration := time.Since(session.CreatedAt).Round(time.Second)
+
+	// The peer connection may already be gone if negotiation failed
+	s.webrtcEngine.ClosePeerConnection(params.SessionID)
+
+	if err := s.webrtcSessions.End(params.SessionID); err != nil {
+		if err == webrtc.ErrSessionNotFound {
+			return nil, &ErrorObj{Code: NotFoundError, Message: "session not found: " + params.SessionID}
+		}
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to end session: " + err.Error()}
+	}
+
+	slog.Info("webrtc_session_ended", "session_id", params.SessionID, "reason", params.Reason, "duration", duration.String())
+
+	return map[string]interface{}{
+		"session_id": params.SessionID,
+		"status":     "terminated",
+		"duration":   duration.String(),
+	}, nil
+}
+
+// handleWebRTCRefreshTURN issues fresh TURN credentials for a live session so
+// the client can renominate ICE before its current credentials expire
+func (s *Server) handleWebRTCRefreshTURN(ctx context.Context, req *Request) (interface{}, *ErrorObj) {
+	var params struct {
+		SessionID string `json:"session_id"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &ErrorObj{Code: InvalidParams, Message: err.Error()}
+	}
+
+	if params.SessionID == "" {
+		return nil, &ErrorObj{Code: InvalidParams, Message: "session_id is required"}
+	}
+
+	if s.webrtcSessions == nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "webrtc not configured"}
+	}
+	if s.turnManager == nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "turn not configured"}
+	}
+
+	// Ended, failed and expired sessions are removed from the manager
+	session, ok := s.webrtcSessions.Get(params.SessionID)
+	if !ok {
+		return nil, &ErrorObj{Code: NotFoundError, Message: "session not found: " + params.SessionID}
+	}
+
+	creds, err := s.turnManager.RefreshCredentials(session.ID)
+	if err != nil {
+		return nil, &ErrorObj{Code: InternalError, Message: "failed to refresh turn credentials: " + err.Error()}
+	}
+	if len(creds) == 0 {
+		return nil, &ErrorObj{Code: InternalError, Message: "no turn servers configured"}
+	}
+
+	result := WebRTCRefreshTURNResult{
+		SessionID:  session.ID,
+		ICEServers: make([]WebRTCICEServer, 0, len(creds)),
+		ExpiresAt:  creds[0].Expires.Unix(),
+	}
+	for _, cred := range creds {
+		result.ICEServers = append(result.ICEServers, WebRTCICEServer{
+			URLs:       []string{cred.TURNServer, cred.STUNServer},
+			Username:   cred.Username,
+			Credential: cred.Password,
+		})
+	}
+
+	latest := creds[0]
+	session.TURNCredentials = &latest
+	session.MarkActivity()
+
+	slog.Info("webrtc_turn_refreshed", "session_id", session.ID, "expires_at", result.ExpiresAt)
+
+	return result, nil
+}
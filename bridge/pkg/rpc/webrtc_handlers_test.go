@@ -0,0 +1,292 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/turn"
+	"github.com/armorclaw/bridge/pkg/webrtc"
+	pion "github.com/pion/webrtc/v3"
+)
+
+func newWebRTCTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	// No STUN servers: host candidates only, so the test never needs network
+	cfg := webrtc.DefaultEngineConfig()
+	cfg.Configuration.ICEServers = nil
+	engine, err := webrtc.NewEngine(cfg)
+	if err != nil {
+		t.Fatalf("failed to create engine: %v", err)
+	}
+	sessions := webrtc.NewSessionManager(webrtc.DefaultSessionConfig())
+	t.Cleanup(func() {
+		sessions.Stop()
+		engine.Stop()
+	})
+
+	return &Server{
+		webrtcEngine:   engine,
+		webrtcSessions: sessions,
+		webrtcTokens:   webrtc.NewTokenManager("test-secret", time.Hour),
+	}
+}
+
+// clientOffer builds a real audio offer the way a browser would
+func clientOffer(t *testing.T) (*pion.PeerConnection, string) {
+	t.Helper()
+
+	client, err := pion.NewPeerConnection(pion.Configuration{})
+	if err != nil {
+		t.Fatalf("failed to create client peer: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	if _, err := client.AddTransceiverFromKind(pion.RTPCodecTypeAudio); err != nil {
+		t.Fatalf("failed to add transceiver: %v", err)
+	}
+	offer, err := client.CreateOffer(nil)
+	if err != nil {
+		t.Fatalf("failed to create offer: %v", err)
+	}
+	if err := client.SetLocalDescription(offer); err != nil {
+		t.Fatalf("failed to set local description: %v", err)
+	}
+	return client, offer.SDP
+}
+
+func TestWebRTCStart_Validation(t *testing.T) {
+	server := newWebRTCTestServer(t)
+
+	tests := []struct {
+		name   string
+		params string
+		code   int
+	}{
+		{"missing room", `{"sdp_offer":"v=0"}`, InvalidParams},
+		{"missing offer", `{"room_id":"!room:example.com"}`, InvalidParams},
+		{"bad ttl", `{"room_id":"!room:example.com","sdp_offer":"v=0","ttl":"soon"}`, InvalidParams},
+		{"garbage offer", `{"room_id":"!room:example.com","sdp_offer":"not sdp"}`, InvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errObj := server.handleWebRTCStart(context.Background(), &Request{Params: json.RawMessage(tt.params)})
+			if errObj == nil || errObj.Code != tt.code {
+				t.Errorf("expected error code %d, got %+v", tt.code, errObj)
+			}
+		})
+	}
+
+	if n := server.webrtcSessions.Count(); n != 0 {
+		t.Errorf("failed starts should not leave sessions behind, got %d", n)
+	}
+}
+
+func TestWebRTCStart_NotConfigured(t *testing.T) {
+	server := &Server{}
+
+	_, errObj := server.handleWebRTCStart(context.Background(), &Request{
+		Params: json.RawMessage(`{"room_id":"!room:example.com","sdp_offer":"v=0"}`),
+	})
+	if errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError, got %+v", errObj)
+	}
+}
+
+func TestWebRTCStart_AnswersOffer(t *testing.T) {
+	server := newWebRTCTestServer(t)
+	client, offer := clientOffer(t)
+
+	params, _ := json.Marshal(map[string]string{"room_id": "!room:example.com", "sdp_offer": offer})
+	result, errObj := server.handleWebRTCStart(context.Background(), &Request{Params: params})
+	if errObj != nil {
+		t.Fatalf("webrtc.start failed: %+v", errObj)
+	}
+
+	started := result.(WebRTCStartResult)
+	if started.SessionID == "" || started.Token == "" {
+		t.Errorf("expected session id and token, got %+v", started)
+	}
+	if !strings.Contains(started.SDPAnswer, "m=audio") {
+		t.Fatalf("expected an audio answer, got %q", started.SDPAnswer)
+	}
+
+	// The client must accept the answer as its remote description
+	if err := client.SetRemoteDescription(pion.SessionDescription{Type: pion.SDPTypeAnswer, SDP: started.SDPAnswer}); err != nil {
+		t.Fatalf("client rejected answer: %v", err)
+	}
+
+	session, ok := server.webrtcSessions.Get(started.SessionID)
+	if !ok || session.SDPAnswer != started.SDPAnswer || session.SDPOffer != offer {
+		t.Errorf("session did not record the negotiation: %+v", session)
+	}
+
+	_, errObj = server.handleWebRTCEnd(context.Background(), &Request{Params: json.RawMessage(`{"session_id":"` + started.SessionID + `"}`)})
+	if errObj != nil {
+		t.Fatalf("webrtc.end failed: %+v", errObj)
+	}
+	if _, ok := server.webrtcEngine.GetPeerConnection(started.SessionID); ok {
+		t.Error("peer connection should be closed after webrtc.end")
+	}
+
+	_, errObj = server.handleWebRTCEnd(context.Background(), &Request{Params: json.RawMessage(`{"session_id":"` + started.SessionID + `"}`)})
+	if errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("expected NotFoundError for ended session, got %+v", errObj)
+	}
+}
+
+func TestWebRTCStart_ClampsTTL(t *testing.T) {
+	server := newWebRTCTestServer(t)
+	_, offer := clientOffer(t)
+
+	// DefaultSessionConfig caps sessions at one hour
+	params, _ := json.Marshal(map[string]string{"room_id": "!room:example.com", "sdp_offer": offer, "ttl": "24h"})
+	result, errObj := server.handleWebRTCStart(context.Background(), &Request{Params: params})
+	if errObj != nil {
+		t.Fatalf("webrtc.start failed: %+v", errObj)
+	}
+
+	started := result.(WebRTCStartResult)
+	if started.TTL != time.Hour.String() {
+		t.Errorf("expected ttl clamped to 1h, got %q", started.TTL)
+	}
+	if remaining := time.Until(time.Unix(started.ExpiresAt, 0)); remaining > time.Hour {
+		t.Errorf("expires_at is beyond the maximum ttl: %v remaining", remaining)
+	}
+
+	server.webrtcEngine.ClosePeerConnection(started.SessionID)
+}
+
+func TestWebRTCStart_MediaConstraints(t *testing.T) {
+	server := newWebRTCTestServer(t)
+
+	invalid := []struct {
+		name   string
+		params string
+	}{
+		{"unsupported codec", `{"room_id":"!room:example.com","sdp_offer":"v=0","codec":"g722"}`},
+		{"bitrate too low", `{"room_id":"!room:example.com","sdp_offer":"v=0","max_bitrate_kbps":2}`},
+		{"bitrate too high", `{"room_id":"!room:example.com","sdp_offer":"v=0","max_bitrate_kbps":1000}`},
+		{"bitrate with pcmu", `{"room_id":"!room:example.com","sdp_offer":"v=0","codec":"pcmu","max_bitrate_kbps":32}`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errObj := server.handleWebRTCStart(context.Background(), &Request{Params: json.RawMessage(tt.params)})
+			if errObj == nil || errObj.Code != InvalidParams {
+				t.Errorf("expected InvalidParams, got %+v", errObj)
+			}
+		})
+	}
+
+	t.Run("opus bitrate cap", func(t *testing.T) {
+		client, offer := clientOffer(t)
+		params, _ := json.Marshal(map[string]interface{}{
+			"room_id": "!room:example.com", "sdp_offer": offer, "codec": "Opus", "max_bitrate_kbps": 24,
+		})
+		result, errObj := server.handleWebRTCStart(context.Background(), &Request{Params: params})
+		if errObj != nil {
+			t.Fatalf("webrtc.start failed: %+v", errObj)
+		}
+		answer := result.(WebRTCStartResult).SDPAnswer
+		if !strings.Contains(answer, "maxaveragebitrate=24000") {
+			t.Errorf("expected bitrate cap in answer, got %q", answer)
+		}
+		if err := client.SetRemoteDescription(pion.SessionDescription{Type: pion.SDPTypeAnswer, SDP: answer}); err != nil {
+			t.Errorf("client rejected capped answer: %v", err)
+		}
+	})
+
+	t.Run("pcmu only", func(t *testing.T) {
+		_, offer := clientOffer(t)
+		params, _ := json.Marshal(map[string]interface{}{
+			"room_id": "!room:example.com", "sdp_offer": offer, "codec": "pcmu",
+		})
+		result, errObj := server.handleWebRTCStart(context.Background(), &Request{Params: params})
+		if errObj != nil {
+			t.Fatalf("webrtc.start failed: %+v", errObj)
+		}
+		answer := result.(WebRTCStartResult).SDPAnswer
+		if !strings.Contains(answer, "PCMU/8000") || strings.Contains(answer, "opus/48000") {
+			t.Errorf("expected a PCMU-only answer, got %q", answer)
+		}
+	})
+
+	t.Run("defaults unchanged", func(t *testing.T) {
+		_, offer := clientOffer(t)
+		params, _ := json.Marshal(map[string]string{"room_id": "!room:example.com", "sdp_offer": offer})
+		result, errObj := server.handleWebRTCStart(context.Background(), &Request{Params: params})
+		if errObj != nil {
+			t.Fatalf("webrtc.start failed: %+v", errObj)
+		}
+		answer := result.(WebRTCStartResult).SDPAnswer
+		if !strings.Contains(answer, "opus/48000") || strings.Contains(answer, "maxaveragebitrate") {
+			t.Errorf("expected default Opus answer without a cap, got %q", answer)
+		}
+	})
+
+	for _, session := range server.webrtcSessions.List() {
+		server.webrtcEngine.ClosePeerConnection(session.ID)
+	}
+}
+
+func TestWebRTCRefreshTURN(t *testing.T) {
+	server := newWebRTCTestServer(t)
+
+	refresh := func(sessionID string) (interface{}, *ErrorObj) {
+		return server.handleWebRTCRefreshTURN(context.Background(), &Request{
+			Params: json.RawMessage(`{"session_id":"` + sessionID + `"}`),
+		})
+	}
+
+	session, err := server.webrtcSessions.Create("", "!room:example.com", 0)
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+
+	if _, errObj := refresh(session.ID); errObj == nil || errObj.Code != InternalError {
+		t.Errorf("expected InternalError without a TURN manager, got %+v", errObj)
+	}
+
+	turnCfg := turn.DefaultConfig()
+	turnCfg.Secret = "test-secret"
+	turnMgr, err := turn.NewManager(turnCfg)
+	if err != nil {
+		t.Fatalf("failed to create TURN manager: %v", err)
+	}
+	server.turnManager = turnMgr
+
+	if _, errObj := refresh(""); errObj == nil || errObj.Code != InvalidParams {
+		t.Errorf("expected InvalidParams, got %+v", errObj)
+	}
+	if _, errObj := refresh("missing"); errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("expected NotFoundError, got %+v", errObj)
+	}
+
+	result, errObj := refresh(session.ID)
+	if errObj != nil {
+		t.Fatalf("webrtc.refresh_turn failed: %+v", errObj)
+	}
+	refreshed := result.(WebRTCRefreshTURNResult)
+	if len(refreshed.ICEServers) != 1 || refreshed.ICEServers[0].Username == "" {
+		t.Fatalf("expected one ICE server with credentials, got %+v", refreshed.ICEServers)
+	}
+	if refreshed.ExpiresAt <= time.Now().Unix() {
+		t.Errorf("expected credentials to expire in the future, got %d", refreshed.ExpiresAt)
+	}
+	ice := refreshed.ICEServers[0]
+	if _, err := turnMgr.ValidateTURNCredentials(ice.Username, ice.Credential); err != nil {
+		t.Errorf("refreshed credentials do not validate: %v", err)
+	}
+	if session.TURNCredentials == nil || session.TURNCredentials.Username != ice.Username {
+		t.Error("session should record the latest TURN credentials")
+	}
+
+	server.webrtcSessions.End(session.ID)
+	if _, errObj := refresh(session.ID); errObj == nil || errObj.Code != NotFoundError {
+		t.Errorf("expected NotFoundError for ended session, got %+v", errObj)
+	}
+}
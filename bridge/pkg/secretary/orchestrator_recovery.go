@@ -0,0 +1,111 @@
+package secretary
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// RecoverWorkflows reloads workflows that were running or blocked when the
+// bridge last stopped. Workflow state is already persisted by the store on
+// every transition; this rebuilds the in-memory bookkeeping so that advance,
+// unblock, cancel and status calls work again after a restart. Call it once
+// after NewWorkflowOrchestrator, before workflows are started.
+//
+// A workflow whose template has been deleted, or whose current step no
+// longer exists in it, cannot continue and is marked failed.
+func (o *WorkflowOrchestratorImpl) RecoverWorkflows() (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	recovered := 0
+	for _, status := range []WorkflowStatus{StatusRunning, StatusBlocked} {
+		status := status
+		workflows, err := o.store.ListWorkflows(o.ctx, WorkflowFilter{Status: &status})
+		if err != nil {
+			return recovered, fmt.Errorf("failed to list %s workflows: %w", status, err)
+		}
+
+		for i := range workflows {
+			workflow := workflows[i]
+			if _, exists := o.activeWorkflows[workflow.ID]; exists {
+				continue
+			}
+
+			active, err := o.restoreWorkflow(&workflow)
+			if err != nil {
+				o.failUnrecoverable(&workflow, err)
+				continue
+			}
+
+			o.activeWorkflows[workflow.ID] = active
+			if workflow.Status == StatusRunning {
+				workflowCtx, cancel := context.WithCancel(o.ctx)
+				active.cancelFunc = cancel
+				go o.executeWorkflow(workflowCtx, workflow.ID)
+			}
+			recovered++
+
+			slog.Info("workflow_recovered",
+				"workflow_id", workflow.ID,
+				"status", workflow.Status,
+				"current_step", workflow.CurrentStep,
+			)
+		}
+	}
+
+	return recovered, nil
+}
+
+// restoreWorkflow rebuilds the active state of a persisted workflow,
+// locating its current step in the template
+func (o *WorkflowOrchestratorImpl) restoreWorkflow(workflow *Workflow) (*activeWorkflow, error) {
+	active := &activeWorkflow{
+		workflow:   workflow,
+		cancelFunc: func() {},
+		startedAt:  workflow.StartedAt,
+	}
+	if workflow.TemplateID == "" {
+		return active, nil
+	}
+
+	template, err := o.store.GetTemplate(o.ctx, workflow.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("template %s unavailable: %w", workflow.TemplateID, err)
+	}
+	active.template = template
+
+	if len(template.Steps) == 0 {
+		return active, nil
+	}
+
+	// Same indexing as StartWorkflow: offset from the first step's Order
+	active.currentIndex = template.Steps[0].Order
+	if workflow.CurrentStep == "" {
+		return active, nil
+	}
+	for i, step := range template.Steps {
+		if step.StepID == workflow.CurrentStep {
+			active.currentIndex += i
+			return active, nil
+		}
+	}
+	return nil, fmt.Errorf("step %s no longer in template %s", workflow.CurrentStep, workflow.TemplateID)
+}
+
+// failUnrecoverable marks a persisted workflow that cannot be resumed as
+// failed, so it no longer shows as running
+func (o *WorkflowOrchestratorImpl) failUnrecoverable(workflow *Workflow, cause error) {
+	slog.Warn("workflow_recovery_failed", "workflow_id", workflow.ID, "error", cause)
+
+	workflow.Status = StatusFailed
+	now := time.Now()
+	workflow.CompletedAt = &now
+	workflow.ErrorMessage = "recovery after restart failed: " + cause.Error()
+	if err := o.store.UpdateWorkflow(o.ctx, workflow); err != nil {
+		slog.Warn("workflow_recovery_update_failed", "workflow_id", workflow.ID, "error", err)
+	}
+
+	o.eventEmitter.EmitFailed(workflow, workflow.CurrentStep, cause, false)
+}
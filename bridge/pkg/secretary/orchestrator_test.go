@@ -883,6 +883,63 @@ func TestOrchestrator_Shutdown_CancelsActiveWorkflows(t *testing.T) {
 	assert.Equal(t, 2, cancelledCount)
 }
 
+//=============================================================================
+// Recovery Tests
+//=============================================================================
+
+func TestOrchestrator_RecoverWorkflows(t *testing.T) {
+	orch, store, emitter := setupTestOrchestrator(t)
+	defer orch.Shutdown()
+
+	store.templates["tpl-1"] = createTestTemplate("tpl-1", []WorkflowStep{
+		{StepID: "step1", Name: "Step 1", Type: StepAction, Order: 0},
+		{StepID: "step2", Name: "Step 2", Type: StepAction, Order: 1},
+		{StepID: "step3", Name: "Step 3", Type: StepAction, Order: 2},
+	})
+
+	// State left behind by a previous bridge process
+	running := createTestWorkflow("wf-running", "tpl-1", StatusRunning)
+	running.CurrentStep = "step2"
+	blocked := createTestWorkflow("wf-blocked", "tpl-1", StatusBlocked)
+	blocked.CurrentStep = "step1"
+	orphaned := createTestWorkflow("wf-orphaned", "tpl-missing", StatusRunning)
+	done := createTestWorkflow("wf-done", "tpl-1", StatusCompleted)
+	for _, w := range []*Workflow{running, blocked, orphaned, done} {
+		store.workflows[w.ID] = w
+	}
+
+	recovered, err := orch.RecoverWorkflows()
+	require.NoError(t, err)
+	assert.Equal(t, 2, recovered)
+	assert.Equal(t, 2, orch.GetActiveWorkflowCount())
+
+	// The running workflow continues from its persisted step
+	require.NoError(t, orch.AdvanceWorkflow("wf-running", "step2"))
+	got, err := orch.GetWorkflow("wf-running")
+	require.NoError(t, err)
+	assert.Equal(t, "step3", got.CurrentStep)
+
+	// The blocked workflow can be unblocked
+	require.NoError(t, orch.UnblockWorkflow("wf-blocked"))
+	assert.Equal(t, StatusRunning, store.workflows["wf-blocked"].Status)
+
+	// A workflow whose template is gone is failed instead of left running
+	assert.Equal(t, StatusFailed, store.workflows["wf-orphaned"].Status)
+	assert.Contains(t, store.workflows["wf-orphaned"].ErrorMessage, "recovery after restart failed")
+	failed := 0
+	for _, e := range emitter.getEvents() {
+		if e.eventType == WorkflowEventFailed {
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+
+	// Recovering twice does not duplicate active workflows
+	recovered, err = orch.RecoverWorkflows()
+	require.NoError(t, err)
+	assert.Equal(t, 0, recovered)
+}
+
 //=============================================================================
 // IsRunning Tests
 //=============================================================================
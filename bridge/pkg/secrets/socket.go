@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +27,20 @@ const (
 
 	// SecretSocketBufferSize is buffer size for socket writes
 	SecretSocketBufferSize = 4096
+
+	// SecretAckTimeout is how long to wait for the container to acknowledge
+	// receipt after the secrets have been written
+	SecretAckTimeout = 5 * time.Second
+
+	// maxAckSize bounds the acknowledgment message read from the container
+	maxAckSize = 1024
+
+	// SecretReaperInterval is how often StartReaper scans for orphaned sockets
+	SecretReaperInterval = time.Minute
+
+	// orphanGracePeriod protects sockets created for containers that are
+	// still being started from being reaped
+	orphanGracePeriod = SecretSocketTimeout + SecretAckTimeout
 )
 
 var (
@@ -33,8 +49,23 @@ var (
 
 	// ErrSecretWriteFailed is returned when writing secrets to socket fails
 	ErrSecretWriteFailed = errors.New("failed to write secrets to socket")
+
+	// ErrSecretNotAcknowledged is returned when the container does not
+	// confirm it received the secrets
+	ErrSecretNotAcknowledged = errors.New("container did not acknowledge secrets")
 )
 
+// ContainerExistsFunc reports whether a container with the given name still
+// exists. An error leaves the container's sockets in place.
+type ContainerExistsFunc func(containerName string) (bool, error)
+
+// secretAck is the length-prefixed JSON message a container sends back once
+// it has read and validated the secrets. Status is "ok" or "error".
+type secretAck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
 // SecretInjector manages in-memory secret injection via Unix sockets.
 // Secrets are never written to disk - only transmitted through socket.
 type SecretInjector struct {
@@ -47,17 +78,21 @@ type SecretInjector struct {
 	securityLog *logger.SecurityLogger
 	log         *logger.Logger // Component-scoped operational logger
 	auditLogger *audit.CriticalOperationLogger
+
+	// Delivery results of InjectSecrets sessions, for WaitForDelivery
+	deliveries map[string]chan error
 }
 
 // secretSession represents an active secret delivery session
 type secretSession struct {
-	socketPath  string
-	credential  keystore.Credential
-	ready       chan struct{} // Closed when socket is ready
-	expiresAt   time.Time
-	server      net.Listener
-	mu           sync.Mutex
-	closed       bool
+	socketPath string
+	credential keystore.Credential
+	ready      chan struct{} // Closed when socket is ready
+	result     chan error    // Receives nil once the container acknowledges, or the failure
+	expiresAt  time.Time
+	server     net.Listener
+	mu         sync.Mutex
+	closed     bool
 }
 
 // NewSecretInjector creates a new secret injector
@@ -75,6 +110,7 @@ func NewSecretInjector(socketDir string, secLog *logger.SecurityLogger) (*Secret
 		cancel:      cancel,
 		securityLog: secLog,
 		log:         logger.Global().WithComponent("secrets"),
+		deliveries:  make(map[string]chan error),
 	}, nil
 }
 
@@ -85,8 +121,30 @@ func (si *SecretInjector) SetAuditLogger(logger *audit.CriticalOperationLogger)
 	si.auditLogger = logger
 }
 
+// DeliverSecrets injects cred into the container that start launches.
+// start is given the host socket path to mount into the container and must
+// create and start it. DeliverSecrets returns nil only once the container
+// has acknowledged the secrets, and an error if it fails to or does not
+// answer within SecretSocketTimeout plus SecretAckTimeout. The socket is
+// removed before it returns.
+func (si *SecretInjector) DeliverSecrets(containerName string, cred keystore.Credential, start func(socketPath string) error) error {
+	socketPath, err := si.InjectSecrets(containerName, cred)
+	if err != nil {
+		return err
+	}
+	defer si.Cleanup(containerName)
+
+	if err := start(socketPath); err != nil {
+		return err
+	}
+	return si.WaitForDelivery(containerName, SecretSocketTimeout+SecretAckTimeout)
+}
+
 // InjectSecrets prepares a Unix socket for secret delivery and waits for container to connect.
 // Returns the socket path that should be mounted into the container.
+// The container only connects once started, so this does not wait for delivery;
+// call WaitForDelivery after starting the container to confirm it acknowledged
+// the secrets, or use DeliverSecrets, which does both.
 // The caller must call Cleanup() after container is started to remove the socket.
 func (si *SecretInjector) InjectSecrets(containerName string, cred keystore.Credential) (string, error) {
 	si.mu.Lock()
@@ -115,13 +173,15 @@ func (si *SecretInjector) InjectSecrets(containerName string, cred keystore.Cred
 	// Create session
 	session := &secretSession{
 		socketPath: socketPath,
-		credential:  cred,
+		credential: cred,
 		ready:      make(chan struct{}),
+		result:     make(chan error, 1),
 		expiresAt:  time.Now().Add(SecretSocketTimeout),
 		server:     listener,
 	}
 
 	si.sockets[containerName] = session
+	si.deliveries[containerName] = session.result
 
 	// Start socket handler in background
 	si.wg.Add(1)
@@ -166,7 +226,7 @@ func (si *SecretInjector) handleSecretConnection(session *secretSession) {
 	select {
 	case conn := <-connChan:
 		defer conn.Close()
-		si.deliverSecrets(session, conn)
+		session.result <- si.deliverSecrets(session, conn)
 
 	case err := <-errChan:
 		if !errors.Is(err, net.ErrClosed) {
@@ -175,21 +235,23 @@ func (si *SecretInjector) handleSecretConnection(session *secretSession) {
 				"error", err.Error(),
 			)
 		}
-		return
+		session.result <- fmt.Errorf("%w: %v", ErrSecretTimeout, err)
 
 	case <-time.After(SecretSocketTimeout):
 		si.log.Error("secret_socket_timeout",
 			"container", filepath.Base(session.socketPath),
 		)
-		return
+		session.result <- ErrSecretTimeout
 
 	case <-si.ctx.Done():
-		return
+		session.result <- si.ctx.Err()
 	}
 }
 
-// deliverSecrets sends credential data over the socket connection
-func (si *SecretInjector) deliverSecrets(session *secretSession, conn net.Conn) {
+// deliverSecrets sends credential data over the socket connection and waits
+// for the container to acknowledge it. It returns nil only once an "ok"
+// acknowledgment has been received.
+func (si *SecretInjector) deliverSecrets(session *secretSession, conn net.Conn) error {
 	containerName := filepath.Base(session.socketPath)
 	success := false
 	defer func() {
@@ -212,17 +274,11 @@ func (si *SecretInjector) deliverSecrets(session *secretSession, conn net.Conn)
 			"container", containerName,
 			"error", err.Error(),
 		)
-		return
+		return fmt.Errorf("%w: %v", ErrSecretWriteFailed, err)
 	}
 
 	// Write length prefix (4 bytes) for message framing
-	length := len(secretsData)
-	lengthPrefix := []byte{
-		byte(length >> 24),
-		byte(length >> 16),
-		byte(length >> 8),
-		byte(length),
-	}
+	lengthPrefix := encodeLength(len(secretsData))
 
 	// Write length prefix
 	if _, err := conn.Write(lengthPrefix); err != nil {
@@ -230,7 +286,7 @@ func (si *SecretInjector) deliverSecrets(session *secretSession, conn net.Conn)
 			"container", filepath.Base(session.socketPath),
 			"error", err.Error(),
 		)
-		return
+		return fmt.Errorf("%w: %v", ErrSecretWriteFailed, err)
 	}
 
 	// Write secrets data
@@ -243,11 +299,20 @@ func (si *SecretInjector) deliverSecrets(session *secretSession, conn net.Conn)
 				"written_bytes", totalWritten,
 				"error", err.Error(),
 			)
-			return
+			return fmt.Errorf("%w: %v", ErrSecretWriteFailed, err)
 		}
 		totalWritten += written
 	}
 
+	// Wait for the container to confirm it read and accepted the secrets
+	if err := readAck(conn, SecretAckTimeout); err != nil {
+		si.log.Error("secret_ack_failed",
+			"container", filepath.Base(session.socketPath),
+			"error", err.Error(),
+		)
+		return err
+	}
+
 	// Mark as successful for audit logging
 	success = true
 
@@ -257,6 +322,73 @@ func (si *SecretInjector) deliverSecrets(session *secretSession, conn net.Conn)
 		"bytes_sent", totalWritten+4,
 		"provider", string(session.credential.Provider),
 	)
+	return nil
+}
+
+// encodeLength returns the 4-byte big-endian length prefix used to frame
+// messages on the secret socket
+func encodeLength(length int) []byte {
+	return []byte{
+		byte(length >> 24),
+		byte(length >> 16),
+		byte(length >> 8),
+		byte(length),
+	}
+}
+
+// readAck reads the container's length-prefixed acknowledgment. A closed
+// connection, a timeout or an "error" status all count as not acknowledged.
+func readAck(conn net.Conn, timeout time.Duration) error {
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return fmt.Errorf("%w: %v", ErrSecretNotAcknowledged, err)
+	}
+	defer conn.SetReadDeadline(time.Time{})
+
+	var prefix [4]byte
+	if _, err := io.ReadFull(conn, prefix[:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrSecretNotAcknowledged, err)
+	}
+	length := int(prefix[0])<<24 | int(prefix[1])<<16 | int(prefix[2])<<8 | int(prefix[3])
+	if length <= 0 || length > maxAckSize {
+		return fmt.Errorf("%w: invalid acknowledgment length %d", ErrSecretNotAcknowledged, length)
+	}
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return fmt.Errorf("%w: %v", ErrSecretNotAcknowledged, err)
+	}
+
+	var ack secretAck
+	if err := json.Unmarshal(data, &ack); err != nil {
+		return fmt.Errorf("%w: invalid acknowledgment: %v", ErrSecretNotAcknowledged, err)
+	}
+	if ack.Status != "ok" {
+		return fmt.Errorf("%w: container reported %q: %s", ErrSecretNotAcknowledged, ack.Status, ack.Error)
+	}
+	return nil
+}
+
+// WaitForDelivery waits for the container started with an InjectSecrets
+// socket to acknowledge its secrets. It returns nil once acknowledged, or
+// the delivery failure, or ErrSecretNotAcknowledged if timeout elapses first.
+func (si *SecretInjector) WaitForDelivery(containerName string, timeout time.Duration) error {
+	si.mu.Lock()
+	result, exists := si.deliveries[containerName]
+	si.mu.Unlock()
+
+	if !exists {
+		return fmt.Errorf("no secret delivery pending for container: %s", containerName)
+	}
+
+	select {
+	case err := <-result:
+		si.mu.Lock()
+		delete(si.deliveries, containerName)
+		si.mu.Unlock()
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("%w within %s: %s", ErrSecretNotAcknowledged, timeout, containerName)
+	}
 }
 
 // cleanupSession removes socket file and cleans up session
@@ -275,9 +407,14 @@ func (si *SecretInjector) cleanupSession(session *secretSession) {
 	// Remove socket file
 	os.Remove(session.socketPath)
 
-	// Remove from tracking
+	// Remove from tracking; sessions are keyed by container name, not by
+	// socket file name
 	si.mu.Lock()
-	delete(si.sockets, filepath.Base(session.socketPath))
+	for key, tracked := range si.sockets {
+		if tracked == session {
+			delete(si.sockets, key)
+		}
+	}
 	si.mu.Unlock()
 }
 
@@ -295,6 +432,10 @@ func (si *SecretInjector) Cleanup(containerName string) error {
 	// Close and cleanup session
 	si.cleanupSession(session)
 
+	si.mu.Lock()
+	delete(si.deliveries, containerName)
+	si.mu.Unlock()
+
 	// Log cleanup
 	si.securityLog.LogSecretCleanup(si.ctx, containerName, "socket_injection_complete")
 
@@ -308,13 +449,15 @@ func (si *SecretInjector) Cleanup(containerName string) error {
 
 // UpdateSecrets sends updated secrets to a running container (P0-CRIT-3)
 // This is used by send_secret RPC method to deliver new credentials to running containers.
+// It returns only once the container has acknowledged the new secrets, so a
+// rotation that never reaches the container is reported as an error.
 func (si *SecretInjector) UpdateSecrets(containerName string, cred keystore.Credential) error {
 	si.mu.Lock()
-	defer si.mu.Unlock()
 
 	// Check if container exists
 	_, exists := si.sockets[containerName]
 	if !exists {
+		si.mu.Unlock()
 		return fmt.Errorf("container not found or not running: %s", containerName)
 	}
 
@@ -323,50 +466,51 @@ func (si *SecretInjector) UpdateSecrets(containerName string, cred keystore.Cred
 
 	listener, err := net.Listen("unix", socketPath)
 	if err != nil {
+		si.mu.Unlock()
 		return fmt.Errorf("failed to create update socket: %w", err)
 	}
 
 	// Set socket permissions
 	if err := os.Chmod(socketPath, 0660); err != nil {
 		listener.Close()
+		si.mu.Unlock()
 		return fmt.Errorf("failed to set socket permissions: %w", err)
 	}
 
 	// Create update session
 	updateSession := &secretSession{
-		socketPath:  socketPath,
-		credential:  cred,
+		socketPath: socketPath,
+		credential: cred,
 		ready:      make(chan struct{}),
+		result:     make(chan error, 1),
 		expiresAt:  time.Now().Add(SecretSocketTimeout),
 		server:     listener,
-		closed:     false,
 	}
 
 	// Add to tracking (use special key to avoid conflict)
 	si.sockets[containerName+".update"] = updateSession
+	si.mu.Unlock()
 
-	// Start handler in background
+	// Deliver in the background; the session is cleaned up when it finishes
 	si.wg.Add(1)
-	go si.handleSecretUpdate(updateSession)
+	go si.handleSecretConnection(updateSession)
 
-	// Wait for connection with timeout
+	var deliveryErr error
 	select {
-	case <-updateSession.ready:
-		// Socket ready for connection
-	case <-time.After(SecretSocketTimeout):
+	case deliveryErr = <-updateSession.result:
+	case <-time.After(SecretSocketTimeout + SecretAckTimeout):
+		deliveryErr = ErrSecretNotAcknowledged
 		si.cleanupSession(updateSession)
-		return fmt.Errorf("container did not connect to update socket: %s", containerName)
 	}
 
-	// Get connection and deliver secrets
-	conn, err := updateSession.server.Accept()
-	if err != nil {
-		si.cleanupSession(updateSession)
-		return err
+	// Audit logging for secret update
+	if si.auditLogger != nil {
+		_ = si.auditLogger.LogSecretInjection(si.ctx, containerName, cred.ID, deliveryErr == nil)
 	}
-	defer conn.Close()
 
-	si.deliverSecrets(updateSession, conn)
+	if deliveryErr != nil {
+		return fmt.Errorf("secret update not delivered to %s: %w", containerName, deliveryErr)
+	}
 
 	// Log the update
 	si.securityLog.LogSecretInject(si.ctx, containerName, cred.ID,
@@ -374,52 +518,123 @@ func (si *SecretInjector) UpdateSecrets(containerName string, cred keystore.Cred
 		slog.String("reason", "credential_update"),
 	)
 
-	// Audit logging for secret update
-	if si.auditLogger != nil {
-		_ = si.auditLogger.LogSecretInjection(si.ctx, containerName, cred.ID, true)
+	return nil
+}
+
+// StartReaper periodically removes secret sockets whose container no longer
+// exists, such as those left behind when an AutoRemove container dies before
+// Cleanup is called. It stops with the injector.
+func (si *SecretInjector) StartReaper(interval time.Duration, exists ContainerExistsFunc) {
+	if interval <= 0 {
+		interval = SecretReaperInterval
 	}
 
-	// Cleanup update session immediately after delivery
-	si.cleanupSession(updateSession)
+	si.wg.Add(1)
+	go func() {
+		defer si.wg.Done()
 
-	return nil
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				si.reapOrphans(exists)
+			case <-si.ctx.Done():
+				return
+			}
+		}
+	}()
 }
 
-// handleSecretUpdate handles update socket connection and delivery
-func (si *SecretInjector) handleSecretUpdate(session *secretSession) {
-	defer si.cleanupSession(session)
+// reapOrphans removes sockets in the socket directory whose container no
+// longer exists and returns how many were removed
+func (si *SecretInjector) reapOrphans(exists ContainerExistsFunc) int {
+	entries, err := os.ReadDir(si.socketDir)
+	if err != nil {
+		si.log.Error("secret_reaper_scan_failed",
+			"dir", si.socketDir,
+			"error", err.Error(),
+		)
+		return 0
+	}
 
-	// Accept connection or timeout
-	connChan := make(chan net.Conn, 1)
-	errChan := make(chan error, 1)
+	reaped := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".sock") {
+			continue
+		}
 
-	go func() {
-		conn, err := session.server.Accept()
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < orphanGracePeriod {
+			continue
+		}
+
+		containerName := socketContainerName(name)
+		found, err := exists(containerName)
 		if err != nil {
-			if !errors.Is(err, net.ErrClosed) {
-				errChan <- err
-			}
-			return
+			si.log.Warn("secret_reaper_check_failed",
+				"container", containerName,
+				"error", err.Error(),
+			)
+			continue
+		}
+		if found {
+			continue
 		}
-		connChan <- conn
-	}()
 
-	select {
-	case conn := <-connChan:
-		// Connection established
-		si.deliverSecrets(session, conn)
-	case err := <-errChan:
-		if !errors.Is(err, net.ErrClosed) {
-			si.log.Error("update_socket_error",
+		socketPath := filepath.Join(si.socketDir, name)
+		keyID := si.dropSessionsFor(containerName, socketPath)
+		if err := os.Remove(socketPath); err != nil && !os.IsNotExist(err) {
+			si.log.Error("secret_reaper_remove_failed",
+				"socket", name,
 				"error", err.Error(),
 			)
+			continue
 		}
-	case <-time.After(SecretSocketTimeout):
-		// Timeout
-		si.log.Error("update_timeout",
-			"container", filepath.Base(session.socketPath),
+
+		reaped++
+		si.securityLog.LogSecretCleanup(si.ctx, containerName, keyID,
+			slog.String("reason", "orphaned_socket"),
+			slog.String("socket", name),
 		)
 	}
+
+	return reaped
+}
+
+// dropSessionsFor closes any tracked session using socketPath and forgets
+// the container's pending delivery. It returns the session's credential ID,
+// or "" if the socket was not tracked.
+func (si *SecretInjector) dropSessionsFor(containerName, socketPath string) string {
+	si.mu.Lock()
+	var orphaned []*secretSession
+	for key, session := range si.sockets {
+		if session.socketPath == socketPath {
+			orphaned = append(orphaned, session)
+			delete(si.sockets, key)
+		}
+	}
+	delete(si.deliveries, containerName)
+	si.mu.Unlock()
+
+	keyID := ""
+	for _, session := range orphaned {
+		keyID = session.credential.ID
+		si.cleanupSession(session)
+	}
+	return keyID
+}
+
+// socketContainerName recovers the container name from a socket file name,
+// either "<container>.sock" or "<container>.update.<unix time>.sock"
+func socketContainerName(fileName string) string {
+	name := strings.TrimSuffix(fileName, ".sock")
+	if i := strings.LastIndex(name, ".update."); i > 0 {
+		return name[:i]
+	}
+	return name
 }
 
 // Stop stops the secret injector and cleans up all sessions
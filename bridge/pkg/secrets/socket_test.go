@@ -0,0 +1,282 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/keystore"
+	"github.com/armorclaw/bridge/pkg/logger"
+)
+
+func newTestSecretInjector(t *testing.T) *SecretInjector {
+	t.Helper()
+	logger.Initialize("info", "text", "stdout")
+
+	si, err := NewSecretInjector(t.TempDir(), logger.NewSecurityLogger(logger.Global()))
+	if err != nil {
+		t.Fatalf("NewSecretInjector() error = %v", err)
+	}
+	t.Cleanup(si.Stop)
+	return si
+}
+
+// readSecretsAndReply plays the container side of the socket: it reads the
+// framed secrets and, if ack is non-nil, sends it back framed the same way
+func readSecretsAndReply(t *testing.T, socketPath string, ack *secretAck) {
+	t.Helper()
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		t.Errorf("dial %s: %v", socketPath, err)
+		return
+	}
+	defer conn.Close()
+
+	var prefix [4]byte
+	if _, err := io.ReadFull(conn, prefix[:]); err != nil {
+		t.Errorf("read length prefix: %v", err)
+		return
+	}
+	length := int(prefix[0])<<24 | int(prefix[1])<<16 | int(prefix[2])<<8 | int(prefix[3])
+	data := make([]byte, length)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		t.Errorf("read secrets: %v", err)
+		return
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal(data, &secrets); err != nil || secrets["token"] != "sk-test" {
+		t.Errorf("secrets = %s, %v; want token sk-test", data, err)
+	}
+
+	if ack == nil {
+		return
+	}
+	reply, _ := json.Marshal(ack)
+	conn.Write(append(encodeLength(len(reply)), reply...))
+}
+
+func testCredential() keystore.Credential {
+	return keystore.Credential{ID: "cred-1", Provider: keystore.ProviderOpenAI, Token: "sk-test"}
+}
+
+func TestWaitForDeliveryAcknowledged(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	socketPath, err := si.InjectSecrets("agent-1", testCredential())
+	if err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+	go readSecretsAndReply(t, socketPath, &secretAck{Status: "ok"})
+
+	if err := si.WaitForDelivery("agent-1", 2*time.Second); err != nil {
+		t.Errorf("WaitForDelivery() error = %v", err)
+	}
+}
+
+func TestWaitForDeliveryErrorAck(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	socketPath, err := si.InjectSecrets("agent-1", testCredential())
+	if err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+	go readSecretsAndReply(t, socketPath, &secretAck{Status: "error", Error: "missing provider or token"})
+
+	err = si.WaitForDelivery("agent-1", 2*time.Second)
+	if !errors.Is(err, ErrSecretNotAcknowledged) {
+		t.Errorf("WaitForDelivery() error = %v, want ErrSecretNotAcknowledged", err)
+	}
+}
+
+func TestWaitForDeliveryMissingAck(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	socketPath, err := si.InjectSecrets("agent-1", testCredential())
+	if err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+	// The container hangs up without replying
+	go readSecretsAndReply(t, socketPath, nil)
+
+	err = si.WaitForDelivery("agent-1", 2*time.Second)
+	if !errors.Is(err, ErrSecretNotAcknowledged) {
+		t.Errorf("WaitForDelivery() error = %v, want ErrSecretNotAcknowledged", err)
+	}
+}
+
+func TestWaitForDeliveryUnknownContainer(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	if err := si.WaitForDelivery("missing", time.Millisecond); err == nil {
+		t.Error("WaitForDelivery() succeeded for a container with no pending delivery")
+	}
+}
+
+func TestDeliverSecrets(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	var mounted string
+	err := si.DeliverSecrets("agent-1", testCredential(), func(socketPath string) error {
+		mounted = socketPath
+		go readSecretsAndReply(t, socketPath, &secretAck{Status: "ok"})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DeliverSecrets() error = %v", err)
+	}
+	if _, err := os.Stat(mounted); !os.IsNotExist(err) {
+		t.Errorf("socket %s still exists after delivery", mounted)
+	}
+}
+
+func TestDeliverSecretsStartFailure(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	startErr := errors.New("container failed to start")
+	err := si.DeliverSecrets("agent-1", testCredential(), func(string) error {
+		return startErr
+	})
+	if !errors.Is(err, startErr) {
+		t.Errorf("DeliverSecrets() error = %v, want the start error", err)
+	}
+
+	// The session is gone, so the container can be launched again
+	err = si.DeliverSecrets("agent-1", testCredential(), func(socketPath string) error {
+		go readSecretsAndReply(t, socketPath, &secretAck{Status: "error", Error: "missing provider or token"})
+		return nil
+	})
+	if !errors.Is(err, ErrSecretNotAcknowledged) {
+		t.Errorf("DeliverSecrets() error = %v, want ErrSecretNotAcknowledged", err)
+	}
+}
+
+func TestUpdateSecretsWaitsForAck(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	if _, err := si.InjectSecrets("agent-1", testCredential()); err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+
+	go func() {
+		// Wait for the update socket to appear, then acknowledge it
+		for i := 0; i < 100; i++ {
+			matches, _ := filepath.Glob(filepath.Join(si.socketDir, "agent-1.update.*.sock"))
+			if len(matches) > 0 {
+				readSecretsAndReply(t, matches[0], &secretAck{Status: "ok"})
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Error("update socket was never created")
+	}()
+
+	if err := si.UpdateSecrets("agent-1", testCredential()); err != nil {
+		t.Errorf("UpdateSecrets() error = %v", err)
+	}
+}
+
+// ageSocket backdates a socket file past the reaper's grace period
+func ageSocket(t *testing.T, path string) {
+	t.Helper()
+	old := time.Now().Add(-2 * orphanGracePeriod)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Chtimes(%s): %v", path, err)
+	}
+}
+
+func TestReapOrphansRemovesSocketsOfMissingContainers(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	gonePath, err := si.InjectSecrets("gone", testCredential())
+	if err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+	alivePath, err := si.InjectSecrets("alive", testCredential())
+	if err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+	ageSocket(t, gonePath)
+	ageSocket(t, alivePath)
+
+	// A leftover update socket from a previous bridge run is not tracked
+	stale := filepath.Join(si.socketDir, "gone.update.1700000000.sock")
+	if err := os.WriteFile(stale, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ageSocket(t, stale)
+
+	reaped := si.reapOrphans(func(name string) (bool, error) {
+		return name == "alive", nil
+	})
+	if reaped != 2 {
+		t.Errorf("reaped %d sockets, want 2", reaped)
+	}
+
+	for _, path := range []string{gonePath, stale} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", filepath.Base(path))
+		}
+	}
+	if _, err := os.Stat(alivePath); err != nil {
+		t.Errorf("socket of running container removed: %v", err)
+	}
+
+	si.mu.Lock()
+	_, tracked := si.sockets["gone"]
+	_, pending := si.deliveries["gone"]
+	si.mu.Unlock()
+	if tracked || pending {
+		t.Errorf("orphaned session still tracked (session %v, delivery %v)", tracked, pending)
+	}
+}
+
+func TestReapOrphansKeepsNewAndUncheckedSockets(t *testing.T) {
+	si := newTestSecretInjector(t)
+
+	// Created just now: its container may not have been started yet
+	fresh, err := si.InjectSecrets("starting", testCredential())
+	if err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+
+	unknown, err := si.InjectSecrets("unknown", testCredential())
+	if err != nil {
+		t.Fatalf("InjectSecrets() error = %v", err)
+	}
+	ageSocket(t, unknown)
+
+	reaped := si.reapOrphans(func(name string) (bool, error) {
+		if name == "unknown" {
+			return false, errors.New("docker unavailable")
+		}
+		return false, nil
+	})
+	if reaped != 0 {
+		t.Errorf("reaped %d sockets, want 0", reaped)
+	}
+	for _, path := range []string{fresh, unknown} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s removed: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestSocketContainerName(t *testing.T) {
+	tests := map[string]string{
+		"agent-1.sock":                   "agent-1",
+		"agent-1.update.1700000000.sock": "agent-1",
+		"openclaw.v2.sock":               "openclaw.v2",
+	}
+	for file, want := range tests {
+		if got := socketContainerName(file); got != want {
+			t.Errorf("socketContainerName(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package socket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+const (
+	// ContainerNamePrefix marks containers started by the bridge
+	ContainerNamePrefix = "armorclaw-"
+
+	// reconcileTimeout bounds the Docker listing done at startup
+	reconcileTimeout = 10 * time.Second
+)
+
+// ContainerLister lists Docker containers; *docker.Client satisfies it
+type ContainerLister interface {
+	ListContainers(ctx context.Context, all bool, filters filters.Args) ([]types.Container, error)
+}
+
+// reconcileContainers repopulates the tracking map from the running
+// containers Docker reports, so containers that survived a bridge restart
+// can still be stopped and listed. Containers already tracked are kept.
+func (s *Server) reconcileContainers(ctx context.Context) (int, error) {
+	if s.docker == nil {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	// Docker's name filter matches substrings, so the prefix is checked below
+	containers, err := s.docker.ListContainers(ctx, false, filters.NewArgs(filters.Arg("name", ContainerNamePrefix)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restored := 0
+	for _, c := range containers {
+		if !hasNamePrefix(c.Names, ContainerNamePrefix) {
+			continue
+		}
+		if _, ok := s.containers[c.ID]; ok {
+			continue
+		}
+		s.containers[c.ID] = &ContainerSession{
+			ID:       c.ID,
+			Endpoint: fmt.Sprintf("/run/armorclaw/%s.sock", c.ID),
+			Provider: c.Labels["armorclaw.provider"],
+			Created:  c.Created,
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// hasNamePrefix reports whether any of a container's names starts with prefix
+func hasNamePrefix(names []string, prefix string) bool {
+	for _, name := range names {
+		if strings.HasPrefix(strings.TrimPrefix(name, "/"), prefix) {
+			return true
+		}
+	}
+	return false
+}
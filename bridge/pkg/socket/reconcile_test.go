@@ -0,0 +1,63 @@
+package socket
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+)
+
+type fakeLister struct {
+	containers []types.Container
+	err        error
+}
+
+func (f *fakeLister) ListContainers(ctx context.Context, all bool, args filters.Args) ([]types.Container, error) {
+	return f.containers, f.err
+}
+
+func TestReconcileContainers(t *testing.T) {
+	lister := &fakeLister{containers: []types.Container{
+		{ID: "agent1", Names: []string{"/armorclaw-instance-1"}, Labels: map[string]string{"armorclaw.provider": "openai"}, Created: 100},
+		{ID: "agent2", Names: []string{"/armorclaw-instance-2"}},
+		// Substring matches from Docker's name filter are not ours
+		{ID: "other", Names: []string{"/my-armorclaw-test"}},
+	}}
+
+	s := &Server{
+		containers: map[string]*ContainerSession{"agent2": {ID: "agent2", Provider: "anthropic"}},
+		docker:     lister,
+	}
+
+	restored, err := s.reconcileContainers(context.Background())
+	if err != nil {
+		t.Fatalf("reconcileContainers failed: %v", err)
+	}
+	if restored != 1 {
+		t.Errorf("expected 1 restored container, got %d", restored)
+	}
+
+	if session, ok := s.containers["agent1"]; !ok || session.Provider != "openai" || session.Created != 100 {
+		t.Errorf("expected agent1 to be tracked from its labels, got %+v", session)
+	}
+	if s.containers["agent2"].Provider != "anthropic" {
+		t.Error("already tracked containers should be kept as is")
+	}
+	if _, ok := s.containers["other"]; ok {
+		t.Error("containers without the armorclaw- prefix should not be tracked")
+	}
+}
+
+func TestReconcileContainers_Errors(t *testing.T) {
+	s := &Server{containers: map[string]*ContainerSession{}}
+	if restored, err := s.reconcileContainers(context.Background()); err != nil || restored != 0 {
+		t.Errorf("expected no-op without a docker client, got %d, %v", restored, err)
+	}
+
+	s.docker = &fakeLister{err: errors.New("daemon unavailable")}
+	if _, err := s.reconcileContainers(context.Background()); err == nil {
+		t.Error("expected listing error to be returned")
+	}
+}
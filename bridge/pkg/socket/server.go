@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/armorclaw/bridge/pkg/docker"
 	"github.com/armorclaw/bridge/pkg/keystore"
 	"github.com/armorclaw/bridge/pkg/trust"
 	"golang.org/x/time/rate"
@@ -76,6 +77,7 @@ const (
 	CodeInternalError     = -32603
 	CodeUnauthorized      = -32000
 	CodeContainerNotFound = -32001
+	CodeKeyExpired        = -32008
 )
 
 // Server handles Unix socket connections
@@ -84,6 +86,8 @@ type Server struct {
 	listener   net.Listener
 	keystore   *keystore.Keystore
 	containers map[string]*ContainerSession
+	limits     docker.ResourceLimits
+	docker     ContainerLister
 	mu         sync.RWMutex
 	ctx        context.Context
 	cancel     context.CancelFunc
@@ -104,6 +108,7 @@ type ContainerSession struct {
 	Endpoint string
 	Provider string
 	Created  int64
+	Limits   docker.ResourceLimits
 }
 
 // Handler is called for each received message
@@ -111,8 +116,10 @@ type Handler func(msg *Message) (*Message, error)
 
 // Config holds server configuration
 type Config struct {
-	SocketPath string
-	Keystore   *keystore.Keystore
+	SocketPath    string
+	Keystore      *keystore.Keystore
+	DefaultLimits docker.ResourceLimits // Used when start omits memory_mb or cpu_shares
+	Docker        ContainerLister       // Optional; enables startup reconciliation
 }
 
 // New creates a new Unix socket server
@@ -131,6 +138,8 @@ func New(cfg Config) (*Server, error) {
 		socketPath:        cfg.SocketPath,
 		keystore:          cfg.Keystore,
 		containers:        make(map[string]*ContainerSession),
+		limits:            cfg.DefaultLimits,
+		docker:            cfg.Docker,
 		ctx:               ctx,
 		cancel:            cancel,
 		rateLimiter:       rate.NewLimiter(rate.Limit(DefaultRateLimit), DefaultRateBurst),
@@ -141,6 +150,13 @@ func New(cfg Config) (*Server, error) {
 
 // Start starts the Unix socket server
 func (s *Server) Start() error {
+	// Pick up agent containers that outlived a previous bridge process
+	if restored, err := s.reconcileContainers(s.ctx); err != nil {
+		slog.Warn("container_reconcile_failed", "error", err)
+	} else if restored > 0 {
+		slog.Info("containers_reconciled", "restored", restored)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -402,6 +418,8 @@ func (s *Server) handleStart(msg *Message) *Message {
 		KeyID     string `json:"key_id"`
 		AgentType string `json:"agent_type"`
 		Image     string `json:"image"`
+		MemoryMB  int64  `json:"memory_mb,omitempty"`
+		CPUShares int64  `json:"cpu_shares,omitempty"`
 	}
 
 	if len(msg.Params) > 0 {
@@ -429,6 +447,25 @@ func (s *Server) handleStart(msg *Message) *Message {
 		}
 	}
 
+	// Requested limits override the configured defaults field by field
+	limits := s.limits
+	if params.MemoryMB != 0 {
+		limits.Memory = params.MemoryMB * 1024 * 1024
+	}
+	if params.CPUShares != 0 {
+		limits.CPUShares = params.CPUShares
+	}
+	if err := limits.Validate(); err != nil {
+		return &Message{
+			JSONRPC: "2.0",
+			ID:      msg.ID,
+			Error: &RPCError{
+				Code:    CodeInvalidParams,
+				Message: err.Error(),
+			},
+		}
+	}
+
 	// Retrieve credential from keystore
 	cred, err := s.keystore.Retrieve(params.KeyID)
 	if err != nil {
@@ -442,6 +479,16 @@ func (s *Server) handleStart(msg *Message) *Message {
 				},
 			}
 		}
+		if errors.Is(err, keystore.ErrKeyExpired) {
+			return &Message{
+				JSONRPC: "2.0",
+				ID:      msg.ID,
+				Error: &RPCError{
+					Code:    CodeKeyExpired,
+					Message: keyExpiredMessage(params.KeyID),
+				},
+			}
+		}
 		return &Message{
 			JSONRPC: "2.0",
 			ID:      msg.ID,
@@ -461,6 +508,7 @@ func (s *Server) handleStart(msg *Message) *Message {
 		ID:       containerID,
 		Provider: string(cred.Provider),
 		Created:  time.Now().Unix(),
+		Limits:   limits,
 	}
 	s.mu.Unlock()
 
@@ -471,6 +519,8 @@ func (s *Server) handleStart(msg *Message) *Message {
 			"container_id": containerID,
 			"status":       "running",
 			"endpoint":     fmt.Sprintf("/run/armorclaw/%s.sock", containerID),
+			"memory_mb":    limits.Memory / 1024 / 1024,
+			"cpu_shares":   limits.CPUShares,
 		},
 	}
 }
@@ -572,6 +622,16 @@ func (s *Server) handleGetCredential(msg *Message) *Message {
 				},
 			}
 		}
+		if errors.Is(err, keystore.ErrKeyExpired) {
+			return &Message{
+				JSONRPC: "2.0",
+				ID:      msg.ID,
+				Error: &RPCError{
+					Code:    CodeKeyExpired,
+					Message: keyExpiredMessage(params.ID),
+				},
+			}
+		}
 		return &Message{
 			JSONRPC: "2.0",
 			ID:      msg.ID,
@@ -594,6 +654,11 @@ func (s *Server) handleGetCredential(msg *Message) *Message {
 	}
 }
 
+// keyExpiredMessage explains an expired credential and how to recover
+func keyExpiredMessage(keyID string) string {
+	return fmt.Sprintf("key %q has expired; rotate it with rotate_key or store a new key", keyID)
+}
+
 // handleListCredentials lists available credentials
 func (s *Server) handleListCredentials(msg *Message) *Message {
 	// Check keystore availability
@@ -15,6 +15,7 @@ import (
 	"github.com/docker/docker/api/types/mount"
 
 	"github.com/armorclaw/bridge/pkg/docker"
+	"github.com/armorclaw/bridge/pkg/keystore"
 	"github.com/armorclaw/bridge/pkg/secrets"
 )
 
@@ -39,13 +40,20 @@ type KeystoreProvider interface {
 	Get(key string) (string, error)
 }
 
+// CredentialStore retrieves the API keys agents are started with
+type CredentialStore interface {
+	Retrieve(id string) (*keystore.Credential, error)
+}
+
 // AgentFactory spawns containers from agent definitions
 type AgentFactory struct {
-	docker      DockerClient
-	store       Store
-	keystore    KeystoreProvider
-	piiInjector *secrets.PIIInjector
-	stateDir    string
+	docker         DockerClient
+	store          Store
+	keystore       KeystoreProvider
+	credentials    CredentialStore
+	piiInjector    *secrets.PIIInjector
+	secretInjector *secrets.SecretInjector
+	stateDir       string
 }
 
 // FactoryConfig configures the agent factory
@@ -55,17 +63,24 @@ type FactoryConfig struct {
 	Keystore     KeystoreProvider
 	PIIInjector  *secrets.PIIInjector
 	DefaultImage string
+
+	// Credentials and SecretInjector deliver the API key named by
+	// SpawnRequest.KeyID over a secret socket; both are needed for KeyID
+	Credentials    CredentialStore
+	SecretInjector *secrets.SecretInjector
 	StateDir     string
 }
 
 // NewAgentFactory creates a new agent factory
 func NewAgentFactory(cfg FactoryConfig) *AgentFactory {
 	return &AgentFactory{
-		docker:      cfg.DockerClient,
-		store:       cfg.Store,
-		keystore:    cfg.Keystore,
-		piiInjector: cfg.PIIInjector,
-		stateDir:    cfg.StateDir,
+		docker:         cfg.DockerClient,
+		store:          cfg.Store,
+		keystore:       cfg.Keystore,
+		credentials:    cfg.Credentials,
+		piiInjector:    cfg.PIIInjector,
+		secretInjector: cfg.SecretInjector,
+		stateDir:       cfg.StateDir,
 	}
 }
 
@@ -84,6 +99,7 @@ type SpawnRequest struct {
 	RoomID          string          `json:"room_id,omitempty"`
 	Config          json.RawMessage `json:"config,omitempty"`
 	Specialization  *SpecializationConfig `json:"specialization,omitempty"`
+	KeyID           string          `json:"key_id,omitempty"` // API key delivered over the secret socket
 }
 
 // SpawnResult contains the result of spawning an agent
@@ -172,24 +188,43 @@ func (f *AgentFactory) Spawn(ctx context.Context, req *SpawnRequest) (*SpawnResu
 		env = append(env, "PII_SOCKET_PATH="+docker.PIIMountPath+"/socket.sock")
 	}
 
-	// 6. Create container
-	createResp, err := f.docker.ContainerCreate(
-		ctx,
-		config,
-		hostConfig,
-		nil, // networking config
-		nil, // platform
-		"armorclaw-"+instanceID,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create container: %w", err)
+	// 6. Create and start container
+	containerName := "armorclaw-" + instanceID
+	var containerID string
+	launch := func() error {
+		createResp, err := f.docker.ContainerCreate(
+			ctx,
+			config,
+			hostConfig,
+			nil, // networking config
+			nil, // platform
+			containerName,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create container: %w", err)
+		}
+
+		if err := f.docker.ContainerStart(ctx, createResp.ID, container.StartOptions{}); err != nil {
+			// Clean up on start failure
+			_ = f.docker.ContainerRemove(ctx, createResp.ID, container.RemoveOptions{Force: true})
+			return fmt.Errorf("failed to start container: %w", err)
+		}
+		containerID = createResp.ID
+		return nil
 	}
 
-	// 8. Start container
-	if err := f.docker.ContainerStart(ctx, createResp.ID, container.StartOptions{}); err != nil {
-		// Clean up on start failure
-		_ = f.docker.ContainerRemove(ctx, createResp.ID, container.RemoveOptions{Force: true})
-		return nil, fmt.Errorf("failed to start container: %w", err)
+	// 8. Deliver the API key, if any; the agent is only reported as running
+	// once it has acknowledged the key
+	if req.KeyID != "" {
+		err = f.launchWithKey(containerName, req.KeyID, config, hostConfig, launch)
+	} else {
+		err = launch()
+	}
+	if err != nil {
+		if containerID != "" {
+			_ = f.docker.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: true})
+		}
+		return nil, err
 	}
 
 	// 9. Track instance in database
@@ -197,7 +232,7 @@ func (f *AgentFactory) Spawn(ctx context.Context, req *SpawnRequest) (*SpawnResu
 	instance := &AgentInstance{
 		ID:              instanceID,
 		DefinitionID:    def.ID,
-		ContainerID:     createResp.ID,
+		ContainerID:     containerID,
 		Status:          StatusRunning,
 		TaskDescription: req.TaskDescription,
 		SpawnedBy:       req.UserID,
@@ -217,6 +252,29 @@ func (f *AgentFactory) Spawn(ctx context.Context, req *SpawnRequest) (*SpawnResu
 	}, nil
 }
 
+// launchWithKey runs launch with the credential keyID mounted as a secret
+// socket, and returns once the container has acknowledged it
+func (f *AgentFactory) launchWithKey(containerName, keyID string, config *container.Config, hostConfig *container.HostConfig, launch func() error) error {
+	if f.credentials == nil || f.secretInjector == nil {
+		return fmt.Errorf("secret delivery is not configured, cannot start with key %s", keyID)
+	}
+
+	cred, err := f.credentials.Retrieve(keyID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve key %s: %w", keyID, err)
+	}
+
+	err = f.secretInjector.DeliverSecrets(containerName, *cred, func(socketPath string) error {
+		hostConfig.Mounts = append(hostConfig.Mounts, docker.PrepareSecretSocketMount(socketPath))
+		config.Env = append(config.Env, "ARMORCLAW_SECRET_SOCKET="+docker.SecretSocketMountPath)
+		return launch()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to deliver key %s: %w", keyID, err)
+	}
+	return nil
+}
+
 // buildEnvironment creates environment variables for the container
 func (f *AgentFactory) buildEnvironment(def *AgentDefinition, task string, spec *SpecializationConfig) ([]string, []string) {
 	var env []string
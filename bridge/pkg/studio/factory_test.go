@@ -2,13 +2,22 @@ package studio
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+
+	"github.com/armorclaw/bridge/pkg/docker"
+	"github.com/armorclaw/bridge/pkg/keystore"
+	"github.com/armorclaw/bridge/pkg/logger"
+	"github.com/armorclaw/bridge/pkg/secrets"
 )
 
 //=============================================================================
@@ -26,6 +35,7 @@ type mockDockerClient struct {
 	removedContainers []string
 	inspectError      error
 	containerState    *types.ContainerState
+	onStart           func(mockContainer) // Plays the container's side after start
 }
 
 type mockContainer struct {
@@ -48,6 +58,13 @@ func (m *mockDockerClient) ContainerCreate(ctx context.Context, config *containe
 
 func (m *mockDockerClient) ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error {
 	m.startedContainers = append(m.startedContainers, containerID)
+	if m.onStart != nil {
+		for _, c := range m.createdContainers {
+			if c.id == containerID {
+				m.onStart(c)
+			}
+		}
+	}
 	return nil
 }
 
@@ -109,6 +126,79 @@ func (m *mockKeystore) Get(key string) (string, error) {
 	return "", fmt.Errorf("key not found: %s", key)
 }
 
+//=============================================================================
+// Mock Credential Store
+//=============================================================================
+
+type mockCredentials map[string]keystore.Credential
+
+func (m mockCredentials) Retrieve(id string) (*keystore.Credential, error) {
+	cred, ok := m[id]
+	if !ok {
+		return nil, keystore.ErrKeyNotFound
+	}
+	return &cred, nil
+}
+
+// ackSecrets connects to the secret socket mounted into c, reads the
+// framed secrets and replies with the given status
+func ackSecrets(t *testing.T, c mockContainer, status string) {
+	t.Helper()
+
+	var socketPath string
+	for _, m := range c.hostConfig.Mounts {
+		if m.Target == docker.SecretSocketMountPath {
+			socketPath = m.Source
+		}
+	}
+	if socketPath == "" {
+		t.Error("secret socket not mounted into the container")
+		return
+	}
+
+	go func() {
+		conn, err := net.Dial("unix", socketPath)
+		if err != nil {
+			t.Errorf("dial secret socket: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var prefix [4]byte
+		if _, err := io.ReadFull(conn, prefix[:]); err != nil {
+			t.Errorf("read length prefix: %v", err)
+			return
+		}
+		data := make([]byte, int(prefix[0])<<24|int(prefix[1])<<16|int(prefix[2])<<8|int(prefix[3]))
+		if _, err := io.ReadFull(conn, data); err != nil {
+			t.Errorf("read secrets: %v", err)
+			return
+		}
+
+		reply, _ := json.Marshal(map[string]string{"status": status})
+		length := len(reply)
+		conn.Write(append([]byte{byte(length >> 24), byte(length >> 16), byte(length >> 8), byte(length)}, reply...))
+	}()
+}
+
+func newTestSecretInjector(t *testing.T) *secrets.SecretInjector {
+	t.Helper()
+
+	// Socket paths are limited to 108 bytes, too short for t.TempDir()
+	dir, err := os.MkdirTemp("", "sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	si, err := secrets.NewSecretInjector(dir, logger.NewSecurityLogger(logger.Global()))
+	if err != nil {
+		t.Fatalf("NewSecretInjector() error = %v", err)
+	}
+	t.Cleanup(si.Stop)
+	return si
+}
+
 //=============================================================================
 // Factory Tests (CGO-free)
 //=============================================================================
@@ -188,6 +278,88 @@ func TestAgentFactory_Spawn(t *testing.T) {
 	}
 }
 
+func TestAgentFactory_Spawn_WithKeyID(t *testing.T) {
+	store, err := NewStore(StoreConfig{Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	def := &AgentDefinition{
+		ID:           "test-agent-key",
+		Name:         "Keyed Agent",
+		Skills:       []string{"browser_navigate"},
+		ResourceTier: "low",
+		CreatedBy:    "@test:example.com",
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+		IsActive:     true,
+	}
+	if err := store.CreateDefinition(def); err != nil {
+		t.Fatalf("failed to create definition: %v", err)
+	}
+
+	credentials := mockCredentials{
+		"openai-main": {ID: "openai-main", Provider: keystore.ProviderOpenAI, Token: "sk-test"},
+	}
+
+	t.Run("acknowledged", func(t *testing.T) {
+		mockDocker := &mockDockerClient{}
+		mockDocker.onStart = func(c mockContainer) { ackSecrets(t, c, "ok") }
+		factory := NewAgentFactory(FactoryConfig{StateDir: t.TempDir(), DockerClient: mockDocker,
+			Store: store, Credentials: credentials, SecretInjector: newTestSecretInjector(t)})
+
+		result, err := factory.Spawn(context.Background(), &SpawnRequest{
+			DefinitionID: def.ID,
+			UserID:       "@test:example.com",
+			KeyID:        "openai-main",
+		})
+		if err != nil {
+			t.Fatalf("Spawn() error = %v", err)
+		}
+		if result.Instance.ContainerID == "" {
+			t.Error("expected the instance to record its container")
+		}
+
+		env := strings.Join(mockDocker.createdContainers[0].config.Env, "\n")
+		if !strings.Contains(env, "ARMORCLAW_SECRET_SOCKET="+docker.SecretSocketMountPath) {
+			t.Error("expected ARMORCLAW_SECRET_SOCKET in the container environment")
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockDocker := &mockDockerClient{}
+		mockDocker.onStart = func(c mockContainer) { ackSecrets(t, c, "error") }
+		factory := NewAgentFactory(FactoryConfig{StateDir: t.TempDir(), DockerClient: mockDocker,
+			Store: store, Credentials: credentials, SecretInjector: newTestSecretInjector(t)})
+
+		_, err := factory.Spawn(context.Background(), &SpawnRequest{
+			DefinitionID: def.ID,
+			UserID:       "@test:example.com",
+			KeyID:        "openai-main",
+		})
+		if err == nil {
+			t.Fatal("Spawn() succeeded although the container rejected its key")
+		}
+		if len(mockDocker.removedContainers) != 1 {
+			t.Errorf("expected the container to be removed, got: %v", mockDocker.removedContainers)
+		}
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		factory := NewAgentFactory(FactoryConfig{StateDir: t.TempDir(), DockerClient: &mockDockerClient{},
+			Store: store})
+
+		_, err := factory.Spawn(context.Background(), &SpawnRequest{
+			DefinitionID: def.ID,
+			KeyID:        "openai-main",
+		})
+		if err == nil {
+			t.Error("Spawn() with a key should fail without secret delivery configured")
+		}
+	})
+}
+
 func TestAgentFactory_Spawn_InactiveDefinition(t *testing.T) {
 	store, err := NewStore(StoreConfig{Path: ":memory:"})
 	if err != nil {
@@ -9,6 +9,8 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+
+	"github.com/armorclaw/bridge/pkg/secrets"
 )
 
 //=============================================================================
@@ -138,6 +140,11 @@ type IntegrationConfig struct {
 
 	// Wizard timeout in minutes (default: 5)
 	WizardTimeout int
+
+	// Credentials and SecretInjector let agents be spawned with an API key
+	// (optional)
+	Credentials    CredentialStore
+	SecretInjector *secrets.SecretInjector
 }
 
 // NewIntegration creates a complete studio integration
@@ -157,8 +164,10 @@ func NewIntegration(cfg IntegrationConfig) (*StudioIntegration, error) {
 	var factory *AgentFactory
 	if cfg.DockerClient != nil {
 		factory = NewAgentFactory(FactoryConfig{
-			DockerClient: cfg.DockerClient,
-			Store:        store,
+			DockerClient:   cfg.DockerClient,
+			Store:          store,
+			Credentials:    cfg.Credentials,
+			SecretInjector: cfg.SecretInjector,
 		})
 	}
 
@@ -618,6 +618,7 @@ func (h *RPCHandler) handleDeleteAgent(req *RPCRequest) *RPCResponse {
 type SpawnAgentParams struct {
 	ID              string `json:"id"`
 	TaskDescription string `json:"task_description,omitempty"`
+	KeyID           string `json:"key_id,omitempty"`
 }
 
 func (h *RPCHandler) handleSpawnAgent(req *RPCRequest) *RPCResponse {
@@ -679,6 +680,7 @@ func (h *RPCHandler) handleSpawnAgent(req *RPCRequest) *RPCResponse {
 			DefinitionID:    def.ID,
 			TaskDescription: params.TaskDescription,
 			UserID:          req.UserID,
+			KeyID:           params.KeyID,
 		})
 		if spawnErr != nil {
 			instance.Status = StatusFailed
@@ -3,8 +3,10 @@
 package trust
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -29,6 +31,16 @@ const (
 	StateExpired TrustState = "expired"
 )
 
+// Errors returned when looking up a verification request
+var (
+	ErrRequestNotFound = errors.New("verification request not found")
+	ErrRequestExpired  = errors.New("verification request expired")
+)
+
+// finishedRequestRetention is how long the outcome of an approved, rejected
+// or expired request stays queryable through RequestStatus
+const finishedRequestRetention = time.Hour
+
 // VerificationMethod defines how devices can be verified
 type VerificationMethod string
 
@@ -87,7 +99,8 @@ type TrustConfig struct {
 	// WaitPeriod is the auto-approval wait duration (for MethodWaitPeriod)
 	WaitPeriod time.Duration `json:"wait_period"`
 
-	// RequestExpiry is how long verification requests are valid
+	// RequestExpiry is how long verification requests are valid. Devices
+	// still unapproved after this are removed by CleanupExpired.
 	RequestExpiry time.Duration `json:"request_expiry"`
 
 	// RequireSecondFactor requires existing device confirmation
@@ -112,13 +125,24 @@ func DefaultTrustConfig() *TrustConfig {
 	}
 }
 
-// Manager handles device trust verification
+// Manager handles device trust verification. Its state is in memory; the
+// durable trust state of a device is the DeviceStore record that
+// device.approve and device.reject update.
 type Manager struct {
-	mu       sync.RWMutex
-	config   *TrustConfig
-	devices  map[string]*Device
-	pending  map[string]*VerificationRequest
-	admins   []string // Admin user IDs for notifications
+	mu      sync.RWMutex
+	config  *TrustConfig
+	devices map[string]*Device
+	pending map[string]*VerificationRequest
+	admins  []string // Admin user IDs for notifications
+	// finished records the outcome of requests no longer pending
+	finished map[string]finishedRequest
+	logger   *slog.Logger
+}
+
+// finishedRequest is the outcome of a verification request
+type finishedRequest struct {
+	state TrustState
+	at    time.Time
 }
 
 // NewManager creates a new trust manager
@@ -127,10 +151,12 @@ func NewManager(config *TrustConfig) *Manager {
 		config = DefaultTrustConfig()
 	}
 	return &Manager{
-		config:  config,
-		devices: make(map[string]*Device),
-		pending: make(map[string]*VerificationRequest),
-		admins:  make([]string, 0),
+		config:   config,
+		devices:  make(map[string]*Device),
+		pending:  make(map[string]*VerificationRequest),
+		admins:   make([]string, 0),
+		finished: make(map[string]finishedRequest),
+		logger:   slog.Default().With("component", "trust_devices"),
 	}
 }
 
@@ -218,12 +244,12 @@ func (m *Manager) ApproveDevice(requestID, adminID string) error {
 
 	request, exists := m.pending[requestID]
 	if !exists {
-		return errors.New("verification request not found")
+		return m.missingRequestError(requestID)
 	}
 
 	if time.Now().After(request.ExpiresAt) {
-		delete(m.pending, requestID)
-		return errors.New("verification request expired")
+		m.expireRequest(requestID, request, time.Now())
+		return ErrRequestExpired
 	}
 
 	device, exists := m.devices[request.DeviceID]
@@ -240,7 +266,7 @@ func (m *Manager) ApproveDevice(requestID, adminID string) error {
 	request.ApprovedBy = adminID
 	request.ApprovedAt = &now
 
-	delete(m.pending, requestID)
+	m.finishRequest(requestID, StateVerified, now)
 
 	return nil
 }
@@ -252,7 +278,7 @@ func (m *Manager) RejectDevice(requestID, adminID, reason string) error {
 
 	request, exists := m.pending[requestID]
 	if !exists {
-		return errors.New("verification request not found")
+		return m.missingRequestError(requestID)
 	}
 
 	device, exists := m.devices[request.DeviceID]
@@ -269,7 +295,7 @@ func (m *Manager) RejectDevice(requestID, adminID, reason string) error {
 	request.RejectedBy = adminID
 	request.RejectedAt = &now
 
-	delete(m.pending, requestID)
+	m.finishRequest(requestID, StateRejected, now)
 
 	return nil
 }
@@ -286,7 +312,7 @@ func (m *Manager) ConfirmSecondFactor(requestID, confirmingDeviceID string) erro
 
 	request, exists := m.pending[requestID]
 	if !exists {
-		return errors.New("verification request not found")
+		return m.missingRequestError(requestID)
 	}
 
 	// Verify confirming device is trusted
@@ -314,7 +340,7 @@ func (m *Manager) ConfirmSecondFactor(requestID, confirmingDeviceID string) erro
 	request.ApprovedBy = "second_factor:" + confirmingDeviceID
 	request.ApprovedAt = &now
 
-	delete(m.pending, requestID)
+	m.finishRequest(requestID, StateVerified, now)
 
 	return nil
 }
@@ -368,6 +394,25 @@ func (m *Manager) ListPendingRequests() []*VerificationRequest {
 	return requests
 }
 
+// RequestStatus returns the state of a verification request: pending
+// approval, verified, rejected or expired. Finished requests are remembered
+// for an hour; after that, and for unknown IDs, it returns ErrRequestNotFound.
+func (m *Manager) RequestStatus(requestID string) (TrustState, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if request, exists := m.pending[requestID]; exists {
+		if time.Now().After(request.ExpiresAt) {
+			return StateExpired, nil
+		}
+		return StatePendingApproval, nil
+	}
+	if finished, exists := m.finished[requestID]; exists {
+		return finished.state, nil
+	}
+	return "", ErrRequestNotFound
+}
+
 // ListUserDevices returns all devices for a user
 func (m *Manager) ListUserDevices(userID string) []*Device {
 	m.mu.RLock()
@@ -436,7 +481,9 @@ func (m *Manager) GetAdmins() []string {
 	return admins
 }
 
-// CleanupExpired removes expired verification requests
+// CleanupExpired removes verification requests older than RequestExpiry
+// along with their unapproved devices, and forgets finished requests past
+// their retention. It returns the number of requests expired.
 func (m *Manager) CleanupExpired() int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -446,17 +493,70 @@ func (m *Manager) CleanupExpired() int {
 
 	for id, request := range m.pending {
 		if now.After(request.ExpiresAt) {
-			if device, exists := m.devices[request.DeviceID]; exists {
-				device.TrustState = StateExpired
-			}
-			delete(m.pending, id)
+			m.expireRequest(id, request, now)
 			count++
 		}
 	}
 
+	for id, finished := range m.finished {
+		if now.Sub(finished.at) > finishedRequestRetention {
+			delete(m.finished, id)
+		}
+	}
+
 	return count
 }
 
+// StartCleanupRoutine runs CleanupExpired every interval until ctx is done
+func (m *Manager) StartCleanupRoutine(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.CleanupExpired()
+			}
+		}
+	}()
+}
+
+// expireRequest drops an expired request and its device, unless the device
+// was verified by other means. Callers must hold m.mu.
+func (m *Manager) expireRequest(requestID string, request *VerificationRequest, now time.Time) {
+	if device, exists := m.devices[request.DeviceID]; exists && device.TrustState != StateVerified {
+		delete(m.devices, request.DeviceID)
+	}
+	m.finishRequest(requestID, StateExpired, now)
+
+	m.logger.Info("pending_device_expired",
+		"request_id", requestID,
+		"device_id", request.DeviceID,
+		"user_id", request.UserID,
+		"device_name", request.DeviceName,
+		"age", now.Sub(request.CreatedAt).Round(time.Second).String(),
+	)
+}
+
+// finishRequest moves a request out of pending, recording its outcome.
+// Callers must hold m.mu.
+func (m *Manager) finishRequest(requestID string, state TrustState, now time.Time) {
+	delete(m.pending, requestID)
+	m.finished[requestID] = finishedRequest{state: state, at: now}
+}
+
+// missingRequestError explains why a request is no longer pending. Callers
+// must hold m.mu.
+func (m *Manager) missingRequestError(requestID string) error {
+	if finished, exists := m.finished[requestID]; exists && finished.state == StateExpired {
+		return ErrRequestExpired
+	}
+	return ErrRequestNotFound
+}
+
 // SetConfig updates the trust configuration
 func (m *Manager) SetConfig(config *TrustConfig) {
 	m.mu.Lock()
@@ -0,0 +1,72 @@
+package trust
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCleanupExpiredRemovesAbandonedDevices(t *testing.T) {
+	cfg := DefaultTrustConfig()
+	cfg.RequestExpiry = time.Hour
+	m := NewManager(cfg)
+
+	stale, staleReq, err := m.RegisterDevice("@alice:example.com", "Alice", "Old Phone", "fp-old")
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	fresh, freshReq, err := m.RegisterDevice("@alice:example.com", "Alice", "New Phone", "fp-new")
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	staleReq.ExpiresAt = time.Now().Add(-time.Minute)
+
+	if n := m.CleanupExpired(); n != 1 {
+		t.Fatalf("CleanupExpired() = %d, want 1", n)
+	}
+
+	if _, err := m.GetDevice(stale.ID); err == nil {
+		t.Error("abandoned device was not removed")
+	}
+	if _, err := m.GetDevice(fresh.ID); err != nil {
+		t.Errorf("unexpired device was removed: %v", err)
+	}
+	if len(m.ListPendingRequests()) != 1 {
+		t.Errorf("pending requests = %d, want 1", len(m.ListPendingRequests()))
+	}
+
+	if state, err := m.RequestStatus(staleReq.ID); err != nil || state != StateExpired {
+		t.Errorf("RequestStatus(swept) = %q, %v; want expired", state, err)
+	}
+	if state, err := m.RequestStatus(freshReq.ID); err != nil || state != StatePendingApproval {
+		t.Errorf("RequestStatus(pending) = %q, %v; want pending_approval", state, err)
+	}
+	if err := m.ApproveDevice(staleReq.ID, "@admin:example.com"); !errors.Is(err, ErrRequestExpired) {
+		t.Errorf("ApproveDevice(swept) = %v, want ErrRequestExpired", err)
+	}
+	if _, err := m.RequestStatus("verify_unknown"); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("RequestStatus(unknown) = %v, want ErrRequestNotFound", err)
+	}
+}
+
+func TestRequestStatusAfterApproval(t *testing.T) {
+	m := NewManager(nil)
+
+	_, req, err := m.RegisterDevice("@bob:example.com", "Bob", "Laptop", "fp-bob")
+	if err != nil {
+		t.Fatalf("RegisterDevice() error = %v", err)
+	}
+	if err := m.ApproveDevice(req.ID, "@admin:example.com"); err != nil {
+		t.Fatalf("ApproveDevice() error = %v", err)
+	}
+	if state, err := m.RequestStatus(req.ID); err != nil || state != StateVerified {
+		t.Errorf("RequestStatus() = %q, %v; want verified", state, err)
+	}
+
+	// Outcomes are forgotten once past their retention
+	m.finished[req.ID] = finishedRequest{state: StateVerified, at: time.Now().Add(-2 * finishedRequestRetention)}
+	m.CleanupExpired()
+	if _, err := m.RequestStatus(req.ID); !errors.Is(err, ErrRequestNotFound) {
+		t.Errorf("RequestStatus() after retention = %v, want ErrRequestNotFound", err)
+	}
+}
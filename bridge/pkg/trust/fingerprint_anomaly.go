@@ -0,0 +1,195 @@
+package trust
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+)
+
+// AnomalyFingerprintChange flags a device whose fingerprint is unlike any
+// the user has connected from before, which may mean stolen credentials.
+// It stays set until the device is verified.
+const AnomalyFingerprintChange = "fingerprint_change"
+
+// DefaultFingerprintSimilarityThreshold is the similarity to a user's known
+// fingerprints below which a new device is flagged
+const DefaultFingerprintSimilarityThreshold = 0.5
+
+// codeFingerprintAnomaly is the error code reported to the admin
+const codeFingerprintAnomaly = "SYS-041"
+
+// maxFingerprintHistory is how many distinct devices are remembered per user
+const maxFingerprintHistory = 10
+
+// fingerprintRecord is one device in a user's fingerprint history
+type fingerprintRecord struct {
+	deviceID string
+	input    DeviceFingerprintInput
+	lastSeen time.Time
+}
+
+// fingerprintWeights weight each attribute by how rarely it changes on the
+// same device. A browser update changes the user agent; a new machine
+// changes the platform, canvas and WebGL hashes together.
+var fingerprintWeights = struct {
+	userAgent, platform, screenRes, timezone, language float64
+	canvas, webgl, audio, plugins, fonts               float64
+}{
+	userAgent: 2, platform: 2, screenRes: 1, timezone: 1, language: 1,
+	canvas: 2, webgl: 2, audio: 1, plugins: 1, fonts: 1,
+}
+
+// FingerprintSimilarity returns how alike two fingerprints are, from 0 (no
+// shared attributes) to 1 (identical). Attributes missing from both are
+// ignored; list attributes score their overlap.
+func FingerprintSimilarity(a, b DeviceFingerprintInput) float64 {
+	var matched, total float64
+
+	compare := func(x, y string, weight float64) {
+		if x == "" && y == "" {
+			return
+		}
+		total += weight
+		if x == y {
+			matched += weight
+		}
+	}
+	compareSet := func(x, y []string, weight float64) {
+		if len(x) == 0 && len(y) == 0 {
+			return
+		}
+		total += weight
+		matched += weight * setOverlap(x, y)
+	}
+
+	w := fingerprintWeights
+	compare(a.UserAgent, b.UserAgent, w.userAgent)
+	compare(a.Platform, b.Platform, w.platform)
+	compare(a.ScreenRes, b.ScreenRes, w.screenRes)
+	compare(a.Timezone, b.Timezone, w.timezone)
+	compare(a.Language, b.Language, w.language)
+	compare(a.CanvasHash, b.CanvasHash, w.canvas)
+	compare(a.WebGLHash, b.WebGLHash, w.webgl)
+	compare(a.AudioHash, b.AudioHash, w.audio)
+	compareSet(a.Plugins, b.Plugins, w.plugins)
+	compareSet(a.Fonts, b.Fonts, w.fonts)
+
+	if total == 0 {
+		return 1
+	}
+	return matched / total
+}
+
+// setOverlap returns the Jaccard index of two string sets
+func setOverlap(x, y []string) float64 {
+	inX := make(map[string]bool, len(x))
+	for _, v := range x {
+		inX[v] = true
+	}
+	union := make(map[string]bool, len(x)+len(y))
+	shared := make(map[string]bool)
+	for _, v := range x {
+		union[v] = true
+	}
+	for _, v := range y {
+		union[v] = true
+		if inX[v] {
+			shared[v] = true
+		}
+	}
+	if len(union) == 0 {
+		return 1
+	}
+	return float64(len(shared)) / float64(len(union))
+}
+
+// fingerprintEmpty reports whether a request carried no fingerprint at all,
+// as QuickEnforce requests do
+func fingerprintEmpty(input *DeviceFingerprintInput) bool {
+	return FingerprintSimilarity(*input, DeviceFingerprintInput{}) == 1
+}
+
+// checkFingerprint compares a device's fingerprint with the user's history
+// the first time the device is seen, flags it if it is unlike all of them,
+// and records it. It returns an admin alert for a new anomaly, or nil.
+// Callers must hold m.mu.
+func (m *ZeroTrustManager) checkFingerprint(userID string, device *DeviceFingerprintData, input *DeviceFingerprintInput) *errsys.TracedError {
+	if m.config.FingerprintSimilarityThreshold < 0 || fingerprintEmpty(input) {
+		return nil
+	}
+
+	now := time.Now()
+	history := m.fingerprints[userID]
+	for i := range history {
+		if history[i].deviceID == device.ID {
+			history[i].lastSeen = now
+			return nil
+		}
+	}
+
+	var alert *errsys.TracedError
+	if len(history) > 0 {
+		best, closest := 0.0, ""
+		for _, record := range history {
+			if s := FingerprintSimilarity(*input, record.input); s > best || closest == "" {
+				best, closest = s, record.deviceID
+			}
+		}
+
+		if best < m.config.FingerprintSimilarityThreshold {
+			device.FingerprintAnomaly = true
+			device.FingerprintSimilarity = math.Round(best*100) / 100
+
+			m.logger.Warn("fingerprint_anomaly",
+				"user_id", userID,
+				"device_id", device.ID,
+				"closest_device_id", closest,
+				"similarity", device.FingerprintSimilarity,
+				"known_devices", len(history),
+			)
+
+			alert = errsys.NewBuilder(codeFingerprintAnomaly).
+				WithMessage(fmt.Sprintf("%s connected from an unfamiliar device (%.0f%% similar to the closest of %d known)",
+					userID, device.FingerprintSimilarity*100, len(history))).
+				WithFunction("ZeroTrustManager.Verify").
+				WithStateValue("user_id", userID).
+				WithStateValue("device_id", device.ID).
+				WithStateValue("closest_device_id", closest).
+				WithStateValue("similarity", device.FingerprintSimilarity).
+				WithStateValue("platform", input.Platform).
+				WithStateValue("user_agent", input.UserAgent).
+				Build()
+		}
+	}
+
+	history = append(history, fingerprintRecord{deviceID: device.ID, input: *input, lastSeen: now})
+	if len(history) > maxFingerprintHistory {
+		oldest := 0
+		for i := range history {
+			if history[i].lastSeen.Before(history[oldest].lastSeen) {
+				oldest = i
+			}
+		}
+		history = append(history[:oldest], history[oldest+1:]...)
+	}
+	m.fingerprints[userID] = history
+
+	return alert
+}
+
+// notifyFingerprintAnomaly reports an anomaly to the admin through the
+// configured error system, or the global notifier if none is configured
+func (m *ZeroTrustManager) notifyFingerprintAnomaly(ctx context.Context, alert *errsys.TracedError) {
+	var err error
+	if m.config.ErrorSystem != nil {
+		err = m.config.ErrorSystem.Notify(ctx, alert)
+	} else if errsys.GetGlobalNotifier() != nil {
+		err = errsys.GlobalNotify(ctx, alert)
+	}
+	if err != nil {
+		m.logger.Warn("fingerprint_anomaly_notify_failed", "error", err)
+	}
+}
@@ -0,0 +1,179 @@
+package trust
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	errsys "github.com/armorclaw/bridge/pkg/errors"
+)
+
+var (
+	laptopFingerprint = DeviceFingerprintInput{
+		UserAgent:  "Mozilla/5.0 (Macintosh) Firefox/128.0",
+		Platform:   "macos",
+		ScreenRes:  "2560x1600",
+		Timezone:   "Europe/Berlin",
+		Language:   "de-DE",
+		CanvasHash: "c-laptop",
+		WebGLHash:  "w-laptop",
+		Fonts:      []string{"Helvetica", "Menlo", "SF Pro"},
+	}
+	// The same laptop after a browser update
+	updatedLaptopFingerprint = DeviceFingerprintInput{
+		UserAgent:  "Mozilla/5.0 (Macintosh) Firefox/129.0",
+		Platform:   "macos",
+		ScreenRes:  "2560x1600",
+		Timezone:   "Europe/Berlin",
+		Language:   "de-DE",
+		CanvasHash: "c-laptop",
+		WebGLHash:  "w-laptop",
+		Fonts:      []string{"Helvetica", "Menlo", "SF Pro"},
+	}
+	unknownFingerprint = DeviceFingerprintInput{
+		UserAgent:  "Mozilla/5.0 (Windows NT 10.0) Chrome/126.0",
+		Platform:   "windows",
+		ScreenRes:  "1920x1080",
+		Timezone:   "Asia/Shanghai",
+		Language:   "zh-CN",
+		CanvasHash: "c-unknown",
+		WebGLHash:  "w-unknown",
+		Fonts:      []string{"Arial", "SimSun"},
+	}
+)
+
+func fingerprintRequest(session string, fp DeviceFingerprintInput) *ZeroTrustRequest {
+	return &ZeroTrustRequest{
+		SessionID:         session,
+		UserID:            "@alice:example.com",
+		DeviceFingerprint: fp,
+		IPAddress:         "192.168.1.1",
+		Action:            "login",
+	}
+}
+
+func hasAnomaly(flags []string, anomaly string) bool {
+	for _, f := range flags {
+		if f == anomaly {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFingerprintSimilarity(t *testing.T) {
+	if s := FingerprintSimilarity(laptopFingerprint, laptopFingerprint); s != 1 {
+		t.Errorf("identical fingerprints similarity = %v, want 1", s)
+	}
+	if s := FingerprintSimilarity(laptopFingerprint, updatedLaptopFingerprint); s < DefaultFingerprintSimilarityThreshold {
+		t.Errorf("browser update similarity = %v, want at least %v", s, DefaultFingerprintSimilarityThreshold)
+	}
+	if s := FingerprintSimilarity(laptopFingerprint, unknownFingerprint); s != 0 {
+		t.Errorf("unrelated fingerprints similarity = %v, want 0", s)
+	}
+	if s := FingerprintSimilarity(DeviceFingerprintInput{}, DeviceFingerprintInput{}); s != 1 {
+		t.Errorf("empty fingerprints similarity = %v, want 1", s)
+	}
+}
+
+func TestVerifyFlagsFingerprintChange(t *testing.T) {
+	manager := NewZeroTrustManager(ZeroTrustConfig{})
+	ctx := context.Background()
+
+	first, _ := manager.Verify(ctx, fingerprintRequest("s1", laptopFingerprint))
+	if hasAnomaly(first.AnomalyFlags, AnomalyFingerprintChange) {
+		t.Error("first device should not be flagged")
+	}
+
+	updated, _ := manager.Verify(ctx, fingerprintRequest("s2", updatedLaptopFingerprint))
+	if hasAnomaly(updated.AnomalyFlags, AnomalyFingerprintChange) {
+		t.Error("similar device should not be flagged")
+	}
+
+	unknown, _ := manager.Verify(ctx, fingerprintRequest("s3", unknownFingerprint))
+	if !hasAnomaly(unknown.AnomalyFlags, AnomalyFingerprintChange) {
+		t.Fatalf("anomaly flags = %v, want %s", unknown.AnomalyFlags, AnomalyFingerprintChange)
+	}
+	device, _ := manager.GetDeviceData(unknown.DeviceID)
+	if !device.FingerprintAnomaly || device.FingerprintSimilarity != 0 {
+		t.Errorf("device = %+v, want flagged with similarity 0", device)
+	}
+
+	// The flag persists until the device is verified (step-up approval)
+	again, _ := manager.Verify(ctx, fingerprintRequest("s3", unknownFingerprint))
+	if !hasAnomaly(again.AnomalyFlags, AnomalyFingerprintChange) {
+		t.Error("unverified anomalous device should stay flagged")
+	}
+	if err := manager.VerifyDevice(unknown.DeviceID, "admin_approval"); err != nil {
+		t.Fatal(err)
+	}
+	verified, _ := manager.Verify(ctx, fingerprintRequest("s3", unknownFingerprint))
+	if hasAnomaly(verified.AnomalyFlags, AnomalyFingerprintChange) {
+		t.Error("verified device should no longer be flagged")
+	}
+}
+
+func TestVerifyFingerprintCheckDisabled(t *testing.T) {
+	manager := NewZeroTrustManager(ZeroTrustConfig{FingerprintSimilarityThreshold: -1})
+	ctx := context.Background()
+
+	manager.Verify(ctx, fingerprintRequest("s1", laptopFingerprint))
+	result, _ := manager.Verify(ctx, fingerprintRequest("s2", unknownFingerprint))
+	if hasAnomaly(result.AnomalyFlags, AnomalyFingerprintChange) {
+		t.Error("fingerprint anomalies should not be flagged when disabled")
+	}
+}
+
+func TestVerifyFingerprintChangeNotifiesAdmin(t *testing.T) {
+	system, err := errsys.Initialize(errsys.Config{
+		ConfigAdminMXID: "@admin:example.com",
+		Enabled:         true,
+		NotifyEnabled:   true,
+	})
+	if err != nil {
+		t.Fatalf("initialize error system: %v", err)
+	}
+	defer system.Stop()
+	system.SetDryRun(true)
+
+	manager := NewZeroTrustManager(ZeroTrustConfig{ErrorSystem: system})
+	ctx := context.Background()
+	manager.Verify(ctx, fingerprintRequest("s1", laptopFingerprint))
+	manager.Verify(ctx, fingerprintRequest("s2", unknownFingerprint))
+	manager.Verify(ctx, fingerprintRequest("s2", unknownFingerprint))
+
+	messages := system.DryRunMessages()
+	if len(messages) != 1 || !strings.Contains(messages[0].Message, "SYS-041") || !strings.Contains(messages[0].Message, "@alice:example.com") {
+		t.Errorf("admin notifications = %+v, want one SYS-041 for @alice:example.com", messages)
+	}
+}
+
+func TestEnforceFingerprintChange(t *testing.T) {
+	manager := NewZeroTrustManager(ZeroTrustConfig{})
+	tm := NewTrustMiddleware(TrustMiddlewareConfig{TrustManager: manager})
+	tm.RegisterPolicy(EnforcementPolicy{Operation: "login", MinTrustLevel: TrustScoreUntrusted, MaxRiskScore: 100})
+	tm.RegisterPolicy(EnforcementPolicy{Operation: "admin", MinTrustLevel: TrustScoreUntrusted, MaxRiskScore: 100, RequireStepUpOnFingerprintChange: true})
+	ctx := context.Background()
+
+	if _, err := tm.Enforce(ctx, "login", fingerprintRequest("s1", laptopFingerprint)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Flagged but allowed by default
+	login, err := tm.Enforce(ctx, "login", fingerprintRequest("s2", unknownFingerprint))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !login.Allowed || !login.FingerprintChanged {
+		t.Errorf("login = %+v, want allowed and flagged", login)
+	}
+
+	// Denied pending step-up where the policy requires it
+	admin, err := tm.Enforce(ctx, "admin", fingerprintRequest("s2", unknownFingerprint))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin.Allowed || !hasAnomaly(admin.RequiredActions, "step_up_approval") {
+		t.Errorf("admin = %+v, want denied with step_up_approval", admin)
+	}
+}
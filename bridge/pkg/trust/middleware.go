@@ -27,9 +27,15 @@ type EnforcementPolicy struct {
 	// Whether verified device is required
 	RequireVerifiedDevice bool `json:"require_verified_device"`
 
-	// Allowed anomaly flags (empty = none allowed)
+	// Allowed anomaly flags (empty = none allowed). A fingerprint change is
+	// governed by RequireStepUpOnFingerprintChange instead.
 	AllowedAnomalies []string `json:"allowed_anomalies"`
 
+	// Deny requests from a device flagged with a fingerprint change until
+	// the device is verified (step-up approval). When false the decision is
+	// only flagged.
+	RequireStepUpOnFingerprintChange bool `json:"require_step_up_on_fingerprint_change"`
+
 	// Bypass trust check for specific conditions
 	BypassConditions []string `json:"bypass_conditions,omitempty"`
 }
@@ -51,6 +57,9 @@ type EnforcementResult struct {
 	// Anomalies detected
 	Anomalies []string `json:"anomalies,omitempty"`
 
+	// Whether the device fingerprint is unlike the user's known devices
+	FingerprintChanged bool `json:"fingerprint_changed,omitempty"`
+
 	// Required actions for the user
 	RequiredActions []string `json:"required_actions,omitempty"`
 
@@ -159,6 +168,17 @@ func (tm *TrustMiddleware) Enforce(ctx context.Context, operation string, req *Z
 		SessionID:       result.SessionID,
 	}
 
+	// A fingerprint change is flagged on every decision and only denied
+	// when the policy asks for step-up approval
+	var anomalies []string
+	for _, anomaly := range result.AnomalyFlags {
+		if anomaly == AnomalyFingerprintChange {
+			enforcement.FingerprintChanged = true
+			continue
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
 	// Check trust level
 	if result.TrustLevel < policy.MinTrustLevel {
 		enforcement.Allowed = false
@@ -198,16 +218,25 @@ func (tm *TrustMiddleware) Enforce(ctx context.Context, operation string, req *Z
 		}
 	}
 
+	// Check fingerprint change
+	if enforcement.FingerprintChanged && policy.RequireStepUpOnFingerprintChange {
+		enforcement.Allowed = false
+		enforcement.DenialReason = "Device fingerprint changed; step-up approval required"
+		enforcement.RequiredActions = append(enforcement.RequiredActions, "step_up_approval")
+		tm.logEnforcement(ctx, operation, req, enforcement, policy)
+		return enforcement, nil
+	}
+
 	// Check anomaly flags
-	if len(result.AnomalyFlags) > 0 && len(policy.AllowedAnomalies) == 0 {
+	if len(anomalies) > 0 && len(policy.AllowedAnomalies) == 0 {
 		enforcement.Allowed = false
-		enforcement.DenialReason = fmt.Sprintf("Anomalies detected: %v", result.AnomalyFlags)
+		enforcement.DenialReason = fmt.Sprintf("Anomalies detected: %v", anomalies)
 		tm.logEnforcement(ctx, operation, req, enforcement, policy)
 		return enforcement, nil
 	}
 
 	// Check for disallowed anomalies
-	for _, anomaly := range result.AnomalyFlags {
+	for _, anomaly := range anomalies {
 		allowed := false
 		for _, allowedAnomaly := range policy.AllowedAnomalies {
 			if anomaly == allowedAnomaly {
@@ -250,6 +279,7 @@ func (tm *TrustMiddleware) logEnforcement(ctx context.Context, operation string,
 			"trust_level", result.TrustLevel.String(),
 			"risk_score", result.RiskScore,
 			"session_id", result.SessionID,
+			"fingerprint_changed", result.FingerprintChanged,
 		)
 	} else {
 		tm.logger.Warn("trust_enforcement_denied",
@@ -259,6 +289,7 @@ func (tm *TrustMiddleware) logEnforcement(ctx context.Context, operation string,
 			"reason", result.DenialReason,
 			"session_id", result.SessionID,
 			"anomalies", result.Anomalies,
+			"fingerprint_changed", result.FingerprintChanged,
 		)
 	}
 
@@ -276,6 +307,8 @@ func (tm *TrustMiddleware) logEnforcement(ctx context.Context, operation string,
 		severity := "low"
 		if !result.Allowed {
 			severity = "high"
+		} else if result.FingerprintChanged {
+			severity = "medium"
 		}
 		compliance := audit.ComplianceFlags{
 			Category:      "trust_enforcement",
@@ -294,6 +327,7 @@ func (tm *TrustMiddleware) logEnforcement(ctx context.Context, operation string,
 			"risk_score":      result.RiskScore,
 			"denial_reason":   result.DenialReason,
 			"anomalies":       result.Anomalies,
+			"fingerprint_changed": result.FingerprintChanged,
 			"required_actions": result.RequiredActions,
 			"min_trust_required": policy.MinTrustLevel.String(),
 			"max_risk_allowed":   policy.MaxRiskScore,
@@ -0,0 +1,237 @@
+package trust
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armorclaw/bridge/pkg/logger"
+)
+
+// ErrLastTrustedSender is returned when removing a sender would empty the
+// allowlist. An empty allowlist trusts every sender, so the last entry can
+// only be removed through configuration.
+var ErrLastTrustedSender = errors.New("cannot remove the last trusted sender: an empty allowlist trusts every sender")
+
+// ErrInvalidSenderPattern is returned for a pattern that is not a Matrix
+// user ID or a supported wildcard
+var ErrInvalidSenderPattern = errors.New("invalid sender pattern")
+
+// SenderAllowlistTarget is the component that enforces the sender and room
+// allowlists, normally the Matrix adapter
+type SenderAllowlistTarget interface {
+	GetTrustedSenders() []string
+	SetTrustedSenders(senders []string)
+	GetTrustedRooms() []string
+}
+
+// SenderAllowlist manages the zero-trust sender allowlist at runtime.
+// Changes are applied to the target immediately and persisted as overrides
+// on top of the configured list, so they survive a restart and a sender
+// removed at runtime stays removed even if the config still lists it.
+type SenderAllowlist struct {
+	db          *sql.DB
+	target      SenderAllowlistTarget
+	securityLog *logger.SecurityLogger
+	mu          sync.Mutex
+}
+
+// NewSenderAllowlist opens a SenderAllowlist against db, creating the schema
+// if needed, and applies persisted overrides to the target's configured list
+func NewSenderAllowlist(db *sql.DB, target SenderAllowlistTarget) (*SenderAllowlist, error) {
+	a := &SenderAllowlist{
+		db:          db,
+		target:      target,
+		securityLog: logger.NewSecurityLogger(logger.Global().WithComponent("trust_allowlist")),
+	}
+	if err := a.initSchema(); err != nil {
+		return nil, fmt.Errorf("failed to initialize sender allowlist schema: %w", err)
+	}
+	if err := a.load(); err != nil {
+		return nil, fmt.Errorf("failed to load sender allowlist overrides: %w", err)
+	}
+	return a, nil
+}
+
+// initSchema creates the overrides table if it does not already exist
+func (a *SenderAllowlist) initSchema() error {
+	const ddl = `
+	CREATE TABLE IF NOT EXISTS trusted_sender_overrides (
+		pattern    TEXT PRIMARY KEY,
+		action     TEXT NOT NULL,
+		actor      TEXT,
+		updated_at INTEGER NOT NULL
+	);
+	`
+	_, err := a.db.Exec(ddl)
+	return err
+}
+
+// load merges persisted overrides into the target's configured senders
+func (a *SenderAllowlist) load() error {
+	rows, err := a.db.Query(`SELECT pattern, action FROM trusted_sender_overrides ORDER BY updated_at, pattern`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	configured := a.target.GetTrustedSenders()
+	senders := configured
+	for rows.Next() {
+		var pattern, action string
+		if err := rows.Scan(&pattern, &action); err != nil {
+			return err
+		}
+		switch action {
+		case "add":
+			senders = appendSender(senders, pattern)
+		case "remove":
+			senders = removeSender(senders, pattern)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// The config may have changed since the overrides were recorded; never
+	// let them empty the list, which would trust every sender
+	if len(senders) == 0 && len(configured) > 0 {
+		a.securityLog.LogSecurityEvent("trusted_sender_overrides_ignored",
+			slog.String("reason", "overrides would empty the sender allowlist"),
+			slog.Int("configured_senders", len(configured)))
+		return nil
+	}
+
+	a.target.SetTrustedSenders(senders)
+	return nil
+}
+
+// Add trusts a sender pattern. It reports false if the pattern was already
+// trusted. Adding the first sender turns filtering on: every other sender is
+// rejected from then on.
+func (a *SenderAllowlist) Add(pattern, actor string) (bool, error) {
+	pattern = strings.TrimSpace(pattern)
+	if err := ValidateSenderPattern(pattern); err != nil {
+		return false, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	senders := a.target.GetTrustedSenders()
+	if containsSender(senders, pattern) {
+		return false, nil
+	}
+	if err := a.persist(pattern, "add", actor); err != nil {
+		return false, err
+	}
+	a.target.SetTrustedSenders(appendSender(senders, pattern))
+
+	a.securityLog.LogSecurityEvent("trusted_sender_added",
+		slog.String("pattern", pattern),
+		slog.String("actor", actor),
+		slog.Int("trusted_senders", len(senders)+1))
+	return true, nil
+}
+
+// Remove stops trusting a sender pattern. It reports false if the pattern
+// was not trusted. The last pattern cannot be removed; see
+// ErrLastTrustedSender.
+func (a *SenderAllowlist) Remove(pattern, actor string) (bool, error) {
+	pattern = strings.TrimSpace(pattern)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	senders := a.target.GetTrustedSenders()
+	if !containsSender(senders, pattern) {
+		return false, nil
+	}
+	if len(senders) == 1 {
+		a.securityLog.LogAccessDenied(context.Background(), "trust.allowlist", actor, "would empty the sender allowlist",
+			slog.String("pattern", pattern))
+		return false, ErrLastTrustedSender
+	}
+	if err := a.persist(pattern, "remove", actor); err != nil {
+		return false, err
+	}
+	a.target.SetTrustedSenders(removeSender(senders, pattern))
+
+	a.securityLog.LogSecurityEvent("trusted_sender_removed",
+		slog.String("pattern", pattern),
+		slog.String("actor", actor),
+		slog.Int("trusted_senders", len(senders)-1))
+	return true, nil
+}
+
+// Senders returns the trusted sender patterns in effect
+func (a *SenderAllowlist) Senders() []string {
+	return a.target.GetTrustedSenders()
+}
+
+// Rooms returns the trusted rooms in effect
+func (a *SenderAllowlist) Rooms() []string {
+	return a.target.GetTrustedRooms()
+}
+
+// persist records the latest action for a pattern. Callers must hold a.mu.
+func (a *SenderAllowlist) persist(pattern, action, actor string) error {
+	_, err := a.db.Exec(`
+		INSERT OR REPLACE INTO trusted_sender_overrides (pattern, action, actor, updated_at)
+		VALUES (?, ?, ?, ?)
+	`, pattern, action, actor, time.Now().UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to persist sender allowlist change: %w", err)
+	}
+	return nil
+}
+
+// ValidateSenderPattern checks that a pattern is a Matrix user ID
+// (@user:domain.com) or a wildcard (*@domain.com, *:domain.com)
+func ValidateSenderPattern(pattern string) error {
+	switch {
+	case strings.HasPrefix(pattern, "*@"), strings.HasPrefix(pattern, "*:"):
+		if len(pattern) == 2 || strings.ContainsAny(pattern[2:], "*@ \t") {
+			return fmt.Errorf("%w %q: expected *@domain or *:domain", ErrInvalidSenderPattern, pattern)
+		}
+	case strings.HasPrefix(pattern, "@"):
+		localpart, server, ok := strings.Cut(pattern[1:], ":")
+		if !ok || localpart == "" || server == "" || strings.ContainsAny(pattern, "* \t") {
+			return fmt.Errorf("%w %q: expected @user:domain", ErrInvalidSenderPattern, pattern)
+		}
+	default:
+		return fmt.Errorf("%w %q: expected @user:domain, *@domain or *:domain", ErrInvalidSenderPattern, pattern)
+	}
+	return nil
+}
+
+func containsSender(senders []string, pattern string) bool {
+	for _, s := range senders {
+		if s == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func appendSender(senders []string, pattern string) []string {
+	if containsSender(senders, pattern) {
+		return senders
+	}
+	return append(append([]string{}, senders...), pattern)
+}
+
+func removeSender(senders []string, pattern string) []string {
+	out := make([]string, 0, len(senders))
+	for _, s := range senders {
+		if s != pattern {
+			out = append(out, s)
+		}
+	}
+	return out
+}
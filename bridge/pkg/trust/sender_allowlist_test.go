@@ -0,0 +1,136 @@
+package trust
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeAllowlistTarget stands in for the Matrix adapter
+type fakeAllowlistTarget struct {
+	mu      sync.Mutex
+	senders []string
+	rooms   []string
+}
+
+func (f *fakeAllowlistTarget) GetTrustedSenders() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.senders...)
+}
+
+func (f *fakeAllowlistTarget) SetTrustedSenders(senders []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.senders = senders
+}
+
+func (f *fakeAllowlistTarget) GetTrustedRooms() []string {
+	return append([]string{}, f.rooms...)
+}
+
+func openAllowlistDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "keystore.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestValidateSenderPattern(t *testing.T) {
+	valid := []string{"@alice:example.com", "*@example.com", "*:example.com", "*:example.com:8448"}
+	for _, p := range valid {
+		if err := ValidateSenderPattern(p); err != nil {
+			t.Errorf("ValidateSenderPattern(%q) error = %v", p, err)
+		}
+	}
+
+	invalid := []string{"", "alice", "@alice", "@:example.com", "@alice:", "*", "*@", "*:", "**@example.com", "@ali ce:example.com"}
+	for _, p := range invalid {
+		if err := ValidateSenderPattern(p); !errors.Is(err, ErrInvalidSenderPattern) {
+			t.Errorf("ValidateSenderPattern(%q) error = %v, want ErrInvalidSenderPattern", p, err)
+		}
+	}
+}
+
+func TestSenderAllowlistAddRemove(t *testing.T) {
+	target := &fakeAllowlistTarget{senders: []string{"@admin:example.com"}}
+	allowlist, err := NewSenderAllowlist(openAllowlistDB(t), target)
+	if err != nil {
+		t.Fatalf("NewSenderAllowlist() error = %v", err)
+	}
+
+	added, err := allowlist.Add(" @alice:example.com ", "@admin:example.com")
+	if err != nil || !added {
+		t.Fatalf("Add() = %v, %v; want added", added, err)
+	}
+	if added, _ := allowlist.Add("@alice:example.com", ""); added {
+		t.Error("Add() of an existing sender should report no change")
+	}
+	if got := strings.Join(target.GetTrustedSenders(), ","); got != "@admin:example.com,@alice:example.com" {
+		t.Errorf("target senders = %s", got)
+	}
+
+	removed, err := allowlist.Remove("@admin:example.com", "@alice:example.com")
+	if err != nil || !removed {
+		t.Fatalf("Remove() = %v, %v; want removed", removed, err)
+	}
+	if removed, _ := allowlist.Remove("@nobody:example.com", ""); removed {
+		t.Error("Remove() of an unknown sender should report no change")
+	}
+
+	// The last sender stays; removing it would trust everyone
+	if _, err := allowlist.Remove("@alice:example.com", ""); !errors.Is(err, ErrLastTrustedSender) {
+		t.Errorf("Remove() of the last sender error = %v, want ErrLastTrustedSender", err)
+	}
+	if got := strings.Join(allowlist.Senders(), ","); got != "@alice:example.com" {
+		t.Errorf("senders = %s, want @alice:example.com", got)
+	}
+}
+
+func TestSenderAllowlistPersistsOverrides(t *testing.T) {
+	db := openAllowlistDB(t)
+	configured := []string{"@admin:example.com", "@bob:example.com"}
+
+	first := &fakeAllowlistTarget{senders: append([]string{}, configured...)}
+	allowlist, err := NewSenderAllowlist(db, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowlist.Add("*:partner.org", "@admin:example.com")
+	allowlist.Remove("@bob:example.com", "@admin:example.com")
+
+	// A restart starts from the configured list again
+	second := &fakeAllowlistTarget{senders: append([]string{}, configured...)}
+	if _, err := NewSenderAllowlist(db, second); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(second.GetTrustedSenders(), ","); got != "@admin:example.com,*:partner.org" {
+		t.Errorf("senders after restart = %s, want runtime changes applied", got)
+	}
+}
+
+func TestSenderAllowlistOverridesNeverEmptyList(t *testing.T) {
+	db := openAllowlistDB(t)
+
+	first := &fakeAllowlistTarget{senders: []string{"@admin:example.com", "@bob:example.com"}}
+	allowlist, err := NewSenderAllowlist(db, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowlist.Remove("@bob:example.com", "")
+
+	// The config now lists only the removed sender
+	second := &fakeAllowlistTarget{senders: []string{"@bob:example.com"}}
+	if _, err := NewSenderAllowlist(db, second); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(second.GetTrustedSenders(), ","); got != "@bob:example.com" {
+		t.Errorf("senders = %q, want the configured list kept", got)
+	}
+}
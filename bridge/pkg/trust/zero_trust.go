@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	errsys "github.com/armorclaw/bridge/pkg/errors"
 	"github.com/armorclaw/bridge/pkg/securerandom"
 )
 
@@ -68,24 +69,37 @@ type ZeroTrustConfig struct {
 	// Verification interval for continuous mode
 	VerificationInterval time.Duration
 
+	// Similarity to a user's known devices below which a new device is
+	// flagged as a fingerprint anomaly (0-1, default 0.5; negative disables)
+	FingerprintSimilarityThreshold float64
+
+	// Error system for admin notifications; the global notifier is used
+	// if nil
+	ErrorSystem *errsys.System
+
 	// Logger
 	Logger *slog.Logger
 }
 
 // DeviceFingerprintData represents a device's unique identifier
 type DeviceFingerprintData struct {
-	ID                  string            `json:"id"`
-	Hash                string            `json:"hash"`
-	UserAgent           string            `json:"user_agent"`
-	Platform            string            `json:"platform"`
-	FirstSeen           time.Time         `json:"first_seen"`
-	LastSeen            time.Time         `json:"last_seen"`
-	TrustLevel          TrustScore        `json:"trust_level"`
-	VerificationCount   int               `json:"verification_count"`
-	FailedVerifications int               `json:"failed_verifications"`
+	ID                  string               `json:"id"`
+	Hash                string               `json:"hash"`
+	UserAgent           string               `json:"user_agent"`
+	Platform            string               `json:"platform"`
+	FirstSeen           time.Time            `json:"first_seen"`
+	LastSeen            time.Time            `json:"last_seen"`
+	TrustLevel          TrustScore           `json:"trust_level"`
+	VerificationCount   int                  `json:"verification_count"`
+	FailedVerifications int                  `json:"failed_verifications"`
 	KnownIPs            map[string]time.Time `json:"known_ips"`
-	Verified            bool              `json:"verified"`
-	VerificationMethod  string            `json:"verification_method,omitempty"`
+	Verified            bool                 `json:"verified"`
+	VerificationMethod  string               `json:"verification_method,omitempty"`
+
+	// Set when the device first appeared unlike any of the user's known
+	// devices; cleared when the device is verified
+	FingerprintAnomaly    bool    `json:"fingerprint_anomaly,omitempty"`
+	FingerprintSimilarity float64 `json:"fingerprint_similarity,omitempty"`
 }
 
 // TrustedSession represents a user session with trust information
@@ -152,13 +166,14 @@ type ZeroTrustVerifier interface {
 
 // ZeroTrustManager manages zero-trust verification
 type ZeroTrustManager struct {
-	config      ZeroTrustConfig
-	devices     map[string]*DeviceFingerprintData
-	sessions    map[string]*TrustedSession
-	userDevices map[string]map[string]bool
-	mu          sync.RWMutex
-	logger      *slog.Logger
-	verifiers   []ZeroTrustVerifier
+	config       ZeroTrustConfig
+	devices      map[string]*DeviceFingerprintData
+	sessions     map[string]*TrustedSession
+	userDevices  map[string]map[string]bool
+	fingerprints map[string][]fingerprintRecord
+	mu           sync.RWMutex
+	logger       *slog.Logger
+	verifiers    []ZeroTrustVerifier
 }
 
 // NewZeroTrustManager creates a new zero-trust manager
@@ -191,13 +206,18 @@ func NewZeroTrustManager(config ZeroTrustConfig) *ZeroTrustManager {
 		config.VerificationInterval = 5 * time.Minute
 	}
 
+	if config.FingerprintSimilarityThreshold == 0 {
+		config.FingerprintSimilarityThreshold = DefaultFingerprintSimilarityThreshold
+	}
+
 	return &ZeroTrustManager{
-		config:      config,
-		devices:     make(map[string]*DeviceFingerprintData),
-		sessions:    make(map[string]*TrustedSession),
-		userDevices: make(map[string]map[string]bool),
-		logger:      config.Logger,
-		verifiers:   make([]ZeroTrustVerifier, 0),
+		config:       config,
+		devices:      make(map[string]*DeviceFingerprintData),
+		sessions:     make(map[string]*TrustedSession),
+		userDevices:  make(map[string]map[string]bool),
+		fingerprints: make(map[string][]fingerprintRecord),
+		logger:       config.Logger,
+		verifiers:    make([]ZeroTrustVerifier, 0),
 	}
 }
 
@@ -210,6 +230,14 @@ func (m *ZeroTrustManager) AddVerifier(verifier ZeroTrustVerifier) {
 
 // Verify performs trust verification
 func (m *ZeroTrustManager) Verify(ctx context.Context, req *ZeroTrustRequest) (*ZeroTrustResult, error) {
+	// Notify after the lock is released; delivery may be slow
+	var alert *errsys.TracedError
+	defer func() {
+		if alert != nil {
+			m.notifyFingerprintAnomaly(ctx, alert)
+		}
+	}()
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -229,6 +257,7 @@ func (m *ZeroTrustManager) Verify(ctx context.Context, req *ZeroTrustRequest) (*
 	}
 
 	device := m.getOrCreateDevice(req.UserID, &req.DeviceFingerprint)
+	alert = m.checkFingerprint(req.UserID, device, &req.DeviceFingerprint)
 
 	if device.KnownIPs == nil {
 		device.KnownIPs = make(map[string]time.Time)
@@ -484,6 +513,10 @@ func (m *ZeroTrustManager) detectAnomalies(session *TrustedSession, device *Devi
 		anomalies = append(anomalies, "multiple_failed_verifications")
 	}
 
+	if device.FingerprintAnomaly && !device.Verified {
+		anomalies = append(anomalies, AnomalyFingerprintChange)
+	}
+
 	return anomalies
 }
 
@@ -500,6 +533,7 @@ func (m *ZeroTrustManager) VerifyDevice(deviceID, method string) error {
 	device.Verified = true
 	device.VerificationMethod = method
 	device.TrustLevel = TrustScoreVerified
+	device.FingerprintAnomaly = false
 
 	m.logger.Info("device_verified",
 		"device_id", deviceID,
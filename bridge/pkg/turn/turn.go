@@ -10,6 +10,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -49,6 +50,26 @@ type ServerConfig struct {
 	Realm    string // TURN realm (for auth)
 }
 
+// URLs returns the TURN and STUN URLs clients use to reach the server
+func (s ServerConfig) URLs() (turnURL, stunURL string) {
+	hostPort := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	switch s.Protocol {
+	case "tcp":
+		turnURL = "turn:" + hostPort + "?transport=tcp"
+	case "tls":
+		turnURL = "turns:" + hostPort
+	default:
+		turnURL = "turn:" + hostPort
+	}
+	return turnURL, "stun:" + hostPort
+}
+
+// Default ports for TURN over UDP/TCP and over TLS
+const (
+	DefaultTURNPort  = 3478
+	DefaultTURNSPort = 5349
+)
+
 // DefaultConfig returns default TURN configuration
 func DefaultConfig() Config {
 	return Config{
@@ -95,7 +116,62 @@ func NewManager(config Config) (*Manager, error) {
 	}, nil
 }
 
-// GenerateTURNCredentials creates ephemeral TURN credentials for a session
+// ParseServerURL parses a TURN URL into a ServerConfig. It accepts
+// turn:host[:port][?transport=udp|tcp], turns:host[:port] and a bare
+// host[:port]. The port defaults to 3478, or 5349 for turns.
+func ParseServerURL(rawURL string) (ServerConfig, error) {
+	rest := strings.TrimSpace(rawURL)
+	server := ServerConfig{Protocol: "udp", Realm: "armorclaw"}
+	port := DefaultTURNPort
+
+	switch {
+	case strings.HasPrefix(rest, "turns:"):
+		rest = strings.TrimPrefix(rest, "turns:")
+		server.Protocol = "tls"
+		port = DefaultTURNSPort
+	case strings.HasPrefix(rest, "turn:"):
+		rest = strings.TrimPrefix(rest, "turn:")
+	case strings.HasPrefix(rest, "stun:"), strings.HasPrefix(rest, "stuns:"):
+		return ServerConfig{}, fmt.Errorf("%w: %q is a STUN URL", ErrTURNInvalidURL, rawURL)
+	}
+
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		query, err := url.ParseQuery(rest[i+1:])
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf("%w: %q: %v", ErrTURNInvalidURL, rawURL, err)
+		}
+		rest = rest[:i]
+		switch transport := query.Get("transport"); transport {
+		case "", "udp":
+		case "tcp":
+			if server.Protocol != "tls" {
+				server.Protocol = "tcp"
+			}
+		default:
+			return ServerConfig{}, fmt.Errorf("%w: %q: unknown transport %q", ErrTURNInvalidURL, rawURL, transport)
+		}
+	}
+
+	host := rest
+	if h, p, err := net.SplitHostPort(rest); err == nil {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 1 || n > 65535 {
+			return ServerConfig{}, fmt.Errorf("%w: %q: invalid port %q", ErrTURNInvalidURL, rawURL, p)
+		}
+		host, port = h, n
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if host == "" {
+		return ServerConfig{}, fmt.Errorf("%w: %q: missing host", ErrTURNInvalidURL, rawURL)
+	}
+
+	server.Host = host
+	server.Port = port
+	return server, nil
+}
+
+// GenerateTURNCredentials creates ephemeral TURN credentials for a session,
+// one per configured server in priority order
 // Format: username = <expiry>:<session_id>, password = HMAC(secret, username)
 func (m *Manager) GenerateTURNCredentials(sessionID string, ttl time.Duration) ([]TURNCredentials, error) {
 	// Validate TTL
@@ -120,16 +196,8 @@ func (m *Manager) GenerateTURNCredentials(sessionID string, ttl time.Duration) (
 		// Generate password: HMAC(secret, username)
 		password := m.hmac(username)
 
-		// Create TURN server URL
-		turnURL := fmt.Sprintf("turn:%s:%d", server.Host, server.Port)
-		if server.Protocol == "tcp" {
-			turnURL = fmt.Sprintf("turn:%s:%d?transport=tcp", server.Host, server.Port)
-		} else if server.Protocol == "tls" {
-			turnURL = fmt.Sprintf("turns:%s:%d", server.Host, server.Port)
-		}
-
-		// Create STUN server URL
-		stunURL := fmt.Sprintf("stun:%s:%d", server.Host, server.Port)
+		// Create TURN and STUN server URLs
+		turnURL, stunURL := server.URLs()
 
 		cred := TURNCredentials{
 			Username:   username,
@@ -153,6 +221,16 @@ func (m *Manager) GenerateTURNCredentials(sessionID string, ttl time.Duration) (
 	return creds, nil
 }
 
+// RefreshCredentials issues new credentials for a session before its current
+// ones expire. The earlier credentials stay valid until their own expiry, so
+// the existing allocation keeps working while ICE renominates.
+func (m *Manager) RefreshCredentials(sessionID string) ([]TURNCredentials, error) {
+	if sessionID == "" {
+		return nil, ErrTURNSessionRequired
+	}
+	return m.GenerateTURNCredentials(sessionID, m.config.DefaultTTL)
+}
+
 // ValidateTURNCredentials validates TURN credentials
 func (m *Manager) ValidateTURNCredentials(username, password string) (string, error) {
 	// Look up credential
@@ -454,7 +532,7 @@ func CreateICEServers(config Config, sessionID string, ttl time.Duration) ([]ICE
 
 	// Add STUN servers
 	for _, server := range config.Servers {
-		stunURL := fmt.Sprintf("stun:%s:%d", server.Host, server.Port)
+		_, stunURL := server.URLs()
 		servers = append(servers, ICEServer{
 			URLs: []string{stunURL},
 		})
@@ -494,14 +572,7 @@ func createTurnCreds(config Config, sessionID string, ttl time.Duration) ([]TURN
 		password := hmacString(config.Secret, username)
 
 		// Create server URLs
-		turnURL := fmt.Sprintf("turn:%s:%d", server.Host, server.Port)
-		if server.Protocol == "tcp" {
-			turnURL = fmt.Sprintf("turn:%s:%d?transport=tcp", server.Host, server.Port)
-		} else if server.Protocol == "tls" {
-			turnURL = fmt.Sprintf("turns:%s:%d", server.Host, server.Port)
-		}
-
-		stunURL := fmt.Sprintf("stun:%s:%d", server.Host, server.Port)
+		turnURL, stunURL := server.URLs()
 
 		cred := TURNCredentials{
 			Username:   username,
@@ -837,6 +908,12 @@ var (
 	// ErrTURNInvalidFormat is returned for malformed TURN credentials
 	ErrTURNInvalidFormat = fmt.Errorf("malformed TURN credentials format")
 
+	// ErrTURNInvalidURL is returned for a TURN server URL that cannot be parsed
+	ErrTURNInvalidURL = fmt.Errorf("invalid TURN server URL")
+
+	// ErrTURNSessionRequired is returned when refreshing without a session ID
+	ErrTURNSessionRequired = fmt.Errorf("session ID is required")
+
 	// ErrSTUNNotSupported is returned when STUN is not supported
 	ErrSTUNNotSupported = fmt.Errorf("STUN not supported")
 
@@ -120,6 +120,125 @@ func TestValidateTURNCredentials(t *testing.T) {
 	}
 }
 
+// TestRefreshCredentials tests issuing new credentials for a live session
+func TestRefreshCredentials(t *testing.T) {
+	config := Config{
+		Servers: []ServerConfig{
+			{Host: "turn.example.com", Port: 3478, Protocol: "udp"},
+		},
+		Secret:     "test-secret",
+		DefaultTTL: 10 * time.Minute,
+		MaxTTL:     1 * time.Hour,
+	}
+
+	manager, err := NewManager(config)
+	if err != nil {
+		t.Fatalf("NewManager() failed: %v", err)
+	}
+	defer manager.Stop()
+
+	sessionID := "test-session-123"
+	old, _ := manager.GenerateTURNCredentials(sessionID, 2*time.Minute)
+
+	fresh, err := manager.RefreshCredentials(sessionID)
+	if err != nil {
+		t.Fatalf("Failed to refresh credentials: %v", err)
+	}
+	if len(fresh) != 1 {
+		t.Fatalf("Expected 1 credential, got %d", len(fresh))
+	}
+	if fresh[0].Username == old[0].Username {
+		t.Error("Refreshed credentials should have a new username")
+	}
+	if !fresh[0].Expires.After(old[0].Expires) {
+		t.Errorf("Refreshed credentials should outlive the old ones: %v <= %v", fresh[0].Expires, old[0].Expires)
+	}
+
+	// Both sets validate, so the call survives the switch
+	for _, cred := range []TURNCredentials{old[0], fresh[0]} {
+		got, err := manager.ValidateTURNCredentials(cred.Username, cred.Password)
+		if err != nil || got != sessionID {
+			t.Errorf("Expected %s to validate for %s, got %q, %v", cred.Username, sessionID, got, err)
+		}
+	}
+
+	if _, err := manager.RefreshCredentials(""); err != ErrTURNSessionRequired {
+		t.Errorf("Expected ErrTURNSessionRequired, got %v", err)
+	}
+}
+
+// TestParseServerURL tests parsing TURN URLs from configuration
+func TestParseServerURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		host     string
+		port     int
+		protocol string
+	}{
+		{"turn:turn.example.com:3478", "turn.example.com", 3478, "udp"},
+		{"turn:turn.example.com", "turn.example.com", DefaultTURNPort, "udp"},
+		{"turn:turn.example.com:443?transport=tcp", "turn.example.com", 443, "tcp"},
+		{"turns:turn.example.com", "turn.example.com", DefaultTURNSPort, "tls"},
+		{"turn:[2001:db8::1]:3478", "2001:db8::1", 3478, "udp"},
+		{"turn.example.com:3479", "turn.example.com", 3479, "udp"},
+	}
+	for _, tt := range tests {
+		server, err := ParseServerURL(tt.url)
+		if err != nil {
+			t.Errorf("ParseServerURL(%q) failed: %v", tt.url, err)
+			continue
+		}
+		if server.Host != tt.host || server.Port != tt.port || server.Protocol != tt.protocol {
+			t.Errorf("ParseServerURL(%q) = %+v, want %s:%d/%s", tt.url, server, tt.host, tt.port, tt.protocol)
+		}
+	}
+
+	for _, bad := range []string{"", "turn:", "stun:stun.example.com", "turn:host:99999", "turn:host?transport=sctp"} {
+		if _, err := ParseServerURL(bad); err == nil {
+			t.Errorf("ParseServerURL(%q) should fail", bad)
+		}
+	}
+}
+
+// TestGenerateTURNCredentialsMultipleServers tests credentials keep server order
+func TestGenerateTURNCredentialsMultipleServers(t *testing.T) {
+	config := Config{
+		Servers: []ServerConfig{
+			{Host: "turn1.example.com", Port: 3478, Protocol: "udp"},
+			{Host: "turn2.example.com", Port: 443, Protocol: "tcp"},
+			{Host: "2001:db8::1", Port: 5349, Protocol: "tls"},
+		},
+		Secret:     "test-secret",
+		DefaultTTL: 10 * time.Minute,
+		MaxTTL:     1 * time.Hour,
+	}
+
+	manager, err := NewManager(config)
+	if err != nil {
+		t.Fatalf("NewManager() failed: %v", err)
+	}
+	defer manager.Stop()
+
+	creds, err := manager.GenerateTURNCredentials("test-session", 0)
+	if err != nil {
+		t.Fatalf("Failed to generate credentials: %v", err)
+	}
+
+	want := []string{
+		"turn:turn1.example.com:3478",
+		"turn:turn2.example.com:443?transport=tcp",
+		"turns:[2001:db8::1]:5349",
+	}
+	if len(creds) != len(want) {
+		t.Fatalf("Expected %d credentials, got %d", len(want), len(creds))
+	}
+	for i, cred := range creds {
+		if cred.TURNServer != want[i] {
+			t.Errorf("Credential %d: expected %s, got %s", i, want[i], cred.TURNServer)
+		}
+	}
+}
+
 // TestTURNCredentialsExpiry tests TURN credential expiration
 func TestTURNCredentialsExpiry(t *testing.T) {
 	config := Config{
@@ -3,6 +3,7 @@ package webrtc
 import (
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -80,6 +81,7 @@ type PeerConnectionWrapper struct {
 	onICECandidate func(candidate *webrtc.ICECandidate)
 	onTrack       func(track *webrtc.TrackRemote)
 	onDataChannel func(dc *webrtc.DataChannel)
+	maxBitrateKbps int // Opus bitrate cap advertised in answers; 0 means none
 	closeOnce     sync.Once
 	closed        bool
 }
@@ -117,8 +119,84 @@ func NewEngine(config EngineConfig) (*Engine, error) {
 	}, nil
 }
 
+// Audio codecs that can be requested per session
+const (
+	CodecOpus = "opus"
+	CodecPCMU = "pcmu"
+)
+
+// Opus bitrate bounds (RFC 7587 maxaveragebitrate range)
+const (
+	MinOpusBitrateKbps = 6
+	MaxOpusBitrateKbps = 510
+)
+
+// PeerConnectionOptions constrains the media negotiated for one peer
+// connection. The zero value keeps the engine defaults: Opus with no
+// bitrate cap.
+type PeerConnectionOptions struct {
+	Codec          string // CodecOpus or CodecPCMU; empty means Opus
+	MaxBitrateKbps int    // Opus maxaveragebitrate in kbps; 0 means no cap
+}
+
+// Validate checks that the options name a supported codec and a usable bitrate
+func (o PeerConnectionOptions) Validate() error {
+	switch o.Codec {
+	case "", CodecOpus:
+		if o.MaxBitrateKbps != 0 && (o.MaxBitrateKbps < MinOpusBitrateKbps || o.MaxBitrateKbps > MaxOpusBitrateKbps) {
+			return fmt.Errorf("max_bitrate_kbps must be between %d and %d", MinOpusBitrateKbps, MaxOpusBitrateKbps)
+		}
+	case CodecPCMU:
+		if o.MaxBitrateKbps != 0 {
+			return fmt.Errorf("max_bitrate_kbps is only supported with opus")
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedCodec, o.Codec)
+	}
+	return nil
+}
+
+// audioCodec returns the codec parameters for the given options
+func (e *Engine) audioCodec(opts PeerConnectionOptions) webrtc.RTPCodecParameters {
+	if opts.Codec == CodecPCMU {
+		return webrtc.RTPCodecParameters{
+			RTPCodecCapability: webrtc.RTPCodecCapability{
+				MimeType:  webrtc.MimeTypePCMU,
+				ClockRate: 8000,
+				Channels:  1,
+			},
+			PayloadType: 0,
+		}
+	}
+
+	fmtp := "minptime=10;useinbandfec=1"
+	if opts.MaxBitrateKbps > 0 {
+		fmtp += fmt.Sprintf(";maxaveragebitrate=%d", opts.MaxBitrateKbps*1000)
+	}
+	return webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{
+			MimeType:    webrtc.MimeTypeOpus,
+			ClockRate:   e.config.MediaConfig.SampleRate,
+			Channels:    e.config.MediaConfig.Channels,
+			SDPFmtpLine: fmtp,
+		},
+		PayloadType: e.config.MediaConfig.PayloadType,
+	}
+}
+
 // CreatePeerConnection creates a new WebRTC peer connection for a session
 func (e *Engine) CreatePeerConnection(sessionID string) (*PeerConnectionWrapper, error) {
+	return e.CreatePeerConnectionWithOptions(sessionID, PeerConnectionOptions{})
+}
+
+// CreatePeerConnectionWithOptions creates a peer connection whose audio is
+// limited to the requested codec and bitrate. Non-default options get their
+// own media engine so only that codec is offered in the answer.
+func (e *Engine) CreatePeerConnectionWithOptions(sessionID string, opts PeerConnectionOptions) (*PeerConnectionWrapper, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -127,20 +205,28 @@ func (e *Engine) CreatePeerConnection(sessionID string) (*PeerConnectionWrapper,
 		return nil, fmt.Errorf("peer connection already exists for session: %s", sessionID)
 	}
 
+	codec := e.audioCodec(opts)
+	api := e.mediaAPI
+	if opts != (PeerConnectionOptions{}) {
+		mediaEngine := &webrtc.MediaEngine{}
+		if err := mediaEngine.RegisterCodec(codec, webrtc.RTPCodecTypeAudio); err != nil {
+			return nil, fmt.Errorf("failed to register codec: %w", err)
+		}
+		api = webrtc.NewAPI(
+			webrtc.WithMediaEngine(mediaEngine),
+			webrtc.WithSettingEngine(webrtc.SettingEngine{}),
+		)
+	}
+
 	// Create peer connection
-	peerConnection, err := e.mediaAPI.NewPeerConnection(e.config.Configuration)
+	peerConnection, err := api.NewPeerConnection(e.config.Configuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create peer connection: %w", err)
 	}
 
 	// Create local audio track for sending audio to the client
 	audioTrack, err := webrtc.NewTrackLocalStaticSample(
-		webrtc.RTPCodecCapability{
-			MimeType:    webrtc.MimeTypeOpus,
-			ClockRate:   e.config.MediaConfig.SampleRate,
-			Channels:    e.config.MediaConfig.Channels,
-			SDPFmtpLine: "minptime=10;useinbandfec=1",
-		},
+		codec.RTPCodecCapability,
 		"audio",
 		"armorclaw-audio",
 	)
@@ -158,9 +244,10 @@ func (e *Engine) CreatePeerConnection(sessionID string) (*PeerConnectionWrapper,
 
 	// Create wrapper
 	wrapper := &PeerConnectionWrapper{
-		pc:         peerConnection,
-		sessionID:  sessionID,
-		audioTrack: audioTrack,
+		pc:             peerConnection,
+		sessionID:      sessionID,
+		audioTrack:     audioTrack,
+		maxBitrateKbps: opts.MaxBitrateKbps,
 	}
 
 	// Set up ICE candidate handler
@@ -288,6 +375,96 @@ func (pcw *PeerConnectionWrapper) CreateAnswer(offerSDP string) (string, error)
 	return answer.SDP, nil
 }
 
+// AnswerOffer applies a client's SDP offer and returns the local answer once
+// ICE gathering has finished, so the answer carries the bridge's candidates
+// and the client does not need to trickle them. If gathering takes longer than
+// timeout the answer is returned with the candidates found so far.
+func (pcw *PeerConnectionWrapper) AnswerOffer(offerSDP string, timeout time.Duration) (string, error) {
+	if pcw.closed {
+		return "", ErrPeerConnectionClosed
+	}
+
+	offer := webrtc.SessionDescription{
+		Type: webrtc.SDPTypeOffer,
+		SDP:  offerSDP,
+	}
+	if err := pcw.pc.SetRemoteDescription(offer); err != nil {
+		return "", fmt.Errorf("failed to set remote description: %w", err)
+	}
+
+	answer, err := pcw.pc.CreateAnswer(nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create answer: %w", err)
+	}
+
+	// The promise must be created before SetLocalDescription starts gathering
+	gatherComplete := webrtc.GatheringCompletePromise(pcw.pc)
+	if err := pcw.pc.SetLocalDescription(answer); err != nil {
+		return "", fmt.Errorf("failed to set local description: %w", err)
+	}
+
+	select {
+	case <-gatherComplete:
+	case <-time.After(timeout):
+	}
+
+	local := pcw.pc.LocalDescription()
+	if local == nil {
+		return "", fmt.Errorf("local description not set")
+	}
+
+	// Answer fmtp lines mirror the offer and pion rejects a munged local
+	// description, so the cap is only added to the copy sent to the client.
+	// It is a receive preference for the client's encoder; our side ignores it.
+	if pcw.maxBitrateKbps > 0 {
+		return capOpusBitrate(local.SDP, pcw.maxBitrateKbps), nil
+	}
+	return local.SDP, nil
+}
+
+// capOpusBitrate adds maxaveragebitrate to every Opus payload in an SDP,
+// asking the remote side to keep its audio under the cap
+func capOpusBitrate(sdp string, kbps int) string {
+	param := fmt.Sprintf("maxaveragebitrate=%d", kbps*1000)
+	lines := strings.Split(sdp, "\r\n")
+
+	opus := make(map[string]bool)
+	hasFmtp := make(map[string]bool)
+	for _, line := range lines {
+		if rest, ok := strings.CutPrefix(line, "a=rtpmap:"); ok {
+			if pt, codec, found := strings.Cut(rest, " "); found && strings.HasPrefix(strings.ToLower(codec), "opus/") {
+				opus[pt] = true
+			}
+		}
+		if rest, ok := strings.CutPrefix(line, "a=fmtp:"); ok {
+			pt, _, _ := strings.Cut(rest, " ")
+			hasFmtp[pt] = true
+		}
+	}
+
+	out := make([]string, 0, len(lines)+len(opus))
+	for _, line := range lines {
+		if rest, ok := strings.CutPrefix(line, "a=fmtp:"); ok {
+			pt, params, _ := strings.Cut(rest, " ")
+			if opus[pt] && !strings.Contains(params, "maxaveragebitrate=") {
+				if params == "" {
+					line = "a=fmtp:" + pt + " " + param
+				} else {
+					line += ";" + param
+				}
+			}
+		}
+		out = append(out, line)
+		if rest, ok := strings.CutPrefix(line, "a=rtpmap:"); ok {
+			pt, _, _ := strings.Cut(rest, " ")
+			if opus[pt] && !hasFmtp[pt] {
+				out = append(out, "a=fmtp:"+pt+" "+param)
+			}
+		}
+	}
+	return strings.Join(out, "\r\n")
+}
+
 // SetRemoteDescription sets the remote SDP description
 func (pcw *PeerConnectionWrapper) SetRemoteDescription(sdpType, sdp string) error {
 	var sd webrtc.SessionDescription
@@ -346,6 +523,11 @@ func (pcw *PeerConnectionWrapper) OnDataChannel(handler func(*webrtc.DataChannel
 	pcw.onDataChannel = handler
 }
 
+// OnConnectionStateChange sets the handler for peer connection state changes
+func (pcw *PeerConnectionWrapper) OnConnectionStateChange(handler func(webrtc.PeerConnectionState)) {
+	pcw.pc.OnConnectionStateChange(handler)
+}
+
 // ConnectionState returns the current connection state
 func (pcw *PeerConnectionWrapper) ConnectionState() webrtc.PeerConnectionState {
 	return pcw.pc.ConnectionState()
@@ -437,4 +619,7 @@ var (
 
 	// ErrPeerConnectionClosed is returned when operating on a closed connection
 	ErrPeerConnectionClosed = fmt.Errorf("peer connection is closed")
+
+	// ErrUnsupportedCodec is returned when a session requests an unknown codec
+	ErrUnsupportedCodec = fmt.Errorf("unsupported codec")
 )
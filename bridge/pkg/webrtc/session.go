@@ -5,9 +5,12 @@ package webrtc
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/armorclaw/bridge/pkg/eventbus"
 	"github.com/armorclaw/bridge/pkg/turn"
 )
 
@@ -126,6 +129,8 @@ type SessionManager struct {
 	config   SessionConfig
 	stopChan chan struct{}
 	wg       sync.WaitGroup
+	eventBus atomic.Pointer[eventbus.EventBus] // Optional; receives state transitions
+	onExpire atomic.Pointer[func(*Session)]    // Optional; releases resources of expired sessions
 }
 
 // NewSessionManager creates a new session manager with the given configuration
@@ -133,6 +138,16 @@ func NewSessionManager(config SessionConfig) *SessionManager {
 	if config.DefaultTTL == 0 {
 		config = DefaultSessionConfig()
 	}
+	defaults := DefaultSessionConfig()
+	if config.MaxTTL <= 0 {
+		config.MaxTTL = defaults.MaxTTL
+	}
+	if config.DefaultTTL > config.MaxTTL {
+		config.DefaultTTL = config.MaxTTL
+	}
+	if config.CleanupInterval <= 0 {
+		config.CleanupInterval = defaults.CleanupInterval
+	}
 
 	sm := &SessionManager{
 		sessions: sync.Map{},
@@ -147,16 +162,52 @@ func NewSessionManager(config SessionConfig) *SessionManager {
 	return sm
 }
 
-// Create creates a new session and adds it to the manager
-func (sm *SessionManager) Create(containerID, roomID string, ttl time.Duration) (*Session, error) {
-	// Validate TTL
-	if ttl <= 0 {
-		ttl = sm.config.DefaultTTL
+// SetEventBus publishes session state transitions to bus so clients can
+// follow call status live. Passing nil stops publishing.
+func (sm *SessionManager) SetEventBus(bus *eventbus.EventBus) {
+	sm.eventBus.Store(bus)
+}
+
+// SetExpiryHandler registers fn to run after a session expires, so resources
+// tied to it such as the peer connection are released at the TTL.
+func (sm *SessionManager) SetExpiryHandler(fn func(*Session)) {
+	if fn == nil {
+		sm.onExpire.Store(nil)
+		return
 	}
-	if ttl > sm.config.MaxTTL {
-		ttl = sm.config.MaxTTL
+	sm.onExpire.Store(&fn)
+}
+
+// EffectiveTTL returns the TTL a session created with the requested TTL gets:
+// the default when none is requested, and never more than MaxTTL
+func (sm *SessionManager) EffectiveTTL(requested time.Duration) time.Duration {
+	if requested <= 0 {
+		requested = sm.config.DefaultTTL
+	}
+	if requested > sm.config.MaxTTL {
+		requested = sm.config.MaxTTL
+	}
+	return requested
+}
+
+// publishStateChange emits a state transition if an event bus is configured.
+// oldState is empty for a newly created session.
+func (sm *SessionManager) publishStateChange(session *Session, oldState string, newState SessionState) {
+	bus := sm.eventBus.Load()
+	if bus == nil {
+		return
 	}
 
+	event := eventbus.NewWebRTCSessionStateEvent(session.ID, session.RoomID, oldState, newState.String())
+	if err := bus.PublishBridgeEvent(event); err != nil {
+		slog.Warn("webrtc_session_state_publish_failed", "session_id", session.ID, "error", err)
+	}
+}
+
+// Create creates a new session and adds it to the manager
+func (sm *SessionManager) Create(containerID, roomID string, ttl time.Duration) (*Session, error) {
+	ttl = sm.EffectiveTTL(ttl)
+
 	// Generate session ID
 	sessionID := generateSessionID()
 
@@ -178,6 +229,13 @@ func (sm *SessionManager) Create(containerID, roomID string, ttl time.Duration)
 
 	// Store session
 	sm.sessions.Store(sessionID, session)
+	sm.publishStateChange(session, "", SessionPending)
+
+	// Expire exactly at the TTL rather than on the next cleanup tick. If the
+	// session ends first, expire finds nothing to do.
+	time.AfterFunc(ttl, func() {
+		sm.expire(session)
+	})
 
 	// Emit session created event (will be logged by caller)
 	// logger.LogSecurityEvent("session_created", map[string]interface{}{
@@ -205,9 +263,14 @@ func (sm *SessionManager) UpdateState(sessionID string, state SessionState) erro
 		return ErrSessionNotFound
 	}
 
+	oldState := session.State
 	session.State = state
 	session.MarkActivity()
 
+	if oldState != state {
+		sm.publishStateChange(session, oldState.String(), state)
+	}
+
 	// Emit state change event
 	// logger.LogSecurityEvent("session_state_changed", map[string]interface{}{
 	// 	"session_id": sessionID,
@@ -226,6 +289,7 @@ func (sm *SessionManager) End(sessionID string) error {
 	}
 
 	// Update state
+	oldState := session.State
 	session.State = SessionEnded
 
 	// Close the session
@@ -233,6 +297,7 @@ func (sm *SessionManager) End(sessionID string) error {
 
 	// Remove from sessions map
 	sm.sessions.Delete(sessionID)
+	sm.publishStateChange(session, oldState.String(), SessionEnded)
 
 	// Emit session ended event
 	// logger.LogSecurityEvent("session_ended", map[string]interface{}{
@@ -252,6 +317,7 @@ func (sm *SessionManager) Fail(sessionID string, reason string) error {
 	}
 
 	// Update state
+	oldState := session.State
 	session.State = SessionFailed
 
 	// Close the session
@@ -259,6 +325,7 @@ func (sm *SessionManager) Fail(sessionID string, reason string) error {
 
 	// Remove from sessions map
 	sm.sessions.Delete(sessionID)
+	sm.publishStateChange(session, oldState.String(), SessionFailed)
 
 	// Emit session failed event
 	// logger.LogSecurityEvent("session_failed", map[string]interface{}{
@@ -294,22 +361,36 @@ func (sm *SessionManager) cleanupExpired() {
 		session := value.(*Session)
 
 		if now.After(session.ExpiresAt) {
-			// Session has expired
-			session.State = SessionExpired
-			session.Close()
-			sm.sessions.Delete(key)
-
-			// Emit session expired event
-			// logger.LogSecurityEvent("session_expired", map[string]interface{}{
-			// 	"session_id": session.ID,
-			// 	"container_id": session.ContainerID,
-			// })
+			sm.expire(session)
 		}
 
 		return true
 	})
 }
 
+// expire terminates a session that reached its TTL. The cleanup loop is a
+// backstop for the per-session timer, so only the first caller acts.
+func (sm *SessionManager) expire(session *Session) {
+	if _, loaded := sm.sessions.LoadAndDelete(session.ID); !loaded {
+		return
+	}
+
+	oldState := session.State
+	session.State = SessionExpired
+	session.Close()
+	sm.publishStateChange(session, oldState.String(), SessionExpired)
+
+	if fn := sm.onExpire.Load(); fn != nil {
+		(*fn)(session)
+	}
+
+	// Emit session expired event
+	// logger.LogSecurityEvent("session_expired", map[string]interface{}{
+	// 	"session_id": session.ID,
+	// 	"container_id": session.ContainerID,
+	// })
+}
+
 // Stop stops the session manager and cleans up all sessions
 func (sm *SessionManager) Stop() {
 	close(sm.stopChan)
@@ -1,8 +1,11 @@
 package webrtc
 
 import (
+	"sort"
 	"testing"
 	"time"
+
+	"github.com/armorclaw/bridge/pkg/eventbus"
 )
 
 // TestSessionManager_Create tests creating a new session
@@ -313,6 +316,63 @@ func TestSessionManager_TTLValidation(t *testing.T) {
 	}
 }
 
+// TestSessionManager_ExpiresAtTTL tests that sessions expire at their TTL
+// without waiting for the cleanup loop
+func TestSessionManager_ExpiresAtTTL(t *testing.T) {
+	config := SessionConfig{
+		DefaultTTL:      time.Minute,
+		MaxTTL:          50 * time.Millisecond,
+		CleanupInterval: time.Hour,
+	}
+
+	sm := NewSessionManager(config)
+	defer sm.Stop()
+
+	expired := make(chan string, 1)
+	sm.SetExpiryHandler(func(s *Session) {
+		expired <- s.ID
+	})
+
+	if ttl := sm.EffectiveTTL(time.Hour); ttl != 50*time.Millisecond {
+		t.Errorf("Expected TTL clamped to 50ms, got %v", ttl)
+	}
+	if ttl := sm.EffectiveTTL(0); ttl != 50*time.Millisecond {
+		t.Errorf("Expected default TTL clamped to 50ms, got %v", ttl)
+	}
+
+	session, err := sm.Create("test-container", "!testRoom:example.com", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	select {
+	case id := <-expired:
+		if id != session.ID {
+			t.Errorf("Expected expiry of %s, got %s", session.ID, id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Session was not expired at its TTL")
+	}
+
+	if _, ok := sm.Get(session.ID); ok {
+		t.Error("Expired session should be removed")
+	}
+	if session.State != SessionExpired {
+		t.Errorf("Expected state expired, got %s", session.State)
+	}
+
+	// Sessions ended before their TTL are not expired later
+	ended, _ := sm.Create("test-container", "!testRoom:example.com", 0)
+	if err := sm.End(ended.ID); err != nil {
+		t.Fatalf("Failed to end session: %v", err)
+	}
+	select {
+	case id := <-expired:
+		t.Errorf("Ended session %s should not expire", id)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
 // TestSessionManager_MaxTTL tests maximum TTL enforcement
 func TestSessionManager_MaxTTL(t *testing.T) {
 	config := SessionConfig{
@@ -345,6 +405,59 @@ func TestSessionManager_MaxTTL(t *testing.T) {
 	}
 }
 
+// TestSessionManager_StateEvents tests that transitions reach the event bus
+func TestSessionManager_StateEvents(t *testing.T) {
+	sm := NewSessionManager(DefaultSessionConfig())
+	defer sm.Stop()
+
+	// No event bus configured: transitions must not panic
+	quiet, _ := sm.Create("", "!quiet:example.com", 0)
+	sm.UpdateState(quiet.ID, SessionActive)
+
+	bus := eventbus.NewEventBus(eventbus.DefaultConfig())
+	received := make(chan *eventbus.WebRTCSessionStateEvent, 10)
+	bus.RegisterBridgeHandler(eventbus.EventTypeWebRTCSessionState, func(e eventbus.BridgeEvent) {
+		received <- e.(*eventbus.WebRTCSessionStateEvent)
+	})
+	sm.SetEventBus(bus)
+
+	session, err := sm.Create("", "!room:example.com", 0)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	sm.UpdateState(session.ID, SessionActive)
+	sm.UpdateState(session.ID, SessionActive) // no transition, no event
+	sm.End(session.ID)
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case e := <-received:
+			if e.SessionID != session.ID || e.RoomID != "!room:example.com" || e.Timestamp().IsZero() {
+				t.Errorf("Unexpected event: %+v", e)
+			}
+			got = append(got, e.OldState+">"+e.NewState)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Timed out waiting for events, got %v", got)
+		}
+	}
+
+	// Handlers run concurrently, so compare without ordering
+	sort.Strings(got)
+	want := []string{">pending", "active>ended", "pending>active"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected transitions %v, got %v", want, got)
+		}
+	}
+
+	select {
+	case e := <-received:
+		t.Errorf("Unexpected extra event: %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 // TestTokenManager_GenerateValidate tests token generation and validation
 func TestTokenManager_GenerateValidate(t *testing.T) {
 	secret := "test-secret-key"
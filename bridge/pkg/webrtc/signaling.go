@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	wsadapter "github.com/armorclaw/bridge/pkg/websocket"
 	"github.com/gorilla/websocket"
 )
 
@@ -46,6 +47,7 @@ type SignalingServer struct {
 	hub       *Hub             // Connection hub
 	sessions  *SessionManager  // Session manager for auth
 	tokens    *TokenManager    // Token manager for validation
+	keepalive wsadapter.Keepalive // Ping/pong heartbeat on client connections
 	mu        sync.RWMutex
 	running   bool
 	stopChan  chan struct{}
@@ -95,6 +97,12 @@ func (ss *SignalingServer) SetTLS(certFile, keyFile string) {
 	ss.tlsKey = keyFile
 }
 
+// SetKeepalive sets the ping/pong heartbeat for client connections. Clients
+// that miss pongs are disconnected. Must be called before Start.
+func (ss *SignalingServer) SetKeepalive(keepalive wsadapter.Keepalive) {
+	ss.keepalive = keepalive
+}
+
 // Start starts the signaling server
 func (ss *SignalingServer) Start() error {
 	ss.mu.Lock()
@@ -170,7 +178,7 @@ func (ss *SignalingServer) handleWebSocket(w http.ResponseWriter, r *http.Reques
 	ss.hub.register <- client
 
 	// Start client pumps
-	go client.writePump()
+	go client.writePump(ss.keepalive)
 	go client.readPump(ss)
 }
 
@@ -181,11 +189,7 @@ func (c *Client) readPump(server *SignalingServer) {
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		return nil
-	})
+	server.keepalive.Arm(c.conn)
 
 	for {
 		_, message, err := c.conn.ReadMessage()
@@ -244,8 +248,8 @@ func (c *Client) readPump(server *SignalingServer) {
 }
 
 // writePump pumps messages from the hub to the WebSocket connection
-func (c *Client) writePump() {
-	ticker := time.NewTicker(54 * time.Second)
+func (c *Client) writePump(keepalive wsadapter.Keepalive) {
+	ticker := time.NewTicker(keepalive.Interval())
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -277,8 +281,7 @@ func (c *Client) writePump() {
 				return
 			}
 		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := keepalive.Ping(c.conn); err != nil {
 				return
 			}
 		}
@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultPingInterval is how often the server pings an idle connection
+	DefaultPingInterval = 30 * time.Second
+	// DefaultPongTimeout is how long a ping may go unanswered
+	DefaultPongTimeout = 30 * time.Second
+
+	// keepaliveWriteWait bounds writing a single ping
+	keepaliveWriteWait = 10 * time.Second
+)
+
+// Keepalive configures ping/pong heartbeats on server WebSocket
+// connections. NAT gateways on mobile networks drop idle connections
+// without a close frame; regular pings keep the mapping open and a missed
+// pong lets the server close the connection instead of keeping a zombie.
+type Keepalive struct {
+	PingInterval time.Duration // 0 = DefaultPingInterval
+	PongTimeout  time.Duration // 0 = DefaultPongTimeout
+}
+
+// withDefaults fills in unset intervals
+func (k Keepalive) withDefaults() Keepalive {
+	if k.PingInterval <= 0 {
+		k.PingInterval = DefaultPingInterval
+	}
+	if k.PongTimeout <= 0 {
+		k.PongTimeout = DefaultPongTimeout
+	}
+	return k
+}
+
+// Interval returns the effective ping interval
+func (k Keepalive) Interval() time.Duration {
+	return k.withDefaults().PingInterval
+}
+
+// ReadTimeout returns how long a connection may stay silent before it is
+// considered dead: one ping interval plus the pong timeout
+func (k Keepalive) ReadTimeout() time.Duration {
+	k = k.withDefaults()
+	return k.PingInterval + k.PongTimeout
+}
+
+// Arm sets the initial read deadline and extends it on every pong. A
+// connection that misses pongs fails its next read, which ends the read
+// loop and closes it.
+func (k Keepalive) Arm(conn *gorilla.Conn) {
+	timeout := k.ReadTimeout()
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(timeout))
+	})
+}
+
+// Ping sends a ping frame. Call it every Interval from the connection's
+// writer goroutine; gorilla connections support only one concurrent writer.
+func (k Keepalive) Ping(conn *gorilla.Conn) error {
+	conn.SetWriteDeadline(time.Now().Add(keepaliveWriteWait))
+	return conn.WriteMessage(gorilla.PingMessage, nil)
+}
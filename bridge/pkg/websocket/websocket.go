@@ -34,6 +34,20 @@ type EventBroadcaster interface {
 	BroadcastEvent(eventType string, payload []byte)
 }
 
+// FilteredBroadcaster is implemented by broadcasters that keep a
+// subscription filter per connection and only deliver matching events.
+// It returns the number of matching clients skipped because their send
+// buffer was full.
+type FilteredBroadcaster interface {
+	BroadcastFiltered(eventType, roomID string, payload []byte) (dropped int)
+}
+
+// ClientCounter is implemented by broadcasters that can report how many
+// WebSocket clients are connected.
+type ClientCounter interface {
+	ClientCount() int
+}
+
 // Server is a WebSocket adapter that delegates broadcasting to the
 // HTTP server's gorilla/websocket implementation. It does NOT manage
 // its own listener — the HTTP server owns the /ws endpoint.
@@ -104,6 +118,39 @@ func (s *Server) Broadcast(message []byte) error {
 	return nil
 }
 
+// BroadcastFiltered sends a message to the WebSocket clients subscribed to
+// the event type and room, returning the number of slow clients skipped.
+// Broadcasters without per-connection filters receive it as a plain
+// broadcast and report no drops.
+func (s *Server) BroadcastFiltered(eventType, roomID string, message []byte) (int, error) {
+	s.mu.RLock()
+	b := s.broadcaster
+	s.mu.RUnlock()
+
+	if b == nil {
+		return 0, errNoBroadcaster()
+	}
+
+	if fb, ok := b.(FilteredBroadcaster); ok {
+		return fb.BroadcastFiltered(eventType, roomID, message), nil
+	}
+	b.BroadcastEvent(eventType, message)
+	return 0, nil
+}
+
+// ClientCount returns the number of connected WebSocket clients, if the
+// broadcaster reports it
+func (s *Server) ClientCount() (int, bool) {
+	s.mu.RLock()
+	b := s.broadcaster
+	s.mu.RUnlock()
+
+	if cc, ok := b.(ClientCounter); ok {
+		return cc.ClientCount(), true
+	}
+	return 0, false
+}
+
 // errNoBroadcaster returns the sentinel error used when no broadcaster
 // is wired. Kept as a function so the crash-only log.Fatalf in
 // eventbus.go:146 fires correctly.
@@ -71,6 +71,20 @@ if '--version' in sys.argv or '-v' in sys.argv:
 # Secrets Loading (File Descriptor Passing)
 # ============================================================================
 
+def send_secret_ack(sock, error: str = None):
+    """
+    Acknowledge secret delivery to the bridge.
+
+    The bridge waits for this length-prefixed JSON reply before it reports
+    the secrets as delivered; an error reply marks the delivery as failed.
+    """
+    ack = {'status': 'ok'} if error is None else {'status': 'error', 'error': error}
+    data = json.dumps(ack).encode('utf-8')
+    try:
+        sock.sendall(len(data).to_bytes(4, 'big') + data)
+    except OSError as e:
+        print(f"[ArmorClaw] ⚠️ Failed to acknowledge secrets: {e}", file=sys.stderr)
+
 def load_secrets_from_socket() -> dict:
     """
     Load secrets from Unix domain socket (P0-CRIT-3).
@@ -123,16 +137,24 @@ def load_secrets_from_socket() -> dict:
                     return None
                 secrets_data += chunk
 
-            sock.close()
-
             # Parse JSON
-            secrets = json.loads(secrets_data.decode('utf-8'))
+            try:
+                secrets = json.loads(secrets_data.decode('utf-8'))
+            except json.JSONDecodeError:
+                send_secret_ack(sock, 'invalid JSON')
+                sock.close()
+                raise
 
             # Validate structure
             if not secrets.get('provider') or not secrets.get('token'):
                 print(f"[ArmorClaw] ✗ ERROR: Invalid secrets structure from socket", file=sys.stderr)
+                send_secret_ack(sock, 'missing provider or token')
+                sock.close()
                 return None
 
+            send_secret_ack(sock)
+            sock.close()
+
             print(f"[ArmorClaw] ✓ Secrets loaded from socket (P0-CRIT-3: memory-only)")
             return secrets
 
@@ -4,8 +4,10 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -27,14 +29,30 @@ type Config struct {
 	DatabaseURL     string
 	AdminToken      string
 	GracePeriodDays int
+
+	// Per license+feature limit, applied in addition to the per-license
+	// tier limit so one runaway feature check can't starve the others
+	FeatureRateLimit  int           // Requests per window (0 = disabled)
+	FeatureRateWindow time.Duration // Window length (default 1m)
+
+	// Ed25519 key that signs offline license tokens (nil = not issued)
+	SigningKey ed25519.PrivateKey
+
+	// Outbound lifecycle webhooks (disabled when WebhookURLs is empty)
+	WebhookURLs              []string
+	WebhookSecret            string // HMAC-SHA256 key for X-ArmorClaw-Signature
+	WebhookMaxAttempts       int    // Deliveries before dead-lettering (default 5)
+	WebhookExpiryWarningDays int    // Days before expiry to emit license.expiring (default 7)
 }
 
 // Server represents the license server
 type Server struct {
-	config  Config
-	db      *sql.DB
-	logger  *slog.Logger
-	limiter *RateLimiter
+	config         Config
+	db             *sql.DB
+	logger         *slog.Logger
+	limiter        *RateLimiter
+	featureLimiter *RateLimiter       // nil when per-feature limiting is disabled
+	webhooks       *WebhookDispatcher // nil when webhooks are disabled
 }
 
 // RateLimiter manages rate limiting per license
@@ -48,6 +66,22 @@ type RateLimitEntry struct {
 	ResetAt time.Time
 }
 
+// prune drops entries whose window ended before now and returns how many
+// were removed
+func (rl *RateLimiter) prune(now time.Time) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	removed := 0
+	for key, entry := range rl.requests {
+		if now.After(entry.ResetAt) {
+			delete(rl.requests, key)
+			removed++
+		}
+	}
+	return removed
+}
+
 // License represents a license in the database
 type License struct {
 	ID                int
@@ -82,6 +116,39 @@ type Instance struct {
 	Version    string
 }
 
+// Instance listing defaults
+const (
+	staleInstanceAge     = 30 * 24 * time.Hour // last_seen older than this is stale
+	defaultInstanceLimit = 50
+	maxInstanceLimit     = 500
+)
+
+// InstanceInfo is an instance as returned by the admin instances endpoint
+type InstanceInfo struct {
+	InstanceID string `json:"instance_id"`
+	Hostname   string `json:"hostname"`
+	Version    string `json:"version"`
+	FirstSeen  string `json:"first_seen"`
+	LastSeen   string `json:"last_seen"`
+	Stale      bool   `json:"stale"`
+}
+
+// Validation history defaults
+const (
+	defaultValidationLimit = 100
+	maxValidationLimit     = 1000
+)
+
+// ValidationRecord is a logged validation as returned by the admin history endpoint
+type ValidationRecord struct {
+	InstanceID  string `json:"instance_id"`
+	Hostname    string `json:"hostname"`
+	FeatureKey  string `json:"feature_key"`
+	ValidatedAt string `json:"validated_at"`
+	WasValid    bool   `json:"was_valid"`
+	ErrorCode   string `json:"error_code,omitempty"`
+}
+
 // ValidationRequest is the request body for license validation
 type ValidationRequest struct {
 	LicenseKey string `json:"license_key"`
@@ -135,12 +202,26 @@ func main() {
 		DatabaseURL:     getEnv("DATABASE_URL", ""),
 		AdminToken:      getEnv("ADMIN_TOKEN", ""),
 		GracePeriodDays: parseInt(getEnv("GRACE_PERIOD_DAYS", "3"), 3),
+
+		FeatureRateLimit:  parseInt(getEnv("FEATURE_RATE_LIMIT", "0"), 0),
+		FeatureRateWindow: parseDurationEnv(getEnv("FEATURE_RATE_LIMIT_WINDOW", "1m"), time.Minute),
+
+		WebhookURLs:              parseWebhookURLs(getEnv("WEBHOOK_URLS", "")),
+		WebhookSecret:            getEnv("WEBHOOK_SECRET", ""),
+		WebhookMaxAttempts:       parseInt(getEnv("WEBHOOK_MAX_ATTEMPTS", "5"), 5),
+		WebhookExpiryWarningDays: parseInt(getEnv("WEBHOOK_EXPIRY_WARNING_DAYS", "7"), 7),
 	}
 
 	if config.DatabaseURL == "" {
 		log.Fatal("DATABASE_URL environment variable is required")
 	}
 
+	signingKey, err := parseSigningKey(getEnv("LICENSE_SIGNING_KEY", ""))
+	if err != nil {
+		log.Fatalf("Invalid LICENSE_SIGNING_KEY: %v", err)
+	}
+	config.SigningKey = signingKey
+
 	// Initialize logger
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -175,6 +256,29 @@ func main() {
 		logger:  logger,
 		limiter: &RateLimiter{requests: make(map[string]*RateLimitEntry)},
 	}
+	if config.FeatureRateLimit > 0 {
+		server.featureLimiter = &RateLimiter{requests: make(map[string]*RateLimitEntry)}
+		logger.Info("Per-feature rate limiting enabled",
+			"limit", config.FeatureRateLimit,
+			"window", config.FeatureRateWindow,
+		)
+	}
+	if len(config.WebhookURLs) > 0 {
+		if config.WebhookSecret == "" {
+			log.Fatal("WEBHOOK_SECRET is required when WEBHOOK_URLS is set")
+		}
+		server.webhooks = NewWebhookDispatcher(config.WebhookURLs, config.WebhookSecret, config.WebhookMaxAttempts, db, logger)
+		logger.Info("License webhooks enabled",
+			"endpoints", len(config.WebhookURLs),
+			"max_attempts", config.WebhookMaxAttempts,
+			"expiry_warning_days", config.WebhookExpiryWarningDays,
+		)
+	}
+	if config.SigningKey != nil {
+		logger.Info("Offline license tokens enabled",
+			"public_key", base64.StdEncoding.EncodeToString(config.SigningKey.Public().(ed25519.PublicKey)),
+		)
+	}
 
 	// Setup routes
 	mux := http.NewServeMux()
@@ -182,7 +286,11 @@ func main() {
 	mux.HandleFunc("GET /v1/licenses/status", server.withAdminAuth(server.handleStatus))
 	mux.HandleFunc("POST /v1/licenses/activate", server.handleActivate)
 	mux.HandleFunc("POST /admin/v1/licenses", server.withAdminAuth(server.handleAdminCreate))
+	mux.HandleFunc("POST /admin/v1/licenses/bulk", server.withAdminAuth(server.handleAdminBulkCreate))
 	mux.HandleFunc("DELETE /admin/v1/licenses/{key}", server.withAdminAuth(server.handleAdminRevoke))
+	mux.HandleFunc("GET /admin/v1/licenses/{key}/instances", server.withAdminAuth(server.handleAdminListInstances))
+	mux.HandleFunc("DELETE /admin/v1/licenses/{key}/instances/{instance_id}", server.withAdminAuth(server.handleAdminDeregisterInstance))
+	mux.HandleFunc("GET /admin/v1/licenses/{key}/validations", server.withAdminAuth(server.handleAdminListValidations))
 	mux.HandleFunc("GET /health", server.handleHealth)
 
 	// Start server
@@ -191,6 +299,12 @@ func main() {
 
 	// Start background validation retention worker (90-day cleanup)
 	go server.runValidationRetention(context.Background())
+	if server.webhooks != nil {
+		go server.runExpiryNotifier(context.Background())
+	}
+	if server.featureLimiter != nil {
+		go server.runFeatureLimiterSweep(context.Background())
+	}
 
 	if err := http.ListenAndServe(addr, server.loggingMiddleware(mux)); err != nil {
 		log.Fatalf("Server failed: %v", err)
@@ -287,6 +401,22 @@ func initSchema(db *sql.DB) error {
 			ALTER TABLE licenses ADD COLUMN max_instances INTEGER DEFAULT 1;
 		END IF;
 	END $$;
+
+	-- Expiry warnings are sent once per license
+	ALTER TABLE licenses ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMP;
+
+	-- Webhook deliveries that exhausted their retries
+	CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		id SERIAL PRIMARY KEY,
+		delivery_id VARCHAR(64) NOT NULL,
+		event VARCHAR(100) NOT NULL,
+		url TEXT NOT NULL,
+		payload JSONB NOT NULL,
+		attempts INTEGER NOT NULL,
+		last_error TEXT,
+		created_at TIMESTAMP DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_created_at ON webhook_dead_letters(created_at);
 	`
 
 	_, err := db.Exec(schema)
@@ -317,6 +447,14 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Per-feature rate limiting, checked first so a throttled feature
+	// doesn't also consume the license-wide quota
+	if ok, retryAfter := s.checkFeatureRateLimit(req.LicenseKey, req.Feature); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(retryAfter)))
+		s.writeError(w, http.StatusTooManyRequests, "Feature rate limit exceeded")
+		return
+	}
+
 	// Rate limiting
 	tier := s.getLicenseTier(req.LicenseKey)
 	if !s.checkRateLimit(req.LicenseKey, tier) {
@@ -523,6 +661,13 @@ func (s *Server) handleActivate(w http.ResponseWriter, r *http.Request) {
 				"current_instances", currentInstances,
 				"max_instances", license.MaxInstances,
 			)
+			s.webhooks.Emit(EventInstanceLimitExceeded, map[string]interface{}{
+				"license_key":       req.LicenseKey,
+				"tier":              license.Tier,
+				"instance_id":       req.InstanceID,
+				"current_instances": currentInstances,
+				"max_instances":     license.MaxInstances,
+			})
 			s.writeError(w, http.StatusForbidden, ErrorResponse{
 				Error:        "Instance limit exceeded",
 				ErrorCode:    "INSTANCE_LIMIT_EXCEEDED",
@@ -567,6 +712,16 @@ func (s *Server) handleActivate(w http.ResponseWriter, r *http.Request) {
 		"instance_id", req.InstanceID[:8]+"...",
 		"tier", license.Tier,
 	)
+	if existingCount == 0 {
+		s.webhooks.Emit(EventLicenseActivated, map[string]interface{}{
+			"license_key": req.LicenseKey,
+			"tier":        license.Tier,
+			"instance_id": req.InstanceID,
+			"hostname":    req.Hostname,
+			"version":     req.Version,
+			"expires_at":  license.ExpiresAt.Format(time.RFC3339),
+		})
+	}
 
 	resp := &ActivationResponse{
 		Activated: true,
@@ -686,21 +841,8 @@ func (s *Server) handleAdminCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Assign features
-	for _, featureKey := range req.Features {
-		s.db.ExecContext(r.Context(), `
-			INSERT INTO license_features (license_id, feature_id)
-			SELECT $1, id FROM features WHERE feature_key = $2
-			ON CONFLICT DO NOTHING
-		`, licenseID, featureKey)
-	}
-
-	// If no specific features, assign all features for tier
-	if len(req.Features) == 0 {
-		s.db.ExecContext(r.Context(), `
-			INSERT INTO license_features (license_id, feature_id)
-			SELECT $1, id FROM features WHERE tier = $2 OR tier = 'pro'
-		`, licenseID, req.Tier)
+	if err := assignLicenseFeatures(r.Context(), s.db, licenseID, req.Tier, req.Features); err != nil {
+		s.logger.Warn("Failed to assign license features", "error", err, "license_key", maskLicenseKey(licenseKey))
 	}
 
 	s.logger.Info("License created", "license_key", maskLicenseKey(licenseKey), "tier", req.Tier, "expires_at", expiresAt, "max_instances", maxInstances)
@@ -712,10 +854,160 @@ func (s *Server) handleAdminCreate(w http.ResponseWriter, r *http.Request) {
 		"max_instances": maxInstances,
 	}
 
+	// Offline token lets air-gapped bridges verify the license locally
+	if s.config.SigningKey != nil {
+		token, err := s.issueOfflineToken(r.Context(), s.db, licenseID, licenseKey, req.Tier, maxInstances, expiresAt)
+		if err != nil {
+			s.logger.Error("Failed to issue offline token", "error", err, "license_key", maskLicenseKey(licenseKey))
+			s.writeError(w, http.StatusInternalServerError, "Failed to issue offline token")
+			return
+		}
+		response["offline_token"] = token
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// maxBulkLicenses bounds a single bulk creation request
+const maxBulkLicenses = 1000
+
+// handleAdminBulkCreate handles POST /admin/v1/licenses/bulk
+// Creates count licenses with the same parameters in one transaction, so a
+// reseller batch either lands completely or not at all
+func (s *Server) handleAdminBulkCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Count        int      `json:"count"`
+		Tier         string   `json:"tier"`
+		Email        string   `json:"email"`
+		DurationDays int      `json:"duration_days"`
+		MaxInstances int      `json:"max_instances"` // 0 = tier default
+		Features     []string `json:"features"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Tier == "" || req.DurationDays == 0 {
+		s.writeError(w, http.StatusBadRequest, "tier and duration_days are required")
+		return
+	}
+	if req.Count < 1 || req.Count > maxBulkLicenses {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("count must be between 1 and %d", maxBulkLicenses))
+		return
+	}
+
+	maxInstances := req.MaxInstances
+	if maxInstances == 0 {
+		maxInstances = getDefaultMaxInstances(req.Tier)
+	}
+	expiresAt := time.Now().AddDate(0, 0, req.DurationDays)
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.logger.Error("Failed to begin transaction", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	defer tx.Rollback()
+
+	licenses := make([]map[string]interface{}, 0, req.Count)
+	seen := make(map[string]bool, req.Count)
+	for len(licenses) < req.Count {
+		licenseKey, err := generateLicenseKey(req.Tier)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, "Failed to generate license key")
+			return
+		}
+		if seen[licenseKey] {
+			continue
+		}
+		seen[licenseKey] = true
+
+		// ON CONFLICT skips a key that collides with an existing license;
+		// the loop then draws a fresh one
+		var licenseID int
+		err = tx.QueryRowContext(r.Context(), `
+			INSERT INTO licenses (license_key, tier, customer_email, expires_at, status, max_instances)
+			VALUES ($1, $2, $3, $4, 'active', $5)
+			ON CONFLICT (license_key) DO NOTHING
+			RETURNING id
+		`, licenseKey, req.Tier, req.Email, expiresAt, maxInstances).Scan(&licenseID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			s.logger.Error("Failed to create license", "error", err)
+			s.writeError(w, http.StatusInternalServerError, "Failed to create licenses")
+			return
+		}
+
+		if err := assignLicenseFeatures(r.Context(), tx, licenseID, req.Tier, req.Features); err != nil {
+			s.logger.Error("Failed to assign license features", "error", err)
+			s.writeError(w, http.StatusInternalServerError, "Failed to create licenses")
+			return
+		}
+
+		license := map[string]interface{}{
+			"license_key": licenseKey,
+		}
+		if s.config.SigningKey != nil {
+			token, err := s.issueOfflineToken(r.Context(), tx, licenseID, licenseKey, req.Tier, maxInstances, expiresAt)
+			if err != nil {
+				s.logger.Error("Failed to issue offline token", "error", err)
+				s.writeError(w, http.StatusInternalServerError, "Failed to issue offline token")
+				return
+			}
+			license["offline_token"] = token
+		}
+		licenses = append(licenses, license)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Failed to commit transaction", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to create licenses")
+		return
+	}
+
+	s.logger.Info("Licenses created in bulk", "count", len(licenses), "tier", req.Tier, "expires_at", expiresAt, "max_instances", maxInstances)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"count":         len(licenses),
+		"tier":          req.Tier,
+		"expires_at":    expiresAt.Format(time.RFC3339),
+		"max_instances": maxInstances,
+		"licenses":      licenses,
+	})
+}
+
+// assignLicenseFeatures links the requested features to a license, or every
+// feature of its tier when none are requested
+func assignLicenseFeatures(ctx context.Context, db Querier, licenseID int, tier string, features []string) error {
+	for _, featureKey := range features {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO license_features (license_id, feature_id)
+			SELECT $1, id FROM features WHERE feature_key = $2
+			ON CONFLICT DO NOTHING
+		`, licenseID, featureKey); err != nil {
+			return err
+		}
+	}
+
+	// If no specific features, assign all features for tier
+	if len(features) == 0 {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO license_features (license_id, feature_id)
+			SELECT $1, id FROM features WHERE tier = $2 OR tier = 'pro'
+		`, licenseID, tier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // getDefaultMaxInstances returns the default instance limit for a tier
 func getDefaultMaxInstances(tier string) int {
 	switch tier {
@@ -791,9 +1083,278 @@ func (s *Server) handleAdminRevoke(w http.ResponseWriter, r *http.Request) {
 
 	s.logger.Info("License revoked", "license_key", maskLicenseKey(licenseKey))
 
+	revokedAt := time.Now().Format(time.RFC3339)
+	s.webhooks.Emit(EventLicenseRevoked, map[string]interface{}{
+		"license_key": licenseKey,
+		"revoked_at":  revokedAt,
+	})
+
 	response := map[string]interface{}{
 		"revoked":    true,
-		"revoked_at": time.Now().Format(time.RFC3339),
+		"revoked_at": revokedAt,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleAdminListInstances handles GET /admin/v1/licenses/{key}/instances
+// Supports ?limit=N&offset=M&order=asc|desc (by last_seen, default desc)
+func (s *Server) handleAdminListInstances(w http.ResponseWriter, r *http.Request) {
+	licenseKey := r.PathValue("key")
+	if licenseKey == "" {
+		s.writeError(w, http.StatusBadRequest, "License key required")
+		return
+	}
+
+	limit, offset, err := parsePagination(r, defaultInstanceLimit, maxInstanceLimit)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order := "DESC"
+	switch strings.ToLower(r.URL.Query().Get("order")) {
+	case "", "desc":
+	case "asc":
+		order = "ASC"
+	default:
+		s.writeError(w, http.StatusBadRequest, "order must be 'asc' or 'desc'")
+		return
+	}
+
+	var licenseID int
+	err = s.db.QueryRowContext(r.Context(), `
+		SELECT id FROM licenses WHERE license_key = $1
+	`, licenseKey).Scan(&licenseID)
+	if err == sql.ErrNoRows {
+		s.writeError(w, http.StatusNotFound, "License not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to query license", "error", err, "license_key", maskLicenseKey(licenseKey))
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(r.Context(), `
+		SELECT COUNT(*) FROM instances WHERE license_id = $1
+	`, licenseID).Scan(&total); err != nil {
+		s.logger.Error("Failed to count instances", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	// order is one of two fixed values, never user input
+	rows, err := s.db.QueryContext(r.Context(), `
+		SELECT instance_id, COALESCE(hostname, ''), COALESCE(version, ''), first_seen, last_seen
+		FROM instances WHERE license_id = $1
+		ORDER BY last_seen `+order+`, id
+		LIMIT $2 OFFSET $3
+	`, licenseID, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list instances", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	instances := []InstanceInfo{}
+	staleCount := 0
+	for rows.Next() {
+		var inst Instance
+		if err := rows.Scan(&inst.InstanceID, &inst.Hostname, &inst.Version, &inst.FirstSeen, &inst.LastSeen); err != nil {
+			s.logger.Error("Failed to scan instance", "error", err)
+			s.writeError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		info := InstanceInfo{
+			InstanceID: inst.InstanceID,
+			Hostname:   inst.Hostname,
+			Version:    inst.Version,
+			FirstSeen:  inst.FirstSeen.Format(time.RFC3339),
+			LastSeen:   inst.LastSeen.Format(time.RFC3339),
+			Stale:      isStaleInstance(inst.LastSeen, now),
+		}
+		if info.Stale {
+			staleCount++
+		}
+		instances = append(instances, info)
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Error("Failed to list instances", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	response := map[string]interface{}{
+		"license_key":      maskLicenseKey(licenseKey),
+		"total":            total,
+		"limit":            limit,
+		"offset":           offset,
+		"stale_count":      staleCount,
+		"stale_after_days": int(staleInstanceAge / (24 * time.Hour)),
+		"instances":        instances,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleAdminDeregisterInstance handles DELETE /admin/v1/licenses/{key}/instances/{instance_id}
+// Removes a decommissioned instance so its slot can be reused by a new activation
+func (s *Server) handleAdminDeregisterInstance(w http.ResponseWriter, r *http.Request) {
+	licenseKey := r.PathValue("key")
+	instanceID := r.PathValue("instance_id")
+	if licenseKey == "" || instanceID == "" {
+		s.writeError(w, http.StatusBadRequest, "License key and instance ID required")
+		return
+	}
+	if !isValidInstanceID(instanceID) {
+		s.writeError(w, http.StatusBadRequest, "instance_id must be a UUID")
+		return
+	}
+
+	tx, err := s.db.BeginTx(r.Context(), nil)
+	if err != nil {
+		s.logger.Error("Failed to begin transaction", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	defer tx.Rollback()
+
+	// Lock the license row so a concurrent activation can't race the slot count
+	var licenseID int
+	err = tx.QueryRowContext(r.Context(), `
+		SELECT id FROM licenses WHERE license_key = $1
+		FOR UPDATE
+	`, licenseKey).Scan(&licenseID)
+	if err == sql.ErrNoRows {
+		s.writeError(w, http.StatusNotFound, "License not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to query license", "error", err, "license_key", maskLicenseKey(licenseKey))
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	result, err := tx.ExecContext(r.Context(), `
+		DELETE FROM instances WHERE instance_id = $1 AND license_id = $2
+	`, instanceID, licenseID)
+	if err != nil {
+		s.logger.Error("Failed to delete instance", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to deregister instance")
+		return
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		s.writeError(w, http.StatusNotFound, "Instance not found for this license")
+		return
+	}
+
+	var remaining int
+	if err := tx.QueryRowContext(r.Context(), `
+		SELECT COUNT(*) FROM instances WHERE license_id = $1
+	`, licenseID).Scan(&remaining); err != nil {
+		s.logger.Error("Failed to count instances", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("Failed to commit transaction", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Failed to deregister instance")
+		return
+	}
+
+	s.logger.Info("Instance deregistered",
+		"license_key", maskLicenseKey(licenseKey),
+		"instance_id", instanceID[:8]+"...",
+		"remaining_instances", remaining,
+	)
+
+	response := map[string]interface{}{
+		"deregistered":        true,
+		"instance_id":         instanceID,
+		"remaining_instances": remaining,
+		"deregistered_at":     time.Now().Format(time.RFC3339),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleAdminListValidations handles GET /admin/v1/licenses/{key}/validations
+// Returns the most recent validations across all of the license's instances
+func (s *Server) handleAdminListValidations(w http.ResponseWriter, r *http.Request) {
+	licenseKey := r.PathValue("key")
+	if licenseKey == "" {
+		s.writeError(w, http.StatusBadRequest, "License key required")
+		return
+	}
+
+	limit, _, err := parsePagination(r, defaultValidationLimit, maxValidationLimit)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var licenseID int
+	err = s.db.QueryRowContext(r.Context(), `
+		SELECT id FROM licenses WHERE license_key = $1
+	`, licenseKey).Scan(&licenseID)
+	if err == sql.ErrNoRows {
+		s.writeError(w, http.StatusNotFound, "License not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to query license", "error", err, "license_key", maskLicenseKey(licenseKey))
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	rows, err := s.db.QueryContext(r.Context(), `
+		SELECT v.instance_id, COALESCE(i.hostname, ''), COALESCE(v.feature_key, ''),
+			v.validated_at, COALESCE(v.was_valid, FALSE), COALESCE(v.error_code, '')
+		FROM validations v
+		JOIN instances i ON i.instance_id = v.instance_id
+		WHERE i.license_id = $1
+		ORDER BY v.validated_at DESC, v.id DESC
+		LIMIT $2
+	`, licenseID, limit)
+	if err != nil {
+		s.logger.Error("Failed to list validations", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	defer rows.Close()
+
+	validations := []ValidationRecord{}
+	for rows.Next() {
+		var rec ValidationRecord
+		var validatedAt time.Time
+		if err := rows.Scan(&rec.InstanceID, &rec.Hostname, &rec.FeatureKey, &validatedAt, &rec.WasValid, &rec.ErrorCode); err != nil {
+			s.logger.Error("Failed to scan validation", "error", err)
+			s.writeError(w, http.StatusInternalServerError, "Database error")
+			return
+		}
+		rec.ValidatedAt = validatedAt.Format(time.RFC3339)
+		validations = append(validations, rec)
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Error("Failed to list validations", "error", err)
+		s.writeError(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	response := map[string]interface{}{
+		"license_key": maskLicenseKey(licenseKey),
+		"limit":       limit,
+		"validations": validations,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -844,6 +1405,29 @@ func (s *Server) runValidationRetention(ctx context.Context) {
 	}
 }
 
+// runFeatureLimiterSweep evicts per license+feature rate limit entries
+// once their window has passed, so keys that stop calling do not stay in
+// memory. Runs once per FeatureRateWindow in a background goroutine.
+func (s *Server) runFeatureLimiterSweep(ctx context.Context) {
+	window := s.config.FeatureRateWindow
+	if window <= 0 {
+		window = time.Minute
+	}
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if removed := s.featureLimiter.prune(time.Now()); removed > 0 {
+				s.logger.Debug("feature rate limit entries evicted", "count", removed)
+			}
+		}
+	}
+}
+
 // Helper functions
 
 func (s *Server) writeError(w http.ResponseWriter, code int, message interface{}) {
@@ -917,6 +1501,50 @@ func (s *Server) checkRateLimit(licenseKey, tier string) bool {
 	return true
 }
 
+// checkFeatureRateLimit applies the optional per license+feature limit.
+// When the limit is exceeded it returns false and the time until reset.
+func (s *Server) checkFeatureRateLimit(licenseKey, feature string) (bool, time.Duration) {
+	if s.featureLimiter == nil || s.config.FeatureRateLimit <= 0 || feature == "" {
+		return true, 0
+	}
+
+	window := s.config.FeatureRateWindow
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	s.featureLimiter.mu.Lock()
+	defer s.featureLimiter.mu.Unlock()
+
+	key := licenseKey + "|" + feature
+	entry, exists := s.featureLimiter.requests[key]
+	now := time.Now()
+
+	if !exists || now.After(entry.ResetAt) {
+		s.featureLimiter.requests[key] = &RateLimitEntry{
+			Count:   1,
+			ResetAt: now.Add(window),
+		}
+		return true, 0
+	}
+
+	if entry.Count >= s.config.FeatureRateLimit {
+		return false, entry.ResetAt.Sub(now)
+	}
+
+	entry.Count++
+	return true, 0
+}
+
+// retryAfterSeconds rounds a wait up to whole seconds for Retry-After
+func retryAfterSeconds(d time.Duration) int {
+	secs := int((d + time.Second - 1) / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return secs
+}
+
 func (s *Server) getLicenseTier(licenseKey string) string {
 	parts := strings.Split(licenseKey, "-")
 	if len(parts) >= 2 {
@@ -1044,6 +1672,52 @@ func maskLicenseKey(key string) string {
 	return key[:10] + "****"
 }
 
+// parsePagination reads ?limit= and ?offset= with a default and maximum limit
+func parsePagination(r *http.Request, defaultLimit, maxLimit int) (limit, offset int, err error) {
+	limit = defaultLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return 0, 0, fmt.Errorf("limit must be a positive integer")
+		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// isValidInstanceID checks that an instance ID is a canonical UUID
+func isValidInstanceID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+	for i, c := range id {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isStaleInstance reports whether an instance has not checked in recently
+func isStaleInstance(lastSeen, now time.Time) bool {
+	return now.Sub(lastSeen) > staleInstanceAge
+}
+
 func containsFeature(features []string, feature string) bool {
 	for _, f := range features {
 		if f == feature {
@@ -1067,6 +1741,13 @@ func getEnv(key, defaultVal string) string {
 	return defaultVal
 }
 
+func parseDurationEnv(s string, defaultVal time.Duration) time.Duration {
+	if val, err := time.ParseDuration(s); err == nil && val > 0 {
+		return val
+	}
+	return defaultVal
+}
+
 func parseInt(s string, defaultVal int) int {
 	if val, err := strconv.Atoi(s); err == nil {
 		return val
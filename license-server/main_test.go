@@ -3,12 +3,18 @@ package main
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -331,6 +337,104 @@ func TestRateLimiterInternal(t *testing.T) {
 	}
 }
 
+// TestFeatureRateLimit tests the per license+feature limiter
+func TestFeatureRateLimit(t *testing.T) {
+	server := createTestServer(nil, "test-token")
+
+	// Disabled by default
+	for i := 0; i < 10; i++ {
+		if ok, _ := server.checkFeatureRateLimit("key", "sso-integration"); !ok {
+			t.Fatal("Feature limiter should be disabled by default")
+		}
+	}
+
+	server.config.FeatureRateLimit = 3
+	server.config.FeatureRateWindow = time.Minute
+	server.featureLimiter = &RateLimiter{requests: make(map[string]*RateLimitEntry)}
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := server.checkFeatureRateLimit("key", "sso-integration"); !ok {
+			t.Errorf("Request %d should be allowed", i)
+		}
+	}
+
+	ok, retryAfter := server.checkFeatureRateLimit("key", "sso-integration")
+	if ok {
+		t.Error("Request beyond feature limit should be denied")
+	}
+	if retryAfter <= 0 || retryAfter > time.Minute {
+		t.Errorf("retryAfter = %v, want within the 1m window", retryAfter)
+	}
+
+	// Other features and other licenses are unaffected
+	if ok, _ := server.checkFeatureRateLimit("key", "slack-adapter"); !ok {
+		t.Error("Different feature should not be limited")
+	}
+	if ok, _ := server.checkFeatureRateLimit("other-key", "sso-integration"); !ok {
+		t.Error("Different license should not be limited")
+	}
+}
+
+// TestFeatureRateLimitPrune tests that idle entries are evicted once their
+// window has passed while active ones are kept
+func TestFeatureRateLimitPrune(t *testing.T) {
+	server := createTestServer(nil, "test-token")
+	server.config.FeatureRateLimit = 3
+	server.config.FeatureRateWindow = time.Minute
+	server.featureLimiter = &RateLimiter{requests: make(map[string]*RateLimitEntry)}
+
+	server.checkFeatureRateLimit("idle-key", "sso-integration")
+	server.checkFeatureRateLimit("active-key", "sso-integration")
+	server.featureLimiter.requests["idle-key|sso-integration"].ResetAt = time.Now().Add(-time.Second)
+
+	if removed := server.featureLimiter.prune(time.Now()); removed != 1 {
+		t.Errorf("prune removed %d entries, want 1", removed)
+	}
+	if _, ok := server.featureLimiter.requests["idle-key|sso-integration"]; ok {
+		t.Error("Idle entry should have been evicted")
+	}
+	if _, ok := server.featureLimiter.requests["active-key|sso-integration"]; !ok {
+		t.Error("Active entry should be kept")
+	}
+}
+
+// TestFeatureRateLimitRetryAfterHeader tests the 429 response from handleValidate
+func TestFeatureRateLimitRetryAfterHeader(t *testing.T) {
+	server := createTestServer(nil, "test-token")
+	server.config.FeatureRateLimit = 1
+	server.config.FeatureRateWindow = 30 * time.Second
+	server.featureLimiter = &RateLimiter{requests: make(map[string]*RateLimitEntry)}
+
+	// Exhaust the feature limit without touching the database
+	server.checkFeatureRateLimit("SCLW-PRO-0123456789abcdef", "sso-integration")
+
+	body, _ := json.Marshal(ValidationRequest{
+		LicenseKey: "SCLW-PRO-0123456789abcdef",
+		InstanceID: "instance-001",
+		Feature:    "sso-integration",
+	})
+	req := httptest.NewRequest("POST", "/v1/licenses/validate", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	server.handleValidate(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", w.Code)
+	}
+	retryAfter := w.Header().Get("Retry-After")
+	if retryAfter == "" || retryAfter == "0" || len(retryAfter) > 2 {
+		t.Errorf("Retry-After = %q, want seconds until the 30s window resets", retryAfter)
+	}
+
+	var errResp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if errResp.Error != "Feature rate limit exceeded" {
+		t.Errorf("Error = %q, want %q", errResp.Error, "Feature rate limit exceeded")
+	}
+}
+
 // TestLicenseStatusWithDB tests the status endpoint
 func TestLicenseStatusWithDB(t *testing.T) {
 	config := getTestConfig()
@@ -367,6 +471,330 @@ func TestLicenseStatusWithDB(t *testing.T) {
 	}
 }
 
+// TestAdminListInstancesWithDB tests paginated instance listing with stale detection
+func TestAdminListInstancesWithDB(t *testing.T) {
+	config := getTestConfig()
+	if config.DatabaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	server := createTestServer(db, config.AdminToken)
+
+	licenseKey := fmt.Sprintf("TEST-INSTANCES-%d", time.Now().UnixNano())
+	var licenseID int
+	err = db.QueryRow(`
+		INSERT INTO licenses (license_key, tier, customer_email, expires_at)
+		VALUES ($1, 'pro', 'test@example.com', NOW() + INTERVAL '30 days')
+		RETURNING id
+	`, licenseKey).Scan(&licenseID)
+	if err != nil {
+		t.Fatalf("Failed to create test license: %v", err)
+	}
+	defer db.Exec("DELETE FROM licenses WHERE license_key = $1", licenseKey)
+
+	_, err = db.Exec(`
+		INSERT INTO instances (instance_id, license_id, hostname, version, first_seen, last_seen) VALUES
+			(gen_random_uuid(), $1, 'fresh-host', '1.0.0', NOW() - INTERVAL '60 days', NOW()),
+			(gen_random_uuid(), $1, 'old-host', '0.9.0', NOW() - INTERVAL '90 days', NOW() - INTERVAL '45 days')
+	`, licenseID)
+	if err != nil {
+		t.Fatalf("Failed to create test instances: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/admin/v1/licenses/"+licenseKey+"/instances?limit=1", nil)
+	req.SetPathValue("key", licenseKey)
+	w := httptest.NewRecorder()
+
+	server.handleAdminListInstances(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Total     int            `json:"total"`
+		Instances []InstanceInfo `json:"instances"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Total != 2 || len(resp.Instances) != 1 {
+		t.Fatalf("Expected total 2 with 1 returned, got %d/%d", resp.Total, len(resp.Instances))
+	}
+	if resp.Instances[0].Hostname != "fresh-host" || resp.Instances[0].Stale {
+		t.Errorf("Expected most recent non-stale instance first, got %+v", resp.Instances[0])
+	}
+}
+
+// TestAdminListInstancesInvalidParams tests parameter validation before any database access
+func TestAdminListInstancesInvalidParams(t *testing.T) {
+	server := createTestServer(nil, "test-token")
+
+	for _, query := range []string{"limit=0", "limit=abc", "offset=-1", "order=sideways"} {
+		req := httptest.NewRequest("GET", "/admin/v1/licenses/SCLW-PRO-0000000000000000/instances?"+query, nil)
+		req.SetPathValue("key", "SCLW-PRO-0000000000000000")
+		w := httptest.NewRecorder()
+
+		server.handleAdminListInstances(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+// TestAdminBulkCreateWithDB tests that a bulk request creates unique licenses
+func TestAdminBulkCreateWithDB(t *testing.T) {
+	config := getTestConfig()
+	if config.DatabaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	server := createTestServer(db, config.AdminToken)
+
+	body := []byte(`{"count": 5, "tier": "pro", "email": "reseller@example.com", "duration_days": 30}`)
+	req := httptest.NewRequest("POST", "/admin/v1/licenses/bulk", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	server.handleAdminBulkCreate(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Count        int `json:"count"`
+		MaxInstances int `json:"max_instances"`
+		Licenses     []struct {
+			LicenseKey string `json:"license_key"`
+		} `json:"licenses"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Count != 5 || len(resp.Licenses) != 5 {
+		t.Fatalf("Expected 5 licenses, got %d/%d", resp.Count, len(resp.Licenses))
+	}
+	if resp.MaxInstances != 3 {
+		t.Errorf("Expected pro default of 3 instances, got %d", resp.MaxInstances)
+	}
+
+	seen := make(map[string]bool)
+	for _, l := range resp.Licenses {
+		defer db.Exec("DELETE FROM licenses WHERE license_key = $1", l.LicenseKey)
+		if seen[l.LicenseKey] {
+			t.Errorf("Duplicate license key %s", l.LicenseKey)
+		}
+		seen[l.LicenseKey] = true
+		if !isValidLicenseKey(l.LicenseKey) {
+			t.Errorf("Invalid license key %s", l.LicenseKey)
+		}
+	}
+}
+
+// TestAdminBulkCreateInvalidParams tests request validation before any database access
+func TestAdminBulkCreateInvalidParams(t *testing.T) {
+	server := createTestServer(nil, "test-token")
+
+	for _, body := range []string{
+		`{"count": 0, "tier": "pro", "duration_days": 30}`,
+		`{"count": 1001, "tier": "pro", "duration_days": 30}`,
+		`{"count": 5, "duration_days": 30}`,
+		`{"count": 5, "tier": "pro"}`,
+		`not json`,
+	} {
+		req := httptest.NewRequest("POST", "/admin/v1/licenses/bulk", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+
+		server.handleAdminBulkCreate(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", body, w.Code)
+		}
+	}
+}
+
+// TestAdminDeregisterInstanceWithDB tests removing an instance frees its slot
+func TestAdminDeregisterInstanceWithDB(t *testing.T) {
+	config := getTestConfig()
+	if config.DatabaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	server := createTestServer(db, config.AdminToken)
+
+	licenseKey := fmt.Sprintf("TEST-DEREGISTER-%d", time.Now().UnixNano())
+	var licenseID int
+	err = db.QueryRow(`
+		INSERT INTO licenses (license_key, tier, customer_email, expires_at, max_instances)
+		VALUES ($1, 'pro', 'test@example.com', NOW() + INTERVAL '30 days', 1)
+		RETURNING id
+	`, licenseKey).Scan(&licenseID)
+	if err != nil {
+		t.Fatalf("Failed to create test license: %v", err)
+	}
+	defer db.Exec("DELETE FROM licenses WHERE license_key = $1", licenseKey)
+
+	var instanceID string
+	err = db.QueryRow(`
+		INSERT INTO instances (instance_id, license_id, hostname) VALUES (gen_random_uuid(), $1, 'old-host')
+		RETURNING instance_id
+	`, licenseID).Scan(&instanceID)
+	if err != nil {
+		t.Fatalf("Failed to create test instance: %v", err)
+	}
+
+	deregister := func(key, id string) int {
+		req := httptest.NewRequest("DELETE", "/admin/v1/licenses/"+key+"/instances/"+id, nil)
+		req.SetPathValue("key", key)
+		req.SetPathValue("instance_id", id)
+		w := httptest.NewRecorder()
+		server.handleAdminDeregisterInstance(w, req)
+		return w.Code
+	}
+
+	// Instance belonging to a different license is not found
+	if code := deregister("SCLW-PRO-0000000000000000", instanceID); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for wrong license, got %d", code)
+	}
+
+	if code := deregister(licenseKey, instanceID); code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+
+	var remaining int
+	db.QueryRow("SELECT COUNT(*) FROM instances WHERE license_id = $1", licenseID).Scan(&remaining)
+	if remaining != 0 {
+		t.Errorf("Expected instance to be removed, %d remain", remaining)
+	}
+
+	// Second delete is a 404
+	if code := deregister(licenseKey, instanceID); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for already deregistered instance, got %d", code)
+	}
+}
+
+// TestAdminListValidationsWithDB tests validation history across a license's instances
+func TestAdminListValidationsWithDB(t *testing.T) {
+	config := getTestConfig()
+	if config.DatabaseURL == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", config.DatabaseURL)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	server := createTestServer(db, config.AdminToken)
+
+	licenseKey := fmt.Sprintf("TEST-VALIDATIONS-%d", time.Now().UnixNano())
+	var licenseID int
+	err = db.QueryRow(`
+		INSERT INTO licenses (license_key, tier, customer_email, expires_at)
+		VALUES ($1, 'pro', 'test@example.com', NOW() + INTERVAL '30 days')
+		RETURNING id
+	`, licenseKey).Scan(&licenseID)
+	if err != nil {
+		t.Fatalf("Failed to create test license: %v", err)
+	}
+	defer db.Exec("DELETE FROM licenses WHERE license_key = $1", licenseKey)
+
+	var instanceID string
+	err = db.QueryRow(`
+		INSERT INTO instances (instance_id, license_id, hostname) VALUES (gen_random_uuid(), $1, 'host-a')
+		RETURNING instance_id
+	`, licenseID).Scan(&instanceID)
+	if err != nil {
+		t.Fatalf("Failed to create test instance: %v", err)
+	}
+	defer db.Exec("DELETE FROM validations WHERE instance_id = $1", instanceID)
+
+	_, err = db.Exec(`
+		INSERT INTO validations (instance_id, feature_key, validated_at, was_valid, error_code) VALUES
+			($1, 'slack-adapter', NOW() - INTERVAL '1 hour', TRUE, NULL),
+			($1, 'sso-integration', NOW(), FALSE, 'LICENSE_EXPIRED')
+	`, instanceID)
+	if err != nil {
+		t.Fatalf("Failed to create test validations: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/admin/v1/licenses/"+licenseKey+"/validations?limit=10", nil)
+	req.SetPathValue("key", licenseKey)
+	w := httptest.NewRecorder()
+
+	server.handleAdminListValidations(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Validations []ValidationRecord `json:"validations"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Validations) != 2 {
+		t.Fatalf("Expected 2 validations, got %d", len(resp.Validations))
+	}
+	latest := resp.Validations[0]
+	if latest.FeatureKey != "sso-integration" || latest.WasValid || latest.ErrorCode != "LICENSE_EXPIRED" {
+		t.Errorf("Expected most recent failed validation first, got %+v", latest)
+	}
+}
+
+// TestIsValidInstanceID tests UUID validation for instance IDs
+func TestIsValidInstanceID(t *testing.T) {
+	valid := []string{
+		"3f2504e0-4f89-11d3-9a0c-0305e82c3301",
+		"3F2504E0-4F89-11D3-9A0C-0305E82C3301",
+	}
+	invalid := []string{
+		"",
+		"instance-001",
+		"3f2504e0-4f89-11d3-9a0c-0305e82c330",
+		"3f2504e0x4f89-11d3-9a0c-0305e82c3301",
+		"3f2504e0-4f89-11d3-9a0c-0305e82c330g",
+	}
+
+	for _, id := range valid {
+		if !isValidInstanceID(id) {
+			t.Errorf("isValidInstanceID(%q) = false, want true", id)
+		}
+	}
+	for _, id := range invalid {
+		if isValidInstanceID(id) {
+			t.Errorf("isValidInstanceID(%q) = true, want false", id)
+		}
+	}
+}
+
+// TestIsStaleInstance tests the 30-day stale threshold
+func TestIsStaleInstance(t *testing.T) {
+	now := time.Now()
+
+	if isStaleInstance(now.Add(-29*24*time.Hour), now) {
+		t.Error("Instance seen 29 days ago should not be stale")
+	}
+	if !isStaleInstance(now.Add(-31*24*time.Hour), now) {
+		t.Error("Instance seen 31 days ago should be stale")
+	}
+}
+
 // TestValidationWithoutDatabase tests validation gracefully handles no database
 func TestValidationWithoutDatabase(t *testing.T) {
 	server := createTestServer(nil, "test-token")
@@ -464,3 +892,102 @@ func TestTierDefaults_Enterprise(t *testing.T) {
 		t.Errorf("Enterprise MaxMembersPerTeam = %d, want 50", maxMembers)
 	}
 }
+
+// TestOfflineTokenSignature checks offline tokens verify against the public key
+func TestOfflineTokenSignature(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	key, err := parseSigningKey(base64.StdEncoding.EncodeToString(seed))
+	if err != nil {
+		t.Fatalf("parseSigningKey: %v", err)
+	}
+
+	now := time.Now()
+	claims := newOfflineTokenClaims("SCLW-PRO-0000000000000000", "pro", []string{"adapters"}, 3, now, now.Add(24*time.Hour))
+	token, err := signOfflineToken(key, claims)
+	if err != nil {
+		t.Fatalf("signOfflineToken: %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d segments, want 3", len(parts))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	if !ed25519.Verify(pub, []byte(parts[0]+"."+parts[1]), sig) {
+		t.Error("signature does not verify with the public key")
+	}
+
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var got OfflineTokenClaims
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatalf("decode claims: %v", err)
+	}
+	if got.Subject != claims.Subject || got.Tier != "pro" || got.ExpiresAt != claims.ExpiresAt || got.Issuer != offlineTokenIssuer {
+		t.Errorf("claims = %+v, want %+v", got, claims)
+	}
+
+	if _, err := parseSigningKey(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Error("expected error for a key of the wrong size")
+	}
+	if key, err := parseSigningKey(""); key != nil || err != nil {
+		t.Errorf("empty key = %v, %v; want nil, nil", key, err)
+	}
+}
+
+// TestWebhookDelivery tests signed delivery and retry after a failed attempt
+func TestWebhookDelivery(t *testing.T) {
+	secret := "webhook-secret"
+	received := make(chan WebhookEvent, 1)
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		want := "sha256=" + signWebhook([]byte(secret), r.Header.Get(webhookTimestampHeader), body)
+		if got := r.Header.Get(webhookSignatureHeader); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if got := r.Header.Get(webhookEventHeader); got != EventLicenseRevoked {
+			t.Errorf("event header = %q, want %q", got, EventLicenseRevoked)
+		}
+		var event WebhookEvent
+		json.Unmarshal(body, &event)
+		received <- event
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	d := NewWebhookDispatcher([]string{srv.URL}, secret, 3, nil, logger)
+	d.backoff = 10 * time.Millisecond
+
+	d.Emit(EventLicenseRevoked, map[string]interface{}{"license_key": "SCLW-PRO-0000000000000000"})
+
+	select {
+	case event := <-received:
+		if event.Event != EventLicenseRevoked || event.Data["license_key"] != "SCLW-PRO-0000000000000000" {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	if n := atomic.LoadInt32(&attempts); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+
+	// Disabled webhooks drop events without panicking
+	var disabled *WebhookDispatcher
+	disabled.Emit(EventLicenseActivated, nil)
+	if NewWebhookDispatcher(parseWebhookURLs(" , "), secret, 3, nil, logger) != nil {
+		t.Error("expected no dispatcher without URLs")
+	}
+}
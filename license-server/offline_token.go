@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// offlineTokenIssuer is the iss claim of every offline license token
+const offlineTokenIssuer = "armorclaw-license-server"
+
+// OfflineTokenClaims are the claims of an offline license token. Bridges
+// verify the token with the server's public key and never call back, so
+// everything they need to gate features is in the claims.
+type OfflineTokenClaims struct {
+	Issuer       string   `json:"iss"`
+	Subject      string   `json:"sub"` // License key
+	Tier         string   `json:"tier"`
+	Features     []string `json:"features"`
+	MaxInstances int      `json:"max_instances"`
+	IssuedAt     int64    `json:"iat"`
+	ExpiresAt    int64    `json:"exp"`
+}
+
+// offlineTokenHeader is the fixed JWT header; only EdDSA (Ed25519) is issued
+var offlineTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"EdDSA","typ":"JWT"}`))
+
+// parseSigningKey decodes LICENSE_SIGNING_KEY: a base64 Ed25519 seed (32
+// bytes) or private key (64 bytes). An empty value disables offline tokens.
+func parseSigningKey(s string) (ed25519.PrivateKey, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("signing key is not valid base64: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("signing key is %d bytes, want %d or %d", len(raw), ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
+
+// signOfflineToken encodes claims as a compact EdDSA-signed JWT
+func signOfflineToken(key ed25519.PrivateKey, claims OfflineTokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal claims: %w", err)
+	}
+
+	signingInput := offlineTokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(key, []byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// newOfflineTokenClaims builds the claims for a freshly created license
+func newOfflineTokenClaims(licenseKey, tier string, features []string, maxInstances int, issuedAt, expiresAt time.Time) OfflineTokenClaims {
+	if features == nil {
+		features = []string{}
+	}
+	return OfflineTokenClaims{
+		Issuer:       offlineTokenIssuer,
+		Subject:      licenseKey,
+		Tier:         tier,
+		Features:     features,
+		MaxInstances: maxInstances,
+		IssuedAt:     issuedAt.Unix(),
+		ExpiresAt:    expiresAt.Unix(),
+	}
+}
+
+// issueOfflineToken signs an offline token for a license, reading its
+// features through db so it also sees rows of an uncommitted transaction
+func (s *Server) issueOfflineToken(ctx context.Context, db Querier, licenseID int, licenseKey, tier string, maxInstances int, expiresAt time.Time) (string, error) {
+	features, err := s.getLicenseFeaturesWithContext(ctx, db, licenseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load license features: %w", err)
+	}
+	claims := newOfflineTokenClaims(licenseKey, tier, features, maxInstances, time.Now(), expiresAt)
+	return signOfflineToken(s.config.SigningKey, claims)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// License lifecycle events delivered to webhooks
+const (
+	EventLicenseActivated      = "license.activated"
+	EventLicenseExpiring       = "license.expiring"
+	EventLicenseRevoked        = "license.revoked"
+	EventInstanceLimitExceeded = "license.instance_limit_exceeded"
+)
+
+// Webhook request headers
+const (
+	webhookEventHeader     = "X-ArmorClaw-Event"
+	webhookDeliveryHeader  = "X-ArmorClaw-Delivery"
+	webhookTimestampHeader = "X-ArmorClaw-Timestamp"
+	webhookSignatureHeader = "X-ArmorClaw-Signature"
+)
+
+// WebhookEvent is the JSON body POSTed to every webhook endpoint
+type WebhookEvent struct {
+	ID         string                 `json:"id"`
+	Event      string                 `json:"event"`
+	OccurredAt string                 `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// WebhookDispatcher delivers signed lifecycle events to the configured
+// endpoints. Deliveries run in the background and retry with exponential
+// backoff; a delivery that exhausts its attempts is written to the
+// webhook_dead_letters table so billing systems can be reconciled later.
+type WebhookDispatcher struct {
+	urls        []string
+	secret      []byte
+	maxAttempts int
+	backoff     time.Duration // Delay before the first retry, doubled after each
+	client      *http.Client
+	db          *sql.DB
+	logger      *slog.Logger
+}
+
+// NewWebhookDispatcher returns nil when no endpoints are configured, which
+// disables webhooks
+func NewWebhookDispatcher(urls []string, secret string, maxAttempts int, db *sql.DB, logger *slog.Logger) *WebhookDispatcher {
+	if len(urls) == 0 {
+		return nil
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &WebhookDispatcher{
+		urls:        urls,
+		secret:      []byte(secret),
+		maxAttempts: maxAttempts,
+		backoff:     time.Second,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		db:          db,
+		logger:      logger,
+	}
+}
+
+// Emit queues event for delivery to every endpoint. It never blocks the
+// caller; a nil dispatcher drops the event.
+func (d *WebhookDispatcher) Emit(event string, data map[string]interface{}) {
+	if d == nil {
+		return
+	}
+
+	id, err := newDeliveryID()
+	if err != nil {
+		d.logger.Error("Failed to generate webhook delivery ID", "error", err)
+		return
+	}
+	body, err := json.Marshal(WebhookEvent{
+		ID:         id,
+		Event:      event,
+		OccurredAt: time.Now().UTC().Format(time.RFC3339),
+		Data:       data,
+	})
+	if err != nil {
+		d.logger.Error("Failed to marshal webhook event", "event", event, "error", err)
+		return
+	}
+
+	for _, url := range d.urls {
+		go d.deliver(context.Background(), url, id, event, body)
+	}
+}
+
+// deliver posts body to url until it is accepted or attempts run out
+func (d *WebhookDispatcher) deliver(ctx context.Context, url, id, event string, body []byte) {
+	var lastErr error
+	delay := d.backoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				d.deadLetter(url, id, event, body, ctx.Err())
+				return
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		if lastErr = d.post(ctx, url, id, event, body); lastErr == nil {
+			return
+		}
+		d.logger.Warn("Webhook delivery failed",
+			"event", event,
+			"delivery_id", id,
+			"url", url,
+			"attempt", attempt,
+			"error", lastErr,
+		)
+	}
+
+	d.deadLetter(url, id, event, body, lastErr)
+}
+
+// post makes a single delivery attempt. Any 2xx response counts as delivered.
+func (d *WebhookDispatcher) post(ctx context.Context, url, id, event string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, event)
+	req.Header.Set(webhookDeliveryHeader, id)
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(d.secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// deadLetter records a delivery that exhausted its retries
+func (d *WebhookDispatcher) deadLetter(url, id, event string, body []byte, lastErr error) {
+	d.logger.Error("Webhook delivery abandoned",
+		"event", event,
+		"delivery_id", id,
+		"url", url,
+		"attempts", d.maxAttempts,
+		"error", lastErr,
+	)
+
+	if d.db == nil {
+		return
+	}
+	errMsg := ""
+	if lastErr != nil {
+		errMsg = lastErr.Error()
+	}
+	if _, err := d.db.Exec(`
+		INSERT INTO webhook_dead_letters (delivery_id, event, url, payload, attempts, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, id, event, url, string(body), d.maxAttempts, errMsg); err != nil {
+		d.logger.Error("Failed to record webhook dead letter", "delivery_id", id, "error", err)
+	}
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "timestamp.body". Receivers
+// recompute it with the shared secret and reject stale timestamps to stop
+// replays.
+func signWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newDeliveryID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// parseWebhookURLs splits a comma-separated WEBHOOK_URLS value
+func parseWebhookURLs(s string) []string {
+	var urls []string
+	for _, u := range strings.Split(s, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// runExpiryNotifier emits license.expiring once per license when it comes
+// within the configured warning window of its expiry
+func (s *Server) runExpiryNotifier(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		s.notifyExpiringLicenses(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyExpiringLicenses marks and announces licenses entering the warning
+// window. Marking first means a crash can drop a warning but never repeat it.
+func (s *Server) notifyExpiringLicenses(ctx context.Context) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE licenses SET expiry_notified_at = NOW()
+		WHERE status = 'active'
+			AND expiry_notified_at IS NULL
+			AND expires_at > NOW()
+			AND expires_at <= NOW() + make_interval(days => $1)
+		RETURNING license_key, tier, customer_email, expires_at
+	`, s.config.WebhookExpiryWarningDays)
+	if err != nil {
+		s.logger.Error("expiry notification query failed", "error", err)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var licenseKey, tier string
+		var email sql.NullString
+		var expiresAt time.Time
+		if err := rows.Scan(&licenseKey, &tier, &email, &expiresAt); err != nil {
+			s.logger.Error("expiry notification scan failed", "error", err)
+			return
+		}
+		s.webhooks.Emit(EventLicenseExpiring, map[string]interface{}{
+			"license_key":    licenseKey,
+			"tier":           tier,
+			"customer_email": email.String,
+			"expires_at":     expiresAt.Format(time.RFC3339),
+			"days_remaining": int(time.Until(expiresAt).Hours() / 24),
+		})
+	}
+}